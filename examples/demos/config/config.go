@@ -7,7 +7,7 @@
 //   Used by ALL demo examples to connect to MT5 servers.
 //
 //  LOADING PRIORITY:
-//   1️. config.json file (if exists)
+//   1️. config.json file (if exists; another file with --config / SetPath)
 //   2️. Environment variables (fallback)
 //   3️. Error if neither found
 //
@@ -89,15 +89,35 @@ type MT5Config struct {
 	TestVolume float64 `json:"test_volume"`
 }
 
+// DefaultPath is the config file LoadConfig reads unless SetPath was called.
+const DefaultPath = "config/config.json"
+
+// path is the config file read by LoadConfig; explicit is set by SetPath.
+var (
+	path     = DefaultPath
+	explicit bool
+)
+
+// SetPath makes LoadConfig read the given file (e.g. from --config).
+// Unlike the default file, an explicit file must exist: there is no
+// fallback to environment variables.
+func SetPath(file string) {
+	path = file
+	explicit = true
+}
+
 // LoadConfig loads configuration from file or environment variables
 // Priority: 1. config.json file, 2. environment variables
 func LoadConfig() (*MT5Config, error) {
 	// Try to load from config.json first
-	config, err := loadFromFile("config/config.json")
+	config, err := loadFromFile(path)
 	if err == nil {
-		fmt.Println("✓ Loaded configuration from config.json")
+		fmt.Printf("✓ Loaded configuration from %s\n", path)
 		return config, nil
 	}
+	if explicit {
+		return nil, err
+	}
 
 	// If config file not found, try environment variables
 	config, err = loadFromEnv()
@@ -0,0 +1,257 @@
+/*══════════════════════════════════════════════════════════════════════════════
+ FILE: headless.go - NON-INTERACTIVE RUN FLAGS
+
+ PURPOSE:
+   Lets demos and orchestrators run unattended (CI, containers, cron):
+   no Enter-key prompts, a bounded run time and a machine-readable result.
+
+ FLAGS (anywhere on the command line, "--flag value" or "--flag=value"):
+   --duration 5m        Orchestrator run time (overrides each runner's MaxRuntime)
+   --config FILE        Config file instead of config/config.json (must exist)
+   --no-prompt          Never wait for Enter; a command argument is required
+   --json-output FILE   Write the run result as JSON ("-" = stdout)
+   --progress MODE      Orchestrator progress: auto, bar, silent, json
+                        (auto = bar on a terminal, silent otherwise and
+                        with --json-output -)
+   --summary-dir DIR    Directory for per-run summary files (default runs,
+                        "off" = none); see orchestrators/run_summary.go
+
+ EXAMPLES:
+   go run . grid --duration 2m --no-prompt --json-output result.json
+   go run . risk --config /etc/mt5/demo.json --no-prompt --json-output -
+
+ The process exits with status 1 if the command failed.
+══════════════════════════════════════════════════════════════════════════════*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/MetaRPC/GoMT5/examples/demos/config"
+	"github.com/MetaRPC/GoMT5/examples/demos/helpers"
+	"github.com/MetaRPC/GoMT5/examples/demos/orchestrators"
+)
+
+// runOptions holds the parsed command line.
+type runOptions struct {
+	Args       []string      // Command and its arguments, flags removed
+	Duration   time.Duration // --duration (0 = runner default)
+	ConfigPath string        // --config
+	NoPrompt   bool          // --no-prompt
+	JSONOutput string        // --json-output ("" = off, "-" = stdout)
+	Progress   string        // --progress ("" = auto)
+	SummaryDir string        // --summary-dir ("" = orchestrators.DefaultRunSummaryDir, "off" = none)
+}
+
+// opts is the command line of this process.
+var opts runOptions
+
+// parseRunOptions separates the run flags from the command arguments.
+// Unknown "--" arguments (e.g. kill --flatten) stay in Args.
+func parseRunOptions(args []string) (runOptions, error) {
+	var o runOptions
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := strings.Cut(arg, "=")
+
+		// value returns the flag argument, inline or the next word.
+		next := func() (string, error) {
+			if hasValue {
+				return value, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("flag %s needs a value", name)
+			}
+			i++
+			return args[i], nil
+		}
+
+		switch name {
+		case "--duration", "-duration":
+			v, err := next()
+			if err != nil {
+				return o, err
+			}
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				return o, fmt.Errorf("invalid --duration %q (use e.g. 90s, 5m, 1h)", v)
+			}
+			o.Duration = d
+		case "--config", "-config":
+			v, err := next()
+			if err != nil {
+				return o, err
+			}
+			o.ConfigPath = v
+		case "--json-output", "-json-output":
+			v, err := next()
+			if err != nil {
+				return o, err
+			}
+			o.JSONOutput = v
+		case "--progress", "-progress":
+			v, err := next()
+			if err != nil {
+				return o, err
+			}
+			if _, err := helpers.ParseMonitorMode(v); err != nil {
+				return o, err
+			}
+			o.Progress = v
+		case "--summary-dir", "-summary-dir":
+			v, err := next()
+			if err != nil {
+				return o, err
+			}
+			o.SummaryDir = v
+		case "--no-prompt", "-no-prompt":
+			o.NoPrompt = true
+		default:
+			o.Args = append(o.Args, arg)
+		}
+	}
+	return o, nil
+}
+
+// runDuration returns the orchestrator run time: --duration or def.
+func runDuration(def time.Duration) time.Duration {
+	if opts.Duration > 0 {
+		return opts.Duration
+	}
+	return def
+}
+
+// waitForEnter blocks for the Enter key unless --no-prompt is set.
+func waitForEnter(message string) {
+	if opts.NoPrompt {
+		return
+	}
+	fmt.Println(message)
+	fmt.Scanln()
+}
+
+// ═════════════════════════════════════════════════════════════════
+// JSON RESULT
+// ═════════════════════════════════════════════════════════════════
+
+// runResult is the machine-readable outcome of one command.
+type runResult struct {
+	Command       string               `json:"command"`
+	OK            bool                 `json:"ok"`
+	Error         string               `json:"error,omitempty"`
+	StartedAt     time.Time            `json:"started_at"`
+	DurationSec   float64              `json:"duration_sec"`
+	Orchestrators []orchestratorResult `json:"orchestrators,omitempty"`
+	SummaryFiles  []string             `json:"summary_files,omitempty"`
+}
+
+// orchestratorResult is the final state of one orchestrator.
+type orchestratorResult struct {
+	Name        string  `json:"name"`
+	StopReason  string  `json:"stop_reason,omitempty"`
+	Crashed     bool    `json:"crashed,omitempty"`
+	UptimeSec   float64 `json:"uptime_sec"`
+	Operations  int     `json:"operations"`
+	Errors      int     `json:"errors"`
+	LastError   string  `json:"last_error,omitempty"`
+	Trades      int     `json:"trades"`
+	WinRate     float64 `json:"win_rate"`
+	NetProfit   float64 `json:"net_profit"`
+	FloatingPnL float64 `json:"floating_pnl"`
+	TotalPnL    float64 `json:"total_pnl"`
+	MaxDrawdown float64 `json:"max_drawdown"`
+}
+
+// orchestratorResults collects the orchestrators reported during the run.
+var orchestratorResults []orchestratorResult
+
+// recordOrchestrator adds the final state of orch to the JSON result.
+func recordOrchestrator(orch orchestrators.Orchestrator) {
+	status := orch.GetStatus()
+	metrics := orch.GetMetrics()
+	orchestratorResults = append(orchestratorResults, orchestratorResult{
+		Name:        status.Name,
+		StopReason:  status.StopReason,
+		Crashed:     status.Crashed,
+		UptimeSec:   status.Uptime.Seconds(),
+		Operations:  status.SuccessCount,
+		Errors:      status.ErrorCount,
+		LastError:   status.LastError,
+		Trades:      metrics.TotalTrades,
+		WinRate:     metrics.WinRate,
+		NetProfit:   metrics.NetProfit,
+		FloatingPnL: metrics.FloatingPnL,
+		TotalPnL:    metrics.TotalPnL,
+		MaxDrawdown: metrics.MaxDrawdown,
+	})
+}
+
+// summaryFiles are the run summary files written during the run.
+var summaryFiles []string
+
+// writeRunSummary writes summary to --summary-dir unless it is "off".
+// A failure is reported but does not fail the run.
+func writeRunSummary(summary orchestrators.RunSummary) {
+	if opts.SummaryDir == "off" {
+		return
+	}
+	path, err := summary.WriteFile(opts.SummaryDir)
+	if err != nil {
+		fmt.Printf("\n⚠️  %v\n", err)
+		return
+	}
+	summaryFiles = append(summaryFiles, path)
+	fmt.Printf("\n📝 Run summary: %s\n", path)
+}
+
+// writeRunResult writes the JSON result if --json-output is set.
+func writeRunResult(command string, started time.Time, runErr error) error {
+	if opts.JSONOutput == "" {
+		return nil
+	}
+	result := runResult{
+		Command:       command,
+		OK:            runErr == nil,
+		StartedAt:     started,
+		DurationSec:   time.Since(started).Seconds(),
+		Orchestrators: orchestratorResults,
+		SummaryFiles:  summaryFiles,
+	}
+	if runErr != nil {
+		result.Error = runErr.Error()
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+	data = append(data, '\n')
+
+	if opts.JSONOutput == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(opts.JSONOutput, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write result: %w", err)
+	}
+	return nil
+}
+
+// applyRunOptions installs the options that affect shared packages.
+func applyRunOptions() {
+	if opts.ConfigPath != "" {
+		config.SetPath(opts.ConfigPath)
+	}
+
+	// Progress output must not mix into a JSON result on stdout.
+	mode, _ := helpers.ParseMonitorMode(opts.Progress)
+	if mode == helpers.MonitorAuto && opts.JSONOutput == "-" {
+		mode = helpers.MonitorSilent
+	}
+	helpers.DefaultMonitorMode = mode
+}
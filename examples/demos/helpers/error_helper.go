@@ -13,6 +13,7 @@ package helpers
    • PrintSuccess() - Print success message
    • PrintWarning() - Print warning message
    • FormatApiError() - Format ApiError with full details
+   • CheckRetCode() - Check trade return code, print cause and fix
 
  USAGE EXAMPLE:
 
@@ -101,12 +102,18 @@ func PrintIfError(err error, context string) bool {
 			// Generic API error
 			fmt.Printf("      API Error: %s\n", apiErr.ErrorCode())
 		}
+		if ex, ok := mt5.ExplainErr(err); ok {
+			printHint(ex)
+		}
 
 		return true
 	}
 
 	// Regular error - simple format
 	fmt.Printf("  ⚠️  %s: %v\n", context, err)
+	if ex, ok := mt5.ExplainErr(err); ok {
+		printHint(ex)
+	}
 	return true
 }
 
@@ -141,6 +148,9 @@ func PrintShortError(err error, context string) bool {
 	} else {
 		fmt.Printf("  ❌ %s: %v\n", context, err)
 	}
+	if ex, ok := mt5.ExplainErr(err); ok {
+		printHint(ex)
+	}
 
 	return true
 }
@@ -215,23 +225,20 @@ func CheckRetCode(retCode uint32, operation string) bool {
 	fmt.Printf("  ❌ %s failed (RetCode: %d)\n", operation, retCode)
 	fmt.Printf("     %s\n", mt5.GetRetCodeMessage(retCode))
 
-	// Provide helpful hints for common errors
-	switch retCode {
-	case mt5.TradeRetCodeNoMoney:
-		fmt.Println("     💡 Hint: Check account margin - insufficient funds")
-	case mt5.TradeRetCodeInvalidStops:
-		fmt.Println("     💡 Hint: SL/TP too close to market price - check SYMBOL_TRADE_STOPS_LEVEL")
-	case mt5.TradeRetCodeInvalidVolume:
-		fmt.Println("     💡 Hint: Check SYMBOL_VOLUME_MIN, SYMBOL_VOLUME_MAX, SYMBOL_VOLUME_STEP")
-	case mt5.TradeRetCodeMarketClosed:
-		fmt.Println("     💡 Hint: Market is closed - check trading hours")
-	case mt5.TradeRetCodeRequote, mt5.TradeRetCodePriceChanged:
-		fmt.Println("     💡 Hint: Price changed - retry with updated price")
-	}
+	// Likely cause and fix, e.g. stops level for INVALID_STOPS
+	printHint(mt5.Explain(retCode))
 
 	return false
 }
 
+// printHint prints the cause and suggested fix of an explained error code.
+func printHint(ex mt5.Explanation) {
+	if ex.Fix == "" {
+		return
+	}
+	fmt.Printf("     💡 Hint: %s - %s\n", ex.Cause, ex.Fix)
+}
+
 // PrintRetCodeWarning prints a warning for non-critical trade return codes.
 // Use when operation partially succeeded or requires retry.
 //
@@ -0,0 +1,263 @@
+/*══════════════════════════════════════════════════════════════════════════════
+ FILE: examples/demos/helpers/monitor.go
+ PURPOSE:
+   Waits while an orchestrator runs: calls a status callback on a ticker,
+   shows progress and returns when the time is up, the context ends, the
+   callback asks to stop or the user presses Ctrl+C.
+
+ MODES:
+   MonitorAuto     Bar on a terminal, Silent otherwise (default)
+   MonitorBar      Progress bar plus the callback's status line
+   MonitorSilent   No output (CI logs, --json-output -)
+   MonitorJSON     One JSON object per callback, for log collectors
+
+ USAGE EXAMPLE:
+   result := helpers.Monitor{
+       Message:  "Grid Trader Active",
+       Duration: 10 * time.Minute,
+       Interval: 5 * time.Second,
+       Update: func() helpers.MonitorStatus {
+           m := grid.GetMetrics()
+           return helpers.MonitorStatus{
+               Line:   fmt.Sprintf("Positions: %d", m.CurrentPositions),
+               Fields: map[string]any{"positions": m.CurrentPositions},
+           }
+       },
+   }.Run(grid.GetContext())
+
+   Output (Bar):
+   Grid Trader Active: [█████████░░░░░░░░░░] 45% (27s / 60s) - 33s remaining | Positions: 3
+
+   Output (JSON):
+   {"time":"...","message":"Grid Trader Active","elapsed_sec":27,"total_sec":60,"status":"Positions: 3","fields":{"positions":3}}
+
+══════════════════════════════════════════════════════════════════════════════*/
+
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// MonitorMode selects how a Monitor reports progress.
+type MonitorMode int
+
+const (
+	MonitorAuto   MonitorMode = iota // Bar on a terminal, Silent otherwise
+	MonitorBar                       // Progress bar redrawn every second
+	MonitorSilent                    // No output
+	MonitorJSON                      // One JSON line per Update
+)
+
+// DefaultMonitorMode is used by monitors that leave Mode at MonitorAuto.
+// Set once at startup (e.g. from a --progress flag).
+var DefaultMonitorMode = MonitorAuto
+
+// ParseMonitorMode parses "auto", "bar", "silent" or "json".
+func ParseMonitorMode(s string) (MonitorMode, error) {
+	switch strings.ToLower(s) {
+	case "", "auto":
+		return MonitorAuto, nil
+	case "bar":
+		return MonitorBar, nil
+	case "silent", "none", "off":
+		return MonitorSilent, nil
+	case "json":
+		return MonitorJSON, nil
+	}
+	return MonitorAuto, fmt.Errorf("unknown progress mode %q (use auto, bar, silent or json)", s)
+}
+
+// MonitorStatus is what the Update callback reports each interval.
+type MonitorStatus struct {
+	Line   string         // Human-readable status shown after the bar
+	Fields map[string]any // Structured values for MonitorJSON (optional)
+	Stop   bool           // End the wait early
+}
+
+// Monitor waits for an orchestrator. Zero fields take the defaults noted
+// on each field. Run it with Run; a Monitor can be reused.
+type Monitor struct {
+	Message  string               // Label in front of the bar
+	Duration time.Duration        // Wait at most this long (0 = until ctx ends)
+	Interval time.Duration        // Update period (0 = 5s)
+	Mode     MonitorMode          // Output mode (MonitorAuto = DefaultMonitorMode)
+	Out      io.Writer            // Destination (nil = os.Stdout)
+	Update   func() MonitorStatus // Status callback (optional)
+}
+
+// MonitorReason tells why Monitor.Run returned.
+type MonitorReason string
+
+const (
+	MonitorCompleted   MonitorReason = "completed"   // Duration elapsed
+	MonitorCanceled    MonitorReason = "canceled"    // ctx ended (e.g. the orchestrator stopped itself)
+	MonitorStopped     MonitorReason = "stopped"     // Update returned Stop
+	MonitorInterrupted MonitorReason = "interrupted" // Ctrl+C / SIGTERM
+)
+
+// MonitorResult is the outcome of Monitor.Run.
+type MonitorResult struct {
+	Reason  MonitorReason
+	Elapsed time.Duration
+	Last    MonitorStatus // Last status reported by Update
+}
+
+// monitorEvent is one MonitorJSON line.
+type monitorEvent struct {
+	Time       time.Time      `json:"time"`
+	Message    string         `json:"message"`
+	ElapsedSec int            `json:"elapsed_sec"`
+	TotalSec   int            `json:"total_sec,omitempty"`
+	Status     string         `json:"status,omitempty"`
+	Fields     map[string]any `json:"fields,omitempty"`
+	Reason     MonitorReason  `json:"reason,omitempty"`
+}
+
+// Run blocks until Duration elapses, ctx ends, Update returns Stop or the
+// process receives Ctrl+C/SIGTERM. The signal is consumed while Run is
+// active so the caller can still stop its orchestrator cleanly.
+func (m Monitor) Run(ctx context.Context) MonitorResult {
+	interval := m.Interval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	out := m.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	mode := m.mode(out)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	var deadline <-chan time.Time
+	if m.Duration > 0 {
+		timer := time.NewTimer(m.Duration)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	updates := time.NewTicker(interval)
+	defer updates.Stop()
+
+	// The bar is redrawn every second; other modes only print on updates.
+	var redraw <-chan time.Time
+	if mode == MonitorBar {
+		bar := time.NewTicker(time.Second)
+		defer bar.Stop()
+		redraw = bar.C
+	}
+
+	start := time.Now()
+	var last MonitorStatus
+	finish := func(reason MonitorReason) MonitorResult {
+		result := MonitorResult{Reason: reason, Elapsed: time.Since(start), Last: last}
+		switch mode {
+		case MonitorBar:
+			m.drawBar(out, result.Elapsed, last)
+			fmt.Fprintln(out)
+			if reason == MonitorInterrupted {
+				fmt.Fprintln(out, "  ⚠️  Interrupted, stopping...")
+			}
+		case MonitorJSON:
+			m.writeEvent(out, result.Elapsed, last, reason)
+		}
+		return result
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return finish(MonitorCanceled)
+
+		case <-sigCh:
+			return finish(MonitorInterrupted)
+
+		case <-deadline:
+			return finish(MonitorCompleted)
+
+		case <-redraw:
+			m.drawBar(out, time.Since(start), last)
+
+		case <-updates.C:
+			if m.Update == nil {
+				continue
+			}
+			last = m.Update()
+			switch mode {
+			case MonitorBar:
+				m.drawBar(out, time.Since(start), last)
+			case MonitorJSON:
+				m.writeEvent(out, time.Since(start), last, "")
+			}
+			if last.Stop {
+				return finish(MonitorStopped)
+			}
+		}
+	}
+}
+
+// mode resolves MonitorAuto: Bar if out is a terminal, Silent otherwise.
+func (m Monitor) mode(out io.Writer) MonitorMode {
+	mode := m.Mode
+	if mode == MonitorAuto {
+		mode = DefaultMonitorMode
+	}
+	if mode != MonitorAuto {
+		return mode
+	}
+	if f, ok := out.(*os.File); ok {
+		if info, err := f.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+			return MonitorBar
+		}
+	}
+	return MonitorSilent
+}
+
+// drawBar redraws the progress line in place.
+func (m Monitor) drawBar(out io.Writer, elapsed time.Duration, status MonitorStatus) {
+	secs := int(elapsed.Seconds())
+	var line string
+	if total := int(m.Duration.Seconds()); total > 0 {
+		if secs > total {
+			secs = total
+		}
+		progress := float64(secs) / float64(total)
+		filledWidth := int(progress * float64(BarWidth))
+		bar := strings.Repeat(FilledChar, filledWidth) + strings.Repeat(EmptyChar, BarWidth-filledWidth)
+		line = fmt.Sprintf("  %s: [%s] %d%% (%ds / %ds) - %ds remaining",
+			m.Message, bar, int(progress*100), secs, total, total-secs)
+	} else {
+		line = fmt.Sprintf("  %s: %ds elapsed", m.Message, secs)
+	}
+	if status.Line != "" {
+		line += " | " + status.Line
+	}
+	fmt.Fprint(out, "\r\033[K"+line)
+}
+
+// writeEvent writes one MonitorJSON line.
+func (m Monitor) writeEvent(out io.Writer, elapsed time.Duration, status MonitorStatus, reason MonitorReason) {
+	data, err := json.Marshal(monitorEvent{
+		Time:       time.Now(),
+		Message:    m.Message,
+		ElapsedSec: int(elapsed.Seconds()),
+		TotalSec:   int(m.Duration.Seconds()),
+		Status:     status.Line,
+		Fields:     status.Fields,
+		Reason:     reason,
+	})
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(out, string(data))
+}
@@ -3,6 +3,8 @@
  PURPOSE:
    Utility package for displaying console progress bars during time-based waits.
    Used by orchestrators to visualize countdowns and waiting periods.
+   WaitWithProgressBar* are wrappers over Monitor (monitor.go), which
+   orchestrator runners should use directly.
 
  USAGE EXAMPLE:
    // Wait 60 seconds with progress bar
@@ -50,28 +52,10 @@ const (
 //	ctx := context.Background()
 //	WaitWithProgressBar(60, "Countdown to trade", ctx)
 func WaitWithProgressBar(totalSeconds int, message string, ctx context.Context) {
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
-
-	elapsed := 0
-
-	for {
-		select {
-		case <-ctx.Done():
-			fmt.Println() // Move to next line
-			return
-
-		case <-ticker.C:
-			// Display progress
-			displayProgress(elapsed, totalSeconds, message)
-
-			elapsed++
-			if elapsed > totalSeconds {
-				fmt.Println() // Move to next line after completion
-				return
-			}
-		}
-	}
+	Monitor{
+		Message:  message,
+		Duration: time.Duration(totalSeconds) * time.Second,
+	}.Run(ctx)
 }
 
 // CountdownWithoutBar displays a simple countdown without progress bar.
@@ -154,27 +138,6 @@ func MonitorWithProgressBar(maxSeconds int, message string, updateInterval time.
 	}
 }
 
-// displayProgress displays the progress bar with percentage and time info
-func displayProgress(elapsed, total int, message string) {
-	// Calculate progress
-	progress := float64(elapsed) / float64(total)
-	filledWidth := int(progress * float64(BarWidth))
-	emptyWidth := BarWidth - filledWidth
-
-	// Build progress bar
-	bar := strings.Repeat(FilledChar, filledWidth) + strings.Repeat(EmptyChar, emptyWidth)
-	percent := int(progress * 100)
-	remaining := total - elapsed
-
-	// Build output string
-	output := fmt.Sprintf("  %s: [%s] %d%% (%ds / %ds) - %ds remaining",
-		message, bar, percent, elapsed, total, remaining)
-
-	// Clear line and write progress
-	clearLine()
-	fmt.Print(output)
-}
-
 // clearLine clears the current console line
 func clearLine() {
 	// ANSI escape code to clear line: \r moves cursor to start, spaces overwrite
@@ -186,6 +149,9 @@ func clearLine() {
 // WaitWithProgressBarAndCallback is an advanced version that calls a callback during wait.
 // Useful for checking conditions while waiting.
 //
+// Deprecated: use Monitor, which also reports a status line, honours
+// Ctrl+C and has silent/JSON modes for non-terminal runs.
+//
 // Parameters:
 //   - totalSeconds: Total seconds to wait
 //   - message: Message to display
@@ -209,38 +175,15 @@ func WaitWithProgressBarAndCallback(
 	callback func() bool,
 	ctx context.Context) {
 
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
-
-	callbackTicker := time.NewTicker(interval)
-	defer callbackTicker.Stop()
-
-	elapsed := 0
-
-	for {
-		select {
-		case <-ctx.Done():
-			fmt.Println()
-			return
-
-		case <-callbackTicker.C:
-			// Call callback
-			if callback != nil && !callback() {
-				fmt.Println() // Condition met, stop early
-				return
-			}
-
-		case <-ticker.C:
-			// Display progress
-			displayProgress(elapsed, totalSeconds, message)
-
-			elapsed++
-			if elapsed > totalSeconds {
-				fmt.Println()
-				return
-			}
-		}
+	m := Monitor{
+		Message:  message,
+		Duration: time.Duration(totalSeconds) * time.Second,
+		Interval: interval,
+	}
+	if callback != nil {
+		m.Update = func() MonitorStatus { return MonitorStatus{Stop: !callback()} }
 	}
+	m.Run(ctx)
 }
 
 // SpinnerWait displays a spinning animation while waiting.
@@ -64,6 +64,11 @@
    sugar06, sugar07, sugar08, sugar09,
    grid, trailing, scaler, risk, rebalancer, adaptive
 
+ Unattended (CI / containers, see headless.go):
+   go run main.go grid --duration 2m --no-prompt --json-output result.json
+   Flags: --duration, --config FILE, --no-prompt, --json-output FILE|-,
+          --progress auto|bar|silent|json
+
  ╔═══════════════════════════════════════════════════════════════════════════╗
  ║                         PROJECT STRUCTURE                                 ║
  ╚═══════════════════════════════════════════════════════════════════════════╝
@@ -87,6 +92,7 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -102,14 +108,28 @@ import (
 )
 
 func main() {
+	var err error
+	opts, err = parseRunOptions(os.Args[1:])
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(2)
+	}
+	applyRunOptions()
+
+	// Direct command mode: go run main.go <command> [flags]
+	direct := len(opts.Args) > 0
+	if opts.NoPrompt && !direct {
+		fmt.Println("❌ --no-prompt needs a command, e.g.: go run main.go grid --no-prompt")
+		os.Exit(2)
+	}
+
 	// Main loop
 	for {
 		var command string
 
 		// Get the command from the arguments or show the menu
-		if len(os.Args) > 1 {
-			// Direct command mode: go run main.go <command>
-			command = strings.ToLower(os.Args[1])
+		if direct {
+			command = strings.ToLower(opts.Args[0])
 		} else {
 			// Interactive menu mode
 			printBanner()
@@ -117,14 +137,20 @@ func main() {
 		}
 
 		// Execute command
+		started := time.Now()
 		exitRequested, err := executeCommand(command)
+		if direct {
+			if werr := writeRunResult(command, started, err); werr != nil {
+				fmt.Printf("\n⚠️  %v\n", werr)
+			}
+		}
 		if err != nil {
 			fmt.Println("\n╔════════════════════════════════════════════════════════════╗")
 			fmt.Println("║                    ERROR OCCURRED                          ║")
 			fmt.Println("╚════════════════════════════════════════════════════════════╝")
 			fmt.Printf("\nError: %v\n", err)
 
-			if len(os.Args) > 1 {
+			if direct {
 				os.Exit(1)
 			}
 
@@ -139,9 +165,8 @@ func main() {
 		}
 
 		// Command line mode: exit after a single run
-		if len(os.Args) > 1 {
-			fmt.Println("\n\nPress Enter to exit...")
-			fmt.Scanln()
+		if direct {
+			waitForEnter("\n\nPress Enter to exit...")
 			return
 		}
 
@@ -214,6 +239,7 @@ func showMenu() string {
 	fmt.Println("├──────────────────────────────────────────────────────────────────┤")
 	fmt.Println("│  [16] Adaptive Preset      → go run main.go adaptive             │")
 	fmt.Println("│  [17] Protobuf Inspector   → go run main.go inspect              │")
+	fmt.Println("│  [19] Kill Switch          → go run main.go kill [--flatten]     │")
 	fmt.Println("├──────────────────────────────────────────────────────────────────┤")
 	fmt.Println("│  USER CODE SANDBOX (Uncomment in main.go to enable)              │")
 	fmt.Println("├──────────────────────────────────────────────────────────────────┤")
@@ -313,6 +339,9 @@ func executeCommand(command string) (exitRequested bool, err error) {
 		helpers.RunProtobufInspector()
 		return false, nil
 
+	case "19", "kill", "killswitch":
+		return false, RunKillSwitch()
+
 	// ═════════════════════════════════════════════════════════════
 	// USER CODE SANDBOX (DISABLED BY DEFAULT)
 	// ═════════════════════════════════════════════════════════════
@@ -361,6 +390,7 @@ func executeCommand(command string) (exitRequested bool, err error) {
 		fmt.Println("  Sugar:          sugar06, sugar07, sugar08, sugar09")
 		fmt.Println("  Orchestrators:  trailing, scaler, grid, risk, rebalancer")
 		fmt.Println("  Presets:        adaptive")
+		fmt.Println("  Tools:          inspect, kill")
 		return false, nil
 	}
 }
@@ -407,6 +437,9 @@ func RunOrchestrator_TrailingStop() error {
 		Symbols:          []string{},      // All symbols (empty = all)
 		MinDistance:      100,             // Min 100 points from price
 		StepSize:         50,              // Adjust in 50 point steps
+
+		// Auto-stop after 3 minutes (--duration overrides; see RuntimeLimits)
+		Limits: orchestrators.RuntimeLimits{MaxRuntime: runDuration(3 * time.Minute)},
 	}
 
 	fmt.Println("\n📋 Configuration:")
@@ -421,37 +454,37 @@ func RunOrchestrator_TrailingStop() error {
 		return fmt.Errorf("failed to start: %w", err)
 	}
 
-	// Run for 3 minutes with live progress bar and metrics
+	// Run until a runtime limit stops it, with live progress bar and metrics
 	fmt.Println("  ✓ Starting monitoring...")
 	fmt.Println()
 
-	helpers.WaitWithProgressBarAndCallback(
-		180,                      // 3 minutes = 180 seconds
-		"Trailing Stop Active",
-		2 * time.Second,          // Update callback every 2 seconds (matches UpdateInterval)
-		func() bool {
-			// Display live metrics during operation
+	helpers.Monitor{
+		Message:  "Trailing Stop Active",
+		Duration: orchConfig.Limits.MaxRuntime, // Ends early when a limit stops the orchestrator
+		Interval: 2 * time.Second,              // Update status every 2 seconds (matches UpdateInterval)
+		Update: func() helpers.MonitorStatus {
+			// Live metrics during operation
 			status := tsManager.GetStatus()
 			metrics := tsManager.GetMetrics()
 
-			fmt.Printf("\r  📊 Positions: %d | SL Updates: %d | Success: %d | Errors: %d        ",
-				metrics.CurrentPositions,
-				status.SuccessCount,
-				status.SuccessCount,
-				status.ErrorCount)
-
-			return true // Continue monitoring
+			return helpers.MonitorStatus{
+				Line: fmt.Sprintf("📊 Positions: %d | SL Updates: %d | Errors: %d",
+					metrics.CurrentPositions, status.SuccessCount, status.ErrorCount),
+				Fields: map[string]any{
+					"positions":  metrics.CurrentPositions,
+					"sl_updates": status.SuccessCount,
+					"errors":     status.ErrorCount,
+				},
+			}
 		},
-		tsManager.GetContext(),
-	)
-	fmt.Println() // New line after progress bar completes
+	}.Run(tsManager.GetContext()) // Ctrl+C ends the wait; the manager is stopped below
 
-	fmt.Println("\n🛑 Stopping...")
-	if err := tsManager.Stop(); err != nil {
-		return fmt.Errorf("failed to stop: %w", err)
+	if err := stopOrchestrator(tsManager); err != nil {
+		return err
 	}
 
 	showOrchestratorMetrics(tsManager)
+	writeRunSummary(orchestrators.NewRunSummary(tsManager, orchConfig))
 	return nil
 }
 
@@ -491,6 +524,9 @@ func RunOrchestrator_PositionScaler() error {
 		StopLossPerScale: 150,                      // 150 points SL per scale
 		Symbols:          []string{cfg.TestSymbol}, // EURUSD
 		CheckInterval:    5 * time.Second,
+
+		// Auto-stop after 5 minutes (--duration overrides; see RuntimeLimits)
+		Limits: orchestrators.RuntimeLimits{MaxRuntime: runDuration(5 * time.Minute)},
 	}
 
 	fmt.Println("\n📋 Configuration:")
@@ -508,37 +544,38 @@ func RunOrchestrator_PositionScaler() error {
 		return fmt.Errorf("failed to start: %w", err)
 	}
 
-	// Run for 5 minutes with live progress bar and metrics
+	// Run until a runtime limit stops it, with live progress bar and metrics
 	fmt.Println("  ✓ Starting monitoring...")
 	fmt.Println()
 
-	helpers.WaitWithProgressBarAndCallback(
-		300,                      // 5 minutes = 300 seconds
-		"Position Scaler Active",
-		5 * time.Second,          // Update callback every 5 seconds (matches CheckInterval)
-		func() bool {
-			// Display live metrics during operation
+	helpers.Monitor{
+		Message:  "Position Scaler Active",
+		Duration: orchConfig.Limits.MaxRuntime, // Ends early when a limit stops the orchestrator
+		Interval: 5 * time.Second,              // Update status every 5 seconds (matches CheckInterval)
+		Update: func() helpers.MonitorStatus {
+			// Live metrics during operation
 			status := scaler.GetStatus()
 			metrics := scaler.GetMetrics()
 
-			fmt.Printf("\r  📊 Groups: %d | Scales: %d | Trades: %d | Errors: %d        ",
-				metrics.CurrentPositions,
-				status.SuccessCount,
-				metrics.TotalTrades,
-				status.ErrorCount)
-
-			return true // Continue monitoring
+			return helpers.MonitorStatus{
+				Line: fmt.Sprintf("📊 Groups: %d | Scales: %d | Trades: %d | Errors: %d",
+					metrics.CurrentPositions, status.SuccessCount, metrics.TotalTrades, status.ErrorCount),
+				Fields: map[string]any{
+					"groups": metrics.CurrentPositions,
+					"scales": status.SuccessCount,
+					"trades": metrics.TotalTrades,
+					"errors": status.ErrorCount,
+				},
+			}
 		},
-		scaler.GetContext(),
-	)
-	fmt.Println() // New line after progress bar completes
+	}.Run(scaler.GetContext()) // Ctrl+C ends the wait; the scaler is stopped below
 
-	fmt.Println("\n🛑 Stopping...")
-	if err := scaler.Stop(); err != nil {
-		return fmt.Errorf("failed to stop: %w", err)
+	if err := stopOrchestrator(scaler); err != nil {
+		return err
 	}
 
 	showOrchestratorMetrics(scaler)
+	writeRunSummary(orchestrators.NewRunSummary(scaler, orchConfig))
 	return nil
 }
 
@@ -577,6 +614,9 @@ func RunOrchestrator_Grid() error {
 		StopLoss:       0,               // No stop loss
 		CheckInterval:  5 * time.Second, // Check every 5 seconds
 		RebuildOnFill:  false,           // Don't rebuild grid on fill
+
+		// Auto-stop after 10 minutes or $100 loss (--duration overrides; see RuntimeLimits)
+		Limits: orchestrators.RuntimeLimits{MaxRuntime: runDuration(10 * time.Minute), MaxLoss: 100},
 	}
 
 	fmt.Println("\n📋 Configuration:")
@@ -593,37 +633,51 @@ func RunOrchestrator_Grid() error {
 		return fmt.Errorf("failed to start: %w", err)
 	}
 
-	// Run for 10 minutes with live progress bar and metrics
+	// Run until a runtime limit stops it, with live progress bar and metrics
 	fmt.Println("  ✓ Starting monitoring...")
 	fmt.Println()
 
-	helpers.WaitWithProgressBarAndCallback(
-		600,                      // 10 minutes = 600 seconds
-		"Grid Trader Active",
-		5*time.Second, // Update callback every 5 seconds (matches CheckInterval)
-		func() bool {
-			// Display live metrics during operation
+	helpers.Monitor{
+		Message:  "Grid Trader Active",
+		Duration: orchConfig.Limits.MaxRuntime, // Ends early when a limit stops the orchestrator
+		Interval: 5 * time.Second,              // Update status every 5 seconds (matches CheckInterval)
+		Update: func() helpers.MonitorStatus {
+			// Live metrics during operation
 			status := gridTrader.GetStatus()
 			metrics := gridTrader.GetMetrics()
 
-			fmt.Printf("\r  📊 Positions: %d | Orders: %d | Trades: %d | Errors: %d        ",
-				metrics.CurrentPositions,
-				status.SuccessCount, // Active pending orders count
-				metrics.TotalTrades,
-				status.ErrorCount)
-
-			return true // Continue monitoring
+			return helpers.MonitorStatus{
+				Line: fmt.Sprintf("📊 Positions: %d | Orders: %d | Trades: %d | Errors: %d",
+					metrics.CurrentPositions,
+					status.SuccessCount, // Active pending orders count
+					metrics.TotalTrades,
+					status.ErrorCount),
+				Fields: map[string]any{
+					"positions": metrics.CurrentPositions,
+					"orders":    status.SuccessCount,
+					"trades":    metrics.TotalTrades,
+					"errors":    status.ErrorCount,
+				},
+			}
 		},
-		gridTrader.GetContext(),
-	)
-	fmt.Println() // New line after progress bar completes
+	}.Run(gridTrader.GetContext()) // Ctrl+C ends the wait; the grid is stopped below
 
-	fmt.Println("\n🛑 Stopping...")
-	if err := gridTrader.Stop(); err != nil {
-		return fmt.Errorf("failed to stop: %w", err)
+	fmt.Println("\n📐 Grid ladder:")
+	for _, level := range gridTrader.GetLevels() {
+		filled := ""
+		if !level.FilledAt.IsZero() {
+			filled = "filled " + level.FilledAt.Format("15:04:05")
+		}
+		fmt.Printf("  %+3d  %.5f  %-10s  %-7s  #%-10d %s\n",
+			level.Index, level.Price, level.Side, level.State, level.Ticket, filled)
+	}
+
+	if err := stopOrchestrator(gridTrader); err != nil {
+		return err
 	}
 
 	showOrchestratorMetrics(gridTrader)
+	writeRunSummary(orchestrators.NewRunSummary(gridTrader, orchConfig))
 	return nil
 }
 
@@ -665,6 +719,9 @@ func RunOrchestrator_RiskManager() error {
 		CheckInterval:       5 * time.Second,  // Check every 5 seconds
 		EnableAutoClose:     true,             // Auto-close on breach
 		EnableTradeBlocking: true,             // Block trades on breach
+
+		// Auto-stop after 15 minutes (--duration overrides; see RuntimeLimits)
+		Limits: orchestrators.RuntimeLimits{MaxRuntime: runDuration(15 * time.Minute)},
 	}
 
 	fmt.Println("\n📋 Configuration:")
@@ -681,16 +738,16 @@ func RunOrchestrator_RiskManager() error {
 		return fmt.Errorf("failed to start: %w", err)
 	}
 
-	// Run for 15 minutes with live progress bar and risk metrics
+	// Run until a runtime limit stops it, with live progress bar and metrics
 	fmt.Println("  ✓ Starting monitoring...")
 	fmt.Println()
 
-	helpers.WaitWithProgressBarAndCallback(
-		900,                      // 15 minutes = 900 seconds
-		"Risk Manager Active",
-		5*time.Second, // Update callback every 5 seconds (matches CheckInterval)
-		func() bool {
-			// Display live risk metrics during operation
+	helpers.Monitor{
+		Message:  "Risk Manager Active",
+		Duration: orchConfig.Limits.MaxRuntime, // Ends early when a limit stops the orchestrator
+		Interval: 5 * time.Second,              // Update status every 5 seconds (matches CheckInterval)
+		Update: func() helpers.MonitorStatus {
+			// Live risk metrics during operation
 			metrics := riskManager.GetMetrics()
 			todayProfit := riskManager.GetTodayProfit()
 			isBlocked := riskManager.IsTradingBlocked()
@@ -701,30 +758,30 @@ func RunOrchestrator_RiskManager() error {
 				blockedStr = "🔒"
 			}
 
-			profitSign := ""
-			if todayProfit > 0 {
-				profitSign = "+"
+			return helpers.MonitorStatus{
+				Line: fmt.Sprintf("🛡️ DD: %.1f%% (max %.1f%%) | Daily: %+.2f | Events: %d | Trading: %s",
+					metrics.CurrentDrawdownPercent, // Current drawdown percentage
+					metrics.MaxDrawdownPercent,     // Deepest drawdown so far
+					todayProfit,                    // Today's profit/loss
+					riskEvents,                     // Risk events count
+					blockedStr),                    // Trading status
+				Fields: map[string]any{
+					"drawdown_pct":     metrics.CurrentDrawdownPercent,
+					"max_drawdown_pct": metrics.MaxDrawdownPercent,
+					"daily_profit":     todayProfit,
+					"risk_events":      riskEvents,
+					"trading_blocked":  isBlocked,
+				},
 			}
-
-			fmt.Printf("\r  🛡️ DD: %.1f%% | Daily: %s%.2f | Events: %d | Trading: %s        ",
-				metrics.MaxDrawdown,   // Current drawdown percentage
-				profitSign,
-				todayProfit,           // Today's profit/loss
-				riskEvents,            // Risk events count
-				blockedStr)            // Trading status
-
-			return true // Continue monitoring
 		},
-		riskManager.GetContext(),
-	)
-	fmt.Println() // New line after progress bar completes
+	}.Run(riskManager.GetContext()) // Ctrl+C ends the wait; the manager is stopped below
 
-	fmt.Println("\n🛑 Stopping...")
-	if err := riskManager.Stop(); err != nil {
-		return fmt.Errorf("failed to stop: %w", err)
+	if err := stopOrchestrator(riskManager); err != nil {
+		return err
 	}
 
 	showOrchestratorMetrics(riskManager)
+	writeRunSummary(orchestrators.NewRunSummary(riskManager, orchConfig))
 	return nil
 }
 
@@ -768,6 +825,9 @@ func RunOrchestrator_PortfolioRebalancer() error {
 		UseMarketOrders:    true,
 		SlippageTolerance:  50,
 		MaxTradesPerCycle:  10,
+
+		// Auto-stop after 1 hour or 20 trades (--duration overrides; see RuntimeLimits)
+		Limits: orchestrators.RuntimeLimits{MaxRuntime: runDuration(time.Hour), MaxTrades: 20},
 	}
 
 	fmt.Println("\n📋 Configuration:")
@@ -785,16 +845,16 @@ func RunOrchestrator_PortfolioRebalancer() error {
 		return fmt.Errorf("failed to start: %w", err)
 	}
 
-	// Run for 1 hour with progress bar
+	// Run until a runtime limit stops it, with live progress bar and metrics
 	fmt.Println("  ✓ Starting monitoring...")
 	fmt.Println()
 
-	helpers.WaitWithProgressBarAndCallback(
-		3600,                      // 1 hour = 3600 seconds
-		"Portfolio Rebalancer Active",
-		10*time.Second, // Update callback every 10 seconds
-		func() bool {
-			// Display live portfolio metrics
+	helpers.Monitor{
+		Message:  "Portfolio Rebalancer Active",
+		Duration: orchConfig.Limits.MaxRuntime, // Ends early when a limit stops the orchestrator
+		Interval: 10 * time.Second,             // Update status every 10 seconds
+		Update: func() helpers.MonitorStatus {
+			// Live portfolio metrics
 			rebalanceCount := rebalancer.GetRebalanceCount()
 			allocations := rebalancer.GetCurrentAllocations()
 
@@ -815,24 +875,24 @@ func RunOrchestrator_PortfolioRebalancer() error {
 				balancedStr = "⚠️"
 			}
 
-			fmt.Printf("\r  📊 Rebalances: %d | Max Deviation: %.1f%% | Unbalanced: %d | Status: %s        ",
-				rebalanceCount,
-				maxDeviation,
-				needsAdjustment,
-				balancedStr)
-
-			return true // Continue monitoring
+			return helpers.MonitorStatus{
+				Line: fmt.Sprintf("📊 Rebalances: %d | Max Deviation: %.1f%% | Unbalanced: %d | Status: %s",
+					rebalanceCount, maxDeviation, needsAdjustment, balancedStr),
+				Fields: map[string]any{
+					"rebalances":        rebalanceCount,
+					"max_deviation_pct": maxDeviation,
+					"unbalanced":        needsAdjustment,
+				},
+			}
 		},
-		rebalancer.GetContext(),
-	)
-	fmt.Println() // New line after progress bar completes
+	}.Run(rebalancer.GetContext()) // Ctrl+C ends the wait; the rebalancer is stopped below
 
-	fmt.Println("\n🛑 Stopping...")
-	if err := rebalancer.Stop(); err != nil {
-		return fmt.Errorf("failed to stop: %w", err)
+	if err := stopOrchestrator(rebalancer); err != nil {
+		return err
 	}
 
 	showOrchestratorMetrics(rebalancer)
+	writeRunSummary(orchestrators.NewRunSummary(rebalancer, orchConfig))
 	return nil
 }
 
@@ -868,19 +928,96 @@ func RunOrchestrator_AdaptivePreset() error {
 	if err != nil {
 		return fmt.Errorf("preset execution failed: %w", err)
 	}
+	writeRunSummary(preset.Summary())
 
 	fmt.Printf("\n\n📊 Final Result: $%.2f\n", totalProfit)
 	return nil
 }
 
+// RunKillSwitch is the operator command for an emergency stop: it cancels
+// every pending order and, with --flatten, closes every position.
+//
+//	go run main.go kill [--flatten] [reason...]
+//
+// Trades of other running processes are not blocked by this command; trigger
+// their own KillSwitch (e.g. POST /kill) for that.
+func RunKillSwitch() error {
+	fmt.Println("\n=== KILL SWITCH ===")
+
+	flatten := false
+	var words []string
+	if len(opts.Args) > 1 {
+		for _, arg := range opts.Args[1:] {
+			if arg == "--flatten" {
+				flatten = true
+				continue
+			}
+			words = append(words, arg)
+		}
+	}
+	reason := strings.Join(words, " ")
+	if reason == "" {
+		reason = "operator command"
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	sugar, err := mt5.NewMT5Sugar(cfg.User, cfg.Password, cfg.GrpcServer)
+	if err != nil {
+		return fmt.Errorf("failed to create MT5Sugar: %w", err)
+	}
+
+	err = sugar.QuickConnect(cfg.MtCluster)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer sugar.GetService().GetAccount().Close()
+
+	fmt.Printf("  Reason:  %s\n", reason)
+	fmt.Printf("  Flatten: %v\n", flatten)
+
+	ks := mt5.NewKillSwitch(sugar, mt5.KillSwitchConfig{FlattenPositions: flatten})
+	event, err := ks.Trigger(reason)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n🛑 Cancelled %d pending orders, closed %d positions\n", event.CancelledOrders, event.ClosedPositions)
+	for _, e := range event.Errors {
+		fmt.Printf("  ⚠️  %s\n", e)
+	}
+	if len(event.Errors) > 0 {
+		return fmt.Errorf("kill switch finished with %d errors", len(event.Errors))
+	}
+	return nil
+}
+
 // ═════════════════════════════════════════════════════════════════
 // HELPER FUNCTIONS
 // ═════════════════════════════════════════════════════════════════
 
+// stopOrchestrator stops orch unless a runtime limit already did, and
+// reports why it stopped.
+func stopOrchestrator(orch orchestrators.Orchestrator) error {
+	if reason := orch.GetStatus().StopReason; reason != "" && !orch.IsRunning() {
+		fmt.Printf("\n⏹️  Stopped automatically: %s\n", reason)
+		return nil
+	}
+	fmt.Println("\n🛑 Stopping...")
+	if err := orch.Stop(); err != nil {
+		return fmt.Errorf("failed to stop: %w", err)
+	}
+	return nil
+}
+
 // showOrchestratorMetrics displays final orchestrator metrics.
 func showOrchestratorMetrics(orch orchestrators.Orchestrator) {
 	status := orch.GetStatus()
 	metrics := orch.GetMetrics()
+	recordOrchestrator(orch)
 
 	fmt.Println("\n" + strings.Repeat("=", 70))
 	fmt.Println("FINAL METRICS")
@@ -888,6 +1025,9 @@ func showOrchestratorMetrics(orch orchestrators.Orchestrator) {
 
 	fmt.Printf("\n📊 Orchestrator: %s\n", status.Name)
 	fmt.Printf("   Uptime:       %s\n", orchestrators.FormatDuration(status.Uptime))
+	if status.StopReason != "" {
+		fmt.Printf("   Stopped by:   %s\n", status.StopReason)
+	}
 	fmt.Printf("   Operations:   %d total (%d success, %d failed)\n",
 		status.SuccessCount+status.ErrorCount,
 		status.SuccessCount,
@@ -898,10 +1038,35 @@ func showOrchestratorMetrics(orch orchestrators.Orchestrator) {
 		fmt.Printf("   Total Trades:     %d\n", metrics.TotalTrades)
 		fmt.Printf("   Winning:          %d (%.1f%%)\n", metrics.WinningTrades, metrics.WinRate)
 		fmt.Printf("   Losing:           %d\n", metrics.LosingTrades)
+		fmt.Printf("   Gross P/L:        %.2f\n", metrics.GrossPnL)
+		fmt.Printf("   Fees:             %.2f\n", metrics.Fees)
 		fmt.Printf("   Net Profit:       %.2f\n", metrics.NetProfit)
 		fmt.Printf("   Current Positions: %d\n", metrics.CurrentPositions)
 	}
 
+	if metrics.FloatingPnL != 0 || metrics.MaxDrawdown > 0 {
+		fmt.Printf("\n📉 P/L & Drawdown:\n")
+		fmt.Printf("   Realized:         %.2f\n", metrics.NetProfit)
+		fmt.Printf("   Floating:         %.2f\n", metrics.FloatingPnL)
+		fmt.Printf("   Current DD:       %.2f (%.1f%%)\n", metrics.CurrentDrawdown, metrics.CurrentDrawdownPercent)
+		fmt.Printf("   Max DD:           %.2f (%.1f%%)\n", metrics.MaxDrawdown, metrics.MaxDrawdownPercent)
+	}
+
+	if len(metrics.BySymbol) > 0 {
+		symbols := make([]string, 0, len(metrics.BySymbol))
+		for symbol := range metrics.BySymbol {
+			symbols = append(symbols, symbol)
+		}
+		sort.Strings(symbols)
+
+		fmt.Printf("\n📋 By Symbol:\n")
+		for _, symbol := range symbols {
+			sym := metrics.BySymbol[symbol]
+			fmt.Printf("   %-10s trades %3d | net %10.2f | fees %8.2f | floating %10.2f\n",
+				symbol, sym.Trades, sym.NetProfit, sym.Fees, sym.FloatingPnL)
+		}
+	}
+
 	if status.LastError != "" {
 		fmt.Printf("\n⚠️  Last Error: %s\n", status.LastError)
 	}
@@ -124,6 +124,9 @@ type TrailingStopConfig struct {
 	Symbols          []string      // Symbols to manage (empty = all)
 	MinDistance      float64       // Minimum distance from current price
 	StepSize         float64       // Minimum step size for SL adjustments
+
+	// Runtime limits (zero = run until Stop)
+	Limits RuntimeLimits
 }
 
 // DefaultTrailingStopConfig returns sensible defaults.
@@ -192,7 +195,10 @@ func (t *TrailingStopManager) Start() error {
 	t.MarkStarted()
 
 	// Start monitoring loop
-	go t.monitorLoop()
+	t.GoSafe(t.monitorLoop)
+
+	// Stop automatically on MaxRuntime / MaxTrades / MaxLoss
+	t.EnforceLimits(t.config.Limits, t.Stop)
 
 	return nil
 }
@@ -364,7 +370,7 @@ func (t *TrailingStopManager) updatePositionTrailingStop(pos *pb.PositionInfo) b
 func (t *TrailingStopManager) modifyStopLoss(ticket uint64, newSL float64, tracker *positionTracker) bool {
 	err := t.sugar.ModifyPositionSL(ticket, newSL)
 	if err != nil {
-		t.IncrementError(fmt.Sprintf("failed to modify SL for #%d: %v", ticket, err))
+		t.IncrementError(fmt.Sprintf("failed to modify SL for #%d: %s", ticket, explainTradeError(t.sugar, err, tracker.symbol)))
 		return false
 	}
 
@@ -405,7 +411,8 @@ func (t *TrailingStopManager) isSymbolTracked(symbol string) bool {
 	return false
 }
 
-// cleanupClosedPositions removes trackers for closed positions.
+// cleanupClosedPositions removes trackers for closed positions and records
+// them as closed trades.
 func (t *TrailingStopManager) cleanupClosedPositions(openPositions []*pb.PositionInfo) {
 	// Build map of open position tickets
 	openTickets := make(map[uint64]bool)
@@ -414,11 +421,18 @@ func (t *TrailingStopManager) cleanupClosedPositions(openPositions []*pb.Positio
 	}
 
 	// Remove trackers for positions that are no longer open
+	var closed []uint64
 	for ticket := range t.trackedPositions {
 		if !openTickets[ticket] {
 			delete(t.trackedPositions, ticket)
+			closed = append(closed, ticket)
 		}
 	}
+
+	// Count them as closed trades (usually stopped out by the trailed SL)
+	if err := t.RecordClosedTrades(t.sugar, closed...); err != nil {
+		t.IncrementError(err.Error())
+	}
 }
 
 /* ══════════════════════════════════════════════════════════════════════════════
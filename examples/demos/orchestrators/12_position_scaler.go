@@ -56,8 +56,10 @@
    • MaxLossToAverage: Max loss before stopping (averaging, default: 500)
    • InitialLotSize: Base position size (default: 0.10 lots)
    • ScaleLotSize: Size for each scale-in (default: 0.05 lots)
+   • Sizer: Optional mt5.PositionSizer, overrides ScaleLotSize (default: nil)
    • MaxScales: Maximum number of scale-ins (default: 3)
    • TotalMaxLotSize: Maximum total position size (default: 1.0 lots)
+   • EquityScaled: ScaleLotSize/TotalMaxLotSize are lots per $10k equity (default: false)
    • StopLossPerScale: SL distance for scale-ins (default: 150 pts)
 
  USE CASES:
@@ -132,6 +134,8 @@ type PositionScalerConfig struct {
 	ScaleLotSize    float64 // Size for each scale-in
 	MaxScales       int     // Maximum number of scale-ins
 	ReducePerScale  float64 // Reduce lot size by this % each scale (0-1)
+	Sizer           mt5.PositionSizer // Optional: sizes each scale-in (overrides ScaleLotSize, SL = StopLossPerScale)
+	EquityScaled    bool    // ScaleLotSize and TotalMaxLotSize are lots per 10k equity (rescaled as equity changes)
 
 	// Risk Management
 	TotalMaxLotSize float64 // Maximum total position size
@@ -141,6 +145,9 @@ type PositionScalerConfig struct {
 	// Operational
 	Symbols       []string      // Symbols to manage (empty = all)
 	CheckInterval time.Duration // How often to check for scaling opportunities
+
+	// Runtime limits (zero = run until Stop)
+	Limits RuntimeLimits
 }
 
 // DefaultPositionScalerConfig returns sensible defaults for pyramiding.
@@ -215,7 +222,10 @@ func (p *PositionScaler) Start() error {
 	p.MarkStarted()
 
 	// Start monitoring loop
-	go p.monitorLoop()
+	p.GoSafe(p.monitorLoop)
+
+	// Stop automatically on MaxRuntime / MaxTrades / MaxLoss
+	p.EnforceLimits(p.config.Limits, p.Stop)
 
 	return nil
 }
@@ -268,10 +278,18 @@ func (p *PositionScaler) checkScalingOpportunities() {
 	// Check each group for scaling opportunities
 	for _, group := range p.trackedGroups {
 		if p.shouldScale(group) {
+			// Scale-ins add exposure: hold them back during volatility spikes
+			scaleIn := p.config.Mode != ScaleOut
+			if scaleIn && p.ThrottleEntry(group.Symbol, group.IsBuy) != nil {
+				continue
+			}
 			if err := p.executeScale(group); err != nil {
-				p.IncrementError(fmt.Sprintf("scale failed for %s: %v", group.Symbol, err))
+				p.IncrementError(fmt.Sprintf("scale failed for %s: %s", group.Symbol, explainTradeError(p.sugar, err, group.Symbol)))
 			} else {
 				p.IncrementSuccess()
+				if scaleIn {
+					p.RecordEntry(group.Symbol, group.IsBuy)
+				}
 			}
 		}
 	}
@@ -286,6 +304,10 @@ func (p *PositionScaler) checkScalingOpportunities() {
 func (p *PositionScaler) updateTrackedGroups(positions []*pb.PositionInfo) {
 	// Clear groups that are no longer open
 	activeSymbols := make(map[string]bool)
+	openTickets := make(map[uint64]bool, len(positions))
+	for _, pos := range positions {
+		openTickets[pos.Ticket] = true
+	}
 
 	for _, pos := range positions {
 		// Skip if symbol not in our list
@@ -321,12 +343,21 @@ func (p *PositionScaler) updateTrackedGroups(positions []*pb.PositionInfo) {
 		group.TotalLotSize = totalSize
 	}
 
-	// Remove groups for symbols no longer in positions
-	for symbol := range p.trackedGroups {
+	// Count closed positions of the groups as trades, then drop closed groups
+	var closed []uint64
+	for symbol, group := range p.trackedGroups {
+		for _, ticket := range append([]uint64{group.BaseTicket}, group.ScaleTickets...) {
+			if !openTickets[ticket] {
+				closed = append(closed, ticket)
+			}
+		}
 		if !activeSymbols[symbol] {
 			delete(p.trackedGroups, symbol)
 		}
 	}
+	if err := p.RecordClosedTrades(p.sugar, closed...); err != nil {
+		p.IncrementError(err.Error())
+	}
 }
 
 // shouldScale determines if a position group should be scaled.
@@ -337,8 +368,12 @@ func (p *PositionScaler) shouldScale(group *PositionGroup) bool {
 	}
 
 	// Check if total position size would exceed maximum
-	nextScaleSize := p.calculateNextScaleSize(group)
-	if group.TotalLotSize+nextScaleSize > p.config.TotalMaxLotSize {
+	nextScaleSize, err := p.calculateNextScaleSize(group)
+	if err != nil {
+		p.IncrementError(err.Error())
+		return false
+	}
+	if group.TotalLotSize+nextScaleSize > p.maxTotalLotSize() {
 		return false
 	}
 
@@ -446,7 +481,10 @@ func (p *PositionScaler) executeScale(group *PositionGroup) error {
 	}
 
 	// Scale in: open additional position
-	lotSize := p.calculateNextScaleSize(group)
+	lotSize, err := p.calculateNextScaleSize(group)
+	if err != nil {
+		return err
+	}
 
 	// Get current price for SL calculation
 	priceInfo, err := p.sugar.GetPriceInfo(group.Symbol)
@@ -480,13 +518,13 @@ func (p *PositionScaler) executeScale(group *PositionGroup) error {
 	}
 
 	// Update group
+	p.Own(ticket)
 	group.ScaleTickets = append(group.ScaleTickets, ticket)
 	group.ScaleCount++
 	group.LastScaleTime = time.Now()
 	group.TotalLotSize += lotSize
 
 	p.UpdateMetrics(func(m *OrchestratorMetrics) {
-		m.TotalTrades++
 		m.LastOperation = fmt.Sprintf("[SCALE #%d/%d] Opened %s position #%d → +%.2f lots (Total: %.2f)",
 			group.ScaleCount, p.config.MaxScales, group.Symbol, ticket, lotSize, group.TotalLotSize)
 	})
@@ -518,7 +556,6 @@ func (p *PositionScaler) executeScaleOut(group *PositionGroup) error {
 	group.TotalLotSize -= closeVolume
 
 	p.UpdateMetrics(func(m *OrchestratorMetrics) {
-		m.TotalTrades++
 		m.LastOperation = fmt.Sprintf("[SCALE-OUT #%d/%d] Closed %s position #%d → -%.2f lots (Remaining: %.2f)",
 			group.ScaleCount, p.config.MaxScales, group.Symbol, largestPos.Ticket, closeVolume, group.TotalLotSize)
 	})
@@ -527,16 +564,54 @@ func (p *PositionScaler) executeScaleOut(group *PositionGroup) error {
 }
 
 // calculateNextScaleSize calculates the lot size for next scale-in.
-func (p *PositionScaler) calculateNextScaleSize(group *PositionGroup) float64 {
+// A sizer error skips the scale: ScaleLotSize is no fallback, since with
+// EquityScaled it is lots per 10k rather than a volume.
+func (p *PositionScaler) calculateNextScaleSize(group *PositionGroup) (float64, error) {
 	baseSize := p.config.ScaleLotSize
+	sizer := p.config.Sizer
+	if sizer == nil && p.config.EquityScaled {
+		sizer = &mt5.EquityScaledSizer{LotsPer10k: p.config.ScaleLotSize}
+	}
+	if sizer != nil {
+		size, err := p.sugar.SizePosition(group.Symbol, sizer, p.config.StopLossPerScale)
+		if err != nil {
+			return 0, fmt.Errorf("position sizer failed: %w", err)
+		}
+		baseSize = size
+	}
 
 	// Apply reduction per scale if configured
 	if p.config.ReducePerScale > 0 {
 		reduction := 1.0 - (p.config.ReducePerScale * float64(group.ScaleCount))
-		baseSize = baseSize * reduction
+		if reduction <= 0 {
+			return 0, fmt.Errorf("scale #%d reduced to zero (ReducePerScale %.2f)", group.ScaleCount+1, p.config.ReducePerScale)
+		}
+		info, err := p.sugar.GetSymbolInfo(group.Symbol)
+		if err != nil {
+			return 0, err
+		}
+		baseSize = mt5.NormalizeVolume(baseSize*reduction, mt5.SizingInput{
+			VolumeMin:  info.VolumeMin,
+			VolumeMax:  info.VolumeMax,
+			VolumeStep: info.VolumeStep,
+		})
 	}
 
-	return baseSize
+	return baseSize, nil
+}
+
+// maxTotalLotSize returns TotalMaxLotSize, rescaled to the current equity
+// with EquityScaled.
+func (p *PositionScaler) maxTotalLotSize() float64 {
+	if !p.config.EquityScaled {
+		return p.config.TotalMaxLotSize
+	}
+	equity, err := p.sugar.GetEquity()
+	if err != nil {
+		p.IncrementError(fmt.Sprintf("failed to get equity: %v", err))
+		return 0
+	}
+	return mt5.EquityScaledLots(p.config.TotalMaxLotSize, equity)
 }
 
 // getSymbolPoint gets or caches the point value for a symbol.
@@ -112,6 +112,8 @@
    • GridSize: Number of levels above/below (default: 5 = 10 total orders)
    • GridStep: Distance between levels in points (default: 100pts = 10 pips)
    • LotSize: Volume for each order (default: 0.01 lots)
+   • Sizer: Optional mt5.PositionSizer, overrides LotSize (default: nil)
+   • EquityScaled: LotSize is lots per $10k equity, rescaled as equity changes (default: false)
    • MaxPositions: Max concurrent positions (default: 10)
    • TakeProfit: TP distance (default: 0 = use GridStep)
    • StopLoss: SL distance (default: 0 = no SL)
@@ -153,6 +155,9 @@ package orchestrators
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	pb "github.com/MetaRPC/GoMT5/package"
@@ -169,11 +174,16 @@ type GridTraderConfig struct {
 	GridSize       int           // Number of grid levels (above and below)
 	GridStep       float64       // Distance between levels in points
 	LotSize        float64       // Volume for each order
+	Sizer          mt5.PositionSizer // Optional: sizes each order (overrides LotSize)
+	EquityScaled   bool          // LotSize is lots per 10k equity (rescaled as equity changes)
 	MaxPositions   int           // Maximum concurrent positions
 	TakeProfit     float64       // Take profit in points (0 = use grid step)
 	StopLoss       float64       // Stop loss in points (0 = no SL)
 	CheckInterval  time.Duration // How often to check and update grid
 	RebuildOnFill  bool          // Rebuild entire grid when order fills
+
+	// Runtime limits (zero = run until Stop)
+	Limits RuntimeLimits
 }
 
 // DefaultGridTraderConfig returns sensible default configuration.
@@ -191,6 +201,33 @@ func DefaultGridTraderConfig(symbol string) GridTraderConfig {
 	}
 }
 
+// ══════════════════════════════════════════════════════════════════════════════
+// GRID LEVELS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// GridLevelState is the lifecycle state of one grid rung.
+type GridLevelState string
+
+const (
+	GridLevelPending GridLevelState = "pending" // Pending order waiting at the level
+	GridLevelFilled  GridLevelState = "filled"  // Order filled, position open
+	GridLevelClosed  GridLevelState = "closed"  // Filled and closed (TP/SL), or removed outside the grid
+	GridLevelFailed  GridLevelState = "failed"  // Order could not be placed
+)
+
+// GridLevel is one rung of the grid ladder.
+type GridLevel struct {
+	Index    int            `json:"index"`            // +1..+GridSize above the center, -1..-GridSize below
+	Price    float64        `json:"price"`            // Order price
+	Side     string         `json:"side"`             // "BUY LIMIT" or "SELL LIMIT"
+	State    GridLevelState `json:"state"`            // Current state
+	Ticket   uint64         `json:"ticket,omitempty"` // Pending order ticket (= position identifier once filled)
+	Volume   float64        `json:"volume,omitempty"` // Order volume
+	PlacedAt time.Time      `json:"placed_at"`        // When the order was placed
+	FilledAt time.Time      `json:"filled_at"`        // Position open time (zero until filled)
+	Error    string         `json:"error,omitempty"`  // Placement error (GridLevelFailed)
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // GRID TRADER IMPLEMENTATION
 // ══════════════════════════════════════════════════════════════════════════════
@@ -207,6 +244,9 @@ type GridTrader struct {
 	digits        int         // Symbol decimal digits
 	point         float64     // Point value for symbol
 	currentPrice  float64     // Last known price
+
+	levelsMu sync.RWMutex
+	levels   []GridLevel // Ladder of the current grid (see GetLevels)
 }
 
 // NewGridTrader creates a new grid trading orchestrator.
@@ -245,7 +285,10 @@ func (g *GridTrader) Start() error {
 	}
 
 	// Start monitoring loop
-	go g.monitorLoop()
+	g.GoSafe(g.monitorLoop)
+
+	// Stop automatically on MaxRuntime / MaxTrades / MaxLoss
+	g.EnforceLimits(g.config.Limits, g.Stop)
 
 	return nil
 }
@@ -300,29 +343,73 @@ func (g *GridTrader) buildGrid() error {
 	// Calculate grid levels
 	g.gridLevels = make([]float64, 0)
 	gridStepPrice := g.config.GridStep * g.point
+	levels := make([]GridLevel, 0, g.config.GridSize*2)
 
 	// Build levels above and below current price
 	for i := 1; i <= g.config.GridSize; i++ {
 		levelAbove := g.currentPrice + float64(i)*gridStepPrice
 		levelBelow := g.currentPrice - float64(i)*gridStepPrice
 		g.gridLevels = append(g.gridLevels, levelAbove, levelBelow)
+		levels = append(levels,
+			GridLevel{Index: i, Price: levelAbove, Side: "SELL LIMIT"},
+			GridLevel{Index: -i, Price: levelBelow, Side: "BUY LIMIT"})
 	}
 
-	// Place orders at each grid level
-	for _, level := range g.gridLevels {
-		if level > g.currentPrice {
-			// Place SELL LIMIT above current price
-			if err := g.placeSellLimit(level); err != nil {
-				g.IncrementError(fmt.Sprintf("failed to place sell limit: %v", err))
-			}
-		} else {
-			// Place BUY LIMIT below current price
-			if err := g.placeBuyLimit(level); err != nil {
-				g.IncrementError(fmt.Sprintf("failed to place buy limit: %v", err))
+	// Place orders at each grid level. The worker pool keeps other batch
+	// operations (rebalancer, bulk close) off this symbol meanwhile.
+	pool := g.sugar.WorkerPool()
+	ctx := context.Background()
+	for i := range levels {
+		level := &levels[i]
+		var ticket uint64
+		var volume float64
+		err := pool.Do(ctx, g.config.Symbol, func(context.Context) error {
+			var err error
+			if level.Index > 0 {
+				// Place SELL LIMIT above current price
+				ticket, volume, err = g.placeSellLimit(level.Price)
+			} else {
+				// Place BUY LIMIT below current price
+				ticket, volume, err = g.placeBuyLimit(level.Price)
 			}
+			return err
+		})
+		decision := DecisionEntry{
+			Symbol: g.config.Symbol,
+			Action: strings.ToLower(level.Side),
+			Reason: fmt.Sprintf("grid level %+d of ±%d around mid price", level.Index, g.config.GridSize),
+			Signals: map[string]float64{
+				"mid_price": g.currentPrice,
+				"level":     level.Price,
+			},
+			Thresholds: map[string]float64{
+				"grid_step":   g.config.GridStep,
+				"take_profit": g.config.TakeProfit,
+				"stop_loss":   g.config.StopLoss,
+			},
+			Ticket: ticket,
+		}
+		if err != nil {
+			g.IncrementError(fmt.Sprintf("failed to place %s: %s", strings.ToLower(level.Side), explainTradeError(g.sugar, err, g.config.Symbol)))
+			level.State = GridLevelFailed
+			level.Error = err.Error()
+			decision.Skipped = true
+			decision.Reason = fmt.Sprintf("grid level %+d rejected: %v", level.Index, err)
+			g.LogDecision(decision)
+			continue
 		}
+		g.LogDecision(decision)
+		level.State = GridLevelPending
+		level.Ticket = ticket
+		level.Volume = volume
+		level.PlacedAt = time.Now()
 	}
 
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Price > levels[j].Price })
+	g.levelsMu.Lock()
+	g.levels = levels
+	g.levelsMu.Unlock()
+
 	g.UpdateMetrics(func(m *OrchestratorMetrics) {
 		m.LastOperation = fmt.Sprintf("Built grid with %d levels", len(g.gridLevels))
 	})
@@ -330,8 +417,34 @@ func (g *GridTrader) buildGrid() error {
 	return nil
 }
 
+// orderVolume returns the volume for a new grid order.
+// With a Sizer, the SL distance is StopLoss (or GridStep when no SL is set).
+// With EquityScaled, LotSize is rescaled to the current equity.
+// A sizer error skips the order: LotSize is no fallback, since with
+// EquityScaled it is lots per 10k rather than a volume.
+func (g *GridTrader) orderVolume() (float64, error) {
+	sizer := g.config.Sizer
+	if sizer == nil && g.config.EquityScaled {
+		sizer = &mt5.EquityScaledSizer{LotsPer10k: g.config.LotSize}
+	}
+	if sizer == nil {
+		return g.config.LotSize, nil
+	}
+
+	stopPoints := g.config.StopLoss
+	if stopPoints <= 0 {
+		stopPoints = g.config.GridStep
+	}
+
+	volume, err := g.sugar.SizePosition(g.config.Symbol, sizer, stopPoints)
+	if err != nil {
+		return 0, fmt.Errorf("position sizer failed: %w", err)
+	}
+	return volume, nil
+}
+
 // placeBuyLimit places a BUY LIMIT order at specified price.
-func (g *GridTrader) placeBuyLimit(price float64) error {
+func (g *GridTrader) placeBuyLimit(price float64) (uint64, float64, error) {
 	// Calculate TP/SL if configured
 	tp := 0.0
 	sl := 0.0
@@ -346,20 +459,25 @@ func (g *GridTrader) placeBuyLimit(price float64) error {
 		sl = price - g.config.StopLoss*g.point
 	}
 
+	volume, err := g.orderVolume()
+	if err != nil {
+		return 0, 0, err
+	}
+
 	var ticket uint64
-	var err error
 
 	if g.config.StopLoss > 0 || g.config.TakeProfit > 0 {
-		ticket, err = g.sugar.BuyLimitWithSLTP(g.config.Symbol, g.config.LotSize, price, sl, tp)
+		ticket, err = g.sugar.BuyLimitWithSLTP(g.config.Symbol, volume, price, sl, tp)
 	} else {
-		ticket, err = g.sugar.BuyLimit(g.config.Symbol, g.config.LotSize, price)
+		ticket, err = g.sugar.BuyLimit(g.config.Symbol, volume, price)
 	}
 
 	if err != nil {
-		return err
+		return 0, 0, err
 	}
 
 	g.activeOrders = append(g.activeOrders, ticket)
+	g.Own(ticket)
 	g.IncrementSuccess()
 
 	// Log with numbering
@@ -375,11 +493,11 @@ func (g *GridTrader) placeBuyLimit(price float64) error {
 			orderNum, totalOrders, price, ticket)
 	})
 
-	return nil
+	return ticket, volume, nil
 }
 
 // placeSellLimit places a SELL LIMIT order at specified price.
-func (g *GridTrader) placeSellLimit(price float64) error {
+func (g *GridTrader) placeSellLimit(price float64) (uint64, float64, error) {
 	// Calculate TP/SL if configured
 	tp := 0.0
 	sl := 0.0
@@ -394,20 +512,25 @@ func (g *GridTrader) placeSellLimit(price float64) error {
 		sl = price + g.config.StopLoss*g.point
 	}
 
+	volume, err := g.orderVolume()
+	if err != nil {
+		return 0, 0, err
+	}
+
 	var ticket uint64
-	var err error
 
 	if g.config.StopLoss > 0 || g.config.TakeProfit > 0 {
-		ticket, err = g.sugar.SellLimitWithSLTP(g.config.Symbol, g.config.LotSize, price, sl, tp)
+		ticket, err = g.sugar.SellLimitWithSLTP(g.config.Symbol, volume, price, sl, tp)
 	} else {
-		ticket, err = g.sugar.SellLimit(g.config.Symbol, g.config.LotSize, price)
+		ticket, err = g.sugar.SellLimit(g.config.Symbol, volume, price)
 	}
 
 	if err != nil {
-		return err
+		return 0, 0, err
 	}
 
 	g.activeOrders = append(g.activeOrders, ticket)
+	g.Own(ticket)
 	g.IncrementSuccess()
 
 	// Log with numbering
@@ -423,7 +546,7 @@ func (g *GridTrader) placeSellLimit(price float64) error {
 			orderNum, totalOrders, price, ticket)
 	})
 
-	return nil
+	return ticket, volume, nil
 }
 
 // monitorLoop continuously monitors the grid and adjusts as needed.
@@ -450,6 +573,8 @@ func (g *GridTrader) checkAndUpdateGrid() {
 		return
 	}
 
+	g.refreshLevels(positions)
+
 	// Update metrics
 	g.UpdateMetrics(func(m *OrchestratorMetrics) {
 		m.CurrentPositions = len(positions)
@@ -490,16 +615,74 @@ func (g *GridTrader) checkAndUpdateGrid() {
 
 	// Update positions profit tracking
 	totalProfit := 0.0
+	floating := make(map[string]float64)
 	for _, pos := range positions {
 		totalProfit += pos.Profit
+		floating[pos.Symbol] += pos.Profit + pos.Swap + pos.PositionCommission
 	}
 
 	g.UpdateMetrics(func(m *OrchestratorMetrics) {
-		m.CurrentDrawdown = totalProfit
+		m.SetFloating(floating)
 		m.LastOperation = fmt.Sprintf("Monitoring %d positions, P/L: %.2f", len(positions), totalProfit)
 	})
 }
 
+// GetLevels returns the ladder of the current grid, highest price first.
+// Level states are refreshed on every check interval.
+func (g *GridTrader) GetLevels() []GridLevel {
+	g.levelsMu.RLock()
+	defer g.levelsMu.RUnlock()
+	return append([]GridLevel(nil), g.levels...)
+}
+
+// refreshLevels moves levels to filled or closed from the open positions
+// and pending orders of the account, and records closed levels as trades.
+func (g *GridTrader) refreshLevels(positions []*pb.PositionInfo) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, orderTickets, err := g.sugar.GetService().GetOpenedTickets(ctx)
+	if err != nil {
+		g.IncrementError(fmt.Sprintf("failed to get pending orders: %v", err))
+		return
+	}
+
+	pending := make(map[uint64]bool, len(orderTickets))
+	for _, ticket := range orderTickets {
+		pending[uint64(ticket)] = true
+	}
+	open := make(map[uint64]*pb.PositionInfo, len(positions))
+	for _, pos := range positions {
+		open[uint64(pos.Identifier)] = pos
+	}
+
+	var closed []uint64
+	g.levelsMu.Lock()
+	for i := range g.levels {
+		level := &g.levels[i]
+		if level.State != GridLevelPending && level.State != GridLevelFilled {
+			continue
+		}
+		if pos, ok := open[level.Ticket]; ok {
+			level.State = GridLevelFilled
+			if pos.OpenTime != nil {
+				level.FilledAt = pos.OpenTime.AsTime()
+			}
+			continue
+		}
+		if !pending[level.Ticket] {
+			level.State = GridLevelClosed
+			closed = append(closed, level.Ticket)
+		}
+	}
+	g.levelsMu.Unlock()
+
+	// Filled levels that left the book closed at their TP/SL; a pending level
+	// may have filled and closed between two checks
+	if err := g.RecordClosedTrades(g.sugar, closed...); err != nil {
+		g.IncrementError(err.Error())
+	}
+}
+
 // cleanupOrders cancels all pending orders.
 func (g *GridTrader) cleanupOrders() {
 	// Cancel all active pending orders using Service.CloseOrder
@@ -511,13 +694,17 @@ func (g *GridTrader) cleanupOrders() {
 		fmt.Printf("\n  🧹 Cleaning up %d pending orders...\n", totalOrders)
 	}
 
+	pool := g.sugar.WorkerPool()
 	deletedCount := 0
 	for i, ticket := range g.activeOrders {
 		// CloseOrder can delete pending orders (not just close positions)
 		req := &pb.OrderCloseRequest{
 			Ticket: ticket,
 		}
-		_, err := service.CloseOrder(ctx, req)
+		err := pool.Do(ctx, g.config.Symbol, func(ctx context.Context) error {
+			_, err := service.CloseOrder(ctx, req)
+			return err
+		})
 		if err != nil {
 			g.IncrementError(fmt.Sprintf("failed to delete order #%d: %v", ticket, err))
 			fmt.Printf("  [CLEANUP %d/%d] ❌ Failed to delete order #%d: %v\n", i+1, totalOrders, ticket, err)
@@ -744,6 +931,11 @@ EXPECTED RESULT:
   0.01 = 1 micro lot
   Tip: Start small! GridSize × LotSize = total exposure
 
+• EquityScaled (bool)
+  Treat LotSize as lots per $10,000 equity
+  LotSize=0.01 → 0.01 lots at $10k, 0.025 at $25k, 0.005 at $5k (min lot applies)
+  Tip: Keeps grid risk proportional as the account grows or draws down
+
 • MaxPositions (int)
   Maximum concurrent open positions allowed
   Safety limit to prevent overexposure
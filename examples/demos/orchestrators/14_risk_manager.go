@@ -30,7 +30,8 @@
 
  KEY PROTECTIONS:
    1️⃣ Drawdown Protection   2️⃣ Daily Loss Limit   3️⃣ Margin Safety
-   4️⃣ Position Limits       5️⃣ Daily Profit Target
+   4️⃣ Position Limits       5️⃣ Daily Profit Target   6️⃣ Portfolio VaR Cap
+   7️⃣ Currency Exposure (net lots / notional per currency or cluster)
 
  COMMAND-LINE USAGE:
    cd examples/demos
@@ -48,7 +49,9 @@ package orchestrators
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	mt5 "github.com/MetaRPC/GoMT5/examples/mt5"
@@ -77,10 +80,27 @@ type RiskManagerConfig struct {
 	MaxSymbolExposure int     // Maximum positions per symbol
 	MaxPositionSize   float64 // Maximum lot size per position
 
+	// Portfolio VaR (0 = disabled)
+	MaxPortfolioVaR float64       // Block new entries above this VaR (account currency)
+	VaRConfidence   float64       // VaR confidence level (e.g., 0.99)
+	VaRTimeframe    time.Duration // Candle size for returns, also the VaR horizon
+
+	// Currency Exposure (nil = disabled), keyed by currency ("USD") or cluster name
+	ExposureClusters    []mt5.ExposureCluster // Currency groups, e.g. {"USD bloc", [USD CAD HKD]}
+	MaxCurrencyLots     map[string]float64    // Max absolute net lots per currency/cluster
+	MaxCurrencyNotional map[string]float64    // Max absolute net notional per currency/cluster (account currency)
+
 	// Operational
 	CheckInterval      time.Duration // How often to check risk
 	EnableAutoClose    bool          // Automatically close positions
 	EnableTradeBlocking bool         // Block new trades when limits hit
+
+	// KillSwitch is engaged before the emergency close on critical breaches:
+	// it cancels pendings and blocks every new trade of the account (nil = off)
+	KillSwitch *mt5.KillSwitch
+
+	// Runtime limits (zero = run until Stop)
+	Limits RuntimeLimits
 }
 
 // DefaultRiskManagerConfig returns conservative default settings.
@@ -95,12 +115,22 @@ func DefaultRiskManagerConfig() RiskManagerConfig {
 		MaxOpenPositions:    20,
 		MaxSymbolExposure:   5,
 		MaxPositionSize:     1.0,
+		MaxPortfolioVaR:     0,
+		VaRConfidence:       0.99,
+		VaRTimeframe:        time.Minute,
 		CheckInterval:       5 * time.Second,
 		EnableAutoClose:     true,
 		EnableTradeBlocking: true,
 	}
 }
 
+// ApplyMonteCarlo sets the drawdown limits to the simulated worst case at the
+// given confidence (e.g., 0.95): trading stops once live drawdown is deeper
+// than that share of the resampled trade sequences produced.
+func (c *RiskManagerConfig) ApplyMonteCarlo(result *mt5.MonteCarloResult, confidence float64) {
+	c.MaxDrawdownAbsolute, c.MaxDrawdownPercent = result.WorstDrawdown(confidence)
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // RISK MANAGER IMPLEMENTATION
 // ══════════════════════════════════════════════════════════════════════════════
@@ -119,30 +149,45 @@ type RiskManager struct {
 	tradingBlocked    bool
 	lastResetDate     time.Time
 
+	// Portfolio VaR
+	varCalc    *mt5.VaRCalculator
+	lastVaR    *mt5.VaRResult
+	varBlocked bool
+
+	// Currency exposure over limit: currency/cluster → sign of the exposure
+	// (+1 long, -1 short); entries adding to it are rejected
+	currencyBreaches map[string]float64
+
 	// Risk Events
 	riskEvents []RiskEvent
 }
 
 // RiskEvent records a risk limit breach.
 type RiskEvent struct {
-	Timestamp   time.Time
-	EventType   string
-	Severity    string
-	Description string
-	Value       float64
-	Limit       float64
-	ActionTaken string
+	Timestamp   time.Time `json:"timestamp"`
+	EventType   string    `json:"event_type"`
+	Severity    string    `json:"severity"`
+	Description string    `json:"description"`
+	Value       float64   `json:"value"`
+	Limit       float64   `json:"limit"`
+	ActionTaken string    `json:"action_taken,omitempty"`
 }
 
 // NewRiskManager creates a new risk management orchestrator.
 func NewRiskManager(sugar *mt5.MT5Sugar, config RiskManagerConfig) *RiskManager {
-	return &RiskManager{
+	r := &RiskManager{
 		BaseOrchestrator: NewBaseOrchestrator("Risk Manager"),
 		sugar:            sugar,
 		config:           config,
 		riskEvents:       make([]RiskEvent, 0),
 		lastResetDate:    time.Now(),
 	}
+
+	if config.MaxPortfolioVaR > 0 {
+		r.varCalc = mt5.NewVaRCalculator(sugar.GetService(), config.VaRConfidence, config.VaRTimeframe, 0)
+	}
+
+	return r
 }
 
 // Start begins risk monitoring.
@@ -164,7 +209,10 @@ func (r *RiskManager) Start() error {
 	r.MarkStarted()
 
 	// Start monitoring loop
-	go r.monitorLoop()
+	r.GoSafe(r.monitorLoop)
+
+	// Stop automatically on MaxRuntime / MaxTrades / MaxLoss
+	r.EnforceLimits(r.config.Limits, r.Stop)
 
 	return nil
 }
@@ -245,8 +293,7 @@ func (r *RiskManager) checkRiskLimits() {
 
 	// Update metrics
 	r.UpdateMetrics(func(m *OrchestratorMetrics) {
-		m.CurrentDrawdown = currentDrawdown
-		m.MaxDrawdown = drawdownPercent
+		m.ObserveEquity(equity)
 	})
 
 	// Check each risk limit
@@ -254,6 +301,8 @@ func (r *RiskManager) checkRiskLimits() {
 	r.checkDailyLimits()
 	r.checkMarginLimits(marginLevel)
 	r.checkPositionLimits()
+	r.checkPortfolioVaR()
+	r.checkCurrencyExposure()
 
 	// Update status
 	r.UpdateMetrics(func(m *OrchestratorMetrics) {
@@ -363,22 +412,230 @@ func (r *RiskManager) checkPositionLimits() {
 	}
 }
 
-// closeAllPositionsEmergency closes all positions immediately.
+// checkPortfolioVaR samples prices of held symbols and caps new entries
+// while portfolio VaR is above MaxPortfolioVaR.
+// VaR needs at least 20 closed candles of history before it reports a value.
+func (r *RiskManager) checkPortfolioVaR() {
+	if r.varCalc == nil {
+		return
+	}
+
+	positions, err := r.sugar.GetOpenPositions()
+	if err != nil {
+		return
+	}
+
+	ctx := r.GetContext()
+	service := r.sugar.GetService()
+	sampled := make(map[string]bool)
+	for _, pos := range positions {
+		if sampled[pos.Symbol] {
+			continue
+		}
+		sampled[pos.Symbol] = true
+		if tick, err := service.GetSymbolTick(ctx, pos.Symbol); err == nil {
+			r.varCalc.Observe(tick)
+		}
+	}
+
+	result, err := r.varCalc.Calculate(ctx)
+	if err != nil {
+		r.IncrementError(fmt.Sprintf("VaR calculation failed: %v", err))
+		return
+	}
+	r.lastVaR = result
+
+	if result.VaR > r.config.MaxPortfolioVaR {
+		r.logRiskEvent("PORTFOLIO_VAR", "WARNING",
+			fmt.Sprintf("Portfolio VaR $%.2f exceeds limit $%.2f", result.VaR, r.config.MaxPortfolioVaR),
+			result.VaR, r.config.MaxPortfolioVaR)
+		r.varBlocked = r.config.EnableTradeBlocking
+		return
+	}
+
+	r.varBlocked = false
+}
+
+// checkCurrencyExposure compares net exposure per currency and cluster with
+// MaxCurrencyLots / MaxCurrencyNotional and records the breached directions.
+func (r *RiskManager) checkCurrencyExposure() {
+	if len(r.config.MaxCurrencyLots) == 0 && len(r.config.MaxCurrencyNotional) == 0 {
+		return
+	}
+
+	exposure, err := r.sugar.GetCurrencyExposure()
+	if err != nil {
+		r.IncrementError(fmt.Sprintf("currency exposure failed: %v", err))
+		return
+	}
+	all := make(map[string]*mt5.CurrencyExposure, len(exposure))
+	for name, e := range exposure {
+		all[name] = e
+	}
+	for name, e := range mt5.ClusterExposure(exposure, r.config.ExposureClusters...) {
+		all[name] = e
+	}
+
+	breaches := make(map[string]float64)
+	for name, e := range all {
+		if limit, ok := r.config.MaxCurrencyLots[name]; ok && math.Abs(e.NetLots) > limit {
+			r.logRiskEvent("CURRENCY_EXPOSURE", "WARNING",
+				fmt.Sprintf("%s net exposure %+.2f lots exceeds limit %.2f", name, e.NetLots, limit),
+				math.Abs(e.NetLots), limit)
+			breaches[name] = math.Copysign(1, e.NetLots)
+		}
+		if limit, ok := r.config.MaxCurrencyNotional[name]; ok && math.Abs(e.Notional) > limit {
+			r.logRiskEvent("CURRENCY_EXPOSURE", "WARNING",
+				fmt.Sprintf("%s net exposure $%+.2f exceeds limit $%.2f", name, e.Notional, limit),
+				math.Abs(e.Notional), limit)
+			breaches[name] = math.Copysign(1, e.Notional)
+		}
+	}
+	r.currencyBreaches = breaches
+}
+
+// checkCurrencyEntry rejects an order that adds to a currency or cluster
+// already over its exposure limit. Orders reducing that exposure pass.
+func (r *RiskManager) checkCurrencyEntry(req mt5.OrderRequest) error {
+	if len(r.currencyBreaches) == 0 {
+		return nil
+	}
+	base, profit, err := r.sugar.SymbolCurrencies(req.Symbol)
+	if err != nil {
+		return fmt.Errorf("currency exposure check failed: %w", err)
+	}
+
+	direction := 1.0
+	if !req.IsBuy() {
+		direction = -1
+	}
+	legs := map[string]float64{profit: -direction}
+	if base == "" || base == profit {
+		legs[profit] = direction
+	} else {
+		legs[base] = direction
+	}
+
+	for currency, sign := range legs {
+		names := []string{currency}
+		for _, cluster := range r.config.ExposureClusters {
+			for _, member := range cluster.Currencies {
+				if member == currency {
+					names = append(names, cluster.Name)
+				}
+			}
+		}
+		for _, name := range names {
+			if breach, ok := r.currencyBreaches[name]; ok && breach == sign {
+				return fmt.Errorf("%s exposure over limit, entry would increase it", name)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckNewEntry reports whether a planned order is allowed.
+// Besides the daily blocks, it rejects the order if it adds to a currency
+// over its exposure limit, or if portfolio VaR INCLUDING the new order would
+// exceed MaxPortfolioVaR.
+func (r *RiskManager) CheckNewEntry(req mt5.OrderRequest) error {
+	if r.tradingBlocked {
+		return fmt.Errorf("trading blocked by risk manager")
+	}
+	if err := r.checkCurrencyEntry(req); err != nil {
+		return err
+	}
+	if r.varCalc == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := r.varCalc.Calculate(ctx, req)
+	if err != nil {
+		return fmt.Errorf("VaR check failed: %w", err)
+	}
+	if result.VaR > r.config.MaxPortfolioVaR {
+		return fmt.Errorf("portfolio VaR after entry $%.2f exceeds limit $%.2f", result.VaR, r.config.MaxPortfolioVaR)
+	}
+	return nil
+}
+
+// closeAllPositionsEmergency closes all positions immediately. A configured
+// kill switch is engaged first, so no new trade races the close; positions
+// it leaves open (FlattenPositions off, or already engaged) are closed here.
+// Only positions that actually closed are recorded as trades.
 func (r *RiskManager) closeAllPositionsEmergency(reason string) {
-	closed, err := r.sugar.CloseAllPositions()
+	before := r.openTickets()
+	if r.config.KillSwitch != nil {
+		r.triggerKillSwitch(reason)
+	}
+
+	positions, err := r.sugar.GetOpenPositions()
 	if err != nil {
 		r.IncrementError(fmt.Sprintf("emergency close failed: %v", err))
 		return
 	}
 
+	// Gone since the kill switch fired: closed by its flatten
+	remaining := make(map[uint64]bool, len(positions))
+	for _, pos := range positions {
+		remaining[pos.Ticket] = true
+	}
+	var closed []uint64
+	for _, ticket := range before {
+		if !remaining[ticket] {
+			closed = append(closed, ticket)
+		}
+	}
+
+	bySwitch, failed := len(closed), 0
+	for _, pos := range positions {
+		err := r.sugar.ClosePosition(pos.Ticket)
+		if errors.Is(err, mt5.ErrForeignPosition) {
+			continue
+		}
+		if err != nil {
+			r.IncrementError(fmt.Sprintf("emergency close #%d failed: %v", pos.Ticket, err))
+			failed++
+			continue
+		}
+		closed = append(closed, pos.Ticket)
+	}
+	r.recordClosed(closed...)
+
 	r.UpdateMetrics(func(m *OrchestratorMetrics) {
-		m.LastOperation = fmt.Sprintf("EMERGENCY: Closed %d positions - %s", closed, reason)
-		m.OperationsTotal += closed
+		m.LastOperation = fmt.Sprintf("EMERGENCY: Closed %d positions (%d failed) - %s", len(closed), failed, reason)
+		m.OperationsTotal += len(closed) - bySwitch // the kill switch counted its own
 	})
 
 	r.logRiskEvent("EMERGENCY_CLOSE", "CRITICAL",
-		fmt.Sprintf("Closed all %d positions: %s", closed, reason),
-		float64(closed), 0)
+		fmt.Sprintf("Closed %d positions, %d failed: %s", len(closed), failed, reason),
+		float64(len(closed)), 0)
+}
+
+// triggerKillSwitch engages the configured kill switch.
+func (r *RiskManager) triggerKillSwitch(reason string) {
+	if r.config.KillSwitch.Engaged() != nil {
+		return
+	}
+
+	event, err := r.config.KillSwitch.Trigger("risk manager: " + reason)
+	if err != nil {
+		r.IncrementError(fmt.Sprintf("kill switch cleanup failed: %v", err))
+	}
+	r.tradingBlocked = true
+
+	r.UpdateMetrics(func(m *OrchestratorMetrics) {
+		m.LastOperation = fmt.Sprintf("KILL SWITCH: Closed %d positions, cancelled %d orders - %s",
+			event.ClosedPositions, event.CancelledOrders, reason)
+		m.OperationsTotal += event.ClosedPositions + event.CancelledOrders
+	})
+
+	r.logRiskEvent("KILL_SWITCH", "CRITICAL",
+		fmt.Sprintf("Kill switch engaged: %s", reason),
+		float64(event.ClosedPositions), 0)
 }
 
 // closeMostLosingPosition closes the position with largest loss.
@@ -403,10 +660,32 @@ func (r *RiskManager) closeMostLosingPosition(reason string) {
 			r.UpdateMetrics(func(m *OrchestratorMetrics) {
 				m.LastOperation = fmt.Sprintf("Closed losing position #%d: %s", mostLosingTicket, reason)
 			})
+			r.recordClosed(mostLosingTicket)
 		}
 	}
 }
 
+// openTickets returns the tickets of all open positions (nil on error).
+func (r *RiskManager) openTickets() []uint64 {
+	positions, err := r.sugar.GetOpenPositions()
+	if err != nil {
+		return nil
+	}
+	tickets := make([]uint64, len(positions))
+	for i, pos := range positions {
+		tickets[i] = pos.Ticket
+	}
+	return tickets
+}
+
+// recordClosed counts positions closed by the risk manager as trades.
+// Tickets of positions that failed to close are dropped after a while.
+func (r *RiskManager) recordClosed(tickets ...uint64) {
+	if err := r.RecordClosedTrades(r.sugar, tickets...); err != nil {
+		r.IncrementError(err.Error())
+	}
+}
+
 // checkDailyReset resets daily counters at start of new day.
 func (r *RiskManager) checkDailyReset() {
 	now := time.Now()
@@ -451,9 +730,21 @@ func (r *RiskManager) GetRiskEvents() []RiskEvent {
 	return r.riskEvents
 }
 
-// IsTradingBlocked returns whether trading is currently blocked.
+// IsTradingBlocked returns whether trading is currently blocked
+// (daily limits or portfolio VaR cap).
 func (r *RiskManager) IsTradingBlocked() bool {
-	return r.tradingBlocked
+	return r.tradingBlocked || r.varBlocked
+}
+
+// GetPortfolioVaR returns the last portfolio VaR estimate (nil if VaR is disabled or not yet run).
+func (r *RiskManager) GetPortfolioVaR() *mt5.VaRResult {
+	return r.lastVaR
+}
+
+// GetCurrencyBreaches returns the currencies and clusters over their exposure
+// limit at the last check, with the direction (+1 long, -1 short).
+func (r *RiskManager) GetCurrencyBreaches() map[string]float64 {
+	return r.currencyBreaches
 }
 
 // GetTodayProfit returns today's profit/loss.
@@ -533,6 +824,10 @@ func RunOrchestrator_RiskManager() error {
         MaxOpenPositions:    20,               // ← Max 20 positions
         MaxSymbolExposure:   5,                // ← Max 5 per symbol
         MaxPositionSize:     1.0,              // ← Max 1.0 lot
+        MaxPortfolioVaR:     300.0,            // ← Cap entries above $300 VaR (0 = off)
+        VaRConfidence:       0.99,             // ← 99% confidence
+        VaRTimeframe:        time.Minute,      // ← M1 returns / 1-minute horizon
+        MaxCurrencyLots:     map[string]float64{"USD": 3.0}, // ← Max 3 lots net USD (nil = off)
         CheckInterval:       5 * time.Second,  // ← Check every 5 seconds
         EnableAutoClose:     true,             // ← Auto-close on breach
         EnableTradeBlocking: true,             // ← Block trades on breach
@@ -741,6 +1036,14 @@ EXPECTED BEHAVIOR:
   Currently logged only (future: could enforce on new trades)
   Tip: Limits single-trade risk
 
+• MaxCurrencyLots / MaxCurrencyNotional (map[string]float64)
+  Net exposure limit per currency or ExposureClusters name, in lots or in
+  account-currency notional (EURUSD long = EUR long + USD short)
+  Example: {"USD": 3.0, "USD bloc": 5.0} with a cluster {USD, CAD, HKD}
+  Trigger: Exceeds limit → WARNING logged, CheckNewEntry rejects orders
+           that add to the breached side (reducing orders pass)
+  Tip: Catches one currency bet spread over several correlated pairs
+
 • CheckInterval (time.Duration)
   How often to check risk metrics
   Example: 5 * time.Second = check every 5 seconds
@@ -202,6 +202,9 @@ type PortfolioRebalancerConfig struct {
 	UseMarketOrders   bool    // Use market orders (true) or limit orders (false)
 	SlippageTolerance float64 // Maximum slippage in points for limit orders
 	MaxTradesPerCycle int     // Max trades per rebalancing cycle
+
+	// Runtime limits (zero = run until Stop)
+	Limits RuntimeLimits
 }
 
 // DefaultPortfolioRebalancerConfig returns sensible defaults.
@@ -291,7 +294,10 @@ func (p *PortfolioRebalancer) Start() error {
 	p.MarkStarted()
 
 	// Start monitoring loop
-	go p.monitorLoop()
+	p.GoSafe(p.monitorLoop)
+
+	// Stop automatically on MaxRuntime / MaxTrades / MaxLoss
+	p.EnforceLimits(p.config.Limits, p.Stop)
 
 	return nil
 }
@@ -448,27 +454,38 @@ func (p *PortfolioRebalancer) analyzePortfolio() []*SymbolAllocation {
 }
 
 // executeRebalance performs trades to restore target allocations.
+// Symbols are adjusted in parallel through the Sugar worker pool, one trade
+// at a time per symbol.
 func (p *PortfolioRebalancer) executeRebalance(allocations []*SymbolAllocation) error {
-	tradesExecuted := 0
-
+	var pending []*SymbolAllocation
 	for _, alloc := range allocations {
 		if !alloc.NeedsAdjustment {
 			continue
 		}
 
-		if tradesExecuted >= p.config.MaxTradesPerCycle {
+		if len(pending) >= p.config.MaxTradesPerCycle {
 			p.UpdateMetrics(func(m *OrchestratorMetrics) {
 				m.LastOperation = fmt.Sprintf("Max trades per cycle reached (%d)", p.config.MaxTradesPerCycle)
 			})
 			break
 		}
+		pending = append(pending, alloc)
+	}
+
+	tasks := make([]mt5.SymbolTask, len(pending))
+	for i, alloc := range pending {
+		alloc := alloc
+		tasks[i] = mt5.SymbolTask{Symbol: alloc.Symbol, Run: func(context.Context) error {
+			return p.adjustSymbolExposure(alloc)
+		}}
+	}
 
-		// Execute adjustment
-		if err := p.adjustSymbolExposure(alloc); err != nil {
-			p.IncrementError(fmt.Sprintf("failed to adjust %s: %v", alloc.Symbol, err))
+	tradesExecuted := 0
+	for i, err := range p.sugar.WorkerPool().Run(p.GetContext(), tasks) {
+		if err != nil {
+			p.IncrementError(fmt.Sprintf("failed to adjust %s: %s", pending[i].Symbol, explainTradeError(p.sugar, err, pending[i].Symbol)))
 			continue
 		}
-
 		tradesExecuted++
 	}
 
@@ -495,10 +512,7 @@ func (p *PortfolioRebalancer) adjustSymbolExposure(alloc *SymbolAllocation) erro
 		if err != nil {
 			return fmt.Errorf("buy failed: %w", err)
 		}
-
-		p.UpdateMetrics(func(m *OrchestratorMetrics) {
-			m.TotalTrades++
-		})
+		p.Own(ticket)
 
 		fmt.Printf("Opened BUY position #%d for %s: %.2f lots\n", ticket, alloc.Symbol, lotSize)
 
@@ -511,9 +525,9 @@ func (p *PortfolioRebalancer) adjustSymbolExposure(alloc *SymbolAllocation) erro
 				return fmt.Errorf("close failed: %w", err)
 			}
 
-			p.UpdateMetrics(func(m *OrchestratorMetrics) {
-				m.TotalTrades++
-			})
+			if err := p.RecordClosedTrades(p.sugar, positions[0].Ticket); err != nil {
+				p.IncrementError(err.Error())
+			}
 		}
 	}
 
@@ -0,0 +1,558 @@
+package orchestrators
+
+/*══════════════════════════════════════════════════════════════════════════════
+ ORCHESTRATOR: AlertRuleEngine (Price Alert → Action Automation)
+
+ PURPOSE:
+   Binds price alert conditions to trading actions, defined as data instead
+   of code. When a condition fires on the tick stream, its actions run
+   through Sugar (place order, close positions) or act on other
+   orchestrators (pause / resume).
+
+ CONDITIONS (AlertCondition):
+   • Field:  "bid", "ask", "mid" or "spread" (points)
+   • Op:     ">", ">=", "<", "<=", "crosses_above", "crosses_below"
+   • Value:  threshold
+
+ ACTIONS (AlertAction.Type):
+   • "buy", "sell"               - market order (Volume, SLPips, TPPips)
+   • "buy_limit", "sell_limit",
+     "buy_stop",  "sell_stop"    - pending order at Price, or OffsetPts points
+                                   from Ask (buy) / Bid (sell) when Price is 0
+   • "close_symbol"              - close all positions of Symbol
+   • "close_all"                 - close every open position
+   • "pause", "resume"           - Stop / Start the orchestrator named Target
+
+   With a SignalGate attached (SetSignalGate), repeated entries are held back
+   by its cooldown / open-position rules; they show up as "suppressed" in the
+   firing results instead of counting as errors. Entries held back by a
+   VolatilityCircuit (SetVolatilityCircuit) are reported the same way.
+
+ CONFIG FILE (JSON, see LoadAlertRules):
+   [
+     {
+       "name": "Breakout long",
+       "when": {"symbol": "EURUSD", "field": "bid", "op": "crosses_above", "value": 1.1000},
+       "then": [{"type": "buy", "symbol": "EURUSD", "volume": 0.1, "sl_pips": 20, "tp_pips": 40}],
+       "once": true
+     },
+     {
+       "name": "Spread spike",
+       "when": {"symbol": "EURUSD", "field": "spread", "op": ">", "value": 30},
+       "then": [{"type": "pause", "target": "Grid Trader"}],
+       "cooldown_sec": 300
+     }
+   ]
+
+ PROGRAMMATIC USAGE:
+   rules, err := orchestrators.LoadAlertRules("alerts.json")
+   engine := orchestrators.NewAlertRuleEngine(sugar, rules)
+   engine.Register("Grid Trader", gridTrader)
+   engine.Start()
+   defer engine.Stop()
+══════════════════════════════════════════════════════════════════════════════*/
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	mt5 "github.com/MetaRPC/GoMT5/examples/mt5"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CONFIGURATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// AlertCondition is a price condition on one symbol.
+type AlertCondition struct {
+	Symbol string  `json:"symbol"`
+	Field  string  `json:"field"` // bid, ask, mid, spread
+	Op     string  `json:"op"`    // >, >=, <, <=, crosses_above, crosses_below
+	Value  float64 `json:"value"`
+}
+
+// AlertAction is an action template executed when a rule fires.
+type AlertAction struct {
+	Type      string  `json:"type"`
+	Symbol    string  `json:"symbol,omitempty"`
+	Volume    float64 `json:"volume,omitempty"`
+	Price     float64 `json:"price,omitempty"`         // Pending order price
+	OffsetPts float64 `json:"offset_points,omitempty"` // Pending orders: distance from Ask (buy) / Bid (sell) instead of Price
+	SLPips    float64 `json:"sl_pips,omitempty"`       // Market orders: SL distance in pips
+	TPPips    float64 `json:"tp_pips,omitempty"`       // Market orders: TP distance in pips
+	Target    string  `json:"target,omitempty"`        // Orchestrator name for pause/resume
+}
+
+// AlertRule binds a condition to actions.
+type AlertRule struct {
+	Name        string         `json:"name"`
+	When        AlertCondition `json:"when"`
+	Then        []AlertAction  `json:"then"`
+	Once        bool           `json:"once,omitempty"`         // Disable after first trigger
+	CooldownSec int            `json:"cooldown_sec,omitempty"` // Min seconds between triggers
+}
+
+// LoadAlertRules reads rules from a JSON file and validates them.
+func LoadAlertRules(path string) ([]AlertRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alert rules: %w", err)
+	}
+
+	var rules []AlertRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse alert rules: %w", err)
+	}
+	for i := range rules {
+		if err := rules[i].Validate(); err != nil {
+			return nil, fmt.Errorf("alert rule %d (%s): %w", i, rules[i].Name, err)
+		}
+	}
+	return rules, nil
+}
+
+// Validate checks that the rule's condition and actions are well-formed.
+func (r AlertRule) Validate() error {
+	if r.When.Symbol == "" {
+		return fmt.Errorf("condition symbol is required")
+	}
+	switch r.When.Field {
+	case "bid", "ask", "mid", "spread":
+	default:
+		return fmt.Errorf("unknown condition field %q", r.When.Field)
+	}
+	switch r.When.Op {
+	case ">", ">=", "<", "<=", "crosses_above", "crosses_below":
+	default:
+		return fmt.Errorf("unknown condition op %q", r.When.Op)
+	}
+	if len(r.Then) == 0 {
+		return fmt.Errorf("rule has no actions")
+	}
+
+	for _, a := range r.Then {
+		switch a.Type {
+		case "buy", "sell":
+			if a.Volume <= 0 {
+				return fmt.Errorf("%s action needs volume", a.Type)
+			}
+		case "buy_limit", "sell_limit", "buy_stop", "sell_stop":
+			if a.Volume <= 0 || a.Price <= 0 && a.OffsetPts <= 0 {
+				return fmt.Errorf("%s action needs volume and price or offset_points", a.Type)
+			}
+		case "close_symbol", "close_all":
+		case "pause", "resume":
+			if a.Target == "" {
+				return fmt.Errorf("%s action needs target", a.Type)
+			}
+		default:
+			return fmt.Errorf("unknown action type %q", a.Type)
+		}
+	}
+	return nil
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// ALERT RULE ENGINE IMPLEMENTATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// AlertFiring records one triggered rule.
+type AlertFiring struct {
+	Rule    string
+	Symbol  string
+	Value   float64 // Field value that triggered
+	Time    time.Time
+	Results []string // One line per action
+}
+
+// AlertRuleEngine evaluates alert rules on live ticks and runs their actions.
+type AlertRuleEngine struct {
+	*BaseOrchestrator
+	sugar *mt5.MT5Sugar
+	rules []AlertRule
+
+	mu        sync.Mutex
+	targets   map[string]Orchestrator
+	lastValue map[int]float64   // Previous field value per rule (for crosses)
+	lastFired map[int]time.Time // Last trigger per rule
+	disabled  map[int]bool      // Once-rules already fired
+	points    map[string]float64
+	firings   []AlertFiring
+}
+
+// NewAlertRuleEngine creates an engine for the given rules.
+func NewAlertRuleEngine(sugar *mt5.MT5Sugar, rules []AlertRule) *AlertRuleEngine {
+	return &AlertRuleEngine{
+		BaseOrchestrator: NewBaseOrchestrator("Alert Rule Engine"),
+		sugar:            sugar,
+		rules:            rules,
+		targets:          make(map[string]Orchestrator),
+		lastValue:        make(map[int]float64),
+		lastFired:        make(map[int]time.Time),
+		disabled:         make(map[int]bool),
+		points:           make(map[string]float64),
+	}
+}
+
+// Register makes an orchestrator available to pause/resume actions under name.
+func (e *AlertRuleEngine) Register(name string, o Orchestrator) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.targets[name] = o
+}
+
+// Start validates the rules and begins watching ticks.
+func (e *AlertRuleEngine) Start() error {
+	if e.IsRunning() {
+		return fmt.Errorf("alert rule engine already running")
+	}
+	for i := range e.rules {
+		if err := e.rules[i].Validate(); err != nil {
+			return fmt.Errorf("alert rule %d (%s): %w", i, e.rules[i].Name, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e.SetContext(ctx, cancel)
+
+	e.MarkStarted()
+
+	e.GoSafe(e.watchLoop)
+
+	return nil
+}
+
+// Stop stops watching ticks.
+func (e *AlertRuleEngine) Stop() error {
+	if !e.IsRunning() {
+		return fmt.Errorf("alert rule engine not running")
+	}
+
+	e.CancelContext()
+	e.MarkStopped()
+
+	return nil
+}
+
+// watchLoop streams ticks of every symbol referenced by a rule.
+func (e *AlertRuleEngine) watchLoop() {
+	ctx := e.GetContext()
+
+	seen := make(map[string]bool)
+	var symbols []string
+	for _, rule := range e.rules {
+		if !seen[rule.When.Symbol] {
+			seen[rule.When.Symbol] = true
+			symbols = append(symbols, rule.When.Symbol)
+		}
+	}
+	if len(symbols) == 0 {
+		return
+	}
+
+	tickCh, errCh := e.sugar.GetService().StreamTicks(ctx, symbols)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case tick, ok := <-tickCh:
+			if !ok {
+				return
+			}
+			e.evaluate(tick)
+		case err, ok := <-errCh:
+			if !ok {
+				errCh = nil
+				continue
+			}
+			if err != nil && ctx.Err() == nil {
+				e.IncrementError(fmt.Sprintf("tick stream: %v", err))
+			}
+		}
+	}
+}
+
+// evaluate checks every rule on the tick's symbol.
+func (e *AlertRuleEngine) evaluate(tick *mt5.SymbolTick) {
+	now := time.Now()
+
+	for i, rule := range e.rules {
+		if rule.When.Symbol != tick.Symbol {
+			continue
+		}
+
+		value, ok := e.fieldValue(rule.When.Field, tick)
+		if !ok {
+			continue
+		}
+
+		e.mu.Lock()
+		prev, hasPrev := e.lastValue[i]
+		e.lastValue[i] = value
+		blocked := e.disabled[i] ||
+			(rule.CooldownSec > 0 && now.Sub(e.lastFired[i]) < time.Duration(rule.CooldownSec)*time.Second)
+		e.mu.Unlock()
+
+		if blocked || !conditionMet(rule.When, value, prev, hasPrev) {
+			continue
+		}
+
+		e.mu.Lock()
+		e.lastFired[i] = now
+		if rule.Once {
+			e.disabled[i] = true
+		}
+		e.mu.Unlock()
+
+		e.fire(rule, value, now)
+	}
+}
+
+// fieldValue extracts the condition field from a tick.
+func (e *AlertRuleEngine) fieldValue(field string, tick *mt5.SymbolTick) (float64, bool) {
+	if tick.Bid <= 0 || tick.Ask <= 0 {
+		return 0, false
+	}
+	switch field {
+	case "bid":
+		return tick.Bid, true
+	case "ask":
+		return tick.Ask, true
+	case "mid":
+		return (tick.Bid + tick.Ask) / 2, true
+	case "spread":
+		point := e.symbolPoint(tick.Symbol)
+		if point <= 0 {
+			return 0, false
+		}
+		return (tick.Ask - tick.Bid) / point, true
+	}
+	return 0, false
+}
+
+// symbolPoint returns the cached point size for a symbol.
+func (e *AlertRuleEngine) symbolPoint(symbol string) float64 {
+	e.mu.Lock()
+	point, ok := e.points[symbol]
+	e.mu.Unlock()
+	if ok {
+		return point
+	}
+
+	info, err := e.sugar.GetSymbolInfo(symbol)
+	if err != nil {
+		return 0
+	}
+
+	e.mu.Lock()
+	e.points[symbol] = info.Point
+	e.mu.Unlock()
+	return info.Point
+}
+
+// conditionMet evaluates a condition; crosses need a previous value.
+func conditionMet(c AlertCondition, value, prev float64, hasPrev bool) bool {
+	switch c.Op {
+	case ">":
+		return value > c.Value
+	case ">=":
+		return value >= c.Value
+	case "<":
+		return value < c.Value
+	case "<=":
+		return value <= c.Value
+	case "crosses_above":
+		return hasPrev && prev <= c.Value && value > c.Value
+	case "crosses_below":
+		return hasPrev && prev >= c.Value && value < c.Value
+	}
+	return false
+}
+
+// fire runs all actions of a rule and records the outcome.
+func (e *AlertRuleEngine) fire(rule AlertRule, value float64, now time.Time) {
+	firing := AlertFiring{
+		Rule:   rule.Name,
+		Symbol: rule.When.Symbol,
+		Value:  value,
+		Time:   now,
+	}
+
+	failed := false
+	for _, action := range rule.Then {
+		result, err := e.runAction(action, rule.When.Symbol)
+		if err != nil {
+			failed = true
+			symbol := action.Symbol
+			if symbol == "" {
+				symbol = rule.When.Symbol
+			}
+			result = fmt.Sprintf("%s failed: %s", action.Type, explainTradeError(e.sugar, err, symbol))
+			e.IncrementError(fmt.Sprintf("rule %q: %s", rule.Name, result))
+		}
+		firing.Results = append(firing.Results, result)
+	}
+
+	e.mu.Lock()
+	e.firings = append(e.firings, firing)
+	if len(e.firings) > 100 {
+		e.firings = e.firings[len(e.firings)-100:]
+	}
+	e.mu.Unlock()
+
+	e.UpdateMetrics(func(m *OrchestratorMetrics) {
+		m.OperationsTotal++
+		m.LastOperation = fmt.Sprintf("Rule %q fired at %.5f", rule.Name, value)
+	})
+	if !failed {
+		e.IncrementSuccess()
+	}
+}
+
+// runAction executes one action template. Symbol defaults to the condition's symbol.
+func (e *AlertRuleEngine) runAction(a AlertAction, defaultSymbol string) (string, error) {
+	symbol := a.Symbol
+	if symbol == "" {
+		symbol = defaultSymbol
+	}
+
+	switch a.Type {
+	case "buy", "sell":
+		if err := e.AllowEntry(symbol, a.Type == "buy"); err != nil {
+			return suppressedOrError(a.Type, err)
+		}
+		var ticket uint64
+		var err error
+		if a.Type == "buy" {
+			if a.SLPips > 0 || a.TPPips > 0 {
+				ticket, err = e.sugar.BuyMarketWithPips(symbol, a.Volume, a.SLPips, a.TPPips)
+			} else {
+				ticket, err = e.sugar.BuyMarket(symbol, a.Volume)
+			}
+		} else {
+			if a.SLPips > 0 || a.TPPips > 0 {
+				ticket, err = e.sugar.SellMarketWithPips(symbol, a.Volume, a.SLPips, a.TPPips)
+			} else {
+				ticket, err = e.sugar.SellMarket(symbol, a.Volume)
+			}
+		}
+		if err != nil {
+			return "", err
+		}
+		e.Own(ticket)
+		e.RecordEntry(symbol, a.Type == "buy")
+		return fmt.Sprintf("%s %.2f %s → #%d", a.Type, a.Volume, symbol, ticket), nil
+
+	case "buy_limit", "sell_limit", "buy_stop", "sell_stop":
+		place := map[string]func(string, float64, float64) (uint64, error){
+			"buy_limit":  e.sugar.BuyLimit,
+			"sell_limit": e.sugar.SellLimit,
+			"buy_stop":   e.sugar.BuyStop,
+			"sell_stop":  e.sugar.SellStop,
+		}[a.Type]
+		arg, at := a.Price, fmt.Sprintf("@ %.5f", a.Price)
+		if a.Price <= 0 {
+			// Offset from the side of the quote that triggers the order
+			place = map[string]func(string, float64, float64) (uint64, error){
+				"buy_limit":  e.sugar.BuyLimitPoints,
+				"sell_limit": e.sugar.SellLimitPoints,
+				"buy_stop":   e.sugar.BuyStopPoints,
+				"sell_stop":  e.sugar.SellStopPoints,
+			}[a.Type]
+			arg, at = a.OffsetPts, fmt.Sprintf("%.1f pts away", a.OffsetPts)
+		}
+		buy := a.Type == "buy_limit" || a.Type == "buy_stop"
+		if err := e.AllowEntry(symbol, buy); err != nil {
+			return suppressedOrError(a.Type, err)
+		}
+		ticket, err := place(symbol, a.Volume, arg)
+		if err != nil {
+			return "", err
+		}
+		e.Own(ticket)
+		e.RecordEntry(symbol, buy)
+		return fmt.Sprintf("%s %.2f %s %s → #%d", a.Type, a.Volume, symbol, at, ticket), nil
+
+	case "close_symbol":
+		results, err := e.sugar.CloseBySymbol(symbol)
+		if err != nil {
+			return "", err
+		}
+		var tickets []uint64
+		for _, r := range results {
+			if r.Err == nil {
+				tickets = append(tickets, r.Ticket)
+			}
+		}
+		e.recordClosed(tickets...)
+		return fmt.Sprintf("closed %d position(s) on %s", len(results), symbol), nil
+
+	case "close_all":
+		var tickets []uint64
+		if positions, err := e.sugar.GetOpenPositions(); err == nil {
+			for _, pos := range positions {
+				tickets = append(tickets, pos.Ticket)
+			}
+		}
+		closed, err := e.sugar.CloseAllPositions()
+		if err != nil {
+			return "", err
+		}
+		e.recordClosed(tickets...)
+		return fmt.Sprintf("closed %d position(s)", closed), nil
+
+	case "pause", "resume":
+		e.mu.Lock()
+		target, ok := e.targets[a.Target]
+		e.mu.Unlock()
+		if !ok {
+			return "", fmt.Errorf("orchestrator %q not registered", a.Target)
+		}
+		if a.Type == "pause" {
+			if !target.IsRunning() {
+				return fmt.Sprintf("%s already paused", a.Target), nil
+			}
+			if err := target.Stop(); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("paused %s", a.Target), nil
+		}
+		if target.IsRunning() {
+			return fmt.Sprintf("%s already running", a.Target), nil
+		}
+		if err := target.Start(); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("resumed %s", a.Target), nil
+	}
+
+	return "", fmt.Errorf("unknown action type %q", a.Type)
+}
+
+// GetFirings returns the last 100 triggered rules.
+func (e *AlertRuleEngine) GetFirings() []AlertFiring {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]AlertFiring(nil), e.firings...)
+}
+
+// recordClosed counts positions closed by an action as trades.
+func (e *AlertRuleEngine) recordClosed(tickets ...uint64) {
+	if err := e.RecordClosedTrades(e.sugar, tickets...); err != nil {
+		e.IncrementError(err.Error())
+	}
+}
+
+// suppressedOrError reports a signal-gate suppression as a result, not a failure.
+func suppressedOrError(action string, err error) (string, error) {
+	var suppressed *SuppressedSignalError
+	if errors.As(err, &suppressed) {
+		return fmt.Sprintf("%s suppressed: %v", action, suppressed.Reason), nil
+	}
+	return "", err
+}
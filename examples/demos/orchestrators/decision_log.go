@@ -0,0 +1,231 @@
+package orchestrators
+
+/*══════════════════════════════════════════════════════════════════════════════
+ DECISION LOG: Why a Strategy Did (or Did Not) Act
+
+ PURPOSE:
+   Metrics and LastOperation say what happened. The decision log records why:
+   the signal values a strategy looked at, the thresholds it compared them
+   with, the action it chose and - just as important - entries it skipped
+   and the reason. Entries are kept in memory and optionally appended to a
+   JSON Lines file, so they can be queried after the run.
+
+ ENTRY:
+   • Orchestrator - filled in by LogDecision
+   • Action       - "buy", "sell limit", "close", "modify", ...
+   • Skipped      - action considered but not taken (Reason says why)
+   • Signals      - inputs, e.g. {"rsi": 27.4, "price": 1.0842}
+   • Thresholds   - limits they were compared with, e.g. {"rsi_buy": 30}
+   • Ticket       - resulting order/position (0 if none)
+
+ Entries suppressed by the signal gate (AllowEntry) are logged as skipped
+ automatically.
+
+ PROGRAMMATIC USAGE:
+   log, err := orchestrators.OpenDecisionLog("decisions.jsonl", 10000)
+   defer log.Close()
+   grid.SetDecisionLog(log)
+
+   // Inside an orchestrator:
+   o.LogDecision(orchestrators.DecisionEntry{
+       Symbol: "EURUSD", Action: "buy", Reason: "RSI oversold",
+       Signals: map[string]float64{"rsi": rsi}, Thresholds: map[string]float64{"rsi_buy": 30},
+   })
+
+   // Later:
+   skipped := true
+   entries := log.Query(orchestrators.DecisionQuery{Symbol: "EURUSD", Skipped: &skipped})
+══════════════════════════════════════════════════════════════════════════════*/
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// DecisionEntry is one recorded strategy decision.
+type DecisionEntry struct {
+	Time         time.Time          `json:"time"`
+	Orchestrator string             `json:"orchestrator"`
+	Symbol       string             `json:"symbol,omitempty"`
+	Action       string             `json:"action"`
+	Skipped      bool               `json:"skipped,omitempty"`
+	Reason       string             `json:"reason,omitempty"`
+	Signals      map[string]float64 `json:"signals,omitempty"`
+	Thresholds   map[string]float64 `json:"thresholds,omitempty"`
+	Ticket       uint64             `json:"ticket,omitempty"`
+}
+
+// String formats the entry as one log line.
+func (e DecisionEntry) String() string {
+	verb := "did"
+	if e.Skipped {
+		verb = "skipped"
+	}
+	s := fmt.Sprintf("%s [%s] %s %s %s", e.Time.Format("2006-01-02 15:04:05"), e.Orchestrator, verb, e.Action, e.Symbol)
+	if e.Ticket > 0 {
+		s += fmt.Sprintf(" #%d", e.Ticket)
+	}
+	if e.Reason != "" {
+		s += ": " + e.Reason
+	}
+	return s
+}
+
+// DecisionQuery selects entries. Zero fields match everything.
+type DecisionQuery struct {
+	Orchestrator string
+	Symbol       string
+	Action       string
+	Skipped      *bool     // nil = taken and skipped
+	From         time.Time // Inclusive
+	To           time.Time // Exclusive
+	Limit        int       // Newest N entries (0 = all)
+}
+
+func (q DecisionQuery) match(e DecisionEntry) bool {
+	switch {
+	case q.Orchestrator != "" && e.Orchestrator != q.Orchestrator,
+		q.Symbol != "" && e.Symbol != q.Symbol,
+		q.Action != "" && e.Action != q.Action,
+		q.Skipped != nil && e.Skipped != *q.Skipped,
+		!q.From.IsZero() && e.Time.Before(q.From),
+		!q.To.IsZero() && !e.Time.Before(q.To):
+		return false
+	}
+	return true
+}
+
+// DecisionLog stores decisions in memory (the newest max entries) and,
+// when opened with a path, appends every entry to a JSON Lines file.
+// Safe for concurrent use by several orchestrators.
+type DecisionLog struct {
+	mu      sync.RWMutex
+	entries []DecisionEntry
+	max     int
+	file    *os.File
+	enc     *json.Encoder
+}
+
+// NewDecisionLog creates an in-memory log keeping the newest max entries
+// (0 = unlimited).
+func NewDecisionLog(max int) *DecisionLog {
+	return &DecisionLog{max: max}
+}
+
+// OpenDecisionLog creates a log backed by a JSON Lines file. Existing
+// entries are loaded; new ones are appended.
+//
+// PARAMETERS:
+//   - path: JSON Lines file (created if missing)
+//   - max: Entries kept in memory (0 = unlimited); the file keeps all
+func OpenDecisionLog(path string, max int) (*DecisionLog, error) {
+	l := NewDecisionLog(max)
+	if err := l.load(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open decision log failed: %w", err)
+	}
+	l.file = f
+	l.enc = json.NewEncoder(f)
+	return l, nil
+}
+
+// LoadDecisionLog reads a decision log file for querying.
+func LoadDecisionLog(path string) (*DecisionLog, error) {
+	l := NewDecisionLog(0)
+	if err := l.load(path); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *DecisionLog) load(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e DecisionEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return fmt.Errorf("decision log %s line %d: %w", path, line, err)
+		}
+		l.append(e)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read decision log failed: %w", err)
+	}
+	return nil
+}
+
+func (l *DecisionLog) append(e DecisionEntry) {
+	l.entries = append(l.entries, e)
+	if l.max > 0 && len(l.entries) > l.max {
+		l.entries = append(l.entries[:0], l.entries[len(l.entries)-l.max:]...)
+	}
+}
+
+// Record adds an entry (Time defaults to now). A file write error is
+// returned; the entry is kept in memory regardless.
+func (l *DecisionLog) Record(e DecisionEntry) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.append(e)
+	if l.enc != nil {
+		if err := l.enc.Encode(e); err != nil {
+			return fmt.Errorf("write decision log failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// Query returns matching entries, oldest first.
+func (l *DecisionLog) Query(q DecisionQuery) []DecisionEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	var out []DecisionEntry
+	for _, e := range l.entries {
+		if q.match(e) {
+			out = append(out, e)
+		}
+	}
+	if q.Limit > 0 && len(out) > q.Limit {
+		out = out[len(out)-q.Limit:]
+	}
+	return out
+}
+
+// Len returns the number of entries held in memory.
+func (l *DecisionLog) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.entries)
+}
+
+// Close closes the backing file. The log stays queryable.
+func (l *DecisionLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file, l.enc = nil, nil
+	return err
+}
@@ -0,0 +1,388 @@
+package orchestrators
+
+/*══════════════════════════════════════════════════════════════════════════════
+ ORCHESTRATOR: HealthServer (Liveness / Readiness Probes)
+
+ PURPOSE:
+   Periodically probes the gRPC connection and the MT5 terminal, watches the
+   lag of open streams and the state of registered orchestrators, and serves
+   the result over HTTP for Kubernetes probes, systemd watchdogs or load
+   balancers. Probes run in the background, so an HTTP check never blocks on
+   the terminal.
+
+ ENDPOINTS:
+   • GET /livez   - 200 while the probe loop is alive (liveness)
+   • GET /healthz - 200 when connected, terminal alive, no stream lagging and
+                    no orchestrator crashed; 503 with reasons otherwise (readiness)
+   • GET /status  - full JSON report: connection, streams, orchestrators, errors
+
+ CHECKS (healthz):
+   • gRPC connection present
+   • Terminal alive: last CheckConnect succeeded within MaxProbeAge
+   • Stream lag: time since last message of a stream kind in MaxStreamLag
+   • Orchestrators: a registered orchestrator crashed (GoSafe panic)
+
+ KUBERNETES:
+   livenessProbe:  { httpGet: { path: /livez,   port: 9101 } }
+   readinessProbe: { httpGet: { path: /healthz, port: 9101 } }
+
+ PROGRAMMATIC USAGE:
+   health := orchestrators.NewHealthServer(sugar, orchestrators.DefaultHealthServerConfig())
+   health.Register(gridTrader)
+   health.Start()
+   defer health.Stop()
+
+   // Or share the metrics server's port (Addr: "" disables the own listener):
+   for _, path := range []string{"/livez", "/healthz", "/status"} {
+       metricsServer.Handle(path, health.Handler())
+   }
+══════════════════════════════════════════════════════════════════════════════*/
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	mt5 "github.com/MetaRPC/GoMT5/examples/mt5"
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CONFIGURATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// HealthServerConfig holds health server parameters.
+type HealthServerConfig struct {
+	Addr          string                   // HTTP listen address ("" = do not listen, use Handler)
+	ProbeInterval time.Duration            // How often the terminal is probed
+	ProbeTimeout  time.Duration            // Timeout of one CheckConnect probe
+	MaxProbeAge   time.Duration            // Terminal counts as dead when the last good probe is older
+	MaxStreamLag  map[string]time.Duration // Per stream kind (e.g. "OnSymbolTick"); unlisted kinds are not checked
+}
+
+// DefaultHealthServerConfig returns a config probing every 10 seconds and
+// flagging tick streams silent for more than 5 minutes.
+func DefaultHealthServerConfig() HealthServerConfig {
+	return HealthServerConfig{
+		Addr:          ":9101",
+		ProbeInterval: 10 * time.Second,
+		ProbeTimeout:  3 * time.Second,
+		MaxProbeAge:   30 * time.Second,
+		MaxStreamLag: map[string]time.Duration{
+			"OnSymbolTick": 5 * time.Minute,
+		},
+	}
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// STATUS REPORT
+// ══════════════════════════════════════════════════════════════════════════════
+
+// ConnectionHealth is the result of the latest terminal probes.
+type ConnectionHealth struct {
+	GrpcConnected bool      `json:"grpc_connected"`
+	TerminalAlive bool      `json:"terminal_alive"`
+	LastProbe     time.Time `json:"last_probe"`
+	LastAlive     time.Time `json:"last_alive"`
+	ProbeLatency  string    `json:"probe_latency"`
+	LastError     string    `json:"last_error,omitempty"`
+}
+
+// StreamHealth describes one open stream.
+type StreamHealth struct {
+	Name       string    `json:"name"`
+	StartedAt  time.Time `json:"started_at"`
+	Messages   uint64    `json:"messages"`
+	Reconnects uint64    `json:"reconnects"`
+	Lag        string    `json:"lag"`
+	Lagging    bool      `json:"lagging"`
+}
+
+// OrchestratorHealth describes one registered orchestrator.
+type OrchestratorHealth struct {
+	Name       string    `json:"name"`
+	Running    bool      `json:"running"`
+	Crashed    bool      `json:"crashed"`
+	Uptime     string    `json:"uptime"`
+	Successes  int       `json:"successes"`
+	Errors     int       `json:"errors"`
+	LastError  string    `json:"last_error,omitempty"`
+	LastUpdate time.Time `json:"last_update"`
+}
+
+// HealthStatus is the full report served by /status.
+type HealthStatus struct {
+	Healthy       bool                 `json:"healthy"`
+	Problems      []string             `json:"problems,omitempty"`
+	CheckedAt     time.Time            `json:"checked_at"`
+	Uptime        string               `json:"uptime"`
+	Connection    ConnectionHealth     `json:"connection"`
+	Streams       []StreamHealth       `json:"streams"`
+	Orchestrators []OrchestratorHealth `json:"orchestrators"`
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// HEALTH SERVER IMPLEMENTATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// HealthServer probes the terminal and serves health endpoints.
+type HealthServer struct {
+	*BaseOrchestrator
+	sugar  *mt5.MT5Sugar
+	config HealthServerConfig
+
+	mu            sync.RWMutex
+	connection    ConnectionHealth
+	lastLoop      time.Time
+	orchestrators []Orchestrator
+
+	httpServer *http.Server
+}
+
+// NewHealthServer creates a new health server.
+func NewHealthServer(sugar *mt5.MT5Sugar, config HealthServerConfig) *HealthServer {
+	if config.ProbeInterval <= 0 {
+		config.ProbeInterval = 10 * time.Second
+	}
+	if config.ProbeTimeout <= 0 {
+		config.ProbeTimeout = 3 * time.Second
+	}
+	if config.MaxProbeAge <= 0 {
+		config.MaxProbeAge = 3 * config.ProbeInterval
+	}
+	return &HealthServer{
+		BaseOrchestrator: NewBaseOrchestrator("Health Server"),
+		sugar:            sugar,
+		config:           config,
+	}
+}
+
+// Register adds an orchestrator whose state is reported.
+func (h *HealthServer) Register(o Orchestrator) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.orchestrators = append(h.orchestrators, o)
+}
+
+// Handler returns the HTTP handler, for mounting on an existing server.
+func (h *HealthServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/livez", h.handleLive)
+	mux.HandleFunc("/healthz", h.handleHealth)
+	mux.HandleFunc("/status", h.handleStatus)
+	return mux
+}
+
+// Start begins probing and, if Addr is set, serving.
+func (h *HealthServer) Start() error {
+	if h.IsRunning() {
+		return fmt.Errorf("health server already running")
+	}
+
+	var listener net.Listener
+	if h.config.Addr != "" {
+		var err error
+		listener, err = net.Listen("tcp", h.config.Addr)
+		if err != nil {
+			return fmt.Errorf("health server listen on %s: %w", h.config.Addr, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.SetContext(ctx, cancel)
+
+	h.MarkStarted()
+
+	if listener != nil {
+		server := &http.Server{Handler: h.Handler(), ReadHeaderTimeout: 10 * time.Second}
+		h.httpServer = server
+		h.GoSafe(func() {
+			if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				h.IncrementError(fmt.Sprintf("http server: %v", err))
+			}
+		})
+	}
+	h.GoSafe(h.probeLoop)
+
+	return nil
+}
+
+// Stop stops probing and shuts the HTTP server down.
+func (h *HealthServer) Stop() error {
+	if !h.IsRunning() {
+		return fmt.Errorf("health server not running")
+	}
+
+	h.CancelContext()
+
+	var err error
+	if h.httpServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		err = h.httpServer.Shutdown(ctx)
+		h.httpServer = nil
+	}
+
+	h.MarkStopped()
+
+	return err
+}
+
+// probeLoop probes on every tick of ProbeInterval.
+func (h *HealthServer) probeLoop() {
+	ticker := time.NewTicker(h.config.ProbeInterval)
+	defer ticker.Stop()
+
+	h.probe()
+
+	for {
+		select {
+		case <-h.GetContext().Done():
+			return
+		case <-ticker.C:
+			h.probe()
+		}
+	}
+}
+
+// probe checks the gRPC connection and terminal liveness once.
+func (h *HealthServer) probe() {
+	account := h.sugar.GetAccount()
+	now := time.Now()
+
+	h.mu.Lock()
+	h.lastLoop = now
+	h.connection.GrpcConnected = account.IsConnected()
+	h.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(h.GetContext(), h.config.ProbeTimeout)
+	defer cancel()
+
+	data, err := account.CheckConnect(ctx, &pb.CheckConnectRequest{})
+	latency := time.Since(now)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.connection.LastProbe = now
+	h.connection.ProbeLatency = latency.Round(time.Millisecond).String()
+	switch {
+	case err != nil:
+		h.connection.LastError = err.Error()
+	case data.HealthCheck == nil || !data.HealthCheck.IsAlive:
+		h.connection.LastError = "terminal reports not alive"
+	default:
+		h.connection.LastError = ""
+		h.connection.LastAlive = now
+	}
+}
+
+// Status builds the current health report.
+func (h *HealthServer) Status() HealthStatus {
+	now := time.Now()
+	report := HealthStatus{CheckedAt: now}
+
+	h.mu.RLock()
+	report.Connection = h.connection
+	registered := append([]Orchestrator(nil), h.orchestrators...)
+	h.mu.RUnlock()
+
+	// Probes may be late; judge terminal liveness by the last good probe's age now
+	report.Connection.TerminalAlive = !report.Connection.LastAlive.IsZero() &&
+		now.Sub(report.Connection.LastAlive) <= h.config.MaxProbeAge
+
+	if status := h.GetStatus(); status.IsRunning {
+		report.Uptime = FormatDuration(status.Uptime)
+	}
+
+	if !report.Connection.GrpcConnected {
+		report.Problems = append(report.Problems, "gRPC connection is down")
+	}
+	if !report.Connection.TerminalAlive {
+		reason := "terminal not responding"
+		if report.Connection.LastError != "" {
+			reason += ": " + report.Connection.LastError
+		}
+		report.Problems = append(report.Problems, reason)
+	}
+
+	report.Streams = []StreamHealth{}
+	for _, s := range h.sugar.GetAccount().ActiveStreams() {
+		last := s.LastMessage
+		if last.IsZero() {
+			last = s.StartedAt
+		}
+		lag := now.Sub(last)
+		limit, checked := h.config.MaxStreamLag[s.Name]
+		lagging := checked && limit > 0 && lag > limit
+		if lagging {
+			report.Problems = append(report.Problems, fmt.Sprintf("stream %s #%d silent for %s", s.Name, s.ID, FormatDuration(lag)))
+		}
+		report.Streams = append(report.Streams, StreamHealth{
+			Name:       s.Name,
+			StartedAt:  s.StartedAt,
+			Messages:   s.Messages,
+			Reconnects: s.Reconnects,
+			Lag:        lag.Round(time.Millisecond).String(),
+			Lagging:    lagging,
+		})
+	}
+
+	report.Orchestrators = []OrchestratorHealth{}
+	for _, o := range registered {
+		status := o.GetStatus()
+		if status.Crashed {
+			report.Problems = append(report.Problems, fmt.Sprintf("orchestrator %s crashed: %s", status.Name, status.LastError))
+		}
+		report.Orchestrators = append(report.Orchestrators, OrchestratorHealth{
+			Name:       status.Name,
+			Running:    o.IsRunning(),
+			Crashed:    status.Crashed,
+			Uptime:     FormatDuration(status.Uptime),
+			Successes:  status.SuccessCount,
+			Errors:     status.ErrorCount,
+			LastError:  status.LastError,
+			LastUpdate: status.LastUpdate,
+		})
+	}
+
+	report.Healthy = len(report.Problems) == 0
+	return report
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// HTTP HANDLERS
+// ══════════════════════════════════════════════════════════════════════════════
+
+func (h *HealthServer) handleLive(w http.ResponseWriter, r *http.Request) {
+	h.mu.RLock()
+	lastLoop := h.lastLoop
+	h.mu.RUnlock()
+
+	// A probe loop stuck for several intervals means the process is wedged
+	if !h.IsRunning() || time.Since(lastLoop) > 3*h.config.ProbeInterval+h.config.ProbeTimeout {
+		http.Error(w, "probe loop not running", http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
+
+func (h *HealthServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	report := h.Status()
+	if !report.Healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		for _, p := range report.Problems {
+			fmt.Fprintln(w, p)
+		}
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
+
+func (h *HealthServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, h.Status())
+}
@@ -0,0 +1,501 @@
+package orchestrators
+
+/*══════════════════════════════════════════════════════════════════════════════
+ ORCHESTRATOR: MetricsServer (Grafana-friendly Time-Series Endpoint)
+
+ PURPOSE:
+   Samples account equity, per-symbol exposure and the metrics of registered
+   orchestrators into in-memory time series, and serves them over HTTP so a
+   Grafana dashboard can chart a running bot without a separate database.
+
+ SERIES (name{labels}):
+   • account_balance, account_equity, account_margin, account_floating_pnl
+   • exposure_net_lots{symbol}, exposure_net_notional{symbol},
+     exposure_floating_pnl{symbol}
+   • orchestrator_net_profit{orchestrator}, orchestrator_floating_pnl{orchestrator},
+     orchestrator_fees{orchestrator}, orchestrator_drawdown{orchestrator},
+     orchestrator_max_drawdown{orchestrator}, orchestrator_positions{orchestrator}, orchestrator_trades{orchestrator},
+     orchestrator_errors{orchestrator}, orchestrator_running{orchestrator}
+
+ ENDPOINTS:
+   • GET  /series                      - all series with labels and sample count
+   • GET  /query?target=...&from=&to=  - JSON datapoints [[value, unix_ms], ...]
+                                         (from/to: unix ms or RFC3339; target
+                                         may repeat, a bare name matches all labels)
+   • POST /search, POST /query         - Grafana "SimpleJSON" / JSON datasource API
+   • GET  /metrics                     - latest values in Prometheus text format
+
+ GRAFANA:
+   Add a "JSON API" / "Infinity" datasource pointing at /query, or a
+   SimpleJSON datasource pointing at the server root. Prometheus can scrape
+   /metrics directly.
+
+ PROGRAMMATIC USAGE:
+   config := orchestrators.DefaultMetricsServerConfig()
+   config.Addr = ":9100"
+
+   server := orchestrators.NewMetricsServer(sugar, config)
+   server.Register(gridTrader)
+   server.Register(riskManager)
+   server.Start()
+   defer server.Stop()
+══════════════════════════════════════════════════════════════════════════════*/
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	mt5 "github.com/MetaRPC/GoMT5/examples/mt5"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CONFIGURATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// MetricsServerConfig holds metrics server parameters.
+type MetricsServerConfig struct {
+	Addr           string        // HTTP listen address
+	SampleInterval time.Duration // How often account, exposure and orchestrators are sampled
+	MaxSamples     int           // Samples kept per series (oldest dropped first)
+	SkipExposure   bool          // Do not sample per-symbol exposure
+}
+
+// DefaultMetricsServerConfig returns a config sampling every 10 seconds and
+// keeping one day of samples.
+func DefaultMetricsServerConfig() MetricsServerConfig {
+	return MetricsServerConfig{
+		Addr:           ":9100",
+		SampleInterval: 10 * time.Second,
+		MaxSamples:     8640,
+	}
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// TIME SERIES
+// ══════════════════════════════════════════════════════════════════════════════
+
+// MetricSample is one point of a series.
+type MetricSample struct {
+	Time  time.Time
+	Value float64
+}
+
+// MetricSeries is a named, labelled series of samples.
+type MetricSeries struct {
+	Name    string
+	Labels  map[string]string
+	Samples []MetricSample
+}
+
+// Target returns the series identifier, e.g. exposure_net_lots{symbol="EURUSD"}.
+func (s *MetricSeries) Target() string {
+	if len(s.Labels) == 0 {
+		return s.Name
+	}
+	keys := make([]string, 0, len(s.Labels))
+	for k := range s.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%q", k, s.Labels[k])
+	}
+	return s.Name + "{" + strings.Join(parts, ",") + "}"
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// METRICS SERVER IMPLEMENTATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// MetricsServer samples trading metrics and serves them as time series.
+type MetricsServer struct {
+	*BaseOrchestrator
+	sugar  *mt5.MT5Sugar
+	config MetricsServerConfig
+
+	mu            sync.RWMutex
+	series        map[string]*MetricSeries // Keyed by Target()
+	orchestrators []Orchestrator
+	routes        map[string]http.Handler // Extra handlers added with Handle
+
+	httpServer *http.Server
+}
+
+// NewMetricsServer creates a new metrics server.
+func NewMetricsServer(sugar *mt5.MT5Sugar, config MetricsServerConfig) *MetricsServer {
+	if config.Addr == "" {
+		config.Addr = ":9100"
+	}
+	if config.SampleInterval <= 0 {
+		config.SampleInterval = 10 * time.Second
+	}
+	if config.MaxSamples <= 0 {
+		config.MaxSamples = 8640
+	}
+	return &MetricsServer{
+		BaseOrchestrator: NewBaseOrchestrator("Metrics Server"),
+		sugar:            sugar,
+		config:           config,
+		series:           make(map[string]*MetricSeries),
+	}
+}
+
+// Register adds an orchestrator whose metrics are sampled.
+func (m *MetricsServer) Register(o Orchestrator) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.orchestrators = append(m.orchestrators, o)
+}
+
+// Record adds a sample to a series, creating it on first use. Use it to
+// publish custom values next to the built-in series.
+func (m *MetricsServer) Record(name string, labels map[string]string, at time.Time, value float64) {
+	key := (&MetricSeries{Name: name, Labels: labels}).Target()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.series[key]
+	if !ok {
+		copied := make(map[string]string, len(labels))
+		for k, v := range labels {
+			copied[k] = v
+		}
+		s = &MetricSeries{Name: name, Labels: copied}
+		m.series[key] = s
+	}
+	s.Samples = append(s.Samples, MetricSample{Time: at, Value: value})
+	if len(s.Samples) > m.config.MaxSamples {
+		s.Samples = s.Samples[len(s.Samples)-m.config.MaxSamples:]
+	}
+}
+
+// Handle serves an extra handler on the metrics port (e.g. HealthServer
+// endpoints). Must be called before Start.
+func (m *MetricsServer) Handle(pattern string, handler http.Handler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.routes == nil {
+		m.routes = make(map[string]http.Handler)
+	}
+	m.routes[pattern] = handler
+}
+
+// Handler returns the HTTP handler, for mounting on an existing server.
+func (m *MetricsServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	m.mu.RLock()
+	for pattern, handler := range m.routes {
+		mux.Handle(pattern, handler)
+	}
+	m.mu.RUnlock()
+	mux.HandleFunc("/series", m.handleSeries)
+	mux.HandleFunc("/search", m.handleSearch)
+	mux.HandleFunc("/query", m.handleQuery)
+	mux.HandleFunc("/metrics", m.handleMetrics)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		// SimpleJSON datasources probe the root to test the connection
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
+
+// Start begins sampling and serving.
+func (m *MetricsServer) Start() error {
+	if m.IsRunning() {
+		return fmt.Errorf("metrics server already running")
+	}
+
+	listener, err := net.Listen("tcp", m.config.Addr)
+	if err != nil {
+		return fmt.Errorf("metrics server listen on %s: %w", m.config.Addr, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.SetContext(ctx, cancel)
+	server := &http.Server{Handler: m.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	m.httpServer = server
+
+	m.MarkStarted()
+
+	m.GoSafe(func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			m.IncrementError(fmt.Sprintf("http server: %v", err))
+		}
+	})
+	m.GoSafe(m.sampleLoop)
+
+	return nil
+}
+
+// Stop stops sampling and shuts the HTTP server down.
+func (m *MetricsServer) Stop() error {
+	if !m.IsRunning() {
+		return fmt.Errorf("metrics server not running")
+	}
+
+	m.CancelContext()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := m.httpServer.Shutdown(ctx)
+
+	m.MarkStopped()
+
+	return err
+}
+
+// sampleLoop samples on every tick of SampleInterval.
+func (m *MetricsServer) sampleLoop() {
+	ticker := time.NewTicker(m.config.SampleInterval)
+	defer ticker.Stop()
+
+	m.sample()
+
+	for {
+		select {
+		case <-m.GetContext().Done():
+			return
+		case <-ticker.C:
+			m.sample()
+		}
+	}
+}
+
+// sample records one point of every built-in series.
+func (m *MetricsServer) sample() {
+	now := time.Now()
+
+	if info, err := m.sugar.GetAccountInfo(); err != nil {
+		m.IncrementError(fmt.Sprintf("failed to get account info: %v", err))
+	} else {
+		m.Record("account_balance", nil, now, info.Balance)
+		m.Record("account_equity", nil, now, info.Equity)
+		m.Record("account_margin", nil, now, info.Margin)
+		m.Record("account_floating_pnl", nil, now, info.Equity-info.Balance)
+	}
+
+	if !m.config.SkipExposure {
+		exposure, err := m.sugar.GetNetExposure()
+		if err != nil {
+			m.IncrementError(fmt.Sprintf("failed to get exposure: %v", err))
+		}
+		for symbol, e := range exposure {
+			labels := map[string]string{"symbol": symbol}
+			m.Record("exposure_net_lots", labels, now, e.NetLots)
+			m.Record("exposure_net_notional", labels, now, e.NetNotional)
+			m.Record("exposure_floating_pnl", labels, now, e.FloatingPnL)
+		}
+	}
+
+	m.mu.RLock()
+	registered := append([]Orchestrator(nil), m.orchestrators...)
+	m.mu.RUnlock()
+
+	for _, o := range registered {
+		status := o.GetStatus()
+		metrics := o.GetMetrics()
+		labels := map[string]string{"orchestrator": status.Name}
+
+		running := 0.0
+		if o.IsRunning() {
+			running = 1
+		}
+		m.Record("orchestrator_net_profit", labels, now, metrics.NetProfit)
+		m.Record("orchestrator_floating_pnl", labels, now, metrics.FloatingPnL)
+		m.Record("orchestrator_fees", labels, now, metrics.Fees)
+		m.Record("orchestrator_drawdown", labels, now, metrics.CurrentDrawdown)
+		m.Record("orchestrator_max_drawdown", labels, now, metrics.MaxDrawdown)
+		m.Record("orchestrator_positions", labels, now, float64(metrics.CurrentPositions))
+		m.Record("orchestrator_trades", labels, now, float64(metrics.TotalTrades))
+		m.Record("orchestrator_errors", labels, now, float64(status.ErrorCount))
+		m.Record("orchestrator_running", labels, now, running)
+	}
+
+	m.IncrementSuccess()
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// QUERIES
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Query returns the series matching target within [from, to]. A target is
+// either a full identifier from /series or a bare name matching all its
+// label sets. Zero from/to leave that side unbounded.
+func (m *MetricsServer) Query(target string, from, to time.Time) []MetricSeries {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []MetricSeries
+	for key, s := range m.series {
+		if key != target && s.Name != target {
+			continue
+		}
+		out := MetricSeries{Name: s.Name, Labels: s.Labels}
+		for _, p := range s.Samples {
+			if (!from.IsZero() && p.Time.Before(from)) || (!to.IsZero() && p.Time.After(to)) {
+				continue
+			}
+			out.Samples = append(out.Samples, p)
+		}
+		result = append(result, out)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Target() < result[j].Target() })
+	return result
+}
+
+// targets returns all series identifiers, sorted.
+func (m *MetricsServer) targets() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.sortedKeysLocked()
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// HTTP HANDLERS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// timeSeriesJSON is one series in the SimpleJSON response format.
+type timeSeriesJSON struct {
+	Target     string            `json:"target"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Datapoints [][2]float64      `json:"datapoints"` // [value, unix_ms]
+}
+
+func (m *MetricsServer) handleSeries(w http.ResponseWriter, r *http.Request) {
+	type seriesInfo struct {
+		Target  string            `json:"target"`
+		Name    string            `json:"name"`
+		Labels  map[string]string `json:"labels,omitempty"`
+		Samples int               `json:"samples"`
+	}
+
+	m.mu.RLock()
+	infos := make([]seriesInfo, 0, len(m.series))
+	for key, s := range m.series {
+		infos = append(infos, seriesInfo{Target: key, Name: s.Name, Labels: s.Labels, Samples: len(s.Samples)})
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Target < infos[j].Target })
+	writeJSON(w, infos)
+}
+
+func (m *MetricsServer) handleSearch(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, m.targets())
+}
+
+func (m *MetricsServer) handleQuery(w http.ResponseWriter, r *http.Request) {
+	var targets []string
+	var from, to time.Time
+
+	if r.Method == http.MethodPost {
+		// Grafana SimpleJSON request body
+		var req struct {
+			Range struct {
+				From time.Time `json:"from"`
+				To   time.Time `json:"to"`
+			} `json:"range"`
+			Targets []struct {
+				Target string `json:"target"`
+			} `json:"targets"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid query: %v", err), http.StatusBadRequest)
+			return
+		}
+		from, to = req.Range.From, req.Range.To
+		for _, t := range req.Targets {
+			targets = append(targets, t.Target)
+		}
+	} else {
+		q := r.URL.Query()
+		targets = q["target"]
+		var err error
+		if from, err = parseQueryTime(q.Get("from")); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if to, err = parseQueryTime(q.Get("to")); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	response := make([]timeSeriesJSON, 0, len(targets))
+	for _, target := range targets {
+		for _, s := range m.Query(target, from, to) {
+			points := make([][2]float64, len(s.Samples))
+			for i, p := range s.Samples {
+				points[i] = [2]float64{p.Value, float64(p.Time.UnixMilli())}
+			}
+			response = append(response, timeSeriesJSON{Target: s.Target(), Labels: s.Labels, Datapoints: points})
+		}
+	}
+	writeJSON(w, response)
+}
+
+func (m *MetricsServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, key := range m.sortedKeysLocked() {
+		s := m.series[key]
+		if len(s.Samples) == 0 {
+			continue
+		}
+		last := s.Samples[len(s.Samples)-1]
+		fmt.Fprintf(w, "mt5_%s %s %d\n", key, strconv.FormatFloat(last.Value, 'g', -1, 64), last.Time.UnixMilli())
+	}
+}
+
+// sortedKeysLocked returns series keys sorted; caller holds m.mu.
+func (m *MetricsServer) sortedKeysLocked() []string {
+	keys := make([]string, 0, len(m.series))
+	for key := range m.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// parseQueryTime parses unix milliseconds or RFC3339; "" is the zero time.
+func parseQueryTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.UnixMilli(ms), nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: want unix ms or RFC3339", v)
+	}
+	return t, nil
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
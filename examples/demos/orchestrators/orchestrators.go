@@ -193,8 +193,11 @@ package orchestrators
 import (
 	"context"
 	"fmt"
+	"runtime/debug"
 	"sync"
 	"time"
+
+	mt5 "github.com/MetaRPC/GoMT5/examples/mt5"
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -236,22 +239,69 @@ type OrchestratorStatus struct {
 	SuccessCount int           // Successful operations
 	LastError    string        // Last error message (if any)
 	Uptime       time.Duration // Time since start
+	Crashed      bool          // Stopped by a recovered panic
+	StopReason   string        // Why it stopped itself (runtime limit, panic); "" = Stop called
 }
 
 // OrchestratorMetrics tracks performance and trading statistics.
+//
+// COMPUTATION CONTRACT:
+//   All money values are in account currency.
+//
+//   Closed trades (RecordTrade):
+//     gross = price P/L of the trade, fees = commission + swap + fee (negative = cost),
+//     net = gross + fees. A trade is winning if net > 0, losing if net < 0,
+//     breakeven otherwise.
+//     GrossPnL    = Σ gross          Fees      = Σ fees
+//     TotalProfit = Σ net of wins    TotalLoss = Σ |net| of losses (positive)
+//     NetProfit   = TotalProfit - TotalLoss (= GrossPnL + Fees)
+//
+//   Open positions (SetFloating):
+//     FloatingPnL = profit + swap + commission of the open positions
+//     TotalPnL    = NetProfit + FloatingPnL
+//
+//   Drawdown (ObserveEquity):
+//     PeakEquity             = highest equity observed
+//     CurrentDrawdown        = PeakEquity - last equity (>= 0)
+//     MaxDrawdown            = largest CurrentDrawdown ever observed
+//     *DrawdownPercent       = drawdown / PeakEquity * 100 (0 while PeakEquity <= 0)
+//   RecordTrade and SetFloating observe Capital + TotalPnL automatically, so a
+//   strategy only has to report trades and floating P/L. Account-level
+//   orchestrators call ObserveEquity with account equity instead; from the
+//   first call on, RecordTrade and SetFloating no longer observe.
+//
+//   Orchestrators report closed positions with BaseOrchestrator.RecordClosedTrades,
+//   which takes gross and fees from the position history.
+//
+//   BySymbol holds the same realized / floating split per symbol.
 type OrchestratorMetrics struct {
 	// Trading Stats
-	TotalTrades      int     // Total number of trades executed
-	WinningTrades    int     // Number of profitable trades
-	LosingTrades     int     // Number of losing trades
-	BreakevenTrades  int     // Trades closed at breakeven
-
-	// Financial Metrics
-	TotalProfit      float64 // Total realized profit
-	TotalLoss        float64 // Total realized loss
-	NetProfit        float64 // Net profit (profit - loss)
-	MaxDrawdown      float64 // Maximum drawdown experienced
-	CurrentDrawdown  float64 // Current drawdown
+	TotalTrades      int     // Total number of closed trades
+	WinningTrades    int     // Trades with net > 0
+	LosingTrades     int     // Trades with net < 0
+	BreakevenTrades  int     // Trades with net == 0
+
+	// Realized P/L (closed trades)
+	GrossPnL         float64 // Price P/L before fees
+	Fees             float64 // Commission + swap + fees (negative = cost)
+	TotalProfit      float64 // Sum of net results of winning trades
+	TotalLoss        float64 // Sum of net losses of losing trades (positive)
+	NetProfit        float64 // Realized net P/L (TotalProfit - TotalLoss)
+
+	// Floating P/L (open positions)
+	FloatingPnL      float64 // Profit + swap + commission of open positions
+	TotalPnL         float64 // NetProfit + FloatingPnL
+
+	// Drawdown
+	Capital                float64 // Strategy capital added to TotalPnL for drawdown (0 = P/L curve only)
+	PeakEquity             float64 // Highest equity observed
+	CurrentDrawdown        float64 // PeakEquity - current equity
+	CurrentDrawdownPercent float64 // Current drawdown as % of PeakEquity
+	MaxDrawdown            float64 // Deepest drawdown observed
+	MaxDrawdownPercent     float64 // Deepest drawdown as % of the peak it started from
+
+	// Per-Symbol Breakdown
+	BySymbol         map[string]SymbolMetrics
 
 	// Position Stats
 	CurrentPositions int     // Currently open positions
@@ -260,7 +310,7 @@ type OrchestratorMetrics struct {
 
 	// Performance Metrics
 	WinRate          float64 // Win rate percentage
-	ProfitFactor     float64 // Gross profit / gross loss
+	ProfitFactor     float64 // TotalProfit / TotalLoss
 	AvgWin           float64 // Average winning trade
 	AvgLoss          float64 // Average losing trade
 
@@ -268,6 +318,129 @@ type OrchestratorMetrics struct {
 	OperationsTotal  int     // Total operations performed
 	OperationsFailed int     // Failed operations
 	LastOperation    string  // Description of last operation
+
+	equityObserved bool // PeakEquity holds a real sample
+	equityExternal bool // ObserveEquity was called: equity comes from the account
+}
+
+// SymbolMetrics is the per-symbol part of OrchestratorMetrics.
+type SymbolMetrics struct {
+	Trades      int     // Closed trades
+	Wins        int     // Trades with net > 0
+	Losses      int     // Trades with net < 0
+	GrossPnL    float64 // Price P/L before fees
+	Fees        float64 // Commission + swap + fees (negative = cost)
+	NetProfit   float64 // GrossPnL + Fees
+	FloatingPnL float64 // Floating P/L of open positions
+}
+
+// RecordTrade adds one closed trade.
+// gross is the price P/L, fees the signed commission + swap + fee.
+func (m *OrchestratorMetrics) RecordTrade(symbol string, gross, fees float64) {
+	net := gross + fees
+
+	m.TotalTrades++
+	m.GrossPnL += gross
+	m.Fees += fees
+	switch {
+	case net > 0:
+		m.WinningTrades++
+		m.TotalProfit += net
+	case net < 0:
+		m.LosingTrades++
+		m.TotalLoss += -net
+	default:
+		m.BreakevenTrades++
+	}
+
+	sym := m.symbol(symbol)
+	sym.Trades++
+	sym.GrossPnL += gross
+	sym.Fees += fees
+	sym.NetProfit += net
+	if net > 0 {
+		sym.Wins++
+	} else if net < 0 {
+		sym.Losses++
+	}
+	m.BySymbol[symbol] = sym
+
+	m.UpdateMetrics()
+	m.observePnL()
+}
+
+// SetFloating replaces the floating P/L of open positions, per symbol.
+// Symbols missing from bySymbol are treated as flat.
+func (m *OrchestratorMetrics) SetFloating(bySymbol map[string]float64) {
+	m.FloatingPnL = 0
+	for symbol, sym := range m.BySymbol {
+		sym.FloatingPnL = 0
+		m.BySymbol[symbol] = sym
+	}
+	for symbol, pnl := range bySymbol {
+		sym := m.symbol(symbol)
+		sym.FloatingPnL = pnl
+		m.BySymbol[symbol] = sym
+		m.FloatingPnL += pnl
+	}
+
+	m.UpdateMetrics()
+	m.observePnL()
+}
+
+// ObserveEquity updates peak, current and max drawdown with a new equity value.
+// Once called, drawdown follows only the equity passed here.
+func (m *OrchestratorMetrics) ObserveEquity(equity float64) {
+	m.equityExternal = true
+	m.observe(equity)
+}
+
+// observePnL samples Capital + TotalPnL unless equity comes from ObserveEquity.
+func (m *OrchestratorMetrics) observePnL() {
+	if !m.equityExternal {
+		m.observe(m.Capital + m.TotalPnL)
+	}
+}
+
+// observe updates peak, current and max drawdown.
+func (m *OrchestratorMetrics) observe(equity float64) {
+	if !m.equityObserved || equity > m.PeakEquity {
+		m.PeakEquity = equity
+		m.equityObserved = true
+	}
+
+	m.CurrentDrawdown = m.PeakEquity - equity
+	m.CurrentDrawdownPercent = 0
+	if m.PeakEquity > 0 {
+		m.CurrentDrawdownPercent = m.CurrentDrawdown / m.PeakEquity * 100
+	}
+
+	if m.CurrentDrawdown > m.MaxDrawdown {
+		m.MaxDrawdown = m.CurrentDrawdown
+	}
+	if m.CurrentDrawdownPercent > m.MaxDrawdownPercent {
+		m.MaxDrawdownPercent = m.CurrentDrawdownPercent
+	}
+}
+
+// symbol returns the per-symbol entry, allocating the map on first use.
+func (m *OrchestratorMetrics) symbol(symbol string) SymbolMetrics {
+	if m.BySymbol == nil {
+		m.BySymbol = make(map[string]SymbolMetrics)
+	}
+	return m.BySymbol[symbol]
+}
+
+// clone returns a copy that shares no map with m.
+func (m OrchestratorMetrics) clone() OrchestratorMetrics {
+	if m.BySymbol != nil {
+		bySymbol := make(map[string]SymbolMetrics, len(m.BySymbol))
+		for symbol, sym := range m.BySymbol {
+			bySymbol[symbol] = sym
+		}
+		m.BySymbol = bySymbol
+	}
+	return m
 }
 
 // CalculateWinRate calculates the win rate percentage.
@@ -299,11 +472,18 @@ func (m *OrchestratorMetrics) CalculateAverages() {
 // UpdateMetrics recalculates all derived metrics.
 func (m *OrchestratorMetrics) UpdateMetrics() {
 	m.NetProfit = m.TotalProfit - m.TotalLoss
+	m.TotalPnL = m.NetProfit + m.FloatingPnL
 	m.CalculateWinRate()
 	m.CalculateProfitFactor()
 	m.CalculateAverages()
 }
 
+// KellySizer builds a Kelly position sizer from these performance stats.
+// fraction is the share of full Kelly to use, maxRiskPercent caps risk per trade.
+func (m *OrchestratorMetrics) KellySizer(fraction, maxRiskPercent float64) *mt5.KellySizer {
+	return mt5.NewKellySizer(m.WinRate, m.AvgWin, m.AvgLoss, fraction, maxRiskPercent)
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // BASE ORCHESTRATOR IMPLEMENTATION
 // ══════════════════════════════════════════════════════════════════════════════
@@ -318,6 +498,13 @@ type BaseOrchestrator struct {
 	status      OrchestratorStatus
 	metrics     OrchestratorMetrics
 	updateChan  chan struct{}
+	onCrash     func(reason string)
+	owned       map[uint64]bool // Order/position tickets opened by this orchestrator
+	tracked     map[uint64]time.Time // Closed positions not yet found in history → when reported
+	settled     map[uint64]bool // Closed positions counted in metrics or given up on
+	gate        *SignalGate     // Entry cooldown/deduplication (nil = off)
+	circuit     *VolatilityCircuit // Entry throttling on volatility/spread spikes (nil = off)
+	decisions   *DecisionLog    // Decision reasons (nil = off)
 }
 
 // NewBaseOrchestrator creates a new base orchestrator with given name.
@@ -356,7 +543,7 @@ func (b *BaseOrchestrator) GetMetrics() OrchestratorMetrics {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
-	metrics := b.metrics
+	metrics := b.metrics.clone()
 	metrics.UpdateMetrics()
 	return metrics
 }
@@ -409,6 +596,7 @@ func (b *BaseOrchestrator) MarkStarted() {
 	b.status.IsRunning = true
 	b.status.StartTime = time.Now()
 	b.status.LastUpdate = time.Now()
+	b.status.StopReason = ""
 }
 
 // MarkStopped marks orchestrator as stopped.
@@ -444,6 +632,275 @@ func (b *BaseOrchestrator) CancelContext() {
 	}
 }
 
+// Own records a ticket opened by this orchestrator, for P&L attribution.
+// In MT5 a position's ticket equals the ticket of the order that opened it,
+// so recording the order ticket also covers the resulting position.
+func (b *BaseOrchestrator) Own(ticket uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.owned == nil {
+		b.owned = make(map[uint64]bool)
+	}
+	b.owned[ticket] = true
+}
+
+// OwnsTicket reports whether ticket was recorded with Own.
+func (b *BaseOrchestrator) OwnsTicket(ticket uint64) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.owned[ticket]
+}
+
+// closedTradeLookup is how long RecordClosedTrades looks for a closed
+// position in the history.
+const closedTradeLookup = 5 * time.Minute
+
+// RecordClosedTrades adds closed positions to the metrics with RecordTrade,
+// each once. Pass the tickets of positions the orchestrator closed or saw
+// disappear (closed by SL/TP); tickets not yet in the position history are
+// kept and looked up again on later calls for up to closedTradeLookup.
+// Without pending tickets it makes no request.
+func (b *BaseOrchestrator) RecordClosedTrades(sugar *mt5.MT5Sugar, tickets ...uint64) error {
+	now := time.Now()
+
+	b.mu.Lock()
+	if b.tracked == nil {
+		b.tracked = make(map[uint64]time.Time)
+		b.settled = make(map[uint64]bool)
+	}
+	for _, ticket := range tickets {
+		if _, ok := b.tracked[ticket]; !ok && !b.settled[ticket] {
+			b.tracked[ticket] = now
+		}
+	}
+	pending := len(b.tracked)
+	started := b.status.StartTime
+	b.mu.Unlock()
+	if pending == 0 {
+		return nil
+	}
+
+	// History times are in server time: widen the window by a day each way
+	if started.IsZero() {
+		started = now
+	}
+	closed, err := sugar.GetDealsDateRange(started.Add(-24*time.Hour), now.Add(24*time.Hour))
+	if err != nil {
+		return fmt.Errorf("record closed trades: %w", err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, pos := range closed {
+		ticket := pos.PositionTicket
+		if _, ok := b.tracked[ticket]; !ok {
+			continue
+		}
+		delete(b.tracked, ticket)
+		b.settled[ticket] = true
+		b.metrics.RecordTrade(pos.Symbol, pos.Profit, pos.Commission+pos.Swap+pos.Fee)
+	}
+	for ticket, at := range b.tracked {
+		if now.Sub(at) > closedTradeLookup {
+			delete(b.tracked, ticket) // Still open (partial close) or not in history
+			b.settled[ticket] = true
+		}
+	}
+	return nil
+}
+
+// SetSignalGate attaches an entry cooldown/deduplication gate. The gate's
+// rules for this orchestrator are looked up by its name.
+func (b *BaseOrchestrator) SetSignalGate(gate *SignalGate) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.gate = gate
+}
+
+// SetVolatilityCircuit attaches an entry throttle for volatility and spread
+// spikes. The circuit's rules for this orchestrator are looked up by its name.
+func (b *BaseOrchestrator) SetVolatilityCircuit(circuit *VolatilityCircuit) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.circuit = circuit
+}
+
+// AllowEntry checks the volatility circuit and the signal gate before
+// opening a position. Always nil without either; a suppressed entry returns
+// *SuppressedSignalError.
+func (b *BaseOrchestrator) AllowEntry(symbol string, buy bool) error {
+	if err := b.ThrottleEntry(symbol, buy); err != nil {
+		return err
+	}
+
+	b.mu.RLock()
+	gate, name := b.gate, b.status.Name
+	b.mu.RUnlock()
+	if gate == nil {
+		return nil
+	}
+	err := gate.Allow(name, symbol, buy, b.OwnsTicket)
+	if err != nil {
+		b.logSkippedEntry(symbol, buy, err)
+	}
+	return err
+}
+
+// ThrottleEntry checks only the volatility circuit, for entries that the
+// signal gate's deduplication does not fit (scale-ins, grid orders). Always
+// nil without a circuit.
+func (b *BaseOrchestrator) ThrottleEntry(symbol string, buy bool) error {
+	b.mu.RLock()
+	circuit, name := b.circuit, b.status.Name
+	b.mu.RUnlock()
+	if circuit == nil {
+		return nil
+	}
+	err := circuit.Allow(name, symbol, buy)
+	if err != nil {
+		b.logSkippedEntry(symbol, buy, err)
+	}
+	return err
+}
+
+// logSkippedEntry records a suppressed entry in the decision log.
+func (b *BaseOrchestrator) logSkippedEntry(symbol string, buy bool, err error) {
+	action := "sell"
+	if buy {
+		action = "buy"
+	}
+	b.LogDecision(DecisionEntry{Symbol: symbol, Action: action, Skipped: true, Reason: err.Error()})
+}
+
+// RecordEntry starts the gate's cooldown and the circuit's entry interval
+// after a successful entry.
+func (b *BaseOrchestrator) RecordEntry(symbol string, buy bool) {
+	b.mu.RLock()
+	gate, circuit, name := b.gate, b.circuit, b.status.Name
+	b.mu.RUnlock()
+	if gate != nil {
+		gate.Record(name, symbol, buy)
+	}
+	if circuit != nil {
+		circuit.Record(name, symbol)
+	}
+}
+
+// SetDecisionLog attaches a log for decision reasons. Several orchestrators
+// may share one log; entries carry the orchestrator name.
+func (b *BaseOrchestrator) SetDecisionLog(log *DecisionLog) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.decisions = log
+}
+
+// LogDecision records why the orchestrator acted or skipped an action.
+// The orchestrator name and time are filled in; no-op without a log.
+func (b *BaseOrchestrator) LogDecision(entry DecisionEntry) {
+	b.mu.RLock()
+	log, name := b.decisions, b.status.Name
+	b.mu.RUnlock()
+	if log == nil {
+		return
+	}
+	entry.Orchestrator = name
+	if err := log.Record(entry); err != nil {
+		b.IncrementError(err.Error())
+	}
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CRASH ISOLATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// GoSafe runs fn in a new goroutine with panic recovery.
+// Use it instead of a bare "go" for orchestrator loops. A panic inside fn is
+// converted into ErrorCount/LastError, the orchestrator is stopped (context
+// cancelled, marked not running) and the crash handler, if set, is called.
+// One buggy strategy can no longer take down the whole process.
+func (b *BaseOrchestrator) GoSafe(fn func()) {
+	go func() {
+		defer b.recoverPanic()
+		fn()
+	}()
+}
+
+// SetCrashHandler registers a function called after a recovered panic.
+// See FlattenOnCrash for a ready-made handler that closes its positions.
+func (b *BaseOrchestrator) SetCrashHandler(handler func(reason string)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onCrash = handler
+}
+
+// recoverPanic performs the emergency stop after a panic.
+func (b *BaseOrchestrator) recoverPanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	reason := fmt.Sprintf("panic: %v", r)
+	name := b.GetStatus().Name
+	fmt.Printf("\n🔥 [%s] %s - emergency stop\n%s\n", name, reason, debug.Stack())
+
+	b.IncrementError(reason)
+	b.UpdateStatus(func(s *OrchestratorStatus) {
+		s.Crashed = true
+		s.StopReason = reason
+	})
+	b.CancelContext()
+	b.MarkStopped()
+
+	b.mu.RLock()
+	handler := b.onCrash
+	b.mu.RUnlock()
+
+	if handler != nil {
+		// The handler must not bring the process down either
+		defer func() {
+			if r := recover(); r != nil {
+				fmt.Printf("🔥 [%s] crash handler panicked: %v\n", name, r)
+			}
+		}()
+		handler(reason)
+	}
+}
+
+// FlattenOnCrash returns a crash handler that closes the open positions owned
+// by the crashed orchestrator (tickets recorded with Own). Positions of other
+// strategies and manual trades on the account are left alone.
+//
+// Usage:
+//   orch.SetCrashHandler(orchestrators.FlattenOnCrash(sugar, orch))
+func FlattenOnCrash(sugar *mt5.MT5Sugar, owner TicketOwner) func(reason string) {
+	return func(reason string) {
+		positions, err := sugar.GetOpenPositions()
+		if err != nil {
+			fmt.Printf("⚠️  Flatten after crash failed: %v\n", err)
+			return
+		}
+
+		closed, failed := 0, 0
+		for _, pos := range positions {
+			if !owner.OwnsTicket(pos.Ticket) {
+				continue
+			}
+			if err := sugar.ClosePosition(pos.Ticket); err != nil {
+				fmt.Printf("⚠️  Flatten after crash: #%d not closed: %v\n", pos.Ticket, err)
+				failed++
+				continue
+			}
+			closed++
+		}
+		if failed > 0 {
+			fmt.Printf("⚠️  Flatten after crash incomplete (%d closed, %d failed)\n", closed, failed)
+			return
+		}
+		fmt.Printf("🛡️  Flattened %d owned position(s) after crash (%s)\n", closed, reason)
+	}
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // UTILITY FUNCTIONS
 // ══════════════════════════════════════════════════════════════════════════════
@@ -479,3 +936,15 @@ func RoundToDigits(price float64, digits int) float64 {
 	}
 	return float64(int(price*multiplier+0.5)) / multiplier
 }
+
+// explainTradeError formats a failed trade for IncrementError: the error plus
+// the likely cause and fix when it carries an MT5 code, e.g. "... (Invalid
+// stops) - SL/TP closer than stops level 30 points; fix: place SL/TP at least
+// 30 points from the current price".
+func explainTradeError(sugar *mt5.MT5Sugar, err error, symbol string) string {
+	ex, ok := sugar.ExplainErr(err, symbol)
+	if !ok || ex.Fix == "" {
+		return err.Error()
+	}
+	return fmt.Sprintf("%v - %s; fix: %s", err, ex.Cause, ex.Fix)
+}
@@ -0,0 +1,129 @@
+package orchestrators
+
+import (
+	"math"
+	"testing"
+)
+
+func approx(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
+
+func TestRecordTradeClassifiesByNet(t *testing.T) {
+	var m OrchestratorMetrics
+	m.RecordTrade("EURUSD", 100, -5) // win: net 95
+	m.RecordTrade("EURUSD", -40, -2) // loss: net -42
+	m.RecordTrade("GBPUSD", 3, -3)   // breakeven: gross profit eaten by fees
+
+	if m.TotalTrades != 3 || m.WinningTrades != 1 || m.LosingTrades != 1 || m.BreakevenTrades != 1 {
+		t.Fatalf("trades = %d (%d/%d/%d), want 3 (1/1/1)",
+			m.TotalTrades, m.WinningTrades, m.LosingTrades, m.BreakevenTrades)
+	}
+	approx(t, "GrossPnL", m.GrossPnL, 63)
+	approx(t, "Fees", m.Fees, -10)
+	approx(t, "TotalProfit", m.TotalProfit, 95)
+	approx(t, "TotalLoss", m.TotalLoss, 42)
+	approx(t, "NetProfit", m.NetProfit, 53)
+	approx(t, "NetProfit vs GrossPnL+Fees", m.NetProfit, m.GrossPnL+m.Fees)
+	approx(t, "TotalPnL", m.TotalPnL, 53)
+	approx(t, "WinRate", m.WinRate, 100.0/3)
+	approx(t, "ProfitFactor", m.ProfitFactor, 95.0/42)
+	approx(t, "AvgWin", m.AvgWin, 95)
+	approx(t, "AvgLoss", m.AvgLoss, 42)
+}
+
+func TestRecordTradePerSymbol(t *testing.T) {
+	var m OrchestratorMetrics
+	m.RecordTrade("EURUSD", 100, -5)
+	m.RecordTrade("EURUSD", -40, -2)
+	m.RecordTrade("GBPUSD", 3, -3)
+
+	eur := m.BySymbol["EURUSD"]
+	if eur.Trades != 2 || eur.Wins != 1 || eur.Losses != 1 {
+		t.Errorf("EURUSD trades = %d (%d/%d), want 2 (1/1)", eur.Trades, eur.Wins, eur.Losses)
+	}
+	approx(t, "EURUSD GrossPnL", eur.GrossPnL, 60)
+	approx(t, "EURUSD Fees", eur.Fees, -7)
+	approx(t, "EURUSD NetProfit", eur.NetProfit, 53)
+
+	gbp := m.BySymbol["GBPUSD"]
+	if gbp.Trades != 1 || gbp.Wins != 0 || gbp.Losses != 0 {
+		t.Errorf("GBPUSD trades = %d (%d/%d), want 1 (0/0)", gbp.Trades, gbp.Wins, gbp.Losses)
+	}
+	approx(t, "GBPUSD NetProfit", gbp.NetProfit, 0)
+
+	var net float64
+	for _, sym := range m.BySymbol {
+		net += sym.NetProfit
+	}
+	approx(t, "sum of BySymbol NetProfit", net, m.NetProfit)
+}
+
+func TestProfitFactorWithoutLosses(t *testing.T) {
+	var m OrchestratorMetrics
+	m.RecordTrade("EURUSD", 10, 0)
+	approx(t, "ProfitFactor", m.ProfitFactor, 999.99)
+
+	var flat OrchestratorMetrics
+	flat.RecordTrade("EURUSD", 1, -1)
+	approx(t, "ProfitFactor (breakeven only)", flat.ProfitFactor, 0)
+}
+
+func TestFloatingAndDrawdown(t *testing.T) {
+	m := OrchestratorMetrics{Capital: 1000}
+	m.RecordTrade("EURUSD", 100, -5) // equity 1095 (peak)
+	m.RecordTrade("EURUSD", -40, -2) // equity 1053
+	approx(t, "PeakEquity", m.PeakEquity, 1095)
+	approx(t, "CurrentDrawdown", m.CurrentDrawdown, 42)
+
+	m.SetFloating(map[string]float64{"EURUSD": -20, "GBPUSD": 5}) // equity 1038
+	approx(t, "FloatingPnL", m.FloatingPnL, -15)
+	approx(t, "TotalPnL", m.TotalPnL, 38)
+	approx(t, "CurrentDrawdown", m.CurrentDrawdown, 57)
+	approx(t, "CurrentDrawdownPercent", m.CurrentDrawdownPercent, 57.0/1095*100)
+
+	m.SetFloating(map[string]float64{"GBPUSD": 30}) // EURUSD flat again, equity 1083
+	approx(t, "EURUSD FloatingPnL", m.BySymbol["EURUSD"].FloatingPnL, 0)
+	approx(t, "FloatingPnL", m.FloatingPnL, 30)
+	approx(t, "CurrentDrawdown", m.CurrentDrawdown, 12)
+	approx(t, "MaxDrawdown", m.MaxDrawdown, 57)
+	approx(t, "MaxDrawdownPercent", m.MaxDrawdownPercent, 57.0/1095*100)
+}
+
+func TestObserveEquityTakesOverDrawdown(t *testing.T) {
+	var m OrchestratorMetrics
+	m.ObserveEquity(10000)
+	m.ObserveEquity(9800)
+	m.RecordTrade("EURUSD", -50, 0) // must not feed the P/L curve into account drawdown
+
+	approx(t, "PeakEquity", m.PeakEquity, 10000)
+	approx(t, "CurrentDrawdown", m.CurrentDrawdown, 200)
+	approx(t, "NetProfit", m.NetProfit, -50)
+}
+
+func TestCloneSharesNoMap(t *testing.T) {
+	var m OrchestratorMetrics
+	m.RecordTrade("EURUSD", 10, -1)
+
+	c := m.clone()
+	sym := c.BySymbol["EURUSD"]
+	sym.NetProfit = 999
+	c.BySymbol["EURUSD"] = sym
+	c.BySymbol["GBPUSD"] = SymbolMetrics{Trades: 1}
+
+	approx(t, "original EURUSD NetProfit", m.BySymbol["EURUSD"].NetProfit, 9)
+	if _, ok := m.BySymbol["GBPUSD"]; ok {
+		t.Error("symbol added to the clone shows up in the original")
+	}
+	if c.TotalTrades != m.TotalTrades || c.NetProfit != m.NetProfit {
+		t.Errorf("clone totals = %d/%v, want %d/%v", c.TotalTrades, c.NetProfit, m.TotalTrades, m.NetProfit)
+	}
+
+	var empty OrchestratorMetrics
+	if empty.clone().BySymbol != nil {
+		t.Error("clone of empty metrics allocated BySymbol")
+	}
+}
@@ -0,0 +1,429 @@
+package orchestrators
+
+/*══════════════════════════════════════════════════════════════════════════════
+ PARITY HARNESS: Backtest-to-Live Decision Diff
+
+ PURPOSE:
+   A strategy that backtests well and trades differently live usually has
+   lookahead bias (peeking at a bar before it closed, reading time.Now()
+   instead of the event time) or depends on something the replay does not
+   reproduce. The harness runs the same strategy twice and diffs what it
+   DECIDED:
+
+   • Dry-run  - live ticks through a StrategyRunner; decisions are recorded,
+                never sent
+   • Backtest - the same period replayed tick by tick into a fresh instance
+                (the ticks seen live, or a TickStore for an independent feed)
+
+ Strategies report decisions to the DecisionRecorder they are built with
+ instead of calling Sugar; each decision is stamped with the time of the
+ event being handled. Replay delivers OnTick/OnBar exactly like the runner
+ and fires OnTimer on tick time. Trade events are not replayed.
+
+ REPORT:
+   • Matched     - same symbol and action within TimeTolerance
+   • Mismatched  - matched, but volume or price differ
+   • LiveOnly    - decided live, not in replay (state, wall clock, feed)
+   • ReplayOnly  - decided in replay only (often lookahead)
+
+ PROGRAMMATIC USAGE:
+   harness := orchestrators.NewParityHarness("Breakout parity", sugar,
+       func(rec *orchestrators.DecisionRecorder) orchestrators.Strategy {
+           return &breakout{rec: rec} // rec.Decide("EURUSD", "BUY", 0.1, ask, "range break")
+       },
+       orchestrators.ParityConfig{Runner: orchestrators.DefaultStrategyRunnerConfig([]string{"EURUSD"})})
+   harness.Start()
+   time.Sleep(4 * time.Hour)
+   harness.Stop()
+
+   report, err := harness.Compare()
+   fmt.Println(report)
+══════════════════════════════════════════════════════════════════════════════*/
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	mt5 "github.com/MetaRPC/GoMT5/examples/mt5"
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// DECISIONS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Decision is one trading decision of a strategy.
+type Decision struct {
+	Time   time.Time // Time of the event the decision was made on
+	Symbol string
+	Action string // Strategy-defined (e.g., "BUY", "SELL", "CLOSE")
+	Volume float64
+	Price  float64
+	Reason string
+}
+
+func (d Decision) String() string {
+	s := fmt.Sprintf("%s %s %s %.2f @ %g", d.Time.Format("2006-01-02 15:04:05.000"), d.Symbol, d.Action, d.Volume, d.Price)
+	if d.Reason != "" {
+		s += " (" + d.Reason + ")"
+	}
+	return s
+}
+
+// DecisionRecorder collects the decisions of one strategy instance. Safe for
+// concurrent use.
+type DecisionRecorder struct {
+	mu        sync.Mutex
+	clock     time.Time
+	decisions []Decision
+}
+
+// Decide records a decision at the time of the event being handled.
+func (r *DecisionRecorder) Decide(symbol, action string, volume, price float64, reason string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.decisions = append(r.decisions, Decision{
+		Time: r.clock, Symbol: symbol, Action: action, Volume: volume, Price: price, Reason: reason,
+	})
+}
+
+// Decisions returns a copy of the recorded decisions, in order.
+func (r *DecisionRecorder) Decisions() []Decision {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Decision(nil), r.decisions...)
+}
+
+// setClock sets the time stamped on the next decisions.
+func (r *DecisionRecorder) setClock(t time.Time) {
+	r.mu.Lock()
+	r.clock = t
+	r.mu.Unlock()
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CONFIGURATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// ParityStrategyFactory builds a fresh strategy that reports decisions to rec.
+type ParityStrategyFactory func(rec *DecisionRecorder) Strategy
+
+// ParityConfig holds harness parameters.
+type ParityConfig struct {
+	Runner         StrategyRunnerConfig // Symbols, bars and timer of both runs
+	Ticks          *mt5.TickStore       // Replay source (nil = the ticks received live)
+	TimeTolerance  time.Duration        // Decisions this close in time match (default 1s)
+	PriceTolerance float64              // Max price difference of matched decisions (0 = prices not compared)
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// PARITY HARNESS IMPLEMENTATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// parityTap forwards events to the live strategy, recording ticks and
+// keeping the recorder clock on event time.
+type parityTap struct {
+	strategy Strategy
+	rec      *DecisionRecorder
+
+	mu    sync.Mutex
+	ticks []mt5.SymbolTick
+}
+
+func (t *parityTap) OnTick(tick *mt5.SymbolTick) error {
+	t.mu.Lock()
+	t.ticks = append(t.ticks, *tick)
+	t.mu.Unlock()
+	t.rec.setClock(tick.Time)
+	return t.strategy.OnTick(tick)
+}
+
+func (t *parityTap) OnBar(bar *mt5.Candle) error {
+	return t.strategy.OnBar(bar)
+}
+
+func (t *parityTap) OnTradeEvent(event *pb.OnTradeData) error {
+	return t.strategy.OnTradeEvent(event)
+}
+
+func (t *parityTap) OnTimer(now time.Time) error {
+	t.rec.setClock(now)
+	return t.strategy.OnTimer(now)
+}
+
+// ParityHarness runs a strategy in dry-run and compares it with a replay.
+type ParityHarness struct {
+	*BaseOrchestrator
+	sugar   *mt5.MT5Sugar
+	factory ParityStrategyFactory
+	config  ParityConfig
+
+	live    *StrategyRunner
+	liveRec *DecisionRecorder
+	tap     *parityTap
+	from    time.Time
+	to      time.Time
+}
+
+// NewParityHarness creates a harness for the strategies built by factory.
+func NewParityHarness(name string, sugar *mt5.MT5Sugar, factory ParityStrategyFactory, config ParityConfig) *ParityHarness {
+	if config.TimeTolerance <= 0 {
+		config.TimeTolerance = time.Second
+	}
+	return &ParityHarness{
+		BaseOrchestrator: NewBaseOrchestrator(name),
+		sugar:            sugar,
+		factory:          factory,
+		config:           config,
+	}
+}
+
+// Start begins the dry-run on live data.
+func (h *ParityHarness) Start() error {
+	if h.IsRunning() {
+		return fmt.Errorf("parity harness already running")
+	}
+	if h.factory == nil {
+		return fmt.Errorf("strategy factory is nil")
+	}
+
+	h.liveRec = &DecisionRecorder{}
+	h.tap = &parityTap{strategy: h.factory(h.liveRec), rec: h.liveRec}
+	h.live = NewStrategyRunner(h.GetStatus().Name+" (dry-run)", h.sugar, h.tap, h.config.Runner)
+	if err := h.live.Start(); err != nil {
+		return err
+	}
+	h.from, h.to = time.Now(), time.Time{}
+	h.MarkStarted()
+	return nil
+}
+
+// Stop ends the dry-run; Compare can be called afterwards.
+func (h *ParityHarness) Stop() error {
+	if !h.IsRunning() {
+		return fmt.Errorf("parity harness not running")
+	}
+	err := h.live.Stop()
+	h.to = time.Now()
+	h.MarkStopped()
+	return err
+}
+
+// GetMetrics returns the metrics of the dry-run runner.
+func (h *ParityHarness) GetMetrics() OrchestratorMetrics {
+	if h.live == nil {
+		return h.BaseOrchestrator.GetMetrics()
+	}
+	return h.live.GetMetrics()
+}
+
+// replayTicks returns the ticks to replay, oldest first.
+func (h *ParityHarness) replayTicks() ([]mt5.SymbolTick, error) {
+	if h.config.Ticks == nil {
+		h.tap.mu.Lock()
+		defer h.tap.mu.Unlock()
+		return append([]mt5.SymbolTick(nil), h.tap.ticks...), nil
+	}
+
+	to := h.to
+	if to.IsZero() {
+		to = time.Now()
+	}
+	var ticks []mt5.SymbolTick
+	for _, symbol := range h.config.Runner.Symbols {
+		symbolTicks, err := h.config.Ticks.Load(symbol, h.from, to)
+		if err != nil {
+			return nil, err
+		}
+		ticks = append(ticks, symbolTicks...)
+	}
+	sort.SliceStable(ticks, func(i, j int) bool { return ticks[i].TimeMS < ticks[j].TimeMS })
+	return ticks, nil
+}
+
+// Compare replays the dry-run period into a fresh strategy and diffs the
+// decisions of both runs. May be called while the dry-run is running; the
+// replay then covers the ticks received so far.
+//
+// RETURNS:
+//   - *ParityReport, or error if no dry-run was started or replay ticks could not be loaded
+func (h *ParityHarness) Compare() (*ParityReport, error) {
+	if h.tap == nil {
+		return nil, fmt.Errorf("parity harness: no dry-run to compare")
+	}
+	ticks, err := h.replayTicks()
+	if err != nil {
+		return nil, fmt.Errorf("parity harness: %w", err)
+	}
+
+	replayRec := &DecisionRecorder{}
+	errs := ReplayStrategy(h.factory(replayRec), ticks, h.config.Runner, replayRec.setClock)
+
+	report := DiffDecisions(h.liveRec.Decisions(), replayRec.Decisions(), h.config.TimeTolerance, h.config.PriceTolerance)
+	report.Ticks = len(ticks)
+	report.ReplayErrors = errs
+	return report, nil
+}
+
+// ReplayStrategy feeds recorded ticks into a strategy the way StrategyRunner
+// delivers live ticks: OnTick, then the bar the tick closes. OnTimer fires
+// every TimerInterval of tick time, before the first tick past each interval.
+// onClock (optional) is called with the event time before every callback.
+//
+// RETURNS:
+//   - Number of callbacks that returned an error
+func ReplayStrategy(strategy Strategy, ticks []mt5.SymbolTick, config StrategyRunnerConfig, onClock func(time.Time)) int {
+	if onClock == nil {
+		onClock = func(time.Time) {}
+	}
+	builders := make(map[string]*mt5.CandleBuilder)
+	barBuilders := make(map[string]mt5.BarBuilder)
+	for _, symbol := range config.Symbols {
+		if config.BarFactory != nil {
+			barBuilders[symbol] = config.BarFactory(symbol)
+		} else if config.BarTimeframe > 0 {
+			builders[symbol] = mt5.NewCandleBuilder(symbol, config.BarTimeframe)
+		}
+	}
+
+	errs := 0
+	count := func(err error) {
+		if err != nil {
+			errs++
+		}
+	}
+
+	var nextTimer time.Time
+	for i := range ticks {
+		tick := &ticks[i]
+		if config.TimerInterval > 0 {
+			if nextTimer.IsZero() {
+				nextTimer = tick.Time.Add(config.TimerInterval)
+			}
+			for !tick.Time.Before(nextTimer) {
+				onClock(nextTimer)
+				count(strategy.OnTimer(nextTimer))
+				nextTimer = nextTimer.Add(config.TimerInterval)
+			}
+		}
+
+		onClock(tick.Time)
+		count(strategy.OnTick(tick))
+		if builder, ok := barBuilders[tick.Symbol]; ok {
+			for _, bar := range builder.Add(tick) {
+				count(strategy.OnBar(&bar))
+			}
+		} else if builder, ok := builders[tick.Symbol]; ok {
+			if bar := builder.Add(tick); bar != nil {
+				count(strategy.OnBar(bar))
+			}
+		}
+	}
+	return errs
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// DIFF
+// ══════════════════════════════════════════════════════════════════════════════
+
+// DecisionPair is a live decision and its replay counterpart.
+type DecisionPair struct {
+	Live   Decision
+	Replay Decision
+	Detail string // What differs (Mismatched only)
+}
+
+// ParityReport is the diff of a dry-run and its replay.
+type ParityReport struct {
+	Ticks        int // Ticks replayed
+	ReplayErrors int // Strategy callbacks that failed during replay
+	Live         int // Decisions in the dry-run
+	Replay       int // Decisions in the replay
+	Matched      []DecisionPair
+	Mismatched   []DecisionPair
+	LiveOnly     []Decision
+	ReplayOnly   []Decision
+}
+
+// OK reports whether both runs made the same decisions.
+func (r *ParityReport) OK() bool {
+	return len(r.Mismatched) == 0 && len(r.LiveOnly) == 0 && len(r.ReplayOnly) == 0
+}
+
+func (r *ParityReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "parity: %d live / %d replay decisions over %d ticks: %d matched, %d mismatched, %d live only, %d replay only",
+		r.Live, r.Replay, r.Ticks, len(r.Matched), len(r.Mismatched), len(r.LiveOnly), len(r.ReplayOnly))
+	for _, p := range r.Mismatched {
+		fmt.Fprintf(&b, "\n  ≠ %s\n    replay %s: %s", p.Live, p.Replay, p.Detail)
+	}
+	for _, d := range r.LiveOnly {
+		fmt.Fprintf(&b, "\n  live only   %s", d)
+	}
+	for _, d := range r.ReplayOnly {
+		fmt.Fprintf(&b, "\n  replay only %s", d)
+	}
+	return b.String()
+}
+
+// DiffDecisions pairs each live decision with the closest unmatched replay
+// decision of the same symbol and action within timeTolerance.
+//
+// PARAMETERS:
+//   - live, replay: Decisions of both runs
+//   - timeTolerance: Max time difference of a pair
+//   - priceTolerance: Max price difference of a pair (0 = prices not compared)
+//
+// RETURNS:
+//   - *ParityReport
+func DiffDecisions(live, replay []Decision, timeTolerance time.Duration, priceTolerance float64) *ParityReport {
+	report := &ParityReport{Live: len(live), Replay: len(replay)}
+	used := make([]bool, len(replay))
+
+	for _, l := range live {
+		best := -1
+		var bestDiff time.Duration
+		for j, r := range replay {
+			if used[j] || r.Symbol != l.Symbol || r.Action != l.Action {
+				continue
+			}
+			diff := r.Time.Sub(l.Time)
+			if diff < 0 {
+				diff = -diff
+			}
+			if diff <= timeTolerance && (best < 0 || diff < bestDiff) {
+				best, bestDiff = j, diff
+			}
+		}
+		if best < 0 {
+			report.LiveOnly = append(report.LiveOnly, l)
+			continue
+		}
+		used[best] = true
+		pair := DecisionPair{Live: l, Replay: replay[best]}
+
+		var details []string
+		if math.Abs(l.Volume-pair.Replay.Volume) > 1e-9 {
+			details = append(details, fmt.Sprintf("volume %.2f vs %.2f", l.Volume, pair.Replay.Volume))
+		}
+		if priceTolerance > 0 && math.Abs(l.Price-pair.Replay.Price) > priceTolerance {
+			details = append(details, fmt.Sprintf("price %g vs %g", l.Price, pair.Replay.Price))
+		}
+		if len(details) > 0 {
+			pair.Detail = strings.Join(details, ", ")
+			report.Mismatched = append(report.Mismatched, pair)
+		} else {
+			report.Matched = append(report.Matched, pair)
+		}
+	}
+	for j, r := range replay {
+		if !used[j] {
+			report.ReplayOnly = append(report.ReplayOnly, r)
+		}
+	}
+	return report
+}
@@ -0,0 +1,302 @@
+package orchestrators
+
+/*══════════════════════════════════════════════════════════════════════════════
+ ORCHESTRATOR: PendingOrderSweeper (Stale Pending Order Cleanup)
+
+ PURPOSE:
+   Background janitor for pending orders. Limit/stop orders that were placed
+   long ago, or whose price is now far away from (or too close to) the
+   market, are cancelled automatically instead of lingering on the book.
+
+ RULES (per symbol, with a default for all others):
+   • MaxAge            - cancel orders older than this
+   • MaxDistancePoints - cancel orders farther than this from current price
+   • MinDistancePoints - cancel orders closer than this to current price
+
+ METRICS:
+   • Cancellations by reason ("age", "too far", "too close") and by symbol
+   • Last 100 cancelled orders with details (GetSweptOrders)
+
+ PROGRAMMATIC USAGE:
+   config := orchestrators.DefaultPendingSweeperConfig()
+   config.SymbolRules["XAUUSD"] = orchestrators.SweepRule{MaxAge: time.Hour, MaxDistancePoints: 5000}
+
+   sweeper := orchestrators.NewPendingOrderSweeper(sugar, config)
+   sweeper.Start()
+   defer sweeper.Stop()
+══════════════════════════════════════════════════════════════════════════════*/
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	mt5 "github.com/MetaRPC/GoMT5/examples/mt5"
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CONFIGURATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// SweepRule defines when a pending order is considered stale. Zero fields are disabled.
+type SweepRule struct {
+	MaxAge            time.Duration // Cancel orders older than this
+	MaxDistancePoints float64       // Cancel orders farther than this from current price
+	MinDistancePoints float64       // Cancel orders closer than this to current price
+}
+
+// PendingSweeperConfig holds sweeper parameters.
+type PendingSweeperConfig struct {
+	DefaultRule   SweepRule            // Rule for symbols without their own rule
+	SymbolRules   map[string]SweepRule // Per-symbol overrides
+	Magic         int64                // Only sweep orders with this magic (0 = all orders)
+	CheckInterval time.Duration        // How often to sweep
+}
+
+// DefaultPendingSweeperConfig returns a config that cancels orders older than one day.
+func DefaultPendingSweeperConfig() PendingSweeperConfig {
+	return PendingSweeperConfig{
+		DefaultRule: SweepRule{
+			MaxAge: 24 * time.Hour,
+		},
+		SymbolRules:   make(map[string]SweepRule),
+		CheckInterval: 30 * time.Second,
+	}
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// PENDING ORDER SWEEPER IMPLEMENTATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// SweptOrder records one cancelled pending order.
+type SweptOrder struct {
+	Ticket    uint64
+	Symbol    string
+	Reason    string
+	Price     float64
+	Market    float64
+	Age       time.Duration
+	Cancelled time.Time
+}
+
+// PendingOrderSweeper cancels stale pending orders in the background.
+type PendingOrderSweeper struct {
+	*BaseOrchestrator
+	sugar  *mt5.MT5Sugar
+	config PendingSweeperConfig
+
+	mu          sync.RWMutex
+	points      map[string]float64
+	byReason    map[string]int
+	bySymbol    map[string]int
+	sweptOrders []SweptOrder
+}
+
+// NewPendingOrderSweeper creates a new sweeper.
+func NewPendingOrderSweeper(sugar *mt5.MT5Sugar, config PendingSweeperConfig) *PendingOrderSweeper {
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = 30 * time.Second
+	}
+	if config.SymbolRules == nil {
+		config.SymbolRules = make(map[string]SweepRule)
+	}
+	return &PendingOrderSweeper{
+		BaseOrchestrator: NewBaseOrchestrator("Pending Order Sweeper"),
+		sugar:            sugar,
+		config:           config,
+		points:           make(map[string]float64),
+		byReason:         make(map[string]int),
+		bySymbol:         make(map[string]int),
+	}
+}
+
+// Start begins sweeping.
+func (p *PendingOrderSweeper) Start() error {
+	if p.IsRunning() {
+		return fmt.Errorf("pending order sweeper already running")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.SetContext(ctx, cancel)
+
+	p.MarkStarted()
+
+	p.GoSafe(p.monitorLoop)
+
+	return nil
+}
+
+// Stop stops sweeping.
+func (p *PendingOrderSweeper) Stop() error {
+	if !p.IsRunning() {
+		return fmt.Errorf("pending order sweeper not running")
+	}
+
+	p.CancelContext()
+	p.MarkStopped()
+
+	return nil
+}
+
+// monitorLoop sweeps on every tick of CheckInterval.
+func (p *PendingOrderSweeper) monitorLoop() {
+	ticker := time.NewTicker(p.config.CheckInterval)
+	defer ticker.Stop()
+
+	p.sweep()
+
+	for {
+		select {
+		case <-p.GetContext().Done():
+			return
+		case <-ticker.C:
+			p.sweep()
+		}
+	}
+}
+
+// sweep checks every pending order against its rule.
+func (p *PendingOrderSweeper) sweep() {
+	ctx, cancel := context.WithTimeout(p.GetContext(), 30*time.Second)
+	defer cancel()
+
+	service := p.sugar.GetService()
+	data, err := service.GetOpenedOrders(ctx, pb.BMT5_ENUM_OPENED_ORDER_SORT_TYPE_BMT5_OPENED_ORDER_SORT_BY_OPEN_TIME_ASC)
+	if err != nil {
+		p.IncrementError(fmt.Sprintf("failed to get orders: %v", err))
+		return
+	}
+
+	now := time.Now()
+	for _, order := range data.OpenedOrders {
+		if p.config.Magic != 0 && order.MagicNumber != p.config.Magic {
+			continue
+		}
+
+		reason := p.staleReason(order, now)
+		if reason == "" {
+			continue
+		}
+
+		retCode, err := service.CloseOrder(ctx, &pb.OrderCloseRequest{Ticket: order.Ticket})
+		if err != nil || retCode != 10009 {
+			p.IncrementError(fmt.Sprintf("failed to cancel #%d: code %d, err %v", order.Ticket, retCode, err))
+			continue
+		}
+
+		p.recordSweep(order, reason, now)
+	}
+}
+
+// staleReason returns why an order should be cancelled, or "" to keep it.
+func (p *PendingOrderSweeper) staleReason(order *pb.OpenedOrderInfo, now time.Time) string {
+	rule, ok := p.config.SymbolRules[order.Symbol]
+	if !ok {
+		rule = p.config.DefaultRule
+	}
+
+	if rule.MaxAge > 0 && order.TimeSetup != nil && now.Sub(order.TimeSetup.AsTime()) > rule.MaxAge {
+		return "age"
+	}
+
+	if rule.MaxDistancePoints <= 0 && rule.MinDistancePoints <= 0 {
+		return ""
+	}
+
+	point := p.symbolPoint(order.Symbol)
+	if point <= 0 || order.PriceCurrent <= 0 {
+		return ""
+	}
+
+	distance := math.Abs(order.PriceOpen-order.PriceCurrent) / point
+	if rule.MaxDistancePoints > 0 && distance > rule.MaxDistancePoints {
+		return "too far"
+	}
+	if rule.MinDistancePoints > 0 && distance < rule.MinDistancePoints {
+		return "too close"
+	}
+	return ""
+}
+
+// symbolPoint returns the cached point size for a symbol.
+func (p *PendingOrderSweeper) symbolPoint(symbol string) float64 {
+	p.mu.RLock()
+	point, ok := p.points[symbol]
+	p.mu.RUnlock()
+	if ok {
+		return point
+	}
+
+	info, err := p.sugar.GetSymbolInfo(symbol)
+	if err != nil {
+		return 0
+	}
+
+	p.mu.Lock()
+	p.points[symbol] = info.Point
+	p.mu.Unlock()
+	return info.Point
+}
+
+// recordSweep updates cancellation metrics.
+func (p *PendingOrderSweeper) recordSweep(order *pb.OpenedOrderInfo, reason string, now time.Time) {
+	swept := SweptOrder{
+		Ticket:    order.Ticket,
+		Symbol:    order.Symbol,
+		Reason:    reason,
+		Price:     order.PriceOpen,
+		Market:    order.PriceCurrent,
+		Cancelled: now,
+	}
+	if order.TimeSetup != nil {
+		swept.Age = now.Sub(order.TimeSetup.AsTime())
+	}
+
+	p.mu.Lock()
+	p.byReason[reason]++
+	p.bySymbol[order.Symbol]++
+	p.sweptOrders = append(p.sweptOrders, swept)
+	if len(p.sweptOrders) > 100 {
+		p.sweptOrders = p.sweptOrders[len(p.sweptOrders)-100:]
+	}
+	p.mu.Unlock()
+
+	p.UpdateMetrics(func(m *OrchestratorMetrics) {
+		m.OperationsTotal++
+		m.LastOperation = fmt.Sprintf("Cancelled #%d %s (%s)", order.Ticket, order.Symbol, reason)
+	})
+}
+
+// GetCancellationsByReason returns cancellation counts per reason.
+func (p *PendingOrderSweeper) GetCancellationsByReason() map[string]int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	out := make(map[string]int, len(p.byReason))
+	for k, v := range p.byReason {
+		out[k] = v
+	}
+	return out
+}
+
+// GetCancellationsBySymbol returns cancellation counts per symbol.
+func (p *PendingOrderSweeper) GetCancellationsBySymbol() map[string]int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	out := make(map[string]int, len(p.bySymbol))
+	for k, v := range p.bySymbol {
+		out[k] = v
+	}
+	return out
+}
+
+// GetSweptOrders returns the last 100 cancelled orders.
+func (p *PendingOrderSweeper) GetSweptOrders() []SweptOrder {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return append([]SweptOrder(nil), p.sweptOrders...)
+}
@@ -0,0 +1,195 @@
+package orchestrators
+
+/*══════════════════════════════════════════════════════════════════════════════
+ TOOL: PnLAttributor (Per-Strategy P&L Attribution)
+
+ PURPOSE:
+   Splits account P&L by strategy when several orchestrators trade the same
+   account, so a combined total no longer hides which one made or lost money.
+   Closed positions (realized) and open positions (floating) are assigned to
+   the first rule they match; the rest is reported as "Unattributed"
+   (manual trades, other EAs).
+
+ MATCHING (AttributionRule, any set criterion matches):
+   • Magic          - position magic number
+   • CommentPrefix  - position comment starts with this tag
+   • Owner          - orchestrator that recorded the ticket with Own()
+                      (every BaseOrchestrator does for the orders it places)
+
+ Several rules may share a Name; their P&L is summed, so the instances of one
+ strategy across restarts or cycles are reported together.
+
+ PROGRAMMATIC USAGE:
+   attributor := orchestrators.NewPnLAttributor(sugar, time.Now())
+   attributor.Track(gridTrader)
+   attributor.AddRule(orchestrators.AttributionRule{Name: "Manual hedge", Magic: 777})
+
+   report, err := attributor.Calculate()
+   for _, s := range report {
+       fmt.Printf("%-20s realized %.2f floating %.2f\n", s.Name, s.Realized, s.Floating)
+   }
+══════════════════════════════════════════════════════════════════════════════*/
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	mt5 "github.com/MetaRPC/GoMT5/examples/mt5"
+)
+
+// UnattributedName is the report entry for positions no rule matches.
+const UnattributedName = "Unattributed"
+
+// TicketOwner is implemented by orchestrators that record the tickets they open.
+type TicketOwner interface {
+	OwnsTicket(ticket uint64) bool
+}
+
+// AttributionRule assigns positions to a strategy.
+type AttributionRule struct {
+	Name          string      // Strategy name in the report
+	Magic         int64       // Match positions with this magic (0 = not used)
+	CommentPrefix string      // Match positions whose comment starts with this ("" = not used)
+	Owner         TicketOwner // Match tickets recorded by this orchestrator (nil = not used)
+}
+
+// matches reports whether a position belongs to the rule.
+func (r AttributionRule) matches(ticket uint64, magic int64, comment string) bool {
+	if r.Magic != 0 && magic == r.Magic {
+		return true
+	}
+	if r.CommentPrefix != "" && strings.HasPrefix(comment, r.CommentPrefix) {
+		return true
+	}
+	return r.Owner != nil && r.Owner.OwnsTicket(ticket)
+}
+
+// StrategyPnL is the attributed P&L of one strategy.
+type StrategyPnL struct {
+	Name          string  `json:"name"`
+	Realized      float64 `json:"realized"` // Net P/L of positions closed since the attributor's start
+	Fees          float64 `json:"fees"`     // Commission + swap + fees included in Realized (negative = cost)
+	Floating      float64 `json:"floating"` // Profit + swap + commission of open positions
+	ClosedTrades  int     `json:"closed_trades"`
+	OpenPositions int     `json:"open_positions"`
+	Volume        float64 `json:"volume"` // Lots closed
+}
+
+// Total returns realized plus floating P&L.
+func (s StrategyPnL) Total() float64 {
+	return s.Realized + s.Floating
+}
+
+// PnLAttributor splits account P&L by strategy.
+type PnLAttributor struct {
+	sugar *mt5.MT5Sugar
+	since time.Time
+
+	mu    sync.RWMutex
+	rules []AttributionRule
+}
+
+// NewPnLAttributor creates an attributor counting positions closed after since.
+func NewPnLAttributor(sugar *mt5.MT5Sugar, since time.Time, rules ...AttributionRule) *PnLAttributor {
+	return &PnLAttributor{
+		sugar: sugar,
+		since: since,
+		rules: append([]AttributionRule(nil), rules...),
+	}
+}
+
+// AddRule appends a rule; earlier rules win when several match.
+func (a *PnLAttributor) AddRule(rule AttributionRule) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.rules = append(a.rules, rule)
+}
+
+// Track adds an ownership rule for an orchestrator, named after its status.
+func (a *PnLAttributor) Track(o Orchestrator) {
+	owner, ok := o.(TicketOwner)
+	if !ok {
+		return
+	}
+	a.AddRule(AttributionRule{Name: o.GetStatus().Name, Owner: owner})
+}
+
+// Calculate attributes realized and floating P&L.
+//
+// Returns:
+//   - One entry per strategy name in rule order, plus UnattributedName if
+//     any position matched no rule
+func (a *PnLAttributor) Calculate() ([]StrategyPnL, error) {
+	a.mu.RLock()
+	rules := append([]AttributionRule(nil), a.rules...)
+	a.mu.RUnlock()
+
+	var report []StrategyPnL
+	index := make(map[string]int)
+	entry := func(name string) *StrategyPnL {
+		i, ok := index[name]
+		if !ok {
+			i = len(report)
+			index[name] = i
+			report = append(report, StrategyPnL{Name: name})
+		}
+		return &report[i]
+	}
+	for _, rule := range rules {
+		entry(rule.Name)
+	}
+
+	classify := func(ticket uint64, magic int64, comment string) string {
+		for _, rule := range rules {
+			if rule.matches(ticket, magic, comment) {
+				return rule.Name
+			}
+		}
+		return UnattributedName
+	}
+
+	deals, err := a.sugar.GetDealsDateRange(a.since, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("P&L attribution: %w", err)
+	}
+	for _, deal := range deals {
+		if deal.CloseTime == nil || deal.CloseTime.AsTime().Before(a.since) {
+			continue
+		}
+		fees := deal.Commission + deal.Swap + deal.Fee
+		s := entry(classify(deal.PositionTicket, deal.Magic, deal.Comment))
+		s.Realized += deal.Profit + fees
+		s.Fees += fees
+		s.Volume += deal.Volume
+		s.ClosedTrades++
+	}
+
+	positions, err := a.sugar.GetOpenPositions()
+	if err != nil {
+		return nil, fmt.Errorf("P&L attribution: %w", err)
+	}
+	for _, pos := range positions {
+		s := entry(classify(pos.Ticket, pos.MagicNumber, pos.Comment))
+		s.Floating += pos.Profit + pos.Swap + pos.PositionCommission
+		s.OpenPositions++
+	}
+
+	return report, nil
+}
+
+// FormatAttribution renders a report as an aligned text table.
+func FormatAttribution(report []StrategyPnL) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "  %-24s %10s %10s %10s %7s %5s\n", "Strategy", "Realized", "Floating", "Total", "Closed", "Open")
+	var realized, floating float64
+	for _, s := range report {
+		fmt.Fprintf(&b, "  %-24s %10.2f %10.2f %10.2f %7d %5d\n",
+			s.Name, s.Realized, s.Floating, s.Total(), s.ClosedTrades, s.OpenPositions)
+		realized += s.Realized
+		floating += s.Floating
+	}
+	fmt.Fprintf(&b, "  %-24s %10.2f %10.2f %10.2f\n", "TOTAL", realized, floating, realized+floating)
+	return b.String()
+}
@@ -0,0 +1,217 @@
+package orchestrators
+
+/*══════════════════════════════════════════════════════════════════════════════
+ RUN SUMMARY: One JSON File per Run, for Comparing Runs
+
+ PURPOSE:
+   At the end of an orchestrator or preset run, records what was run and how
+   it went: the config used, start/end time and duration, trades and P&L,
+   errors and risk events. Each run is written to its own timestamped file
+   (e.g. runs/grid-trader-20250114-093000.json), so two runs with different
+   settings can be diffed or loaded into a notebook.
+
+ CONTENTS:
+   • name, started_at, ended_at, duration_sec, stop_reason, crashed
+   • config        - the config struct as JSON (unexported fields omitted)
+   • operations, errors, last_error
+   • trades, wins, losses, win_rate, gross_pnl, fees, net_profit,
+     floating_pnl, total_pnl, max_drawdown, max_drawdown_pct, by_symbol
+   • risk_events   - from orchestrators with GetRiskEvents (Risk Manager)
+   • strategies    - P&L attribution (presets)
+   • orchestrators - per-orchestrator summaries (presets)
+   • extra         - runner-specific values (e.g. preset cycles, balances)
+
+ PROGRAMMATIC USAGE:
+   grid.Stop()
+   summary := orchestrators.NewRunSummary(grid, gridConfig)
+   path, err := summary.WriteFile(orchestrators.DefaultRunSummaryDir)
+══════════════════════════════════════════════════════════════════════════════*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultRunSummaryDir is the directory run summaries are written to.
+const DefaultRunSummaryDir = "runs"
+
+// RunSummary is the structured outcome of one orchestrator or preset run.
+type RunSummary struct {
+	Name        string          `json:"name"`
+	StartedAt   time.Time       `json:"started_at"`
+	EndedAt     time.Time       `json:"ended_at"`
+	DurationSec float64         `json:"duration_sec"`
+	StopReason  string          `json:"stop_reason,omitempty"`
+	Crashed     bool            `json:"crashed,omitempty"`
+	Config      json.RawMessage `json:"config,omitempty"`
+
+	Operations int    `json:"operations"`
+	Errors     int    `json:"errors"`
+	LastError  string `json:"last_error,omitempty"`
+
+	Trades         int                         `json:"trades"`
+	Wins           int                         `json:"wins"`
+	Losses         int                         `json:"losses"`
+	WinRate        float64                     `json:"win_rate"`
+	GrossPnL       float64                     `json:"gross_pnl"`
+	Fees           float64                     `json:"fees"`
+	NetProfit      float64                     `json:"net_profit"`
+	FloatingPnL    float64                     `json:"floating_pnl"`
+	TotalPnL       float64                     `json:"total_pnl"`
+	MaxDrawdown    float64                     `json:"max_drawdown"`
+	MaxDrawdownPct float64                     `json:"max_drawdown_pct"`
+	BySymbol       map[string]RunSymbolSummary `json:"by_symbol,omitempty"`
+
+	RiskEvents    []RiskEvent    `json:"risk_events,omitempty"`
+	Strategies    []StrategyPnL  `json:"strategies,omitempty"`
+	Orchestrators []RunSummary   `json:"orchestrators,omitempty"`
+	Extra         map[string]any `json:"extra,omitempty"` // Runner-specific values (e.g. cycles, balances)
+}
+
+// RunSymbolSummary is the per-symbol part of a RunSummary.
+type RunSymbolSummary struct {
+	Trades      int     `json:"trades"`
+	Wins        int     `json:"wins"`
+	Losses      int     `json:"losses"`
+	NetProfit   float64 `json:"net_profit"`
+	Fees        float64 `json:"fees"`
+	FloatingPnL float64 `json:"floating_pnl"`
+}
+
+// NewRunSummary captures the final state of orch. config is the config the
+// orchestrator was created with (nil = omitted). Call it after Stop.
+func NewRunSummary(orch Orchestrator, config any) RunSummary {
+	status := orch.GetStatus()
+	metrics := orch.GetMetrics()
+
+	s := RunSummary{
+		Name:           status.Name,
+		StartedAt:      status.StartTime,
+		EndedAt:        time.Now(),
+		StopReason:     status.StopReason,
+		Crashed:        status.Crashed,
+		Operations:     status.SuccessCount + status.ErrorCount,
+		Errors:         status.ErrorCount,
+		LastError:      status.LastError,
+		Trades:         metrics.TotalTrades,
+		Wins:           metrics.WinningTrades,
+		Losses:         metrics.LosingTrades,
+		WinRate:        metrics.WinRate,
+		GrossPnL:       metrics.GrossPnL,
+		Fees:           metrics.Fees,
+		NetProfit:      metrics.NetProfit,
+		FloatingPnL:    metrics.FloatingPnL,
+		TotalPnL:       metrics.TotalPnL,
+		MaxDrawdown:    metrics.MaxDrawdown,
+		MaxDrawdownPct: metrics.MaxDrawdownPercent,
+	}
+	if !s.StartedAt.IsZero() {
+		s.DurationSec = s.EndedAt.Sub(s.StartedAt).Seconds()
+	}
+	if len(metrics.BySymbol) > 0 {
+		s.BySymbol = make(map[string]RunSymbolSummary, len(metrics.BySymbol))
+		for symbol, sym := range metrics.BySymbol {
+			s.BySymbol[symbol] = RunSymbolSummary{
+				Trades:      sym.Trades,
+				Wins:        sym.Wins,
+				Losses:      sym.Losses,
+				NetProfit:   sym.NetProfit,
+				Fees:        sym.Fees,
+				FloatingPnL: sym.FloatingPnL,
+			}
+		}
+	}
+	if r, ok := orch.(interface{ GetRiskEvents() []RiskEvent }); ok {
+		s.RiskEvents = append([]RiskEvent(nil), r.GetRiskEvents()...)
+	}
+	s.SetConfig(config)
+	return s
+}
+
+// SetConfig stores config as JSON. Values that cannot be encoded (funcs,
+// channels) are stored as their %+v text instead.
+func (s *RunSummary) SetConfig(config any) {
+	if config == nil {
+		s.Config = nil
+		return
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprintf("%+v", config))
+	}
+	s.Config = data
+}
+
+// Add folds a child orchestrator's results into a preset summary: counters
+// and P&L are summed, risk events appended and child kept in Orchestrators.
+// WinRate and drawdown are not combined; they stay per child.
+func (s *RunSummary) Add(child RunSummary) {
+	s.Operations += child.Operations
+	s.Errors += child.Errors
+	if child.LastError != "" {
+		s.LastError = child.LastError
+	}
+	s.Trades += child.Trades
+	s.Wins += child.Wins
+	s.Losses += child.Losses
+	s.GrossPnL += child.GrossPnL
+	s.Fees += child.Fees
+	s.NetProfit += child.NetProfit
+	s.FloatingPnL += child.FloatingPnL
+	s.TotalPnL += child.TotalPnL
+	s.RiskEvents = append(s.RiskEvents, child.RiskEvents...)
+	s.Orchestrators = append(s.Orchestrators, child)
+	if s.Trades > 0 {
+		s.WinRate = float64(s.Wins) / float64(s.Trades) * 100
+	}
+}
+
+// FileName returns the summary's file name: <name>-<start time>.json,
+// e.g. "grid-trader-20250114-093000.json".
+func (s RunSummary) FileName() string {
+	started := s.StartedAt
+	if started.IsZero() {
+		started = s.EndedAt
+	}
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		}
+		return '-'
+	}, strings.TrimSpace(s.Name))
+	slug = strings.Trim(slug, "-")
+	for strings.Contains(slug, "--") {
+		slug = strings.ReplaceAll(slug, "--", "-")
+	}
+	if slug == "" {
+		slug = "run"
+	}
+	return slug + "-" + started.Format("20060102-150405") + ".json"
+}
+
+// WriteFile writes the summary as indented JSON into dir (created if
+// missing) and returns the file path.
+func (s RunSummary) WriteFile(dir string) (string, error) {
+	if dir == "" {
+		dir = DefaultRunSummaryDir
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode run summary: %w", err)
+	}
+	path := filepath.Join(dir, s.FileName())
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write run summary: %w", err)
+	}
+	return path, nil
+}
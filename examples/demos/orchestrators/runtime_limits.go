@@ -0,0 +1,105 @@
+package orchestrators
+
+/*══════════════════════════════════════════════════════════════════════════════
+ RUNTIME LIMITS: Automatic Stop on Time, Trade Budget or Loss
+
+ PURPOSE:
+   Bounds an unattended run. Every orchestrator config has a Limits field;
+   once a limit is reached the orchestrator stops itself (its own Stop, so
+   pending orders are cleaned up as usual) and records why in
+   OrchestratorStatus.StopReason.
+
+ LIMITS (zero = no limit):
+   • MaxRuntime - wall time since Start
+   • MaxTrades  - successful trade operations (status SuccessCount: orders
+                  placed, scale-ins, SL moves, rebalancing trades)
+   • MaxLoss    - TotalPnL (realized + floating) at or below -MaxLoss
+
+ PROGRAMMATIC USAGE:
+   grid := orchestrators.NewGridTrader(sugar, orchestrators.GridTraderConfig{
+       ...
+       Limits: orchestrators.RuntimeLimits{MaxRuntime: 2 * time.Hour, MaxLoss: 250},
+   })
+   grid.Start()
+   <-grid.GetContext().Done()
+   fmt.Println("stopped:", grid.GetStatus().StopReason)
+
+   // Inside a custom orchestrator's Start, after MarkStarted:
+   o.EnforceLimits(o.config.Limits, o.Stop)
+══════════════════════════════════════════════════════════════════════════════*/
+
+import (
+	"fmt"
+	"time"
+)
+
+// limitCheckInterval is how often EnforceLimits evaluates the limits.
+const limitCheckInterval = time.Second
+
+// RuntimeLimits stops an orchestrator automatically. Zero fields are off.
+type RuntimeLimits struct {
+	MaxRuntime time.Duration // Stop after running this long
+	MaxTrades  int           // Stop after this many successful trade operations (SuccessCount)
+	MaxLoss    float64       // Stop once TotalPnL <= -MaxLoss (account currency)
+}
+
+// IsZero reports whether no limit is set.
+func (l RuntimeLimits) IsZero() bool {
+	return l.MaxRuntime <= 0 && l.MaxTrades <= 0 && l.MaxLoss <= 0
+}
+
+// Check returns why a limit is reached, or "" if none is.
+func (l RuntimeLimits) Check(status OrchestratorStatus, metrics OrchestratorMetrics) string {
+	switch {
+	case l.MaxRuntime > 0 && !status.StartTime.IsZero() && time.Since(status.StartTime) >= l.MaxRuntime:
+		return fmt.Sprintf("max runtime %s reached", l.MaxRuntime)
+	case l.MaxTrades > 0 && status.SuccessCount >= l.MaxTrades:
+		return fmt.Sprintf("trade budget of %d used", l.MaxTrades)
+	case l.MaxLoss > 0 && metrics.TotalPnL <= -l.MaxLoss:
+		return fmt.Sprintf("max loss %.2f reached (P/L %.2f)", l.MaxLoss, metrics.TotalPnL)
+	}
+	return ""
+}
+
+// EnforceLimits watches limits while the orchestrator runs and calls stop
+// once one is reached, with StopReason set beforehand. Call it at the end
+// of Start; it returns immediately and is a no-op for zero limits.
+func (b *BaseOrchestrator) EnforceLimits(limits RuntimeLimits, stop func() error) {
+	if limits.IsZero() {
+		return
+	}
+	ctx := b.GetContext()
+
+	b.GoSafe(func() {
+		ticker := time.NewTicker(limitCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if !b.IsRunning() {
+				return
+			}
+			reason := limits.Check(b.GetStatus(), b.GetMetrics())
+			if reason == "" {
+				continue
+			}
+
+			name := b.GetStatus().Name
+			fmt.Printf("\n⏹️  [%s] %s - stopping\n", name, reason)
+			b.UpdateStatus(func(s *OrchestratorStatus) {
+				s.StopReason = reason
+			})
+			b.UpdateMetrics(func(m *OrchestratorMetrics) {
+				m.LastOperation = "Stopped: " + reason
+			})
+			if err := stop(); err != nil {
+				b.IncrementError(fmt.Sprintf("limit stop failed: %v", err))
+			}
+			return
+		}
+	})
+}
@@ -0,0 +1,233 @@
+package orchestrators
+
+/*══════════════════════════════════════════════════════════════════════════════
+ SIGNAL GATE: Entry Deduplication and Cooldown
+
+ PURPOSE:
+   Stops a strategy from firing the same entry over and over. A signal that
+   stays true for several ticks (or an alert that keeps re-triggering) would
+   otherwise open a new position on every evaluation.
+
+ RULES (per orchestrator, with a default for all others):
+   • Cooldown       - no new entry on the same symbol/direction within this
+                      time of the last one
+   • AnyDirection   - cooldown covers the symbol in both directions
+   • BlockWhileOpen - no new entry while an equivalent position (same symbol
+                      and direction) is open
+   • OwnedOnly      - only positions opened by the orchestrator itself count
+                      as equivalent (recorded with Own)
+
+ Suppressed entries return a *SuppressedSignalError wrapping ErrSignalCooldown
+ or ErrSignalDuplicate; they are not failures and are counted separately.
+
+ PROGRAMMATIC USAGE:
+   gate := orchestrators.NewSignalGate(sugar, orchestrators.SignalGateConfig{
+       Cooldown: 5 * time.Minute, BlockWhileOpen: true, OwnedOnly: true,
+   })
+   gate.Configure("Alert Rule Engine", orchestrators.SignalGateConfig{Cooldown: time.Hour})
+
+   engine.SetSignalGate(gate)
+
+   // Inside an orchestrator:
+   if err := o.AllowEntry("EURUSD", true); err != nil {
+       return err // errors.Is(err, orchestrators.ErrSignalCooldown)
+   }
+   ticket, err := o.sugar.BuyMarket("EURUSD", 0.1)
+   if err == nil {
+       o.Own(ticket)
+       o.RecordEntry("EURUSD", true)
+   }
+══════════════════════════════════════════════════════════════════════════════*/
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	mt5 "github.com/MetaRPC/GoMT5/examples/mt5"
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+var (
+	ErrSignalCooldown  = errors.New("entry cooldown active")
+	ErrSignalDuplicate = errors.New("equivalent position already open")
+)
+
+// SuppressedSignalError explains why an entry was not allowed.
+type SuppressedSignalError struct {
+	Strategy string
+	Symbol   string
+	Buy      bool
+	Reason   error         // ErrSignalCooldown, ErrSignalDuplicate, or wraps ErrVolatilityPause / ErrVolatilityThrottle
+	Wait     time.Duration // Remaining cooldown, pause or entry interval
+	Ticket   uint64        // Equivalent open position (ErrSignalDuplicate only)
+}
+
+func (e *SuppressedSignalError) Error() string {
+	side := "sell"
+	if e.Buy {
+		side = "buy"
+	}
+	switch {
+	case e.Wait > 0:
+		return fmt.Sprintf("%s %s %s: %s (%s left)", e.Strategy, side, e.Symbol, e.Reason, e.Wait.Round(time.Second))
+	case e.Ticket > 0:
+		return fmt.Sprintf("%s %s %s: %s (#%d)", e.Strategy, side, e.Symbol, e.Reason, e.Ticket)
+	}
+	return fmt.Sprintf("%s %s %s: %s", e.Strategy, side, e.Symbol, e.Reason)
+}
+
+func (e *SuppressedSignalError) Unwrap() error {
+	return e.Reason
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CONFIGURATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// SignalGateConfig defines when an entry counts as a repeat. Zero values disable a rule.
+type SignalGateConfig struct {
+	Cooldown       time.Duration // Minimum time between entries on the same symbol/direction
+	AnyDirection   bool          // Cooldown applies to both directions of the symbol
+	BlockWhileOpen bool          // Refuse entries while an equivalent position is open
+	OwnedOnly      bool          // Equivalent positions must be owned by the orchestrator
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// SIGNAL GATE IMPLEMENTATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// SignalGate tracks entries per strategy and symbol. One gate can be shared
+// by several orchestrators; each is keyed by its name.
+type SignalGate struct {
+	sugar *mt5.MT5Sugar
+
+	mu         sync.Mutex
+	defaults   SignalGateConfig
+	configs    map[string]SignalGateConfig // Strategy name → config
+	last       map[string]time.Time        // "strategy|symbol|side" → last entry
+	suppressed map[string]int              // Strategy name → suppressed entries
+}
+
+// NewSignalGate creates a gate applying defaults to every strategy without
+// its own config.
+func NewSignalGate(sugar *mt5.MT5Sugar, defaults SignalGateConfig) *SignalGate {
+	return &SignalGate{
+		sugar:      sugar,
+		defaults:   defaults,
+		configs:    make(map[string]SignalGateConfig),
+		last:       make(map[string]time.Time),
+		suppressed: make(map[string]int),
+	}
+}
+
+// Configure sets the rules for one strategy (orchestrator name).
+func (g *SignalGate) Configure(strategy string, config SignalGateConfig) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.configs[strategy] = config
+}
+
+// config returns the rules of a strategy. Caller holds g.mu.
+func (g *SignalGate) config(strategy string) SignalGateConfig {
+	if config, ok := g.configs[strategy]; ok {
+		return config
+	}
+	return g.defaults
+}
+
+// entryKey identifies an entry for the cooldown.
+func entryKey(strategy, symbol string, buy, anyDirection bool) string {
+	side := "sell"
+	switch {
+	case anyDirection:
+		side = "*"
+	case buy:
+		side = "buy"
+	}
+	return strategy + "|" + symbol + "|" + side
+}
+
+// Allow checks whether strategy may enter symbol in the given direction.
+// owns reports tickets opened by the strategy (used with OwnedOnly).
+// Returns nil if allowed, *SuppressedSignalError if suppressed, or an error
+// if open positions could not be read.
+func (g *SignalGate) Allow(strategy, symbol string, buy bool, owns func(ticket uint64) bool) error {
+	g.mu.Lock()
+	config := g.config(strategy)
+	last, seen := g.last[entryKey(strategy, symbol, buy, config.AnyDirection)]
+	g.mu.Unlock()
+
+	if config.Cooldown > 0 && seen {
+		if wait := config.Cooldown - time.Since(last); wait > 0 {
+			return g.suppress(&SuppressedSignalError{
+				Strategy: strategy, Symbol: symbol, Buy: buy, Reason: ErrSignalCooldown, Wait: wait,
+			})
+		}
+	}
+
+	if config.BlockWhileOpen {
+		positions, err := g.sugar.GetPositionsBySymbol(symbol)
+		if err != nil {
+			return fmt.Errorf("signal gate: %w", err)
+		}
+		for _, pos := range positions {
+			if (pos.Type == pb.BMT5_ENUM_POSITION_TYPE_BMT5_POSITION_TYPE_BUY) != buy {
+				continue
+			}
+			if config.OwnedOnly && (owns == nil || !owns(pos.Ticket)) {
+				continue
+			}
+			return g.suppress(&SuppressedSignalError{
+				Strategy: strategy, Symbol: symbol, Buy: buy, Reason: ErrSignalDuplicate, Ticket: pos.Ticket,
+			})
+		}
+	}
+
+	return nil
+}
+
+// suppress counts a suppressed entry.
+func (g *SignalGate) suppress(err *SuppressedSignalError) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.suppressed[err.Strategy]++
+	return err
+}
+
+// Record marks an entry as taken, starting its cooldown.
+func (g *SignalGate) Record(strategy, symbol string, buy bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	config := g.config(strategy)
+	g.last[entryKey(strategy, symbol, buy, config.AnyDirection)] = time.Now()
+}
+
+// Reset forgets the entry history of a strategy ("" = all strategies).
+func (g *SignalGate) Reset(strategy string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if strategy == "" {
+		g.last = make(map[string]time.Time)
+		return
+	}
+	prefix := strategy + "|"
+	for key := range g.last {
+		if strings.HasPrefix(key, prefix) {
+			delete(g.last, key)
+		}
+	}
+}
+
+// Suppressed returns the number of suppressed entries per strategy.
+func (g *SignalGate) Suppressed() map[string]int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	counts := make(map[string]int, len(g.suppressed))
+	for strategy, n := range g.suppressed {
+		counts[strategy] = n
+	}
+	return counts
+}
@@ -0,0 +1,291 @@
+package orchestrators
+
+/*══════════════════════════════════════════════════════════════════════════════
+ STRATEGY FRAMEWORK: Strategy + StrategyRunner
+
+ PURPOSE:
+   Event-driven alternative to writing a full orchestrator for every idea.
+   Implement four callbacks, hand the strategy to a StrategyRunner, and the
+   runner wires the tick stream, candle building, trade events and a timer
+   into it. The runner itself is an Orchestrator (Start/Stop/GetStatus/
+   GetMetrics), so it plugs into the existing demo runners unchanged.
+
+ EVENTS:
+   • OnTick       - every tick of a subscribed symbol
+   • OnBar        - every CLOSED candle of BarTimeframe (built from ticks),
+                    or every Renko/range/tick bar when BarFactory is set
+   • OnTradeEvent - every OnTrade event (orders, deals, positions)
+   • OnTimer      - every TimerInterval
+
+ All callbacks run on ONE goroutine, so strategy state needs no locking.
+
+ FROZEN FEEDS:
+   With StaleAfter set, a symbol silent for that long during its trading
+   hours is flagged; strategies should check runner.IsFeedStale(symbol)
+   before acting on the last price.
+
+ PROGRAMMATIC USAGE:
+   type myStrategy struct{ sugar *mt5.MT5Sugar }
+
+   func (s *myStrategy) OnTick(tick *mt5.SymbolTick) error          { return nil }
+   func (s *myStrategy) OnBar(bar *mt5.Candle) error                { ... }
+   func (s *myStrategy) OnTradeEvent(event *pb.OnTradeData) error   { return nil }
+   func (s *myStrategy) OnTimer(now time.Time) error                { return nil }
+
+   runner := orchestrators.NewStrategyRunner("MyStrategy", sugar, &myStrategy{sugar},
+       orchestrators.DefaultStrategyRunnerConfig([]string{"EURUSD"}))
+   runner.Start()
+   defer runner.Stop()
+══════════════════════════════════════════════════════════════════════════════*/
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	mt5 "github.com/MetaRPC/GoMT5/examples/mt5"
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// STRATEGY INTERFACE
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Strategy receives market and trade events from a StrategyRunner.
+// Returning an error counts it in the runner's ErrorCount; the runner keeps going.
+type Strategy interface {
+	OnTick(tick *mt5.SymbolTick) error
+	OnBar(bar *mt5.Candle) error
+	OnTradeEvent(event *pb.OnTradeData) error
+	OnTimer(now time.Time) error
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CONFIGURATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// StrategyRunnerConfig selects which events are delivered to the strategy.
+type StrategyRunnerConfig struct {
+	Symbols       []string                           // Symbols to stream ticks for
+	BarTimeframe  time.Duration                      // Candle size for OnBar (0 = no bars)
+	BarFactory    func(symbol string) mt5.BarBuilder // Non-time bars for OnBar (overrides BarTimeframe)
+	TimerInterval time.Duration                      // OnTimer period (0 = no timer)
+	TradeEvents   bool                               // Deliver OnTrade events
+	StaleAfter    time.Duration                      // Flag a symbol's feed stale after this much silence (0 = off)
+
+	// Runtime limits (zero = run until Stop)
+	Limits RuntimeLimits
+}
+
+// DefaultStrategyRunnerConfig returns sensible defaults (M1 bars, 1s timer, trade events on).
+func DefaultStrategyRunnerConfig(symbols []string) StrategyRunnerConfig {
+	return StrategyRunnerConfig{
+		Symbols:       symbols,
+		BarTimeframe:  time.Minute,
+		TimerInterval: time.Second,
+		TradeEvents:   true,
+	}
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// STRATEGY RUNNER IMPLEMENTATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// StrategyRunner drives a Strategy from live streams.
+type StrategyRunner struct {
+	*BaseOrchestrator
+	sugar    *mt5.MT5Sugar
+	strategy Strategy
+	config   StrategyRunnerConfig
+
+	builders    map[string]*mt5.CandleBuilder
+	barBuilders map[string]mt5.BarBuilder
+	staleness   *mt5.StalenessMonitor
+}
+
+// NewStrategyRunner creates a runner for the given strategy.
+func NewStrategyRunner(name string, sugar *mt5.MT5Sugar, strategy Strategy, config StrategyRunnerConfig) *StrategyRunner {
+	return &StrategyRunner{
+		BaseOrchestrator: NewBaseOrchestrator(name),
+		sugar:            sugar,
+		strategy:         strategy,
+		config:           config,
+		builders:         make(map[string]*mt5.CandleBuilder),
+		barBuilders:      make(map[string]mt5.BarBuilder),
+	}
+}
+
+// Start subscribes to streams and begins dispatching events.
+func (r *StrategyRunner) Start() error {
+	if r.IsRunning() {
+		return fmt.Errorf("strategy runner already running")
+	}
+	if r.strategy == nil {
+		return fmt.Errorf("strategy is nil")
+	}
+
+	for _, symbol := range r.config.Symbols {
+		if r.config.BarFactory != nil {
+			r.barBuilders[symbol] = r.config.BarFactory(symbol)
+		} else if r.config.BarTimeframe > 0 {
+			r.builders[symbol] = mt5.NewCandleBuilder(symbol, r.config.BarTimeframe)
+		}
+	}
+
+	if r.config.StaleAfter > 0 {
+		r.staleness = mt5.NewStalenessMonitor(r.config.StaleAfter, func(symbol string) bool {
+			open, err := r.sugar.IsTradingTime(symbol)
+			return err != nil || open
+		})
+		r.staleness.Watch(r.config.Symbols...)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.SetContext(ctx, cancel)
+
+	r.MarkStarted()
+
+	r.GoSafe(r.eventLoop)
+
+	// Stop automatically on MaxRuntime / MaxTrades / MaxLoss
+	r.EnforceLimits(r.config.Limits, r.Stop)
+
+	return nil
+}
+
+// Stop cancels all streams and stops dispatching.
+func (r *StrategyRunner) Stop() error {
+	if !r.IsRunning() {
+		return fmt.Errorf("strategy runner not running")
+	}
+
+	r.CancelContext()
+	r.MarkStopped()
+
+	return nil
+}
+
+// eventLoop multiplexes all event sources onto a single goroutine.
+func (r *StrategyRunner) eventLoop() {
+	ctx := r.GetContext()
+	service := r.sugar.GetService()
+
+	var tickCh <-chan *mt5.SymbolTick
+	var tickErrCh <-chan error
+	if len(r.config.Symbols) > 0 {
+		tickCh, tickErrCh = service.StreamTicks(ctx, r.config.Symbols)
+	}
+
+	var tradeCh <-chan *pb.OnTradeData
+	var tradeErrCh <-chan error
+	if r.config.TradeEvents {
+		tradeCh, tradeErrCh = service.StreamTradeUpdates(ctx)
+	}
+
+	var timerCh <-chan time.Time
+	if r.config.TimerInterval > 0 {
+		ticker := time.NewTicker(r.config.TimerInterval)
+		defer ticker.Stop()
+		timerCh = ticker.C
+	}
+
+	var staleCheckCh <-chan time.Time
+	var staleEventCh <-chan mt5.StalenessEvent
+	if r.staleness != nil {
+		ticker := time.NewTicker(r.config.StaleAfter / 2)
+		defer ticker.Stop()
+		staleCheckCh = ticker.C
+		staleEventCh = r.staleness.Events()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case tick, ok := <-tickCh:
+			if !ok {
+				tickCh = nil
+				continue
+			}
+			r.handleTick(tick)
+
+		case err, ok := <-tickErrCh:
+			if !ok {
+				tickErrCh = nil
+				continue
+			}
+			if err != nil && ctx.Err() == nil {
+				r.IncrementError(fmt.Sprintf("tick stream: %v", err))
+			}
+
+		case event, ok := <-tradeCh:
+			if !ok {
+				tradeCh = nil
+				continue
+			}
+			r.report("OnTradeEvent", r.strategy.OnTradeEvent(event))
+
+		case err, ok := <-tradeErrCh:
+			if !ok {
+				tradeErrCh = nil
+				continue
+			}
+			if err != nil && ctx.Err() == nil {
+				r.IncrementError(fmt.Sprintf("trade stream: %v", err))
+			}
+
+		case now := <-timerCh:
+			r.report("OnTimer", r.strategy.OnTimer(now))
+
+		case <-staleCheckCh:
+			r.staleness.Check()
+
+		case event := <-staleEventCh:
+			if !event.Recovered {
+				r.IncrementError(fmt.Sprintf("%s feed stale: no tick for %v", event.Symbol, event.Gap.Round(time.Second)))
+			}
+		}
+	}
+}
+
+// handleTick delivers the tick and any candle it closes.
+func (r *StrategyRunner) handleTick(tick *mt5.SymbolTick) {
+	if r.staleness != nil {
+		r.staleness.Observe(tick)
+	}
+	r.report("OnTick", r.strategy.OnTick(tick))
+
+	if builder, ok := r.barBuilders[tick.Symbol]; ok {
+		for _, bar := range builder.Add(tick) {
+			r.report("OnBar", r.strategy.OnBar(&bar))
+		}
+		return
+	}
+
+	builder, ok := r.builders[tick.Symbol]
+	if !ok {
+		return
+	}
+	if bar := builder.Add(tick); bar != nil {
+		r.report("OnBar", r.strategy.OnBar(bar))
+	}
+}
+
+// report records the outcome of a strategy callback.
+func (r *StrategyRunner) report(callback string, err error) {
+	if err != nil {
+		r.IncrementError(fmt.Sprintf("%s: %v", callback, err))
+		return
+	}
+	r.UpdateMetrics(func(m *OrchestratorMetrics) {
+		m.OperationsTotal++
+		m.LastOperation = callback
+	})
+}
+
+// IsFeedStale reports whether a symbol's tick feed is currently frozen
+// (always false unless StaleAfter is configured).
+func (r *StrategyRunner) IsFeedStale(symbol string) bool {
+	return r.staleness != nil && r.staleness.IsStale(symbol)
+}
@@ -0,0 +1,382 @@
+package orchestrators
+
+/*══════════════════════════════════════════════════════════════════════════════
+ VOLATILITY CIRCUIT: Entry Throttling During Volatility and Spread Spikes
+
+ PURPOSE:
+   Keeps strategies from entering into a spike. News candles, open gaps and
+   liquidity holes widen the spread and whip the price around; entries taken
+   then fill badly and stop out fast. The circuit watches realized
+   volatility and spread per symbol and, above the thresholds, widens the
+   minimum interval between entries or pauses entries altogether.
+
+ MEASURES (per symbol, from the tick stream):
+   • Realized volatility - square root of the sum of squared mid-price
+                           changes over Window, in points
+   • Spread              - current Ask - Bid, in points
+
+ RULES (per orchestrator, with a default for all others; zero = off):
+   • ThrottleVol / ThrottleSpread - above either, entries need MinInterval
+                                    between them on the symbol
+   • PauseVol / PauseSpread       - above either, no entries at all
+   • Cooldown                     - the circuit stays tripped this long after
+                                    the measures drop back below the thresholds
+
+ Without ticks for a symbol (not watched, or stale for longer than Window)
+ the circuit has no reading and lets entries through.
+
+ Suppressed entries return a *SuppressedSignalError wrapping
+ ErrVolatilityPause or ErrVolatilityThrottle, like the signal gate's.
+
+ PROGRAMMATIC USAGE:
+   circuit := orchestrators.NewVolatilityCircuit(sugar, orchestrators.VolatilityCircuitConfig{
+       Window: 2 * time.Minute, ThrottleVol: 150, PauseVol: 400,
+       ThrottleSpread: 25, PauseSpread: 60,
+       MinInterval: 10 * time.Minute, Cooldown: 5 * time.Minute,
+   })
+   circuit.Configure("Position Scaler", orchestrators.VolatilityCircuitConfig{PauseSpread: 40})
+   go circuit.Run(ctx, []string{"EURUSD", "GBPUSD"})
+
+   engine.SetVolatilityCircuit(circuit)   // checked by AllowEntry / ThrottleEntry
+══════════════════════════════════════════════════════════════════════════════*/
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	mt5 "github.com/MetaRPC/GoMT5/examples/mt5"
+)
+
+var (
+	ErrVolatilityPause    = errors.New("entries paused on volatility spike")
+	ErrVolatilityThrottle = errors.New("entry interval widened on high volatility")
+)
+
+// defaultVolatilityWindow is the realized volatility window when none is set.
+const defaultVolatilityWindow = 5 * time.Minute
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CONFIGURATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// VolatilityCircuitConfig defines when entries are throttled or paused.
+// Zero thresholds disable the corresponding rule.
+type VolatilityCircuitConfig struct {
+	Window         time.Duration // Realized volatility window (default 5m)
+	ThrottleVol    float64       // Realized volatility (points) that widens the entry interval
+	ThrottleSpread float64       // Spread (points) that widens the entry interval
+	PauseVol       float64       // Realized volatility (points) that pauses entries
+	PauseSpread    float64       // Spread (points) that pauses entries
+	MinInterval    time.Duration // Minimum time between entries on a symbol while throttled
+	Cooldown       time.Duration // Stay tripped this long after the measures calm down
+}
+
+// window returns the configured window or the default.
+func (c VolatilityCircuitConfig) window() time.Duration {
+	if c.Window > 0 {
+		return c.Window
+	}
+	return defaultVolatilityWindow
+}
+
+// VolatilityReading is the current state of a symbol.
+type VolatilityReading struct {
+	Symbol      string
+	RealizedVol float64 // Points over the window
+	Spread      float64 // Points, from the last tick
+	Samples     int     // Ticks in the window
+	Time        time.Time
+}
+
+// circuitLevel orders the circuit states.
+type circuitLevel int
+
+const (
+	circuitClosed circuitLevel = iota
+	circuitThrottled
+	circuitPaused
+)
+
+// level returns how far the reading trips the config.
+func (c VolatilityCircuitConfig) level(r VolatilityReading) circuitLevel {
+	switch {
+	case c.PauseVol > 0 && r.RealizedVol >= c.PauseVol,
+		c.PauseSpread > 0 && r.Spread >= c.PauseSpread:
+		return circuitPaused
+	case c.ThrottleVol > 0 && r.RealizedVol >= c.ThrottleVol,
+		c.ThrottleSpread > 0 && r.Spread >= c.ThrottleSpread:
+		return circuitThrottled
+	}
+	return circuitClosed
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// VOLATILITY CIRCUIT IMPLEMENTATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// volSample is one tick of a symbol.
+type volSample struct {
+	at     time.Time
+	mid    float64
+	spread float64 // Points
+}
+
+// circuitTrip holds a tripped circuit of one strategy and symbol through
+// its cooldown.
+type circuitTrip struct {
+	pausedUntil    time.Time
+	throttledUntil time.Time
+}
+
+// VolatilityCircuit tracks volatility per symbol and entries per strategy.
+// One circuit can be shared by several orchestrators; each is keyed by its
+// name.
+type VolatilityCircuit struct {
+	sugar *mt5.MT5Sugar
+
+	mu         sync.Mutex
+	defaults   VolatilityCircuitConfig
+	configs    map[string]VolatilityCircuitConfig // Strategy name → config
+	points     map[string]float64                 // Symbol → point size
+	samples    map[string][]volSample             // Symbol → ticks, oldest first
+	trips      map[string]circuitTrip             // "strategy|symbol" → tripped state
+	last       map[string]time.Time               // "strategy|symbol" → last entry
+	suppressed map[string]int                     // Strategy name → suppressed entries
+}
+
+// NewVolatilityCircuit creates a circuit applying defaults to every strategy
+// without its own config. Feed it ticks with Run or Observe.
+func NewVolatilityCircuit(sugar *mt5.MT5Sugar, defaults VolatilityCircuitConfig) *VolatilityCircuit {
+	return &VolatilityCircuit{
+		sugar:      sugar,
+		defaults:   defaults,
+		configs:    make(map[string]VolatilityCircuitConfig),
+		points:     make(map[string]float64),
+		samples:    make(map[string][]volSample),
+		trips:      make(map[string]circuitTrip),
+		last:       make(map[string]time.Time),
+		suppressed: make(map[string]int),
+	}
+}
+
+// Configure sets the rules for one strategy (orchestrator name).
+func (v *VolatilityCircuit) Configure(strategy string, config VolatilityCircuitConfig) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.configs[strategy] = config
+}
+
+// config returns the rules of a strategy. Caller holds v.mu.
+func (v *VolatilityCircuit) config(strategy string) VolatilityCircuitConfig {
+	if config, ok := v.configs[strategy]; ok {
+		return config
+	}
+	return v.defaults
+}
+
+// retention is the longest window of any config. Caller holds v.mu.
+func (v *VolatilityCircuit) retention() time.Duration {
+	longest := v.defaults.window()
+	for _, config := range v.configs {
+		if w := config.window(); w > longest {
+			longest = w
+		}
+	}
+	return longest
+}
+
+// Run streams ticks of symbols into the circuit until ctx is cancelled.
+// Returns nil on cancellation, or the tick stream error.
+func (v *VolatilityCircuit) Run(ctx context.Context, symbols []string) error {
+	tickCh, errCh := v.sugar.GetService().StreamTicks(ctx, symbols)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case tick, ok := <-tickCh:
+			if !ok {
+				return nil
+			}
+			v.Observe(tick)
+		case err, ok := <-errCh:
+			if !ok {
+				errCh = nil
+				continue
+			}
+			if err != nil && ctx.Err() == nil {
+				return fmt.Errorf("volatility circuit: %w", err)
+			}
+		}
+	}
+}
+
+// Observe adds a tick to its symbol's history. Ticks are stamped with the
+// local receive time, since tick.Time is in server time.
+func (v *VolatilityCircuit) Observe(tick *mt5.SymbolTick) {
+	if tick == nil || tick.Bid <= 0 || tick.Ask <= 0 {
+		return
+	}
+	point := v.symbolPoint(tick.Symbol)
+	if point <= 0 {
+		return
+	}
+	at := time.Now()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	samples := append(v.samples[tick.Symbol], volSample{
+		at:     at,
+		mid:    (tick.Bid + tick.Ask) / 2,
+		spread: (tick.Ask - tick.Bid) / point,
+	})
+	cutoff := at.Add(-v.retention())
+	drop := 0
+	for drop < len(samples)-1 && samples[drop].at.Before(cutoff) {
+		drop++
+	}
+	v.samples[tick.Symbol] = samples[drop:]
+}
+
+// symbolPoint returns the cached point size of a symbol.
+func (v *VolatilityCircuit) symbolPoint(symbol string) float64 {
+	v.mu.Lock()
+	point, ok := v.points[symbol]
+	v.mu.Unlock()
+	if ok {
+		return point
+	}
+
+	info, err := v.sugar.GetSymbolInfo(symbol)
+	if err != nil {
+		return 0
+	}
+
+	v.mu.Lock()
+	v.points[symbol] = info.Point
+	v.mu.Unlock()
+	return info.Point
+}
+
+// Reading returns the volatility of symbol over strategy's window, or false
+// if there are no recent ticks.
+func (v *VolatilityCircuit) Reading(strategy, symbol string) (VolatilityReading, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.reading(symbol, v.config(strategy).window(), time.Now())
+}
+
+// reading computes a symbol's reading over window. Caller holds v.mu.
+func (v *VolatilityCircuit) reading(symbol string, window time.Duration, now time.Time) (VolatilityReading, bool) {
+	samples := v.samples[symbol]
+	if len(samples) == 0 {
+		return VolatilityReading{}, false
+	}
+	latest := samples[len(samples)-1]
+	if now.Sub(latest.at) > window {
+		return VolatilityReading{}, false
+	}
+
+	r := VolatilityReading{Symbol: symbol, Spread: latest.spread, Time: latest.at}
+	point := v.points[symbol]
+	cutoff := now.Add(-window)
+	var sumSq float64
+	var prev *volSample
+	for i := range samples {
+		if samples[i].at.Before(cutoff) {
+			continue
+		}
+		r.Samples++
+		if prev != nil {
+			d := (samples[i].mid - prev.mid) / point
+			sumSq += d * d
+		}
+		prev = &samples[i]
+	}
+	r.RealizedVol = math.Sqrt(sumSq)
+	return r, true
+}
+
+// Allow checks whether strategy may enter symbol now. Returns nil if
+// allowed or *SuppressedSignalError if the circuit holds the entry back.
+func (v *VolatilityCircuit) Allow(strategy, symbol string, buy bool) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := time.Now()
+	config := v.config(strategy)
+	key := strategy + "|" + symbol
+
+	reading, ok := v.reading(symbol, config.window(), now)
+	current := circuitClosed
+	if ok {
+		current = config.level(reading)
+	}
+
+	trip := v.trips[key]
+	switch current {
+	case circuitPaused:
+		trip.pausedUntil = now.Add(config.Cooldown)
+		trip.throttledUntil = trip.pausedUntil
+		v.trips[key] = trip
+	case circuitThrottled:
+		trip.throttledUntil = now.Add(config.Cooldown)
+		v.trips[key] = trip
+	}
+	level := current
+	if now.Before(trip.pausedUntil) {
+		level = circuitPaused
+	} else if level == circuitClosed && now.Before(trip.throttledUntil) {
+		level = circuitThrottled
+	}
+
+	detail := fmt.Sprintf("realized vol %.0f, spread %.0f points", reading.RealizedVol, reading.Spread)
+	switch {
+	case !ok:
+		detail = "cooling down"
+	case level > current:
+		detail = "cooling down, " + detail
+	}
+	switch level {
+	case circuitPaused:
+		v.suppressed[strategy]++
+		return &SuppressedSignalError{
+			Strategy: strategy, Symbol: symbol, Buy: buy,
+			Reason: fmt.Errorf("%w (%s)", ErrVolatilityPause, detail), Wait: trip.pausedUntil.Sub(now),
+		}
+	case circuitThrottled:
+		last, seen := v.last[key]
+		if config.MinInterval <= 0 || !seen {
+			return nil
+		}
+		if wait := config.MinInterval - now.Sub(last); wait > 0 {
+			v.suppressed[strategy]++
+			return &SuppressedSignalError{
+				Strategy: strategy, Symbol: symbol, Buy: buy,
+				Reason: fmt.Errorf("%w (%s)", ErrVolatilityThrottle, detail), Wait: wait,
+			}
+		}
+	}
+	return nil
+}
+
+// Record marks an entry as taken, for the throttled entry interval.
+func (v *VolatilityCircuit) Record(strategy, symbol string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.last[strategy+"|"+symbol] = time.Now()
+}
+
+// Suppressed returns the number of entries held back per strategy.
+func (v *VolatilityCircuit) Suppressed() map[string]int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	counts := make(map[string]int, len(v.suppressed))
+	for strategy, n := range v.suppressed {
+		counts[strategy] = n
+	}
+	return counts
+}
@@ -196,6 +196,10 @@ type AdaptiveOrchestratorPreset struct {
 	initialBalance   float64
 	dailyStartBalance float64
 	activeOrchestrators []orchestrators.Orchestrator
+	finished         []orchestrators.RunSummary // Orchestrators stopped by earlier cycles
+	startTime        time.Time
+	attribution      *orchestrators.PnLAttributor
+	regime           *mt5.RegimeClassifier
 	ctx              context.Context
 	cancel           context.CancelFunc
 }
@@ -218,6 +222,25 @@ func NewAdaptiveOrchestratorPreset(sugar *mt5.MT5Sugar) *AdaptiveOrchestratorPre
 	}
 }
 
+// UseWatchlist takes the portfolio symbols from a stored watchlist instead
+// of the built-in list (e.g. store.json: {"portfolio": ["EURUSD", "XAUUSD"]}).
+func (p *AdaptiveOrchestratorPreset) UseWatchlist(store *mt5.WatchlistStore, name string) error {
+	list, err := store.Get(name)
+	if err != nil {
+		return err
+	}
+	if len(list.Symbols) == 0 {
+		return fmt.Errorf("watchlist %s is empty", name)
+	}
+	if failed := p.sugar.EnsureVisible(list); len(failed) > 0 {
+		for symbol, err := range failed {
+			fmt.Printf("  ⚠️  %s not visible: %v\n", symbol, err)
+		}
+	}
+	p.PortfolioSymbols = list.Symbols
+	return nil
+}
+
 // MarketMode represents the current market regime.
 type MarketMode int
 
@@ -251,6 +274,7 @@ type MarketCondition struct {
 	Mode             MarketMode
 	VolatilityPoints float64
 	TrendStrength    float64 // -1.0 to 1.0 (bearish to bullish)
+	Regime           mt5.Regime // Classifier regime (RegimeUnknown while warming up)
 	Reason           string
 	ActivePositions  int
 	ActiveSymbols    int
@@ -272,6 +296,9 @@ func (p *AdaptiveOrchestratorPreset) Execute() (float64, error) {
 		return 0, fmt.Errorf("failed to get balance: %w", err)
 	}
 	p.dailyStartBalance = p.initialBalance
+	p.startTime = time.Now()
+	p.finished = nil
+	p.attribution = orchestrators.NewPnLAttributor(p.sugar, p.startTime)
 
 	fmt.Printf("  💰 Starting balance: $%.2f\n", p.initialBalance)
 	fmt.Printf("  📊 Primary symbol: %s\n", p.Symbol)
@@ -283,6 +310,16 @@ func (p *AdaptiveOrchestratorPreset) Execute() (float64, error) {
 	p.ctx, p.cancel = context.WithCancel(context.Background())
 	defer p.cancel()
 
+	// Regime classifier on M1 candles; spread-based detection is used until it has enough bars
+	regimeConfig := mt5.DefaultRegimeConfig()
+	regimeConfig.Timeframe = time.Minute
+	p.regime = mt5.NewRegimeClassifier(regimeConfig)
+	go func() {
+		if err := p.regime.Run(p.ctx, p.sugar.GetService(), []string{p.Symbol}); err != nil {
+			fmt.Printf("  ⚠️  Regime classifier stopped: %v\n", err)
+		}
+	}()
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
@@ -345,6 +382,7 @@ func (p *AdaptiveOrchestratorPreset) executeCycle() {
 
 	fmt.Printf("  🔍 Market Analysis Complete:\n")
 	fmt.Printf("     Mode: %s\n", condition.Mode)
+	fmt.Printf("     Regime: %s\n", condition.Regime)
 	fmt.Printf("     Volatility: %.1f points\n", condition.VolatilityPoints)
 	fmt.Printf("     Trend: %.2f (%.0f%% strength)\n", condition.TrendStrength, math.Abs(condition.TrendStrength)*100)
 	fmt.Printf("     Reason: %s\n", condition.Reason)
@@ -372,6 +410,11 @@ func (p *AdaptiveOrchestratorPreset) executeCycle() {
 		fmt.Printf("  ⚠️  Unknown market mode, skipping cycle\n")
 	}
 
+	// Attribute P&L of this cycle's orchestrators by the tickets they open
+	for _, orch := range p.activeOrchestrators {
+		p.attribution.Track(orch)
+	}
+
 	// Monitor for cycle duration
 	p.monitorCycle()
 
@@ -384,12 +427,21 @@ func (p *AdaptiveOrchestratorPreset) executeCycle() {
 	fmt.Printf("     Profit: $%.2f\n", cycleProfit)
 	fmt.Printf("     Total P/L: $%.2f\n", p.totalProfit)
 	fmt.Printf("     Current Balance: $%.2f\n", cycleEndBalance)
+	p.showAttribution()
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
 // MARKET ANALYSIS
 // ══════════════════════════════════════════════════════════════════════════════
 
+// regimeModes maps classifier regimes to the orchestrator mode run in them.
+var regimeModes = map[mt5.Regime]MarketMode{
+	mt5.RegimeQuiet:    GridMode,
+	mt5.RegimeRanging:  ManagedMode,
+	mt5.RegimeTrending: TrendingMode,
+	mt5.RegimeVolatile: ProtectionMode,
+}
+
 // analyzeMarketConditions analyzes current market and returns conditions.
 func (p *AdaptiveOrchestratorPreset) analyzeMarketConditions() (*MarketCondition, error) {
 	// Get current market data
@@ -424,10 +476,24 @@ func (p *AdaptiveOrchestratorPreset) analyzeMarketConditions() (*MarketCondition
 	// For demo, use random-like value based on spread
 	condition.TrendStrength = math.Mod(spreadPoints, 2.0) - 1.0 // -1.0 to 1.0
 
+	// Regime classifier replaces the spread estimate once it has warmed up
+	var state mt5.RegimeState
+	if p.regime != nil {
+		state, _ = p.regime.Current(p.Symbol)
+	}
+	condition.Regime = state.Regime
+	if state.Regime != mt5.RegimeUnknown {
+		condition.TrendStrength = state.Efficiency
+	}
+
 	// Determine market mode (PRIORITY ORDER: Portfolio → Protection → Grid → Trending → Managed)
 	if p.EnablePortfolioMode && len(symbolMap) >= 2 {
 		condition.Mode = PortfolioMode
 		condition.Reason = fmt.Sprintf("Multi-symbol portfolio (%d symbols active)", len(symbolMap))
+	} else if state.Regime != mt5.RegimeUnknown {
+		condition.Mode = regimeModes[state.Regime]
+		condition.Reason = fmt.Sprintf("Regime %s (ATR percentile %.0f, efficiency %+.2f, compression %.2f)",
+			state.Regime, state.ATRPercentile, state.Efficiency, state.Compression)
 	} else if estimatedVolatility > p.HighVolatilityThreshold {
 		condition.Mode = ProtectionMode
 		condition.Reason = fmt.Sprintf("High volatility (%.1f pts) - risk protection needed", estimatedVolatility)
@@ -560,23 +626,36 @@ func (p *AdaptiveOrchestratorPreset) executeTrendingMode(condition *MarketCondit
 
 // monitorCycle monitors orchestrators for the cycle duration with progress bar.
 func (p *AdaptiveOrchestratorPreset) monitorCycle() {
-	totalSeconds := int(p.CycleDuration.Seconds())
-
-	// Use progress bar with callback to show orchestrator status
-	helpers.WaitWithProgressBarAndCallback(
-		totalSeconds,
-		fmt.Sprintf("Cycle #%d monitoring", p.cycleNumber),
-		10*time.Second, // Update orchestrator status every 10 seconds
-		func() bool {
-			// Show detailed orchestrator status during callback
-			fmt.Println() // New line before status
-			p.showOrchestratorStatus()
-			return true // Continue waiting
-		},
-		p.ctx,
-	)
+	helpers.Monitor{
+		Message:  fmt.Sprintf("Cycle #%d monitoring", p.cycleNumber),
+		Duration: p.CycleDuration,
+		Interval: 10 * time.Second, // Update orchestrator status every 10 seconds
+		Update:   p.cycleStatus,
+	}.Run(p.ctx)
+
+	p.showOrchestratorStatus()
+	fmt.Printf("  ✓ Cycle #%d completed\n", p.cycleNumber)
+}
 
-	fmt.Printf("\n  ✓ Cycle #%d completed\n", p.cycleNumber)
+// cycleStatus summarizes the active orchestrators for the cycle monitor.
+func (p *AdaptiveOrchestratorPreset) cycleStatus() helpers.MonitorStatus {
+	ops, errs := 0, 0
+	names := make([]string, 0, len(p.activeOrchestrators))
+	for _, orch := range p.activeOrchestrators {
+		status := orch.GetStatus()
+		ops += status.SuccessCount + status.ErrorCount
+		errs += status.ErrorCount
+		names = append(names, status.Name)
+	}
+	return helpers.MonitorStatus{
+		Line: fmt.Sprintf("Active: %d | Ops: %d | Errors: %d", len(p.activeOrchestrators), ops, errs),
+		Fields: map[string]any{
+			"cycle":         p.cycleNumber,
+			"orchestrators": names,
+			"operations":    ops,
+			"errors":        errs,
+		},
+	}
 }
 
 // showOrchestratorStatus displays status of all active orchestrators.
@@ -601,6 +680,7 @@ func (p *AdaptiveOrchestratorPreset) stopAllOrchestrators() {
 		if orch.IsRunning() {
 			orch.Stop()
 		}
+		p.finished = append(p.finished, orchestrators.NewRunSummary(orch, nil))
 	}
 	p.activeOrchestrators = make([]orchestrators.Orchestrator, 0)
 }
@@ -644,6 +724,59 @@ func (p *AdaptiveOrchestratorPreset) showFinalResults() {
 	fmt.Printf("  Cycles Completed: %d\n", p.cycleNumber)
 	fmt.Printf("  Average Per Cycle: $%.2f\n", p.totalProfit/float64(p.cycleNumber))
 	fmt.Println("+============================================================+\n")
+	p.showAttribution()
+}
+
+// showAttribution prints realized and floating P&L per strategy.
+func (p *AdaptiveOrchestratorPreset) showAttribution() {
+	report, err := p.GetAttribution()
+	if err != nil {
+		fmt.Printf("  ⚠️  P&L attribution failed: %v\n", err)
+		return
+	}
+	fmt.Printf("\n  📊 P&L by strategy:\n")
+	fmt.Print(orchestrators.FormatAttribution(report))
+}
+
+// GetAttribution returns P&L per strategy since Execute started.
+func (p *AdaptiveOrchestratorPreset) GetAttribution() ([]orchestrators.StrategyPnL, error) {
+	if p.attribution == nil {
+		return nil, fmt.Errorf("preset not started")
+	}
+	return p.attribution.Calculate()
+}
+
+// Summary returns the run summary of Execute: the preset settings, balance
+// change and cycles, P&L by strategy and one entry per orchestrator the
+// cycles ran. Call it after Execute returns.
+func (p *AdaptiveOrchestratorPreset) Summary() orchestrators.RunSummary {
+	s := orchestrators.RunSummary{
+		Name:      "Adaptive Orchestrator Preset",
+		StartedAt: p.startTime,
+		EndedAt:   time.Now(),
+	}
+	if !p.startTime.IsZero() {
+		s.DurationSec = s.EndedAt.Sub(p.startTime).Seconds()
+	}
+	s.SetConfig(p) // Exported settings only
+	for _, child := range p.finished {
+		s.Add(child)
+	}
+	for _, orch := range p.activeOrchestrators {
+		s.Add(orchestrators.NewRunSummary(orch, nil))
+	}
+	if report, err := p.GetAttribution(); err == nil {
+		s.Strategies = report
+	}
+
+	finalBalance, _ := p.sugar.GetBalance()
+	s.Extra = map[string]any{
+		"cycles":          p.cycleNumber,
+		"initial_balance": p.initialBalance,
+		"final_balance":   finalBalance,
+		"total_profit":    p.totalProfit,
+	}
+	return s
 }
 
 // GetCycleNumber returns current cycle number.
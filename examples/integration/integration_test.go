@@ -0,0 +1,454 @@
+//go:build integration
+
+/*══════════════════════════════════════════════════════════════════════════════
+ FILE: integration_test.go - INTEGRATION SUITE AGAINST A DEMO ACCOUNT
+
+ PURPOSE:
+   Exercises the Sugar/Service/Account wrappers end to end against a real
+   demo account, so regressions are caught before a release:
+
+     1. TestConnect          - QuickConnect, Ping, account is DEMO or CONTEST
+     2. TestQuotes           - GetPriceInfo, GetSymbolInfo, one streamed tick
+     3. TestPendingOrder     - BuyLimit far below market, modify price, delete
+     4. TestMarketRoundTrip  - minimum-volume BuyMarket, verify, ClosePosition;
+                               then the round trip appears in orders and
+                               positions history (subtest "history")
+
+   Every order and position a test creates is removed by t.Cleanup, also
+   after a failed step, and on Ctrl+C. The suite refuses to trade on a REAL
+   account: every test fails before sending anything.
+
+ BUILD TAG:
+   Built only with -tags integration, so regular test runs never touch an
+   account:
+
+     cd examples
+     go test -tags integration ./integration -v                 # config/config.json or MT5_* env
+     go test -tags integration ./integration -v -args -config demo.json -symbol GBPUSD
+
+ SKIPS:
+   Market and history tests are skipped, not failed, when the broker reports
+   the market closed (retcode 10018) or no tick arrives, e.g. on weekends.
+══════════════════════════════════════════════════════════════════════════════*/
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MetaRPC/GoMT5/examples/demos/config"
+	mt5 "github.com/MetaRPC/GoMT5/examples/mt5"
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+var (
+	configPath = flag.String("config", "", "config file (default config/config.json, then MT5_* environment)")
+	symbolFlag = flag.String("symbol", "", "symbol to trade (default: test_symbol from config)")
+)
+
+// session is the demo connection shared by all tests.
+var session struct {
+	once   sync.Once
+	sugar  *mt5.MT5Sugar
+	symbol string
+	since  time.Time // Suite start, lower bound of history reads
+	err    error     // Setup error, reported by every test
+
+	mu      sync.Mutex
+	pending map[uint64]bool // Pending orders not removed yet
+	opened  map[uint64]bool // Positions not closed yet
+}
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+
+	// Clean up on Ctrl+C as well; t.Cleanup does not run when the binary is killed
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		fmt.Println("\ninterrupted - cleaning up")
+		cleanupAll()
+		os.Exit(1)
+	}()
+
+	code := m.Run()
+	if session.sugar != nil {
+		session.sugar.GetService().GetAccount().Close()
+	}
+	os.Exit(code)
+}
+
+// demo returns the shared connection, connecting on first use. The test
+// fails if setup failed or the account is REAL.
+func demo(t *testing.T) *mt5.MT5Sugar {
+	t.Helper()
+	session.once.Do(func() {
+		session.err = connect()
+	})
+	if session.err != nil {
+		t.Fatalf("setup: %v", session.err)
+	}
+	return session.sugar
+}
+
+// connect loads the config, connects and refuses real accounts.
+func connect() error {
+	if *configPath != "" {
+		config.SetPath(*configPath)
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	session.symbol = *symbolFlag
+	if session.symbol == "" {
+		session.symbol = cfg.TestSymbol
+	}
+
+	sugar, err := mt5.NewMT5Sugar(cfg.User, cfg.Password, cfg.GrpcServer)
+	if err != nil {
+		return err
+	}
+	session.sugar = sugar
+	session.since = time.Now().Add(-time.Minute)
+	session.pending = make(map[uint64]bool)
+	session.opened = make(map[uint64]bool)
+
+	if err := sugar.QuickConnect(cfg.MtCluster); err != nil {
+		return fmt.Errorf("connect failed: %w", err)
+	}
+	mode, ok := sugar.GetService().GetAccount().TradeMode()
+	if !ok {
+		return fmt.Errorf("account trade mode unknown; refusing to trade")
+	}
+	if mode == pb.MrpcEnumAccountTradeMode_MRPC_ACCOUNT_TRADE_MODE_REAL {
+		return fmt.Errorf("REAL account; the integration suite only runs on demo accounts")
+	}
+	return nil
+}
+
+// ═══════════════════════════════════════════════════════════════
+// TESTS
+// ═══════════════════════════════════════════════════════════════
+
+// TestConnect checks the session.
+func TestConnect(t *testing.T) {
+	sugar := demo(t)
+	if !sugar.IsConnected() {
+		t.Fatal("not connected after QuickConnect")
+	}
+	if err := sugar.Ping(); err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+}
+
+// TestQuotes reads prices and symbol parameters and waits for one tick.
+func TestQuotes(t *testing.T) {
+	sugar := demo(t)
+
+	price, err := sugar.GetPriceInfo(session.symbol)
+	if err != nil {
+		t.Fatalf("GetPriceInfo: %v", err)
+	}
+	if price.Bid <= 0 || price.Ask < price.Bid {
+		t.Fatalf("implausible quote bid=%v ask=%v", price.Bid, price.Ask)
+	}
+
+	info := symbolInfo(t)
+	if info.Point <= 0 || info.VolumeMin <= 0 {
+		t.Fatalf("implausible symbol info point=%v volume_min=%v", info.Point, info.VolumeMin)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	ticks, errs := sugar.GetService().StreamTicks(ctx, []string{session.symbol})
+	select {
+	case tick, ok := <-ticks:
+		if !ok {
+			t.Fatal("tick stream closed without data")
+		}
+		if tick.Bid <= 0 {
+			t.Fatalf("implausible streamed tick bid=%v", tick.Bid)
+		}
+	case err := <-errs:
+		if errors.Is(err, context.DeadlineExceeded) {
+			t.Skip("no tick within 30s (market closed?)")
+		}
+		t.Fatalf("tick stream: %v", err)
+	}
+}
+
+// TestPendingOrder places a BUY LIMIT far below market, moves it and deletes it.
+func TestPendingOrder(t *testing.T) {
+	sugar := demo(t)
+	info := symbolInfo(t)
+
+	distance := math.Max(float64(info.StopLevel)+100, 1000) * info.Point
+	price := round(info, info.Bid-distance)
+
+	ticket, err := sugar.BuyLimit(session.symbol, info.VolumeMin, price)
+	if err != nil {
+		skipIfMarketClosed(t, err)
+		t.Fatalf("BuyLimit: %v", err)
+	}
+	trackOrder(t, ticket)
+
+	order := findOrder(t, ticket)
+	if order == nil {
+		t.Fatalf("order #%d not among open orders", ticket)
+	}
+	if math.Abs(order.PriceOpen-price) > info.Point/2 {
+		t.Fatalf("order #%d price %v, placed at %v", ticket, order.PriceOpen, price)
+	}
+
+	moved := round(info, price-100*info.Point)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	result, err := sugar.GetService().ModifyOrder(ctx, &pb.OrderModifyRequest{Ticket: ticket, Price: &moved})
+	if err != nil {
+		t.Fatalf("ModifyOrder: %v", err)
+	}
+	if result.ReturnedCode != 10009 {
+		t.Fatalf("ModifyOrder rejected, code %d: %s", result.ReturnedCode, result.Comment)
+	}
+	if order = findOrder(t, ticket); order == nil {
+		t.Fatalf("order #%d not among open orders after modify", ticket)
+	}
+	if math.Abs(order.PriceOpen-moved) > info.Point/2 {
+		t.Fatalf("order #%d price %v after modify, want %v", ticket, order.PriceOpen, moved)
+	}
+
+	if err := deleteOrder(ticket); err != nil {
+		t.Fatal(err)
+	}
+	if findOrder(t, ticket) != nil {
+		t.Fatalf("order #%d still open after delete", ticket)
+	}
+}
+
+// TestMarketRoundTrip opens and closes a minimum-volume position, then looks
+// for it in the history.
+func TestMarketRoundTrip(t *testing.T) {
+	sugar := demo(t)
+	info := symbolInfo(t)
+
+	ticket, err := sugar.BuyMarket(session.symbol, info.VolumeMin)
+	if err != nil {
+		skipIfMarketClosed(t, err)
+		t.Fatalf("BuyMarket: %v", err)
+	}
+	trackPosition(t, ticket)
+
+	pos, err := sugar.GetPositionByTicket(ticket)
+	if err != nil {
+		t.Fatalf("position #%d not found: %v", ticket, err)
+	}
+	if pos.Volume != info.VolumeMin {
+		t.Fatalf("position #%d volume %v, want %v", ticket, pos.Volume, info.VolumeMin)
+	}
+
+	if err := closePosition(ticket); err != nil {
+		t.Fatalf("ClosePosition: %v", err)
+	}
+	if _, err := sugar.GetPositionByTicket(ticket); err == nil {
+		t.Fatalf("position #%d still open after close", ticket)
+	}
+
+	t.Run("history", func(t *testing.T) {
+		testHistory(t, ticket)
+	})
+}
+
+// testHistory finds a closed round trip in orders and positions history.
+// History is updated asynchronously, so it polls for a few seconds.
+func testHistory(t *testing.T, ticket uint64) {
+	service := demo(t).GetService()
+
+	deadline := time.Now().Add(15 * time.Second)
+	var orderFound, positionFound bool
+	for time.Now().Before(deadline) && !(orderFound && positionFound) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		orders, err := service.GetOrderHistory(ctx, session.since, time.Now().Add(time.Minute),
+			pb.BMT5_ENUM_ORDER_HISTORY_SORT_TYPE_BMT5_SORT_BY_OPEN_TIME_DESC, 0, 100)
+		if err != nil {
+			cancel()
+			t.Fatalf("GetOrderHistory: %v", err)
+		}
+		for _, item := range orders.GetHistoryData() {
+			if item.GetHistoryOrder().GetTicket() == ticket {
+				orderFound = true
+			}
+		}
+
+		from, to := session.since, time.Now().Add(time.Minute)
+		positions, err := service.GetPositionsHistory(ctx, pb.AH_ENUM_POSITIONS_HISTORY_SORT_TYPE_AH_POSITION_OPEN_TIME_DESC, &from, &to, nil, nil)
+		cancel()
+		if err != nil {
+			t.Fatalf("GetPositionsHistory: %v", err)
+		}
+		for _, p := range positions.GetHistoryPositions() {
+			if p.GetPositionTicket() == ticket {
+				positionFound = true
+			}
+		}
+		if !(orderFound && positionFound) {
+			time.Sleep(time.Second)
+		}
+	}
+
+	if !orderFound {
+		t.Errorf("order #%d not in order history", ticket)
+	}
+	if !positionFound {
+		t.Errorf("position #%d not in positions history", ticket)
+	}
+}
+
+// ═══════════════════════════════════════════════════════════════
+// HELPERS
+// ═══════════════════════════════════════════════════════════════
+
+// symbolInfo reads the test symbol's parameters.
+func symbolInfo(t *testing.T) *mt5.SymbolInfo {
+	t.Helper()
+	info, err := demo(t).GetSymbolInfo(session.symbol)
+	if err != nil {
+		t.Fatalf("GetSymbolInfo: %v", err)
+	}
+	return info
+}
+
+// trackOrder deletes the pending order when the test ends, unless the test
+// removed it itself.
+func trackOrder(t *testing.T, ticket uint64) {
+	session.mu.Lock()
+	session.pending[ticket] = true
+	session.mu.Unlock()
+
+	t.Cleanup(func() {
+		if !tracked(session.pending, ticket) {
+			return
+		}
+		if err := deleteOrder(ticket); err != nil {
+			t.Errorf("cleanup: order #%d: %v", ticket, err)
+		}
+	})
+}
+
+// trackPosition closes the position when the test ends, unless the test
+// closed it itself.
+func trackPosition(t *testing.T, ticket uint64) {
+	session.mu.Lock()
+	session.opened[ticket] = true
+	session.mu.Unlock()
+
+	t.Cleanup(func() {
+		if !tracked(session.opened, ticket) {
+			return
+		}
+		if err := closePosition(ticket); err != nil {
+			t.Errorf("cleanup: position #%d: %v", ticket, err)
+		}
+	})
+}
+
+// tracked reports whether ticket is still waiting for removal.
+func tracked(tickets map[uint64]bool, ticket uint64) bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return tickets[ticket]
+}
+
+// cleanupAll removes every order and position still tracked (interrupt path).
+func cleanupAll() {
+	session.mu.Lock()
+	var pending, opened []uint64
+	for ticket := range session.pending {
+		pending = append(pending, ticket)
+	}
+	for ticket := range session.opened {
+		opened = append(opened, ticket)
+	}
+	session.mu.Unlock()
+
+	for _, ticket := range pending {
+		if err := deleteOrder(ticket); err != nil {
+			fmt.Printf("cleanup: order #%d: %v\n", ticket, err)
+		}
+	}
+	for _, ticket := range opened {
+		if err := closePosition(ticket); err != nil {
+			fmt.Printf("cleanup: position #%d: %v\n", ticket, err)
+		}
+	}
+}
+
+// deleteOrder removes a pending order.
+func deleteOrder(ticket uint64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	code, err := session.sugar.GetService().CloseOrder(ctx, &pb.OrderCloseRequest{Ticket: ticket})
+	if err != nil {
+		return fmt.Errorf("delete order #%d: %w", ticket, err)
+	}
+	if code != 10009 {
+		return fmt.Errorf("delete order #%d rejected, code %d", ticket, code)
+	}
+	session.mu.Lock()
+	delete(session.pending, ticket)
+	session.mu.Unlock()
+	return nil
+}
+
+// closePosition closes a position.
+func closePosition(ticket uint64) error {
+	if err := session.sugar.ClosePosition(ticket); err != nil {
+		return err
+	}
+	session.mu.Lock()
+	delete(session.opened, ticket)
+	session.mu.Unlock()
+	return nil
+}
+
+// findOrder returns the open pending order with the given ticket, or nil.
+func findOrder(t *testing.T, ticket uint64) *pb.OpenedOrderInfo {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	data, err := session.sugar.GetService().GetOpenedOrders(ctx, pb.BMT5_ENUM_OPENED_ORDER_SORT_TYPE_BMT5_OPENED_ORDER_SORT_BY_OPEN_TIME_ASC)
+	if err != nil {
+		t.Fatalf("GetOpenedOrders: %v", err)
+	}
+	for _, order := range data.GetOpenedOrders() {
+		if order.GetTicket() == ticket {
+			return order
+		}
+	}
+	return nil
+}
+
+// round rounds a price to the symbol's digits.
+func round(info *mt5.SymbolInfo, price float64) float64 {
+	scale := math.Pow(10, float64(info.Digits))
+	return math.Round(price*scale) / scale
+}
+
+// skipIfMarketClosed skips the test if err is a "market closed" rejection.
+func skipIfMarketClosed(t *testing.T, err error) {
+	t.Helper()
+	var rejected *mt5.TradeRejectedError
+	if errors.As(err, &rejected) && rejected.Result.ReturnedCode == 10018 { // TRADE_RETCODE_MARKET_CLOSED
+		t.Skipf("market closed: %v", err)
+	}
+}
@@ -8,13 +8,20 @@ LOW → MT5Account (protobuf Request/Data, direct gRPC)
 MID → MT5Service (Go types, removes Data wrappers)
 HIGH → MT5Sugar (business logic, ready-made patterns)
 
-Methods (37 items):
+Methods (53 items):
+
+CONNECTION:
+- Connect() - connect using ConnectOptions (picks the variant below)
+- ConnectByServerName() - connect by cluster/server name
+- ConnectByHostPort() - connect by trade server host:port
+- ConnectByProxy() - connect through a proxy
 
 ACCOUNT:
 - GetAccountSummary() - all account information
 - GetAccountDouble() - double property (Balance, Equity)
 - GetAccountInteger() - integer property (Login, Leverage)
 - GetAccountString() - string property (Currency, Company)
+- WatchAccountChanges() - balance/credit/leverage diffs explained by deals
 
 SYMBOL:
 - GetSymbolsTotal() - number of symbols
@@ -30,13 +37,18 @@ SYMBOL:
 - GetSymbolSessionQuote() - quote session time
 - GetSymbolSessionTrade() - trading session time
 - GetSymbolParamsMany() - parameters of multiple symbols
+- GetSymbolFillingModes() - filling policies allowed for a symbol
+- SetSymbolResolver() - canonical → broker names for every symbol argument
 
 POSITIONS & ORDERS:
 - GetPositionsTotal() - number of open positions
 - GetOpenedOrders() - all open orders/positions
 - GetOpenedTickets() - ticket numbers only
+- WatchOpenedOrders() - add/remove/change deltas from the tickets stream
 - GetOrderHistory() - order history
 - GetPositionsHistory() - closed positions history
+- ExportDeals() - deal history as CSV/JSONL/Parquet
+- ExportOrders() - order history as CSV/JSONL/Parquet
 
 MARKET DEPTH:
 - SubscribeMarketDepth() - subscribe to DOM
@@ -44,24 +56,35 @@ MARKET DEPTH:
 - GetMarketDepth() - current DOM snapshot
 
 TRADING:
-- PlaceOrder() - sending an order
+- PlaceOrder() - sending an order (deprecated, use SendOrder)
+- SendOrder() - sending an order built from OrderRequest
+- SendOrderWithRequoteRetry() - sending an order with requote resubmission
 - ModifyOrder() - modifying an order/position
 - CloseOrder() - closing a position
 - CheckOrder() - preliminary order check
+- CheckOrderRequest() - order check with filling fallback
 - CalculateMargin() - calculating required margin
 - CalculateProfit() - calculating potential profit
 
 STREAMING:
 - StreamTicks() - tick stream
+- StreamQuotes() - bid/ask/time only, pooled, no per-tick allocation
+- StreamTickBatches() - struct-of-arrays tick batches
 - StreamTrades() - trade stream
 - StreamPositionProfits() - position profit stream
 - StreamTicketChanges() - ticket change stream
 - StreamTradeTransactions() - trade transaction stream
+- StreamTicksWithLag() - tick stream with lag measurement and stall reconnect
+- StreamPositionProfitsWithLag() - profit stream with heartbeat reconnect
+- StreamTransactionsSequenced() - deduplicated, per-order ordered transactions
+- StreamTradeUpdatesDeduped() - trade stream without replayed messages
 */
 
 import (
 	"context"
 	"fmt"
+	"math"
+	"slices"
 	"time"
 
 	pb "github.com/MetaRPC/GoMT5/package"
@@ -77,6 +100,7 @@ import (
 // This layer unwraps protobuf and provides convenient request builders.
 type MT5Service struct {
 	account *helpers.MT5Account
+	symbols *SuffixResolver // Canonical → broker symbol names (nil = names used as given)
 }
 
 // NewMT5Service creates a new MT5Service wrapping an MT5Account instance.
@@ -134,6 +158,7 @@ type SymbolMarginRate struct {
 // ADVANTAGE: Clean Go struct with time.Time instead of protobuf SymbolInfoTickData.
 // Time is already converted from Unix timestamp to time.Time.
 type SymbolTick struct {
+	Symbol     string    // Symbol name
 	Time       time.Time // Tick time (converted from Unix timestamp)
 	Bid        float64   // Current Bid price
 	Ask        float64   // Current Ask price
@@ -174,6 +199,7 @@ type SymbolParams struct {
 	SwapShort            float64 // Swap for short positions
 	MarginInitial        float64 // Initial margin requirement
 	MarginMaintenance    float64 // Maintenance margin requirement
+	FillingModes         []pb.BMT5_ENUM_ORDER_TYPE_FILLING // Filling policies allowed by the broker
 }
 
 // BookInfo holds a single Depth of Market (DOM) price level entry.
@@ -185,21 +211,54 @@ type BookInfo struct {
 	VolumeReal float64     // Volume with decimal precision
 }
 
+// SendResult is the broker's answer to an order send or modify, mapped from
+// OrderSendData / OrderModifyData. Service returns it inside OrderResult,
+// Sugar through SendOrderResult and TradeRejectedError.
+//
+// A rejected order is not a transport error: check OK() or Err().
+type SendResult struct {
+	ReturnedCode            uint32  // Operation return code (10009 = TRADE_RETCODE_DONE)
+	ReturnedStringCode      string  // Return code name, e.g. "TRADE_RETCODE_DONE" ("" if the server sent none)
+	ReturnedCodeDescription string  // Return code description (built-in text if the server sent none)
+	Deal                    uint64  // Deal ticket number (if executed)
+	Order                   uint64  // Order ticket number (if placed)
+	Volume                  float64 // Executed volume confirmed by broker
+	Price                   float64 // Execution price confirmed by broker
+	Bid                     float64 // Current Bid price
+	Ask                     float64 // Current Ask price
+	Comment                 string  // Broker comment or error description
+	RequestID               uint32  // Request ID set by terminal
+	RetCodeExternal         int32   // Return code from external trading system
+
+	op string // "order" or "modify", for TradeRejectedError
+}
+
 // OrderResult holds the result of a trading operation.
 //
 // ADVANTAGE: Clean Go struct instead of protobuf OrderSendData/OrderModifyData.
 // All fields in convenient Go types.
 type OrderResult struct {
-	ReturnedCode    uint32  // Operation return code (10009 = TRADE_RETCODE_DONE)
-	Deal            uint64  // Deal ticket number (if executed)
-	Order           uint64  // Order ticket number (if placed)
-	Volume          float64 // Executed volume confirmed by broker
-	Price           float64 // Execution price confirmed by broker
-	Bid             float64 // Current Bid price
-	Ask             float64 // Current Ask price
-	Comment         string  // Broker comment or error description
-	RequestID       uint32  // Request ID set by terminal
-	RetCodeExternal int32   // Return code from external trading system
+	SendResult
+
+	Attempts []OrderAttempt // Every submission made (set by SendOrderWithRequoteRetry)
+}
+
+// OrderAttempt records a single order submission made during requote retries.
+type OrderAttempt struct {
+	Price        float64 // Price sent with this attempt (0 = market)
+	ReturnedCode uint32  // Return code received for this attempt
+	Comment      string  // Broker comment for this attempt
+}
+
+// RequoteRetryOptions controls automatic resubmission on requotes.
+//
+// On TRADE_RETCODE_REQUOTE (10004) or TRADE_RETCODE_PRICE_CHANGED (10020) the
+// current price is re-fetched and the order is sent again, as long as the new
+// price stays within MaxPriceDeviation of the first price seen.
+type RequoteRetryOptions struct {
+	MaxAttempts       int           // Total submissions including the first one (default 3)
+	MaxPriceDeviation float64       // Max distance from the original price in price units (0 = unlimited)
+	Delay             time.Duration // Pause before each resubmission (default 100ms)
 }
 
 // OrderCheckResult holds the result of order pre-validation.
@@ -217,6 +276,233 @@ type OrderCheckResult struct {
 	Comment      string  // Error description (if validation failed)
 }
 
+// OrderRequest describes an order with plain Go fields instead of protobuf pointers.
+//
+// ADVANTAGE: No &price / &sl pointer juggling and no long positional argument lists.
+// Zero values mean "not set": Price 0 = market price, StopLoss 0 = no SL, etc.
+// TimeType defaults to GTC (good till cancelled), which every broker accepts.
+type OrderRequest struct {
+	Symbol         string                       // Trading symbol (REQUIRED)
+	Type           pb.TMT5_ENUM_ORDER_TYPE      // Order type (BUY, SELL, BUY_LIMIT, ...)
+	Volume         float64                      // Volume in lots (REQUIRED, > 0)
+	Price          float64                      // Entry price (REQUIRED for pending orders, 0 = market)
+	StopLimitPrice float64                      // Limit price for STOP_LIMIT orders
+	StopLoss       float64                      // Stop Loss price (0 = none)
+	TakeProfit     float64                      // Take Profit price (0 = none)
+	Slippage       uint64                       // Max deviation from requested price in points (0 = server default)
+	Comment        string                       // Order comment
+	Magic          uint64                       // Expert Advisor ID (magic number)
+	TimeType       pb.TMT5_ENUM_ORDER_TYPE_TIME // Expiration type (default GTC)
+	Expiration     time.Time                    // Expiration time (REQUIRED for SPECIFIED / SPECIFIED_DAY)
+
+	// Filling policy (FOK/IOC/RETURN/BOC). nil = pick automatically from symbol settings.
+	// NOTE: OrderSendRequest has no filling field - the terminal applies the symbol's
+	// filling mode on send, so a send cannot be retried with another mode. SendOrder
+	// refuses a Filling the symbol does not allow (ErrFillingNotAllowed) before sending.
+	// Filling is honored by OrderCheck (see CheckOrderRequest).
+	Filling *pb.MRPC_ENUM_ORDER_TYPE_FILLING
+}
+
+// ConnectOptions describes a terminal connection for all connect variants.
+//
+// ADVANTAGE: One struct instead of three protobuf requests with different fields.
+// Which RPC is used depends on what is set: Proxy → ConnectProxy,
+// Host → Connect (host:port), otherwise ServerName → ConnectEx (cluster name).
+type ConnectOptions struct {
+	User            uint64              // Account number (REQUIRED)
+	Password        string              // Account password (REQUIRED)
+	ServerName      string              // MT5 cluster/server name (e.g., "FxPro-MT5 Demo")
+	Host            string              // Trade server IP or DNS name (instead of ServerName)
+	Port            int32               // Trade server port (REQUIRED with Host, usually 443)
+	Proxy           *ProxyOptions       // Proxy to connect through (requires Host/Port)
+	BaseChartSymbol string              // Base chart symbol (ServerName connections only, "" = server default)
+	Experts         []*pb.ExpertAdviser // Expert Advisors to add to the terminal
+	TimeoutSeconds  uint32              // Terminal readiness timeout (0 = server default, 120s)
+}
+
+// ProxyOptions describes the proxy used by ConnectByProxy.
+type ProxyOptions struct {
+	Host     string        // Proxy host (REQUIRED)
+	Port     uint32        // Proxy port (REQUIRED)
+	User     string        // Proxy login (optional)
+	Password string        // Proxy password (optional)
+	Type     pb.ProxyTypes // HTTPS, SOCKS4 or SOCKS5
+}
+
+// ConnectResult holds the identity of the connected terminal instance.
+//
+// ADVANTAGE: Same result type for every connect variant
+// (ConnectData and ConnectProxyData differ in protobuf).
+type ConnectResult struct {
+	TerminalID   string          // Terminal instance GUID / unique identifier
+	TerminalType pb.TerminalType // Terminal type reported by the server
+}
+
+// #endregion
+
+// ══════════════════════════════════════════════════════════════════════════════
+// #region CONNECTION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Connect connects using whichever variant ConnectOptions describes:
+// proxy if Proxy is set, host:port if Host is set, cluster name otherwise.
+//
+// Parameters:
+//   - ctx: Context for timeout and cancellation
+//   - opts: Connection options (credentials + server name, host:port or proxy)
+//
+// Returns:
+//   - ConnectResult with terminal identity
+//   - Error if options are incomplete or connection failed
+func (s *MT5Service) Connect(ctx context.Context, opts ConnectOptions) (*ConnectResult, error) {
+	switch {
+	case opts.Proxy != nil:
+		return s.ConnectByProxy(ctx, opts)
+	case opts.Host != "":
+		return s.ConnectByHostPort(ctx, opts)
+	default:
+		return s.ConnectByServerName(ctx, opts)
+	}
+}
+
+// ConnectByServerName connects by MT5 cluster/server name (MT5Account.ConnectEx).
+//
+// Parameters:
+//   - ctx: Context for timeout and cancellation
+//   - opts: User, Password, ServerName (REQUIRED); BaseChartSymbol, Experts, TimeoutSeconds
+//
+// Returns:
+//   - ConnectResult with terminal identity
+//   - Error if connection failed
+func (s *MT5Service) ConnectByServerName(ctx context.Context, opts ConnectOptions) (*ConnectResult, error) {
+	if err := opts.validate(); err != nil {
+		return nil, fmt.Errorf("ConnectByServerName failed: %w", err)
+	}
+	if opts.ServerName == "" {
+		return nil, fmt.Errorf("ConnectByServerName failed: server name is required")
+	}
+
+	req := &pb.ConnectExRequest{
+		User:          opts.User,
+		Password:      opts.Password,
+		MtClusterName: opts.ServerName,
+		ExpertsToAdd:  opts.Experts,
+	}
+	if opts.BaseChartSymbol != "" {
+		req.BaseChartSymbol = &opts.BaseChartSymbol
+	}
+	if opts.TimeoutSeconds > 0 {
+		req.TimeoutSeconds = &opts.TimeoutSeconds
+	}
+
+	data, err := s.account.ConnectEx(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("ConnectByServerName failed: %w", err)
+	}
+
+	return &ConnectResult{
+		TerminalID:   data.TerminalInstanceGuid,
+		TerminalType: data.TerminalType,
+	}, nil
+}
+
+// ConnectByHostPort connects directly to a trade server address (MT5Account.Connect).
+//
+// Parameters:
+//   - ctx: Context for timeout and cancellation
+//   - opts: User, Password, Host, Port (REQUIRED); Experts, TimeoutSeconds
+//
+// Returns:
+//   - ConnectResult with terminal identity
+//   - Error if connection failed
+func (s *MT5Service) ConnectByHostPort(ctx context.Context, opts ConnectOptions) (*ConnectResult, error) {
+	if err := opts.validate(); err != nil {
+		return nil, fmt.Errorf("ConnectByHostPort failed: %w", err)
+	}
+	if opts.Host == "" || opts.Port <= 0 {
+		return nil, fmt.Errorf("ConnectByHostPort failed: host and port are required")
+	}
+
+	req := &pb.ConnectRequest{
+		User:         opts.User,
+		Password:     opts.Password,
+		Host:         opts.Host,
+		Port:         opts.Port,
+		ExpertsToAdd: opts.Experts,
+	}
+	if opts.TimeoutSeconds > 0 {
+		req.TimeoutSeconds = &opts.TimeoutSeconds
+	}
+
+	data, err := s.account.Connect(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("ConnectByHostPort failed: %w", err)
+	}
+
+	return &ConnectResult{
+		TerminalID:   data.TerminalInstanceGuid,
+		TerminalType: data.TerminalType,
+	}, nil
+}
+
+// ConnectByProxy connects to a trade server through a proxy (MT5Account.ConnectProxy).
+//
+// Parameters:
+//   - ctx: Context for timeout and cancellation
+//   - opts: User, Password, Host, Port, Proxy (REQUIRED); Experts, TimeoutSeconds
+//
+// Returns:
+//   - ConnectResult with terminal identity
+//   - Error if connection failed
+func (s *MT5Service) ConnectByProxy(ctx context.Context, opts ConnectOptions) (*ConnectResult, error) {
+	if err := opts.validate(); err != nil {
+		return nil, fmt.Errorf("ConnectByProxy failed: %w", err)
+	}
+	if opts.Host == "" || opts.Port <= 0 {
+		return nil, fmt.Errorf("ConnectByProxy failed: host and port are required")
+	}
+	if opts.Proxy == nil || opts.Proxy.Host == "" || opts.Proxy.Port == 0 {
+		return nil, fmt.Errorf("ConnectByProxy failed: proxy host and port are required")
+	}
+
+	req := &pb.ConnectProxyRequest{
+		User:          opts.User,
+		Password:      opts.Password,
+		Host:          opts.Host,
+		Port:          opts.Port,
+		ProxyUser:     opts.Proxy.User,
+		ProxyPassword: opts.Proxy.Password,
+		ProxyHost:     opts.Proxy.Host,
+		ProxyPort:     opts.Proxy.Port,
+		ProxyType:     opts.Proxy.Type,
+		ExpertsToAdd:  opts.Experts,
+	}
+	if opts.TimeoutSeconds > 0 {
+		req.TimeoutSeconds = &opts.TimeoutSeconds
+	}
+
+	data, err := s.account.ConnectProxy(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("ConnectByProxy failed: %w", err)
+	}
+
+	return &ConnectResult{
+		TerminalID:   data.UniqueIdentifier,
+		TerminalType: data.TerminalType,
+	}, nil
+}
+
+// validate checks the credentials shared by all connect variants.
+func (o ConnectOptions) validate() error {
+	if o.User == 0 {
+		return fmt.Errorf("user is required")
+	}
+	if o.Password == "" {
+		return fmt.Errorf("password is required")
+	}
+	return nil
+}
+
 // #endregion
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -386,6 +672,7 @@ func (s *MT5Service) GetSymbolsTotal(ctx context.Context, selectedOnly bool) (in
 // SymbolExist checks if a symbol exists in the terminal.
 // Returns (exists, isCustom, error). Use this before working with a symbol.
 func (s *MT5Service) SymbolExist(ctx context.Context, symbol string) (bool, bool, error) {
+	symbol = s.resolveSymbol(symbol)
 	req := &pb.SymbolExistRequest{
 		Name: symbol,
 	}
@@ -417,6 +704,7 @@ func (s *MT5Service) GetSymbolName(ctx context.Context, index int32, selectedOnl
 // SymbolSelect adds or removes a symbol from Market Watch window.
 // Returns true if operation successful. Use select_=true to add, false to remove.
 func (s *MT5Service) SymbolSelect(ctx context.Context, symbol string, select_ bool) (bool, error) {
+	symbol = s.resolveSymbol(symbol)
 	req := &pb.SymbolSelectRequest{
 		Symbol: symbol,
 		Select: select_,
@@ -433,6 +721,7 @@ func (s *MT5Service) SymbolSelect(ctx context.Context, symbol string, select_ bo
 // IsSymbolSynchronized checks if symbol data is synchronized with the trade server.
 // Returns true if symbol is fully synchronized and ready for trading operations.
 func (s *MT5Service) IsSymbolSynchronized(ctx context.Context, symbol string) (bool, error) {
+	symbol = s.resolveSymbol(symbol)
 	req := &pb.SymbolIsSynchronizedRequest{
 		Symbol: symbol,
 	}
@@ -448,6 +737,7 @@ func (s *MT5Service) IsSymbolSynchronized(ctx context.Context, symbol string) (b
 // GetSymbolDouble retrieves a double-type symbol property (Bid, Ask, Point, etc.).
 // Returns float64 value directly. For multiple properties use GetSymbolParamsMany instead.
 func (s *MT5Service) GetSymbolDouble(ctx context.Context, symbol string, property pb.SymbolInfoDoubleProperty) (float64, error) {
+	symbol = s.resolveSymbol(symbol)
 	req := &pb.SymbolInfoDoubleRequest{
 		Symbol: symbol,
 		Type:   property,
@@ -464,6 +754,7 @@ func (s *MT5Service) GetSymbolDouble(ctx context.Context, symbol string, propert
 // GetSymbolInteger retrieves an integer-type symbol property (Digits, Spread, etc.).
 // Returns int64 value directly. For multiple properties use GetSymbolParamsMany instead.
 func (s *MT5Service) GetSymbolInteger(ctx context.Context, symbol string, property pb.SymbolInfoIntegerProperty) (int64, error) {
+	symbol = s.resolveSymbol(symbol)
 	req := &pb.SymbolInfoIntegerRequest{
 		Symbol: symbol,
 		Type:   property,
@@ -480,6 +771,7 @@ func (s *MT5Service) GetSymbolInteger(ctx context.Context, symbol string, proper
 // GetSymbolString retrieves a string-type symbol property (Description, Path, etc.).
 // Returns string value directly.
 func (s *MT5Service) GetSymbolString(ctx context.Context, symbol string, property pb.SymbolInfoStringProperty) (string, error) {
+	symbol = s.resolveSymbol(symbol)
 	req := &pb.SymbolInfoStringRequest{
 		Symbol: symbol,
 		Type:   property,
@@ -508,6 +800,7 @@ func (s *MT5Service) GetSymbolString(ctx context.Context, symbol string, propert
 //   - SymbolMarginRate struct with margin rates
 //   - Error if request failed
 func (s *MT5Service) GetSymbolMarginRate(ctx context.Context, symbol string, orderType pb.ENUM_ORDER_TYPE) (*SymbolMarginRate, error) {
+	symbol = s.resolveSymbol(symbol)
 	req := &pb.SymbolInfoMarginRateRequest{
 		Symbol:    symbol,
 		OrderType: orderType,
@@ -539,6 +832,7 @@ func (s *MT5Service) GetSymbolMarginRate(ctx context.Context, symbol string, ord
 //   - SymbolTick struct with current tick data
 //   - Error if request failed
 func (s *MT5Service) GetSymbolTick(ctx context.Context, symbol string) (*SymbolTick, error) {
+	symbol = s.resolveSymbol(symbol)
 	req := &pb.SymbolInfoTickRequest{
 		Symbol: symbol,
 	}
@@ -549,6 +843,7 @@ func (s *MT5Service) GetSymbolTick(ctx context.Context, symbol string) (*SymbolT
 	}
 
 	return &SymbolTick{
+		Symbol:     symbol,
 		Time:       time.Unix(data.Time, 0),
 		Bid:        data.Bid,
 		Ask:        data.Ask,
@@ -576,6 +871,7 @@ func (s *MT5Service) GetSymbolTick(ctx context.Context, symbol string) (*SymbolT
 //   - SessionTime struct with From/To times
 //   - Error if request failed
 func (s *MT5Service) GetSymbolSessionQuote(ctx context.Context, symbol string, dayOfWeek pb.DayOfWeek, sessionIndex uint32) (*SessionTime, error) {
+	symbol = s.resolveSymbol(symbol)
 	req := &pb.SymbolInfoSessionQuoteRequest{
 		Symbol:       symbol,
 		DayOfWeek:    dayOfWeek,
@@ -596,6 +892,7 @@ func (s *MT5Service) GetSymbolSessionQuote(ctx context.Context, symbol string, d
 // GetSymbolSessionTrade retrieves trade session times for a symbol.
 // Similar to GetSymbolSessionQuote but for trading sessions instead of quote sessions.
 func (s *MT5Service) GetSymbolSessionTrade(ctx context.Context, symbol string, dayOfWeek pb.DayOfWeek, sessionIndex uint32) (*SessionTime, error) {
+	symbol = s.resolveSymbol(symbol)
 	req := &pb.SymbolInfoSessionTradeRequest{
 		Symbol:       symbol,
 		DayOfWeek:    dayOfWeek,
@@ -635,6 +932,10 @@ func (s *MT5Service) GetSymbolSessionTrade(ctx context.Context, symbol string, d
 //   - Total number of symbols matching the filter
 //   - Error if request failed
 func (s *MT5Service) GetSymbolParamsMany(ctx context.Context, symbolName *string, sortType *pb.AH_SYMBOL_PARAMS_MANY_SORT_TYPE, pageNumber *int32, itemsPerPage *int32) ([]SymbolParams, int32, error) {
+	if symbolName != nil {
+		resolved := s.resolveSymbol(*symbolName)
+		symbolName = &resolved
+	}
 	req := &pb.SymbolParamsManyRequest{
 		SymbolName:   symbolName,
 		SortType:     sortType,
@@ -667,11 +968,47 @@ func (s *MT5Service) GetSymbolParamsMany(ctx context.Context, symbolName *string
 			SwapShort:         info.SwapShort,
 			MarginInitial:     info.MarginInitial,
 			MarginMaintenance: info.MarginMaintenance,
+			FillingModes:      info.FillingMode,
 		}
 	}
 
 	return symbols, data.SymbolsTotal, nil
 }
+
+// GetSymbolFillingModes returns the filling policies the broker allows for a symbol.
+//
+// ADVANTAGE over GetSymbolParamsMany:
+//   - Single symbol lookup, no paging parameters
+//   - Returns MRPC filling enum ready to use in OrderRequest.Filling
+//
+// Parameters:
+//   - ctx: Context for timeout and cancellation
+//   - symbol: Symbol name
+//
+// Returns:
+//   - Allowed filling modes (may be empty if broker does not report them)
+//   - Error if request failed or symbol not found
+func (s *MT5Service) GetSymbolFillingModes(ctx context.Context, symbol string) ([]pb.MRPC_ENUM_ORDER_TYPE_FILLING, error) {
+	symbol = s.resolveSymbol(symbol)
+	symbols, _, err := s.GetSymbolParamsMany(ctx, &symbol, nil, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetSymbolFillingModes failed: %w", err)
+	}
+
+	for _, info := range symbols {
+		if info.Name != symbol {
+			continue
+		}
+		// BMT5 and MRPC filling enums share numeric values (FOK=0, IOC=1, RETURN=2, BOC=3)
+		modes := make([]pb.MRPC_ENUM_ORDER_TYPE_FILLING, len(info.FillingModes))
+		for i, m := range info.FillingModes {
+			modes[i] = pb.MRPC_ENUM_ORDER_TYPE_FILLING(m)
+		}
+		return modes, nil
+	}
+
+	return nil, fmt.Errorf("GetSymbolFillingModes failed: symbol %s not found", symbol)
+}
 // #endregion
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -816,6 +1153,7 @@ func (s *MT5Service) GetPositionsHistory(ctx context.Context, sortType pb.AH_ENU
 // SubscribeMarketDepth subscribes to Depth of Market (DOM) updates for a symbol.
 // Must be called before GetMarketDepth. Returns true if subscription successful.
 func (s *MT5Service) SubscribeMarketDepth(ctx context.Context, symbol string) (bool, error) {
+	symbol = s.resolveSymbol(symbol)
 	req := &pb.MarketBookAddRequest{
 		Symbol: symbol,
 	}
@@ -831,6 +1169,7 @@ func (s *MT5Service) SubscribeMarketDepth(ctx context.Context, symbol string) (b
 // UnsubscribeMarketDepth unsubscribes from Depth of Market updates.
 // Call this to stop receiving DOM updates and free resources.
 func (s *MT5Service) UnsubscribeMarketDepth(ctx context.Context, symbol string) (bool, error) {
+	symbol = s.resolveSymbol(symbol)
 	req := &pb.MarketBookReleaseRequest{
 		Symbol: symbol,
 	}
@@ -846,6 +1185,7 @@ func (s *MT5Service) UnsubscribeMarketDepth(ctx context.Context, symbol string)
 // GetMarketDepth retrieves current Depth of Market (DOM) snapshot for a symbol.
 // Requires prior SubscribeMarketDepth call. Returns slice of BookInfo entries.
 func (s *MT5Service) GetMarketDepth(ctx context.Context, symbol string) ([]BookInfo, error) {
+	symbol = s.resolveSymbol(symbol)
 	req := &pb.MarketBookGetRequest{
 		Symbol: symbol,
 	}
@@ -887,24 +1227,305 @@ func (s *MT5Service) GetMarketDepth(ctx context.Context, symbol string) ([]BookI
 // Returns:
 //   - OrderResult struct with execution details
 //   - Error if request failed
+//
+// Deprecated: Optional fields are protobuf pointers and nothing is checked
+// before the request reaches the broker. Use SendOrder with an OrderRequest.
 func (s *MT5Service) PlaceOrder(ctx context.Context, req *pb.OrderSendRequest) (*OrderResult, error) {
+	req.Symbol = s.resolveSymbol(req.Symbol)
 	data, err := s.account.OrderSend(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("PlaceOrder failed: %w", err)
 	}
 
-	return &OrderResult{
-		ReturnedCode:    data.ReturnedCode,
-		Deal:            data.Deal,
-		Order:           data.Order,
-		Volume:          data.Volume,
-		Price:           data.Price,
-		Bid:             data.Bid,
-		Ask:             data.Ask,
-		Comment:         data.Comment,
-		RequestID:       data.RequestId,
-		RetCodeExternal: data.RetCodeExternal,
-	}, nil
+	return &OrderResult{SendResult: newSendResult(data)}, nil
+}
+
+// SendOrder sends an order described by an OrderRequest struct.
+//
+// ADVANTAGE over PlaceOrder:
+//   - Plain Go fields instead of protobuf pointers
+//   - Request is validated locally before anything is sent to the broker
+//   - Sensible defaults (GTC expiration, market price when Price is 0)
+//
+// Parameters:
+//   - ctx: Context for timeout and cancellation
+//   - req: Order description (see OrderRequest)
+//
+// Returns:
+//   - OrderResult struct with execution details
+//   - Error if validation or request failed
+func (s *MT5Service) SendOrder(ctx context.Context, req OrderRequest) (*OrderResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("SendOrder failed: %w", err)
+	}
+	if err := s.checkFilling(ctx, req); err != nil {
+		return nil, fmt.Errorf("SendOrder failed: %w", err)
+	}
+
+	result, err := s.PlaceOrder(ctx, req.ToProto())
+	if err != nil {
+		return nil, fmt.Errorf("SendOrder failed: %w", err)
+	}
+
+	return result, nil
+}
+
+// checkFilling refuses an explicit req.Filling that the symbol does not allow.
+// The terminal sends with the symbol's own filling mode, so such a request
+// could not be honored. Nil Filling, or a symbol that reports no modes, passes.
+func (s *MT5Service) checkFilling(ctx context.Context, req OrderRequest) error {
+	if req.Filling == nil {
+		return nil
+	}
+	allowed, err := s.GetSymbolFillingModes(ctx, req.Symbol)
+	if err != nil {
+		return err
+	}
+	if len(allowed) == 0 || slices.Contains(allowed, *req.Filling) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s allows %v, not %s", ErrFillingNotAllowed, req.Symbol, allowed, *req.Filling)
+}
+
+// invalidFillError explains a TRADE_RETCODE_INVALID_FILL reply to req: the
+// filling modes the symbol allows and, found with CheckOrderRequest, the one
+// the broker accepts. The error wraps result.Err().
+func (s *MT5Service) invalidFillError(ctx context.Context, req OrderRequest, result *OrderResult) error {
+	modes, _ := s.GetSymbolFillingModes(ctx, req.Symbol)
+
+	req.Filling = nil
+	check, filling, err := s.CheckOrderRequest(ctx, req)
+	if err != nil || check.ReturnedCode == helpers.TradeRetCodeInvalidFill {
+		return fmt.Errorf("%w (symbol allows filling modes: %v)", result.Err(), modes)
+	}
+	return fmt.Errorf("%w (symbol allows filling modes: %v, broker accepts %s)", result.Err(), modes, filling)
+}
+
+// SendOrderWithRequoteRetry sends an order and resubmits it at the fresh price
+// when the broker answers with a requote.
+//
+// ADVANTAGE over SendOrder:
+//   - Requotes (10004) and price changes (10020) are retried automatically
+//   - Retries stop once price drifts beyond opts.MaxPriceDeviation
+//   - Every submission is reported in OrderResult.Attempts
+//
+// Market orders are resubmitted at current Ask (BUY) or Bid (SELL).
+// Pending orders keep their price, since a requote there means the level itself is wrong.
+//
+// Parameters:
+//   - ctx: Context for timeout and cancellation
+//   - req: Order description (see OrderRequest)
+//   - opts: Retry limits (see RequoteRetryOptions)
+//
+// Returns:
+//   - OrderResult of the last submission, with all attempts listed
+//   - Error if validation or a request failed
+func (s *MT5Service) SendOrderWithRequoteRetry(ctx context.Context, req OrderRequest, opts RequoteRetryOptions) (*OrderResult, error) {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 3
+	}
+	if opts.Delay <= 0 {
+		opts.Delay = 100 * time.Millisecond
+	}
+
+	isMarket := req.Type == pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY || req.Type == pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL
+	originalPrice := req.Price
+	attempts := make([]OrderAttempt, 0, opts.MaxAttempts)
+
+	var result *OrderResult
+	for attempt := 1; attempt <= opts.MaxAttempts; attempt++ {
+		var err error
+		result, err = s.SendOrder(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("SendOrderWithRequoteRetry failed: %w", err)
+		}
+
+		attempts = append(attempts, OrderAttempt{
+			Price:        req.Price,
+			ReturnedCode: result.ReturnedCode,
+			Comment:      result.Comment,
+		})
+
+		if !helpers.IsRetCodeRequote(result.ReturnedCode) || !isMarket || attempt == opts.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			result.Attempts = attempts
+			return result, ctx.Err()
+		case <-time.After(opts.Delay):
+		}
+
+		tick, err := s.GetSymbolTick(ctx, req.Symbol)
+		if err != nil {
+			return nil, fmt.Errorf("SendOrderWithRequoteRetry failed: %w", err)
+		}
+
+		newPrice := tick.Bid
+		if req.Type == pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY {
+			newPrice = tick.Ask
+		}
+		if originalPrice == 0 {
+			// First requote on a market order: its quote becomes the reference price
+			originalPrice = result.Ask
+			if req.Type == pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL {
+				originalPrice = result.Bid
+			}
+			if originalPrice == 0 {
+				originalPrice = newPrice
+			}
+		}
+
+		if opts.MaxPriceDeviation > 0 && math.Abs(newPrice-originalPrice) > opts.MaxPriceDeviation {
+			break
+		}
+		req.Price = newPrice
+	}
+
+	result.Attempts = attempts
+	return result, nil
+}
+
+// Validate checks an OrderRequest for missing or inconsistent fields.
+// Returns nil if the request can be sent.
+func (r OrderRequest) Validate() error {
+	if r.Symbol == "" {
+		return fmt.Errorf("symbol is required")
+	}
+	if r.Volume <= 0 {
+		return fmt.Errorf("volume must be positive, got %.2f", r.Volume)
+	}
+	if r.Price < 0 || r.StopLoss < 0 || r.TakeProfit < 0 || r.StopLimitPrice < 0 {
+		return fmt.Errorf("prices must not be negative")
+	}
+
+	switch r.Type {
+	case pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY,
+		pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL:
+		// Market orders: Price is optional
+	case pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_LIMIT,
+		pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL_LIMIT,
+		pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_STOP,
+		pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL_STOP:
+		if r.Price == 0 {
+			return fmt.Errorf("price is required for %s orders", r.Type)
+		}
+	case pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_STOP_LIMIT,
+		pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL_STOP_LIMIT:
+		if r.Price == 0 || r.StopLimitPrice == 0 {
+			return fmt.Errorf("price and stop limit price are required for %s orders", r.Type)
+		}
+	default:
+		return fmt.Errorf("unsupported order type: %s", r.Type)
+	}
+
+	switch r.TimeType {
+	case pb.TMT5_ENUM_ORDER_TYPE_TIME_TMT5_ORDER_TIME_SPECIFIED,
+		pb.TMT5_ENUM_ORDER_TYPE_TIME_TMT5_ORDER_TIME_SPECIFIED_DAY:
+		if r.Expiration.IsZero() {
+			return fmt.Errorf("expiration time is required for %s", r.TimeType)
+		}
+	}
+
+	return nil
+}
+
+// ToProto converts an OrderRequest to protobuf OrderSendRequest.
+// Zero-valued optional fields are left unset so the server applies its defaults.
+func (r OrderRequest) ToProto() *pb.OrderSendRequest {
+	req := &pb.OrderSendRequest{
+		Symbol:    r.Symbol,
+		Operation: r.Type,
+		Volume:    r.Volume,
+	}
+
+	if r.Price > 0 {
+		price := r.Price
+		req.Price = &price
+	}
+	if r.StopLimitPrice > 0 {
+		stopLimit := r.StopLimitPrice
+		req.StopLimitPrice = &stopLimit
+	}
+	if r.StopLoss > 0 {
+		sl := r.StopLoss
+		req.StopLoss = &sl
+	}
+	if r.TakeProfit > 0 {
+		tp := r.TakeProfit
+		req.TakeProfit = &tp
+	}
+	if r.Slippage > 0 {
+		slippage := r.Slippage
+		req.Slippage = &slippage
+	}
+	if r.Comment != "" {
+		comment := r.Comment
+		req.Comment = &comment
+	}
+	if r.Magic > 0 {
+		magic := r.Magic
+		req.ExpertId = &magic
+	}
+
+	timeType := r.TimeType
+	req.ExpirationTimeType = &timeType
+	if !r.Expiration.IsZero() {
+		req.ExpirationTime = timestamppb.New(r.Expiration)
+	}
+
+	return req
+}
+
+// ToCheckProto converts an OrderRequest to protobuf OrderCheckRequest.
+// Filling defaults to FOK when not set.
+func (r OrderRequest) ToCheckProto() *pb.OrderCheckRequest {
+	action := pb.MRPC_ENUM_TRADE_REQUEST_ACTIONS_TRADE_ACTION_PENDING
+	if r.Type == pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY || r.Type == pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL {
+		action = pb.MRPC_ENUM_TRADE_REQUEST_ACTIONS_TRADE_ACTION_DEAL
+	}
+
+	mql := &pb.MrpcMqlTradeRequest{
+		Action:                   action,
+		ExpertAdvisorMagicNumber: r.Magic,
+		Symbol:                   r.Symbol,
+		Volume:                   r.Volume,
+		Price:                    r.Price,
+		StopLimit:                r.StopLimitPrice,
+		StopLoss:                 r.StopLoss,
+		TakeProfit:               r.TakeProfit,
+		Deviation:                r.Slippage,
+		// TMT5 and TF/MRPC enums share numeric values
+		OrderType: pb.ENUM_ORDER_TYPE_TF(r.Type),
+		TypeTime:  pb.MRPC_ENUM_ORDER_TYPE_TIME(r.TimeType),
+		Comment:   r.Comment,
+	}
+	if r.Filling != nil {
+		mql.TypeFilling = *r.Filling
+	}
+	if !r.Expiration.IsZero() {
+		mql.Expiration = timestamppb.New(r.Expiration)
+	}
+
+	return &pb.OrderCheckRequest{MqlTradeRequest: mql}
+}
+
+// IsBuy reports whether the order opens or targets a long position.
+func (r OrderRequest) IsBuy() bool {
+	switch r.Type {
+	case pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY,
+		pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_LIMIT,
+		pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_STOP,
+		pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_STOP_LIMIT:
+		return true
+	}
+	return false
+}
+
+// IsMarket reports whether the order executes immediately at market price.
+func (r OrderRequest) IsMarket() bool {
+	return r.Type == pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY || r.Type == pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL
 }
 
 // ModifyOrder modifies an existing order or position (change SL/TP/price).
@@ -915,18 +1536,124 @@ func (s *MT5Service) ModifyOrder(ctx context.Context, req *pb.OrderModifyRequest
 		return nil, fmt.Errorf("ModifyOrder failed: %w", err)
 	}
 
-	return &OrderResult{
-		ReturnedCode:    data.ReturnedCode,
-		Deal:            data.Deal,
-		Order:           data.Order,
-		Volume:          data.Volume,
-		Price:           data.Price,
-		Bid:             data.Bid,
-		Ask:             data.Ask,
-		Comment:         data.Comment,
-		RequestID:       data.RequestId,
-		RetCodeExternal: data.RetCodeExternal,
-	}, nil
+	return &OrderResult{SendResult: newModifyResult(data)}, nil
+}
+
+// newSendResult maps an OrderSend reply.
+func newSendResult(data *pb.OrderSendData) SendResult {
+	return SendResult{
+		ReturnedCode:            data.ReturnedCode,
+		ReturnedStringCode:      data.ReturnedStringCode,
+		ReturnedCodeDescription: retCodeDescription(data.ReturnedCode, data.ReturnedCodeDescription),
+		Deal:                    data.Deal,
+		Order:                   data.Order,
+		Volume:                  data.Volume,
+		Price:                   data.Price,
+		Bid:                     data.Bid,
+		Ask:                     data.Ask,
+		Comment:                 data.Comment,
+		RequestID:               data.RequestId,
+		RetCodeExternal:         data.RetCodeExternal,
+		op:                      "order",
+	}
+}
+
+// newModifyResult maps an OrderModify reply.
+func newModifyResult(data *pb.OrderModifyData) SendResult {
+	return SendResult{
+		ReturnedCode:            data.ReturnedCode,
+		ReturnedStringCode:      data.ReturnedStringCode,
+		ReturnedCodeDescription: retCodeDescription(data.ReturnedCode, data.ReturnedCodeDescription),
+		Deal:                    data.Deal,
+		Order:                   data.Order,
+		Volume:                  data.Volume,
+		Price:                   data.Price,
+		Bid:                     data.Bid,
+		Ask:                     data.Ask,
+		Comment:                 data.Comment,
+		RequestID:               data.RequestId,
+		RetCodeExternal:         data.RetCodeExternal,
+		op:                      "modify",
+	}
+}
+
+// retCodeDescription returns the server's description, or the built-in one.
+func retCodeDescription(code uint32, fromServer string) string {
+	if fromServer != "" {
+		return fromServer
+	}
+	return helpers.GetRetCodeMessage(code)
+}
+
+// OK reports whether the request was executed (TRADE_RETCODE_DONE).
+func (r SendResult) OK() bool {
+	return helpers.IsRetCodeSuccess(r.ReturnedCode)
+}
+
+// Err returns nil if the request was executed, otherwise a *TradeRejectedError.
+func (r SendResult) Err() error {
+	if r.OK() {
+		return nil
+	}
+	return &TradeRejectedError{Result: r}
+}
+
+// String formats the result for logs, e.g.
+// "10009 TRADE_RETCODE_DONE (Request completed successfully) order #123 deal #124 0.10 @ 1.08512".
+func (r SendResult) String() string {
+	s := fmt.Sprintf("%d", r.ReturnedCode)
+	if r.ReturnedStringCode != "" {
+		s += " " + r.ReturnedStringCode
+	}
+	if r.ReturnedCodeDescription != "" {
+		s += " (" + r.ReturnedCodeDescription + ")"
+	}
+	if r.Order != 0 {
+		s += fmt.Sprintf(" order #%d", r.Order)
+	}
+	if r.Deal != 0 {
+		s += fmt.Sprintf(" deal #%d", r.Deal)
+	}
+	if r.Volume > 0 {
+		s += fmt.Sprintf(" %.2f @ %g", r.Volume, r.Price)
+	}
+	if r.Comment != "" {
+		s += ", comment: " + r.Comment
+	}
+	return s
+}
+
+// Explain returns the likely cause of the return code and a suggested fix.
+// Use MT5Sugar.ExplainErr to have the symbol's stops level filled in.
+func (r SendResult) Explain() helpers.Explanation {
+	return helpers.Explain(r.ReturnedCode)
+}
+
+// TradeRejectedError is returned when the trade server answers a request
+// with anything but TRADE_RETCODE_DONE. Result holds the full reply.
+type TradeRejectedError struct {
+	Result SendResult
+}
+
+func (e *TradeRejectedError) Error() string {
+	op := e.Result.op
+	if op == "" {
+		op = "order"
+	}
+	msg := fmt.Sprintf("%s rejected, code: %d", op, e.Result.ReturnedCode)
+	if e.Result.ReturnedCodeDescription != "" {
+		msg += " (" + e.Result.ReturnedCodeDescription + ")"
+	}
+	if e.Result.Comment != "" {
+		msg += ", comment: " + e.Result.Comment
+	}
+	return msg
+}
+
+// RetCode returns the trade return code, so helpers.ExplainErr can explain
+// the rejection.
+func (e *TradeRejectedError) RetCode() uint32 {
+	return e.Result.ReturnedCode
 }
 
 // CloseOrder closes a position or deletes a pending order.
@@ -957,6 +1684,9 @@ func (s *MT5Service) CloseOrder(ctx context.Context, req *pb.OrderCloseRequest)
 //   - OrderCheckResult with validation results
 //   - Error if request failed
 func (s *MT5Service) CheckOrder(ctx context.Context, req *pb.OrderCheckRequest) (*OrderCheckResult, error) {
+	if req.MqlTradeRequest != nil {
+		req.MqlTradeRequest.Symbol = s.resolveSymbol(req.MqlTradeRequest.Symbol)
+	}
 	data, err := s.account.OrderCheck(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("CheckOrder failed: %w", err)
@@ -975,9 +1705,68 @@ func (s *MT5Service) CheckOrder(ctx context.Context, req *pb.OrderCheckRequest)
 	}, nil
 }
 
+// CheckOrderRequest validates an OrderRequest with the broker, falling back to
+// another filling policy if the chosen one is rejected.
+//
+// ADVANTAGE over CheckOrder:
+//   - Takes OrderRequest instead of nested MrpcMqlTradeRequest
+//   - On TRADE_RETCODE_INVALID_FILL (10030) retries with every filling mode
+//     the symbol allows, until one is accepted
+//   - req.Filling == nil means "try symbol's allowed modes in broker order"
+//
+// Parameters:
+//   - ctx: Context for timeout and cancellation
+//   - req: Order description
+//
+// Returns:
+//   - OrderCheckResult of the last check performed
+//   - Filling mode that was used for that check
+//   - Error if validation or request failed
+func (s *MT5Service) CheckOrderRequest(ctx context.Context, req OrderRequest) (*OrderCheckResult, pb.MRPC_ENUM_ORDER_TYPE_FILLING, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, fmt.Errorf("CheckOrderRequest failed: %w", err)
+	}
+
+	candidates := []pb.MRPC_ENUM_ORDER_TYPE_FILLING{}
+	if req.Filling != nil {
+		candidates = append(candidates, *req.Filling)
+	}
+
+	allowed, err := s.GetSymbolFillingModes(ctx, req.Symbol)
+	if err == nil {
+		for _, mode := range allowed {
+			if req.Filling == nil || mode != *req.Filling {
+				candidates = append(candidates, mode)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = append(candidates, pb.MRPC_ENUM_ORDER_TYPE_FILLING_ORDER_FILLING_FOK)
+	}
+
+	var result *OrderCheckResult
+	var filling pb.MRPC_ENUM_ORDER_TYPE_FILLING
+	for _, mode := range candidates {
+		mode := mode
+		req.Filling = &mode
+		filling = mode
+
+		result, err = s.CheckOrder(ctx, req.ToCheckProto())
+		if err != nil {
+			return nil, filling, fmt.Errorf("CheckOrderRequest failed: %w", err)
+		}
+		if result.ReturnedCode != helpers.TradeRetCodeInvalidFill {
+			break
+		}
+	}
+
+	return result, filling, nil
+}
+
 // CalculateMargin calculates required margin for a potential order.
 // Use this before placing orders to check if you have enough free margin.
 func (s *MT5Service) CalculateMargin(ctx context.Context, req *pb.OrderCalcMarginRequest) (float64, error) {
+	req.Symbol = s.resolveSymbol(req.Symbol)
 	data, err := s.account.OrderCalcMargin(ctx, req)
 	if err != nil {
 		return 0, fmt.Errorf("CalculateMargin failed: %w", err)
@@ -989,6 +1778,7 @@ func (s *MT5Service) CalculateMargin(ctx context.Context, req *pb.OrderCalcMargi
 // CalculateProfit calculates potential profit for a hypothetical order.
 // Useful for profit/risk calculations before placing actual orders.
 func (s *MT5Service) CalculateProfit(ctx context.Context, req *pb.OrderCalcProfitRequest) (float64, error) {
+	req.Symbol = s.resolveSymbol(req.Symbol)
 	data, err := s.account.OrderCalcProfit(ctx, req)
 	if err != nil {
 		return 0, fmt.Errorf("CalculateProfit failed: %w", err)
@@ -1021,8 +1811,12 @@ func (s *MT5Service) CalculateProfit(ctx context.Context, req *pb.OrderCalcProfi
 //   - Read-only channel of *SymbolTick structs
 //   - Read-only channel of errors
 func (s *MT5Service) StreamTicks(ctx context.Context, symbols []string) (<-chan *SymbolTick, <-chan error) {
+	names := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		names[i] = s.resolveSymbol(symbol)
+	}
 	req := &pb.OnSymbolTickRequest{
-		SymbolNames: symbols,
+		SymbolNames: names,
 	}
 
 	dataCh, errCh := s.account.OnSymbolTick(ctx, req)
@@ -1042,6 +1836,7 @@ func (s *MT5Service) StreamTicks(ctx context.Context, symbols []string) (<-chan
 				}
 				tick := data.SymbolTick
 				tickCh <- &SymbolTick{
+					Symbol:     tick.Symbol,
 					Time:       tick.Time.AsTime(),
 					Bid:        tick.Bid,
 					Ask:        tick.Ask,
@@ -1067,6 +1862,47 @@ func (s *MT5Service) StreamTicks(ctx context.Context, symbols []string) (<-chan
 	return tickCh, outErrCh
 }
 
+// StreamQuotes streams bid/ask/time of every tick as plain values.
+//
+// ADVANTAGE over StreamTicks:
+//   - No per-tick allocation (reply messages are pooled, values are copied)
+//   - For consumers that only need prices (spread monitors, signal engines)
+//
+// Parameters:
+//   - ctx: Context for cancellation (closing ctx stops the stream)
+//   - symbols: Symbol names to stream
+//
+// Returns:
+//   - Read-only channel of helpers.TickQuote values
+//   - Read-only channel of errors
+func (s *MT5Service) StreamQuotes(ctx context.Context, symbols []string) (<-chan helpers.TickQuote, <-chan error) {
+	names := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		names[i] = s.resolveSymbol(symbol)
+	}
+	return s.account.OnSymbolTickQuotes(ctx, &pb.OnSymbolTickRequest{SymbolNames: names})
+}
+
+// StreamTickBatches streams ticks in struct-of-arrays batches.
+// Call Release on every batch once processed.
+//
+// Parameters:
+//   - ctx: Context for cancellation (closing ctx stops the stream)
+//   - symbols: Symbol names to stream
+//   - size: Max ticks per batch
+//   - maxWait: Deliver a partial batch this long after its first tick (0 = full batches only)
+//
+// Returns:
+//   - Read-only channel of *helpers.TickBatch
+//   - Read-only channel of errors
+func (s *MT5Service) StreamTickBatches(ctx context.Context, symbols []string, size int, maxWait time.Duration) (<-chan *helpers.TickBatch, <-chan error) {
+	names := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		names[i] = s.resolveSymbol(symbol)
+	}
+	return s.account.OnSymbolTickBatches(ctx, &pb.OnSymbolTickRequest{SymbolNames: names}, size, maxWait)
+}
+
 // StreamTradeUpdates streams trade events (new/disappeared orders and positions, history updates).
 //
 // This method provides real-time notifications about:
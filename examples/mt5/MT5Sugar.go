@@ -13,20 +13,41 @@
    • Simple monitoring and automation tools
    • Anyone preferring simplicity over fine-grained control
 
- 📚 COMPLETE METHOD INDEX (61 METHODS IN 13 CATEGORIES):
+ 📚 COMPLETE METHOD INDEX (151 METHODS IN 14 CATEGORIES):
 
    ┌─────────────────────────────────────────────────────────────┐
-   │  INITIALIZATION & HELPERS (3 methods)                       │
+   │  INITIALIZATION & HELPERS (19 methods + 1 struct)           │
    ├─────────────────────────────────────────────────────────────┤
    │  • NewMT5Sugar()    - Create Sugar instance                 │
+   │  • NewMT5SugarWithOptions() - Gzip, message size limits     │
+   │  • NewMT5SugarFromAccount() - Wrap account (e.g. replay)    │
    │  • GetService()     - Access underlying Service layer       │
    │  • GetAccount()     - Access underlying Account layer       │
+   │  • SetServerTimezone() - Broker timezone for day boundaries │
+   │  • SetCommissionModel() - Commission when broker reports 0  │
+   │  • SetMaxDeviationPoints() - Enforce slippage on market ord.│
+   │  • GetFillDeviations() - Fills outside deviation tolerance  │
+   │  • SetStopsAutoAdjust() - Nudge SL/TP to the stops level    │
+   │  • SetTradeGuards() - Permission checks before each order   │
+   │  • SetNewsFilter()  - Block entries around calendar events  │
+   │  • SetRolloverGuard() - Block entries before swap rollover  │
+   │  • SetTagManager()  - Foreign positions: ignore/adopt/guard │
+   │  • SetTradeTags()   - Default comment/magic for all orders  │
+   │  • WithTradeTags()  - Same connection, own comment/magic    │
+   │  • TradeTags        - Comment & magic structure             │
+   │  • SetNumberLocale() - Number format of statements          │
+   │  • NewFormatter()   - Locale-aware price/volume/money text  │
+   │  • WorkerPool()     - Per-symbol serialized batch execution │
    └─────────────────────────────────────────────────────────────┘
 
    ┌─────────────────────────────────────────────────────────────┐
-   │  1. CONNECTION METHODS (3 methods)                          │
+   │  1. CONNECTION METHODS (7 methods)                          │
    ├─────────────────────────────────────────────────────────────┤
    │  • QuickConnect()   - Connect via cluster name (RECOMMENDED)│
+   │  • Connect()        - Connect with ConnectOptions           │
+   │  • ConnectByHostPort() - Connect by server host:port        │
+   │  • ConnectByProxy() - Connect through a proxy               │
+   │  • DetectBrokerCapabilities() - Hedging, FIFO, fills, stops │
    │  • IsConnected()    - Check connection status               │
    │  • Ping()           - Verify connection health              │
    └─────────────────────────────────────────────────────────────┘
@@ -65,28 +86,52 @@
    └─────────────────────────────────────────────────────────────┘
 
    ┌─────────────────────────────────────────────────────────────┐
-   │  5. TRADING WITH SL/TP (4 methods)                          │
+   │  5. TRADING WITH SL/TP (8 methods + 1 struct)               │
    ├─────────────────────────────────────────────────────────────┤
-   │  • BuyMarketWithSLTP()  - BUY with Stop Loss & Take Profit  │
-   │  • SellMarketWithSLTP() - SELL with Stop Loss & Take Profit │
-   │  • BuyLimitWithSLTP()   - BUY LIMIT with SL/TP              │
-   │  • SellLimitWithSLTP()  - SELL LIMIT with SL/TP             │
+   │  • BuyMarketWithSLTP()  - BUY with SL/TP (deprecated)       │
+   │  • SellMarketWithSLTP() - SELL with SL/TP (deprecated)      │
+   │  • BuyLimitWithSLTP()   - BUY LIMIT with SL/TP (deprecated) │
+   │  • SellLimitWithSLTP()  - SELL LIMIT with SL/TP (deprecated)│
+   │  • SendOrder()          - Any order from OrderRequest struct│
+   │  • SendOrderResult()    - SendOrder with full SendResult    │
+   │  • PlaceAndConfirm()    - Send, wait for broker final state │
+   │  • ExplainErr()         - Reject cause & fix (stops level)  │
+   │  • OrderConfirmation    - Confirmed state, fills & reply    │
    └─────────────────────────────────────────────────────────────┘
 
    ┌─────────────────────────────────────────────────────────────┐
-   │  6. POSITION MANAGEMENT (7 methods)                         │
+   │  6. POSITION MANAGEMENT (21 methods + 5 structs)            │
    ├─────────────────────────────────────────────────────────────┤
    │  • ClosePosition()        - Close full position             │
    │  • ClosePositionPartial() - Close partial volume            │
+   │  • ClosePartialPercent()  - Close % of position volume      │
+   │  • ClosePartialAmount()   - Close volume worth N money      │
    │  • CloseAllPositions()    - Close all open positions        │
    │  • CloseAllBySymbol()     - Close all for specific symbol   │
    │  • ModifyPositionSL()     - Change Stop Loss                │
    │  • ModifyPositionTP()     - Change Take Profit              │
    │  • ModifyPositionSLTP()   - Change both SL and TP           │
+   │  • CloseAllProfitable()   - Close all positions in profit   │
+   │  • CloseAllLosing()       - Close positions losing > N      │
+   │  • CloseBySymbol()        - Close symbol, per-ticket result │
+   │  • CloseByMagic()         - Close by magic number           │
+   │  • CloseOlderThan()       - Close positions older than N    │
+   │  • ReversePosition()      - Flip position to other side     │
+   │  • HedgePosition()        - Open opposite hedge (hedging)   │
+   │  • AddToPosition()        - Scale in with same magic/SL/TP  │
+   │  • FlipTo()               - Flatten and reverse on signal   │
+   │  • SetStopLossAll()       - Bulk SL by points or price      │
+   │  • SetTakeProfitAll()     - Bulk TP by points or price      │
+   │  • RemoveStops()          - Bulk remove SL and TP           │
+   │  • CloseResult            - Per-ticket close outcome        │
+   │  • ModifyResult           - Per-ticket modify outcome       │
+   │  • FlipResult             - Consolidated FlipTo outcome     │
+   │  • PositionFilter         - Symbol/magic position filter    │
+   │  • StopTarget             - SL/TP as points or price        │
    └─────────────────────────────────────────────────────────────┘
 
    ┌─────────────────────────────────────────────────────────────┐
-   │  7. POSITION INFORMATION (7 methods)                        │
+   │  7. POSITION INFORMATION (14 methods + 5 structs)           │
    ├─────────────────────────────────────────────────────────────┤
    │  • GetOpenPositions()    - Get all open positions           │
    │  • GetPositionByTicket() - Find position by ticket number   │
@@ -95,10 +140,22 @@
    │  • CountOpenPositions()  - Count total open positions       │
    │  • GetTotalProfit()      - Total floating P/L               │
    │  • GetProfitBySymbol()   - Profit for specific symbol       │
+   │  • GetFloatingPnL()      - Profit + swap + commission       │
+   │  • GetFloatingPnLBySymbol() - Floating P/L per symbol       │
+   │  • GetNetExposure()      - Net lots & notional per symbol   │
+   │  • SymbolExposure        - Exposure structure               │
+   │  • GetCurrencyExposure() - Net lots & notional per currency │
+   │  • SymbolCurrencies()    - Base and profit currency         │
+   │  • CurrencyExposure      - Currency exposure structure      │
+   │  • PositionsQuery()      - Filter by symbols/magic/side/age │
+   │  • PositionQuery         - Query criteria structure         │
+   │  • Position              - Typed position (side, age)       │
+   │  • Readopt()             - Re-attach stored IDs on restart  │
+   │  • OrderIDStore          - Strategy ID → tickets (JSON)     │
    └─────────────────────────────────────────────────────────────┘
 
    ┌─────────────────────────────────────────────────────────────┐
-   │  8. HISTORY & PROFIT ANALYSIS (9 methods)                   │
+   │  8. HISTORY & PROFIT ANALYSIS (15 methods + 3 structs)      │
    ├─────────────────────────────────────────────────────────────┤
    │  • GetDealsToday()       - All deals from today             │
    │  • GetDealsYesterday()   - All deals from yesterday         │
@@ -108,45 +165,105 @@
    │  • GetProfitToday()      - Total profit from today          │
    │  • GetProfitThisWeek()   - Total profit from this week      │
    │  • GetProfitThisMonth()  - Total profit from this month     │
+   │  • TradingSummary()      - P/L, win rate, volume, fees      │
+   │  • TradingSummaryResult  - Period summary structure         │
+   │  • BuildStatement()      - End-of-day account statement     │
+   │  • SendStatement()       - Render statement to HTML & mail  │
+   │  • RunDailyStatements()  - Mail statement on a schedule     │
+   │  • Statement             - Statement structure (HTML())     │
+   │  • MonteCarloFromHistory() - Drawdown/return distributions  │
+   │  • DumpTradeContext()    - Trade support bundle (JSON)      │
+   │  • TradeContext          - Bundle structure (Save())        │
    └─────────────────────────────────────────────────────────────┘
 
    ┌─────────────────────────────────────────────────────────────┐
-   │  9. SYMBOL INFORMATION METHODS (5 methods + 1 struct)       │
+   │  9. SYMBOL INFORMATION METHODS (18 methods + 4 structs)     │
    ├─────────────────────────────────────────────────────────────┤
    │  • GetSymbolInfo()       - Complete symbol information      │
    │  • GetAllSymbols()       - List all available symbols       │
    │  • IsSymbolAvailable()   - Check if symbol is tradeable     │
    │  • GetMinStopLevel()     - Minimum stop level for symbol    │
    │  • GetSymbolDigits()     - Symbol decimal precision         │
+   │  • IsTradingTime()       - Session open and not a holiday   │
+   │  • AddHoliday()          - Add a market holiday             │
+   │  • CurrentSession()      - Current trade session window     │
+   │  • TimeUntilSessionClose() - Time left in current session   │
+   │  • MinutesIntoSession()  - Minutes since session open       │
+   │  • SessionWindow         - Session open/close structure     │
+   │  • DiscoverSymbolNames() - Detect broker suffix (.pro, m)   │
+   │  • ResolveSymbol()       - Canonical → broker symbol name   │
+   │  • GetSymbolResolver()   - Access the suffix resolver       │
+   │  • ApplySymbolMap()      - Per-broker names from config     │
+   │  • SymbolMap             - Broker symbol names (JSON file)  │
+   │  • ExportSymbolCatalog() - All symbol specs to CSV/JSON     │
+   │  • EnsureVisible()       - Add watchlist to Market Watch    │
+   │  • SnapshotQuotes()      - Current quotes of a watchlist    │
+   │  • SubscribeWatchlist()  - Tick stream of a watchlist       │
+   │  • Watchlist             - Named symbol set (WatchlistStore)│
    │  • SymbolInfo            - Symbol information structure     │
    └─────────────────────────────────────────────────────────────┘
 
    ┌─────────────────────────────────────────────────────────────┐
-   │  10. RISK MANAGEMENT METHODS (4 methods)  IMPORTANT         │
+   │  10. RISK MANAGEMENT METHODS (7 methods + 1 struct)         │
    ├─────────────────────────────────────────────────────────────┤
    │  • CalculatePositionSize()  - Auto-size based on risk %     │
    │  • GetMaxLotSize()          - Maximum tradeable volume      │
    │  • CanOpenPosition()        - Validate before trading       │
    │  • CalculateRequiredMargin()- Margin needed for position    │
+   │  • ValidateOrder()          - Dry-run report, no trade sent │
+   │  • SizePosition()           - Lot size from a PositionSizer │
+   │  • StressTest()             - Equity/margin under shocks    │
+   │  • OrderValidationReport    - Dry-run report structure      │
    └─────────────────────────────────────────────────────────────┘
 
    ┌─────────────────────────────────────────────────────────────┐
-   │  11. TRADING HELPERS (3 methods)                            │
+   │  11. TRADING HELPERS (5 methods)                            │
    ├─────────────────────────────────────────────────────────────┤
    │  • CalculateSLTP()       - Convert pips to price levels     │
    │  • BuyMarketWithPips()   - BUY with SL/TP in pips           │
    │  • SellMarketWithPips()  - SELL with SL/TP in pips          │
+   │  • BuyMarketSized()      - BUY sized by a PositionSizer     │
+   │  • SellMarketSized()     - SELL sized by a PositionSizer    │
    └─────────────────────────────────────────────────────────────┘
 
    ┌─────────────────────────────────────────────────────────────┐
-   │  12. ACCOUNT INFORMATION (2 methods + 2 structs)            │
+   │  12. ACCOUNT INFORMATION (5 methods + 2 structs)            │
    ├─────────────────────────────────────────────────────────────┤
    │  • GetAccountInfo()      - Complete account details         │
    │  • GetDailyStats()       - Daily trading statistics         │
+   │  • IsSwapFree()          - Swap-free (Islamic) account      │
+   │  • SetSwapFree()         - Override swap-free detection     │
+   │  • SwapPerNight()        - Swap of a position per rollover  │
    │  • AccountInfo           - Account information structure    │
    │  • DailyStats            - Daily statistics structure       │
    └─────────────────────────────────────────────────────────────┘
 
+   ┌─────────────────────────────────────────────────────────────┐
+   │  13. PENDING ORDER HELPERS (20 methods + 1 struct)          │
+   ├─────────────────────────────────────────────────────────────┤
+   │  • PlacePendingGTD()     - Any pending, expires at time     │
+   │  • PlacePendingDay()     - Any pending, expires end of day  │
+   │  • PlacePendingUntilDay()- Any pending, until given day     │
+   │  • BuyLimitGTD()         - BUY LIMIT until time             │
+   │  • SellLimitGTD()        - SELL LIMIT until time            │
+   │  • BuyStopGTD()          - BUY STOP until time              │
+   │  • SellStopGTD()         - SELL STOP until time             │
+   │  • BuyLimitDay()         - BUY LIMIT for today only         │
+   │  • SellLimitDay()        - SELL LIMIT for today only        │
+   │  • BuyStopDay()          - BUY STOP for today only          │
+   │  • SellStopDay()         - SELL STOP for today only         │
+   │  • ModifyStopLimit()     - Change STOP LIMIT trigger & limit│
+   │  • PendingPrice()        - Price N points from Ask/Bid side │
+   │  • PendingPriceAtLevel() - Chart (Bid) level → order price  │
+   │  • PlacePendingOffset()  - Any pending, N points from side  │
+   │  • PlacePendingAtLevel() - Any pending at chart level       │
+   │  • BuyStopPoints()       - BUY STOP N points above Ask      │
+   │  • SellStopPoints()      - SELL STOP N points below Bid     │
+   │  • BuyLimitPoints()      - BUY LIMIT N points below Ask     │
+   │  • SellLimitPoints()     - SELL LIMIT N points above Bid    │
+   │  • PendingEntry          - Spread-adjusted entry structure  │
+   └─────────────────────────────────────────────────────────────┘
+
  ⚠️  IMPORTANT NOTES:
    • All methods have built-in timeouts (3-30 seconds depending on operation)
    • Market orders timeout: 10 seconds
@@ -170,7 +287,11 @@ package mt5
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"strings"
+	"sync"
 	"time"
 
 	pb "github.com/MetaRPC/GoMT5/package"
@@ -186,10 +307,44 @@ import (
 // for all common MT5 operations. It automatically handles contexts, timeouts, and
 // provides smart defaults for all parameters.
 type MT5Sugar struct {
-	service  *MT5Service
-	ctx      context.Context
-	user     uint64
-	password string
+	service   *MT5Service
+	ctx       context.Context
+	user      uint64
+	password  string
+	serverLoc   *time.Location   // Broker timezone for day/week/month boundaries
+	serverTZSet bool             // SetServerTimezone was called (needed by market-closed checks)
+	commissions *CommissionModel // Commission estimates when deals report none (nil = off)
+	locale      NumberLocale     // Number format of statements and formatters (zero = LocaleEN)
+
+	maxDeviation uint64              // Max slippage in points for market orders (0 = off)
+	onDeviation  func(FillDeviation) // Called for fills outside tolerance
+	devMu        sync.Mutex
+	deviations   []FillDeviation // Last 100 fills outside tolerance
+
+	stopsAdjust   int64                 // Max SL/TP nudge to the stops level in points (0 = off), guarded by devMu
+	onStopsAdjust func(StopsAdjustment) // Called for each adjusted level (nil = log warning)
+	tradeTags     TradeTags             // Default comment/magic of orders, guarded by devMu
+
+	noTradeGuards bool            // Skip account/symbol permission checks before orders
+	guards        tradeGuardCache // Trade modes read by the checks, reset on connect
+
+	news *NewsFilter // Blocks new orders around calendar events (nil = off)
+
+	swapMu         sync.Mutex
+	swapFree       *bool         // Detected swap-free state, reset on connect
+	swapForced     *bool         // SetSwapFree override (nil = detect)
+	rolloverWindow time.Duration // Block orders this long before server midnight (0 = off)
+
+	calMu    sync.RWMutex
+	holidays map[string]bool // "SYMBOL|2006-01-02" or "|2006-01-02" for all symbols
+
+	symbols *SuffixResolver // Canonical → broker symbol names (e.g., EURUSD → EURUSD.pro)
+	tags    *TagManager     // Own vs foreign positions and their policy (nil = all managed)
+
+	capsMu sync.Mutex
+	caps   *BrokerCapabilities // Cached per session, reset on connect
+
+	pool *WorkerPool // Batch operations: parallel across symbols, serial per symbol
 }
 
 // PriceInfo holds complete current price information for a trading symbol.
@@ -209,6 +364,25 @@ type PriceInfo struct {
 	Time       time.Time
 }
 
+// ErrNettingAccount is returned by operations that need separate opposite
+// positions (hedging) on an account in netting mode.
+// Use errors.Is(err, mt5.ErrNettingAccount) to check for this error.
+var ErrNettingAccount = errors.New("operation requires a hedging account, account is in netting mode")
+
+// ErrMarketClosed is returned when a symbol is outside its trading sessions,
+// on a weekend, or on a configured holiday (see IsTradingTime, AddHoliday).
+// Use errors.Is(err, mt5.ErrMarketClosed) to check for this error.
+var ErrMarketClosed = errors.New("market closed")
+
+// ErrMaxVolumeReached is returned by AddToPosition when the position already
+// holds the maximum total volume (or the remaining room is below VolumeMin).
+var ErrMaxVolumeReached = errors.New("maximum total volume reached")
+
+// ErrFillingNotAllowed is returned by SendOrder when OrderRequest.Filling
+// names a filling policy the symbol does not allow (see GetSymbolFillingModes).
+// Use errors.Is(err, mt5.ErrFillingNotAllowed) to check for this error.
+var ErrFillingNotAllowed = errors.New("filling mode not allowed for symbol")
+
 // ══════════════════════════════════════════════════════════════════════════════
 // INITIALIZATION & HELPERS
 // ══════════════════════════════════════════════════════════════════════════════
@@ -225,19 +399,61 @@ type PriceInfo struct {
 // RETURNS:
 //   *MT5Sugar instance ready for connection, or error if initialization fails
 func NewMT5Sugar(user uint64, password string, grpcServer string) (*MT5Sugar, error) {
-	account, err := helpers.NewMT5Account(user, password, grpcServer, uuid.New())
+	return NewMT5SugarWithOptions(user, password, grpcServer, helpers.AccountOptions{})
+}
+
+// NewMT5SugarWithOptions is NewMT5Sugar with gRPC connection options. Use it
+// when pulling large symbol lists or long histories over slow links.
+//
+// PARAMETERS:
+//   user       - MT5 account login number
+//   password   - MT5 account password
+//   grpcServer - gRPC server address (host:port, e.g., "mt5.server.com:443")
+//   opts       - Compression and message size limits (zero value = gRPC defaults)
+//
+// RETURNS:
+//   *MT5Sugar instance ready for connection, or error if options are invalid
+//   or initialization fails
+//
+// EXAMPLE:
+//   sugar, err := mt5.NewMT5SugarWithOptions(user, password, server, helpers.AccountOptions{
+//       Compression:    helpers.CompressionGzip,
+//       MaxRecvMsgSize: 64 << 20, // 64 MB
+//   })
+func NewMT5SugarWithOptions(user uint64, password string, grpcServer string, opts helpers.AccountOptions) (*MT5Sugar, error) {
+	account, err := helpers.NewMT5AccountWithOptions(user, password, grpcServer, uuid.New(), opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create MT5Account: %w", err)
 	}
+	return NewMT5SugarFromAccount(account), nil
+}
 
+// NewMT5SugarFromAccount wraps an existing MT5Account, e.g. one built by
+// helpers.NewReplayAccount to run Sugar logic against recorded fixtures
+// without a network connection.
+//
+// PARAMETERS:
+//   account - Low-level account; its User and Password are used for connecting
+//
+// EXAMPLE:
+//   fixtures, _ := helpers.LoadFixtures("testdata/buy_market.json")
+//   account, _ := helpers.NewReplayAccount(12345, "", uuid.New(), helpers.NewReplayer(fixtures))
+//   sugar := mt5.NewMT5SugarFromAccount(account)
+//   ticket, err := sugar.BuyMarket("EURUSD", 0.01)
+func NewMT5SugarFromAccount(account *helpers.MT5Account) *MT5Sugar {
 	service := NewMT5Service(account)
+	symbols := NewSuffixResolver()
+	service.SetSymbolResolver(symbols)
 
 	return &MT5Sugar{
-		service:  service,
-		ctx:      context.Background(),
-		user:     user,
-		password: password,
-	}, nil
+		service:   service,
+		ctx:       context.Background(),
+		user:      account.User,
+		password:  account.Password,
+		serverLoc: time.Local,
+		symbols:   symbols,
+		pool:      NewWorkerPool(DefaultPoolWorkers),
+	}
 }
 
 // GetService returns the underlying MT5Service instance for operations that
@@ -260,6 +476,108 @@ func (s *MT5Sugar) GetAccount() *helpers.MT5Account {
 	return s.service.account
 }
 
+// WorkerPool returns the pool used by batch operations (CloseAllProfitable,
+// CloseBySymbol, ...). Use it for your own batch work so it never runs on the
+// same symbol at the same time as a Sugar batch operation.
+//
+// RETURNS:
+//   *WorkerPool shared by this Sugar instance
+func (s *MT5Sugar) WorkerPool() *WorkerPool {
+	return s.pool
+}
+
+// SetServerTimezone sets the broker's server timezone used for day/week/month
+// boundaries in TradingSummary. Most brokers run on EET (UTC+2, UTC+3 in summer),
+// so "today" on the server starts at a different moment than local midnight.
+// Default is the local timezone. Until it is set, WaitForPrice and
+// SubscribeWatchlist do not fail fast with ErrMarketClosed, since sessions
+// cannot be matched against local time.
+//
+// PARAMETERS:
+//   loc - Server timezone (e.g., time.LoadLocation("Europe/Athens"), or time.FixedZone("GMT+2", 2*3600))
+func (s *MT5Sugar) SetServerTimezone(loc *time.Location) {
+	s.serverTZSet = loc != nil
+	if loc == nil {
+		loc = time.Local
+	}
+	s.serverLoc = loc
+}
+
+// SetCommissionModel sets the commission schemes used when the broker does not
+// report commission: ValidateOrder estimates entry+exit commission with it, and
+// TradingSummary applies it to closed positions whose deals carry no commission.
+//
+// PARAMETERS:
+//   model - Per-symbol commission rules (nil = disable estimates)
+func (s *MT5Sugar) SetCommissionModel(model *CommissionModel) {
+	s.commissions = model
+}
+
+// FillDeviation describes a market fill whose price moved against the order
+// by more than MaxDeviationPoints.
+type FillDeviation struct {
+	Time            time.Time
+	Symbol          string
+	Ticket          uint64
+	Buy             bool
+	RequestedPrice  float64 // Ask (BUY) or Bid (SELL) at send time
+	FillPrice       float64 // Price confirmed by broker
+	DeviationPoints float64 // Adverse deviation in points (negative = price improvement)
+	MaxPoints       uint64  // Tolerance at send time
+}
+
+// SetMaxDeviationPoints enforces a maximum slippage on every market order sent
+// through Sugar (BuyMarket, SellMarket, *WithSLTP, *WithPips, *Sized, SendOrder).
+// At send time the current Ask/Bid becomes the requested price and the
+// tolerance is passed to the server as slippage. After execution the fill price
+// is verified; fills beyond tolerance are recorded (GetFillDeviations) and
+// reported to onViolation. The position is NOT closed automatically.
+//
+// PARAMETERS:
+//   points      - Max deviation in points (0 = disable enforcement)
+//   onViolation - Optional callback for fills outside tolerance (may be nil)
+func (s *MT5Sugar) SetMaxDeviationPoints(points uint64, onViolation func(FillDeviation)) {
+	s.devMu.Lock()
+	defer s.devMu.Unlock()
+	s.maxDeviation = points
+	s.onDeviation = onViolation
+}
+
+// GetFillDeviations returns the last 100 market fills outside MaxDeviationPoints.
+//
+// RETURNS:
+//   Slice of FillDeviation, oldest first
+func (s *MT5Sugar) GetFillDeviations() []FillDeviation {
+	s.devMu.Lock()
+	defer s.devMu.Unlock()
+	return append([]FillDeviation(nil), s.deviations...)
+}
+
+// SetTradeGuards enables or disables permission checks before every order sent
+// through Sugar. When enabled (default), ACCOUNT_TRADE_ALLOWED, ACCOUNT_TRADE_EXPERT
+// and the symbol's TRADE_MODE are checked first, and a typed error
+// (ErrTradingDisabled, ErrSymbolCloseOnly, ...) is returned instead of a
+// generic broker reject. The values are read once per session and per symbol,
+// and re-read before an order is refused. On netting accounts a market order
+// that only reduces the open position is allowed on close-only and one-sided
+// symbols.
+//
+// PARAMETERS:
+//   enabled - true to check permissions before sending (default), false to skip
+func (s *MT5Sugar) SetTradeGuards(enabled bool) {
+	s.noTradeGuards = !enabled
+}
+
+// SetNewsFilter blocks every order sent through Sugar while one of the
+// symbol's currencies is inside a calendar blackout window; the order fails
+// with an error wrapping ErrNewsBlackout. Calendar read errors do not block.
+//
+// PARAMETERS:
+//   filter - news filter to consult before each order, nil to disable
+func (s *MT5Sugar) SetNewsFilter(filter *NewsFilter) {
+	s.news = filter
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // #region CONNECTION METHODS
 // ══════════════════════════════════════════════════════════════════════════════
@@ -267,6 +585,8 @@ func (s *MT5Sugar) GetAccount() *helpers.MT5Account {
 // QuickConnect connects to MT5 terminal using cluster name (RECOMMENDED).
 // This is the easiest connection method - just provide your broker's cluster name.
 // Automatically sets up EURUSD as base chart symbol and uses 30-second timeout.
+// After connecting, the broker's symbol naming (e.g., "EURUSD.pro") is detected
+// so canonical names work in all Sugar calls (see DiscoverSymbolNames).
 //
 // PARAMETERS:
 //   clusterName - MT5 cluster identifier (e.g., "FxPro-MT5 Demo", "ICMarkets-Live02")
@@ -285,8 +605,143 @@ func (s *MT5Sugar) QuickConnect(clusterName string) error {
 		BaseChartSymbol: &baseSymbol,
 	}
 
-	_, err := s.GetAccount().ConnectEx(ctx, req)
-	return err
+	if _, err := s.GetAccount().ConnectEx(ctx, req); err != nil {
+		return err
+	}
+
+	s.resetBrokerCapabilities()
+	s.resetSwapFree()
+	s.resetTradeGuards()
+
+	// Best effort: without detection names are used as given
+	_ = s.DiscoverSymbolNames()
+	return nil
+}
+
+// Connect connects to MT5 terminal with full control over the connection.
+// The variant is chosen from the options: proxy if Proxy is set, direct
+// host:port if Host is set, cluster name otherwise. User and Password default
+// to the credentials given to NewMT5Sugar.
+//
+// PARAMETERS:
+//   opts - ConnectOptions (ServerName, or Host/Port, optionally Proxy)
+//
+// RETURNS:
+//   Error if connection fails, nil on success
+//
+// EXAMPLE:
+//   err := sugar.Connect(mt5.ConnectOptions{
+//       Host: "185.10.20.30", Port: 443,
+//       Proxy: &mt5.ProxyOptions{Host: "proxy.local", Port: 1080, Type: pb.ProxyTypes_Socks5},
+//   })
+func (s *MT5Sugar) Connect(opts ConnectOptions) error {
+	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
+	defer cancel()
+
+	if opts.User == 0 {
+		opts.User = s.user
+	}
+	if opts.Password == "" {
+		opts.Password = s.password
+	}
+
+	if _, err := s.service.Connect(ctx, opts); err != nil {
+		return err
+	}
+
+	s.resetBrokerCapabilities()
+	s.resetSwapFree()
+	s.resetTradeGuards()
+
+	// Best effort: without detection names are used as given
+	_ = s.DiscoverSymbolNames()
+	return nil
+}
+
+// ConnectByHostPort connects directly to a trade server address instead of a
+// cluster name. Useful when the broker publishes access point IPs only.
+//
+// PARAMETERS:
+//   host - Trade server IP or DNS name
+//   port - Trade server port (usually 443)
+//
+// RETURNS:
+//   Error if connection fails, nil on success
+func (s *MT5Sugar) ConnectByHostPort(host string, port int32) error {
+	return s.Connect(ConnectOptions{Host: host, Port: port})
+}
+
+// ConnectByProxy connects to a trade server address through a proxy.
+//
+// PARAMETERS:
+//   host  - Trade server IP or DNS name
+//   port  - Trade server port (usually 443)
+//   proxy - Proxy host, port, type and optional credentials
+//
+// RETURNS:
+//   Error if connection fails, nil on success
+func (s *MT5Sugar) ConnectByProxy(host string, port int32, proxy ProxyOptions) error {
+	return s.Connect(ConnectOptions{Host: host, Port: port, Proxy: &proxy})
+}
+
+// DetectBrokerCapabilities returns hedging/netting mode, FIFO rule, order limit
+// and per-symbol filling modes, stops and freeze levels in one struct.
+// Results are cached for the session: symbols already probed are not queried
+// again, and the cache is reset on QuickConnect/Connect.
+//
+// PARAMETERS:
+//   symbols - Symbols to include (may be empty for account rules only)
+//
+// RETURNS:
+//   *BrokerCapabilities, or error if a property could not be read
+//
+// EXAMPLE:
+//   caps, _ := sugar.DetectBrokerCapabilities("EURUSD")
+//   if caps.FIFOClose {
+//       // close oldest position first
+//   }
+func (s *MT5Sugar) DetectBrokerCapabilities(symbols ...string) (*BrokerCapabilities, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
+	defer cancel()
+
+	resolved := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		resolved[i] = s.ResolveSymbol(symbol)
+	}
+
+	s.capsMu.Lock()
+	defer s.capsMu.Unlock()
+
+	if s.caps == nil {
+		caps, err := s.service.DetectBrokerCapabilities(ctx, resolved...)
+		if err != nil {
+			return nil, err
+		}
+		s.caps = caps
+		return caps, nil
+	}
+
+	var missing []string
+	for _, symbol := range resolved {
+		if s.caps.Symbols[symbol] == nil {
+			missing = append(missing, symbol)
+		}
+	}
+	for _, symbol := range missing {
+		symCaps, err := s.service.detectSymbolCapabilities(ctx, symbol)
+		if err != nil {
+			return nil, fmt.Errorf("DetectBrokerCapabilities failed: %w", err)
+		}
+		s.caps.Symbols[symbol] = symCaps
+	}
+	return s.caps, nil
+}
+
+// resetBrokerCapabilities drops cached capabilities (new session).
+func (s *MT5Sugar) resetBrokerCapabilities() {
+	s.capsMu.Lock()
+	s.caps = nil
+	s.capsMu.Unlock()
 }
 
 // IsConnected checks if the connection to MT5 terminal is alive.
@@ -429,6 +884,8 @@ func (s *MT5Sugar) GetProfit() (float64, error) {
 // RETURNS:
 //   Current BID price as float64, or error if symbol not found or query fails
 func (s *MT5Sugar) GetBid(symbol string) (float64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	ctx, cancel := context.WithTimeout(s.ctx, 3*time.Second)
 	defer cancel()
 
@@ -450,6 +907,8 @@ func (s *MT5Sugar) GetBid(symbol string) (float64, error) {
 // RETURNS:
 //   Current ASK price as float64, or error if symbol not found or query fails
 func (s *MT5Sugar) GetAsk(symbol string) (float64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	ctx, cancel := context.WithTimeout(s.ctx, 3*time.Second)
 	defer cancel()
 
@@ -471,6 +930,8 @@ func (s *MT5Sugar) GetAsk(symbol string) (float64, error) {
 // RETURNS:
 //   Current spread in points as float64, or error if symbol not found
 func (s *MT5Sugar) GetSpread(symbol string) (float64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	ctx, cancel := context.WithTimeout(s.ctx, 3*time.Second)
 	defer cancel()
 
@@ -492,6 +953,8 @@ func (s *MT5Sugar) GetSpread(symbol string) (float64, error) {
 // RETURNS:
 //   *PriceInfo structure with all price data, or error if symbol not found
 func (s *MT5Sugar) GetPriceInfo(symbol string) (*PriceInfo, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	ctx, cancel := context.WithTimeout(s.ctx, 3*time.Second)
 	defer cancel()
 
@@ -529,8 +992,17 @@ func (s *MT5Sugar) GetPriceInfo(symbol string) (*PriceInfo, error) {
 //   timeout - Maximum time to wait (e.g., 5*time.Second)
 //
 // RETURNS:
-//   *PriceInfo with valid price data, or error if timeout expires
+//   *PriceInfo with valid price data, ErrMarketClosed immediately if the
+//   symbol is outside its trading sessions (only once SetServerTimezone has
+//   been called), or error if timeout expires
 func (s *MT5Sugar) WaitForPrice(symbol string, timeout time.Duration) (*PriceInfo, error) {
+	symbol = s.ResolveSymbol(symbol)
+
+	// Don't wait for ticks that cannot arrive
+	if s.marketClosed(symbol) {
+		return nil, fmt.Errorf("%w: %s", ErrMarketClosed, symbol)
+	}
+
 	ctx, cancel := context.WithTimeout(s.ctx, timeout)
 	defer cancel()
 
@@ -578,6 +1050,8 @@ func (s *MT5Sugar) WaitForPrice(symbol string, timeout time.Duration) (*PriceInf
 // RETURNS:
 //   Position ticket number (uint64), or error if order rejected or fails
 func (s *MT5Sugar) BuyMarket(symbol string, volume float64) (uint64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
 	defer cancel()
 
@@ -587,13 +1061,13 @@ func (s *MT5Sugar) BuyMarket(symbol string, volume float64) (uint64, error) {
 		Volume:    volume,
 	}
 
-	result, err := s.service.PlaceOrder(ctx, req)
+	result, err := s.placeOrder(ctx, req)
 	if err != nil {
 		return 0, fmt.Errorf("BuyMarket failed: %w", err)
 	}
 
-	if result.ReturnedCode != 10009 {
-		return 0, fmt.Errorf("order rejected, code: %d, comment: %s", result.ReturnedCode, result.Comment)
+	if err := result.Err(); err != nil {
+		return 0, err
 	}
 
 	return result.Order, nil
@@ -610,6 +1084,8 @@ func (s *MT5Sugar) BuyMarket(symbol string, volume float64) (uint64, error) {
 // RETURNS:
 //   Position ticket number (uint64), or error if order rejected or fails
 func (s *MT5Sugar) SellMarket(symbol string, volume float64) (uint64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
 	defer cancel()
 
@@ -619,13 +1095,13 @@ func (s *MT5Sugar) SellMarket(symbol string, volume float64) (uint64, error) {
 		Volume:    volume,
 	}
 
-	result, err := s.service.PlaceOrder(ctx, req)
+	result, err := s.placeOrder(ctx, req)
 	if err != nil {
 		return 0, fmt.Errorf("SellMarket failed: %w", err)
 	}
 
-	if result.ReturnedCode != 10009 {
-		return 0, fmt.Errorf("order rejected, code: %d, comment: %s", result.ReturnedCode, result.Comment)
+	if err := result.Err(); err != nil {
+		return 0, err
 	}
 
 	return result.Order, nil
@@ -643,6 +1119,8 @@ func (s *MT5Sugar) SellMarket(symbol string, volume float64) (uint64, error) {
 // RETURNS:
 //   Pending order ticket number (uint64), or error if order rejected
 func (s *MT5Sugar) BuyLimit(symbol string, volume, price float64) (uint64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
 	defer cancel()
 
@@ -653,13 +1131,13 @@ func (s *MT5Sugar) BuyLimit(symbol string, volume, price float64) (uint64, error
 		Price:     &price,
 	}
 
-	result, err := s.service.PlaceOrder(ctx, req)
+	result, err := s.placeOrder(ctx, req)
 	if err != nil {
 		return 0, fmt.Errorf("BuyLimit failed: %w", err)
 	}
 
-	if result.ReturnedCode != 10009 {
-		return 0, fmt.Errorf("order rejected, code: %d, comment: %s", result.ReturnedCode, result.Comment)
+	if err := result.Err(); err != nil {
+		return 0, err
 	}
 
 	return result.Order, nil
@@ -677,6 +1155,8 @@ func (s *MT5Sugar) BuyLimit(symbol string, volume, price float64) (uint64, error
 // RETURNS:
 //   Pending order ticket number (uint64), or error if order rejected
 func (s *MT5Sugar) SellLimit(symbol string, volume, price float64) (uint64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
 	defer cancel()
 
@@ -687,13 +1167,13 @@ func (s *MT5Sugar) SellLimit(symbol string, volume, price float64) (uint64, erro
 		Price:     &price,
 	}
 
-	result, err := s.service.PlaceOrder(ctx, req)
+	result, err := s.placeOrder(ctx, req)
 	if err != nil {
 		return 0, fmt.Errorf("SellLimit failed: %w", err)
 	}
 
-	if result.ReturnedCode != 10009 {
-		return 0, fmt.Errorf("order rejected, code: %d, comment: %s", result.ReturnedCode, result.Comment)
+	if err := result.Err(); err != nil {
+		return 0, err
 	}
 
 	return result.Order, nil
@@ -711,6 +1191,8 @@ func (s *MT5Sugar) SellLimit(symbol string, volume, price float64) (uint64, erro
 // RETURNS:
 //   Pending order ticket number (uint64), or error if order rejected
 func (s *MT5Sugar) BuyStop(symbol string, volume, price float64) (uint64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
 	defer cancel()
 
@@ -721,13 +1203,13 @@ func (s *MT5Sugar) BuyStop(symbol string, volume, price float64) (uint64, error)
 		Price:     &price,
 	}
 
-	result, err := s.service.PlaceOrder(ctx, req)
+	result, err := s.placeOrder(ctx, req)
 	if err != nil {
 		return 0, fmt.Errorf("BuyStop failed: %w", err)
 	}
 
-	if result.ReturnedCode != 10009 {
-		return 0, fmt.Errorf("order rejected, code: %d, comment: %s", result.ReturnedCode, result.Comment)
+	if err := result.Err(); err != nil {
+		return 0, err
 	}
 
 	return result.Order, nil
@@ -745,6 +1227,8 @@ func (s *MT5Sugar) BuyStop(symbol string, volume, price float64) (uint64, error)
 // RETURNS:
 //   Pending order ticket number (uint64), or error if order rejected
 func (s *MT5Sugar) SellStop(symbol string, volume, price float64) (uint64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
 	defer cancel()
 
@@ -755,13 +1239,13 @@ func (s *MT5Sugar) SellStop(symbol string, volume, price float64) (uint64, error
 		Price:     &price,
 	}
 
-	result, err := s.service.PlaceOrder(ctx, req)
+	result, err := s.placeOrder(ctx, req)
 	if err != nil {
 		return 0, fmt.Errorf("SellStop failed: %w", err)
 	}
 
-	if result.ReturnedCode != 10009 {
-		return 0, fmt.Errorf("order rejected, code: %d, comment: %s", result.ReturnedCode, result.Comment)
+	if err := result.Err(); err != nil {
+		return 0, err
 	}
 
 	return result.Order, nil
@@ -774,7 +1258,6 @@ func (s *MT5Sugar) SellStop(symbol string, volume, price float64) (uint64, error
 // ══════════════════════════════════════════════════════════════════════════════
 
 // BuyMarketWithSLTP opens a BUY position with Stop Loss and Take Profit.
-// This is the recommended way to open positions with risk management built-in.
 // Order executes immediately at market price with SL/TP set. Uses 10-second timeout.
 //
 // PARAMETERS:
@@ -785,7 +1268,12 @@ func (s *MT5Sugar) SellStop(symbol string, volume, price float64) (uint64, error
 //
 // RETURNS:
 //   Position ticket number (uint64), or error if order rejected
+//
+// Deprecated: sl and tp are positional floats that are easy to swap. Use
+// SendOrder with an OrderRequest instead.
 func (s *MT5Sugar) BuyMarketWithSLTP(symbol string, volume, sl, tp float64) (uint64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
 	defer cancel()
 
@@ -797,20 +1285,19 @@ func (s *MT5Sugar) BuyMarketWithSLTP(symbol string, volume, sl, tp float64) (uin
 		TakeProfit: &tp,
 	}
 
-	result, err := s.service.PlaceOrder(ctx, req)
+	result, err := s.placeOrder(ctx, req)
 	if err != nil {
 		return 0, fmt.Errorf("BuyMarketWithSLTP failed: %w", err)
 	}
 
-	if result.ReturnedCode != 10009 {
-		return 0, fmt.Errorf("order rejected, code: %d, comment: %s", result.ReturnedCode, result.Comment)
+	if err := result.Err(); err != nil {
+		return 0, err
 	}
 
 	return result.Order, nil
 }
 
 // SellMarketWithSLTP opens a SELL position with Stop Loss and Take Profit.
-// This is the recommended way to open short positions with risk management built-in.
 // Order executes immediately at market price with SL/TP set. Uses 10-second timeout.
 //
 // PARAMETERS:
@@ -818,7 +1305,15 @@ func (s *MT5Sugar) BuyMarketWithSLTP(symbol string, volume, sl, tp float64) (uin
 //   volume - Lot size (e.g., 0.01, 0.1, 1.0)
 //   sl     - Stop Loss price (must be ABOVE entry price for SELL)
 //   tp     - Take Profit price (must be BELOW entry price for SELL)
+//
+// RETURNS:
+//   Position ticket number (uint64), or error if order rejected
+//
+// Deprecated: sl and tp are positional floats that are easy to swap. Use
+// SendOrder with an OrderRequest instead.
 func (s *MT5Sugar) SellMarketWithSLTP(symbol string, volume, sl, tp float64) (uint64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
 	defer cancel()
 
@@ -830,13 +1325,13 @@ func (s *MT5Sugar) SellMarketWithSLTP(symbol string, volume, sl, tp float64) (ui
 		TakeProfit: &tp,
 	}
 
-	result, err := s.service.PlaceOrder(ctx, req)
+	result, err := s.placeOrder(ctx, req)
 	if err != nil {
 		return 0, fmt.Errorf("SellMarketWithSLTP failed: %w", err)
 	}
 
-	if result.ReturnedCode != 10009 {
-		return 0, fmt.Errorf("order rejected, code: %d, comment: %s", result.ReturnedCode, result.Comment)
+	if err := result.Err(); err != nil {
+		return 0, err
 	}
 
 	return result.Order, nil
@@ -855,7 +1350,12 @@ func (s *MT5Sugar) SellMarketWithSLTP(symbol string, volume, sl, tp float64) (ui
 //
 // RETURNS:
 //   Pending order ticket number (uint64), or error if order rejected
+//
+// Deprecated: Five positional floats are easy to mix up. Use SendOrder with an
+// OrderRequest instead.
 func (s *MT5Sugar) BuyLimitWithSLTP(symbol string, volume, price, sl, tp float64) (uint64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
 	defer cancel()
 
@@ -868,13 +1368,13 @@ func (s *MT5Sugar) BuyLimitWithSLTP(symbol string, volume, price, sl, tp float64
 		TakeProfit: &tp,
 	}
 
-	result, err := s.service.PlaceOrder(ctx, req)
+	result, err := s.placeOrder(ctx, req)
 	if err != nil {
 		return 0, fmt.Errorf("BuyLimitWithSLTP failed: %w", err)
 	}
 
-	if result.ReturnedCode != 10009 {
-		return 0, fmt.Errorf("order rejected, code: %d, comment: %s", result.ReturnedCode, result.Comment)
+	if err := result.Err(); err != nil {
+		return 0, err
 	}
 
 	return result.Order, nil
@@ -893,7 +1393,12 @@ func (s *MT5Sugar) BuyLimitWithSLTP(symbol string, volume, price, sl, tp float64
 //
 // RETURNS:
 //   Pending order ticket number (uint64), or error if order rejected
+//
+// Deprecated: Five positional floats are easy to mix up. Use SendOrder with an
+// OrderRequest instead.
 func (s *MT5Sugar) SellLimitWithSLTP(symbol string, volume, price, sl, tp float64) (uint64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
 	defer cancel()
 
@@ -906,18 +1411,223 @@ func (s *MT5Sugar) SellLimitWithSLTP(symbol string, volume, price, sl, tp float6
 		TakeProfit: &tp,
 	}
 
-	result, err := s.service.PlaceOrder(ctx, req)
+	result, err := s.placeOrder(ctx, req)
 	if err != nil {
 		return 0, fmt.Errorf("SellLimitWithSLTP failed: %w", err)
 	}
 
-	if result.ReturnedCode != 10009 {
-		return 0, fmt.Errorf("order rejected, code: %d, comment: %s", result.ReturnedCode, result.Comment)
+	if err := result.Err(); err != nil {
+		return 0, err
 	}
 
 	return result.Order, nil
 }
 
+// SendOrder sends any order type described by an OrderRequest struct.
+// Named fields replace long positional parameter lists, and the request is
+// validated before it reaches the broker. Uses 10-second timeout.
+//
+// PARAMETERS:
+//   req - Order description (Symbol, Type and Volume are required;
+//         Price is required for pending orders; zero SL/TP = none;
+//         TimeType/Expiration control pending order lifetime)
+//
+// RETURNS:
+//   Order/position ticket number (uint64), or error if invalid or rejected
+//
+// EXAMPLE:
+//   ticket, err := sugar.SendOrder(mt5.OrderRequest{
+//       Symbol:     "EURUSD",
+//       Type:       pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_LIMIT,
+//       Volume:     0.1,
+//       Price:      1.0850,
+//       StopLoss:   1.0800,
+//       TakeProfit: 1.0950,
+//   })
+func (s *MT5Sugar) SendOrder(req OrderRequest) (uint64, error) {
+	result, err := s.SendOrderResult(req)
+	if err != nil {
+		return 0, err
+	}
+	return result.Order, nil
+}
+
+// SendOrderResult is SendOrder returning the broker's full answer instead of
+// the ticket: return code and its description, executed price and volume,
+// request ID and broker comment. Uses 10-second timeout.
+//
+// RETURNS:
+//   *SendResult, or error if invalid or failed. A rejected order returns
+//   both the result and a *TradeRejectedError.
+//
+// EXAMPLE:
+//   result, err := sugar.SendOrderResult(mt5.OrderRequest{Symbol: "EURUSD", Type: buy, Volume: 0.1})
+//   var rejected *mt5.TradeRejectedError
+//   if errors.As(err, &rejected) {
+//       log.Printf("rejected: %s", rejected.Result)   // 10019 TRADE_RETCODE_NO_MONEY (...)
+//   } else if err == nil {
+//       log.Printf("filled %.2f @ %.5f", result.Volume, result.Price)
+//   }
+func (s *MT5Sugar) SendOrderResult(req OrderRequest) (*SendResult, error) {
+	req.Symbol = s.ResolveSymbol(req.Symbol)
+
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+	defer cancel()
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("SendOrder failed: %w", err)
+	}
+	if err := s.service.checkFilling(ctx, req); err != nil {
+		return nil, fmt.Errorf("SendOrder failed: %w", err)
+	}
+
+	result, err := s.placeOrder(ctx, req.ToProto())
+	if err != nil {
+		return nil, fmt.Errorf("SendOrder failed: %w", err)
+	}
+
+	if result.ReturnedCode == helpers.TradeRetCodeInvalidFill {
+		return &result.SendResult, s.service.invalidFillError(ctx, req, result)
+	}
+
+	return &result.SendResult, result.Err()
+}
+
+// ExplainErr explains a failed trade in plain words: the likely cause of the
+// return code or MQL error and a suggested fix. For INVALID_STOPS,
+// INVALID_PRICE and FROZEN the symbol's stops and freeze levels are read so
+// the message names the actual distance. Uses 3-second timeout.
+//
+// PARAMETERS:
+//   err    - Error returned by a trading method
+//   symbol - Symbol of the request ("" = don't look up stops level)
+//
+// RETURNS:
+//   Explanation and true, or false if err carries no MT5 error code
+//
+// EXAMPLE:
+//   _, err := sugar.BuyMarketWithSLTP("EURUSD", 0.1, sl, tp)
+//   if ex, ok := sugar.ExplainErr(err, "EURUSD"); ok {
+//       log.Printf("buy failed: %s", ex)
+//       // TRADE_RETCODE_INVALID_STOPS (10016): SL/TP closer than stops level 30 points. Fix: ...
+//   }
+func (s *MT5Sugar) ExplainErr(err error, symbol string) (helpers.Explanation, bool) {
+	ex, ok := helpers.ExplainErr(err)
+	if !ok || symbol == "" {
+		return ex, ok
+	}
+
+	switch uint32(ex.Code) {
+	case helpers.TradeRetCodeInvalidStops, helpers.TradeRetCodeInvalidPrice, helpers.TradeRetCodeFrozen:
+		symbol = s.ResolveSymbol(symbol)
+
+		ctx, cancel := context.WithTimeout(s.ctx, 3*time.Second)
+		defer cancel()
+
+		// A level that cannot be read stays 0 and keeps the generic text
+		stopsLevel, _ := s.service.GetSymbolInteger(ctx, symbol, pb.SymbolInfoIntegerProperty_SYMBOL_TRADE_STOPS_LEVEL)
+		freezeLevel, _ := s.service.GetSymbolInteger(ctx, symbol, pb.SymbolInfoIntegerProperty_SYMBOL_TRADE_FREEZE_LEVEL)
+		ex = ex.WithStopsLevel(stopsLevel, freezeLevel)
+	}
+	return ex, true
+}
+
+// preTradeChecks refuses an order that the trade guards, the news filter or
+// the rollover block would stop. Shared by placeOrder and ValidateOrder.
+func (s *MT5Sugar) preTradeChecks(ctx context.Context, req *pb.OrderSendRequest) error {
+	if !s.noTradeGuards {
+		if err := s.checkTradeAllowed(ctx, req); err != nil {
+			return err
+		}
+	}
+	if s.news != nil {
+		symbol := req.Symbol
+		if s.symbols != nil {
+			symbol = s.symbols.Canonical(symbol)
+		}
+		if err := s.news.Check(ctx, symbol); errors.Is(err, ErrNewsBlackout) {
+			return err
+		}
+	}
+	return s.checkRollover(time.Now())
+}
+
+// placeOrder sends an order after the trade guards, enforcing
+// MaxDeviationPoints on market orders and SetStopsAutoAdjust on SL/TP.
+func (s *MT5Sugar) placeOrder(ctx context.Context, req *pb.OrderSendRequest) (*OrderResult, error) {
+	s.applyTradeTags(req)
+	buy := OrderRequest{Type: req.Operation}.IsBuy()
+	if err := s.preTradeChecks(ctx, req); err != nil {
+		return nil, err
+	}
+	s.adjustOrderStops(ctx, req)
+
+	s.devMu.Lock()
+	maxDeviation := s.maxDeviation
+	s.devMu.Unlock()
+
+	isMarket := OrderRequest{Type: req.Operation}.IsMarket()
+	if maxDeviation == 0 || !isMarket {
+		return s.service.PlaceOrder(ctx, req)
+	}
+
+	// Requested price = current market at send time
+	tick, err := s.service.GetSymbolTick(ctx, req.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price for deviation check: %w", err)
+	}
+	requested := tick.Bid
+	if buy {
+		requested = tick.Ask
+	}
+	req.Price = &requested
+	if req.Slippage == nil || *req.Slippage > maxDeviation {
+		req.Slippage = &maxDeviation
+	}
+
+	result, err := s.service.PlaceOrder(ctx, req)
+	if err != nil || result.ReturnedCode != 10009 || result.Price <= 0 {
+		return result, err
+	}
+
+	// Verify fill price
+	point, err := s.service.GetSymbolDouble(ctx, req.Symbol, pb.SymbolInfoDoubleProperty_SYMBOL_POINT)
+	if err != nil || point <= 0 {
+		return result, nil
+	}
+	deviation := (requested - result.Price) / point
+	if buy {
+		deviation = (result.Price - requested) / point
+	}
+	if deviation <= float64(maxDeviation) {
+		return result, nil
+	}
+
+	violation := FillDeviation{
+		Time:            time.Now(),
+		Symbol:          req.Symbol,
+		Ticket:          result.Order,
+		Buy:             buy,
+		RequestedPrice:  requested,
+		FillPrice:       result.Price,
+		DeviationPoints: deviation,
+		MaxPoints:       maxDeviation,
+	}
+
+	s.devMu.Lock()
+	s.deviations = append(s.deviations, violation)
+	if len(s.deviations) > 100 {
+		s.deviations = s.deviations[len(s.deviations)-100:]
+	}
+	callback := s.onDeviation
+	s.devMu.Unlock()
+
+	if callback != nil {
+		callback(violation)
+	}
+	return result, nil
+}
+
 // #endregion
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -934,6 +1644,10 @@ func (s *MT5Sugar) SellLimitWithSLTP(symbol string, volume, price, sl, tp float6
 // RETURNS:
 //   Error if close fails or position not found, nil on success
 func (s *MT5Sugar) ClosePosition(ticket uint64) error {
+	if err := s.checkForeign(ticket, true); err != nil {
+		return fmt.Errorf("ClosePosition failed: %w", err)
+	}
+
 	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
 	defer cancel()
 
@@ -964,6 +1678,10 @@ func (s *MT5Sugar) ClosePosition(ticket uint64) error {
 // RETURNS:
 //   Error if close fails, volume invalid, or broker doesn't support partial close
 func (s *MT5Sugar) ClosePositionPartial(ticket uint64, volume float64) error {
+	if err := s.checkForeign(ticket, true); err != nil {
+		return fmt.Errorf("ClosePositionPartial failed: %w", err)
+	}
+
 	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
 	defer cancel()
 
@@ -984,13 +1702,110 @@ func (s *MT5Sugar) ClosePositionPartial(ticket uint64, volume float64) error {
 	return nil
 }
 
-// CloseAllPositions closes all currently open positions across all symbols.
-// Iterates through all positions and attempts to close each one. Continues even
-// if some closes fail. Returns count of successfully closed positions. Uses 30-second timeout.
+// ClosePartialPercent closes a percentage of a position's volume.
+// Volume is rounded DOWN to the symbol's volume step. If the remainder would be
+// below the minimum volume, the whole position is closed. Uses 10-second timeout.
+//
+// PARAMETERS:
+//   ticket  - Position ticket number
+//   percent - Share of volume to close (0-100, e.g., 50 = half)
 //
 // RETURNS:
-//   Number of positions successfully closed (int), and error if operation fails
-func (s *MT5Sugar) CloseAllPositions() (int, error) {
+//   Volume actually closed (float64), or error if close fails
+func (s *MT5Sugar) ClosePartialPercent(ticket uint64, percent float64) (float64, error) {
+	if percent <= 0 || percent > 100 {
+		return 0, fmt.Errorf("percent must be in (0, 100], got %.2f", percent)
+	}
+
+	pos, err := s.GetPositionByTicket(ticket)
+	if err != nil {
+		return 0, fmt.Errorf("ClosePartialPercent failed: %w", err)
+	}
+
+	return s.closePartialVolume(pos, pos.Volume*percent/100.0)
+}
+
+// ClosePartialAmount closes the part of a position whose floating P/L equals
+// amount (in account currency). Volume is derived from tick value/size and the
+// distance between open and current price, then rounded DOWN to volume step.
+// Handy for "bank $100 of this winner" or "cut $50 of this loser". Uses 10-second timeout.
+//
+// PARAMETERS:
+//   ticket - Position ticket number
+//   amount - P/L amount in account currency to realize (sign ignored)
+//
+// RETURNS:
+//   Volume actually closed (float64), or error if price has not moved or close fails
+func (s *MT5Sugar) ClosePartialAmount(ticket uint64, amount float64) (float64, error) {
+	amount = math.Abs(amount)
+	if amount == 0 {
+		return 0, fmt.Errorf("amount must not be zero")
+	}
+
+	pos, err := s.GetPositionByTicket(ticket)
+	if err != nil {
+		return 0, fmt.Errorf("ClosePartialAmount failed: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, 5*time.Second)
+	defer cancel()
+
+	tickValue, err := s.service.GetSymbolDouble(ctx, pos.Symbol, pb.SymbolInfoDoubleProperty_SYMBOL_TRADE_TICK_VALUE)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get tick value: %w", err)
+	}
+	tickSize, err := s.service.GetSymbolDouble(ctx, pos.Symbol, pb.SymbolInfoDoubleProperty_SYMBOL_TRADE_TICK_SIZE)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get tick size: %w", err)
+	}
+	if tickSize <= 0 || tickValue <= 0 {
+		return 0, fmt.Errorf("invalid tick value/size for %s", pos.Symbol)
+	}
+
+	// Floating P/L of 1.0 lot at current price
+	perLot := math.Abs(pos.PriceCurrent-pos.PriceOpen) / tickSize * tickValue
+	if perLot == 0 {
+		return 0, fmt.Errorf("position #%d has no floating P/L to realize", ticket)
+	}
+
+	return s.closePartialVolume(pos, amount/perLot)
+}
+
+// closePartialVolume normalizes volume to the symbol's step and closes it.
+func (s *MT5Sugar) closePartialVolume(pos *pb.PositionInfo, volume float64) (float64, error) {
+	info, err := s.GetSymbolInfo(pos.Symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	if info.VolumeStep > 0 {
+		volume = math.Floor(volume/info.VolumeStep+1e-9) * info.VolumeStep
+	}
+	if volume < info.VolumeMin {
+		return 0, fmt.Errorf("volume %.4f below minimum %.2f for %s", volume, info.VolumeMin, pos.Symbol)
+	}
+
+	// Remainder too small to stay open - close everything
+	if volume >= pos.Volume || pos.Volume-volume < info.VolumeMin {
+		if err := s.ClosePosition(pos.Ticket); err != nil {
+			return 0, err
+		}
+		return pos.Volume, nil
+	}
+
+	if err := s.ClosePositionPartial(pos.Ticket, volume); err != nil {
+		return 0, err
+	}
+	return volume, nil
+}
+
+// CloseAllPositions closes all currently open positions across all symbols.
+// Iterates through all positions and attempts to close each one. Continues even
+// if some closes fail. Returns count of successfully closed positions. Uses 30-second timeout.
+//
+// RETURNS:
+//   Number of positions successfully closed (int), and error if operation fails
+func (s *MT5Sugar) CloseAllPositions() (int, error) {
 	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
 	defer cancel()
 
@@ -1001,6 +1816,9 @@ func (s *MT5Sugar) CloseAllPositions() (int, error) {
 
 	closed := 0
 	for _, pos := range data.PositionInfos {
+		if !s.mayClose(pos) {
+			continue
+		}
 		closeReq := &pb.OrderCloseRequest{
 			Ticket: pos.Ticket,
 		}
@@ -1025,6 +1843,8 @@ func (s *MT5Sugar) CloseAllPositions() (int, error) {
 // RETURNS:
 //   Number of positions successfully closed (int), and error if operation fails
 func (s *MT5Sugar) CloseAllBySymbol(symbol string) (int, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
 	defer cancel()
 
@@ -1035,7 +1855,7 @@ func (s *MT5Sugar) CloseAllBySymbol(symbol string) (int, error) {
 
 	closed := 0
 	for _, pos := range data.PositionInfos {
-		if pos.Symbol == symbol {
+		if pos.Symbol == symbol && s.mayClose(pos) {
 			closeReq := &pb.OrderCloseRequest{
 				Ticket: pos.Ticket,
 			}
@@ -1050,6 +1870,140 @@ func (s *MT5Sugar) CloseAllBySymbol(symbol string) (int, error) {
 	return closed, nil
 }
 
+// CloseResult is the outcome of closing one position in a group close.
+//
+// FIELDS:
+//   Ticket       - Position ticket
+//   Symbol       - Position symbol
+//   Volume       - Position volume
+//   Profit       - Floating profit at the moment of selection
+//   ReturnedCode - Broker return code (10009 = closed)
+//   Err          - Error if the close failed or was rejected, nil on success
+type CloseResult struct {
+	Ticket       uint64
+	Symbol       string
+	Volume       float64
+	Profit       float64
+	ReturnedCode uint32
+	Err          error
+}
+
+// closeWhere closes all positions matching filter through the worker pool
+// (parallel across symbols, one at a time per symbol) and reports the outcome
+// per ticket. Uses 30-second timeout.
+func (s *MT5Sugar) closeWhere(filter func(pos *pb.PositionInfo) bool) ([]CloseResult, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
+	defer cancel()
+
+	data, err := s.service.GetOpenedOrders(ctx, pb.BMT5_ENUM_OPENED_ORDER_SORT_TYPE_BMT5_OPENED_ORDER_SORT_BY_OPEN_TIME_ASC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	var selected []*pb.PositionInfo
+	for _, pos := range data.PositionInfos {
+		if filter(pos) && s.mayClose(pos) {
+			selected = append(selected, pos)
+		}
+	}
+
+	results := make([]CloseResult, len(selected))
+	tasks := make([]SymbolTask, len(selected))
+	for i, pos := range selected {
+		i, pos := i, pos
+		results[i] = CloseResult{
+			Ticket: pos.Ticket,
+			Symbol: pos.Symbol,
+			Volume: pos.Volume,
+			Profit: pos.Profit,
+		}
+		tasks[i] = SymbolTask{Symbol: pos.Symbol, Run: func(ctx context.Context) error {
+			retCode, err := s.service.CloseOrder(ctx, &pb.OrderCloseRequest{Ticket: pos.Ticket})
+			results[i].ReturnedCode = retCode
+			if err == nil && retCode != 10009 {
+				err = fmt.Errorf("close rejected, code: %d", retCode)
+			}
+			return err
+		}}
+	}
+	for i, err := range s.pool.Run(ctx, tasks) {
+		results[i].Err = err
+	}
+
+	return results, nil
+}
+
+// CloseAllProfitable closes every position with positive floating profit.
+// Closes run concurrently. Uses 30-second timeout.
+//
+// RETURNS:
+//   Per-ticket results ([]CloseResult), and error if positions could not be read
+func (s *MT5Sugar) CloseAllProfitable() ([]CloseResult, error) {
+	return s.closeWhere(func(pos *pb.PositionInfo) bool {
+		return pos.Profit > 0
+	})
+}
+
+// CloseAllLosing closes every position whose floating loss exceeds threshold.
+// Closes run concurrently. Uses 30-second timeout.
+//
+// PARAMETERS:
+//   threshold - Loss amount in account currency (e.g., 50 closes positions below -50; 0 = all losing)
+//
+// RETURNS:
+//   Per-ticket results ([]CloseResult), and error if positions could not be read
+func (s *MT5Sugar) CloseAllLosing(threshold float64) ([]CloseResult, error) {
+	limit := -math.Abs(threshold)
+	return s.closeWhere(func(pos *pb.PositionInfo) bool {
+		return pos.Profit < 0 && pos.Profit <= limit
+	})
+}
+
+// CloseBySymbol closes all positions of a symbol concurrently.
+// Unlike CloseAllBySymbol, returns the outcome of every ticket. Uses 30-second timeout.
+//
+// PARAMETERS:
+//   symbol - Trading symbol (e.g., "EURUSD")
+//
+// RETURNS:
+//   Per-ticket results ([]CloseResult), and error if positions could not be read
+func (s *MT5Sugar) CloseBySymbol(symbol string) ([]CloseResult, error) {
+	symbol = s.ResolveSymbol(symbol)
+
+	return s.closeWhere(func(pos *pb.PositionInfo) bool {
+		return pos.Symbol == symbol
+	})
+}
+
+// CloseByMagic closes all positions opened with the given magic number (EA ID).
+// Closes run concurrently. Uses 30-second timeout.
+//
+// PARAMETERS:
+//   magic - Magic number (ExpertId) used when opening the positions
+//
+// RETURNS:
+//   Per-ticket results ([]CloseResult), and error if positions could not be read
+func (s *MT5Sugar) CloseByMagic(magic int64) ([]CloseResult, error) {
+	return s.closeWhere(func(pos *pb.PositionInfo) bool {
+		return pos.MagicNumber == magic
+	})
+}
+
+// CloseOlderThan closes all positions open for longer than age.
+// Closes run concurrently. Uses 30-second timeout.
+//
+// PARAMETERS:
+//   age - Minimum position age (e.g., 24*time.Hour)
+//
+// RETURNS:
+//   Per-ticket results ([]CloseResult), and error if positions could not be read
+func (s *MT5Sugar) CloseOlderThan(age time.Duration) ([]CloseResult, error) {
+	cutoff := time.Now().Add(-age)
+	return s.closeWhere(func(pos *pb.PositionInfo) bool {
+		return pos.OpenTime != nil && pos.OpenTime.AsTime().Before(cutoff)
+	})
+}
+
 // ModifyPositionSL modifies the Stop Loss level of an open position.
 // This allows you to move your stop loss to lock in profit or reduce risk.
 // Use 0 to remove Stop Loss (if broker allows). Uses 10-second timeout.
@@ -1061,6 +2015,10 @@ func (s *MT5Sugar) CloseAllBySymbol(symbol string) (int, error) {
 // RETURNS:
 //   Error if modification rejected or fails, nil on success
 func (s *MT5Sugar) ModifyPositionSL(ticket uint64, sl float64) error {
+	if err := s.checkForeign(ticket, false); err != nil {
+		return fmt.Errorf("ModifyPositionSL failed: %w", err)
+	}
+
 	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
 	defer cancel()
 
@@ -1068,81 +2026,673 @@ func (s *MT5Sugar) ModifyPositionSL(ticket uint64, sl float64) error {
 		Ticket:   ticket,
 		StopLoss: &sl,
 	}
+	s.adjustPositionStops(ctx, ticket, req.StopLoss, nil)
 
 	result, err := s.service.ModifyOrder(ctx, req)
 	if err != nil {
 		return fmt.Errorf("ModifyPositionSL failed: %w", err)
 	}
 
-	if result.ReturnedCode != 10009 {
-		return fmt.Errorf("modify rejected, code: %d, comment: %s", result.ReturnedCode, result.Comment)
-	}
+	if err := result.Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ModifyPositionTP modifies the Take Profit level of an open position.
+// This allows you to adjust your profit target based on market conditions.
+// Use 0 to remove Take Profit (if broker allows). Uses 10-second timeout.
+//
+// PARAMETERS:
+//   ticket - Position ticket number
+//   tp     - New Take Profit price (must be valid for position direction)
+//
+// RETURNS:
+//   Error if modification rejected or fails, nil on success
+func (s *MT5Sugar) ModifyPositionTP(ticket uint64, tp float64) error {
+	if err := s.checkForeign(ticket, false); err != nil {
+		return fmt.Errorf("ModifyPositionTP failed: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+	defer cancel()
+
+	req := &pb.OrderModifyRequest{
+		Ticket:     ticket,
+		TakeProfit: &tp,
+	}
+	s.adjustPositionStops(ctx, ticket, nil, req.TakeProfit)
+
+	result, err := s.service.ModifyOrder(ctx, req)
+	if err != nil {
+		return fmt.Errorf("ModifyPositionTP failed: %w", err)
+	}
+
+	if err := result.Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ModifyPositionSLTP modifies both Stop Loss and Take Profit in one operation.
+// More efficient than calling ModifyPositionSL and ModifyPositionTP separately.
+// This is the recommended way to update risk management levels. Uses 10-second timeout.
+//
+// PARAMETERS:
+//   ticket - Position ticket number
+//   sl     - New Stop Loss price (must be valid for position direction)
+//   tp     - New Take Profit price (must be valid for position direction)
+//
+// RETURNS:
+//   Error if modification rejected or fails, nil on success
+func (s *MT5Sugar) ModifyPositionSLTP(ticket uint64, sl, tp float64) error {
+	if err := s.checkForeign(ticket, false); err != nil {
+		return fmt.Errorf("ModifyPositionSLTP failed: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+	defer cancel()
+
+	req := &pb.OrderModifyRequest{
+		Ticket:     ticket,
+		StopLoss:   &sl,
+		TakeProfit: &tp,
+	}
+	s.adjustPositionStops(ctx, ticket, req.StopLoss, req.TakeProfit)
+
+	result, err := s.service.ModifyOrder(ctx, req)
+	if err != nil {
+		return fmt.Errorf("ModifyPositionSLTP failed: %w", err)
+	}
+
+	if err := result.Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ReversePosition flips a position to the opposite direction.
+// On hedging accounts the position is closed and a new opposite position is opened;
+// if the open fails after the close, the error says the position was already closed.
+// On netting accounts opposite orders totalling (volume + new volume) are sent,
+// which reverse the net position. The total is rounded to the volume step and split
+// into several orders when it exceeds the symbol's VolumeMax; if one of them fails,
+// the error reports how much volume was already executed. Uses 10-second timeout per step.
+//
+// PARAMETERS:
+//   ticket     - Position ticket to reverse
+//   volumeMult - New volume as multiple of current volume (1.0 = same size, <=0 treated as 1.0)
+//
+// RETURNS:
+//   Ticket of the new position/order (uint64; the last one if split), or error if any step fails
+func (s *MT5Sugar) ReversePosition(ticket uint64, volumeMult float64) (uint64, error) {
+	if volumeMult <= 0 {
+		volumeMult = 1.0
+	}
+
+	pos, err := s.GetPositionByTicket(ticket)
+	if err != nil {
+		return 0, fmt.Errorf("ReversePosition failed: %w", err)
+	}
+
+	hedging, err := s.isHedgingAccount()
+	if err != nil {
+		return 0, fmt.Errorf("ReversePosition failed: %w", err)
+	}
+
+	info, err := s.GetSymbolInfo(pos.Symbol)
+	if err != nil {
+		return 0, fmt.Errorf("ReversePosition failed: %w", err)
+	}
+	limits := SizingInput{
+		VolumeMin:  info.VolumeMin,
+		VolumeMax:  info.VolumeMax,
+		VolumeStep: info.VolumeStep,
+	}
+	newVolume := NormalizeVolume(pos.Volume*volumeMult, limits)
+
+	req := OrderRequest{
+		Symbol:  pos.Symbol,
+		Type:    pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL,
+		Volume:  newVolume,
+		Comment: fmt.Sprintf("reverse #%d", ticket),
+	}
+	if pos.MagicNumber > 0 {
+		req.Magic = uint64(pos.MagicNumber)
+	}
+	if pos.Type == pb.BMT5_ENUM_POSITION_TYPE_BMT5_POSITION_TYPE_SELL {
+		req.Type = pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY
+	}
+
+	if hedging {
+		if err := s.ClosePosition(ticket); err != nil {
+			return 0, fmt.Errorf("ReversePosition failed to close #%d: %w", ticket, err)
+		}
+		newTicket, err := s.SendOrder(req)
+		if err != nil {
+			return 0, fmt.Errorf("ReversePosition closed #%d but failed to open the opposite position: %w", ticket, err)
+		}
+		return newTicket, nil
+	}
+
+	// Netting: opposite deals close the old side and open the new one
+	total := roundVolume(pos.Volume + newVolume)
+	chunks := splitVolume(total, limits)
+	if len(chunks) == 0 {
+		return 0, fmt.Errorf("ReversePosition #%d: volume %.2f is below the volume step", ticket, total)
+	}
+
+	var newTicket uint64
+	var done float64
+	for _, volume := range chunks {
+		req.Volume = volume
+		sent, err := s.SendOrder(req)
+		if err != nil {
+			if done == 0 {
+				return 0, fmt.Errorf("ReversePosition failed to open opposite: %w", err)
+			}
+			return newTicket, fmt.Errorf("ReversePosition #%d stopped after %.2f of %.2f lots: %w", ticket, done, total, err)
+		}
+		newTicket = sent
+		done = roundVolume(done + volume)
+	}
+
+	return newTicket, nil
+}
+
+// HedgePosition opens an opposite position that offsets ratio of an existing one.
+// Only possible on hedging accounts; on netting accounts an opposite order would
+// simply reduce the position, so ErrNettingAccount is returned. Uses 10-second timeout.
+//
+// PARAMETERS:
+//   ticket - Position ticket to hedge
+//   ratio  - Hedge volume as share of position volume (1.0 = fully hedged)
+//
+// RETURNS:
+//   Ticket of the hedge position (uint64), or error (ErrNettingAccount on netting accounts)
+func (s *MT5Sugar) HedgePosition(ticket uint64, ratio float64) (uint64, error) {
+	if ratio <= 0 {
+		return 0, fmt.Errorf("hedge ratio must be positive, got %.2f", ratio)
+	}
+
+	hedging, err := s.isHedgingAccount()
+	if err != nil {
+		return 0, fmt.Errorf("HedgePosition failed: %w", err)
+	}
+	if !hedging {
+		return 0, fmt.Errorf("HedgePosition #%d: %w", ticket, ErrNettingAccount)
+	}
+
+	pos, err := s.GetPositionByTicket(ticket)
+	if err != nil {
+		return 0, fmt.Errorf("HedgePosition failed: %w", err)
+	}
+
+	volume, err := s.normalizeVolume(pos.Symbol, pos.Volume*ratio)
+	if err != nil {
+		return 0, fmt.Errorf("HedgePosition failed: %w", err)
+	}
+
+	req := OrderRequest{
+		Symbol:  pos.Symbol,
+		Type:    pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL,
+		Volume:  volume,
+		Comment: fmt.Sprintf("hedge #%d", ticket),
+	}
+	if pos.MagicNumber > 0 {
+		req.Magic = uint64(pos.MagicNumber)
+	}
+	if pos.Type == pb.BMT5_ENUM_POSITION_TYPE_BMT5_POSITION_TYPE_SELL {
+		req.Type = pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY
+	}
+
+	return s.SendOrder(req)
+}
+
+// AddToPosition scales into an existing position (manual pyramiding). The
+// add-on is sent in the position's direction with the same magic, comment
+// and SL/TP, so it is managed and reported together with the original.
+// Volume is rounded down to the symbol's volume step and capped so that the
+// total of the position plus earlier add-ons (same symbol, direction and
+// magic) stays within maxTotalVolume. Required margin is checked against
+// free margin before sending. Uses 10-second timeout.
+//
+// PARAMETERS:
+//   ticket         - Position ticket to add to
+//   volume         - Volume to add in lots
+//   maxTotalVolume - Cap on the combined volume (0 = no cap)
+//
+// RETURNS:
+//   Ticket of the add-on (uint64; on netting accounts the position ticket),
+//   or error (ErrMaxVolumeReached when there is no room left)
+func (s *MT5Sugar) AddToPosition(ticket uint64, volume, maxTotalVolume float64) (uint64, error) {
+	if volume <= 0 {
+		return 0, fmt.Errorf("add volume must be positive, got %.2f", volume)
+	}
+
+	pos, err := s.GetPositionByTicket(ticket)
+	if err != nil {
+		return 0, fmt.Errorf("AddToPosition failed: %w", err)
+	}
+
+	capped := false
+	total := 0.0
+	if maxTotalVolume > 0 {
+		positions, err := s.GetPositionsBySymbol(pos.Symbol)
+		if err != nil {
+			return 0, fmt.Errorf("AddToPosition failed: %w", err)
+		}
+		for _, p := range positions {
+			if p.Type == pos.Type && p.MagicNumber == pos.MagicNumber {
+				total += p.Volume
+			}
+		}
+		if room := maxTotalVolume - total; volume > room {
+			volume, capped = room, true
+		}
+	}
+
+	info, err := s.GetSymbolInfo(pos.Symbol)
+	if err != nil {
+		return 0, fmt.Errorf("AddToPosition failed: %w", err)
+	}
+	if volume < info.VolumeMin {
+		if capped {
+			return 0, fmt.Errorf("AddToPosition #%d: %w (%.2f of %.2f lots)", ticket, ErrMaxVolumeReached, total, maxTotalVolume)
+		}
+		return 0, fmt.Errorf("AddToPosition #%d: volume %.2f below minimum %.2f", ticket, volume, info.VolumeMin)
+	}
+	volume, err = s.normalizeVolume(pos.Symbol, volume)
+	if err != nil {
+		return 0, fmt.Errorf("AddToPosition failed: %w", err)
+	}
+
+	req := OrderRequest{
+		Symbol:     pos.Symbol,
+		Type:       pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY,
+		Volume:     volume,
+		StopLoss:   pos.StopLoss,
+		TakeProfit: pos.TakeProfit,
+		Comment:    pos.Comment,
+	}
+	if pos.MagicNumber > 0 {
+		req.Magic = uint64(pos.MagicNumber)
+	}
+	marginType := pb.ENUM_ORDER_TYPE_TF_ORDER_TYPE_TF_BUY
+	price := info.Ask
+	if pos.Type == pb.BMT5_ENUM_POSITION_TYPE_BMT5_POSITION_TYPE_SELL {
+		req.Type = pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL
+		marginType = pb.ENUM_ORDER_TYPE_TF_ORDER_TYPE_TF_SELL
+		price = info.Bid
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+	defer cancel()
+
+	required, err := s.service.CalculateMargin(ctx, &pb.OrderCalcMarginRequest{
+		Symbol:    pos.Symbol,
+		OrderType: marginType,
+		Volume:    volume,
+		OpenPrice: price,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("AddToPosition failed: %w", err)
+	}
+	freeMargin, err := s.GetFreeMargin()
+	if err != nil {
+		return 0, fmt.Errorf("AddToPosition failed: %w", err)
+	}
+	if required > freeMargin {
+		return 0, fmt.Errorf("AddToPosition #%d: insufficient margin: need %.2f, have %.2f", ticket, required, freeMargin)
+	}
+
+	newTicket, err := s.SendOrder(req)
+	if err != nil {
+		return 0, fmt.Errorf("AddToPosition failed: %w", err)
+	}
+	return newTicket, nil
+}
+
+// FlipResult is the consolidated outcome of FlipTo.
+//
+// FIELDS:
+//   Symbol    - Trading symbol (broker name)
+//   Buy       - Requested direction
+//   Closed    - Opposite positions closed (hedging accounts), per ticket
+//   Realized  - Floating profit of the closed positions when they were closed
+//   Ticket    - Ticket of the new position/order (0 if nothing was opened)
+//   Opened    - Volume sent in the requested direction (excluding netting close-out)
+//   NetVolume - Net volume on the symbol afterwards (positive = long)
+type FlipResult struct {
+	Symbol    string
+	Buy       bool
+	Closed    []CloseResult
+	Realized  float64
+	Ticket    uint64
+	Opened    float64
+	NetVolume float64
+}
+
+// FlipTo flattens any exposure on the symbol against the requested direction
+// and establishes a position of the given volume in that direction.
+// On hedging accounts every opposite position is closed (the API has no
+// close-by order), then the difference between volume and the volume already
+// held in the direction is opened. On netting accounts one order nets out the
+// opposite position and opens the new side in a single deal. Existing
+// volume above the requested one is left untouched. Uses 30-second timeout.
+//
+// PARAMETERS:
+//   symbol    - Trading symbol (e.g., "EURUSD")
+//   direction - "BUY" or "SELL"
+//   volume    - Target volume in the requested direction
+//
+// RETURNS:
+//   *FlipResult, or error if a close or the new order failed (the result
+//   then describes the steps completed so far)
+func (s *MT5Sugar) FlipTo(symbol, direction string, volume float64) (*FlipResult, error) {
+	symbol = s.ResolveSymbol(symbol)
+
+	var buy bool
+	switch strings.ToUpper(direction) {
+	case "BUY":
+		buy = true
+	case "SELL":
+	default:
+		return nil, fmt.Errorf("FlipTo failed: direction must be BUY or SELL, got %q", direction)
+	}
+	if volume <= 0 {
+		return nil, fmt.Errorf("FlipTo failed: volume must be positive, got %.2f", volume)
+	}
+
+	hedging, err := s.isHedgingAccount()
+	if err != nil {
+		return nil, fmt.Errorf("FlipTo failed: %w", err)
+	}
+	positions, err := s.GetPositionsBySymbol(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("FlipTo failed: %w", err)
+	}
+
+	isBuy := func(pos *pb.PositionInfo) bool {
+		return pos.Type == pb.BMT5_ENUM_POSITION_TYPE_BMT5_POSITION_TYPE_BUY
+	}
+	held, opposite := 0.0, 0.0
+	for _, pos := range positions {
+		if isBuy(pos) == buy {
+			held += pos.Volume
+		} else {
+			opposite += pos.Volume
+		}
+	}
+
+	result := &FlipResult{Symbol: symbol, Buy: buy}
+	net := func() float64 {
+		if buy {
+			return held - opposite
+		}
+		return opposite - held
+	}
+
+	if hedging && opposite > 0 {
+		result.Closed, err = s.closeWhere(func(pos *pb.PositionInfo) bool {
+			return pos.Symbol == symbol && isBuy(pos) != buy
+		})
+		if err != nil {
+			result.NetVolume = net()
+			return result, fmt.Errorf("FlipTo failed: %w", err)
+		}
+		for _, c := range result.Closed {
+			if c.Err != nil {
+				result.NetVolume = net()
+				return result, fmt.Errorf("FlipTo failed to close #%d: %w", c.Ticket, c.Err)
+			}
+			result.Realized += c.Profit
+			opposite -= c.Volume
+		}
+	}
+
+	add := 0.0
+	if volume > held {
+		add, err = s.normalizeVolume(symbol, volume-held)
+		if err != nil {
+			result.NetVolume = net()
+			return result, fmt.Errorf("FlipTo failed: %w", err)
+		}
+	}
+	send := add
+	if !hedging {
+		send += opposite // Netting: the same deal closes the opposite side
+	}
+	if send <= 0 {
+		result.NetVolume = net()
+		return result, nil
+	}
+
+	req := OrderRequest{
+		Symbol:  symbol,
+		Type:    pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL,
+		Volume:  send,
+		Comment: "flip",
+	}
+	if buy {
+		req.Type = pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY
+	}
+	result.Ticket, err = s.SendOrder(req)
+	if err != nil {
+		result.NetVolume = net()
+		return result, fmt.Errorf("FlipTo failed to open %s: %w", strings.ToUpper(direction), err)
+	}
+	if !hedging {
+		opposite = 0
+	}
+	held += add
+	result.Opened = add
+	result.NetVolume = net()
+
+	return result, nil
+}
+
+// PositionFilter selects positions for bulk operations. Zero fields match everything.
+//
+// FIELDS:
+//   Symbol - Only positions of this symbol ("" = all symbols)
+//   Magic  - Only positions with this magic number (0 = any magic)
+type PositionFilter struct {
+	Symbol string
+	Magic  int64
+}
+
+// Match reports whether a position passes the filter.
+func (f PositionFilter) Match(pos *pb.PositionInfo) bool {
+	if f.Symbol != "" && pos.Symbol != f.Symbol {
+		return false
+	}
+	if f.Magic != 0 && pos.MagicNumber != f.Magic {
+		return false
+	}
+	return true
+}
+
+// StopTarget describes a SL or TP level either as distance or as absolute price.
+//
+// FIELDS:
+//   Points - Distance from position open price in points (used if > 0)
+//   Price  - Absolute price level (used if Points is 0)
+type StopTarget struct {
+	Points float64
+	Price  float64
+}
+
+// ModifyResult is the outcome of modifying one position in a bulk operation.
+//
+// FIELDS:
+//   Ticket       - Position ticket
+//   Symbol       - Position symbol
+//   StopLoss     - SL sent to the broker
+//   TakeProfit   - TP sent to the broker
+//   ReturnedCode - Broker return code (10009 = modified)
+//   Err          - Error if the modification failed or was rejected, nil on success
+type ModifyResult struct {
+	Ticket       uint64
+	Symbol       string
+	StopLoss     float64
+	TakeProfit   float64
+	ReturnedCode uint32
+	Err          error
+}
 
-	return nil
+// SetStopLossAll sets Stop Loss on all matching positions concurrently.
+// With Points, SL is placed that many points from each position's open price
+// (below for BUY, above for SELL). Uses 30-second timeout.
+//
+// PARAMETERS:
+//   target - SL as points from open price, or absolute price
+//   filter - Which positions to modify (zero value = all)
+//
+// RETURNS:
+//   Per-ticket results ([]ModifyResult), and error if positions could not be read
+func (s *MT5Sugar) SetStopLossAll(target StopTarget, filter PositionFilter) ([]ModifyResult, error) {
+	return s.modifyWhere(filter, func(pos *pb.PositionInfo, point float64) *pb.OrderModifyRequest {
+		sl := target.Price
+		if target.Points > 0 {
+			sl = pos.PriceOpen - target.Points*point
+			if pos.Type == pb.BMT5_ENUM_POSITION_TYPE_BMT5_POSITION_TYPE_SELL {
+				sl = pos.PriceOpen + target.Points*point
+			}
+		}
+		return &pb.OrderModifyRequest{Ticket: pos.Ticket, StopLoss: &sl}
+	})
 }
 
-// ModifyPositionTP modifies the Take Profit level of an open position.
-// This allows you to adjust your profit target based on market conditions.
-// Use 0 to remove Take Profit (if broker allows). Uses 10-second timeout.
+// SetTakeProfitAll sets Take Profit on all matching positions concurrently.
+// With Points, TP is placed that many points from each position's open price
+// (above for BUY, below for SELL). Uses 30-second timeout.
 //
 // PARAMETERS:
-//   ticket - Position ticket number
-//   tp     - New Take Profit price (must be valid for position direction)
+//   target - TP as points from open price, or absolute price
+//   filter - Which positions to modify (zero value = all)
 //
 // RETURNS:
-//   Error if modification rejected or fails, nil on success
-func (s *MT5Sugar) ModifyPositionTP(ticket uint64, tp float64) error {
-	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+//   Per-ticket results ([]ModifyResult), and error if positions could not be read
+func (s *MT5Sugar) SetTakeProfitAll(target StopTarget, filter PositionFilter) ([]ModifyResult, error) {
+	return s.modifyWhere(filter, func(pos *pb.PositionInfo, point float64) *pb.OrderModifyRequest {
+		tp := target.Price
+		if target.Points > 0 {
+			tp = pos.PriceOpen + target.Points*point
+			if pos.Type == pb.BMT5_ENUM_POSITION_TYPE_BMT5_POSITION_TYPE_SELL {
+				tp = pos.PriceOpen - target.Points*point
+			}
+		}
+		return &pb.OrderModifyRequest{Ticket: pos.Ticket, TakeProfit: &tp}
+	})
+}
+
+// RemoveStops removes both SL and TP from all matching positions concurrently.
+// Uses 30-second timeout.
+//
+// PARAMETERS:
+//   filter - Which positions to modify (zero value = all)
+//
+// RETURNS:
+//   Per-ticket results ([]ModifyResult), and error if positions could not be read
+func (s *MT5Sugar) RemoveStops(filter PositionFilter) ([]ModifyResult, error) {
+	return s.modifyWhere(filter, func(pos *pb.PositionInfo, point float64) *pb.OrderModifyRequest {
+		zero := 0.0
+		return &pb.OrderModifyRequest{Ticket: pos.Ticket, StopLoss: &zero, TakeProfit: &zero}
+	})
+}
+
+// modifyWhere applies build to every matching position through the worker pool.
+// build receives the symbol point size and returns the modify request.
+func (s *MT5Sugar) modifyWhere(filter PositionFilter, build func(pos *pb.PositionInfo, point float64) *pb.OrderModifyRequest) ([]ModifyResult, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
 	defer cancel()
 
-	req := &pb.OrderModifyRequest{
-		Ticket:     ticket,
-		TakeProfit: &tp,
+	data, err := s.service.GetOpenedOrders(ctx, pb.BMT5_ENUM_OPENED_ORDER_SORT_TYPE_BMT5_OPENED_ORDER_SORT_BY_OPEN_TIME_ASC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
 	}
 
-	result, err := s.service.ModifyOrder(ctx, req)
-	if err != nil {
-		return fmt.Errorf("ModifyPositionTP failed: %w", err)
+	var selected []*pb.PositionInfo
+	points := make(map[string]float64)
+	for _, pos := range data.PositionInfos {
+		if !filter.Match(pos) {
+			continue
+		}
+		selected = append(selected, pos)
+		if _, ok := points[pos.Symbol]; !ok {
+			point, err := s.service.GetSymbolDouble(ctx, pos.Symbol, pb.SymbolInfoDoubleProperty_SYMBOL_POINT)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get point for %s: %w", pos.Symbol, err)
+			}
+			points[pos.Symbol] = point
+		}
 	}
 
-	if result.ReturnedCode != 10009 {
-		return fmt.Errorf("modify rejected, code: %d, comment: %s", result.ReturnedCode, result.Comment)
+	results := make([]ModifyResult, len(selected))
+	tasks := make([]SymbolTask, len(selected))
+	for i, pos := range selected {
+		i, pos := i, pos
+		req := build(pos, points[pos.Symbol])
+		results[i] = ModifyResult{
+			Ticket:     pos.Ticket,
+			Symbol:     pos.Symbol,
+			StopLoss:   pos.StopLoss,
+			TakeProfit: pos.TakeProfit,
+		}
+		if req.StopLoss != nil {
+			results[i].StopLoss = *req.StopLoss
+		}
+		if req.TakeProfit != nil {
+			results[i].TakeProfit = *req.TakeProfit
+		}
+
+		tasks[i] = SymbolTask{Symbol: pos.Symbol, Run: func(ctx context.Context) error {
+			res, err := s.service.ModifyOrder(ctx, req)
+			if err != nil {
+				return err
+			}
+			results[i].ReturnedCode = res.ReturnedCode
+			if err := res.Err(); err != nil {
+				return err
+			}
+			return nil
+		}}
+	}
+	for i, err := range s.pool.Run(ctx, tasks) {
+		results[i].Err = err
 	}
 
-	return nil
+	return results, nil
 }
 
-// ModifyPositionSLTP modifies both Stop Loss and Take Profit in one operation.
-// More efficient than calling ModifyPositionSL and ModifyPositionTP separately.
-// This is the recommended way to update risk management levels. Uses 10-second timeout.
-//
-// PARAMETERS:
-//   ticket - Position ticket number
-//   sl     - New Stop Loss price (must be valid for position direction)
-//   tp     - New Take Profit price (must be valid for position direction)
-//
-// RETURNS:
-//   Error if modification rejected or fails, nil on success
-func (s *MT5Sugar) ModifyPositionSLTP(ticket uint64, sl, tp float64) error {
-	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+// isHedgingAccount reports whether the account allows multiple opposite positions per symbol.
+func (s *MT5Sugar) isHedgingAccount() (bool, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, 3*time.Second)
 	defer cancel()
 
-	req := &pb.OrderModifyRequest{
-		Ticket:     ticket,
-		StopLoss:   &sl,
-		TakeProfit: &tp,
-	}
-
-	result, err := s.service.ModifyOrder(ctx, req)
+	// ENUM_ACCOUNT_MARGIN_MODE: 0 = retail netting, 1 = exchange, 2 = retail hedging
+	mode, err := s.service.GetAccountInteger(ctx, pb.AccountInfoIntegerPropertyType_ACCOUNT_MARGIN_MODE)
 	if err != nil {
-		return fmt.Errorf("ModifyPositionSLTP failed: %w", err)
+		return false, fmt.Errorf("failed to get margin mode: %w", err)
 	}
+	return mode == 2, nil
+}
 
-	if result.ReturnedCode != 10009 {
-		return fmt.Errorf("modify rejected, code: %d, comment: %s", result.ReturnedCode, result.Comment)
+// normalizeVolume rounds volume down to the symbol's volume step and clamps to min/max.
+func (s *MT5Sugar) normalizeVolume(symbol string, volume float64) (float64, error) {
+	info, err := s.GetSymbolInfo(symbol)
+	if err != nil {
+		return 0, err
 	}
-
-	return nil
+	return NormalizeVolume(volume, SizingInput{
+		VolumeMin:  info.VolumeMin,
+		VolumeMax:  info.VolumeMax,
+		VolumeStep: info.VolumeStep,
+	}), nil
 }
 
 // #endregion
@@ -1204,6 +2754,8 @@ func (s *MT5Sugar) GetPositionByTicket(ticket uint64) (*pb.PositionInfo, error)
 // RETURNS:
 //   Slice of *pb.PositionInfo for the symbol, or error if query fails
 func (s *MT5Sugar) GetPositionsBySymbol(symbol string) ([]*pb.PositionInfo, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	positions, err := s.GetOpenPositions()
 	if err != nil {
 		return nil, err
@@ -1229,6 +2781,8 @@ func (s *MT5Sugar) GetPositionsBySymbol(symbol string) ([]*pb.PositionInfo, erro
 // RETURNS:
 //   true if at least one position exists, false otherwise, or error if query fails
 func (s *MT5Sugar) HasOpenPosition(symbol string) (bool, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	positions, err := s.GetPositionsBySymbol(symbol)
 	if err != nil {
 		return false, err
@@ -1285,6 +2839,8 @@ func (s *MT5Sugar) GetTotalProfit() (float64, error) {
 // RETURNS:
 //   Total profit/loss for symbol as float64, or error if query fails
 func (s *MT5Sugar) GetProfitBySymbol(symbol string) (float64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	positions, err := s.GetPositionsBySymbol(symbol)
 	if err != nil {
 		return 0, err
@@ -1298,6 +2854,112 @@ func (s *MT5Sugar) GetProfitBySymbol(symbol string) (float64, error) {
 	return totalProfit, nil
 }
 
+// SymbolExposure holds net exposure for one symbol (long minus short).
+//
+// FIELDS:
+//   Symbol      - Trading symbol
+//   LongLots    - Total BUY volume
+//   ShortLots   - Total SELL volume
+//   NetLots     - LongLots - ShortLots (negative = net short)
+//   NetNotional - Net position value in account currency (signed)
+//   FloatingPnL - Profit + swap + commission of the symbol's positions
+type SymbolExposure struct {
+	Symbol      string
+	LongLots    float64
+	ShortLots   float64
+	NetLots     float64
+	NetNotional float64
+	FloatingPnL float64
+}
+
+// GetFloatingPnL returns net floating P/L of all open positions including
+// swap and commission (GetTotalProfit counts the profit field only).
+// Uses 5-second timeout.
+//
+// RETURNS:
+//   Net floating P/L as float64, or error if query fails
+func (s *MT5Sugar) GetFloatingPnL() (float64, error) {
+	positions, err := s.GetOpenPositions()
+	if err != nil {
+		return 0, err
+	}
+
+	var total float64
+	for _, pos := range positions {
+		total += pos.Profit + pos.Swap + pos.PositionCommission
+	}
+
+	return total, nil
+}
+
+// GetFloatingPnLBySymbol returns net floating P/L (profit + swap + commission)
+// grouped by symbol. Uses 5-second timeout.
+//
+// RETURNS:
+//   Map symbol -> floating P/L, or error if query fails
+func (s *MT5Sugar) GetFloatingPnLBySymbol() (map[string]float64, error) {
+	positions, err := s.GetOpenPositions()
+	if err != nil {
+		return nil, err
+	}
+
+	pnl := make(map[string]float64)
+	for _, pos := range positions {
+		pnl[pos.Symbol] += pos.Profit + pos.Swap + pos.PositionCommission
+	}
+
+	return pnl, nil
+}
+
+// GetNetExposure returns long, short and net exposure per symbol, in lots
+// and in account-currency notional. Notional is derived from tick value and
+// tick size, so it is correct for FX crosses, metals and CFDs alike.
+// Uses 10-second timeout.
+//
+// RETURNS:
+//   Map symbol -> *SymbolExposure, or error if positions or symbol specs could not be read
+func (s *MT5Sugar) GetNetExposure() (map[string]*SymbolExposure, error) {
+	positions, err := s.GetOpenPositions()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+	defer cancel()
+
+	exposure := make(map[string]*SymbolExposure)
+	for _, pos := range positions {
+		e, ok := exposure[pos.Symbol]
+		if !ok {
+			e = &SymbolExposure{Symbol: pos.Symbol}
+			exposure[pos.Symbol] = e
+		}
+		if pos.Type == pb.BMT5_ENUM_POSITION_TYPE_BMT5_POSITION_TYPE_SELL {
+			e.ShortLots += pos.Volume
+		} else {
+			e.LongLots += pos.Volume
+		}
+		e.FloatingPnL += pos.Profit + pos.Swap + pos.PositionCommission
+	}
+
+	for symbol, e := range exposure {
+		e.NetLots = e.LongLots - e.ShortLots
+
+		symbolName := symbol
+		params, _, err := s.service.GetSymbolParamsMany(ctx, &symbolName, nil, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("GetNetExposure failed: %w", err)
+		}
+		if len(params) == 0 || params[0].TradeTickSize <= 0 {
+			continue
+		}
+		p := params[0]
+		e.NetNotional = e.NetLots * p.Bid / p.TradeTickSize * p.TradeTickValue
+	}
+
+	return exposure, nil
+}
+
 // #endregion
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -1489,6 +3151,146 @@ func (s *MT5Sugar) GetProfitThisMonth() (float64, error) {
 	return totalProfit, nil
 }
 
+// SummaryPeriod selects the time window for TradingSummary.
+type SummaryPeriod int
+
+const (
+	PeriodToday     SummaryPeriod = iota // Since server midnight
+	PeriodThisWeek                       // Since server Monday 00:00
+	PeriodThisMonth                      // Since server 1st of month 00:00
+)
+
+// TradingSummaryResult holds realized trading results for a period.
+//
+// FIELDS:
+//   From, To    - Period boundaries (in server timezone)
+//   Trades      - Closed positions in the period
+//   Wins/Losses - Closed positions with positive/negative net result
+//   WinRate     - Win rate percentage (0-100)
+//   GrossProfit - Sum of profits of winning trades
+//   GrossLoss   - Sum of losses of losing trades (negative)
+//   Commission  - Total commission paid (negative; from CommissionModel if deals report none)
+//   Swap        - Total swap
+//   Fees        - Total broker fees (negative)
+//   RealizedPnL - Net result: profit + swap + commission + fees
+//   Volume      - Total lots traded
+type TradingSummaryResult struct {
+	From        time.Time
+	To          time.Time
+	Trades      int
+	Wins        int
+	Losses      int
+	WinRate     float64
+	GrossProfit float64
+	GrossLoss   float64
+	Commission  float64
+	Swap        float64
+	Fees        float64
+	RealizedPnL float64
+	Volume      float64
+}
+
+// TradingSummary returns realized P/L, trade count, win rate, volume and fees
+// for today, this week or this month. Period boundaries follow the SERVER
+// day (see SetServerTimezone), and only positions CLOSED inside the period are
+// counted. Uses 30-second timeout.
+//
+// PARAMETERS:
+//   period - PeriodToday, PeriodThisWeek or PeriodThisMonth
+//
+// RETURNS:
+//   *TradingSummaryResult, or error if history query fails
+func (s *MT5Sugar) TradingSummary(period SummaryPeriod) (*TradingSummaryResult, error) {
+	now := time.Now().In(s.serverLoc)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.serverLoc)
+
+	switch period {
+	case PeriodToday:
+	case PeriodThisWeek:
+		weekday := int(now.Weekday())
+		if weekday == 0 {
+			weekday = 7
+		}
+		from = from.AddDate(0, 0, -(weekday - 1))
+	case PeriodThisMonth:
+		from = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, s.serverLoc)
+	default:
+		return nil, fmt.Errorf("unknown summary period: %d", period)
+	}
+
+	summary, _, err := s.summarizeClosed(from, now)
+	if err != nil {
+		return nil, fmt.Errorf("TradingSummary failed: %w", err)
+	}
+	return summary, nil
+}
+
+// closedPosition is a position closed in a summary period with its effective
+// commission (from the CommissionModel if the broker reported none).
+type closedPosition struct {
+	info       *pb.PositionHistoryInfo
+	commission float64
+	net        float64 // Profit + swap + commission + fee
+}
+
+// summarizeClosed totals the positions closed in [from, to] and returns them.
+func (s *MT5Sugar) summarizeClosed(from, to time.Time) (*TradingSummaryResult, []closedPosition, error) {
+	deals, err := s.GetDealsDateRange(from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	summary := &TradingSummaryResult{
+		From: from,
+		To:   to,
+	}
+
+	var closed []closedPosition
+	contractSizes := make(map[string]float64)
+	for _, deal := range deals {
+		if deal.CloseTime == nil || deal.CloseTime.AsTime().Before(from) || deal.CloseTime.AsTime().After(to) {
+			continue
+		}
+
+		// Broker reported no commission: apply configured model (costs are negative in deals)
+		commission := deal.Commission
+		if commission == 0 && s.commissions != nil {
+			size, ok := contractSizes[deal.Symbol]
+			if !ok && s.commissions.NeedsContractSize(deal.Symbol) {
+				if info, err := s.GetSymbolInfo(deal.Symbol); err == nil {
+					size = info.ContractSize
+				}
+				contractSizes[deal.Symbol] = size
+			}
+			commission = -s.commissions.RoundTrip(deal.Symbol, deal.Volume, deal.OpenPrice, deal.ClosePrice, size)
+		}
+
+		net := deal.Profit + deal.Swap + commission + deal.Fee
+		closed = append(closed, closedPosition{info: deal, commission: commission, net: net})
+
+		summary.Trades++
+		summary.Volume += deal.Volume
+		summary.Commission += commission
+		summary.Swap += deal.Swap
+		summary.Fees += deal.Fee
+		summary.RealizedPnL += net
+
+		if net > 0 {
+			summary.Wins++
+			summary.GrossProfit += net
+		} else if net < 0 {
+			summary.Losses++
+			summary.GrossLoss += net
+		}
+	}
+
+	if summary.Trades > 0 {
+		summary.WinRate = float64(summary.Wins) / float64(summary.Trades) * 100.0
+	}
+
+	return summary, closed, nil
+}
+
 // #endregion
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -1534,6 +3336,8 @@ type SymbolInfo struct {
 // RETURNS:
 //   *SymbolInfo structure with all important symbol parameters, or error if symbol not found
 func (s *MT5Sugar) GetSymbolInfo(symbol string) (*SymbolInfo, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	ctx, cancel := context.WithTimeout(s.ctx, 5*time.Second)
 	defer cancel()
 
@@ -1605,6 +3409,8 @@ func (s *MT5Sugar) GetAllSymbols() ([]string, error) {
 // RETURNS:
 //   true if symbol exists and is tradeable, false otherwise, or error if query fails
 func (s *MT5Sugar) IsSymbolAvailable(symbol string) (bool, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	ctx, cancel := context.WithTimeout(s.ctx, 3*time.Second)
 	defer cancel()
 
@@ -1613,54 +3419,178 @@ func (s *MT5Sugar) IsSymbolAvailable(symbol string) (bool, error) {
 		return false, err
 	}
 
-	if !exists {
-		return false, nil
-	}
+	if !exists {
+		return false, nil
+	}
+
+	// Check if symbol is synchronized (has data)
+	synced, err := s.service.IsSymbolSynchronized(ctx, symbol)
+	if err != nil {
+		return false, err
+	}
+
+	return synced, nil
+}
+
+// GetMinStopLevel returns the minimum allowed distance for Stop Loss/Take Profit
+// in points. This is broker-enforced minimum distance from current price to SL/TP.
+// If 0, there's no minimum (market execution). Uses 3-second timeout.
+//
+// PARAMETERS:
+//   symbol - Trading symbol (e.g., "EURUSD")
+//
+// RETURNS:
+//   Minimum stop level in points (int64), or error if symbol not found
+func (s *MT5Sugar) GetMinStopLevel(symbol string) (int64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
+	ctx, cancel := context.WithTimeout(s.ctx, 3*time.Second)
+	defer cancel()
+
+	return s.service.GetSymbolInteger(ctx, symbol, pb.SymbolInfoIntegerProperty_SYMBOL_TRADE_STOPS_LEVEL)
+}
+
+// GetSymbolDigits returns the number of decimal places for the symbol price.
+// For example, EURUSD typically has 5 digits (1.08123), gold might have 2.
+// This is essential for proper price formatting and calculations. Uses 3-second timeout.
+//
+// PARAMETERS:
+//   symbol - Trading symbol (e.g., "EURUSD")
+//
+// RETURNS:
+//   Number of decimal places (int32), or error if symbol not found
+func (s *MT5Sugar) GetSymbolDigits(symbol string) (int32, error) {
+	symbol = s.ResolveSymbol(symbol)
+
+	ctx, cancel := context.WithTimeout(s.ctx, 3*time.Second)
+	defer cancel()
+
+	digits, err := s.service.GetSymbolInteger(ctx, symbol, pb.SymbolInfoIntegerProperty_SYMBOL_DIGITS)
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(digits), nil
+}
+
+// IsTradingTime reports whether the symbol can be traded right now: current
+// SERVER time (see SetServerTimezone) falls inside one of today's trade
+// sessions and today is not a holiday (see AddHoliday). Weekends have no
+// sessions, so they return false. Uses 5-second timeout.
+//
+// PARAMETERS:
+//   symbol - Trading symbol (e.g., "EURUSD")
+//
+// RETURNS:
+//   true if trading is open, or error if session info cannot be read
+func (s *MT5Sugar) IsTradingTime(symbol string) (bool, error) {
+	symbol = s.ResolveSymbol(symbol)
+
+	ctx, cancel := context.WithTimeout(s.ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now().In(s.serverLoc)
+	if s.isHoliday(symbol, now) {
+		return false, nil
+	}
+
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.serverLoc)
+	sinceMidnight := now.Sub(midnight)
+
+	sessions, err := s.tradeSessions(ctx, symbol, now.Weekday())
+	if err != nil {
+		return false, err
+	}
+	for _, session := range sessions {
+		if sinceMidnight >= session.from && sinceMidnight < session.to {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// marketClosed reports whether the symbol is known to be closed right now.
+// Without SetServerTimezone the server clock is unknown, so it never guesses.
+func (s *MT5Sugar) marketClosed(symbol string) bool {
+	if !s.serverTZSet {
+		return false
+	}
+	open, err := s.IsTradingTime(symbol)
+	return err == nil && !open
+}
+
+// AddHoliday marks a SERVER-time date as a market holiday, so IsTradingTime
+// returns false for the whole day. Without symbols the holiday applies to all.
+//
+// PARAMETERS:
+//   date    - Any time on the holiday (date is taken in server timezone)
+//   symbols - Symbols closed that day (empty = all symbols)
+//
+// EXAMPLE:
+//   sugar.AddHoliday(time.Date(2026, 12, 25, 12, 0, 0, 0, time.UTC))
+//   sugar.AddHoliday(time.Date(2026, 7, 3, 12, 0, 0, 0, time.UTC), "US500", "US30")
+func (s *MT5Sugar) AddHoliday(date time.Time, symbols ...string) {
+	day := date.In(s.serverLoc).Format("2006-01-02")
+
+	s.calMu.Lock()
+	defer s.calMu.Unlock()
 
-	// Check if symbol is synchronized (has data)
-	synced, err := s.service.IsSymbolSynchronized(ctx, symbol)
-	if err != nil {
-		return false, err
+	if s.holidays == nil {
+		s.holidays = make(map[string]bool)
+	}
+	if len(symbols) == 0 {
+		s.holidays["|"+day] = true
+		return
+	}
+	for _, symbol := range symbols {
+		s.holidays[s.ResolveSymbol(symbol)+"|"+day] = true
 	}
-
-	return synced, nil
 }
 
-// GetMinStopLevel returns the minimum allowed distance for Stop Loss/Take Profit
-// in points. This is broker-enforced minimum distance from current price to SL/TP.
-// If 0, there's no minimum (market execution). Uses 3-second timeout.
+// DiscoverSymbolNames detects the broker's symbol naming convention (e.g.,
+// "EURUSD.pro", "EURUSDm") from the full symbol list. Afterwards every Sugar
+// method accepts canonical names ("EURUSD") and maps them automatically.
+// QuickConnect and Connect call this after connecting. Uses 15-second timeout.
 //
 // PARAMETERS:
-//   symbol - Trading symbol (e.g., "EURUSD")
+//   probes - Canonical symbols to look for (empty = DefaultSuffixProbes)
 //
 // RETURNS:
-//   Minimum stop level in points (int64), or error if symbol not found
-func (s *MT5Sugar) GetMinStopLevel(symbol string) (int64, error) {
-	ctx, cancel := context.WithTimeout(s.ctx, 3*time.Second)
-	defer cancel()
-
-	return s.service.GetSymbolInteger(ctx, symbol, pb.SymbolInfoIntegerProperty_SYMBOL_TRADE_STOPS_LEVEL)
+//   Error if the symbol list cannot be read or no probe symbol exists
+//
+// EXAMPLE:
+//   sugar.DiscoverSymbolNames()
+//   sugar.BuyMarket("EURUSD", 0.1)   // sends EURUSD.pro on a ".pro" account
+func (s *MT5Sugar) DiscoverSymbolNames(probes ...string) error {
+	names, err := s.GetAllSymbols()
+	if err != nil {
+		return fmt.Errorf("DiscoverSymbolNames failed: %w", err)
+	}
+	return s.symbols.Discover(names, probes...)
 }
 
-// GetSymbolDigits returns the number of decimal places for the symbol price.
-// For example, EURUSD typically has 5 digits (1.08123), gold might have 2.
-// This is essential for proper price formatting and calculations. Uses 3-second timeout.
+// ResolveSymbol maps a canonical symbol name to the broker's name. Broker
+// names and unknown names are returned unchanged.
 //
 // PARAMETERS:
-//   symbol - Trading symbol (e.g., "EURUSD")
+//   symbol - Canonical or broker symbol name
 //
 // RETURNS:
-//   Number of decimal places (int32), or error if symbol not found
-func (s *MT5Sugar) GetSymbolDigits(symbol string) (int32, error) {
-	ctx, cancel := context.WithTimeout(s.ctx, 3*time.Second)
-	defer cancel()
-
-	digits, err := s.service.GetSymbolInteger(ctx, symbol, pb.SymbolInfoIntegerProperty_SYMBOL_DIGITS)
-	if err != nil {
-		return 0, err
+//   Broker symbol name
+func (s *MT5Sugar) ResolveSymbol(symbol string) string {
+	if s.symbols == nil {
+		return symbol
 	}
+	return s.symbols.Resolve(symbol)
+}
 
-	return int32(digits), nil
+// GetSymbolResolver returns the resolver used by Sugar, e.g., to add explicit
+// mappings for symbols outside the common pattern ("GOLD" → "XAUUSD.pro").
+//
+// RETURNS:
+//   *SuffixResolver used by this Sugar instance
+func (s *MT5Sugar) GetSymbolResolver() *SuffixResolver {
+	return s.symbols
 }
 
 // #endregion
@@ -1690,6 +3620,8 @@ func (s *MT5Sugar) GetSymbolDigits(symbol string) (int32, error) {
 //   Balance: $10,000, Risk: 2% ($200), SL: 50 pips
 //   → Risk-based: 0.40 lots, Margin-limited: 0.30 lots → Returns: 0.30 lots
 func (s *MT5Sugar) CalculatePositionSize(symbol string, riskPercent, stopLossPips float64) (float64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
 	defer cancel()
 
@@ -1790,6 +3722,8 @@ func (s *MT5Sugar) CalculatePositionSize(symbol string, riskPercent, stopLossPip
 // RETURNS:
 //   Maximum safe lot size (float64), or error if calculation fails
 func (s *MT5Sugar) GetMaxLotSize(symbol string) (float64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	ctx, cancel := context.WithTimeout(s.ctx, 5*time.Second)
 	defer cancel()
 
@@ -1859,6 +3793,8 @@ func (s *MT5Sugar) GetMaxLotSize(symbol string) (float64, error) {
 //   reason - explanation if can't open, empty if can
 //   error  - error if check failed
 func (s *MT5Sugar) CanOpenPosition(symbol string, volume float64) (bool, string, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	ctx, cancel := context.WithTimeout(s.ctx, 5*time.Second)
 	defer cancel()
 
@@ -1938,6 +3874,8 @@ func (s *MT5Sugar) CanOpenPosition(symbol string, volume float64) (bool, string,
 // RETURNS:
 //   Required margin amount (float64), or error if calculation fails
 func (s *MT5Sugar) CalculateRequiredMargin(symbol string, volume float64) (float64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	ctx, cancel := context.WithTimeout(s.ctx, 5*time.Second)
 	defer cancel()
 
@@ -1957,6 +3895,254 @@ func (s *MT5Sugar) CalculateRequiredMargin(symbol string, volume float64) (float
 	return s.service.CalculateMargin(ctx, req)
 }
 
+// OrderValidationReport is the result of a dry-run order validation.
+//
+// FIELDS:
+//   Valid            - True if no violations were found
+//   Price            - Price used for the checks (current market price for market orders)
+//   RequiredMargin   - Margin the order would lock
+//   FreeMarginBefore - Free margin right now
+//   FreeMarginAfter  - Free margin after the order (from broker OrderCheck)
+//   MarginLevelAfter - Margin level % after the order (from broker OrderCheck)
+//   SpreadCost       - Cost of crossing the spread, in account currency
+//   CommissionCost   - Estimated commission (0 - brokers report it only in deals)
+//   CheckCode        - Broker OrderCheck return code (0 = accepted)
+//   CheckComment     - Broker OrderCheck comment
+//   Violations       - Problems that would make the order fail
+//   Warnings         - Non-fatal remarks
+type OrderValidationReport struct {
+	Valid            bool
+	Price            float64
+	RequiredMargin   float64
+	FreeMarginBefore float64
+	FreeMarginAfter  float64
+	MarginLevelAfter float64
+	SpreadCost       float64
+	CommissionCost   float64
+	CheckCode        uint32
+	CheckComment     string
+	Violations       []string
+	Warnings         []string
+}
+
+// String renders the report as human-readable text.
+func (r *OrderValidationReport) String() string {
+	verdict := "✅ VALID"
+	if !r.Valid {
+		verdict = "❌ INVALID"
+	}
+
+	out := fmt.Sprintf("Order validation: %s\n", verdict)
+	out += fmt.Sprintf("  Price:              %.5f\n", r.Price)
+	out += fmt.Sprintf("  Required margin:    %.2f\n", r.RequiredMargin)
+	out += fmt.Sprintf("  Free margin before: %.2f\n", r.FreeMarginBefore)
+	out += fmt.Sprintf("  Free margin after:  %.2f\n", r.FreeMarginAfter)
+	out += fmt.Sprintf("  Margin level after: %.2f%%\n", r.MarginLevelAfter)
+	out += fmt.Sprintf("  Spread cost:        %.2f\n", r.SpreadCost)
+	out += fmt.Sprintf("  Commission (est.):  %.2f\n", r.CommissionCost)
+	out += fmt.Sprintf("  Broker check:       %d %s\n", r.CheckCode, r.CheckComment)
+	for _, v := range r.Violations {
+		out += fmt.Sprintf("  ✗ %s\n", v)
+	}
+	for _, w := range r.Warnings {
+		out += fmt.Sprintf("  ⚠ %s\n", w)
+	}
+	return out
+}
+
+// ValidateOrder runs the full pre-trade pipeline WITHOUT placing the order.
+// Checks request fields, the blocks that stop an order before it is sent
+// (trade permissions, news blackout, rollover, safety limits, kill switch),
+// volume limits, stop levels, margin, and finally asks the broker via OrderCheck. Use it to see why an order would fail and what
+// it would cost before risking a rejection. Uses 10-second timeout.
+//
+// PARAMETERS:
+//   req - Order description (same as for SendOrder)
+//
+// RETURNS:
+//   *OrderValidationReport (print it with fmt.Println), or error if data could not be fetched
+func (s *MT5Sugar) ValidateOrder(req OrderRequest) (*OrderValidationReport, error) {
+	req.Symbol = s.ResolveSymbol(req.Symbol)
+
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+	defer cancel()
+
+	report := &OrderValidationReport{}
+
+	// 1. Request fields, then the blocks placeOrder and the account apply
+	if err := req.Validate(); err != nil {
+		report.Violations = append(report.Violations, err.Error())
+		return report, nil
+	}
+	sendReq := req.ToProto()
+	if err := s.preTradeChecks(ctx, sendReq); err != nil {
+		report.Violations = append(report.Violations, err.Error())
+	}
+	if err := s.service.account.CheckOrderSend(ctx, sendReq); err != nil {
+		report.Violations = append(report.Violations, err.Error())
+	}
+
+	// 2. Symbol specification
+	symbolName := req.Symbol
+	params, _, err := s.service.GetSymbolParamsMany(ctx, &symbolName, nil, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ValidateOrder failed: %w", err)
+	}
+	if len(params) == 0 {
+		report.Violations = append(report.Violations, fmt.Sprintf("symbol %s not found", req.Symbol))
+		return report, nil
+	}
+	p := params[0]
+
+	if req.Volume < p.VolumeMin {
+		report.Violations = append(report.Violations, fmt.Sprintf("volume %.2f below minimum %.2f", req.Volume, p.VolumeMin))
+	}
+	if req.Volume > p.VolumeMax {
+		report.Violations = append(report.Violations, fmt.Sprintf("volume %.2f exceeds maximum %.2f", req.Volume, p.VolumeMax))
+	}
+	if p.VolumeStep > 0 {
+		steps := req.Volume / p.VolumeStep
+		if diff := steps - float64(int(steps+0.5)); diff > 0.0001 || diff < -0.0001 {
+			report.Violations = append(report.Violations, fmt.Sprintf("volume %.2f not a multiple of step %.2f", req.Volume, p.VolumeStep))
+		}
+	}
+
+	// 3. Price and stop distances
+	report.Price = req.Price
+	if req.IsMarket() {
+		report.Price = p.Bid
+		if req.IsBuy() {
+			report.Price = p.Ask
+		}
+	}
+
+	stopLevel, err := s.service.GetSymbolInteger(ctx, req.Symbol, pb.SymbolInfoIntegerProperty_SYMBOL_TRADE_STOPS_LEVEL)
+	if err == nil && stopLevel > 0 && p.Point > 0 {
+		minDistance := float64(stopLevel) * p.Point
+		if req.StopLoss > 0 && math.Abs(report.Price-req.StopLoss) < minDistance {
+			report.Violations = append(report.Violations, fmt.Sprintf("SL %.5f closer than stops level (%d points)", req.StopLoss, stopLevel))
+		}
+		if req.TakeProfit > 0 && math.Abs(report.Price-req.TakeProfit) < minDistance {
+			report.Violations = append(report.Violations, fmt.Sprintf("TP %.5f closer than stops level (%d points)", req.TakeProfit, stopLevel))
+		}
+	}
+	if req.StopLoss > 0 && ((req.IsBuy() && req.StopLoss >= report.Price) || (!req.IsBuy() && req.StopLoss <= report.Price)) {
+		report.Violations = append(report.Violations, fmt.Sprintf("SL %.5f is on the wrong side of price %.5f", req.StopLoss, report.Price))
+	}
+	if req.TakeProfit > 0 && ((req.IsBuy() && req.TakeProfit <= report.Price) || (!req.IsBuy() && req.TakeProfit >= report.Price)) {
+		report.Violations = append(report.Violations, fmt.Sprintf("TP %.5f is on the wrong side of price %.5f", req.TakeProfit, report.Price))
+	}
+	if req.StopLoss == 0 {
+		report.Warnings = append(report.Warnings, "no stop loss set")
+	}
+
+	// 4. Margin
+	orderType := pb.ENUM_ORDER_TYPE_TF_ORDER_TYPE_TF_SELL
+	if req.IsBuy() {
+		orderType = pb.ENUM_ORDER_TYPE_TF_ORDER_TYPE_TF_BUY
+	}
+	report.RequiredMargin, err = s.service.CalculateMargin(ctx, &pb.OrderCalcMarginRequest{
+		Symbol:    req.Symbol,
+		OrderType: orderType,
+		Volume:    req.Volume,
+		OpenPrice: report.Price,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ValidateOrder failed: %w", err)
+	}
+
+	report.FreeMarginBefore, err = s.service.GetAccountDouble(ctx, pb.AccountInfoDoublePropertyType_ACCOUNT_MARGIN_FREE)
+	if err != nil {
+		return nil, fmt.Errorf("ValidateOrder failed: %w", err)
+	}
+	if report.RequiredMargin > report.FreeMarginBefore {
+		report.Violations = append(report.Violations, fmt.Sprintf("insufficient margin: need %.2f, have %.2f", report.RequiredMargin, report.FreeMarginBefore))
+	}
+
+	// 5. Costs
+	if p.TradeTickSize > 0 {
+		spreadPrice := float64(p.Spread) * p.Point
+		report.SpreadCost = spreadPrice / p.TradeTickSize * p.TradeTickValue * req.Volume
+	}
+	if s.commissions != nil {
+		report.CommissionCost = s.commissions.RoundTrip(req.Symbol, req.Volume, report.Price, report.Price, p.TradeContractSize)
+	} else {
+		report.Warnings = append(report.Warnings, "commission is not reported before execution; estimate is 0")
+	}
+
+	// 6. Broker-side check
+	checkReq := req
+	checkReq.Price = report.Price
+	check, _, err := s.service.CheckOrderRequest(ctx, checkReq)
+	if err != nil {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("broker check unavailable: %v", err))
+	} else {
+		report.CheckCode = check.ReturnedCode
+		report.CheckComment = check.Comment
+		report.FreeMarginAfter = check.MarginFree
+		report.MarginLevelAfter = check.MarginLevel
+		// OrderCheck returns 0 when the request passes
+		if check.ReturnedCode != 0 && check.ReturnedCode != 10009 {
+			report.Violations = append(report.Violations, fmt.Sprintf("broker check rejected: code %d, %s", check.ReturnedCode, check.Comment))
+		}
+	}
+
+	report.Valid = len(report.Violations) == 0
+	return report, nil
+}
+
+// SizePosition computes lot size using any PositionSizer (fixed lots, fixed
+// fractional, Kelly, volatility target). Fills SizingInput from live account
+// and symbol data. Uses 10-second timeout.
+//
+// PARAMETERS:
+//   symbol         - Trading symbol (e.g., "EURUSD")
+//   sizer          - Sizing strategy (e.g., &FixedFractionalSizer{RiskPercent: 1})
+//   stopLossPoints - Planned SL distance in points (0 if sizer doesn't need it)
+//
+// RETURNS:
+//   Lot size normalized to symbol volume limits, or error
+func (s *MT5Sugar) SizePosition(symbol string, sizer PositionSizer, stopLossPoints float64) (float64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
+	if sizer == nil {
+		return 0, fmt.Errorf("position sizer is nil")
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+	defer cancel()
+
+	equity, err := s.service.GetAccountDouble(ctx, pb.AccountInfoDoublePropertyType_ACCOUNT_EQUITY)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get equity: %w", err)
+	}
+
+	symbolName := symbol
+	params, _, err := s.service.GetSymbolParamsMany(ctx, &symbolName, nil, nil, nil)
+	if err != nil {
+		return 0, fmt.Errorf("SizePosition failed: %w", err)
+	}
+	if len(params) == 0 {
+		return 0, fmt.Errorf("symbol %s not found", symbol)
+	}
+	p := params[0]
+
+	in := SizingInput{
+		Symbol:         symbol,
+		Equity:         equity,
+		StopLossPoints: stopLossPoints,
+		Point:          p.Point,
+		VolumeMin:      p.VolumeMin,
+		VolumeMax:      p.VolumeMax,
+		VolumeStep:     p.VolumeStep,
+	}
+	if p.TradeTickSize > 0 {
+		in.PointValue = p.Point / p.TradeTickSize * p.TradeTickValue
+	}
+
+	return sizer.Size(in)
+}
+
 // #endregion
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -2033,6 +4219,8 @@ func (s *MT5Sugar) CalculateSLTP(symbol, direction string, entryPrice, stopLossP
 //   ticket, _ := sugar.BuyMarketWithPips("EURUSD", 0.1, 50, 100)
 //   // Opens BUY at market, SL = entry - 50 pips, TP = entry + 100 pips
 func (s *MT5Sugar) BuyMarketWithPips(symbol string, volume, stopLossPips, takeProfitPips float64) (uint64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	// Calculate SL/TP prices
 	sl, tp, err := s.CalculateSLTP(symbol, "BUY", 0, stopLossPips, takeProfitPips)
 	if err != nil {
@@ -2043,6 +4231,52 @@ func (s *MT5Sugar) BuyMarketWithPips(symbol string, volume, stopLossPips, takePr
 	return s.BuyMarketWithSLTP(symbol, volume, sl, tp)
 }
 
+// BuyMarketSized opens a BUY position sized by a PositionSizer, with SL/TP in pips.
+// The SL distance is passed to the sizer, so risk-based sizers risk exactly
+// their configured amount. Uses 10-second timeout.
+//
+// PARAMETERS:
+//   symbol         - Trading symbol (e.g., "EURUSD")
+//   sizer          - Sizing strategy
+//   stopLossPips   - Stop Loss distance in pips from entry
+//   takeProfitPips - Take Profit distance in pips from entry
+//
+// RETURNS:
+//   Position ticket number (uint64), or error if sizing failed or order rejected
+//
+// EXAMPLE:
+//   ticket, _ := sugar.BuyMarketSized("EURUSD", &mt5.FixedFractionalSizer{RiskPercent: 1}, 200, 400)
+func (s *MT5Sugar) BuyMarketSized(symbol string, sizer PositionSizer, stopLossPips, takeProfitPips float64) (uint64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
+	volume, err := s.SizePosition(symbol, sizer, stopLossPips)
+	if err != nil {
+		return 0, err
+	}
+	return s.BuyMarketWithPips(symbol, volume, stopLossPips, takeProfitPips)
+}
+
+// SellMarketSized opens a SELL position sized by a PositionSizer, with SL/TP in pips.
+// Uses 10-second timeout.
+//
+// PARAMETERS:
+//   symbol         - Trading symbol (e.g., "EURUSD")
+//   sizer          - Sizing strategy
+//   stopLossPips   - Stop Loss distance in pips from entry
+//   takeProfitPips - Take Profit distance in pips from entry
+//
+// RETURNS:
+//   Position ticket number (uint64), or error if sizing failed or order rejected
+func (s *MT5Sugar) SellMarketSized(symbol string, sizer PositionSizer, stopLossPips, takeProfitPips float64) (uint64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
+	volume, err := s.SizePosition(symbol, sizer, stopLossPips)
+	if err != nil {
+		return 0, err
+	}
+	return s.SellMarketWithPips(symbol, volume, stopLossPips, takeProfitPips)
+}
+
 // SellMarketWithPips opens a SELL position with SL/TP specified in pips (not price!).
 // This is more intuitive than SellMarketWithSLTP - you specify risk/reward in pips
 // and the method calculates exact prices automatically. Uses 10-second timeout.
@@ -2060,6 +4294,8 @@ func (s *MT5Sugar) BuyMarketWithPips(symbol string, volume, stopLossPips, takePr
 //   ticket, _ := sugar.SellMarketWithPips("EURUSD", 0.1, 50, 100)
 //   // Opens SELL at market, SL = entry + 50 pips, TP = entry - 100 pips
 func (s *MT5Sugar) SellMarketWithPips(symbol string, volume, stopLossPips, takeProfitPips float64) (uint64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
 	// Calculate SL/TP prices
 	sl, tp, err := s.CalculateSLTP(symbol, "SELL", 0, stopLossPips, takeProfitPips)
 	if err != nil {
@@ -2204,3 +4440,265 @@ func (s *MT5Sugar) GetDailyStats() (*DailyStats, error) {
 }
 
 // #endregion
+
+// ══════════════════════════════════════════════════════════════════════════════
+// #region PENDING ORDER HELPERS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// PlacePendingGTD places a pending order that expires at a specific time
+// (ORDER_TIME_SPECIFIED). Uses 10-second timeout.
+//
+// PARAMETERS:
+//   orderType  - BUY_LIMIT, SELL_LIMIT, BUY_STOP or SELL_STOP
+//   symbol     - Trading symbol (e.g., "EURUSD")
+//   volume     - Lot size
+//   price      - Order price
+//   expiration - Moment the order is removed if not triggered
+//
+// RETURNS:
+//   Order ticket number (uint64), or error if invalid or rejected
+//
+// EXAMPLE:
+//   ticket, _ := sugar.PlacePendingGTD(pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_LIMIT,
+//       "EURUSD", 0.1, 1.0850, time.Now().Add(4*time.Hour))
+func (s *MT5Sugar) PlacePendingGTD(orderType pb.TMT5_ENUM_ORDER_TYPE, symbol string, volume, price float64, expiration time.Time) (uint64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
+	if !expiration.After(time.Now()) {
+		return 0, fmt.Errorf("expiration %s is in the past", expiration.Format(time.RFC3339))
+	}
+
+	return s.SendOrder(OrderRequest{
+		Symbol:     symbol,
+		Type:       orderType,
+		Volume:     volume,
+		Price:      price,
+		TimeType:   pb.TMT5_ENUM_ORDER_TYPE_TIME_TMT5_ORDER_TIME_SPECIFIED,
+		Expiration: expiration,
+	})
+}
+
+// PlacePendingDay places a pending order valid for the current trading day
+// only (ORDER_TIME_DAY). The server removes it at the end of the day.
+// Uses 10-second timeout.
+//
+// PARAMETERS:
+//   orderType - BUY_LIMIT, SELL_LIMIT, BUY_STOP or SELL_STOP
+//   symbol    - Trading symbol (e.g., "EURUSD")
+//   volume    - Lot size
+//   price     - Order price
+//
+// RETURNS:
+//   Order ticket number (uint64), or error if invalid or rejected
+func (s *MT5Sugar) PlacePendingDay(orderType pb.TMT5_ENUM_ORDER_TYPE, symbol string, volume, price float64) (uint64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
+	return s.SendOrder(OrderRequest{
+		Symbol:   symbol,
+		Type:     orderType,
+		Volume:   volume,
+		Price:    price,
+		TimeType: pb.TMT5_ENUM_ORDER_TYPE_TIME_TMT5_ORDER_TIME_DAY,
+	})
+}
+
+// PlacePendingUntilDay places a pending order valid until the end of the given
+// day (ORDER_TIME_SPECIFIED_DAY). Only the date part of day is used.
+// Uses 10-second timeout.
+//
+// PARAMETERS:
+//   orderType - BUY_LIMIT, SELL_LIMIT, BUY_STOP or SELL_STOP
+//   symbol    - Trading symbol (e.g., "EURUSD")
+//   volume    - Lot size
+//   price     - Order price
+//   day       - Last day the order is valid
+//
+// RETURNS:
+//   Order ticket number (uint64), or error if invalid or rejected
+func (s *MT5Sugar) PlacePendingUntilDay(orderType pb.TMT5_ENUM_ORDER_TYPE, symbol string, volume, price float64, day time.Time) (uint64, error) {
+	symbol = s.ResolveSymbol(symbol)
+
+	endOfDay := time.Date(day.Year(), day.Month(), day.Day(), 23, 59, 59, 0, day.Location())
+	if !endOfDay.After(time.Now()) {
+		return 0, fmt.Errorf("day %s is in the past", day.Format("2006-01-02"))
+	}
+
+	return s.SendOrder(OrderRequest{
+		Symbol:     symbol,
+		Type:       orderType,
+		Volume:     volume,
+		Price:      price,
+		TimeType:   pb.TMT5_ENUM_ORDER_TYPE_TIME_TMT5_ORDER_TIME_SPECIFIED_DAY,
+		Expiration: endOfDay,
+	})
+}
+
+// BuyLimitGTD places a BUY LIMIT order that expires at the given time.
+// See PlacePendingGTD. Uses 10-second timeout.
+func (s *MT5Sugar) BuyLimitGTD(symbol string, volume, price float64, expiration time.Time) (uint64, error) {
+	return s.PlacePendingGTD(pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_LIMIT, symbol, volume, price, expiration)
+}
+
+// SellLimitGTD places a SELL LIMIT order that expires at the given time.
+// See PlacePendingGTD. Uses 10-second timeout.
+func (s *MT5Sugar) SellLimitGTD(symbol string, volume, price float64, expiration time.Time) (uint64, error) {
+	return s.PlacePendingGTD(pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL_LIMIT, symbol, volume, price, expiration)
+}
+
+// BuyStopGTD places a BUY STOP order that expires at the given time.
+// See PlacePendingGTD. Uses 10-second timeout.
+func (s *MT5Sugar) BuyStopGTD(symbol string, volume, price float64, expiration time.Time) (uint64, error) {
+	return s.PlacePendingGTD(pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_STOP, symbol, volume, price, expiration)
+}
+
+// SellStopGTD places a SELL STOP order that expires at the given time.
+// See PlacePendingGTD. Uses 10-second timeout.
+func (s *MT5Sugar) SellStopGTD(symbol string, volume, price float64, expiration time.Time) (uint64, error) {
+	return s.PlacePendingGTD(pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL_STOP, symbol, volume, price, expiration)
+}
+
+// BuyLimitDay places a BUY LIMIT order valid for today only.
+// See PlacePendingDay. Uses 10-second timeout.
+func (s *MT5Sugar) BuyLimitDay(symbol string, volume, price float64) (uint64, error) {
+	return s.PlacePendingDay(pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_LIMIT, symbol, volume, price)
+}
+
+// SellLimitDay places a SELL LIMIT order valid for today only.
+// See PlacePendingDay. Uses 10-second timeout.
+func (s *MT5Sugar) SellLimitDay(symbol string, volume, price float64) (uint64, error) {
+	return s.PlacePendingDay(pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL_LIMIT, symbol, volume, price)
+}
+
+// BuyStopDay places a BUY STOP order valid for today only.
+// See PlacePendingDay. Uses 10-second timeout.
+func (s *MT5Sugar) BuyStopDay(symbol string, volume, price float64) (uint64, error) {
+	return s.PlacePendingDay(pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_STOP, symbol, volume, price)
+}
+
+// SellStopDay places a SELL STOP order valid for today only.
+// See PlacePendingDay. Uses 10-second timeout.
+func (s *MT5Sugar) SellStopDay(symbol string, volume, price float64) (uint64, error) {
+	return s.PlacePendingDay(pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL_STOP, symbol, volume, price)
+}
+
+// ModifyStopLimit changes trigger price, limit price, SL and TP of a
+// BUY_STOP_LIMIT / SELL_STOP_LIMIT order. OrderModify cannot move the limit
+// price, so the order is REPLACED: new prices are validated against the
+// market and OrderCheck, the old order is cancelled, and a new one with the
+// same volume, magic, comment and expiration is placed. If placing the new
+// order fails, the original order is restored. Uses 10-second timeout per step.
+//
+// PARAMETERS:
+//   ticket  - Ticket of the stop-limit order
+//   trigger - New stop (activation) price
+//   limit   - New limit price placed when trigger is hit
+//   sl      - New Stop Loss (0 = none)
+//   tp      - New Take Profit (0 = none)
+//
+// RETURNS:
+//   Ticket of the replacement order (uint64), or error (original order is kept or restored on failure)
+func (s *MT5Sugar) ModifyStopLimit(ticket uint64, trigger, limit, sl, tp float64) (uint64, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+	defer cancel()
+
+	data, err := s.service.GetOpenedOrders(ctx, pb.BMT5_ENUM_OPENED_ORDER_SORT_TYPE_BMT5_OPENED_ORDER_SORT_BY_OPEN_TIME_ASC)
+	if err != nil {
+		return 0, fmt.Errorf("ModifyStopLimit failed: %w", err)
+	}
+
+	var order *pb.OpenedOrderInfo
+	for _, o := range data.OpenedOrders {
+		if o.Ticket == ticket {
+			order = o
+			break
+		}
+	}
+	if order == nil {
+		return 0, fmt.Errorf("pending order with ticket %d not found", ticket)
+	}
+
+	isBuy := order.Type == pb.BMT5_ENUM_ORDER_TYPE_BMT5_ORDER_TYPE_BUY_STOP_LIMIT
+	if !isBuy && order.Type != pb.BMT5_ENUM_ORDER_TYPE_BMT5_ORDER_TYPE_SELL_STOP_LIMIT {
+		return 0, fmt.Errorf("order #%d is %s, not a stop-limit order", ticket, order.Type)
+	}
+
+	// Validate new levels against the market
+	tick, err := s.service.GetSymbolTick(ctx, order.Symbol)
+	if err != nil {
+		return 0, fmt.Errorf("ModifyStopLimit failed: %w", err)
+	}
+	if isBuy {
+		if trigger <= tick.Ask {
+			return 0, fmt.Errorf("BUY STOP LIMIT trigger %.5f must be above Ask %.5f", trigger, tick.Ask)
+		}
+		if limit > trigger {
+			return 0, fmt.Errorf("BUY STOP LIMIT limit %.5f must not be above trigger %.5f", limit, trigger)
+		}
+		if (sl > 0 && sl >= limit) || (tp > 0 && tp <= limit) {
+			return 0, fmt.Errorf("SL must be below and TP above limit price %.5f", limit)
+		}
+	} else {
+		if trigger >= tick.Bid {
+			return 0, fmt.Errorf("SELL STOP LIMIT trigger %.5f must be below Bid %.5f", trigger, tick.Bid)
+		}
+		if limit < trigger {
+			return 0, fmt.Errorf("SELL STOP LIMIT limit %.5f must not be below trigger %.5f", limit, trigger)
+		}
+		if (sl > 0 && sl <= limit) || (tp > 0 && tp >= limit) {
+			return 0, fmt.Errorf("SL must be above and TP below limit price %.5f", limit)
+		}
+	}
+
+	original := OrderRequest{
+		Symbol:         order.Symbol,
+		Type:           pb.TMT5_ENUM_ORDER_TYPE(order.Type),
+		Volume:         order.VolumeCurrent,
+		Price:          order.PriceOpen,
+		StopLimitPrice: order.StopLimit,
+		StopLoss:       order.StopLoss,
+		TakeProfit:     order.TakeProfit,
+		Comment:        order.Comment,
+		TimeType:       pb.TMT5_ENUM_ORDER_TYPE_TIME(order.TypeTime),
+	}
+	if order.MagicNumber > 0 {
+		original.Magic = uint64(order.MagicNumber)
+	}
+	if order.TimeExpiration != nil && original.TimeType != pb.TMT5_ENUM_ORDER_TYPE_TIME_TMT5_ORDER_TIME_GTC {
+		original.Expiration = order.TimeExpiration.AsTime()
+	}
+
+	replacement := original
+	replacement.Price = trigger
+	replacement.StopLimitPrice = limit
+	replacement.StopLoss = sl
+	replacement.TakeProfit = tp
+
+	if err := replacement.Validate(); err != nil {
+		return 0, fmt.Errorf("ModifyStopLimit: %w", err)
+	}
+	if check, _, err := s.service.CheckOrderRequest(ctx, replacement); err == nil && check.ReturnedCode != 0 && check.ReturnedCode != 10009 {
+		return 0, fmt.Errorf("replacement rejected by check, code: %d, comment: %s", check.ReturnedCode, check.Comment)
+	}
+
+	// Replace: cancel old, place new, restore old on failure
+	retCode, err := s.service.CloseOrder(ctx, &pb.OrderCloseRequest{Ticket: ticket})
+	if err != nil {
+		return 0, fmt.Errorf("ModifyStopLimit failed to cancel #%d: %w", ticket, err)
+	}
+	if retCode != 10009 {
+		return 0, fmt.Errorf("cancel of #%d rejected, code: %d", ticket, retCode)
+	}
+
+	newTicket, err := s.SendOrder(replacement)
+	if err != nil {
+		restored, rbErr := s.SendOrder(original)
+		if rbErr != nil {
+			return 0, fmt.Errorf("replacement failed (%v) and restoring original order failed: %w", err, rbErr)
+		}
+		return 0, fmt.Errorf("replacement failed, original order restored as #%d: %w", restored, err)
+	}
+
+	return newTicket, nil
+}
+
+// #endregion
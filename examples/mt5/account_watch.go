@@ -0,0 +1,288 @@
+package mt5
+
+/*
+Account watch - structured diffs of the account summary between polls.
+
+Balance, credit and leverage can change without the strategy doing anything:
+deposits, withdrawals, swap/commission charges, bonuses, corrections, or a
+broker lowering leverage before news. WatchAccountChanges polls the account
+summary, compares successive snapshots and explains balance and credit
+changes with the deals that appeared since the previous poll:
+
+    balance 10000.00 → 9985.40 (-14.60): deal #8812 COMMISSION_DAILY -4.60, deal #8815 SELL EURUSD -10.00
+    credit 0.00 → 500.00 (+500.00): deal #8820 CREDIT "bonus"
+    leverage 500 → 100
+
+Any part of a change not covered by deals is reported as Unexplained.
+
+Usage:
+    changes, errs := service.WatchAccountChanges(ctx, 10*time.Second)
+    for change := range changes {
+        fmt.Println(change)
+        if change.HasAdjustment() { alert(change) }
+    }
+*/
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// AccountChangeKind identifies which account field changed.
+type AccountChangeKind int
+
+const (
+	AccountBalanceChanged AccountChangeKind = iota
+	AccountCreditChanged
+	AccountLeverageChanged
+)
+
+func (k AccountChangeKind) String() string {
+	switch k {
+	case AccountBalanceChanged:
+		return "balance"
+	case AccountCreditChanged:
+		return "credit"
+	case AccountLeverageChanged:
+		return "leverage"
+	default:
+		return fmt.Sprintf("AccountChangeKind(%d)", int(k))
+	}
+}
+
+// AccountDeal is a deal that contributed to a balance or credit change.
+type AccountDeal struct {
+	Ticket  uint64
+	Type    pb.BMT5_ENUM_DEAL_TYPE
+	Symbol  string
+	Amount  float64 // Profit + swap + commission + fee
+	Comment string
+	Time    time.Time
+}
+
+// IsAdjustment reports whether the deal is a non-trading operation
+// (deposit, credit, charge, correction, bonus, commission, interest).
+func (d AccountDeal) IsAdjustment() bool {
+	switch d.Type {
+	case pb.BMT5_ENUM_DEAL_TYPE_BMT5_DEAL_TYPE_BUY, pb.BMT5_ENUM_DEAL_TYPE_BMT5_DEAL_TYPE_SELL:
+		return false
+	}
+	return true
+}
+
+// AccountChange describes one field changing between two snapshots.
+type AccountChange struct {
+	Kind        AccountChangeKind
+	Previous    float64
+	Current     float64
+	Delta       float64
+	Deals       []AccountDeal // Deals since the previous poll explaining the change
+	Unexplained float64       // Part of Delta not covered by Deals
+	Time        time.Time
+}
+
+// HasAdjustment reports whether a non-trading deal contributed to the change,
+// or part of it is unexplained (silent broker adjustment).
+func (c AccountChange) HasAdjustment() bool {
+	if math.Abs(c.Unexplained) >= 0.01 {
+		return true
+	}
+	for _, deal := range c.Deals {
+		if deal.IsAdjustment() {
+			return true
+		}
+	}
+	return false
+}
+
+func (c AccountChange) String() string {
+	if c.Kind == AccountLeverageChanged {
+		return fmt.Sprintf("leverage %.0f → %.0f", c.Previous, c.Current)
+	}
+
+	var parts []string
+	for _, deal := range c.Deals {
+		part := fmt.Sprintf("deal #%d %s", deal.Ticket, strings.TrimPrefix(deal.Type.String(), "BMT5_DEAL_TYPE_"))
+		if deal.Symbol != "" {
+			part += " " + deal.Symbol
+		}
+		part += fmt.Sprintf(" %+.2f", deal.Amount)
+		if deal.Comment != "" {
+			part += fmt.Sprintf(" %q", deal.Comment)
+		}
+		parts = append(parts, part)
+	}
+	if math.Abs(c.Unexplained) >= 0.01 {
+		parts = append(parts, fmt.Sprintf("unexplained %+.2f", c.Unexplained))
+	}
+	return fmt.Sprintf("%s %.2f → %.2f (%+.2f): %s", c.Kind, c.Previous, c.Current, c.Delta, strings.Join(parts, ", "))
+}
+
+// WatchAccountChanges polls the account summary every interval and emits a
+// change for each of balance, credit and leverage that differs from the
+// previous snapshot. Balance and credit changes carry the deals that appeared
+// since the previous poll.
+//
+// Parameters:
+//   - ctx: Context for cancellation (closing ctx stops the watch)
+//   - interval: Poll interval (default 10s)
+//
+// Returns:
+//   - Read-only channel of AccountChange
+//   - Read-only channel of errors (polling continues after errors)
+func (s *MT5Service) WatchAccountChanges(ctx context.Context, interval time.Duration) (<-chan AccountChange, <-chan error) {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	changeCh := make(chan AccountChange, 16)
+	errCh := make(chan error, 4)
+
+	go func() {
+		defer close(changeCh)
+		defer close(errCh)
+
+		report := func(err error) {
+			select {
+			case errCh <- err:
+			default:
+			}
+		}
+
+		seen := make(map[uint64]time.Time)
+		var prev *AccountSummary
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			summary, err := s.GetAccountSummary(ctx)
+			if err != nil {
+				report(fmt.Errorf("WatchAccountChanges failed: %w", err))
+			} else {
+				// Deals are read on every poll, so the first poll marks existing deals as seen
+				deals, derr := s.newAccountDeals(ctx, seen)
+				if derr != nil {
+					report(fmt.Errorf("WatchAccountChanges deals: %w", derr))
+				}
+				if prev != nil {
+					for _, change := range diffAccountSummary(prev, summary, deals) {
+						select {
+						case changeCh <- change:
+						case <-ctx.Done():
+							return
+						}
+					}
+				}
+				prev = summary
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return changeCh, errCh
+}
+
+// newAccountDeals returns deals not in seen and records them there. The
+// window is wide because deal times are in server time (broker timezone).
+func (s *MT5Service) newAccountDeals(ctx context.Context, seen map[uint64]time.Time) ([]AccountDeal, error) {
+	now := time.Now()
+	from := now.Add(-2 * 24 * time.Hour)
+	to := now.Add(24 * time.Hour)
+
+	data, err := s.GetOrderHistory(ctx, from, to,
+		pb.BMT5_ENUM_ORDER_HISTORY_SORT_TYPE_BMT5_SORT_BY_CLOSE_TIME_DESC, 1, 1000)
+	if err != nil {
+		return nil, err
+	}
+
+	var deals []AccountDeal
+	for _, item := range data.GetHistoryData() {
+		deal := item.GetHistoryDeal()
+		if deal == nil {
+			continue
+		}
+		if _, ok := seen[deal.Ticket]; ok {
+			continue
+		}
+		seen[deal.Ticket] = now
+
+		accountDeal := AccountDeal{
+			Ticket:  deal.Ticket,
+			Type:    deal.Type,
+			Symbol:  deal.Symbol,
+			Amount:  deal.Profit + deal.Swap + deal.Commission + deal.Fee,
+			Comment: deal.Comment,
+		}
+		if deal.Time != nil {
+			accountDeal.Time = deal.Time.AsTime()
+		}
+		deals = append(deals, accountDeal)
+	}
+
+	// Tickets older than the query window cannot reappear
+	for ticket, at := range seen {
+		if now.Sub(at) > 3*24*time.Hour {
+			delete(seen, ticket)
+		}
+	}
+	return deals, nil
+}
+
+// diffAccountSummary compares two snapshots. Credit deals explain credit
+// changes; all other deals explain balance changes.
+func diffAccountSummary(prev, cur *AccountSummary, deals []AccountDeal) []AccountChange {
+	now := time.Now()
+	var changes []AccountChange
+
+	var balanceDeals, creditDeals []AccountDeal
+	for _, deal := range deals {
+		if deal.Type == pb.BMT5_ENUM_DEAL_TYPE_BMT5_DEAL_TYPE_CREDIT {
+			creditDeals = append(creditDeals, deal)
+		} else if deal.Amount != 0 {
+			balanceDeals = append(balanceDeals, deal)
+		}
+	}
+
+	money := func(kind AccountChangeKind, before, after float64, related []AccountDeal) {
+		if math.Abs(after-before) < 0.005 {
+			return
+		}
+		explained := 0.0
+		for _, deal := range related {
+			explained += deal.Amount
+		}
+		changes = append(changes, AccountChange{
+			Kind:        kind,
+			Previous:    before,
+			Current:     after,
+			Delta:       after - before,
+			Deals:       related,
+			Unexplained: (after - before) - explained,
+			Time:        now,
+		})
+	}
+
+	money(AccountBalanceChanged, prev.Balance, cur.Balance, balanceDeals)
+	money(AccountCreditChanged, prev.Credit, cur.Credit, creditDeals)
+
+	if prev.Leverage != cur.Leverage {
+		changes = append(changes, AccountChange{
+			Kind:     AccountLeverageChanged,
+			Previous: float64(prev.Leverage),
+			Current:  float64(cur.Leverage),
+			Delta:    float64(cur.Leverage - prev.Leverage),
+			Time:     now,
+		})
+	}
+	return changes
+}
@@ -0,0 +1,252 @@
+package mt5
+
+/*
+Alternative bars - Renko bricks, range bars and tick bars built from ticks.
+
+Time-based candles (CandleBuilder) close when the clock says so. These
+builders close bars on price movement or activity instead, and return the
+same Candle type, so anything that works on []Candle (AverageTrueRange,
+CandleCache, strategies' OnBar) works on them unchanged.
+
+  • RenkoBuilder     - a brick every BrickSize of movement; reversal needs 2 bricks
+  • RangeBarBuilder  - a bar closes when High-Low reaches Range
+  • TickBarBuilder   - a bar closes every N ticks
+
+Candle.Time is the time of the tick that OPENED the bar and Candle.Timeframe
+is 0 (bars have no fixed length). Prices are Bid, like CandleBuilder.
+
+Usage:
+    renko := mt5.NewRenkoBuilder("EURUSD", 0.0010)
+    for tick := range ticks {
+        for _, brick := range renko.Add(tick) {
+            fmt.Printf("brick %.5f -> %.5f\n", brick.Open, brick.Close)
+        }
+    }
+*/
+
+import (
+	"math"
+)
+
+// BarBuilder is implemented by the non-time bar builders.
+// Add returns the bars finished by the tick (usually none or one; a price
+// gap can finish several Renko bricks or range bars at once).
+type BarBuilder interface {
+	Add(tick *SymbolTick) []Candle
+	Current() *Candle
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// RENKO
+// ══════════════════════════════════════════════════════════════════════════════
+
+// RenkoBuilder builds Renko bricks of a fixed price size. Not safe for concurrent use.
+type RenkoBuilder struct {
+	symbol    string
+	brickSize float64
+	lastClose float64 // Close of the last brick (0 = no base yet)
+	direction int     // +1 up, -1 down, 0 before the first brick
+	current   *Candle // Movement since the last brick
+}
+
+// NewRenkoBuilder creates a Renko builder. brickSize is in price units (e.g., 0.0010 = 10 pips on EURUSD).
+func NewRenkoBuilder(symbol string, brickSize float64) *RenkoBuilder {
+	return &RenkoBuilder{
+		symbol:    symbol,
+		brickSize: brickSize,
+	}
+}
+
+// Add feeds a tick and returns the bricks it completes.
+func (b *RenkoBuilder) Add(tick *SymbolTick) []Candle {
+	if tick == nil || tick.Bid <= 0 || b.brickSize <= 0 {
+		return nil
+	}
+	price := tick.Bid
+
+	if b.lastClose == 0 {
+		b.lastClose = math.Floor(price/b.brickSize) * b.brickSize
+	}
+	b.current = extendBar(b.current, b.symbol, tick)
+
+	var bricks []Candle
+	for {
+		up := b.lastClose + b.brickSize
+		down := b.lastClose - b.brickSize
+		if b.direction > 0 {
+			down = b.lastClose - 2*b.brickSize
+		} else if b.direction < 0 {
+			up = b.lastClose + 2*b.brickSize
+		}
+
+		var open, close float64
+		switch {
+		case price >= up:
+			open, close = up-b.brickSize, up
+			b.direction = 1
+		case price <= down:
+			open, close = down+b.brickSize, down
+			b.direction = -1
+		default:
+			return bricks
+		}
+
+		brick := *b.current
+		brick.Open = open
+		brick.Close = close
+		brick.High = math.Max(open, close)
+		brick.Low = math.Min(open, close)
+		bricks = append(bricks, brick)
+
+		b.lastClose = close
+		b.current = &Candle{Symbol: b.symbol, Time: tick.Time, Open: close, High: close, Low: close, Close: close}
+	}
+}
+
+// Current returns a copy of the movement since the last brick, or nil before the first tick.
+func (b *RenkoBuilder) Current() *Candle {
+	return copyBar(b.current)
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// RANGE BARS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// RangeBarBuilder closes a bar each time its High-Low reaches a fixed range.
+// Not safe for concurrent use.
+type RangeBarBuilder struct {
+	symbol  string
+	rng     float64
+	current *Candle
+}
+
+// NewRangeBarBuilder creates a range bar builder. rng is in price units.
+func NewRangeBarBuilder(symbol string, rng float64) *RangeBarBuilder {
+	return &RangeBarBuilder{
+		symbol: symbol,
+		rng:    rng,
+	}
+}
+
+// Add feeds a tick and returns the bars it completes.
+// A gap larger than the range closes several full-range bars.
+func (b *RangeBarBuilder) Add(tick *SymbolTick) []Candle {
+	if tick == nil || tick.Bid <= 0 || b.rng <= 0 {
+		return nil
+	}
+	price := tick.Bid
+
+	if b.current == nil {
+		b.current = extendBar(nil, b.symbol, tick)
+		return nil
+	}
+
+	var bars []Candle
+	for {
+		c := b.current
+		var edge float64
+		switch {
+		case price > c.Low+b.rng:
+			edge = c.Low + b.rng
+		case price < c.High-b.rng:
+			edge = c.High - b.rng
+		default:
+			c.High = math.Max(c.High, price)
+			c.Low = math.Min(c.Low, price)
+			c.Close = price
+			c.TickVolume++
+			c.RealVolume += tick.VolumeReal
+			return bars
+		}
+
+		c.High = math.Max(c.High, edge)
+		c.Low = math.Min(c.Low, edge)
+		c.Close = edge
+		bars = append(bars, *c)
+
+		b.current = &Candle{Symbol: b.symbol, Time: tick.Time, Open: edge, High: edge, Low: edge, Close: edge}
+	}
+}
+
+// Current returns a copy of the bar still being built, or nil before the first tick.
+func (b *RangeBarBuilder) Current() *Candle {
+	return copyBar(b.current)
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// TICK BARS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// TickBarBuilder closes a bar every N ticks. Not safe for concurrent use.
+type TickBarBuilder struct {
+	symbol  string
+	ticks   uint64
+	current *Candle
+}
+
+// NewTickBarBuilder creates a builder closing a bar every ticksPerBar ticks.
+func NewTickBarBuilder(symbol string, ticksPerBar int) *TickBarBuilder {
+	if ticksPerBar <= 0 {
+		ticksPerBar = 1
+	}
+	return &TickBarBuilder{
+		symbol: symbol,
+		ticks:  uint64(ticksPerBar),
+	}
+}
+
+// Add feeds a tick and returns the bar it completes, if any.
+func (b *TickBarBuilder) Add(tick *SymbolTick) []Candle {
+	if tick == nil || tick.Bid <= 0 {
+		return nil
+	}
+
+	b.current = extendBar(b.current, b.symbol, tick)
+	if b.current.TickVolume < b.ticks {
+		return nil
+	}
+
+	bar := *b.current
+	b.current = nil
+	return []Candle{bar}
+}
+
+// Current returns a copy of the bar still being built, or nil before the first tick.
+func (b *TickBarBuilder) Current() *Candle {
+	return copyBar(b.current)
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// HELPERS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// extendBar applies a tick to bar, opening a new bar if bar is nil.
+func extendBar(bar *Candle, symbol string, tick *SymbolTick) *Candle {
+	if bar == nil {
+		return &Candle{
+			Symbol:     symbol,
+			Time:       tick.Time,
+			Open:       tick.Bid,
+			High:       tick.Bid,
+			Low:        tick.Bid,
+			Close:      tick.Bid,
+			TickVolume: 1,
+			RealVolume: tick.VolumeReal,
+		}
+	}
+	bar.High = math.Max(bar.High, tick.Bid)
+	bar.Low = math.Min(bar.Low, tick.Bid)
+	bar.Close = tick.Bid
+	bar.TickVolume++
+	bar.RealVolume += tick.VolumeReal
+	return bar
+}
+
+// copyBar returns a copy of bar, or nil.
+func copyBar(bar *Candle) *Candle {
+	if bar == nil {
+		return nil
+	}
+	c := *bar
+	return &c
+}
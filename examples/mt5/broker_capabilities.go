@@ -0,0 +1,177 @@
+package mt5
+
+/*
+Broker capabilities - one probe for the settings that differ between brokers.
+
+Strategies written against one broker break on another because of:
+  • Position accounting   - hedging (many positions per symbol) vs netting (one)
+  • FIFO close            - US brokers require closing oldest position first
+  • Order limit           - max positions + pending orders (0 = unlimited)
+  • Filling modes         - FOK / IOC / RETURN per symbol
+  • Stops / freeze level  - min distance of SL/TP and pending orders from price
+
+DetectBrokerCapabilities reads all of them into one struct so a strategy can
+adapt (e.g., close by opposite order on netting, skip grid levels inside the
+stops level) instead of failing on the first rejected order.
+
+Usage:
+    caps, err := service.DetectBrokerCapabilities(ctx, "EURUSD", "XAUUSD")
+    if !caps.Hedging {
+        // netting: a SELL reduces the BUY position instead of opening a second one
+    }
+    if sym := caps.Symbol("EURUSD"); sym != nil {
+        minDistance := float64(sym.StopsLevel) * point
+    }
+*/
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// AccountMarginMode is ENUM_ACCOUNT_MARGIN_MODE (not exposed as protobuf enum).
+type AccountMarginMode int64
+
+const (
+	MarginModeRetailNetting AccountMarginMode = 0 // One position per symbol
+	MarginModeExchange      AccountMarginMode = 1 // Exchange netting
+	MarginModeRetailHedging AccountMarginMode = 2 // Independent positions per symbol
+)
+
+func (m AccountMarginMode) String() string {
+	switch m {
+	case MarginModeRetailNetting:
+		return "RETAIL_NETTING"
+	case MarginModeExchange:
+		return "EXCHANGE"
+	case MarginModeRetailHedging:
+		return "RETAIL_HEDGING"
+	default:
+		return fmt.Sprintf("MARGIN_MODE(%d)", int64(m))
+	}
+}
+
+// SymbolCapabilities holds the trading rules of one symbol.
+type SymbolCapabilities struct {
+	Symbol         string
+	TradeMode      pb.BMT5_ENUM_SYMBOL_TRADE_MODE    // FULL, LONGONLY, CLOSEONLY, ...
+	FillingModes   []pb.MRPC_ENUM_ORDER_TYPE_FILLING // Allowed filling policies
+	StopsLevel     int64                             // Min SL/TP/pending distance from price, points
+	FreezeLevel    int64                             // Distance inside which orders cannot be modified, points
+	ExpirationMode int64                             // SYMBOL_EXPIRATION_MODE flags (GTC=1, DAY=2, SPECIFIED=4, SPECIFIED_DAY=8)
+	OrderMode      int64                             // SYMBOL_ORDER_MODE flags (MARKET=1, LIMIT=2, STOP=4, STOP_LIMIT=8, SL=16, TP=32, CLOSEBY=64)
+}
+
+// SupportsFilling reports whether a filling policy is allowed.
+func (c *SymbolCapabilities) SupportsFilling(mode pb.MRPC_ENUM_ORDER_TYPE_FILLING) bool {
+	for _, m := range c.FillingModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// BrokerCapabilities holds account-level rules and per-symbol rules.
+type BrokerCapabilities struct {
+	MarginMode    AccountMarginMode
+	Hedging       bool  // Opposite positions on one symbol allowed
+	FIFOClose     bool  // Positions must be closed oldest first
+	MaxOrders     int64 // Max open positions + pending orders (0 = unlimited)
+	TradeAllowed  bool  // Account may trade
+	ExpertAllowed bool  // Algo trading allowed
+	Symbols       map[string]*SymbolCapabilities
+	DetectedAt    time.Time
+}
+
+// Symbol returns the capabilities of a symbol, or nil if it was not probed.
+func (c *BrokerCapabilities) Symbol(symbol string) *SymbolCapabilities {
+	return c.Symbols[symbol]
+}
+
+// DetectBrokerCapabilities reads account rules and the rules of the given symbols.
+//
+// Parameters:
+//   - ctx: Context for timeout and cancellation
+//   - symbols: Symbols to probe (may be empty for account rules only)
+//
+// Returns:
+//   - BrokerCapabilities with account and symbol rules
+//   - Error if a property could not be read
+func (s *MT5Service) DetectBrokerCapabilities(ctx context.Context, symbols ...string) (*BrokerCapabilities, error) {
+	account := map[pb.AccountInfoIntegerPropertyType]int64{
+		pb.AccountInfoIntegerPropertyType_ACCOUNT_MARGIN_MODE:   0,
+		pb.AccountInfoIntegerPropertyType_ACCOUNT_HEDGE_ALLOWED: 0,
+		pb.AccountInfoIntegerPropertyType_ACCOUNT_FIFO_CLOSE:    0,
+		pb.AccountInfoIntegerPropertyType_ACCOUNT_LIMIT_ORDERS:  0,
+		pb.AccountInfoIntegerPropertyType_ACCOUNT_TRADE_ALLOWED: 0,
+		pb.AccountInfoIntegerPropertyType_ACCOUNT_TRADE_EXPERT:  0,
+	}
+	for prop := range account {
+		value, err := s.GetAccountInteger(ctx, prop)
+		if err != nil {
+			return nil, fmt.Errorf("DetectBrokerCapabilities failed (%s): %w", prop, err)
+		}
+		account[prop] = value
+	}
+
+	caps := &BrokerCapabilities{
+		MarginMode:    AccountMarginMode(account[pb.AccountInfoIntegerPropertyType_ACCOUNT_MARGIN_MODE]),
+		FIFOClose:     account[pb.AccountInfoIntegerPropertyType_ACCOUNT_FIFO_CLOSE] != 0,
+		MaxOrders:     account[pb.AccountInfoIntegerPropertyType_ACCOUNT_LIMIT_ORDERS],
+		TradeAllowed:  account[pb.AccountInfoIntegerPropertyType_ACCOUNT_TRADE_ALLOWED] != 0,
+		ExpertAllowed: account[pb.AccountInfoIntegerPropertyType_ACCOUNT_TRADE_EXPERT] != 0,
+		Symbols:       make(map[string]*SymbolCapabilities, len(symbols)),
+		DetectedAt:    time.Now(),
+	}
+	// Some servers report HEDGE_ALLOWED = 0 on hedging accounts; margin mode is authoritative
+	caps.Hedging = caps.MarginMode == MarginModeRetailHedging ||
+		account[pb.AccountInfoIntegerPropertyType_ACCOUNT_HEDGE_ALLOWED] != 0
+
+	for _, symbol := range symbols {
+		symCaps, err := s.detectSymbolCapabilities(ctx, symbol)
+		if err != nil {
+			return nil, fmt.Errorf("DetectBrokerCapabilities failed: %w", err)
+		}
+		caps.Symbols[symbol] = symCaps
+	}
+
+	return caps, nil
+}
+
+// detectSymbolCapabilities reads the trading rules of one symbol.
+func (s *MT5Service) detectSymbolCapabilities(ctx context.Context, symbol string) (*SymbolCapabilities, error) {
+	props := []pb.SymbolInfoIntegerProperty{
+		pb.SymbolInfoIntegerProperty_SYMBOL_TRADE_MODE,
+		pb.SymbolInfoIntegerProperty_SYMBOL_TRADE_STOPS_LEVEL,
+		pb.SymbolInfoIntegerProperty_SYMBOL_TRADE_FREEZE_LEVEL,
+		pb.SymbolInfoIntegerProperty_SYMBOL_EXPIRATION_MODE,
+		pb.SymbolInfoIntegerProperty_SYMBOL_ORDER_MODE,
+	}
+	values := make([]int64, len(props))
+	for i, prop := range props {
+		value, err := s.GetSymbolInteger(ctx, symbol, prop)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", symbol, prop, err)
+		}
+		values[i] = value
+	}
+
+	filling, err := s.GetSymbolFillingModes(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SymbolCapabilities{
+		Symbol:         symbol,
+		TradeMode:      pb.BMT5_ENUM_SYMBOL_TRADE_MODE(values[0]),
+		FillingModes:   filling,
+		StopsLevel:     values[1],
+		FreezeLevel:    values[2],
+		ExpirationMode: values[3],
+		OrderMode:      values[4],
+	}, nil
+}
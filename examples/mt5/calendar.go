@@ -0,0 +1,441 @@
+package mt5
+
+/*
+Economic calendar - event model, provider interface and news filter.
+
+Strategies never read a calendar feed directly: they ask a CalendarProvider
+for the events of a time range, so any source (a file exported from a
+website, an HTTP API, a database) can be plugged in by implementing one
+method:
+
+    type CalendarProvider interface {
+        Events(ctx context.Context, from, to time.Time) ([]CalendarEvent, error)
+    }
+
+Built-in providers:
+  • FileCalendar   - CSV or JSON file, re-read when the file changes
+  • StaticCalendar - events held in memory (tests, hand-written lists)
+
+File formats (times in UTC, RFC 3339 or "2006-01-02 15:04"):
+
+    CSV:  time,currency,impact,title,forecast,previous,actual
+          2024-06-07 12:30,USD,high,Non-Farm Payrolls,185K,175K,
+
+    JSON: [{"time": "2024-06-07T12:30:00Z", "currency": "USD",
+            "impact": "high", "title": "Non-Farm Payrolls"}]
+
+Only time, currency and title are required; impact defaults to low. Extra
+CSV columns are ignored, columns are matched by header name.
+
+NewsFilter consumes a provider: it reports the blackout window around
+events of the symbol's currencies and the upcoming events for scheduling.
+Symbol currencies are the base and quote of FX names; map other symbols
+with SetCurrencies("US500", "USD").
+
+Usage:
+    calendar := mt5.NewFileCalendar("calendar.csv")
+    news := mt5.NewNewsFilter(calendar, mt5.ImpactHigh, 15*time.Minute, 30*time.Minute)
+
+    if event, err := news.Blackout(ctx, "EURUSD", time.Now()); err == nil && event != nil {
+        fmt.Printf("skip entry: %s %s at %s\n", event.Currency, event.Title, event.Time)
+    }
+    next, _ := news.Upcoming(ctx, "EURUSD", 24*time.Hour)
+
+    sugar.SetNewsFilter(news) // every Sugar order now fails with ErrNewsBlackout inside a window
+*/
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrNewsBlackout = errors.New("news blackout")
+
+// EventImpact is the expected market impact of a calendar event.
+type EventImpact int
+
+const (
+	ImpactLow EventImpact = iota
+	ImpactMedium
+	ImpactHigh
+)
+
+func (i EventImpact) String() string {
+	switch i {
+	case ImpactMedium:
+		return "medium"
+	case ImpactHigh:
+		return "high"
+	default:
+		return "low"
+	}
+}
+
+// ParseEventImpact parses "low", "medium"/"moderate" or "high" (case
+// insensitive; "1"-"3" also accepted). Empty text is low impact.
+func ParseEventImpact(text string) (EventImpact, error) {
+	switch strings.ToLower(strings.TrimSpace(text)) {
+	case "", "low", "1":
+		return ImpactLow, nil
+	case "medium", "moderate", "2":
+		return ImpactMedium, nil
+	case "high", "3":
+		return ImpactHigh, nil
+	}
+	return ImpactLow, fmt.Errorf("unknown event impact %q", text)
+}
+
+func (i EventImpact) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String())
+}
+
+func (i *EventImpact) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		var level int
+		if err := json.Unmarshal(data, &level); err != nil {
+			return err
+		}
+		text = fmt.Sprint(level)
+	}
+	impact, err := ParseEventImpact(text)
+	if err != nil {
+		return err
+	}
+	*i = impact
+	return nil
+}
+
+// CalendarEvent is one scheduled economic release.
+type CalendarEvent struct {
+	Time     time.Time   `json:"time"`     // Release time (UTC)
+	Currency string      `json:"currency"` // Affected currency (e.g., "USD")
+	Impact   EventImpact `json:"impact"`
+	Title    string      `json:"title"`
+	Forecast string      `json:"forecast,omitempty"`
+	Previous string      `json:"previous,omitempty"`
+	Actual   string      `json:"actual,omitempty"` // Empty until released
+}
+
+// CalendarProvider supplies calendar events. Implementations must be safe
+// for concurrent use.
+type CalendarProvider interface {
+	// Events returns the events with from <= Time < to, sorted by time.
+	Events(ctx context.Context, from, to time.Time) ([]CalendarEvent, error)
+}
+
+// StaticCalendar is an in-memory CalendarProvider.
+type StaticCalendar []CalendarEvent
+
+// Events returns the events in [from, to), sorted by time.
+func (c StaticCalendar) Events(ctx context.Context, from, to time.Time) ([]CalendarEvent, error) {
+	return eventsBetween(c, from, to), nil
+}
+
+// eventsBetween filters events to [from, to) and sorts them by time.
+func eventsBetween(events []CalendarEvent, from, to time.Time) []CalendarEvent {
+	var result []CalendarEvent
+	for _, event := range events {
+		if !event.Time.Before(from) && event.Time.Before(to) {
+			result = append(result, event)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Time.Before(result[j].Time) })
+	return result
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// FILE CALENDAR
+// ══════════════════════════════════════════════════════════════════════════════
+
+// FileCalendar reads events from a CSV or JSON file (chosen by extension).
+// The file is parsed on first use and again whenever its modification time
+// changes, so an external job can refresh it while strategies run.
+type FileCalendar struct {
+	path string
+
+	mu      sync.Mutex
+	loaded  bool
+	modTime time.Time
+	events  []CalendarEvent
+}
+
+// NewFileCalendar creates a provider for a ".csv" or ".json" file. The file
+// is not read until the first Events call.
+func NewFileCalendar(path string) *FileCalendar {
+	return &FileCalendar{path: path}
+}
+
+// Events returns the file's events in [from, to), sorted by time.
+func (c *FileCalendar) Events(ctx context.Context, from, to time.Time) ([]CalendarEvent, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return nil, fmt.Errorf("calendar %s: %w", c.path, err)
+	}
+	if !c.loaded || !info.ModTime().Equal(c.modTime) {
+		events, err := readCalendarFile(c.path)
+		if err != nil {
+			return nil, fmt.Errorf("calendar %s: %w", c.path, err)
+		}
+		c.events, c.modTime, c.loaded = events, info.ModTime(), true
+	}
+	return eventsBetween(c.events, from, to), nil
+}
+
+// readCalendarFile parses a calendar file by extension.
+func readCalendarFile(path string) ([]CalendarEvent, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ReadCalendarCSV(f)
+	case ".json":
+		return ReadCalendarJSON(f)
+	}
+	return nil, fmt.Errorf("unsupported calendar format %q (use .csv or .json)", filepath.Ext(path))
+}
+
+// ReadCalendarJSON parses a JSON array of events.
+func ReadCalendarJSON(r io.Reader) ([]CalendarEvent, error) {
+	var events []CalendarEvent
+	if err := json.NewDecoder(r).Decode(&events); err != nil {
+		return nil, err
+	}
+	for i := range events {
+		if err := events[i].validate(); err != nil {
+			return nil, fmt.Errorf("event %d: %w", i+1, err)
+		}
+		events[i].Time = events[i].Time.UTC()
+		events[i].Currency = strings.ToUpper(events[i].Currency)
+	}
+	return events, nil
+}
+
+// ReadCalendarCSV parses CSV with a header row (see package docs for columns).
+func ReadCalendarCSV(r io.Reader) ([]CalendarEvent, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"time", "currency", "title"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing column %q", required)
+		}
+	}
+
+	var events []CalendarEvent
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		at, err := parseCalendarTime(field("time"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		impact, err := ParseEventImpact(field("impact"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		event := CalendarEvent{
+			Time:     at,
+			Currency: strings.ToUpper(field("currency")),
+			Impact:   impact,
+			Title:    field("title"),
+			Forecast: field("forecast"),
+			Previous: field("previous"),
+			Actual:   field("actual"),
+		}
+		if err := event.validate(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// parseCalendarTime accepts RFC 3339 or "2006-01-02 15:04[:05]" in UTC.
+func parseCalendarTime(text string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", text)
+}
+
+func (e CalendarEvent) validate() error {
+	switch {
+	case e.Time.IsZero():
+		return errors.New("missing time")
+	case e.Currency == "":
+		return errors.New("missing currency")
+	case e.Title == "":
+		return errors.New("missing title")
+	}
+	return nil
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// NEWS FILTER
+// ══════════════════════════════════════════════════════════════════════════════
+
+// NewsFilter blocks trading around calendar events of a symbol's currencies.
+type NewsFilter struct {
+	provider  CalendarProvider
+	minImpact EventImpact
+	before    time.Duration
+	after     time.Duration
+
+	mu         sync.RWMutex
+	currencies map[string][]string // Symbol → currencies, overrides symbol parsing
+}
+
+// NewNewsFilter creates a news filter.
+//
+// Parameters:
+//   - provider: Source of calendar events
+//   - minImpact: Events below this impact are ignored
+//   - before: Blackout starts this long before an event
+//   - after: Blackout ends this long after an event
+func NewNewsFilter(provider CalendarProvider, minImpact EventImpact, before, after time.Duration) *NewsFilter {
+	return &NewsFilter{
+		provider:   provider,
+		minImpact:  minImpact,
+		before:     before,
+		after:      after,
+		currencies: make(map[string][]string),
+	}
+}
+
+// SetCurrencies sets the currencies whose events affect a symbol. Needed
+// for symbols whose name is not two currency codes (e.g., "US500" → "USD",
+// "GER40" → "EUR").
+func (f *NewsFilter) SetCurrencies(symbol string, currencies ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	upper := make([]string, len(currencies))
+	for i, c := range currencies {
+		upper[i] = strings.ToUpper(c)
+	}
+	f.currencies[strings.ToUpper(symbol)] = upper
+}
+
+// symbolCurrencies returns the currencies of a symbol: explicit mapping
+// first, otherwise the base and quote of a 6-letter FX name (suffixes and
+// prefixes around it, as in "EURUSD.pro", are ignored).
+func (f *NewsFilter) symbolCurrencies(symbol string) []string {
+	symbol = strings.ToUpper(symbol)
+	f.mu.RLock()
+	mapped, ok := f.currencies[symbol]
+	f.mu.RUnlock()
+	if ok {
+		return mapped
+	}
+
+	letters := 0
+	for i, r := range symbol {
+		if r >= 'A' && r <= 'Z' {
+			letters++
+			if letters == 6 {
+				code := symbol[i-5 : i+1]
+				return []string{code[:3], code[3:]}
+			}
+			continue
+		}
+		letters = 0
+	}
+	return nil
+}
+
+// relevant keeps the events that affect the symbol at or above minImpact.
+func (f *NewsFilter) relevant(events []CalendarEvent, symbol string) []CalendarEvent {
+	currencies := f.symbolCurrencies(symbol)
+	var result []CalendarEvent
+	for _, event := range events {
+		if event.Impact < f.minImpact {
+			continue
+		}
+		for _, c := range currencies {
+			if event.Currency == c {
+				result = append(result, event)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// Blackout returns the event whose blackout window contains t, or nil when
+// the symbol may be traded.
+func (f *NewsFilter) Blackout(ctx context.Context, symbol string, t time.Time) (*CalendarEvent, error) {
+	events, err := f.provider.Events(ctx, t.Add(-f.after), t.Add(f.before).Add(time.Nanosecond))
+	if err != nil {
+		return nil, fmt.Errorf("NewsFilter failed: %w", err)
+	}
+	events = f.relevant(events, symbol)
+	if len(events) == 0 {
+		return nil, nil
+	}
+	return &events[0], nil
+}
+
+// Check returns an error wrapping ErrNewsBlackout when the symbol is inside
+// a blackout window now, nil otherwise.
+func (f *NewsFilter) Check(ctx context.Context, symbol string) error {
+	event, err := f.Blackout(ctx, symbol, time.Now())
+	if err != nil {
+		return err
+	}
+	if event != nil {
+		return fmt.Errorf("%w: %s %s %s at %s", ErrNewsBlackout, symbol,
+			event.Currency, event.Title, event.Time.Format("15:04 MST"))
+	}
+	return nil
+}
+
+// Upcoming returns the relevant events of the symbol from now until within,
+// sorted by time, so a scheduler can flatten or pause before each one.
+func (f *NewsFilter) Upcoming(ctx context.Context, symbol string, within time.Duration) ([]CalendarEvent, error) {
+	now := time.Now()
+	events, err := f.provider.Events(ctx, now, now.Add(within))
+	if err != nil {
+		return nil, fmt.Errorf("NewsFilter failed: %w", err)
+	}
+	return f.relevant(events, symbol), nil
+}
@@ -0,0 +1,302 @@
+package mt5
+
+/*
+CandleCache - local disk cache of candle history, one file per symbol/timeframe.
+
+Backtests and indicator warm-ups ask for the same months of bars over and
+over. CandleCache keeps them in CSV files under a directory and, on Get,
+fetches only what is missing: bars before the first cached bar and bars
+from the last cached bar onward (the last bar is re-fetched, since it may
+have been incomplete when stored).
+
+The gRPC API has no bar history call, so the source of missing bars is a
+CandleFetcher supplied by the caller (external data feed, recorded ticks
+run through CandleBuilder, etc.). Closed candles from a live CandleBuilder
+can also be written directly with Append.
+
+File layout:
+    <dir>/EURUSD_M1.csv     time,open,high,low,close,tick_volume,real_volume
+
+Usage:
+    cache, err := mt5.NewCandleCache("./candles")
+    bars, err := cache.Get(ctx, "EURUSD", time.Minute, from, to, fetchFromFeed)
+
+    // Live: persist bars as they close
+    if closed := builder.Add(tick); closed != nil {
+        cache.Append(*closed)
+    }
+*/
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CandleFetcher downloads candles of [from, to] for a symbol and timeframe.
+type CandleFetcher func(ctx context.Context, symbol string, timeframe time.Duration, from, to time.Time) ([]Candle, error)
+
+// CandleCache stores candle history on disk. Safe for concurrent use.
+type CandleCache struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewCandleCache creates a cache in dir, creating the directory if needed.
+func NewCandleCache(dir string) (*CandleCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("candle cache dir: %w", err)
+	}
+	return &CandleCache{dir: dir}, nil
+}
+
+// TimeframeLabel returns the MT5-style name of a timeframe (M1, H4, D1, ...).
+// Non-standard timeframes are named by seconds (e.g., S90).
+func TimeframeLabel(timeframe time.Duration) string {
+	switch {
+	case timeframe >= 24*time.Hour && timeframe%(24*time.Hour) == 0:
+		return fmt.Sprintf("D%d", timeframe/(24*time.Hour))
+	case timeframe >= time.Hour && timeframe%time.Hour == 0:
+		return fmt.Sprintf("H%d", timeframe/time.Hour)
+	case timeframe >= time.Minute && timeframe%time.Minute == 0:
+		return fmt.Sprintf("M%d", timeframe/time.Minute)
+	default:
+		return fmt.Sprintf("S%d", timeframe/time.Second)
+	}
+}
+
+// path returns the cache file of a symbol/timeframe.
+func (c *CandleCache) path(symbol string, timeframe time.Duration) string {
+	name := strings.NewReplacer("/", "_", "\\", "_", ":", "_").Replace(symbol)
+	return filepath.Join(c.dir, fmt.Sprintf("%s_%s.csv", name, TimeframeLabel(timeframe)))
+}
+
+// Load returns all cached candles of a symbol/timeframe, oldest first.
+// Returns nil (no error) if nothing is cached yet.
+func (c *CandleCache) Load(symbol string, timeframe time.Duration) ([]Candle, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.loadLocked(symbol, timeframe)
+}
+
+// Get returns candles of [from, to], fetching only bars missing from the cache.
+// Newly fetched bars are merged into the cache file.
+//
+// Parameters:
+//   - symbol, timeframe: Series to read
+//   - from, to: Requested range (bar open times, inclusive)
+//   - fetch: Source of missing bars (nil = cache only)
+//
+// Returns:
+//   - Candles in range, oldest first
+//   - Error if the cache file or the fetcher fails
+func (c *CandleCache) Get(ctx context.Context, symbol string, timeframe time.Duration, from, to time.Time, fetch CandleFetcher) ([]Candle, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, err := c.loadLocked(symbol, timeframe)
+	if err != nil {
+		return nil, err
+	}
+
+	if fetch != nil {
+		var fresh []Candle
+		if len(cached) == 0 {
+			fresh, err = fetch(ctx, symbol, timeframe, from, to)
+			if err != nil {
+				return nil, fmt.Errorf("candle fetch %s %s: %w", symbol, TimeframeLabel(timeframe), err)
+			}
+		} else {
+			first, last := cached[0].Time, cached[len(cached)-1].Time
+			if from.Before(first) {
+				older, err := fetch(ctx, symbol, timeframe, from, first.Add(-timeframe))
+				if err != nil {
+					return nil, fmt.Errorf("candle fetch %s %s: %w", symbol, TimeframeLabel(timeframe), err)
+				}
+				fresh = append(fresh, older...)
+			}
+			if !to.Before(last) {
+				newer, err := fetch(ctx, symbol, timeframe, last, to)
+				if err != nil {
+					return nil, fmt.Errorf("candle fetch %s %s: %w", symbol, TimeframeLabel(timeframe), err)
+				}
+				fresh = append(fresh, newer...)
+			}
+		}
+
+		if len(fresh) > 0 {
+			cached = mergeCandles(cached, fresh)
+			if err := c.writeLocked(symbol, timeframe, cached); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	lo := sort.Search(len(cached), func(i int) bool { return !cached[i].Time.Before(from) })
+	hi := sort.Search(len(cached), func(i int) bool { return cached[i].Time.After(to) })
+	return append([]Candle(nil), cached[lo:hi]...), nil
+}
+
+// Append merges candles into the cache. Candles may belong to different
+// symbols/timeframes; bars with an existing open time replace the cached bar.
+func (c *CandleCache) Append(candles ...Candle) error {
+	type key struct {
+		symbol    string
+		timeframe time.Duration
+	}
+	groups := make(map[key][]Candle)
+	for _, candle := range candles {
+		k := key{candle.Symbol, candle.Timeframe}
+		groups[k] = append(groups[k], candle)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k, group := range groups {
+		cached, err := c.loadLocked(k.symbol, k.timeframe)
+		if err != nil {
+			return err
+		}
+		if err := c.writeLocked(k.symbol, k.timeframe, mergeCandles(cached, group)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Clear deletes the cache file of a symbol/timeframe.
+func (c *CandleCache) Clear(symbol string, timeframe time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.Remove(c.path(symbol, timeframe)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("candle cache clear: %w", err)
+	}
+	return nil
+}
+
+// loadLocked reads a cache file. Caller must hold c.mu.
+func (c *CandleCache) loadLocked(symbol string, timeframe time.Duration) ([]Candle, error) {
+	f, err := os.Open(c.path(symbol, timeframe))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("candle cache read: %w", err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = 7
+
+	var candles []Candle
+	for line := 1; ; line++ {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("candle cache read %s: %w", f.Name(), err)
+		}
+		if line == 1 && rec[0] == "time" {
+			continue
+		}
+
+		candle, err := parseCandleRecord(rec)
+		if err != nil {
+			return nil, fmt.Errorf("candle cache %s line %d: %w", f.Name(), line, err)
+		}
+		candle.Symbol = symbol
+		candle.Timeframe = timeframe
+		candles = append(candles, candle)
+	}
+	return candles, nil
+}
+
+// writeLocked rewrites a cache file atomically. Caller must hold c.mu.
+func (c *CandleCache) writeLocked(symbol string, timeframe time.Duration, candles []Candle) error {
+	path := c.path(symbol, timeframe)
+	tmp, err := os.CreateTemp(c.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("candle cache write: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	w := csv.NewWriter(tmp)
+	w.Write([]string{"time", "open", "high", "low", "close", "tick_volume", "real_volume"})
+	for _, candle := range candles {
+		w.Write([]string{
+			strconv.FormatInt(candle.Time.Unix(), 10),
+			strconv.FormatFloat(candle.Open, 'f', -1, 64),
+			strconv.FormatFloat(candle.High, 'f', -1, 64),
+			strconv.FormatFloat(candle.Low, 'f', -1, 64),
+			strconv.FormatFloat(candle.Close, 'f', -1, 64),
+			strconv.FormatUint(candle.TickVolume, 10),
+			strconv.FormatFloat(candle.RealVolume, 'f', -1, 64),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("candle cache write: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("candle cache write: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("candle cache write: %w", err)
+	}
+	return nil
+}
+
+// parseCandleRecord converts one CSV record into a candle (without symbol/timeframe).
+func parseCandleRecord(rec []string) (Candle, error) {
+	var candle Candle
+
+	sec, err := strconv.ParseInt(rec[0], 10, 64)
+	if err != nil {
+		return candle, err
+	}
+	candle.Time = time.Unix(sec, 0).UTC()
+
+	prices := []*float64{&candle.Open, &candle.High, &candle.Low, &candle.Close}
+	for i, p := range prices {
+		if *p, err = strconv.ParseFloat(rec[i+1], 64); err != nil {
+			return candle, err
+		}
+	}
+	if candle.TickVolume, err = strconv.ParseUint(rec[5], 10, 64); err != nil {
+		return candle, err
+	}
+	if candle.RealVolume, err = strconv.ParseFloat(rec[6], 64); err != nil {
+		return candle, err
+	}
+	return candle, nil
+}
+
+// mergeCandles combines two series by open time; bars in fresh win on conflict.
+func mergeCandles(cached, fresh []Candle) []Candle {
+	byTime := make(map[int64]Candle, len(cached)+len(fresh))
+	for _, candle := range cached {
+		byTime[candle.Time.Unix()] = candle
+	}
+	for _, candle := range fresh {
+		byTime[candle.Time.Unix()] = candle
+	}
+
+	merged := make([]Candle, 0, len(byTime))
+	for _, candle := range byTime {
+		merged = append(merged, candle)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Time.Before(merged[j].Time) })
+	return merged
+}
@@ -0,0 +1,206 @@
+package mt5
+
+/*
+Candles - OHLC bars built locally from the tick stream.
+
+The gRPC API has no bar history call, so candles are aggregated from ticks
+(Bid price, like MT5 charts). CandleBuilder is fed one tick at a time and
+returns a finished candle each time a tick crosses into a new period.
+Resample / ResampleSession convert finished candles (e.g., M1) into any
+larger timeframe, optionally aligned to the broker's server-time sessions.
+
+Usage:
+    builder := mt5.NewCandleBuilder("EURUSD", time.Minute)
+    for tick := range ticks {
+        if closed := builder.Add(tick); closed != nil {
+            fmt.Printf("M1 closed: %.5f\n", closed.Close)
+        }
+    }
+
+    h2 := mt5.ResampleSession(m1Bars, 2*time.Hour, serverLoc, 0)
+*/
+
+import (
+	"time"
+)
+
+// Candle holds a single OHLC bar.
+type Candle struct {
+	Symbol     string        // Symbol name
+	Time       time.Time     // Bar open time (aligned to Timeframe)
+	Timeframe  time.Duration // Bar length (e.g., time.Minute for M1)
+	Open       float64       // First price in the bar
+	High       float64       // Highest price in the bar
+	Low        float64       // Lowest price in the bar
+	Close      float64       // Last price in the bar
+	TickVolume uint64        // Number of ticks in the bar
+	RealVolume float64       // Sum of real volume reported by ticks
+}
+
+// CandleBuilder aggregates ticks of one symbol into fixed-timeframe candles.
+// Not safe for concurrent use.
+type CandleBuilder struct {
+	symbol    string
+	timeframe time.Duration
+	current   *Candle
+}
+
+// NewCandleBuilder creates a builder for the given symbol and timeframe.
+func NewCandleBuilder(symbol string, timeframe time.Duration) *CandleBuilder {
+	return &CandleBuilder{
+		symbol:    symbol,
+		timeframe: timeframe,
+	}
+}
+
+// Add feeds a tick into the builder.
+// Returns the previous candle when this tick opens a new period, otherwise nil.
+// Ticks older than the current bar are ignored.
+func (b *CandleBuilder) Add(tick *SymbolTick) *Candle {
+	if tick == nil || tick.Bid <= 0 {
+		return nil
+	}
+
+	barTime := tick.Time.Truncate(b.timeframe)
+
+	if b.current != nil && barTime.Before(b.current.Time) {
+		return nil
+	}
+
+	if b.current != nil && barTime.Equal(b.current.Time) {
+		c := b.current
+		if tick.Bid > c.High {
+			c.High = tick.Bid
+		}
+		if tick.Bid < c.Low {
+			c.Low = tick.Bid
+		}
+		c.Close = tick.Bid
+		c.TickVolume++
+		c.RealVolume += tick.VolumeReal
+		return nil
+	}
+
+	closed := b.current
+	b.current = &Candle{
+		Symbol:     b.symbol,
+		Time:       barTime,
+		Timeframe:  b.timeframe,
+		Open:       tick.Bid,
+		High:       tick.Bid,
+		Low:        tick.Bid,
+		Close:      tick.Bid,
+		TickVolume: 1,
+		RealVolume: tick.VolumeReal,
+	}
+	return closed
+}
+
+// Current returns a copy of the bar still being built, or nil before the first tick.
+func (b *CandleBuilder) Current() *Candle {
+	if b.current == nil {
+		return nil
+	}
+	c := *b.current
+	return &c
+}
+
+// Flush returns the bar still being built and resets the builder.
+// Use it on shutdown so the last partial bar is not lost.
+func (b *CandleBuilder) Flush() *Candle {
+	c := b.current
+	b.current = nil
+	return c
+}
+
+// AverageTrueRange returns the simple average true range of the last period candles.
+// Returns 0 if there are fewer than period+1 candles.
+func AverageTrueRange(candles []Candle, period int) float64 {
+	if period <= 0 || len(candles) < period+1 {
+		return 0
+	}
+
+	sum := 0.0
+	for i := len(candles) - period; i < len(candles); i++ {
+		prevClose := candles[i-1].Close
+		tr := candles[i].High - candles[i].Low
+		if d := candles[i].High - prevClose; d > tr {
+			tr = d
+		}
+		if d := prevClose - candles[i].Low; d > tr {
+			tr = d
+		}
+		sum += tr
+	}
+	return sum / float64(period)
+}
+
+// BarTime returns the open time of the bar containing t, with bars aligned to
+// sessionStart after midnight in loc (e.g., the broker's server timezone).
+// Intraday timeframes restart at every session boundary, like MT5 charts, so
+// custom sizes (M7, H5) never straddle two sessions. A nil loc means UTC.
+func BarTime(t time.Time, timeframe time.Duration, loc *time.Location, sessionStart time.Duration) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	local := t.In(loc)
+	sessionOpen := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc).Add(sessionStart)
+	if local.Before(sessionOpen) {
+		sessionOpen = sessionOpen.AddDate(0, 0, -1)
+	}
+
+	if timeframe <= 0 {
+		return sessionOpen
+	}
+	if timeframe >= 24*time.Hour {
+		days := int(timeframe / (24 * time.Hour))
+		epoch := time.Date(1970, 1, 1, 0, 0, 0, 0, loc).Add(sessionStart)
+		elapsed := int(sessionOpen.Sub(epoch).Hours()+12) / 24
+		return sessionOpen.AddDate(0, 0, -(elapsed % days))
+	}
+	return sessionOpen.Add(local.Sub(sessionOpen) / timeframe * timeframe)
+}
+
+// Resample aggregates candles (e.g., M1) into a larger timeframe aligned to UTC midnight.
+// Input must be in chronological order; the last output bar may be incomplete.
+func Resample(candles []Candle, timeframe time.Duration) []Candle {
+	return ResampleSession(candles, timeframe, time.UTC, 0)
+}
+
+// ResampleSession aggregates candles into a larger timeframe with bars aligned
+// to server-time session boundaries (see BarTime).
+//
+// Parameters:
+//   - candles: Source bars in chronological order (any timeframe smaller than target)
+//   - timeframe: Target bar length (M3 = 3*time.Minute, H2 = 2*time.Hour, ...)
+//   - loc: Server timezone (nil = UTC)
+//   - sessionStart: Offset of the session open from local midnight (0 for most brokers)
+//
+// Returns:
+//   - Resampled candles, oldest first
+func ResampleSession(candles []Candle, timeframe time.Duration, loc *time.Location, sessionStart time.Duration) []Candle {
+	var out []Candle
+	for _, c := range candles {
+		barTime := BarTime(c.Time, timeframe, loc, sessionStart)
+
+		if n := len(out); n > 0 && out[n-1].Time.Equal(barTime) {
+			bar := &out[n-1]
+			if c.High > bar.High {
+				bar.High = c.High
+			}
+			if c.Low < bar.Low {
+				bar.Low = c.Low
+			}
+			bar.Close = c.Close
+			bar.TickVolume += c.TickVolume
+			bar.RealVolume += c.RealVolume
+			continue
+		}
+
+		c.Time = barTime
+		c.Timeframe = timeframe
+		out = append(out, c)
+	}
+	return out
+}
@@ -0,0 +1,114 @@
+package mt5
+
+/*
+CommissionModel - configurable per-symbol commission schemes.
+
+Many brokers charge commission but do not report it in deal history (it is
+booked separately, or only in the monthly statement), and OrderCheck never
+reports it before execution. CommissionModel fills that gap for cost
+estimates (CostModel, ValidateOrder) and realized P/L reports
+(TradingSummary) when deals carry no commission.
+
+Schemes:
+  • CommissionPerLot     - Rate per 1.0 lot per side (e.g., 3.5 = $7 round trip)
+  • CommissionPerDeal    - flat Rate per deal (entry and exit are two deals)
+  • CommissionPercent    - Rate % of notional (volume x contract size x price)
+
+Amounts are POSITIVE costs in account currency. Percentage commission uses
+the notional in the symbol's quote currency, which is exact only when the
+quote currency is the account currency.
+
+Usage:
+    commissions := mt5.NewCommissionModel(mt5.CommissionRule{Scheme: mt5.CommissionPerLot, Rate: 3.5})
+    commissions.Set("DE40", mt5.CommissionRule{Scheme: mt5.CommissionPercent, Rate: 0.002, Min: 1})
+    sugar.SetCommissionModel(commissions)
+*/
+
+import (
+	"math"
+	"sync"
+)
+
+// CommissionScheme selects how commission is charged.
+type CommissionScheme int
+
+const (
+	CommissionNone    CommissionScheme = iota // No commission
+	CommissionPerLot                          // Rate per lot per side
+	CommissionPerDeal                         // Flat Rate per deal
+	CommissionPercent                         // Rate % of notional per side
+)
+
+// CommissionRule is the commission scheme of one symbol.
+type CommissionRule struct {
+	Scheme CommissionScheme
+	Rate   float64 // Meaning depends on Scheme
+	Min    float64 // Minimum charge per deal (0 = none)
+}
+
+// CommissionModel maps symbols to commission rules. Safe for concurrent use.
+type CommissionModel struct {
+	mu          sync.RWMutex
+	defaultRule CommissionRule
+	rules       map[string]CommissionRule
+}
+
+// NewCommissionModel creates a model applying defaultRule to symbols without their own rule.
+func NewCommissionModel(defaultRule CommissionRule) *CommissionModel {
+	return &CommissionModel{
+		defaultRule: defaultRule,
+		rules:       make(map[string]CommissionRule),
+	}
+}
+
+// Set assigns a rule to a symbol.
+func (m *CommissionModel) Set(symbol string, rule CommissionRule) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rules[symbol] = rule
+}
+
+// Rule returns the rule applied to a symbol.
+func (m *CommissionModel) Rule(symbol string) CommissionRule {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if rule, ok := m.rules[symbol]; ok {
+		return rule
+	}
+	return m.defaultRule
+}
+
+// PerDeal returns the commission of one deal (one side of a trade).
+//
+// Parameters:
+//   - symbol: Trading symbol
+//   - volume: Deal volume in lots
+//   - price: Deal price (used by CommissionPercent only)
+//   - contractSize: Symbol contract size (used by CommissionPercent only)
+func (m *CommissionModel) PerDeal(symbol string, volume, price, contractSize float64) float64 {
+	rule := m.Rule(symbol)
+
+	var amount float64
+	switch rule.Scheme {
+	case CommissionPerLot:
+		amount = rule.Rate * volume
+	case CommissionPerDeal:
+		amount = rule.Rate
+	case CommissionPercent:
+		amount = volume * contractSize * price * rule.Rate / 100.0
+	default:
+		return 0
+	}
+	return math.Max(amount, rule.Min)
+}
+
+// RoundTrip returns the commission of opening and closing a position.
+func (m *CommissionModel) RoundTrip(symbol string, volume, openPrice, closePrice, contractSize float64) float64 {
+	return m.PerDeal(symbol, volume, openPrice, contractSize) + m.PerDeal(symbol, volume, closePrice, contractSize)
+}
+
+// NeedsContractSize reports whether the symbol's rule uses notional value.
+func (m *CommissionModel) NeedsContractSize(symbol string) bool {
+	return m.Rule(symbol).Scheme == CommissionPercent
+}
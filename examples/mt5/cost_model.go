@@ -0,0 +1,193 @@
+package mt5
+
+/*
+CostModel - estimated round-trip execution cost per trade.
+
+A trade pays three costs on the way in and out:
+  • Spread      - crossing Bid/Ask once (current symbol spread)
+  • Commission  - round trip, from CommissionModel or per-lot config
+  • Slippage    - expected slippage on entry AND exit, taken from recorded
+                  fills (SlippageStats) or a configured default
+
+CheckTarget rejects a strategy whose average profit target is not at least
+N times that cost, which catches scalping configs that can only lose.
+
+Usage:
+    stats := mt5.NewSlippageStats(200)
+    stats.Record("EURUSD", 0.8)                 // after each fill, in points
+
+    model := mt5.NewCostModel(service, stats)
+    model.CommissionPerLot["EURUSD"] = 7.0       // round trip, account currency
+    est, err := model.Estimate(ctx, "EURUSD", 0.1)
+    if err := mt5.CheckTarget(est, 80, 3); err != nil {
+        log.Fatal(err)                           // 80-point target < 3x cost
+    }
+*/
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrTargetBelowCost is returned by CheckTarget when a target does not cover costs.
+var ErrTargetBelowCost = errors.New("target below required cost multiple")
+
+// ══════════════════════════════════════════════════════════════════════════════
+// SLIPPAGE STATS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// SlippageStats keeps recent slippage samples per symbol. Safe for concurrent use.
+type SlippageStats struct {
+	mu         sync.RWMutex
+	maxSamples int
+	samples    map[string][]float64
+}
+
+// NewSlippageStats creates a recorder keeping at most maxSamples per symbol (default 200).
+func NewSlippageStats(maxSamples int) *SlippageStats {
+	if maxSamples <= 0 {
+		maxSamples = 200
+	}
+	return &SlippageStats{
+		maxSamples: maxSamples,
+		samples:    make(map[string][]float64),
+	}
+}
+
+// Record adds a slippage sample in points. Positive = worse than requested price.
+func (s *SlippageStats) Record(symbol string, points float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	samples := append(s.samples[symbol], points)
+	if len(samples) > s.maxSamples {
+		samples = samples[len(samples)-s.maxSamples:]
+	}
+	s.samples[symbol] = samples
+}
+
+// Average returns the mean slippage in points and the sample count.
+// Negative (favourable) slippage counts, so the mean reflects real fills.
+func (s *SlippageStats) Average(symbol string) (float64, int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	samples := s.samples[symbol]
+	if len(samples) == 0 {
+		return 0, 0
+	}
+	sum := 0.0
+	for _, v := range samples {
+		sum += v
+	}
+	return sum / float64(len(samples)), len(samples)
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// COST MODEL
+// ══════════════════════════════════════════════════════════════════════════════
+
+// CostEstimate is the expected round-trip cost of one trade.
+type CostEstimate struct {
+	Symbol          string
+	Volume          float64
+	SpreadPoints    float64 // Current spread
+	SlippagePoints  float64 // Expected slippage per side
+	SlippageSamples int     // Samples behind SlippagePoints (0 = default used)
+	PointValue      float64 // Value of 1 point for Volume, in account currency
+	SpreadCost      float64 // Spread in account currency
+	CommissionCost  float64 // Round-trip commission in account currency
+	SlippageCost    float64 // Entry + exit slippage in account currency
+	TotalCost       float64 // Spread + commission + slippage
+	TotalPoints     float64 // TotalCost expressed in points
+}
+
+// CostModel estimates execution cost from live symbol data and fill history.
+type CostModel struct {
+	service  *MT5Service
+	slippage *SlippageStats
+
+	Commissions             *CommissionModel   // Commission schemes (takes precedence over CommissionPerLot)
+	CommissionPerLot        map[string]float64 // Round-trip commission per 1.0 lot, per symbol
+	DefaultCommissionPerLot float64            // Used for symbols not in CommissionPerLot
+	DefaultSlippagePoints   float64            // Per-side slippage when no samples are recorded
+}
+
+// NewCostModel creates a cost model. slippage may be nil (defaults only).
+func NewCostModel(service *MT5Service, slippage *SlippageStats) *CostModel {
+	return &CostModel{
+		service:          service,
+		slippage:         slippage,
+		CommissionPerLot: make(map[string]float64),
+	}
+}
+
+// Estimate returns the round-trip cost of trading volume lots of symbol.
+//
+// Returns:
+//   - CostEstimate in account currency and points
+//   - Error if symbol data is unavailable
+func (m *CostModel) Estimate(ctx context.Context, symbol string, volume float64) (*CostEstimate, error) {
+	name := symbol
+	params, _, err := m.service.GetSymbolParamsMany(ctx, &name, nil, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cost estimate failed: %w", err)
+	}
+	if len(params) == 0 {
+		return nil, fmt.Errorf("symbol %s not found", symbol)
+	}
+	spec := params[0]
+	if spec.Point <= 0 || spec.TradeTickSize <= 0 {
+		return nil, fmt.Errorf("symbol %s has no point/tick size", symbol)
+	}
+
+	est := &CostEstimate{
+		Symbol:         symbol,
+		Volume:         volume,
+		SpreadPoints:   float64(spec.Spread),
+		SlippagePoints: m.DefaultSlippagePoints,
+		PointValue:     spec.TradeTickValue * spec.Point / spec.TradeTickSize * volume,
+	}
+	if est.SpreadPoints == 0 && spec.Ask > spec.Bid {
+		est.SpreadPoints = (spec.Ask - spec.Bid) / spec.Point
+	}
+	if m.slippage != nil {
+		if avg, n := m.slippage.Average(symbol); n > 0 {
+			est.SlippagePoints = avg
+			est.SlippageSamples = n
+		}
+	}
+
+	est.SpreadCost = est.SpreadPoints * est.PointValue
+	if m.Commissions != nil {
+		est.CommissionCost = m.Commissions.RoundTrip(symbol, volume, spec.Bid, spec.Bid, spec.TradeContractSize)
+	} else {
+		commission, ok := m.CommissionPerLot[symbol]
+		if !ok {
+			commission = m.DefaultCommissionPerLot
+		}
+		est.CommissionCost = commission * volume
+	}
+	est.SlippageCost = 2 * est.SlippagePoints * est.PointValue
+	est.TotalCost = est.SpreadCost + est.CommissionCost + est.SlippageCost
+	if est.PointValue > 0 {
+		est.TotalPoints = est.TotalCost / est.PointValue
+	}
+	return est, nil
+}
+
+// CheckTarget returns ErrTargetBelowCost if targetPoints (average profit
+// target of a strategy) is less than multiple times the estimated cost.
+func CheckTarget(est *CostEstimate, targetPoints, multiple float64) error {
+	if est == nil {
+		return fmt.Errorf("cost estimate is nil")
+	}
+	required := est.TotalPoints * multiple
+	if targetPoints < required {
+		return fmt.Errorf("%w: %s target %.1f points, cost %.1f points x %.1f = %.1f",
+			ErrTargetBelowCost, est.Symbol, targetPoints, est.TotalPoints, multiple, required)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package mt5
+
+/*
+Currency exposure - net open positions per currency and currency cluster.
+
+Every position is split into its two currency legs: long 1 lot EURUSD is
++1 lot EUR and -1 lot USD; short 0.5 lot USDJPY is -0.5 lot USD and +0.5 lot
+JPY. Legs of all symbols are netted, so long EURUSD + long USDCHF shows the
+USD legs cancelling and a EUR-long / CHF-short book remaining.
+
+Symbols whose base and profit currency are the same (most CFDs, e.g. US500
+in USD) have a single leg: the position value in the profit currency.
+
+  • NetLots  - lots of the symbols, signed by leg direction
+  • Amount   - net units of the currency (contract size × lots, quote legs × price)
+  • Notional - net value of the legs in account currency (tick value based,
+               like GetNetExposure)
+
+Clusters group correlated currencies ("USD bloc", "commodity FX") and sum
+their members' lots and notional; Amount is not summed across currencies.
+
+Usage:
+    exposure, err := sugar.GetCurrencyExposure()
+    for currency, e := range exposure {
+        fmt.Printf("%s %+.2f lots  %+.2f notional\n", currency, e.NetLots, e.Notional)
+    }
+
+    blocs := mt5.ClusterExposure(exposure,
+        mt5.ExposureCluster{Name: "USD bloc", Currencies: []string{"USD", "CAD", "HKD"}},
+        mt5.ExposureCluster{Name: "Commodity FX", Currencies: []string{"AUD", "NZD", "CAD", "NOK"}},
+    )
+*/
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// CurrencyExposure is the net exposure to one currency or cluster.
+type CurrencyExposure struct {
+	Currency string   // Currency code or cluster name
+	NetLots  float64  // Net lots (positive = long the currency)
+	Amount   float64  // Net units of the currency (0 for clusters)
+	Notional float64  // Net value in account currency (signed)
+	Symbols  []string // Symbols contributing, sorted
+}
+
+// addLeg adds one currency leg of a symbol.
+func (e *CurrencyExposure) addLeg(symbol string, lots, amount, notional float64) {
+	e.NetLots += lots
+	e.Amount += amount
+	e.Notional += notional
+	for _, s := range e.Symbols {
+		if s == symbol {
+			return
+		}
+	}
+	e.Symbols = append(e.Symbols, symbol)
+	sort.Strings(e.Symbols)
+}
+
+// ExposureCluster is a named group of correlated currencies.
+type ExposureCluster struct {
+	Name       string
+	Currencies []string
+}
+
+// SymbolCurrencies returns the base and profit (quote) currency of a symbol.
+// Uses 5-second timeout.
+//
+// Parameters:
+//   - symbol: Trading symbol (e.g., "EURUSD")
+//
+// Returns:
+//   - Base currency ("EUR"), profit currency ("USD"), or error if symbol
+//     info cannot be read
+func (s *MT5Sugar) SymbolCurrencies(symbol string) (string, string, error) {
+	symbol = s.ResolveSymbol(symbol)
+
+	ctx, cancel := context.WithTimeout(s.ctx, 5*time.Second)
+	defer cancel()
+
+	base, err := s.service.GetSymbolString(ctx, symbol, pb.SymbolInfoStringProperty_SYMBOL_CURRENCY_BASE)
+	if err != nil {
+		return "", "", fmt.Errorf("SymbolCurrencies failed: %w", err)
+	}
+	profit, err := s.service.GetSymbolString(ctx, symbol, pb.SymbolInfoStringProperty_SYMBOL_CURRENCY_PROFIT)
+	if err != nil {
+		return "", "", fmt.Errorf("SymbolCurrencies failed: %w", err)
+	}
+	return base, profit, nil
+}
+
+// GetCurrencyExposure nets the currency legs of all open positions.
+// Uses 10-second timeout.
+//
+// Returns:
+//   - Map currency -> *CurrencyExposure, or error if positions or symbol
+//     specs could not be read
+func (s *MT5Sugar) GetCurrencyExposure() (map[string]*CurrencyExposure, error) {
+	bySymbol, err := s.GetNetExposure()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+	defer cancel()
+
+	exposure := make(map[string]*CurrencyExposure)
+	leg := func(currency string) *CurrencyExposure {
+		e, ok := exposure[currency]
+		if !ok {
+			e = &CurrencyExposure{Currency: currency}
+			exposure[currency] = e
+		}
+		return e
+	}
+
+	for symbol, e := range bySymbol {
+		if e.NetLots == 0 {
+			continue
+		}
+		base, profit, err := s.SymbolCurrencies(symbol)
+		if err != nil {
+			return nil, fmt.Errorf("GetCurrencyExposure failed: %w", err)
+		}
+		name := symbol
+		params, _, err := s.service.GetSymbolParamsMany(ctx, &name, nil, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("GetCurrencyExposure failed: %w", err)
+		}
+		if len(params) == 0 {
+			continue
+		}
+		units := e.NetLots * params[0].TradeContractSize
+
+		if base == "" || base == profit {
+			leg(profit).addLeg(symbol, e.NetLots, units*params[0].Bid, e.NetNotional)
+			continue
+		}
+		leg(base).addLeg(symbol, e.NetLots, units, e.NetNotional)
+		leg(profit).addLeg(symbol, -e.NetLots, -units*params[0].Bid, -e.NetNotional)
+	}
+
+	return exposure, nil
+}
+
+// ClusterExposure sums currency exposure into clusters. A currency may belong
+// to several clusters; currencies outside every cluster are not reported.
+func ClusterExposure(exposure map[string]*CurrencyExposure, clusters ...ExposureCluster) map[string]*CurrencyExposure {
+	result := make(map[string]*CurrencyExposure, len(clusters))
+	for _, cluster := range clusters {
+		total := &CurrencyExposure{Currency: cluster.Name}
+		for _, currency := range cluster.Currencies {
+			e, ok := exposure[currency]
+			if !ok {
+				continue
+			}
+			total.NetLots += e.NetLots
+			total.Notional += e.Notional
+			for _, symbol := range e.Symbols {
+				total.addLeg(symbol, 0, 0, 0)
+			}
+		}
+		result[cluster.Name] = total
+	}
+	return result
+}
@@ -0,0 +1,479 @@
+package mt5
+
+/*
+Data integrity - gaps, duplicates and weekend anomalies in history data.
+
+Candles and ticks from external feeds, DownloadTicks re-runs or a recorder
+that lost its connection are rarely clean, and a backtest happily trades a
+duplicated bar or a weekend print that never existed. The verifiers check
+a series before it is consumed:
+
+  • Gap        - bars/ticks missing outside the weekend
+  • Duplicate  - the same bar open time or tick (time, bid, ask) twice
+  • OutOfOrder - timestamps going backwards
+  • Misaligned - bar open time not a multiple of the timeframe
+  • Invalid    - High < Low, Open/Close outside High..Low, zero prices,
+                 Ask < Bid
+  • Weekend    - data between Saturday 00:00 and Monday 00:00 in the data
+                 timezone (server time; set WeekendTrading for crypto)
+
+VerifyCandles / VerifyTicks only report. RepairCandles / RepairTicks sort,
+drop duplicates (last one wins), invalid and weekend records, align bar
+times, and optionally fill short candle gaps with flat bars; what they
+cannot repair (longer gaps) stays in the report. CandleCache.Verify/Repair
+and TickStore.Verify run the checks on stored data.
+
+Usage:
+    opts := mt5.IntegrityOptions{Location: serverLoc, GapTolerance: 5 * time.Minute}
+    report := mt5.VerifyCandles(bars, time.Minute, opts)
+    if !report.OK() {
+        fmt.Println(report)
+        bars, report = mt5.RepairCandles(bars, time.Minute, opts)
+    }
+
+    report, err := cache.Repair("EURUSD", time.Minute, opts) // rewrites the file
+*/
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DataIssueKind is the type of a data integrity issue.
+type DataIssueKind int
+
+const (
+	IssueGap DataIssueKind = iota
+	IssueDuplicate
+	IssueOutOfOrder
+	IssueMisaligned
+	IssueInvalid
+	IssueWeekend
+)
+
+func (k DataIssueKind) String() string {
+	switch k {
+	case IssueGap:
+		return "gap"
+	case IssueDuplicate:
+		return "duplicate"
+	case IssueOutOfOrder:
+		return "out of order"
+	case IssueMisaligned:
+		return "misaligned"
+	case IssueInvalid:
+		return "invalid"
+	case IssueWeekend:
+		return "weekend"
+	}
+	return fmt.Sprintf("DataIssueKind(%d)", int(k))
+}
+
+// DataIssue is one problem found in a series.
+type DataIssue struct {
+	Kind     DataIssueKind
+	Time     time.Time // Bar/tick time (gap: last time before the gap)
+	End      time.Time // Gap only: first time after the gap
+	Missing  int       // Gap only: expected bars missing (candles)
+	Repaired bool      // Fixed by a Repair function
+	Detail   string
+}
+
+func (i DataIssue) String() string {
+	s := fmt.Sprintf("%s %s", i.Time.Format("2006-01-02 15:04:05"), i.Kind)
+	if i.Kind == IssueGap {
+		s += fmt.Sprintf(" until %s", i.End.Format("2006-01-02 15:04:05"))
+	}
+	if i.Detail != "" {
+		s += ": " + i.Detail
+	}
+	if i.Repaired {
+		s += " (repaired)"
+	}
+	return s
+}
+
+// IntegrityOptions configures the checks.
+type IntegrityOptions struct {
+	Location       *time.Location // Timezone of the weekend (server time; nil = UTC)
+	WeekendTrading bool           // Weekend data is expected (crypto, some indices)
+	GapTolerance   time.Duration  // Candles: holes up to this long are not gaps (0 = any missing bar)
+	MaxTickGap     time.Duration  // Ticks: silence longer than this is a gap (default 1 hour)
+	FillGaps       bool           // RepairCandles: fill gaps up to MaxFill with flat bars
+	MaxFill        time.Duration  // Longest gap FillGaps fills (default 5 bars)
+}
+
+// weekend reports whether t falls on a Saturday or Sunday in the data timezone.
+func (o IntegrityOptions) weekend(t time.Time) bool {
+	if o.WeekendTrading {
+		return false
+	}
+	loc := o.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	day := t.In(loc).Weekday()
+	return day == time.Saturday || day == time.Sunday
+}
+
+// expectedBars counts bar open times in (prev, next) outside the weekend.
+func (o IntegrityOptions) expectedBars(prev, next time.Time, timeframe time.Duration) int {
+	n := 0
+	for t := prev.Add(timeframe); t.Before(next); t = t.Add(timeframe) {
+		if !o.weekend(t) {
+			n++
+		}
+	}
+	return n
+}
+
+// tradingTime returns the time in (prev, next) outside the weekend.
+func (o IntegrityOptions) tradingTime(prev, next time.Time) time.Duration {
+	if o.WeekendTrading {
+		return next.Sub(prev)
+	}
+	var total time.Duration
+	for t := prev; t.Before(next); {
+		step := next.Sub(t)
+		if step > time.Hour {
+			step = time.Hour
+		}
+		if !o.weekend(t) {
+			total += step
+		}
+		t = t.Add(step)
+	}
+	return total
+}
+
+// IntegrityReport lists the issues of one series.
+type IntegrityReport struct {
+	Symbol    string
+	Timeframe time.Duration // 0 for ticks
+	Checked   int           // Records checked
+	Issues    []DataIssue
+}
+
+// OK reports whether no unrepaired issue was found.
+func (r *IntegrityReport) OK() bool {
+	for _, issue := range r.Issues {
+		if !issue.Repaired {
+			return false
+		}
+	}
+	return true
+}
+
+// Count returns the number of issues of a kind.
+func (r *IntegrityReport) Count(kind DataIssueKind) int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *IntegrityReport) String() string {
+	label := "ticks"
+	if r.Timeframe > 0 {
+		label = TimeframeLabel(r.Timeframe)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s: %d records, %d issues", r.Symbol, label, r.Checked, len(r.Issues))
+	kinds := []DataIssueKind{IssueGap, IssueDuplicate, IssueOutOfOrder, IssueMisaligned, IssueInvalid, IssueWeekend}
+	var counts []string
+	for _, kind := range kinds {
+		if n := r.Count(kind); n > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", n, kind))
+		}
+	}
+	if len(counts) > 0 {
+		fmt.Fprintf(&b, " (%s)", strings.Join(counts, ", "))
+	}
+	for _, issue := range r.Issues {
+		b.WriteString("\n  " + issue.String())
+	}
+	return b.String()
+}
+
+// candleInvalid describes what is wrong with a bar's prices ("" = valid).
+func candleInvalid(c Candle) string {
+	switch {
+	case c.Open <= 0 || c.High <= 0 || c.Low <= 0 || c.Close <= 0:
+		return "non-positive price"
+	case c.High < c.Low:
+		return fmt.Sprintf("high %g < low %g", c.High, c.Low)
+	case c.Open > c.High || c.Open < c.Low:
+		return fmt.Sprintf("open %g outside %g..%g", c.Open, c.Low, c.High)
+	case c.Close > c.High || c.Close < c.Low:
+		return fmt.Sprintf("close %g outside %g..%g", c.Close, c.Low, c.High)
+	}
+	return ""
+}
+
+// VerifyCandles checks a candle series in the order given.
+//
+// Parameters:
+//   - candles: Bars of one symbol, expected oldest first
+//   - timeframe: Bar length (e.g., time.Minute)
+//   - opts: Weekend timezone and gap tolerance
+//
+// Returns:
+//   - Report of every issue found
+func VerifyCandles(candles []Candle, timeframe time.Duration, opts IntegrityOptions) *IntegrityReport {
+	report := &IntegrityReport{Timeframe: timeframe, Checked: len(candles)}
+	if len(candles) > 0 {
+		report.Symbol = candles[0].Symbol
+	}
+
+	seen := make(map[int64]bool, len(candles))
+	var last time.Time
+	for i, c := range candles {
+		if seen[c.Time.Unix()] {
+			report.Issues = append(report.Issues, DataIssue{Kind: IssueDuplicate, Time: c.Time})
+			continue
+		}
+		seen[c.Time.Unix()] = true
+
+		if timeframe > 0 && !c.Time.Truncate(timeframe).Equal(c.Time) {
+			report.Issues = append(report.Issues, DataIssue{Kind: IssueMisaligned, Time: c.Time,
+				Detail: fmt.Sprintf("not on a %s boundary", TimeframeLabel(timeframe))})
+		}
+		if reason := candleInvalid(c); reason != "" {
+			report.Issues = append(report.Issues, DataIssue{Kind: IssueInvalid, Time: c.Time, Detail: reason})
+		}
+		if opts.weekend(c.Time) {
+			report.Issues = append(report.Issues, DataIssue{Kind: IssueWeekend, Time: c.Time})
+		}
+
+		if i > 0 && c.Time.Before(last) {
+			report.Issues = append(report.Issues, DataIssue{Kind: IssueOutOfOrder, Time: c.Time,
+				Detail: fmt.Sprintf("after %s", last.Format("2006-01-02 15:04:05"))})
+			continue
+		}
+		if i > 0 && timeframe > 0 {
+			if missing := opts.expectedBars(last, c.Time, timeframe); missing > 0 && time.Duration(missing)*timeframe > opts.GapTolerance {
+				report.Issues = append(report.Issues, DataIssue{Kind: IssueGap, Time: last, End: c.Time, Missing: missing,
+					Detail: fmt.Sprintf("%d bars missing", missing)})
+			}
+		}
+		last = c.Time
+	}
+	return report
+}
+
+// RepairCandles returns a cleaned copy of a candle series: sorted, bar times
+// aligned to the timeframe, duplicates merged (last bar wins), invalid and
+// weekend bars dropped, and with FillGaps short gaps filled with flat bars
+// at the previous close. The input is not modified.
+//
+// Returns:
+//   - Repaired candles, oldest first
+//   - Report of the original series; issues fixed are marked Repaired
+func RepairCandles(candles []Candle, timeframe time.Duration, opts IntegrityOptions) ([]Candle, *IntegrityReport) {
+	report := VerifyCandles(candles, timeframe, opts)
+
+	byTime := make(map[int64]Candle, len(candles))
+	for _, c := range candles {
+		if timeframe > 0 {
+			c.Time = c.Time.Truncate(timeframe)
+		}
+		if candleInvalid(c) != "" || opts.weekend(c.Time) {
+			continue
+		}
+		byTime[c.Time.Unix()] = c
+	}
+	repaired := make([]Candle, 0, len(byTime))
+	for _, c := range byTime {
+		repaired = append(repaired, c)
+	}
+	sort.Slice(repaired, func(i, j int) bool { return repaired[i].Time.Before(repaired[j].Time) })
+
+	maxFill := opts.MaxFill
+	if maxFill <= 0 {
+		maxFill = 5 * timeframe
+	}
+	filled := make(map[int64]bool)
+	if opts.FillGaps && timeframe > 0 && len(repaired) > 1 {
+		result := make([]Candle, 0, len(repaired))
+		for i, c := range repaired {
+			if i > 0 {
+				prev := repaired[i-1]
+				if missing := opts.expectedBars(prev.Time, c.Time, timeframe); missing > 0 && time.Duration(missing)*timeframe <= maxFill {
+					for t := prev.Time.Add(timeframe); t.Before(c.Time); t = t.Add(timeframe) {
+						if !opts.weekend(t) {
+							result = append(result, Candle{Symbol: prev.Symbol, Time: t, Timeframe: prev.Timeframe,
+								Open: prev.Close, High: prev.Close, Low: prev.Close, Close: prev.Close})
+						}
+					}
+					filled[prev.Time.Unix()] = true
+				}
+			}
+			result = append(result, c)
+		}
+		repaired = result
+	}
+
+	for i := range report.Issues {
+		issue := &report.Issues[i]
+		issue.Repaired = issue.Kind != IssueGap || filled[issue.Time.Unix()]
+	}
+	return repaired, report
+}
+
+// tickKey identifies duplicate ticks.
+type tickKey struct {
+	ms       int64
+	bid, ask float64
+}
+
+// VerifyTicks checks a tick series in the order given.
+//
+// Parameters:
+//   - ticks: Ticks of one symbol, expected oldest first
+//   - opts: Weekend timezone and MaxTickGap
+//
+// Returns:
+//   - Report of every issue found
+func VerifyTicks(ticks []SymbolTick, opts IntegrityOptions) *IntegrityReport {
+	report := &IntegrityReport{Checked: len(ticks)}
+	if len(ticks) > 0 {
+		report.Symbol = ticks[0].Symbol
+	}
+	maxGap := opts.MaxTickGap
+	if maxGap <= 0 {
+		maxGap = time.Hour
+	}
+
+	seen := make(map[tickKey]bool, len(ticks))
+	var last time.Time
+	for i, tick := range ticks {
+		t := time.UnixMilli(tick.TimeMS).UTC()
+		key := tickKey{tick.TimeMS, tick.Bid, tick.Ask}
+		if seen[key] {
+			report.Issues = append(report.Issues, DataIssue{Kind: IssueDuplicate, Time: t})
+			continue
+		}
+		seen[key] = true
+
+		switch {
+		case tick.Bid <= 0 || tick.Ask <= 0:
+			report.Issues = append(report.Issues, DataIssue{Kind: IssueInvalid, Time: t, Detail: "non-positive price"})
+		case tick.Ask < tick.Bid:
+			report.Issues = append(report.Issues, DataIssue{Kind: IssueInvalid, Time: t,
+				Detail: fmt.Sprintf("ask %g < bid %g", tick.Ask, tick.Bid)})
+		}
+		if opts.weekend(t) {
+			report.Issues = append(report.Issues, DataIssue{Kind: IssueWeekend, Time: t})
+		}
+
+		if i > 0 && t.Before(last) {
+			report.Issues = append(report.Issues, DataIssue{Kind: IssueOutOfOrder, Time: t,
+				Detail: fmt.Sprintf("after %s", last.Format("2006-01-02 15:04:05.000"))})
+			continue
+		}
+		if i > 0 {
+			if silent := opts.tradingTime(last, t); silent > maxGap {
+				report.Issues = append(report.Issues, DataIssue{Kind: IssueGap, Time: last, End: t,
+					Detail: fmt.Sprintf("no ticks for %s", silent.Round(time.Second))})
+			}
+		}
+		last = t
+	}
+	return report
+}
+
+// RepairTicks returns a cleaned copy of a tick series: sorted by time,
+// duplicates, invalid and weekend ticks dropped. Gaps cannot be repaired and
+// stay unrepaired in the report. The input is not modified.
+//
+// Returns:
+//   - Repaired ticks, oldest first
+//   - Report of the original series; issues fixed are marked Repaired
+func RepairTicks(ticks []SymbolTick, opts IntegrityOptions) ([]SymbolTick, *IntegrityReport) {
+	report := VerifyTicks(ticks, opts)
+
+	seen := make(map[tickKey]bool, len(ticks))
+	repaired := make([]SymbolTick, 0, len(ticks))
+	for _, tick := range ticks {
+		key := tickKey{tick.TimeMS, tick.Bid, tick.Ask}
+		if seen[key] || tick.Bid <= 0 || tick.Ask <= 0 || tick.Ask < tick.Bid {
+			continue
+		}
+		if opts.weekend(time.UnixMilli(tick.TimeMS)) {
+			continue
+		}
+		seen[key] = true
+		repaired = append(repaired, tick)
+	}
+	sort.SliceStable(repaired, func(i, j int) bool { return repaired[i].TimeMS < repaired[j].TimeMS })
+
+	for i := range report.Issues {
+		if report.Issues[i].Kind != IssueGap {
+			report.Issues[i].Repaired = true
+		}
+	}
+	return repaired, report
+}
+
+// Verify checks the cached candles of a symbol/timeframe.
+//
+// Returns:
+//   - Report, or error if the cache file cannot be read
+func (c *CandleCache) Verify(symbol string, timeframe time.Duration, opts IntegrityOptions) (*IntegrityReport, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	candles, err := c.loadLocked(symbol, timeframe)
+	if err != nil {
+		return nil, err
+	}
+	report := VerifyCandles(candles, timeframe, opts)
+	report.Symbol = symbol
+	return report, nil
+}
+
+// Repair runs RepairCandles on the cached candles of a symbol/timeframe and
+// rewrites the file if anything changed.
+//
+// Returns:
+//   - Report of the cached series before repair, or error if the cache
+//     file cannot be read or written
+func (c *CandleCache) Repair(symbol string, timeframe time.Duration, opts IntegrityOptions) (*IntegrityReport, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	candles, err := c.loadLocked(symbol, timeframe)
+	if err != nil {
+		return nil, err
+	}
+	repaired, report := RepairCandles(candles, timeframe, opts)
+	report.Symbol = symbol
+
+	changed := len(repaired) != len(candles)
+	for _, issue := range report.Issues {
+		changed = changed || issue.Repaired
+	}
+	if changed {
+		if err := c.writeLocked(symbol, timeframe, repaired); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// Verify checks the stored ticks of [from, to).
+//
+// Returns:
+//   - Report, or error if the tick files cannot be read
+func (s *TickStore) Verify(symbol string, from, to time.Time, opts IntegrityOptions) (*IntegrityReport, error) {
+	ticks, err := s.Load(symbol, from, to)
+	if err != nil {
+		return nil, err
+	}
+	report := VerifyTicks(ticks, opts)
+	report.Symbol = symbol
+	return report, nil
+}
@@ -0,0 +1,337 @@
+package mt5
+
+/*
+EquityTracker - equity curve recording with drawdown and recovery analytics.
+
+Records account equity over time (and, optionally, cumulative P/L per
+strategy) and answers:
+  • Underwater curve   - distance below the running peak at every sample
+  • Max / current DD   - deepest and current drawdown, absolute and %
+  • Longest drawdown   - longest time spent below a previous peak
+  • Time to recovery   - how long it took from trough back to the old peak
+  • Attribution        - how much each strategy lost during the max drawdown
+
+All methods are safe for concurrent use, so the tracker can be queried
+while Run is sampling.
+
+Usage:
+    tracker := mt5.NewEquityTracker(10000)
+    go tracker.Run(ctx, service, 5*time.Second)
+
+    tracker.RecordStrategy("Grid", time.Now(), gridPnL)  // optional
+    report := tracker.Report()
+    fmt.Println(report)
+*/
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// EquityPoint is one sample of an equity (or strategy P/L) curve.
+type EquityPoint struct {
+	Time   time.Time
+	Equity float64
+}
+
+// UnderwaterPoint is the distance below the running peak at one sample.
+type UnderwaterPoint struct {
+	Time            time.Time
+	Drawdown        float64 // Peak - equity (0 at new highs)
+	DrawdownPercent float64 // Drawdown as % of peak
+}
+
+// DrawdownPeriod describes one peak-to-recovery episode.
+type DrawdownPeriod struct {
+	Start        time.Time     // Time of the peak the drawdown started from
+	Trough       time.Time     // Time of the lowest equity
+	End          time.Time     // Time equity regained the peak (zero if not recovered)
+	Peak         float64       // Equity at Start
+	TroughEquity float64       // Equity at Trough
+	Depth        float64       // Peak - TroughEquity
+	DepthPercent float64       // Depth as % of Peak
+	Duration     time.Duration // Start to End (or to last sample if not recovered)
+	Recovery     time.Duration // Trough to End (0 if not recovered)
+	Recovered    bool          // True if equity regained the peak
+}
+
+// DrawdownStats summarizes the drawdowns of one curve.
+type DrawdownStats struct {
+	MaxDrawdown            float64       // Deepest drawdown (absolute)
+	MaxDrawdownPercent     float64       // Deepest drawdown (% of peak)
+	CurrentDrawdown        float64       // Drawdown at the last sample
+	CurrentDrawdownPercent float64       // Current drawdown (% of peak)
+	LongestDrawdown        time.Duration // Longest time below a previous peak
+	AvgRecovery            time.Duration // Average trough-to-recovery time of recovered periods
+	MaxRecovery            time.Duration // Longest trough-to-recovery time
+}
+
+// DrawdownReport is the full drawdown analysis of the tracked account.
+type DrawdownReport struct {
+	DrawdownStats
+	Samples     int                      // Equity samples analyzed
+	Periods     []DrawdownPeriod         // All drawdown episodes, oldest first
+	Attribution map[string]float64       // Strategy P/L change during the max drawdown
+	Strategies  map[string]DrawdownStats // Drawdown stats of each strategy's own P/L curve
+}
+
+// EquityTracker records equity samples and computes drawdown analytics.
+type EquityTracker struct {
+	mu         sync.RWMutex
+	maxSamples int
+	equity     []EquityPoint
+	strategies map[string][]EquityPoint
+}
+
+// NewEquityTracker creates a tracker keeping at most maxSamples points per curve
+// (default 100000).
+func NewEquityTracker(maxSamples int) *EquityTracker {
+	if maxSamples <= 0 {
+		maxSamples = 100000
+	}
+	return &EquityTracker{
+		maxSamples: maxSamples,
+		strategies: make(map[string][]EquityPoint),
+	}
+}
+
+// Record adds an account equity sample.
+func (t *EquityTracker) Record(at time.Time, equity float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.equity = appendPoint(t.equity, EquityPoint{Time: at, Equity: equity}, t.maxSamples)
+}
+
+// RecordStrategy adds a sample of a strategy's cumulative P/L (realized + floating).
+func (t *EquityTracker) RecordStrategy(strategy string, at time.Time, cumulativePnL float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.strategies[strategy] = appendPoint(t.strategies[strategy], EquityPoint{Time: at, Equity: cumulativePnL}, t.maxSamples)
+}
+
+// appendPoint appends a sample and trims the curve to max points.
+func appendPoint(curve []EquityPoint, p EquityPoint, max int) []EquityPoint {
+	curve = append(curve, p)
+	if len(curve) > max {
+		curve = curve[len(curve)-max:]
+	}
+	return curve
+}
+
+// Run samples account equity every interval until ctx is cancelled.
+//
+// Returns:
+//   - ctx.Err() when cancelled
+func (t *EquityTracker) Run(ctx context.Context, service *MT5Service, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		equity, err := service.GetAccountDouble(ctx, pb.AccountInfoDoublePropertyType_ACCOUNT_EQUITY)
+		if err == nil {
+			t.Record(time.Now(), equity)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Curve returns a copy of the recorded equity curve.
+func (t *EquityTracker) Curve() []EquityPoint {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return append([]EquityPoint(nil), t.equity...)
+}
+
+// Underwater returns the underwater curve (drawdown at every sample).
+func (t *EquityTracker) Underwater() []UnderwaterPoint {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	out := make([]UnderwaterPoint, 0, len(t.equity))
+	peak := 0.0
+	for i, p := range t.equity {
+		if i == 0 || p.Equity > peak {
+			peak = p.Equity
+		}
+		u := UnderwaterPoint{Time: p.Time, Drawdown: peak - p.Equity}
+		if peak > 0 {
+			u.DrawdownPercent = u.Drawdown / peak * 100
+		}
+		out = append(out, u)
+	}
+	return out
+}
+
+// Stats returns drawdown statistics of the account equity curve.
+func (t *EquityTracker) Stats() DrawdownStats {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	stats, _ := analyzeDrawdowns(t.equity)
+	return stats
+}
+
+// Report returns the full drawdown analysis including per-strategy attribution.
+func (t *EquityTracker) Report() *DrawdownReport {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	stats, periods := analyzeDrawdowns(t.equity)
+	report := &DrawdownReport{
+		DrawdownStats: stats,
+		Samples:       len(t.equity),
+		Periods:       periods,
+		Attribution:   make(map[string]float64),
+		Strategies:    make(map[string]DrawdownStats),
+	}
+
+	// Find the deepest period for attribution
+	var worst *DrawdownPeriod
+	for i := range periods {
+		if worst == nil || periods[i].Depth > worst.Depth {
+			worst = &periods[i]
+		}
+	}
+
+	for name, curve := range t.strategies {
+		report.Strategies[name], _ = analyzeDrawdowns(curve)
+		if worst != nil {
+			report.Attribution[name] = valueAt(curve, worst.Trough) - valueAt(curve, worst.Start)
+		}
+	}
+
+	return report
+}
+
+// String renders the report as human-readable text.
+func (r *DrawdownReport) String() string {
+	out := fmt.Sprintf("Drawdown report (%d samples, %d periods)\n", r.Samples, len(r.Periods))
+	out += fmt.Sprintf("  Max drawdown:      %.2f (%.2f%%)\n", r.MaxDrawdown, r.MaxDrawdownPercent)
+	out += fmt.Sprintf("  Current drawdown:  %.2f (%.2f%%)\n", r.CurrentDrawdown, r.CurrentDrawdownPercent)
+	out += fmt.Sprintf("  Longest drawdown:  %v\n", r.LongestDrawdown)
+	out += fmt.Sprintf("  Avg recovery:      %v\n", r.AvgRecovery)
+	out += fmt.Sprintf("  Max recovery:      %v\n", r.MaxRecovery)
+
+	if len(r.Attribution) > 0 {
+		names := make([]string, 0, len(r.Attribution))
+		for name := range r.Attribution {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		out += "  Max drawdown attribution:\n"
+		for _, name := range names {
+			s := r.Strategies[name]
+			out += fmt.Sprintf("    %-20s %10.2f   (own max DD %.2f, longest %v)\n",
+				name, r.Attribution[name], s.MaxDrawdown, s.LongestDrawdown)
+		}
+	}
+	return out
+}
+
+// analyzeDrawdowns walks a curve and extracts drawdown periods and stats.
+func analyzeDrawdowns(curve []EquityPoint) (DrawdownStats, []DrawdownPeriod) {
+	var stats DrawdownStats
+	var periods []DrawdownPeriod
+	if len(curve) == 0 {
+		return stats, nil
+	}
+
+	peak := curve[0]
+	var current *DrawdownPeriod
+
+	for _, p := range curve[1:] {
+		if p.Equity >= peak.Equity {
+			if current != nil {
+				current.End = p.Time
+				current.Recovered = true
+				current.Duration = current.End.Sub(current.Start)
+				current.Recovery = current.End.Sub(current.Trough)
+				periods = append(periods, *current)
+				current = nil
+			}
+			peak = p
+			continue
+		}
+
+		if current == nil {
+			current = &DrawdownPeriod{
+				Start:        peak.Time,
+				Peak:         peak.Equity,
+				Trough:       p.Time,
+				TroughEquity: p.Equity,
+			}
+		}
+		if p.Equity < current.TroughEquity {
+			current.Trough = p.Time
+			current.TroughEquity = p.Equity
+		}
+	}
+
+	last := curve[len(curve)-1]
+	if current != nil {
+		current.Duration = last.Time.Sub(current.Start)
+		periods = append(periods, *current)
+	}
+
+	var recoveredCount int
+	var recoveryTotal time.Duration
+	for i := range periods {
+		p := &periods[i]
+		p.Depth = p.Peak - p.TroughEquity
+		if p.Peak > 0 {
+			p.DepthPercent = p.Depth / p.Peak * 100
+		}
+
+		if p.Depth > stats.MaxDrawdown {
+			stats.MaxDrawdown = p.Depth
+		}
+		if p.DepthPercent > stats.MaxDrawdownPercent {
+			stats.MaxDrawdownPercent = p.DepthPercent
+		}
+		if p.Duration > stats.LongestDrawdown {
+			stats.LongestDrawdown = p.Duration
+		}
+		if p.Recovered {
+			recoveredCount++
+			recoveryTotal += p.Recovery
+			if p.Recovery > stats.MaxRecovery {
+				stats.MaxRecovery = p.Recovery
+			}
+		}
+	}
+	if recoveredCount > 0 {
+		stats.AvgRecovery = recoveryTotal / time.Duration(recoveredCount)
+	}
+
+	stats.CurrentDrawdown = peak.Equity - last.Equity
+	if stats.CurrentDrawdown < 0 {
+		stats.CurrentDrawdown = 0
+	}
+	if peak.Equity > 0 {
+		stats.CurrentDrawdownPercent = stats.CurrentDrawdown / peak.Equity * 100
+	}
+
+	return stats, periods
+}
+
+// valueAt returns the last curve value at or before t (first value if t is earlier).
+func valueAt(curve []EquityPoint, t time.Time) float64 {
+	if len(curve) == 0 {
+		return 0
+	}
+	i := sort.Search(len(curve), func(i int) bool { return curve[i].Time.After(t) })
+	if i == 0 {
+		return curve[0].Equity
+	}
+	return curve[i-1].Equity
+}
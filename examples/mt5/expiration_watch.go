@@ -0,0 +1,315 @@
+package mt5
+
+/*
+Expiration watcher - pending orders nearing their expiration.
+
+GTD grids (ORDER_TIME_DAY, SPECIFIED, SPECIFIED_DAY) lose their levels when
+the server removes expired orders, usually at day end when nobody watches.
+ExpirationWatcher checks the pending orders on a timer and, per policy:
+
+  • ExpirationWarning  - the order expires within Warn
+  • ExpirationExtended - the order expires within ExtendBefore and was moved
+                         Extend later (OrderModify, type SPECIFIED)
+  • ExpirationExtendFailed - the extension was rejected (see Err)
+  • ExpirationExpired  - the order disappeared after its expiration time
+
+DAY orders have no expiration time in the order; they are taken to expire
+at the next midnight in the server timezone (SetServerTimezone). GTC orders
+are ignored. Each order is warned once per expiration time; an extended
+order is warned again before its new expiration.
+
+Usage:
+    watcher := mt5.NewExpirationWatcher(sugar, mt5.ExpirationPolicy{
+        Warn:          30 * time.Minute,
+        Extend:        24 * time.Hour, // keep grid levels alive
+        ExtendBefore:  5 * time.Minute,
+        MaxExtensions: 5,
+    })
+    events, errs := watcher.Run(ctx, time.Minute)
+    for ev := range events {
+        fmt.Printf("%s #%d %s expires %s\n", ev.Kind, ev.Ticket, ev.Symbol, ev.Expiration)
+    }
+*/
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ExpirationEventKind is the type of an expiration event.
+type ExpirationEventKind int
+
+const (
+	ExpirationWarning ExpirationEventKind = iota
+	ExpirationExtended
+	ExpirationExtendFailed
+	ExpirationExpired
+)
+
+func (k ExpirationEventKind) String() string {
+	switch k {
+	case ExpirationWarning:
+		return "expiring"
+	case ExpirationExtended:
+		return "extended"
+	case ExpirationExtendFailed:
+		return "extend failed"
+	case ExpirationExpired:
+		return "expired"
+	}
+	return fmt.Sprintf("ExpirationEventKind(%d)", int(k))
+}
+
+// ExpirationPolicy decides when to warn about and extend expiring orders.
+// Zero values disable a rule.
+type ExpirationPolicy struct {
+	Warn          time.Duration // Emit ExpirationWarning when this much time is left
+	Extend        time.Duration // Move the expiration this much later (0 = never extend)
+	ExtendBefore  time.Duration // Extend when this much time is left (0 = Warn, or 1 minute)
+	MaxExtensions int           // Extensions per order (0 = unlimited)
+	Magic         int64         // Only watch orders with this magic (0 = all orders)
+}
+
+// ExpirationEvent reports one pending order nearing or reaching expiration.
+type ExpirationEvent struct {
+	Kind          ExpirationEventKind
+	Ticket        uint64
+	Symbol        string
+	Expiration    time.Time     // Expiration before this event
+	NewExpiration time.Time     // Expiration after an extension (ExpirationExtended only)
+	Left          time.Duration // Time left until Expiration when the event was raised
+	Extensions    int           // Extensions made so far
+	Order         *pb.OpenedOrderInfo
+	Err           error // Why the extension failed (ExpirationExtendFailed only)
+}
+
+// expiringOrder is what the watcher remembers about an order.
+type expiringOrder struct {
+	order      *pb.OpenedOrderInfo
+	expiration time.Time
+	warned     time.Time // Expiration the last warning was for
+	extensions int
+}
+
+// ExpirationWatcher tracks expiring pending orders. Safe for concurrent use.
+type ExpirationWatcher struct {
+	sugar *MT5Sugar
+
+	mu      sync.Mutex
+	policy  ExpirationPolicy
+	symbols map[string]ExpirationPolicy // Per-symbol overrides
+	tracked map[uint64]*expiringOrder
+}
+
+// NewExpirationWatcher creates a watcher applying policy to all symbols
+// without their own policy.
+func NewExpirationWatcher(sugar *MT5Sugar, policy ExpirationPolicy) *ExpirationWatcher {
+	return &ExpirationWatcher{
+		sugar:   sugar,
+		policy:  policy,
+		symbols: make(map[string]ExpirationPolicy),
+		tracked: make(map[uint64]*expiringOrder),
+	}
+}
+
+// SetSymbolPolicy overrides the policy for one symbol.
+func (w *ExpirationWatcher) SetSymbolPolicy(symbol string, policy ExpirationPolicy) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.symbols[w.sugar.ResolveSymbol(symbol)] = policy
+}
+
+// policyLocked returns the policy of a symbol. Caller holds w.mu.
+func (w *ExpirationWatcher) policyLocked(symbol string) ExpirationPolicy {
+	policy, ok := w.symbols[symbol]
+	if !ok {
+		policy = w.policy
+	}
+	if policy.ExtendBefore <= 0 {
+		policy.ExtendBefore = policy.Warn
+		if policy.ExtendBefore <= 0 {
+			policy.ExtendBefore = time.Minute
+		}
+	}
+	return policy
+}
+
+// orderExpiration returns when a pending order expires; ok is false for GTC.
+func (w *ExpirationWatcher) orderExpiration(order *pb.OpenedOrderInfo, now time.Time) (time.Time, bool) {
+	switch order.TypeTime {
+	case pb.BMT5_ENUM_ORDER_TYPE_TIME_BMT5_ORDER_TIME_SPECIFIED, pb.BMT5_ENUM_ORDER_TYPE_TIME_BMT5_ORDER_TIME_SPECIFIED_DAY:
+		if order.TimeExpiration == nil || order.TimeExpiration.AsTime().IsZero() {
+			return time.Time{}, false
+		}
+		return order.TimeExpiration.AsTime(), true
+	case pb.BMT5_ENUM_ORDER_TYPE_TIME_BMT5_ORDER_TIME_DAY:
+		server := now.In(w.sugar.serverLoc)
+		return time.Date(server.Year(), server.Month(), server.Day()+1, 0, 0, 0, 0, w.sugar.serverLoc), true
+	}
+	return time.Time{}, false
+}
+
+// extend moves the expiration of an order to at (type SPECIFIED).
+func (w *ExpirationWatcher) extend(order *pb.OpenedOrderInfo, at time.Time) error {
+	ctx, cancel := context.WithTimeout(w.sugar.ctx, 10*time.Second)
+	defer cancel()
+
+	timeType := pb.TMT5_ENUM_ORDER_TYPE_TIME_TMT5_ORDER_TIME_SPECIFIED
+	price, sl, tp, stopLimit := order.PriceOpen, order.StopLoss, order.TakeProfit, order.StopLimit
+	req := &pb.OrderModifyRequest{
+		Ticket:             order.Ticket,
+		Price:              &price,
+		StopLoss:           &sl,
+		TakeProfit:         &tp,
+		ExpirationTimeType: &timeType,
+		ExpirationTime:     timestamppb.New(at),
+	}
+	if stopLimit > 0 {
+		req.StopLimit = &stopLimit
+	}
+
+	result, err := w.sugar.service.ModifyOrder(ctx, req)
+	if err != nil {
+		return err
+	}
+	if err := result.Err(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Check runs one pass over the pending orders. Uses 10-second timeout for
+// reading orders and per extension.
+//
+// Returns:
+//   - Events raised by this pass, or error if orders could not be read
+func (w *ExpirationWatcher) Check() ([]ExpirationEvent, error) {
+	ctx, cancel := context.WithTimeout(w.sugar.ctx, 10*time.Second)
+	data, err := w.sugar.service.GetOpenedOrders(ctx, pb.BMT5_ENUM_OPENED_ORDER_SORT_TYPE_BMT5_OPENED_ORDER_SORT_BY_OPEN_TIME_ASC)
+	cancel()
+	if err != nil {
+		return nil, fmt.Errorf("expiration check failed: %w", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	var events []ExpirationEvent
+	open := make(map[uint64]bool, len(data.OpenedOrders))
+
+	for _, order := range data.OpenedOrders {
+		policy := w.policyLocked(order.Symbol)
+		if policy.Magic != 0 && order.MagicNumber != policy.Magic {
+			continue
+		}
+		expiration, ok := w.orderExpiration(order, now)
+		if !ok {
+			continue
+		}
+		open[order.Ticket] = true
+
+		t, ok := w.tracked[order.Ticket]
+		if !ok {
+			t = &expiringOrder{}
+			w.tracked[order.Ticket] = t
+		}
+		t.order, t.expiration = order, expiration
+		left := expiration.Sub(now)
+		event := ExpirationEvent{
+			Ticket: order.Ticket, Symbol: order.Symbol, Expiration: expiration,
+			Left: left, Extensions: t.extensions, Order: order,
+		}
+
+		canExtend := policy.Extend > 0 && (policy.MaxExtensions == 0 || t.extensions < policy.MaxExtensions)
+		if canExtend && left <= policy.ExtendBefore {
+			target := expiration.Add(policy.Extend)
+			if err := w.extend(order, target); err != nil {
+				event.Kind, event.Err = ExpirationExtendFailed, err
+			} else {
+				t.extensions++
+				t.expiration = target
+				event.Kind, event.NewExpiration, event.Extensions = ExpirationExtended, target, t.extensions
+			}
+			events = append(events, event)
+			continue
+		}
+
+		if policy.Warn > 0 && left <= policy.Warn && !t.warned.Equal(expiration) {
+			t.warned = expiration
+			event.Kind = ExpirationWarning
+			events = append(events, event)
+		}
+	}
+
+	for ticket, t := range w.tracked {
+		if open[ticket] {
+			continue
+		}
+		if !now.Before(t.expiration.Add(-time.Minute)) {
+			events = append(events, ExpirationEvent{
+				Kind: ExpirationExpired, Ticket: ticket, Symbol: t.order.Symbol,
+				Expiration: t.expiration, Left: t.expiration.Sub(now), Extensions: t.extensions, Order: t.order,
+			})
+		}
+		delete(w.tracked, ticket)
+	}
+
+	return events, nil
+}
+
+// Run checks the pending orders every interval until ctx is done.
+//
+// Parameters:
+//   - ctx: Context for cancellation (closing ctx stops the watcher)
+//   - interval: Time between checks (default 1 minute); keep it well below
+//     ExtendBefore so extensions happen in time
+//
+// Returns:
+//   - Read-only channel of ExpirationEvent
+//   - Read-only channel of errors (the watcher continues after errors)
+func (w *ExpirationWatcher) Run(ctx context.Context, interval time.Duration) (<-chan ExpirationEvent, <-chan error) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	eventCh := make(chan ExpirationEvent, 32)
+	errCh := make(chan error, 4)
+
+	go func() {
+		defer close(eventCh)
+		defer close(errCh)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			events, err := w.Check()
+			if err != nil && ctx.Err() == nil {
+				select {
+				case errCh <- err:
+				default:
+				}
+			}
+			for _, ev := range events {
+				select {
+				case eventCh <- ev:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return eventCh, errCh
+}
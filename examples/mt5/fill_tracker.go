@@ -0,0 +1,260 @@
+package mt5
+
+/*
+FillTracker - follows a partially filled order until its volume is complete.
+
+When OrderSend returns TRADE_RETCODE_DONE_PARTIAL (10010), only part of the
+requested volume was executed. FillTracker listens to trade transactions for
+further deals on the same order and, once the timeout expires, either
+re-submits the remainder as a new order or cancels what is left.
+
+Usage:
+    tracker := mt5.NewFillTracker(service, mt5.FillTrackerOptions{
+        Timeout:           5 * time.Second,
+        ResubmitRemainder: true,
+    })
+    summary, err := tracker.SendAndTrack(ctx, req)
+*/
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+	helpers "github.com/MetaRPC/GoMT5/package/Helpers"
+)
+
+// volumeEpsilon absorbs float rounding when comparing lot volumes.
+const volumeEpsilon = 1e-8
+
+// FillTrackerOptions controls what happens to the unfilled remainder.
+type FillTrackerOptions struct {
+	Timeout           time.Duration // How long to wait for further fills (default 5s)
+	ResubmitRemainder bool          // Send remaining volume as a new order after timeout
+	CancelOnTimeout   bool          // Remove the pending remainder after timeout
+}
+
+// FillSummary holds the consolidated result of an order and all its fills.
+type FillSummary struct {
+	Order           uint64        // Original order ticket
+	Symbol          string        // Trading symbol
+	RequestedVolume float64       // Volume originally requested
+	FilledVolume    float64       // Total volume executed across all deals
+	RemainingVolume float64       // Volume left unfilled
+	AvgPrice        float64       // Volume-weighted average fill price
+	Deals           []uint64      // Deal tickets that contributed to the fill
+	ResubmitOrders  []uint64      // Order tickets created for the remainder
+	Completed       bool          // True if the full volume was filled
+	Cancelled       bool          // True if the remainder was cancelled
+	Duration        time.Duration // Time from send to final state
+}
+
+// FillTracker monitors partial fills via trade transactions.
+type FillTracker struct {
+	service *MT5Service
+	opts    FillTrackerOptions
+}
+
+// NewFillTracker creates a FillTracker on top of MT5Service.
+func NewFillTracker(service *MT5Service, opts FillTrackerOptions) *FillTracker {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	return &FillTracker{
+		service: service,
+		opts:    opts,
+	}
+}
+
+// SendAndTrack sends an order and tracks it until fully filled or timed out.
+//
+// The transaction stream is opened before the order is sent, so no deal
+// belonging to the order can be missed.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - req: Order description
+//
+// Returns:
+//   - FillSummary with consolidated fill information
+//   - Error if the order could not be sent
+func (t *FillTracker) SendAndTrack(ctx context.Context, req OrderRequest) (*FillSummary, error) {
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	txCh, errCh := t.service.StreamTransactions(streamCtx)
+
+	started := time.Now()
+	result, err := t.service.SendOrder(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("SendAndTrack failed: %w", err)
+	}
+
+	return t.track(ctx, req, result, started, txCh, errCh)
+}
+
+// Track follows an order that was already sent.
+// Deals that happened before Track was called are only counted if they are
+// part of result; prefer SendAndTrack for new orders.
+func (t *FillTracker) Track(ctx context.Context, req OrderRequest, result *OrderResult) (*FillSummary, error) {
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	txCh, errCh := t.service.StreamTransactions(streamCtx)
+	return t.track(ctx, req, result, time.Now(), txCh, errCh)
+}
+
+// track consumes transactions until the order is complete or the timeout hits.
+func (t *FillTracker) track(ctx context.Context, req OrderRequest, result *OrderResult, started time.Time,
+	txCh <-chan *pb.OnTradeTransactionData, errCh <-chan error) (*FillSummary, error) {
+
+	summary := &FillSummary{
+		Order:           result.Order,
+		Symbol:          req.Symbol,
+		RequestedVolume: req.Volume,
+	}
+
+	if result.ReturnedCode != helpers.TradeRetCodeDone && result.ReturnedCode != helpers.TradeRetCodeDonePartial {
+		summary.RemainingVolume = req.Volume
+		summary.Duration = time.Since(started)
+		return summary, &TradeRejectedError{Result: result.SendResult}
+	}
+
+	seenDeals := make(map[uint64]bool)
+	if result.Deal != 0 {
+		seenDeals[result.Deal] = true
+		summary.Deals = append(summary.Deals, result.Deal)
+	}
+	t.addFill(summary, result.Volume, result.Price)
+
+	if summary.RemainingVolume <= volumeEpsilon {
+		summary.Completed = true
+		summary.Duration = time.Since(started)
+		return summary, nil
+	}
+
+	timer := time.NewTimer(t.opts.Timeout)
+	defer timer.Stop()
+
+	for summary.RemainingVolume > volumeEpsilon {
+		select {
+		case <-ctx.Done():
+			summary.Duration = time.Since(started)
+			return summary, ctx.Err()
+
+		case err, ok := <-errCh:
+			if ok && err != nil {
+				summary.Duration = time.Since(started)
+				return summary, fmt.Errorf("transaction stream failed: %w", err)
+			}
+			errCh = nil
+
+		case data, ok := <-txCh:
+			if !ok {
+				txCh = nil
+				continue
+			}
+			tx := data.GetTradeTransaction()
+			if tx == nil || tx.Type != pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_DEAL_ADD {
+				continue
+			}
+			if tx.OrderTicket != summary.Order || seenDeals[tx.DealTicket] {
+				continue
+			}
+			seenDeals[tx.DealTicket] = true
+			summary.Deals = append(summary.Deals, tx.DealTicket)
+			t.addFill(summary, tx.Volume, tx.Price)
+
+		case <-timer.C:
+			t.handleRemainder(ctx, req, summary)
+			summary.Duration = time.Since(started)
+			return summary, nil
+		}
+	}
+
+	summary.Completed = true
+	summary.Duration = time.Since(started)
+	return summary, nil
+}
+
+// addFill folds a fill into the volume-weighted average price.
+func (t *FillTracker) addFill(summary *FillSummary, volume, price float64) {
+	if volume <= 0 {
+		summary.RemainingVolume = summary.RequestedVolume - summary.FilledVolume
+		return
+	}
+
+	total := summary.FilledVolume + volume
+	summary.AvgPrice = (summary.AvgPrice*summary.FilledVolume + price*volume) / total
+	summary.FilledVolume = total
+	summary.RemainingVolume = summary.RequestedVolume - summary.FilledVolume
+}
+
+// handleRemainder re-submits or cancels the unfilled volume after timeout.
+func (t *FillTracker) handleRemainder(ctx context.Context, req OrderRequest, summary *FillSummary) {
+	if !t.opts.CancelOnTimeout && !t.opts.ResubmitRemainder {
+		return
+	}
+
+	// With RETURN filling the remainder stays on the book under the same
+	// ticket as the filled position, so the pending list is what counts.
+	// It must be gone before anything is re-submitted, or both could fill.
+	pending, err := t.remainderPending(ctx, summary.Order)
+	if err != nil {
+		return
+	}
+	if pending && !t.removeRemainder(ctx, summary.Order) {
+		return // Remainder may still fill; re-submitting could double the volume
+	}
+	// Removed above, or already dropped by the server (IOC/FOK filling)
+	summary.Cancelled = true
+
+	if !t.opts.ResubmitRemainder {
+		return
+	}
+
+	remainder := req
+	remainder.Volume = summary.RemainingVolume
+	result, err := t.service.SendOrder(ctx, remainder)
+	if err != nil || (result.ReturnedCode != helpers.TradeRetCodeDone && result.ReturnedCode != helpers.TradeRetCodeDonePartial) {
+		return
+	}
+
+	summary.ResubmitOrders = append(summary.ResubmitOrders, result.Order)
+	if result.Deal != 0 {
+		summary.Deals = append(summary.Deals, result.Deal)
+	}
+	t.addFill(summary, result.Volume, result.Price)
+	summary.Cancelled = false
+	summary.Completed = summary.RemainingVolume <= volumeEpsilon
+}
+
+// remainderPending reports whether order is still a pending order. The
+// positions list is not consulted: a partly filled RETURN order is both.
+func (t *FillTracker) remainderPending(ctx context.Context, order uint64) (bool, error) {
+	_, orders, err := t.service.GetOpenedTickets(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, ticket := range orders {
+		if uint64(ticket) == order {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// removeRemainder deletes the pending remainder of order. It reports true
+// only when the server answered with a pending-order removal and the ticket
+// has left the pending list; a reply that closed the position, or any
+// error, leaves the remainder unresolved.
+func (t *FillTracker) removeRemainder(ctx context.Context, order uint64) bool {
+	data, err := t.service.account.OrderClose(ctx, &pb.OrderCloseRequest{Ticket: order})
+	if err != nil || data.ReturnedCode != helpers.TradeRetCodeDone ||
+		data.CloseMode != pb.MRPC_ORDER_CLOSE_MODE_MRPC_PENDING_ORDER_REMOVE {
+		return false
+	}
+	pending, err := t.remainderPending(ctx, order)
+	return err == nil && !pending
+}
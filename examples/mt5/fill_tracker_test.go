@@ -0,0 +1,53 @@
+package mt5
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// partialFill is a BUY of 1 lot with RETURN filling that filled 0.4 lot:
+// ticket #200001 is both the open position and the pending remainder.
+func partialFill() (OrderRequest, *FillSummary) {
+	req := OrderRequest{Symbol: "EURUSD", Type: pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY, Volume: 1}
+	summary := &FillSummary{Order: 200001, Symbol: "EURUSD", RequestedVolume: 1}
+	return req, summary
+}
+
+func TestFillRemainderSharedTicket(t *testing.T) {
+	service, replayer := replayService(t, "fill_remainder_shared_ticket.json")
+	tracker := NewFillTracker(service, FillTrackerOptions{ResubmitRemainder: true})
+
+	req, summary := partialFill()
+	tracker.addFill(summary, 0.4, 1.1002)
+	tracker.handleRemainder(context.Background(), req, summary)
+
+	if len(summary.ResubmitOrders) != 1 || summary.ResubmitOrders[0] != 200003 {
+		t.Fatalf("resubmit orders = %v, want [200003]", summary.ResubmitOrders)
+	}
+	if !summary.Completed || summary.RemainingVolume > volumeEpsilon {
+		t.Errorf("completed = %v, remaining = %v; want the full lot filled", summary.Completed, summary.RemainingVolume)
+	}
+	if left := replayer.Remaining(); len(left) != 0 {
+		t.Errorf("%d fixture(s) not replayed, first %s", len(left), left[0].Method)
+	}
+}
+
+// TestFillRemainderClosedPosition: the server closed the position instead of
+// removing the pending remainder, so nothing may be re-submitted.
+func TestFillRemainderClosedPosition(t *testing.T) {
+	service, replayer := replayService(t, "fill_remainder_closed_position.json")
+	tracker := NewFillTracker(service, FillTrackerOptions{ResubmitRemainder: true})
+
+	req, summary := partialFill()
+	tracker.addFill(summary, 0.4, 1.1002)
+	tracker.handleRemainder(context.Background(), req, summary)
+
+	if len(summary.ResubmitOrders) != 0 || summary.Cancelled {
+		t.Fatalf("resubmit orders = %v, cancelled = %v; want the remainder left unresolved", summary.ResubmitOrders, summary.Cancelled)
+	}
+	if left := replayer.Remaining(); len(left) != 0 {
+		t.Errorf("%d fixture(s) not replayed, first %s", len(left), left[0].Method)
+	}
+}
@@ -0,0 +1,172 @@
+package mt5
+
+/*
+Foreign positions - what the bot may do with positions it did not open.
+
+Manual trades and other EAs share the account with the bot. By default
+Sugar treats every position alike, so CloseAllPositions or a trailing stop
+would happily touch them. With a TagManager attached (SetTagManager),
+every position is classified by its tag (comment or magic, see tags.go):
+
+  • Own      - tagged by this bot: full control
+  • Foreign  - anything else, handled per policy:
+      ForeignIgnore  - never modified or closed (default)
+      ForeignAdopt   - treated as a position of the given strategy
+      ForeignProtect - SL/TP may be managed, but the bot never closes it
+
+Policies are set for all foreign positions and/or per magic number
+(magic 0 = manual trades). Sugar enforces them in ClosePosition*,
+ModifyPosition*, CloseAll* and the Close* group helpers (foreign positions
+are skipped), and PositionQuery.Strategy selects the positions a strategy
+owns, including adopted ones.
+
+Usage:
+    tags := mt5.NewTagManager(770000)
+    tags.Register("grid", 1)
+    tags.SetForeignPolicy(mt5.ForeignProtect, "")          // others: SL only
+    tags.SetForeignMagicPolicy(0, mt5.ForeignAdopt, "grid") // manual trades join grid
+    sugar.SetTagManager(tags)
+
+    positions, err := sugar.PositionsQuery(mt5.PositionQuery{Strategy: "grid"})
+    err = sugar.ClosePosition(foreignTicket) // errors.Is(err, mt5.ErrForeignPosition)
+*/
+
+import (
+	"errors"
+	"fmt"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// ErrForeignPosition is returned when the foreign position policy forbids an action.
+var ErrForeignPosition = errors.New("position not owned by this bot")
+
+// ForeignPolicy decides how positions not opened by this bot are handled.
+type ForeignPolicy int
+
+const (
+	ForeignIgnore  ForeignPolicy = iota // Never modify or close
+	ForeignAdopt                        // Manage as a position of a strategy
+	ForeignProtect                      // Manage SL/TP, never close
+)
+
+func (p ForeignPolicy) String() string {
+	switch p {
+	case ForeignIgnore:
+		return "ignore"
+	case ForeignAdopt:
+		return "adopt"
+	case ForeignProtect:
+		return "protect"
+	}
+	return fmt.Sprintf("ForeignPolicy(%d)", int(p))
+}
+
+// foreignRule is a policy with its adopting strategy.
+type foreignRule struct {
+	policy   ForeignPolicy
+	strategy string
+}
+
+// Ownership classifies one position.
+type Ownership struct {
+	Foreign  bool          // Not tagged by this bot
+	Policy   ForeignPolicy // Policy applied to a foreign position
+	Strategy string        // Owning strategy (own tag or adopting strategy; "" if none)
+	Tag      Tag           // Decoded tag of an own position
+}
+
+// CanModify reports whether the bot may change SL/TP.
+func (o Ownership) CanModify() bool {
+	return !o.Foreign || o.Policy != ForeignIgnore
+}
+
+// CanClose reports whether the bot may close the position.
+func (o Ownership) CanClose() bool {
+	return !o.Foreign || o.Policy == ForeignAdopt
+}
+
+// SetForeignPolicy sets the policy for foreign positions without a
+// per-magic policy. strategy names the adopting strategy for ForeignAdopt.
+func (m *TagManager) SetForeignPolicy(policy ForeignPolicy, strategy string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.foreign = foreignRule{policy: policy, strategy: strategy}
+}
+
+// SetForeignMagicPolicy sets the policy for foreign positions with one
+// magic number (0 = manual trades).
+func (m *TagManager) SetForeignMagicPolicy(magic uint64, policy ForeignPolicy, strategy string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.foreignMagic == nil {
+		m.foreignMagic = make(map[uint64]foreignRule)
+	}
+	m.foreignMagic[magic] = foreignRule{policy: policy, strategy: strategy}
+}
+
+// Classify returns the ownership of a position or order from its comment
+// and magic number.
+func (m *TagManager) Classify(comment string, magic uint64) Ownership {
+	if tag, ok := m.Decode(comment, magic); ok {
+		return Ownership{Strategy: tag.Strategy, Tag: tag}
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	rule, ok := m.foreignMagic[magic]
+	if !ok {
+		rule = m.foreign
+	}
+	o := Ownership{Foreign: true, Policy: rule.policy}
+	if rule.policy == ForeignAdopt {
+		o.Strategy = rule.strategy
+	}
+	return o
+}
+
+// SetTagManager attaches a tag manager whose foreign position policy Sugar
+// enforces on closes and SL/TP changes (nil = every position is managed).
+//
+// Parameters:
+//   - m: Tag manager with registered strategies and foreign policies
+func (s *MT5Sugar) SetTagManager(m *TagManager) {
+	s.tags = m
+}
+
+// ownership classifies a position; every position is own without a tag manager.
+func (s *MT5Sugar) ownership(pos *pb.PositionInfo) Ownership {
+	if s.tags == nil {
+		return Ownership{}
+	}
+	return s.tags.Classify(pos.Comment, uint64(pos.MagicNumber))
+}
+
+// mayClose reports whether the foreign position policy allows closing pos.
+func (s *MT5Sugar) mayClose(pos *pb.PositionInfo) bool {
+	return s.ownership(pos).CanClose()
+}
+
+// checkForeign returns an error wrapping ErrForeignPosition if the policy
+// forbids closing (close) or modifying the position. Tickets that are not an
+// open position pass; the broker reports them. If the positions cannot be
+// read, the error is returned: the policy cannot be checked.
+func (s *MT5Sugar) checkForeign(ticket uint64, close bool) error {
+	if s.tags == nil {
+		return nil
+	}
+	positions, err := s.GetOpenPositions()
+	if err != nil {
+		return fmt.Errorf("foreign position check for #%d: %w", ticket, err)
+	}
+	for _, pos := range positions {
+		if pos.Ticket != ticket {
+			continue
+		}
+		o := s.ownership(pos)
+		if close && !o.CanClose() || !close && !o.CanModify() {
+			return fmt.Errorf("%w: #%d (policy %s)", ErrForeignPosition, ticket, o.Policy)
+		}
+		return nil
+	}
+	return nil
+}
@@ -0,0 +1,305 @@
+package mt5
+
+/*
+Number formatting - prices, volumes and money for people to read.
+
+Reports, CLI output and notifications printed prices with "%g" and money
+with "%.2f": EURUSD showed 1.1 instead of 1.10000, USDJPY money had cents,
+and a German reader got "1234.5" instead of "1.234,50 €". A Formatter
+renders numbers the way a trader expects:
+
+  • Price   - symbol digits (SYMBOL_DIGITS), e.g. 1.08500 / 151.250
+  • Volume  - volume step decimals (SYMBOL_VOLUME_STEP), e.g. 0.10 / 1
+  • Money   - account currency with symbol and currency decimals,
+              e.g. $1,234.50, 1.234,50 €, ¥12,345
+  • Locale  - decimal and thousands separators and currency symbol position
+              (LocaleEN, LocaleDE, LocaleFR, LocaleCH, LocaleRU, LocaleJA)
+
+Symbol digits are read once per symbol from the terminal (Sugar formatter)
+or set with SetSymbolFormat. Unknown symbols fall back to the shortest
+exact representation. Statements use the Sugar locale (SetNumberLocale).
+
+Usage:
+    sugar.SetNumberLocale(mt5.LocaleDE)
+    f, err := sugar.NewFormatter(mt5.LocaleDE)
+    fmt.Println(f.Price("EURUSD", 1.085))   // 1,08500
+    fmt.Println(f.Volume("EURUSD", 0.1))    // 0,10
+    fmt.Println(f.Money(-1234.5))           // -1.234,50 €
+    fmt.Println(f.SignedMoney(12.5))        // +12,50 €
+
+    // Without a connection
+    f = mt5.NewFormatter(mt5.LocaleEN, "USD")
+    f.SetSymbolFormat("XAUUSD", 2, 0.01)
+*/
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// NumberLocale describes how numbers and currency amounts are written.
+type NumberLocale struct {
+	Name        string // Locale tag (e.g., "de")
+	Decimal     string // Decimal separator
+	Group       string // Thousands separator ("" = no grouping)
+	SymbolAfter bool   // Currency symbol after the amount ("1.234,50 €")
+}
+
+// Predefined locales.
+var (
+	LocaleEN = NumberLocale{Name: "en", Decimal: ".", Group: ","}
+	LocaleDE = NumberLocale{Name: "de", Decimal: ",", Group: ".", SymbolAfter: true}
+	LocaleFR = NumberLocale{Name: "fr", Decimal: ",", Group: " ", SymbolAfter: true}
+	LocaleCH = NumberLocale{Name: "de-CH", Decimal: ".", Group: "’"}
+	LocaleRU = NumberLocale{Name: "ru", Decimal: ",", Group: " ", SymbolAfter: true}
+	LocaleJA = NumberLocale{Name: "ja", Decimal: ".", Group: ","}
+)
+
+// LocaleByName returns a predefined locale by tag ("en", "de", "fr", "de-CH",
+// "ru", "ja"; case-insensitive, region suffixes like "en-US" match "en").
+func LocaleByName(name string) (NumberLocale, bool) {
+	locales := []NumberLocale{LocaleEN, LocaleDE, LocaleFR, LocaleCH, LocaleRU, LocaleJA}
+	for _, l := range locales {
+		if strings.EqualFold(l.Name, name) {
+			return l, true
+		}
+	}
+	if i := strings.IndexAny(name, "-_"); i > 0 {
+		return LocaleByName(name[:i])
+	}
+	return NumberLocale{}, false
+}
+
+// orDefault returns LocaleEN for the zero locale.
+func (l NumberLocale) orDefault() NumberLocale {
+	if l.Decimal == "" {
+		return LocaleEN
+	}
+	return l
+}
+
+// FormatNumber writes v with decimals digits after the separator
+// (decimals < 0 = shortest exact representation).
+func (l NumberLocale) FormatNumber(v float64, decimals int) string {
+	l = l.orDefault()
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	s := strconv.FormatFloat(math.Abs(v), 'f', decimals, 64)
+	intPart, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, frac = s[:i], s[i+1:]
+	}
+
+	var b strings.Builder
+	if v < 0 && strings.Trim(s, "0.") != "" {
+		b.WriteByte('-')
+	}
+	for i, c := range intPart {
+		if i > 0 && l.Group != "" && (len(intPart)-i)%3 == 0 {
+			b.WriteString(l.Group)
+		}
+		b.WriteRune(c)
+	}
+	if frac != "" {
+		b.WriteString(l.Decimal)
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+// currencySymbols maps ISO codes to their symbols.
+var currencySymbols = map[string]string{
+	"USD": "$", "EUR": "€", "GBP": "£", "JPY": "¥", "CNY": "¥", "CHF": "CHF",
+	"AUD": "A$", "CAD": "C$", "NZD": "NZ$", "HKD": "HK$", "SGD": "S$",
+	"RUB": "₽", "INR": "₹", "KRW": "₩", "TRY": "₺", "PLN": "zł", "ZAR": "R",
+	"BRL": "R$", "ILS": "₪", "UAH": "₴", "BTC": "₿", "USC": "¢",
+}
+
+// currencyDecimals lists currencies without two decimals.
+var currencyDecimals = map[string]int{"JPY": 0, "KRW": 0, "HUF": 0, "BTC": 8}
+
+// CurrencySymbol returns the symbol of an ISO currency code, or the code
+// itself when it has no common symbol.
+func CurrencySymbol(code string) string {
+	if symbol, ok := currencySymbols[strings.ToUpper(code)]; ok {
+		return symbol
+	}
+	return strings.ToUpper(code)
+}
+
+// CurrencyDecimals returns the usual number of decimals of a currency (2 for most).
+func CurrencyDecimals(code string) int {
+	if digits, ok := currencyDecimals[strings.ToUpper(code)]; ok {
+		return digits
+	}
+	return 2
+}
+
+// stepDecimals returns the decimals needed to write multiples of step.
+func stepDecimals(step float64) int {
+	if step <= 0 || step >= 1 {
+		return 0
+	}
+	return int(math.Ceil(-math.Log10(step) - 1e-9))
+}
+
+// symbolFormat holds the digits of one symbol.
+type symbolFormat struct {
+	digits       int // Price decimals
+	volumeDigits int // Volume decimals
+}
+
+// Formatter renders prices, volumes and money in a locale. Safe for concurrent use.
+type Formatter struct {
+	Locale   NumberLocale
+	Currency string // Account currency for Money (e.g., "USD")
+
+	mu      sync.Mutex
+	symbols map[string]symbolFormat
+	lookup  func(symbol string) (symbolFormat, bool) // Reads unknown symbols (nil = none)
+}
+
+// NewFormatter creates a formatter without a terminal connection; symbol
+// digits come from SetSymbolFormat.
+//
+// Parameters:
+//   - locale: Separators and currency position (zero value = LocaleEN)
+//   - currency: Account currency for Money (e.g., "USD")
+func NewFormatter(locale NumberLocale, currency string) *Formatter {
+	return &Formatter{
+		Locale:   locale.orDefault(),
+		Currency: strings.ToUpper(currency),
+		symbols:  make(map[string]symbolFormat),
+	}
+}
+
+// SetSymbolFormat sets the price digits and volume step of a symbol.
+func (f *Formatter) SetSymbolFormat(symbol string, digits int, volumeStep float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.symbols[symbol] = symbolFormat{digits: digits, volumeDigits: stepDecimals(volumeStep)}
+}
+
+// symbolFormat returns the digits of a symbol; ok is false if unknown.
+func (f *Formatter) symbolFormat(symbol string) (symbolFormat, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if sf, ok := f.symbols[symbol]; ok {
+		return sf, true
+	}
+	if f.lookup == nil {
+		return symbolFormat{}, false
+	}
+	sf, ok := f.lookup(symbol)
+	if ok {
+		f.symbols[symbol] = sf
+	}
+	return sf, ok
+}
+
+// Price formats a price with the digits of its symbol.
+func (f *Formatter) Price(symbol string, price float64) string {
+	digits := -1
+	if sf, ok := f.symbolFormat(symbol); ok {
+		digits = sf.digits
+	}
+	return f.Locale.FormatNumber(price, digits)
+}
+
+// Volume formats a volume in lots with the decimals of the symbol volume step
+// (2 for unknown symbols).
+func (f *Formatter) Volume(symbol string, lots float64) string {
+	digits := 2
+	if sf, ok := f.symbolFormat(symbol); ok {
+		digits = sf.volumeDigits
+	}
+	return f.Locale.FormatNumber(lots, digits)
+}
+
+// Money formats an amount in the account currency.
+func (f *Formatter) Money(amount float64) string {
+	return f.MoneyIn(amount, f.Currency)
+}
+
+// SignedMoney formats an amount in the account currency with an explicit
+// "+" for gains.
+func (f *Formatter) SignedMoney(amount float64) string {
+	s := f.Money(amount)
+	if !strings.HasPrefix(s, "-") && amount != 0 {
+		return "+" + s
+	}
+	return s
+}
+
+// MoneyIn formats an amount in a currency: "$1,234.50", "-1.234,50 €",
+// "1,234.50 XYZ" for currencies without a symbol.
+func (f *Formatter) MoneyIn(amount float64, currency string) string {
+	number := f.Locale.FormatNumber(amount, CurrencyDecimals(currency))
+	if currency == "" {
+		return number
+	}
+	symbol := CurrencySymbol(currency)
+	if f.Locale.SymbolAfter || symbol == strings.ToUpper(currency) {
+		return number + " " + symbol
+	}
+	if sign := strings.HasPrefix(number, "-"); sign {
+		return "-" + symbol + number[1:]
+	}
+	return symbol + number
+}
+
+// SetNumberLocale sets the locale used by NewFormatter defaults and
+// statements (BuildStatement). Default is LocaleEN.
+//
+// Parameters:
+//   - locale: Separators and currency position
+func (s *MT5Sugar) SetNumberLocale(locale NumberLocale) {
+	s.locale = locale.orDefault()
+}
+
+// NewFormatter creates a formatter for the account currency that reads the
+// digits and volume step of each symbol from the terminal on first use.
+// Uses 5-second timeout.
+//
+// Parameters:
+//   - locale: Separators and currency position (zero value = SetNumberLocale locale)
+//
+// Returns:
+//   - *Formatter, or error if the account currency could not be read
+func (s *MT5Sugar) NewFormatter(locale NumberLocale) (*Formatter, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, 5*time.Second)
+	defer cancel()
+
+	currency, err := s.service.GetAccountString(ctx, pb.AccountInfoStringPropertyType_ACCOUNT_CURRENCY)
+	if err != nil {
+		return nil, err
+	}
+	if locale.Decimal == "" {
+		locale = s.locale
+	}
+	return s.newFormatter(locale, currency), nil
+}
+
+// newFormatter creates a formatter reading symbol formats through Sugar.
+func (s *MT5Sugar) newFormatter(locale NumberLocale, currency string) *Formatter {
+	f := NewFormatter(locale, currency)
+	f.lookup = func(symbol string) (symbolFormat, bool) {
+		ctx, cancel := context.WithTimeout(s.ctx, 5*time.Second)
+		defer cancel()
+		name := s.ResolveSymbol(symbol)
+		data, err := s.service.account.SymbolParamsMany(ctx, &pb.SymbolParamsManyRequest{SymbolName: &name})
+		if err != nil || len(data.SymbolInfos) == 0 {
+			return symbolFormat{}, false
+		}
+		p := data.SymbolInfos[0]
+		return symbolFormat{digits: int(p.Digits), volumeDigits: stepDecimals(p.VolumeStep)}, true
+	}
+	return f
+}
@@ -0,0 +1,409 @@
+package mt5
+
+/*
+History export - deals, orders and recorded ticks as CSV, JSON(L) or Parquet.
+
+Every export uses a fixed schema (column names, order and types below), so
+files from different runs and accounts can be concatenated and read by
+pandas, DuckDB or Spark without custom parsing:
+
+    duckdb> SELECT symbol, sum(profit) FROM 'deals.parquet' GROUP BY symbol;
+    pandas: pd.read_json("deals.jsonl", lines=True)
+
+ExportJSON writes the same objects as one JSON array, for tools that do
+not read line-delimited JSON.
+
+Times are UTC: Parquet TIMESTAMP_MILLIS, ISO-8601 with milliseconds in
+CSV/JSONL ("2024-01-02T15:04:05.000Z"; empty/null when unset). Enum values
+are written without their protobuf prefix ("BUY", "ENTRY_IN" → "IN").
+
+Deals:  ticket, time, symbol, type, entry, reason, volume, price, profit,
+        swap, commission, fee, stop_loss, take_profit, position_id,
+        comment, external_id
+Orders: ticket, setup_time, done_time, expiration, symbol, type, state,
+        filling, type_time, volume_initial, volume_current, price_open,
+        price_current, stop_limit, stop_loss, take_profit, magic,
+        position_id, comment, external_id
+Ticks:  symbol, time, bid, ask, last, volume, volume_real, flags
+
+Usage:
+    f, _ := os.Create("deals.parquet")
+    n, err := service.ExportDeals(ctx, from, to, f, mt5.ExportParquet)
+
+    n, err = store.Export("EURUSD", from, to, f, mt5.ExportJSONL)
+*/
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ExportFormat selects the file format of an export.
+type ExportFormat int
+
+const (
+	ExportCSV ExportFormat = iota
+	ExportJSONL
+	ExportParquet
+	ExportJSON
+)
+
+func (f ExportFormat) String() string {
+	switch f {
+	case ExportCSV:
+		return "csv"
+	case ExportJSONL:
+		return "jsonl"
+	case ExportParquet:
+		return "parquet"
+	case ExportJSON:
+		return "json"
+	default:
+		return fmt.Sprintf("ExportFormat(%d)", int(f))
+	}
+}
+
+// ExportFormatFromPath picks the format from a file extension (.csv, .json, .jsonl/.ndjson, .parquet).
+func ExportFormatFromPath(path string) (ExportFormat, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ExportCSV, nil
+	case ".json":
+		return ExportJSON, nil
+	case ".jsonl", ".ndjson":
+		return ExportJSONL, nil
+	case ".parquet":
+		return ExportParquet, nil
+	default:
+		return 0, fmt.Errorf("unknown export format for %s", path)
+	}
+}
+
+// exportKind is the type of an export column.
+type exportKind int
+
+const (
+	exportInt    exportKind = iota // int64
+	exportFloat                    // float64
+	exportString                   // string
+	exportTime                     // time.Time (UTC, milliseconds)
+)
+
+// exportColumn is one column; Value returns int64, float64, string or time.Time.
+type exportColumn struct {
+	Name  string
+	Kind  exportKind
+	Value func(row int) any
+}
+
+// exportTable is a fixed-schema table ready to be written in any format.
+type exportTable struct {
+	Columns []exportColumn
+	Rows    int
+}
+
+// write encodes t in the given format.
+func (t *exportTable) write(w io.Writer, format ExportFormat) error {
+	switch format {
+	case ExportCSV:
+		return writeExportCSV(w, t)
+	case ExportJSONL:
+		return writeExportJSON(w, t, false)
+	case ExportJSON:
+		return writeExportJSON(w, t, true)
+	case ExportParquet:
+		return writeParquet(w, t)
+	default:
+		return fmt.Errorf("unsupported export format %s", format)
+	}
+}
+
+const exportTimeLayout = "2006-01-02T15:04:05.000Z"
+
+// exportText formats a value for CSV.
+func exportText(v any) string {
+	switch v := v.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.UTC().Format(exportTimeLayout)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func writeExportCSV(w io.Writer, t *exportTable) error {
+	cw := csv.NewWriter(w)
+	record := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		record[i] = col.Name
+	}
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+	for row := 0; row < t.Rows; row++ {
+		for i, col := range t.Columns {
+			record[i] = exportText(col.Value(row))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeExportJSON writes one object per line with keys in schema order,
+// wrapped in a JSON array if array is set.
+func writeExportJSON(w io.Writer, t *exportTable, array bool) error {
+	bw := bufio.NewWriter(w)
+	keys := make([][]byte, len(t.Columns))
+	for i, col := range t.Columns {
+		keys[i], _ = json.Marshal(col.Name)
+	}
+
+	if array {
+		bw.WriteString("[\n")
+	}
+	for row := 0; row < t.Rows; row++ {
+		if array && row > 0 {
+			bw.WriteString(",\n")
+		}
+		bw.WriteByte('{')
+		for i, col := range t.Columns {
+			if i > 0 {
+				bw.WriteByte(',')
+			}
+			bw.Write(keys[i])
+			bw.WriteByte(':')
+
+			switch v := col.Value(row).(type) {
+			case float64:
+				if math.IsNaN(v) || math.IsInf(v, 0) {
+					bw.WriteString("null")
+				} else {
+					bw.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+				}
+			case int64:
+				bw.WriteString(strconv.FormatInt(v, 10))
+			case time.Time:
+				if v.IsZero() {
+					bw.WriteString("null")
+				} else {
+					bw.WriteString(`"` + v.UTC().Format(exportTimeLayout) + `"`)
+				}
+			default:
+				raw, err := json.Marshal(v)
+				if err != nil {
+					return err
+				}
+				bw.Write(raw)
+			}
+		}
+		bw.WriteByte('}')
+		if !array {
+			bw.WriteByte('\n')
+		}
+	}
+	if array {
+		if t.Rows > 0 {
+			bw.WriteByte('\n')
+		}
+		bw.WriteString("]\n")
+	}
+	return bw.Flush()
+}
+
+// exportEnum strips the protobuf prefix from an enum name.
+func exportEnum(name, prefix string) string {
+	return strings.TrimPrefix(name, prefix)
+}
+
+// exportTimestamp converts an optional protobuf timestamp.
+func exportTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime().UTC()
+}
+
+// dealTable builds the deals schema.
+func dealTable(deals []*pb.DealHistoryData) *exportTable {
+	d := func(row int) *pb.DealHistoryData { return deals[row] }
+	return &exportTable{Rows: len(deals), Columns: []exportColumn{
+		{"ticket", exportInt, func(r int) any { return int64(d(r).Ticket) }},
+		{"time", exportTime, func(r int) any { return exportTimestamp(d(r).Time) }},
+		{"symbol", exportString, func(r int) any { return d(r).Symbol }},
+		{"type", exportString, func(r int) any { return exportEnum(d(r).Type.String(), "BMT5_DEAL_TYPE_") }},
+		{"entry", exportString, func(r int) any { return exportEnum(d(r).EntryType.String(), "BMT5_DEAL_ENTRY_") }},
+		{"reason", exportString, func(r int) any { return exportEnum(d(r).Reason.String(), "BMT5_DEAL_REASON_") }},
+		{"volume", exportFloat, func(r int) any { return d(r).Volume }},
+		{"price", exportFloat, func(r int) any { return d(r).Price }},
+		{"profit", exportFloat, func(r int) any { return d(r).Profit }},
+		{"swap", exportFloat, func(r int) any { return d(r).Swap }},
+		{"commission", exportFloat, func(r int) any { return d(r).Commission }},
+		{"fee", exportFloat, func(r int) any { return d(r).Fee }},
+		{"stop_loss", exportFloat, func(r int) any { return d(r).StopLoss }},
+		{"take_profit", exportFloat, func(r int) any { return d(r).TakeProfit }},
+		{"position_id", exportInt, func(r int) any { return int64(d(r).PositionId) }},
+		{"comment", exportString, func(r int) any { return d(r).Comment }},
+		{"external_id", exportString, func(r int) any { return d(r).ExternalId }},
+	}}
+}
+
+// orderTable builds the orders schema.
+func orderTable(orders []*pb.OrderHistoryData) *exportTable {
+	o := func(row int) *pb.OrderHistoryData { return orders[row] }
+	return &exportTable{Rows: len(orders), Columns: []exportColumn{
+		{"ticket", exportInt, func(r int) any { return int64(o(r).Ticket) }},
+		{"setup_time", exportTime, func(r int) any { return exportTimestamp(o(r).SetupTime) }},
+		{"done_time", exportTime, func(r int) any { return exportTimestamp(o(r).DoneTime) }},
+		{"expiration", exportTime, func(r int) any { return exportTimestamp(o(r).TimeExpiration) }},
+		{"symbol", exportString, func(r int) any { return o(r).Symbol }},
+		{"type", exportString, func(r int) any { return exportEnum(o(r).Type.String(), "BMT5_ORDER_TYPE_") }},
+		{"state", exportString, func(r int) any { return exportEnum(o(r).State.String(), "BMT5_ORDER_STATE_") }},
+		{"filling", exportString, func(r int) any { return exportEnum(o(r).TypeFilling.String(), "BMT5_ORDER_FILLING_") }},
+		{"type_time", exportString, func(r int) any { return exportEnum(o(r).TypeTime.String(), "BMT5_ORDER_TIME_") }},
+		{"volume_initial", exportFloat, func(r int) any { return o(r).VolumeInitial }},
+		{"volume_current", exportFloat, func(r int) any { return o(r).VolumeCurrent }},
+		{"price_open", exportFloat, func(r int) any { return o(r).PriceOpen }},
+		{"price_current", exportFloat, func(r int) any { return o(r).PriceCurrent }},
+		{"stop_limit", exportFloat, func(r int) any { return o(r).StopLimit }},
+		{"stop_loss", exportFloat, func(r int) any { return o(r).StopLoss }},
+		{"take_profit", exportFloat, func(r int) any { return o(r).TakeProfit }},
+		{"magic", exportInt, func(r int) any { return o(r).MagicNumber }},
+		{"position_id", exportInt, func(r int) any { return int64(o(r).PositionId) }},
+		{"comment", exportString, func(r int) any { return o(r).Comment }},
+		{"external_id", exportString, func(r int) any { return o(r).ExternalId }},
+	}}
+}
+
+// tickTable builds the ticks schema.
+func tickTable(ticks []SymbolTick) *exportTable {
+	t := func(row int) *SymbolTick { return &ticks[row] }
+	return &exportTable{Rows: len(ticks), Columns: []exportColumn{
+		{"symbol", exportString, func(r int) any { return t(r).Symbol }},
+		{"time", exportTime, func(r int) any {
+			if t(r).TimeMS != 0 {
+				return time.UnixMilli(t(r).TimeMS).UTC()
+			}
+			return t(r).Time.UTC()
+		}},
+		{"bid", exportFloat, func(r int) any { return t(r).Bid }},
+		{"ask", exportFloat, func(r int) any { return t(r).Ask }},
+		{"last", exportFloat, func(r int) any { return t(r).Last }},
+		{"volume", exportInt, func(r int) any { return int64(t(r).Volume) }},
+		{"volume_real", exportFloat, func(r int) any { return t(r).VolumeReal }},
+		{"flags", exportInt, func(r int) any { return int64(t(r).Flags) }},
+	}}
+}
+
+// historyPageSize is the page size used to read the full history window.
+const historyPageSize = 1000
+
+// loadHistory reads all deals and orders of [from, to], page by page.
+func (s *MT5Service) loadHistory(ctx context.Context, from, to time.Time) ([]*pb.DealHistoryData, []*pb.OrderHistoryData, error) {
+	var deals []*pb.DealHistoryData
+	var orders []*pb.OrderHistoryData
+
+	for page, read := int32(1), 0; ; page++ {
+		data, err := s.GetOrderHistory(ctx, from, to,
+			pb.BMT5_ENUM_ORDER_HISTORY_SORT_TYPE_BMT5_SORT_BY_OPEN_TIME_ASC, page, historyPageSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		items := data.GetHistoryData()
+		for _, item := range items {
+			if deal := item.GetHistoryDeal(); deal != nil {
+				deals = append(deals, deal)
+			}
+			if order := item.GetHistoryOrder(); order != nil {
+				orders = append(orders, order)
+			}
+		}
+		read += len(items)
+		if len(items) < historyPageSize || read >= int(data.GetArrayTotal()) {
+			break
+		}
+	}
+	return deals, orders, nil
+}
+
+// ExportDeals writes all deals of [from, to] in the deals schema.
+//
+// Parameters:
+//   - ctx: Context for timeout and cancellation
+//   - from, to: History window
+//   - w: Destination (file, buffer, HTTP response)
+//   - format: ExportCSV, ExportJSON, ExportJSONL or ExportParquet
+//
+// Returns:
+//   - Number of deals written
+//   - Error if history could not be read or written
+func (s *MT5Service) ExportDeals(ctx context.Context, from, to time.Time, w io.Writer, format ExportFormat) (int, error) {
+	deals, _, err := s.loadHistory(ctx, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("ExportDeals failed: %w", err)
+	}
+	if err := dealTable(deals).write(w, format); err != nil {
+		return 0, fmt.Errorf("ExportDeals failed: %w", err)
+	}
+	return len(deals), nil
+}
+
+// ExportOrders writes all history orders of [from, to] in the orders schema.
+//
+// Parameters:
+//   - ctx: Context for timeout and cancellation
+//   - from, to: History window
+//   - w: Destination (file, buffer, HTTP response)
+//   - format: ExportCSV, ExportJSON, ExportJSONL or ExportParquet
+//
+// Returns:
+//   - Number of orders written
+//   - Error if history could not be read or written
+func (s *MT5Service) ExportOrders(ctx context.Context, from, to time.Time, w io.Writer, format ExportFormat) (int, error) {
+	_, orders, err := s.loadHistory(ctx, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("ExportOrders failed: %w", err)
+	}
+	if err := orderTable(orders).write(w, format); err != nil {
+		return 0, fmt.Errorf("ExportOrders failed: %w", err)
+	}
+	return len(orders), nil
+}
+
+// Export writes the recorded ticks of a symbol in [from, to) in the ticks schema.
+//
+// Returns:
+//   - Number of ticks written
+//   - Error if the recordings could not be read or written
+func (s *TickStore) Export(symbol string, from, to time.Time, w io.Writer, format ExportFormat) (int, error) {
+	ticks, err := s.Load(symbol, from, to)
+	if err != nil {
+		return 0, err
+	}
+	if err := tickTable(ticks).write(w, format); err != nil {
+		return 0, fmt.Errorf("tick export: %w", err)
+	}
+	return len(ticks), nil
+}
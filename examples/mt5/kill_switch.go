@@ -0,0 +1,276 @@
+package mt5
+
+/*
+KillSwitch - account-level emergency stop.
+
+Trigger engages the account kill switch (MT5Account.Halt) before doing
+anything else, so from that instant every new OrderSend / OrderModify of
+this process is refused, whichever layer or goroutine sends it. It then
+cancels all pending orders, optionally closes all positions, and raises the
+configured alerts. Closing stays allowed while the switch is engaged.
+
+The block lives in the MT5Account of this process. An operator triggering
+the switch from another process (CLI) cancels and flattens on the server,
+but cannot block a different running bot - expose Handler over HTTP for that.
+
+Usage:
+    ks := mt5.NewKillSwitch(sugar, mt5.KillSwitchConfig{
+        FlattenPositions: true,
+        HTTPToken:        os.Getenv("KILL_SWITCH_TOKEN"),
+        Alerts: []func(mt5.KillEvent){
+            mt5.MailKillAlert(sender, []string{"ops@example.com"}),
+        },
+    })
+
+    event, err := ks.Trigger("daily loss limit exceeded")
+    ...
+    ks.Reset() // allow trading again
+
+    // REST: POST /kill {"reason": "..."}, GET /kill, DELETE /kill
+    // POST and DELETE need "Authorization: Bearer <HTTPToken>"
+    metricsServer.Handle("/kill", ks.Handler())
+*/
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+	helpers "github.com/MetaRPC/GoMT5/package/Helpers"
+)
+
+// KillSwitchConfig configures what Trigger does besides blocking new trades.
+type KillSwitchConfig struct {
+	FlattenPositions bool              // Close all open positions after cancelling pendings
+	Alerts           []func(KillEvent) // Called once per engagement, after cleanup
+	HTTPToken        string            // Bearer token Handler requires for POST/DELETE ("" = read-only)
+}
+
+// KillEvent describes one kill switch engagement.
+type KillEvent struct {
+	Reason          string
+	TriggeredAt     time.Time
+	CancelledOrders int      // Pending orders cancelled
+	ClosedPositions int      // Positions closed (FlattenPositions only)
+	Errors          []string // Orders or positions that could not be cancelled / closed
+}
+
+// KillSwitch blocks trading on an account and cleans up exposure. Safe for concurrent use.
+type KillSwitch struct {
+	sugar  *MT5Sugar
+	config KillSwitchConfig
+
+	mu    sync.Mutex
+	event *KillEvent // Last engagement, nil when not engaged
+}
+
+// NewKillSwitch creates a kill switch for the sugar's account.
+func NewKillSwitch(sugar *MT5Sugar, config KillSwitchConfig) *KillSwitch {
+	return &KillSwitch{sugar: sugar, config: config}
+}
+
+// Trigger engages the kill switch, cancels all pending orders and, if
+// configured, closes all positions. Triggering an engaged switch repeats
+// the cleanup (catching orders that raced the block) without re-alerting.
+//
+// Parameters:
+//   - reason: Why trading is stopped (shown in refused trade errors and alerts)
+//
+// Returns:
+//   - The engagement event, and error if orders or positions could not be read
+func (k *KillSwitch) Trigger(reason string) (*KillEvent, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	account := k.sugar.GetAccount()
+	first := account.Halt(reason)
+	if first || k.event == nil {
+		reason, since, _ := account.Halted()
+		k.event = &KillEvent{Reason: reason, TriggeredAt: since}
+	}
+
+	err := k.cleanup(k.event)
+
+	if first {
+		event := *k.event
+		for _, alert := range k.config.Alerts {
+			alert(event)
+		}
+	}
+
+	event := *k.event
+	return &event, err
+}
+
+// cleanup cancels pending orders and optionally closes positions.
+func (k *KillSwitch) cleanup(event *KillEvent) error {
+	ctx, cancel := context.WithTimeout(k.sugar.ctx, 30*time.Second)
+	defer cancel()
+
+	service := k.sugar.GetService()
+	data, err := service.GetOpenedOrders(ctx, pb.BMT5_ENUM_OPENED_ORDER_SORT_TYPE_BMT5_OPENED_ORDER_SORT_BY_OPEN_TIME_ASC)
+	if err != nil {
+		return fmt.Errorf("kill switch: failed to get orders: %w", err)
+	}
+
+	for _, order := range data.OpenedOrders {
+		retCode, err := service.CloseOrder(ctx, &pb.OrderCloseRequest{Ticket: order.Ticket})
+		if err != nil || retCode != 10009 {
+			event.Errors = append(event.Errors, cleanupError("cancel order", order.Ticket, retCode, err))
+			continue
+		}
+		event.CancelledOrders++
+	}
+
+	if k.config.FlattenPositions {
+		for _, pos := range data.PositionInfos {
+			retCode, err := service.CloseOrder(ctx, &pb.OrderCloseRequest{Ticket: pos.Ticket})
+			if err != nil || retCode != 10009 {
+				event.Errors = append(event.Errors, cleanupError("close position", pos.Ticket, retCode, err))
+				continue
+			}
+			event.ClosedPositions++
+		}
+	}
+
+	return nil
+}
+
+// cleanupError describes a failed cancel or close for KillEvent.Errors,
+// with the likely cause and fix of the rejection.
+func cleanupError(what string, ticket uint64, retCode uint32, err error) string {
+	if err == nil {
+		return fmt.Sprintf("%s #%d: %s", what, ticket, helpers.Explain(retCode))
+	}
+	if ex, ok := helpers.ExplainErr(err); ok {
+		return fmt.Sprintf("%s #%d: %v - %s", what, ticket, err, ex)
+	}
+	return fmt.Sprintf("%s #%d: %v", what, ticket, err)
+}
+
+// Reset releases the kill switch and allows trading again.
+func (k *KillSwitch) Reset() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.sugar.GetAccount().Resume()
+	k.event = nil
+}
+
+// Engaged returns the current engagement, or nil if trading is allowed.
+// A switch engaged directly through MT5Account.Halt is reported too.
+func (k *KillSwitch) Engaged() *KillEvent {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	reason, since, halted := k.sugar.GetAccount().Halted()
+	if !halted {
+		return nil
+	}
+	if k.event == nil {
+		return &KillEvent{Reason: reason, TriggeredAt: since}
+	}
+	event := *k.event
+	return &event
+}
+
+// Handler serves the kill switch over HTTP:
+//   - GET: current state ({"engaged": false} or the engagement event)
+//   - POST: trigger, body {"reason": "..."} (optional)
+//   - DELETE: reset
+//
+// POST and DELETE require the header "Authorization: Bearer <HTTPToken>"
+// and are refused outright when no HTTPToken is configured. The token
+// travels in clear text: serve the handler over TLS or on a private network.
+func (k *KillSwitch) Handler() http.Handler {
+	type state struct {
+		Engaged bool       `json:"engaged"`
+		Event   *KillEvent `json:"event,omitempty"`
+		Error   string     `json:"error,omitempty"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost || r.Method == http.MethodDelete {
+			if k.config.HTTPToken == "" {
+				http.Error(w, "kill switch is read-only over HTTP (no token configured)", http.StatusForbidden)
+				return
+			}
+			if !k.authorized(r) {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		var resp state
+		switch r.Method {
+		case http.MethodGet:
+			resp.Event = k.Engaged()
+		case http.MethodPost:
+			var req struct {
+				Reason string `json:"reason"`
+			}
+			if r.ContentLength != 0 {
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+					return
+				}
+			}
+			if strings.TrimSpace(req.Reason) == "" {
+				req.Reason = "triggered via HTTP from " + r.RemoteAddr
+			}
+			event, err := k.Trigger(req.Reason)
+			resp.Event = event
+			if err != nil {
+				resp.Error = err.Error()
+			}
+		case http.MethodDelete:
+			k.Reset()
+		default:
+			w.Header().Set("Allow", "GET, POST, DELETE")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		resp.Engaged = resp.Event != nil
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	})
+}
+
+// authorized reports whether r carries the configured bearer token.
+func (k *KillSwitch) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(k.config.HTTPToken)) == 1
+}
+
+// MailKillAlert returns a kill switch alert that emails the event.
+func MailKillAlert(sender MailSender, recipients []string) func(KillEvent) {
+	return func(event KillEvent) {
+		var body strings.Builder
+		fmt.Fprintf(&body, "<h2>Kill switch engaged</h2><p><b>Reason:</b> %s<br><b>Time:</b> %s</p>",
+			template.HTMLEscapeString(event.Reason), event.TriggeredAt.Format(time.RFC1123))
+		fmt.Fprintf(&body, "<p>Cancelled orders: %d<br>Closed positions: %d</p>", event.CancelledOrders, event.ClosedPositions)
+		if len(event.Errors) > 0 {
+			body.WriteString("<p><b>Errors:</b></p><ul>")
+			for _, e := range event.Errors {
+				fmt.Fprintf(&body, "<li>%s</li>", template.HTMLEscapeString(e))
+			}
+			body.WriteString("</ul>")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		sender.Send(ctx, MailMessage{
+			To:      recipients,
+			Subject: "KILL SWITCH: " + event.Reason,
+			HTML:    body.String(),
+		})
+	}
+}
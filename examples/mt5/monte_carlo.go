@@ -0,0 +1,293 @@
+package mt5
+
+/*
+MonteCarlo - robustness analysis of a trade sequence.
+
+A backtest or live history is one ordering of its trades; its max drawdown is
+a single draw from what the same edge could produce. RunMonteCarlo replays
+the trade P/L list thousands of times in a different order and reports the
+distribution of final return, max drawdown and losing streaks:
+  • MonteCarloShuffle   - permutes the trades (same trades, other order)
+  • MonteCarloBootstrap - draws trades with replacement (other trade mixes too)
+
+Size RiskManager limits from the simulated worst case rather than the single
+historical drawdown: a limit at the 95th percentile drawdown is only hit by
+5% of the simulated paths, so hitting it live suggests the edge is gone.
+
+Usage:
+    trades := []float64{120.5, -80, 45.2, -60, 210, ...} // net P/L per trade
+    result, err := mt5.RunMonteCarlo(trades, mt5.DefaultMonteCarloConfig(10000))
+    dd, ddPercent := result.WorstDrawdown(0.95)
+    fmt.Printf("95%% worst drawdown: %.2f (%.1f%%), ruin: %.1f%%\n",
+        dd, ddPercent, result.RuinProbability*100)
+
+    // Or from closed positions of the account
+    result, err = sugar.MonteCarloFromHistory(from, to, mt5.DefaultMonteCarloConfig(10000))
+*/
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// MonteCarloMethod selects how trade sequences are resampled.
+type MonteCarloMethod int
+
+const (
+	MonteCarloShuffle   MonteCarloMethod = iota // Random permutation of all trades
+	MonteCarloBootstrap                         // Random draw with replacement
+)
+
+// MonteCarloConfig configures a simulation.
+type MonteCarloConfig struct {
+	Runs                int              // Number of simulated sequences
+	StartingEquity      float64          // Equity before the first trade (required for % figures)
+	Method              MonteCarloMethod // Resampling method
+	TradesPerRun        int              // Trades per sequence (0 = number of input trades)
+	RuinDrawdownPercent float64          // Drawdown counted as ruin (e.g., 50 = half the peak)
+	Seed                int64            // Random seed for reproducible results (0 = time based)
+}
+
+// DefaultMonteCarloConfig returns 5000 shuffled runs with ruin at 50% drawdown.
+func DefaultMonteCarloConfig(startingEquity float64) MonteCarloConfig {
+	return MonteCarloConfig{
+		Runs:                5000,
+		StartingEquity:      startingEquity,
+		Method:              MonteCarloShuffle,
+		RuinDrawdownPercent: 50,
+	}
+}
+
+// MonteCarloRun holds the outcome of one trade sequence.
+type MonteCarloRun struct {
+	NetProfit           float64 // Sum of trade P/L
+	ReturnPercent       float64 // NetProfit as % of starting equity
+	MaxDrawdown         float64 // Deepest peak-to-trough equity drop (absolute)
+	MaxDrawdownPercent  float64 // Deepest drop as % of the peak
+	LongestLosingStreak int     // Most consecutive losing trades
+	Ruined              bool    // Drawdown reached RuinDrawdownPercent
+}
+
+// Distribution summarizes simulated values of one statistic.
+type Distribution struct {
+	Mean   float64
+	StdDev float64
+	Min    float64
+	Max    float64
+	sorted []float64
+}
+
+// Percentile returns the value below which p percent of the runs fall
+// (p from 0 to 100, linear interpolation between runs).
+func (d Distribution) Percentile(p float64) float64 {
+	n := len(d.sorted)
+	if n == 0 {
+		return 0
+	}
+	pos := math.Max(0, math.Min(100, p)) / 100 * float64(n-1)
+	lo := int(pos)
+	if lo >= n-1 {
+		return d.sorted[n-1]
+	}
+	frac := pos - float64(lo)
+	return d.sorted[lo] + frac*(d.sorted[lo+1]-d.sorted[lo])
+}
+
+// newDistribution computes the summary of values (values is sorted in place).
+func newDistribution(values []float64) Distribution {
+	d := Distribution{sorted: values}
+	if len(values) == 0 {
+		return d
+	}
+	sort.Float64s(values)
+	d.Min = values[0]
+	d.Max = values[len(values)-1]
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	d.Mean = sum / float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - d.Mean) * (v - d.Mean)
+	}
+	d.StdDev = math.Sqrt(variance / float64(len(values)))
+	return d
+}
+
+// MonteCarloResult holds the simulated distributions.
+type MonteCarloResult struct {
+	Config             MonteCarloConfig
+	Trades             int           // Input trades
+	Historical         MonteCarloRun // The input sequence in its original order
+	NetProfit          Distribution  // Final P/L per run
+	ReturnPercent      Distribution  // Final return % per run
+	MaxDrawdown        Distribution  // Max drawdown per run (absolute)
+	MaxDrawdownPercent Distribution  // Max drawdown per run (% of peak)
+	LosingStreak       Distribution  // Longest losing streak per run
+	RuinProbability    float64       // Share of runs reaching RuinDrawdownPercent (0-1)
+	ProfitProbability  float64       // Share of runs ending in profit (0-1)
+}
+
+// WorstDrawdown returns the max drawdown not exceeded by the given share of
+// runs (e.g., 0.95), in account currency and percent of peak.
+func (r *MonteCarloResult) WorstDrawdown(confidence float64) (absolute, percent float64) {
+	return r.MaxDrawdown.Percentile(confidence * 100), r.MaxDrawdownPercent.Percentile(confidence * 100)
+}
+
+// RunMonteCarlo simulates resampled sequences of the given trades.
+//
+// Parameters:
+//   - trades: Net P/L of each trade in the order they closed
+//   - config: Simulation settings (StartingEquity must be positive)
+//
+// Returns:
+//   - Distributions over config.Runs sequences, or error on invalid input
+func RunMonteCarlo(trades []float64, config MonteCarloConfig) (*MonteCarloResult, error) {
+	if len(trades) == 0 {
+		return nil, errors.New("monte carlo: no trades")
+	}
+	if config.StartingEquity <= 0 {
+		return nil, errors.New("monte carlo: starting equity must be positive")
+	}
+	if config.Runs <= 0 {
+		return nil, errors.New("monte carlo: runs must be positive")
+	}
+	length := config.TradesPerRun
+	if length <= 0 {
+		length = len(trades)
+	}
+	if config.Method == MonteCarloShuffle && length > len(trades) {
+		return nil, fmt.Errorf("monte carlo: shuffle cannot produce %d trades from %d", length, len(trades))
+	}
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	result := &MonteCarloResult{
+		Config:     config,
+		Trades:     len(trades),
+		Historical: simulateSequence(trades, config),
+	}
+
+	profits := make([]float64, config.Runs)
+	returns := make([]float64, config.Runs)
+	drawdowns := make([]float64, config.Runs)
+	drawdownPercents := make([]float64, config.Runs)
+	streaks := make([]float64, config.Runs)
+	var ruined, profitable int
+
+	sequence := make([]float64, length)
+	pool := append([]float64(nil), trades...)
+	for i := 0; i < config.Runs; i++ {
+		switch config.Method {
+		case MonteCarloBootstrap:
+			for j := range sequence {
+				sequence[j] = trades[rng.Intn(len(trades))]
+			}
+		default:
+			rng.Shuffle(len(pool), func(a, b int) { pool[a], pool[b] = pool[b], pool[a] })
+			copy(sequence, pool)
+		}
+
+		run := simulateSequence(sequence, config)
+		profits[i] = run.NetProfit
+		returns[i] = run.ReturnPercent
+		drawdowns[i] = run.MaxDrawdown
+		drawdownPercents[i] = run.MaxDrawdownPercent
+		streaks[i] = float64(run.LongestLosingStreak)
+		if run.Ruined {
+			ruined++
+		}
+		if run.NetProfit > 0 {
+			profitable++
+		}
+	}
+
+	result.NetProfit = newDistribution(profits)
+	result.ReturnPercent = newDistribution(returns)
+	result.MaxDrawdown = newDistribution(drawdowns)
+	result.MaxDrawdownPercent = newDistribution(drawdownPercents)
+	result.LosingStreak = newDistribution(streaks)
+	result.RuinProbability = float64(ruined) / float64(config.Runs)
+	result.ProfitProbability = float64(profitable) / float64(config.Runs)
+	return result, nil
+}
+
+// simulateSequence walks the equity curve of one trade sequence.
+func simulateSequence(trades []float64, config MonteCarloConfig) MonteCarloRun {
+	var run MonteCarloRun
+	equity := config.StartingEquity
+	peak := equity
+	streak := 0
+
+	for _, pnl := range trades {
+		equity += pnl
+		run.NetProfit += pnl
+
+		if pnl < 0 {
+			streak++
+			if streak > run.LongestLosingStreak {
+				run.LongestLosingStreak = streak
+			}
+		} else {
+			streak = 0
+		}
+
+		if equity > peak {
+			peak = equity
+			continue
+		}
+		drawdown := peak - equity
+		if drawdown > run.MaxDrawdown {
+			run.MaxDrawdown = drawdown
+		}
+		if peak > 0 {
+			percent := drawdown / peak * 100
+			if percent > run.MaxDrawdownPercent {
+				run.MaxDrawdownPercent = percent
+			}
+		}
+	}
+
+	run.ReturnPercent = run.NetProfit / config.StartingEquity * 100
+	run.Ruined = config.RuinDrawdownPercent > 0 && run.MaxDrawdownPercent >= config.RuinDrawdownPercent
+	return run
+}
+
+// MonteCarloFromHistory runs RunMonteCarlo on the net P/L (profit + swap +
+// commission + fee) of the positions closed in [from, to], in close order.
+//
+// Parameters:
+//   - from, to: History period
+//   - config: Simulation settings (StartingEquity must be positive)
+//
+// Returns:
+//   - Simulated distributions, or error if history cannot be read or is empty
+func (s *MT5Sugar) MonteCarloFromHistory(from, to time.Time, config MonteCarloConfig) (*MonteCarloResult, error) {
+	_, closed, err := s.summarizeClosed(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("MonteCarloFromHistory failed: %w", err)
+	}
+	sort.SliceStable(closed, func(i, j int) bool {
+		return closed[i].info.CloseTime.AsTime().Before(closed[j].info.CloseTime.AsTime())
+	})
+
+	trades := make([]float64, len(closed))
+	for i, c := range closed {
+		trades[i] = c.net
+	}
+	result, err := RunMonteCarlo(trades, config)
+	if err != nil {
+		return nil, fmt.Errorf("MonteCarloFromHistory failed: %w", err)
+	}
+	return result, nil
+}
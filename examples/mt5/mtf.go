@@ -0,0 +1,383 @@
+package mt5
+
+/*
+MTF - indicators of one symbol on several timeframes, aligned at bar close.
+
+Mixing timeframes (H1 trend filter, M5 entries) invites two classic bugs:
+reading the H1 value of a bar that is still forming (the filter repaints
+during the hour), or reading the H1 value one bar late because the M5 close
+at 10:00 is processed before the H1 close at 10:00. MTF avoids both:
+
+  • Values are computed from CLOSED bars only; a forming bar never counts.
+  • All timeframes are built from the same ticks (or base candles). When a
+    tick closes bars on several timeframes, every timeframe is updated
+    before any event is delivered, so the M5 event of 10:00 already sees
+    the H1 bar 09:00-10:00.
+  • Events of one tick are delivered higher timeframe first, each with a
+    snapshot of every timeframe at that moment.
+
+Bars are aligned like BarTime (server timezone and session start), so H4
+and D1 match the broker's chart.
+
+Usage:
+    mtf := mt5.NewMTF("EURUSD", serverLoc, 0)
+    mtf.AddIndicator(time.Hour, "sma50", mt5.SMAIndicator(50))
+    mtf.AddIndicator(5*time.Minute, "sma10", mt5.SMAIndicator(10))
+    mtf.AddIndicator(5*time.Minute, "atr", mt5.ATRIndicator(14))
+
+    events := mtf.Subscribe(64)
+    go mtf.Run(ctx, service)
+
+    for ev := range events {
+        if ev.Timeframe != 5*time.Minute {
+            continue
+        }
+        trend, ok1 := ev.Snapshot.Value(time.Hour, "sma50")
+        fast, ok2 := ev.Snapshot.Value(5*time.Minute, "sma10")
+        if ok1 && ok2 && ev.Bar.Close > trend && ev.Bar.Close > fast {
+            // long entry on an M5 close, in the direction of the H1 filter
+        }
+    }
+*/
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// IndicatorFunc computes an indicator from closed bars, oldest first.
+// ok is false while there are not enough bars.
+type IndicatorFunc func(bars []Candle) (value float64, ok bool)
+
+// SimpleMovingAverage returns the average close of the last period candles.
+// Returns 0 if there are fewer than period candles.
+func SimpleMovingAverage(candles []Candle, period int) float64 {
+	if period <= 0 || len(candles) < period {
+		return 0
+	}
+	sum := 0.0
+	for _, c := range candles[len(candles)-period:] {
+		sum += c.Close
+	}
+	return sum / float64(period)
+}
+
+// SMAIndicator is the simple moving average of closes over period bars.
+func SMAIndicator(period int) IndicatorFunc {
+	return func(bars []Candle) (float64, bool) {
+		if period <= 0 || len(bars) < period {
+			return 0, false
+		}
+		return SimpleMovingAverage(bars, period), true
+	}
+}
+
+// ATRIndicator is the average true range over period bars (see AverageTrueRange).
+func ATRIndicator(period int) IndicatorFunc {
+	return func(bars []Candle) (float64, bool) {
+		if period <= 0 || len(bars) < period+1 {
+			return 0, false
+		}
+		return AverageTrueRange(bars, period), true
+	}
+}
+
+// MTFSnapshot holds the indicator values of every timeframe at one moment.
+type MTFSnapshot struct {
+	Symbol string
+	Time   time.Time                            // Time of the last tick (or base candle) fed
+	Values map[time.Duration]map[string]float64 // Timeframe → indicator → value (ready indicators only)
+	Bars   map[time.Duration]Candle             // Timeframe → last closed bar
+}
+
+// Value returns an indicator value; ok is false if the timeframe or
+// indicator is unknown or not warmed up yet.
+func (s MTFSnapshot) Value(timeframe time.Duration, name string) (float64, bool) {
+	v, ok := s.Values[timeframe][name]
+	return v, ok
+}
+
+// MTFEvent is delivered each time a bar closes on one of the timeframes.
+type MTFEvent struct {
+	Timeframe time.Duration
+	Bar       Candle      // The bar that closed
+	Snapshot  MTFSnapshot // All timeframes, including bars closed by the same tick
+}
+
+// mtfFrame is one timeframe with its bars and indicators.
+type mtfFrame struct {
+	timeframe  time.Duration
+	current    *Candle  // Forming bar (never used by indicators)
+	bars       []Candle // Closed bars, oldest first
+	indicators map[string]IndicatorFunc
+	values     map[string]float64
+}
+
+// MTF keeps aligned indicator values of one symbol on several timeframes.
+// Safe for concurrent use.
+type MTF struct {
+	symbol       string
+	loc          *time.Location
+	sessionStart time.Duration
+
+	mu          sync.Mutex
+	keep        int
+	last        time.Time // Time of the last tick or candle fed
+	frames      map[time.Duration]*mtfFrame
+	subscribers []chan MTFEvent
+}
+
+// NewMTF creates a helper for symbol with bars aligned to sessionStart after
+// midnight in loc (the broker's server timezone; nil = UTC).
+func NewMTF(symbol string, loc *time.Location, sessionStart time.Duration) *MTF {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return &MTF{
+		symbol:       symbol,
+		loc:          loc,
+		sessionStart: sessionStart,
+		keep:         500,
+		frames:       make(map[time.Duration]*mtfFrame),
+	}
+}
+
+// SetHistory sets how many closed bars are kept per timeframe (default 500).
+// It must cover the longest indicator period.
+func (m *MTF) SetHistory(bars int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if bars > 0 {
+		m.keep = bars
+	}
+}
+
+// frameLocked returns the frame of a timeframe, creating it. Caller holds m.mu.
+func (m *MTF) frameLocked(timeframe time.Duration) *mtfFrame {
+	frame, ok := m.frames[timeframe]
+	if !ok {
+		frame = &mtfFrame{
+			timeframe:  timeframe,
+			indicators: make(map[string]IndicatorFunc),
+			values:     make(map[string]float64),
+		}
+		m.frames[timeframe] = frame
+	}
+	return frame
+}
+
+// AddTimeframe tracks a timeframe without indicators (bars only).
+func (m *MTF) AddTimeframe(timeframe time.Duration) {
+	if timeframe <= 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.frameLocked(timeframe)
+}
+
+// AddIndicator computes fn on every close of timeframe under name.
+// Add indicators before feeding data; an indicator added later is computed
+// from the bars already closed at the next close.
+func (m *MTF) AddIndicator(timeframe time.Duration, name string, fn IndicatorFunc) {
+	if timeframe <= 0 || fn == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.frameLocked(timeframe).indicators[name] = fn
+}
+
+// Subscribe returns a channel receiving every bar close event.
+// Events are dropped for a subscriber whose buffer is full.
+func (m *MTF) Subscribe(buffer int) <-chan MTFEvent {
+	ch := make(chan MTFEvent, buffer)
+	m.mu.Lock()
+	m.subscribers = append(m.subscribers, ch)
+	m.mu.Unlock()
+	return ch
+}
+
+// Observe feeds a tick (Bid price). Ticks of other symbols are ignored.
+//
+// Returns:
+//   - Events of the bars closed by this tick, higher timeframe first
+//     (also delivered to subscribers)
+func (m *MTF) Observe(tick *SymbolTick) []MTFEvent {
+	if tick == nil || tick.Bid <= 0 || (tick.Symbol != "" && tick.Symbol != m.symbol) {
+		return nil
+	}
+	return m.feed(Candle{
+		Time: tick.Time, Open: tick.Bid, High: tick.Bid, Low: tick.Bid, Close: tick.Bid,
+		TickVolume: 1, RealVolume: tick.VolumeReal,
+	})
+}
+
+// AddCandles feeds base candles in chronological order, e.g. M1 bars from a
+// CandleCache to warm up before Run. Their timeframe must divide every
+// tracked timeframe.
+//
+// Returns:
+//   - Events of all bars closed, in order
+func (m *MTF) AddCandles(candles []Candle) []MTFEvent {
+	var events []MTFEvent
+	for _, c := range candles {
+		events = append(events, m.feed(c)...)
+	}
+	return events
+}
+
+// feed merges a price segment into every timeframe, then computes and
+// delivers the events of the bars it closed.
+func (m *MTF) feed(c Candle) []MTFEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c.Time.After(m.last) {
+		m.last = c.Time
+	}
+
+	var closed []*mtfFrame
+	var closedBars []Candle
+	for _, frame := range m.frames {
+		barTime := BarTime(c.Time, frame.timeframe, m.loc, m.sessionStart)
+		cur := frame.current
+
+		if cur != nil && barTime.Before(cur.Time) {
+			continue // Out of order
+		}
+		if cur != nil && barTime.Equal(cur.Time) {
+			if c.High > cur.High {
+				cur.High = c.High
+			}
+			if c.Low < cur.Low {
+				cur.Low = c.Low
+			}
+			cur.Close = c.Close
+			cur.TickVolume += c.TickVolume
+			cur.RealVolume += c.RealVolume
+			continue
+		}
+
+		if cur != nil {
+			frame.bars = append(frame.bars, *cur)
+			if len(frame.bars) > m.keep {
+				frame.bars = frame.bars[len(frame.bars)-m.keep:]
+			}
+			closed = append(closed, frame)
+			closedBars = append(closedBars, *cur)
+		}
+		bar := c
+		bar.Symbol, bar.Time, bar.Timeframe = m.symbol, barTime, frame.timeframe
+		frame.current = &bar
+	}
+	if len(closed) == 0 {
+		return nil
+	}
+
+	// Update every closed timeframe before building any snapshot
+	for _, frame := range closed {
+		for name, fn := range frame.indicators {
+			if v, ok := fn(frame.bars); ok {
+				frame.values[name] = v
+			} else {
+				delete(frame.values, name)
+			}
+		}
+	}
+
+	snapshot := m.snapshotLocked()
+	events := make([]MTFEvent, len(closed))
+	for i, frame := range closed {
+		events[i] = MTFEvent{Timeframe: frame.timeframe, Bar: closedBars[i], Snapshot: snapshot}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Timeframe > events[j].Timeframe })
+
+	for _, ev := range events {
+		for _, ch := range m.subscribers {
+			select {
+			case ch <- ev:
+			default:
+			}
+		}
+	}
+	return events
+}
+
+// snapshotLocked copies the values and last bars. Caller holds m.mu.
+func (m *MTF) snapshotLocked() MTFSnapshot {
+	snapshot := MTFSnapshot{
+		Symbol: m.symbol,
+		Time:   m.last,
+		Values: make(map[time.Duration]map[string]float64, len(m.frames)),
+		Bars:   make(map[time.Duration]Candle, len(m.frames)),
+	}
+	for tf, frame := range m.frames {
+		values := make(map[string]float64, len(frame.values))
+		for name, v := range frame.values {
+			values[name] = v
+		}
+		snapshot.Values[tf] = values
+		if n := len(frame.bars); n > 0 {
+			snapshot.Bars[tf] = frame.bars[n-1]
+		}
+	}
+	return snapshot
+}
+
+// Snapshot returns the values of the last closed bars of every timeframe.
+func (m *MTF) Snapshot() MTFSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.snapshotLocked()
+}
+
+// Bars returns a copy of the closed bars of a timeframe, oldest first.
+func (m *MTF) Bars(timeframe time.Duration) []Candle {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	frame, ok := m.frames[timeframe]
+	if !ok {
+		return nil
+	}
+	return append([]Candle(nil), frame.bars...)
+}
+
+// Run streams ticks of the symbol into the helper until ctx is done or the
+// stream fails. Subscriber channels are closed when Run returns.
+func (m *MTF) Run(ctx context.Context, service *MT5Service) error {
+	defer m.closeSubscribers()
+
+	ticks, errs := service.StreamTicks(ctx, []string{m.symbol})
+	for {
+		select {
+		case tick, ok := <-ticks:
+			if !ok {
+				return nil
+			}
+			m.Observe(tick)
+		case err, ok := <-errs:
+			if !ok {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("MTF %s: %w", m.symbol, err)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// closeSubscribers closes and forgets all subscriber channels.
+func (m *MTF) closeSubscribers() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, ch := range m.subscribers {
+		close(ch)
+	}
+	m.subscribers = nil
+}
@@ -0,0 +1,286 @@
+package mt5
+
+/*
+Opened orders watch - add/remove/change deltas of positions and pending orders.
+
+Polling OpenedOrders returns every position and order on every call, even
+when nothing happened. WatchOpenedOrders listens to the lightweight tickets
+stream instead and only reads a full snapshot when it has to:
+
+  • Ticket disappeared  - Removed is emitted from the cached copy, no lookup
+  • New ticket          - one snapshot, Added for the new tickets (plus any
+                          Changed found on the way)
+  • Every interval      - one reconciling snapshot, catching changes that keep
+                          the ticket set (SL/TP, partial close, netting add-on)
+
+Changed is reported for trading-relevant fields only (volume, open price,
+SL/TP, stop-limit, state, expiration) - not for current price or profit,
+which change on every tick.
+
+The first snapshot is delivered as Added for everything already open, so a
+consumer can build its state from the delta stream alone. If the tickets
+stream fails, the watch keeps going on the reconcile interval.
+
+Usage:
+    deltas, errs := service.WatchOpenedOrders(ctx, 30*time.Second)
+    for d := range deltas {
+        switch {
+        case d.Kind == mt5.DeltaAdded && d.Position != nil:
+            fmt.Printf("opened #%d %s %.2f\n", d.Ticket, d.Position.Symbol, d.Position.Volume)
+        case d.Kind == mt5.DeltaRemoved:
+            fmt.Printf("#%d gone\n", d.Ticket)
+        }
+    }
+*/
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// DeltaKind is the type of an opened orders delta.
+type DeltaKind int
+
+const (
+	DeltaAdded DeltaKind = iota
+	DeltaRemoved
+	DeltaChanged
+)
+
+func (k DeltaKind) String() string {
+	switch k {
+	case DeltaAdded:
+		return "added"
+	case DeltaRemoved:
+		return "removed"
+	case DeltaChanged:
+		return "changed"
+	}
+	return fmt.Sprintf("DeltaKind(%d)", int(k))
+}
+
+// OpenedOrdersDelta is one change of the open positions or pending orders.
+// Exactly one of Position / Order is set.
+type OpenedOrdersDelta struct {
+	Kind   DeltaKind
+	Ticket uint64
+	Time   time.Time
+
+	Position     *pb.PositionInfo    // Current position (last known for Removed)
+	PrevPosition *pb.PositionInfo    // Position before the change (Changed only)
+	Order        *pb.OpenedOrderInfo // Current pending order (last known for Removed)
+	PrevOrder    *pb.OpenedOrderInfo // Order before the change (Changed only)
+}
+
+// IsPosition reports whether the delta is about a position (not a pending order).
+func (d OpenedOrdersDelta) IsPosition() bool {
+	return d.Position != nil
+}
+
+// openedOrdersState is the last known snapshot, by ticket.
+type openedOrdersState struct {
+	positions map[uint64]*pb.PositionInfo
+	orders    map[uint64]*pb.OpenedOrderInfo
+}
+
+// positionChanged compares the trading-relevant fields of a position.
+func positionChanged(a, b *pb.PositionInfo) bool {
+	return a.Volume != b.Volume || a.PriceOpen != b.PriceOpen ||
+		a.StopLoss != b.StopLoss || a.TakeProfit != b.TakeProfit ||
+		a.Type != b.Type
+}
+
+// orderChanged compares the trading-relevant fields of a pending order.
+func orderChanged(a, b *pb.OpenedOrderInfo) bool {
+	return a.VolumeCurrent != b.VolumeCurrent || a.PriceOpen != b.PriceOpen ||
+		a.StopLimit != b.StopLimit || a.StopLoss != b.StopLoss ||
+		a.TakeProfit != b.TakeProfit || a.State != b.State ||
+		a.TimeExpiration.AsTime() != b.TimeExpiration.AsTime()
+}
+
+// apply diffs a full snapshot against the state, updates the state and
+// returns the deltas.
+func (st *openedOrdersState) apply(data *pb.OpenedOrdersData) []OpenedOrdersDelta {
+	now := time.Now()
+	var deltas []OpenedOrdersDelta
+
+	positions := make(map[uint64]*pb.PositionInfo, len(data.PositionInfos))
+	for _, pos := range data.PositionInfos {
+		positions[pos.Ticket] = pos
+		prev, ok := st.positions[pos.Ticket]
+		switch {
+		case !ok:
+			deltas = append(deltas, OpenedOrdersDelta{Kind: DeltaAdded, Ticket: pos.Ticket, Time: now, Position: pos})
+		case positionChanged(prev, pos):
+			deltas = append(deltas, OpenedOrdersDelta{Kind: DeltaChanged, Ticket: pos.Ticket, Time: now, Position: pos, PrevPosition: prev})
+		}
+	}
+	for ticket, prev := range st.positions {
+		if _, ok := positions[ticket]; !ok {
+			deltas = append(deltas, OpenedOrdersDelta{Kind: DeltaRemoved, Ticket: ticket, Time: now, Position: prev})
+		}
+	}
+
+	orders := make(map[uint64]*pb.OpenedOrderInfo, len(data.OpenedOrders))
+	for _, order := range data.OpenedOrders {
+		orders[order.Ticket] = order
+		prev, ok := st.orders[order.Ticket]
+		switch {
+		case !ok:
+			deltas = append(deltas, OpenedOrdersDelta{Kind: DeltaAdded, Ticket: order.Ticket, Time: now, Order: order})
+		case orderChanged(prev, order):
+			deltas = append(deltas, OpenedOrdersDelta{Kind: DeltaChanged, Ticket: order.Ticket, Time: now, Order: order, PrevOrder: prev})
+		}
+	}
+	for ticket, prev := range st.orders {
+		if _, ok := orders[ticket]; !ok {
+			deltas = append(deltas, OpenedOrdersDelta{Kind: DeltaRemoved, Ticket: ticket, Time: now, Order: prev})
+		}
+	}
+
+	st.positions, st.orders = positions, orders
+	return deltas
+}
+
+// applyTickets removes tickets missing from a tickets event and reports
+// whether the event contains tickets not in the state (a snapshot is needed).
+func (st *openedOrdersState) applyTickets(data *pb.OnPositionsAndPendingOrdersTicketsData) ([]OpenedOrdersDelta, bool) {
+	now := time.Now()
+	var deltas []OpenedOrdersDelta
+	unknown := false
+
+	positions := make(map[uint64]bool, len(data.PositionTickets))
+	for _, ticket := range data.PositionTickets {
+		positions[ticket] = true
+		if _, ok := st.positions[ticket]; !ok {
+			unknown = true
+		}
+	}
+	for ticket, prev := range st.positions {
+		if !positions[ticket] {
+			deltas = append(deltas, OpenedOrdersDelta{Kind: DeltaRemoved, Ticket: ticket, Time: now, Position: prev})
+			delete(st.positions, ticket)
+		}
+	}
+
+	orders := make(map[uint64]bool, len(data.PendingOrderTickets))
+	for _, ticket := range data.PendingOrderTickets {
+		orders[ticket] = true
+		if _, ok := st.orders[ticket]; !ok {
+			unknown = true
+		}
+	}
+	for ticket, prev := range st.orders {
+		if !orders[ticket] {
+			deltas = append(deltas, OpenedOrdersDelta{Kind: DeltaRemoved, Ticket: ticket, Time: now, Order: prev})
+			delete(st.orders, ticket)
+		}
+	}
+
+	return deltas, unknown
+}
+
+// WatchOpenedOrders streams add/remove/change deltas of open positions and
+// pending orders, driven by the tickets stream with periodic reconciliation.
+//
+// Parameters:
+//   - ctx: Context for cancellation (closing ctx stops the watch)
+//   - interval: Reconcile interval for changes that keep the ticket set (default 30s)
+//
+// Returns:
+//   - Read-only channel of OpenedOrdersDelta
+//   - Read-only channel of errors (the watch continues after errors)
+func (s *MT5Service) WatchOpenedOrders(ctx context.Context, interval time.Duration) (<-chan OpenedOrdersDelta, <-chan error) {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	deltaCh := make(chan OpenedOrdersDelta, 64)
+	errCh := make(chan error, 4)
+
+	go func() {
+		defer close(deltaCh)
+		defer close(errCh)
+
+		report := func(err error) {
+			select {
+			case errCh <- err:
+			default:
+			}
+		}
+		emit := func(deltas []OpenedOrdersDelta) bool {
+			for _, d := range deltas {
+				select {
+				case deltaCh <- d:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			return true
+		}
+
+		state := &openedOrdersState{
+			positions: make(map[uint64]*pb.PositionInfo),
+			orders:    make(map[uint64]*pb.OpenedOrderInfo),
+		}
+		snapshot := func() bool {
+			data, err := s.GetOpenedOrders(ctx, pb.BMT5_ENUM_OPENED_ORDER_SORT_TYPE_BMT5_OPENED_ORDER_SORT_BY_OPEN_TIME_ASC)
+			if err != nil {
+				if ctx.Err() == nil {
+					report(fmt.Errorf("WatchOpenedOrders failed: %w", err))
+				}
+				return true
+			}
+			return emit(state.apply(data))
+		}
+
+		ticketCh, streamErrCh := s.account.OnPositionsAndPendingOrdersTickets(ctx, &pb.OnPositionsAndPendingOrdersTicketsRequest{
+			TimerPeriodMilliseconds: 500,
+		})
+
+		if !snapshot() {
+			return
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-ticker.C:
+				if !snapshot() {
+					return
+				}
+
+			case data, ok := <-ticketCh:
+				if !ok {
+					ticketCh = nil
+					continue
+				}
+				removed, unknown := state.applyTickets(data)
+				if !emit(removed) {
+					return
+				}
+				if unknown && !snapshot() {
+					return
+				}
+
+			case err, ok := <-streamErrCh:
+				if !ok {
+					streamErrCh = nil
+					continue
+				}
+				if err != nil && ctx.Err() == nil {
+					report(fmt.Errorf("WatchOpenedOrders tickets stream: %w (continuing on %s reconcile)", err, interval))
+				}
+			}
+		}
+	}()
+
+	return deltaCh, errCh
+}
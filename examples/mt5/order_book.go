@@ -0,0 +1,225 @@
+package mt5
+
+/*
+OrderBookAnalyzer - imbalance and depth-weighted mid-price from Depth of Market.
+
+Each DOM snapshot is reduced to a signal over the best N levels per side:
+  • Imbalance    - (bid volume - ask volume) / (bid volume + ask volume), -1..+1;
+                   positive = more resting buy interest
+  • WeightedMid  - best bid and ask weighted by the OPPOSITE side's depth
+                   (micro-price): leans towards the side that is about to be taken
+
+The gRPC API has no DOM stream, so Stream subscribes to market depth, polls
+the book and emits a signal whenever it changes - consumed like StreamTicks.
+
+Usage:
+    analyzer := mt5.NewOrderBookAnalyzer(service, 5)
+    signals, errs := analyzer.Stream(ctx, []string{"EURUSD"}, 200*time.Millisecond)
+    for {
+        select {
+        case s := <-signals:
+            if s.Imbalance > 0.6 && s.WeightedMid > s.Mid { ... }
+        case err := <-errs:
+            log.Println(err)
+        }
+    }
+*/
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// OrderBookSignal is the analysis of one DOM snapshot.
+type OrderBookSignal struct {
+	Symbol      string
+	Time        time.Time
+	BestBid     float64
+	BestAsk     float64
+	BidVolume   float64 // Volume of the best Levels bid levels
+	AskVolume   float64 // Volume of the best Levels ask levels
+	BidLevels   int     // Bid levels used (may be fewer than Levels)
+	AskLevels   int     // Ask levels used
+	Imbalance   float64 // (BidVolume - AskVolume) / (BidVolume + AskVolume), -1..+1
+	Mid         float64 // (BestBid + BestAsk) / 2
+	WeightedMid float64 // Depth-weighted mid (micro-price)
+}
+
+// OrderBookAnalyzer turns Depth of Market snapshots into imbalance signals.
+type OrderBookAnalyzer struct {
+	service *MT5Service
+	levels  int
+}
+
+// NewOrderBookAnalyzer creates an analyzer.
+//
+// Parameters:
+//   - service: MT5Service used to subscribe to and read market depth
+//   - levels: Price levels per side included in volumes (0 = whole book)
+func NewOrderBookAnalyzer(service *MT5Service, levels int) *OrderBookAnalyzer {
+	return &OrderBookAnalyzer{service: service, levels: levels}
+}
+
+// Analyze computes the signal of a DOM snapshot. Market-order entries
+// (BOOK_TYPE_*_MARKET) have no price and are ignored.
+func (a *OrderBookAnalyzer) Analyze(symbol string, book []BookInfo) OrderBookSignal {
+	signal := OrderBookSignal{Symbol: symbol, Time: time.Now()}
+
+	var bids, asks []BookInfo
+	for _, entry := range book {
+		switch entry.Type {
+		case pb.BookType_BOOK_TYPE_BUY:
+			bids = append(bids, entry)
+		case pb.BookType_BOOK_TYPE_SELL:
+			asks = append(asks, entry)
+		}
+	}
+	sort.Slice(bids, func(i, j int) bool { return bids[i].Price > bids[j].Price })
+	sort.Slice(asks, func(i, j int) bool { return asks[i].Price < asks[j].Price })
+	if a.levels > 0 {
+		if len(bids) > a.levels {
+			bids = bids[:a.levels]
+		}
+		if len(asks) > a.levels {
+			asks = asks[:a.levels]
+		}
+	}
+
+	signal.BidLevels = len(bids)
+	signal.AskLevels = len(asks)
+	for _, b := range bids {
+		signal.BidVolume += bookVolume(b)
+	}
+	for _, s := range asks {
+		signal.AskVolume += bookVolume(s)
+	}
+	if total := signal.BidVolume + signal.AskVolume; total > 0 {
+		signal.Imbalance = (signal.BidVolume - signal.AskVolume) / total
+	}
+
+	if len(bids) == 0 || len(asks) == 0 {
+		return signal
+	}
+	signal.BestBid = bids[0].Price
+	signal.BestAsk = asks[0].Price
+	signal.Mid = (signal.BestBid + signal.BestAsk) / 2
+	signal.WeightedMid = signal.Mid
+	if total := signal.BidVolume + signal.AskVolume; total > 0 {
+		signal.WeightedMid = (signal.BestBid*signal.AskVolume + signal.BestAsk*signal.BidVolume) / total
+	}
+	return signal
+}
+
+// bookVolume returns the volume of a DOM entry, preferring the decimal value.
+func bookVolume(entry BookInfo) float64 {
+	if entry.VolumeReal > 0 {
+		return entry.VolumeReal
+	}
+	return float64(entry.Volume)
+}
+
+// Snapshot reads the current DOM of a subscribed symbol and analyzes it.
+func (a *OrderBookAnalyzer) Snapshot(ctx context.Context, symbol string) (*OrderBookSignal, error) {
+	book, err := a.service.GetMarketDepth(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	signal := a.Analyze(symbol, book)
+	return &signal, nil
+}
+
+// Stream subscribes to market depth of the symbols and emits a signal each
+// time a symbol's book changes. Depth subscriptions are released when ctx
+// is cancelled.
+//
+// Parameters:
+//   - ctx: Context for cancellation (closing ctx stops the stream)
+//   - symbols: Symbols to analyze
+//   - interval: DOM poll interval per round (default 250ms)
+//
+// Returns:
+//   - Read-only channel of *OrderBookSignal
+//   - Read-only channel of errors (polling continues after read errors)
+func (a *OrderBookAnalyzer) Stream(ctx context.Context, symbols []string, interval time.Duration) (<-chan *OrderBookSignal, <-chan error) {
+	if interval <= 0 {
+		interval = 250 * time.Millisecond
+	}
+	signalCh := make(chan *OrderBookSignal, 64)
+	errCh := make(chan error, 4)
+
+	go func() {
+		defer close(signalCh)
+		defer close(errCh)
+
+		report := func(err error) {
+			select {
+			case errCh <- err:
+			default:
+			}
+		}
+
+		var subscribed []string
+		for _, symbol := range symbols {
+			if ok, err := a.service.SubscribeMarketDepth(ctx, symbol); err != nil || !ok {
+				report(fmt.Errorf("OrderBookAnalyzer: subscribe %s failed: %v", symbol, err))
+				continue
+			}
+			subscribed = append(subscribed, symbol)
+		}
+		defer func() {
+			releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			for _, symbol := range subscribed {
+				a.service.UnsubscribeMarketDepth(releaseCtx, symbol)
+			}
+		}()
+		if len(subscribed) == 0 {
+			return
+		}
+
+		last := make(map[string]OrderBookSignal)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			for _, symbol := range subscribed {
+				signal, err := a.Snapshot(ctx, symbol)
+				if err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+					report(fmt.Errorf("OrderBookAnalyzer: %s: %w", symbol, err))
+					continue
+				}
+				if prev, ok := last[symbol]; ok && sameBook(prev, *signal) {
+					continue
+				}
+				last[symbol] = *signal
+
+				select {
+				case signalCh <- signal:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return signalCh, errCh
+}
+
+// sameBook reports whether two signals describe an unchanged book top.
+func sameBook(a, b OrderBookSignal) bool {
+	return a.BestBid == b.BestBid && a.BestAsk == b.BestAsk &&
+		a.BidVolume == b.BidVolume && a.AskVolume == b.AskVolume
+}
@@ -0,0 +1,441 @@
+package mt5
+
+/*
+PlaceAndConfirm - send an order, then wait for the broker's final word on it.
+
+The synchronous OrderSend reply says the request was accepted; it is not the
+end of the story. A market order can still be filled partially, a pending
+order rejected by the exchange behind the broker, and a reply lost to a
+timeout says nothing at all. PlaceAndConfirm opens the OnTradeTransaction
+stream before sending and follows the order ticket until its state is final:
+
+  • Market orders: filled (deals for the full volume), partial (order
+    closed with only part filled), rejected or canceled
+  • Pending orders: placed (on the book), rejected or canceled
+
+The subscription goes live asynchronously, so the first transactions of a
+fast fill can be missed. At the deadline the order is therefore looked up in
+order history and the open orders, and a TRADE_RETCODE_DONE reply counts as
+filled. Only if none of them settles it is the last state seen returned with
+ErrOrderUnconfirmed; the synchronous reply is in Reply either way.
+
+Usage:
+    conf, err := sugar.PlaceAndConfirm(mt5.OrderRequest{Symbol: "EURUSD", Type: buy, Volume: 0.1}, 3*time.Second)
+    switch {
+    case errors.Is(err, mt5.ErrOrderUnconfirmed):
+        log.Printf("order #%d still %s, check before retrying", conf.Order, conf.State)
+    case err != nil:
+        log.Printf("failed: %v", err)
+    default:
+        log.Printf("%s: %.2f @ %.5f, position #%d", conf.State, conf.Volume, conf.Price, conf.Position)
+    }
+*/
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+	helpers "github.com/MetaRPC/GoMT5/package/Helpers"
+)
+
+// ErrOrderUnconfirmed is returned by PlaceAndConfirm when the order reached
+// no final state before the deadline.
+var ErrOrderUnconfirmed = errors.New("order not confirmed before deadline")
+
+// ErrOrderCanceled is returned by PlaceAndConfirm when the broker canceled,
+// expired or rejected the order after accepting the request.
+var ErrOrderCanceled = errors.New("order canceled by broker")
+
+// ConfirmState is the state of an order as reported by trade transactions.
+type ConfirmState int
+
+const (
+	ConfirmUnknown  ConfirmState = iota // No transaction for the order seen yet
+	ConfirmPlaced                       // Pending order accepted and on the book
+	ConfirmPartial                      // Part of the volume filled
+	ConfirmFilled                       // Full volume filled
+	ConfirmRejected                     // Rejected by broker
+	ConfirmCanceled                     // Canceled or expired without a fill
+)
+
+func (c ConfirmState) String() string {
+	switch c {
+	case ConfirmPlaced:
+		return "placed"
+	case ConfirmPartial:
+		return "partial"
+	case ConfirmFilled:
+		return "filled"
+	case ConfirmRejected:
+		return "rejected"
+	case ConfirmCanceled:
+		return "canceled"
+	default:
+		return "unknown"
+	}
+}
+
+// OrderConfirmation is the outcome of PlaceAndConfirm.
+type OrderConfirmation struct {
+	State    ConfirmState
+	Order    uint64        // Order ticket
+	Deals    []uint64      // Deal tickets of the fills
+	Position uint64        // Position opened or changed by the fills
+	Volume   float64       // Filled volume
+	Price    float64       // Volume-weighted fill price
+	RetCode  uint32        // Return code (from the REQUEST transaction when seen)
+	Reply    SendResult    // Synchronous OrderSend reply
+	Latency  time.Duration // Send to final state (or deadline)
+
+	requested float64
+	pending   bool
+	seenDeals map[uint64]bool
+	rejection *SendResult // REQUEST transaction result, if it carried a rejection
+}
+
+// PlaceAndConfirm sends an order and waits on the trade transaction stream
+// until the broker reports its final state, for at most deadline (default 5s).
+//
+// The stream is opened before the order is sent, but the subscription goes
+// live asynchronously and early transactions of a fast fill can be missed.
+// If the final state is not seen by the deadline, it is taken from order
+// history, the open orders, or a TRADE_RETCODE_DONE reply.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - req: Order description
+//   - deadline: How long to wait for the final state after sending
+//
+// Returns:
+//   - OrderConfirmation with the confirmed state, fills and synchronous reply
+//   - *TradeRejectedError if the request was rejected, ErrOrderCanceled if the
+//     order was canceled after acceptance, ErrOrderUnconfirmed on deadline
+func (s *MT5Service) PlaceAndConfirm(ctx context.Context, req OrderRequest, deadline time.Duration) (*OrderConfirmation, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("PlaceAndConfirm failed: %w", err)
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	txCh, errCh := s.StreamTransactions(streamCtx)
+
+	sent := time.Now()
+	result, err := s.SendOrder(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("PlaceAndConfirm failed: %w", err)
+	}
+	return confirmOrder(ctx, s, req, result, sent, deadline, txCh, errCh)
+}
+
+// PlaceAndConfirm sends an order through the same checks as SendOrder and
+// waits on the trade transaction stream until the broker reports its final
+// state: filled, partial, placed (pending orders), rejected or canceled.
+// Returns ErrOrderUnconfirmed with the last state seen if none arrives
+// within deadline (default 5s). Uses 10-second timeout plus deadline.
+func (s *MT5Sugar) PlaceAndConfirm(req OrderRequest, deadline time.Duration) (*OrderConfirmation, error) {
+	req.Symbol = s.ResolveSymbol(req.Symbol)
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("PlaceAndConfirm failed: %w", err)
+	}
+	if deadline <= 0 {
+		deadline = 5 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second+deadline)
+	defer cancel()
+
+	if err := s.service.checkFilling(ctx, req); err != nil {
+		return nil, fmt.Errorf("PlaceAndConfirm failed: %w", err)
+	}
+
+	txCh, errCh := s.service.StreamTransactions(ctx)
+
+	sent := time.Now()
+	result, err := s.placeOrder(ctx, req.ToProto())
+	if err != nil {
+		return nil, fmt.Errorf("PlaceAndConfirm failed: %w", err)
+	}
+	return confirmOrder(ctx, s.service, req, result, sent, deadline, txCh, errCh)
+}
+
+// confirmOrder follows the order of result on the transaction stream until
+// its state is final or the deadline expires.
+func confirmOrder(ctx context.Context, service *MT5Service, req OrderRequest, result *OrderResult, sent time.Time, deadline time.Duration,
+	txCh <-chan *pb.OnTradeTransactionData, errCh <-chan error) (*OrderConfirmation, error) {
+
+	c := &OrderConfirmation{
+		Order:     result.Order,
+		RetCode:   result.ReturnedCode,
+		Reply:     result.SendResult,
+		requested: req.Volume,
+		pending:   !req.IsMarket(),
+		seenDeals: make(map[uint64]bool),
+	}
+
+	if !acceptedRetCode(result.ReturnedCode) {
+		c.State = ConfirmRejected
+		c.Latency = time.Since(sent)
+		return c, &TradeRejectedError{Result: result.SendResult}
+	}
+
+	if deadline <= 0 {
+		deadline = 5 * time.Second
+	}
+	timer := time.NewTimer(deadline)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.Latency = time.Since(sent)
+			return c, ctx.Err()
+
+		case err, ok := <-errCh:
+			if ok && err != nil {
+				c.Latency = time.Since(sent)
+				if c.resolve(ctx, service) {
+					return c, c.err()
+				}
+				return c, fmt.Errorf("transaction stream failed: %w", err)
+			}
+			errCh = nil
+
+		case data, ok := <-txCh:
+			if !ok {
+				txCh = nil
+				continue
+			}
+			if c.apply(data) {
+				c.Latency = time.Since(sent)
+				return c, c.err()
+			}
+
+		case <-timer.C:
+			c.Latency = time.Since(sent)
+			if c.resolve(ctx, service) {
+				return c, c.err()
+			}
+			return c, fmt.Errorf("%w: order #%d %s after %s", ErrOrderUnconfirmed, c.Order, c.State, deadline)
+		}
+	}
+}
+
+// apply folds one transaction into c and reports whether the state is final.
+func (c *OrderConfirmation) apply(data *pb.OnTradeTransactionData) bool {
+	tx := data.GetTradeTransaction()
+	if tx == nil {
+		return false
+	}
+
+	if tx.Type == pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_REQUEST {
+		r := data.GetTradeResult()
+		if r == nil || r.OrderTicket != c.Order || r.TradeReturnIntCode == 0 {
+			return false
+		}
+		c.RetCode = r.TradeReturnIntCode
+		if acceptedRetCode(r.TradeReturnIntCode) {
+			return false
+		}
+		rejection := newTransactionResult(r)
+		c.rejection = &rejection
+		c.State = ConfirmRejected
+		return true
+	}
+
+	if tx.OrderTicket != c.Order {
+		return false
+	}
+
+	switch tx.Type {
+	case pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_DEAL_ADD:
+		if tx.DealTicket == 0 || c.seenDeals[tx.DealTicket] {
+			return false
+		}
+		c.seenDeals[tx.DealTicket] = true
+		c.Deals = append(c.Deals, tx.DealTicket)
+		if tx.PositionTicket != 0 {
+			c.Position = tx.PositionTicket
+		}
+		if total := c.Volume + tx.Volume; total > 0 {
+			c.Price = (c.Price*c.Volume + tx.Price*tx.Volume) / total
+			c.Volume = total
+		}
+		if c.Volume >= c.requested-volumeEpsilon {
+			c.State = ConfirmFilled
+			return true
+		}
+		c.State = ConfirmPartial
+
+	case pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_ORDER_ADD,
+		pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_ORDER_UPDATE:
+		switch tx.OrderState {
+		case pb.SUB_ENUM_ORDER_STATE_SUB_ORDER_STATE_PLACED:
+			if c.pending {
+				c.State = ConfirmPlaced
+				return true
+			}
+		case pb.SUB_ENUM_ORDER_STATE_SUB_ORDER_STATE_PARTIAL:
+			c.State = ConfirmPartial
+		case pb.SUB_ENUM_ORDER_STATE_SUB_ORDER_STATE_REJECTED:
+			c.State = ConfirmRejected
+			return true
+		}
+
+	case pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_ORDER_DELETE,
+		pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_HISTORY_ADD:
+		// The order left the book: its state is final
+		switch tx.OrderState {
+		case pb.SUB_ENUM_ORDER_STATE_SUB_ORDER_STATE_FILLED:
+			// Final once the deals are in; MT5 usually sends them right after
+			c.State = ConfirmFilled
+			return c.Volume >= c.requested-volumeEpsilon
+		case pb.SUB_ENUM_ORDER_STATE_SUB_ORDER_STATE_PARTIAL,
+			pb.SUB_ENUM_ORDER_STATE_SUB_ORDER_STATE_CANCELED,
+			pb.SUB_ENUM_ORDER_STATE_SUB_ORDER_STATE_EXPIRED:
+			if c.Volume > volumeEpsilon {
+				c.State = ConfirmPartial
+			} else {
+				c.State = ConfirmCanceled
+			}
+			return true
+		case pb.SUB_ENUM_ORDER_STATE_SUB_ORDER_STATE_REJECTED:
+			c.State = ConfirmRejected
+			return true
+		}
+	}
+	return false
+}
+
+// err returns the error for a final state, nil for placed, filled and partial.
+func (c *OrderConfirmation) err() error {
+	switch c.State {
+	case ConfirmRejected:
+		if c.rejection != nil {
+			return &TradeRejectedError{Result: *c.rejection}
+		}
+		return fmt.Errorf("%w: order #%d rejected", ErrOrderCanceled, c.Order)
+	case ConfirmCanceled:
+		return fmt.Errorf("%w: order #%d canceled", ErrOrderCanceled, c.Order)
+	}
+	return nil
+}
+
+// resolve settles an order whose final transaction was not seen before the
+// deadline, from order history, the open orders or the synchronous reply,
+// and reports whether it did.
+func (c *OrderConfirmation) resolve(ctx context.Context, service *MT5Service) bool {
+	if c.State == ConfirmFilled {
+		// Order reported filled, deal transactions not (yet) seen
+		c.fillFromReply()
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	if order := historyOrder(ctx, service, c.Order); order != nil {
+		if c.Position == 0 {
+			c.Position = order.PositionId
+		}
+		filled := order.VolumeInitial - order.VolumeCurrent
+		switch order.State {
+		case pb.BMT5_ENUM_ORDER_STATE_BMT5_ORDER_STATE_FILLED:
+			c.State = ConfirmFilled
+			c.fillFromReply()
+			return true
+		case pb.BMT5_ENUM_ORDER_STATE_BMT5_ORDER_STATE_PARTIAL,
+			pb.BMT5_ENUM_ORDER_STATE_BMT5_ORDER_STATE_CANCELED,
+			pb.BMT5_ENUM_ORDER_STATE_BMT5_ORDER_STATE_EXPIRED:
+			if filled > volumeEpsilon {
+				c.State = ConfirmPartial
+				if c.Volume == 0 {
+					c.Volume, c.Price = filled, c.Reply.Price
+				}
+			} else {
+				c.State = ConfirmCanceled
+			}
+			return true
+		case pb.BMT5_ENUM_ORDER_STATE_BMT5_ORDER_STATE_REJECTED:
+			c.State = ConfirmRejected
+			return true
+		}
+	}
+
+	if c.pending {
+		if _, orders, err := service.GetOpenedTickets(ctx); err == nil {
+			for _, ticket := range orders {
+				if uint64(ticket) == c.Order {
+					c.State = ConfirmPlaced
+					return true
+				}
+			}
+		}
+	}
+
+	if c.Reply.ReturnedCode == helpers.TradeRetCodeDone && !c.pending {
+		// The reply of a market order already reports the full fill
+		c.State = ConfirmFilled
+		c.fillFromReply()
+		return true
+	}
+	return false
+}
+
+// historyOrder returns the order from recent order history, nil if it is
+// not there (yet) or history cannot be read. The window is wide because
+// history times are in server time.
+func historyOrder(ctx context.Context, service *MT5Service, ticket uint64) *pb.OrderHistoryData {
+	now := time.Now()
+	data, err := service.GetOrderHistory(ctx, now.Add(-2*24*time.Hour), now.Add(24*time.Hour),
+		pb.BMT5_ENUM_ORDER_HISTORY_SORT_TYPE_BMT5_SORT_BY_CLOSE_TIME_DESC, 1, 100)
+	if err != nil {
+		return nil
+	}
+	for _, item := range data.GetHistoryData() {
+		if order := item.GetHistoryOrder(); order != nil && order.Ticket == ticket {
+			return order
+		}
+	}
+	return nil
+}
+
+// fillFromReply takes the fill from the synchronous reply when no deal
+// transaction was seen.
+func (c *OrderConfirmation) fillFromReply() {
+	if c.Volume > 0 {
+		return
+	}
+	c.Volume, c.Price = c.Reply.Volume, c.Reply.Price
+	if c.Reply.Deal != 0 {
+		c.Deals = append(c.Deals, c.Reply.Deal)
+	}
+}
+
+// acceptedRetCode reports whether an OrderSend return code means the
+// request was accepted (done, partially done or placed).
+func acceptedRetCode(code uint32) bool {
+	return code == helpers.TradeRetCodeDone || code == helpers.TradeRetCodeDonePartial || code == helpers.TradeRetCodePlaced
+}
+
+// newTransactionResult maps the result of a REQUEST trade transaction.
+func newTransactionResult(r *pb.MqlTradeResult) SendResult {
+	return SendResult{
+		ReturnedCode:            r.TradeReturnIntCode,
+		ReturnedStringCode:      r.TradeReturnCode.String(),
+		ReturnedCodeDescription: retCodeDescription(r.TradeReturnIntCode, ""),
+		Deal:                    r.DealTicket,
+		Order:                   r.OrderTicket,
+		Volume:                  r.DealVolume,
+		Price:                   r.DealPrice,
+		Bid:                     r.CurrentBid,
+		Ask:                     r.CurrentAsk,
+		Comment:                 r.BrokerCommentToOperation,
+		RequestID:               r.TerminalDispatchRequestId,
+		RetCodeExternal:         r.ReturnCodeExternal,
+		op:                      "order",
+	}
+}
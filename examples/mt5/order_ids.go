@@ -0,0 +1,430 @@
+package mt5
+
+/*
+Order ID store - strategy order IDs mapped to broker tickets across restarts.
+
+Orchestrators name their orders ("grid-L5", "breakout-2024-06-03") but the
+broker only knows tickets, and one logical order can end up with several:
+
+  • A stop-limit order that triggers is replaced by a new limit order
+  • A filled order opens a position (POSITION_IDENTIFIER = order ticket)
+  • Partial fills and netting add deals to an existing position
+
+OrderIDStore keeps id → tickets in a JSON file, rewritten atomically on
+every change. After a restart, Readopt matches the stored IDs against the
+terminal: it follows filled orders to their positions (open positions and
+order history), picks up the limit order that replaced a triggered
+stop-limit (same symbol and magic, price = stop-limit price), and marks IDs
+whose orders and positions are all gone as closed.
+
+Usage:
+    store, err := mt5.NewOrderIDStore("grid-orders.json")
+
+    ticket, err := sugar.BuyLimit("EURUSD", 0.1, 1.0800)
+    store.Bind(mt5.OrderIDMapping{ID: "grid-L5", Symbol: "EURUSD", Magic: 1001}, ticket)
+
+    // On startup
+    result, err := sugar.Readopt(store)
+    for _, m := range result.Live {
+        fmt.Printf("%s: orders %v positions %v\n", m.ID, m.Orders, m.Positions)
+    }
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// ErrOrderIDNotFound is returned for IDs the store does not know.
+var ErrOrderIDNotFound = errors.New("order ID not found")
+
+// OrderIDMapping links a strategy order ID to its broker tickets.
+type OrderIDMapping struct {
+	ID        string    `json:"id"`                   // Strategy order ID
+	Symbol    string    `json:"symbol,omitempty"`     // Broker symbol name
+	Magic     int64     `json:"magic,omitempty"`      // Magic number of the orders (0 = any)
+	Orders    []uint64  `json:"orders"`               // Order tickets, original first
+	Positions []uint64  `json:"positions,omitempty"`  // Positions opened by the orders
+	StopLimit float64   `json:"stop_limit,omitempty"` // Limit price of a stop-limit order (0 = none)
+	Closed    bool      `json:"closed,omitempty"`     // No order or position left
+	Created   time.Time `json:"created"`
+	Updated   time.Time `json:"updated"`
+}
+
+// Order returns the latest order ticket (0 if none).
+func (m OrderIDMapping) Order() uint64 {
+	if len(m.Orders) == 0 {
+		return 0
+	}
+	return m.Orders[len(m.Orders)-1]
+}
+
+// clone returns a copy that shares no slices with m.
+func (m *OrderIDMapping) clone() OrderIDMapping {
+	c := *m
+	c.Orders = append([]uint64(nil), m.Orders...)
+	c.Positions = append([]uint64(nil), m.Positions...)
+	return c
+}
+
+// addTicket appends ticket to list unless present; reports whether it was added.
+func addTicket(list *[]uint64, ticket uint64) bool {
+	for _, t := range *list {
+		if t == ticket {
+			return false
+		}
+	}
+	*list = append(*list, ticket)
+	return true
+}
+
+// OrderIDStore keeps order ID mappings in a JSON file. Safe for concurrent use.
+type OrderIDStore struct {
+	path string
+
+	mu  sync.Mutex
+	ids map[string]*OrderIDMapping
+}
+
+// NewOrderIDStore opens the store at path. A missing file is an empty
+// store; it is created on the first change.
+func NewOrderIDStore(path string) (*OrderIDStore, error) {
+	st := &OrderIDStore{path: path, ids: make(map[string]*OrderIDMapping)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("order ID store: %w", err)
+	}
+	var list []*OrderIDMapping
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("order ID store: %s: %w", path, err)
+	}
+	for _, m := range list {
+		st.ids[m.ID] = m
+	}
+	return st, nil
+}
+
+// writeLocked rewrites the file atomically. Caller holds st.mu.
+func (st *OrderIDStore) writeLocked() error {
+	list := make([]*OrderIDMapping, 0, len(st.ids))
+	for _, m := range st.ids {
+		list = append(list, m)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("order ID store: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(st.path), filepath.Base(st.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("order ID store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("order ID store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("order ID store: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), st.path); err != nil {
+		return fmt.Errorf("order ID store: %w", err)
+	}
+	return nil
+}
+
+// update applies fn to the mapping of id and writes the file.
+func (st *OrderIDStore) update(id string, fn func(m *OrderIDMapping)) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	m, ok := st.ids[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrOrderIDNotFound, id)
+	}
+	fn(m)
+	m.Updated = time.Now()
+	return st.writeLocked()
+}
+
+// Bind records that order ticket belongs to m.ID and writes the file. A new
+// ID is created from m; for a known ID the ticket is added as its latest
+// order (e.g., a re-sent or replaced order) and the ID is reopened.
+//
+// Parameters:
+//   - m: ID plus optional Symbol, Magic and StopLimit (Orders/Positions are ignored)
+//   - ticket: Order ticket returned by the broker
+func (st *OrderIDStore) Bind(m OrderIDMapping, ticket uint64) error {
+	if strings.TrimSpace(m.ID) == "" {
+		return fmt.Errorf("order ID store: empty order ID")
+	}
+	if ticket == 0 {
+		return fmt.Errorf("order ID store: %s: zero ticket", m.ID)
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	now := time.Now()
+	existing, ok := st.ids[m.ID]
+	if !ok {
+		existing = &OrderIDMapping{ID: m.ID, Symbol: m.Symbol, Magic: m.Magic, StopLimit: m.StopLimit, Created: now}
+		st.ids[m.ID] = existing
+	}
+	addTicket(&existing.Orders, ticket)
+	existing.Closed = false
+	existing.Updated = now
+	return st.writeLocked()
+}
+
+// AddPosition records a position opened by the orders of id.
+func (st *OrderIDStore) AddPosition(id string, position uint64) error {
+	return st.update(id, func(m *OrderIDMapping) {
+		addTicket(&m.Positions, position)
+		m.Closed = false
+	})
+}
+
+// Close marks id as done; it is kept for lookups but skipped by Readopt.
+func (st *OrderIDStore) Close(id string) error {
+	return st.update(id, func(m *OrderIDMapping) { m.Closed = true })
+}
+
+// Delete removes id and writes the file. Unknown IDs are ignored.
+func (st *OrderIDStore) Delete(id string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if _, ok := st.ids[id]; !ok {
+		return nil
+	}
+	delete(st.ids, id)
+	return st.writeLocked()
+}
+
+// Prune deletes closed IDs last updated before cutoff.
+//
+// Returns:
+//   - Number of IDs deleted
+func (st *OrderIDStore) Prune(cutoff time.Time) (int, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	n := 0
+	for id, m := range st.ids {
+		if m.Closed && m.Updated.Before(cutoff) {
+			delete(st.ids, id)
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	return n, st.writeLocked()
+}
+
+// Get returns the mapping of id.
+func (st *OrderIDStore) Get(id string) (OrderIDMapping, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	m, ok := st.ids[id]
+	if !ok {
+		return OrderIDMapping{}, false
+	}
+	return m.clone(), true
+}
+
+// Lookup returns the ID owning an order or position ticket.
+func (st *OrderIDStore) Lookup(ticket uint64) (string, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	for id, m := range st.ids {
+		for _, t := range m.Orders {
+			if t == ticket {
+				return id, true
+			}
+		}
+		for _, t := range m.Positions {
+			if t == ticket {
+				return id, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Active returns the mappings not marked closed, sorted by ID.
+func (st *OrderIDStore) Active() []OrderIDMapping {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	var result []OrderIDMapping
+	for _, m := range st.ids {
+		if !m.Closed {
+			result = append(result, m.clone())
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// ReadoptResult is the outcome of Readopt.
+type ReadoptResult struct {
+	Live     []OrderIDMapping  // IDs with an open order or position
+	Closed   []string          // IDs found without any open order or position (now marked closed)
+	Replaced map[string]uint64 // ID → limit order that replaced its triggered stop-limit
+}
+
+// pricesEqual compares prices with a relative tolerance.
+func pricesEqual(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(math.Abs(a), math.Abs(b))
+}
+
+// isLimitOrder reports whether an order type is a plain limit order.
+func isLimitOrder(t pb.BMT5_ENUM_ORDER_TYPE) bool {
+	return t == pb.BMT5_ENUM_ORDER_TYPE_BMT5_ORDER_TYPE_BUY_LIMIT || t == pb.BMT5_ENUM_ORDER_TYPE_BMT5_ORDER_TYPE_SELL_LIMIT
+}
+
+// Readopt matches the active IDs of store against the open orders,
+// positions and order history of the account, records the tickets found
+// (positions of filled orders, limit orders replacing triggered
+// stop-limits) and marks IDs without any open order or position as closed.
+// Uses 30-second timeout.
+//
+// Parameters:
+//   - store: Order ID store of the orchestrator being resumed
+//
+// Returns:
+//   - IDs still live (with their current tickets), IDs closed, replacements;
+//     or error if orders could not be read or the store not written
+func (s *MT5Sugar) Readopt(store *OrderIDStore) (*ReadoptResult, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
+	defer cancel()
+
+	active := store.Active()
+	result := &ReadoptResult{Replaced: make(map[string]uint64)}
+	if len(active) == 0 {
+		return result, nil
+	}
+
+	opened, err := s.service.GetOpenedOrders(ctx, pb.BMT5_ENUM_OPENED_ORDER_SORT_TYPE_BMT5_OPENED_ORDER_SORT_BY_OPEN_TIME_ASC)
+	if err != nil {
+		return nil, fmt.Errorf("Readopt failed: %w", err)
+	}
+	from := time.Now()
+	for _, m := range active {
+		if m.Created.Before(from) {
+			from = m.Created
+		}
+	}
+	_, history, err := s.service.loadHistory(ctx, from.Add(-time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		return nil, fmt.Errorf("Readopt failed: %w", err)
+	}
+
+	openOrders := make(map[uint64]*pb.OpenedOrderInfo, len(opened.OpenedOrders))
+	for _, order := range opened.OpenedOrders {
+		openOrders[order.Ticket] = order
+	}
+	openPositions := make(map[uint64]bool, len(opened.PositionInfos))
+	positionOf := make(map[uint64]uint64, len(opened.PositionInfos)) // opening order → position
+	for _, pos := range opened.PositionInfos {
+		openPositions[pos.Ticket] = true
+		if pos.Identifier > 0 {
+			positionOf[uint64(pos.Identifier)] = pos.Ticket
+		}
+	}
+	historyOrders := make(map[uint64]*pb.OrderHistoryData, len(history))
+	for _, order := range history {
+		historyOrders[order.Ticket] = order
+	}
+	owned := make(map[uint64]bool)
+	for _, m := range active {
+		for _, t := range m.Orders {
+			owned[t] = true
+		}
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	now := time.Now()
+
+	for _, snapshot := range active {
+		m, ok := store.ids[snapshot.ID]
+		if !ok || m.Closed {
+			continue
+		}
+		changed := false
+		live := false
+
+		for _, ticket := range m.Orders {
+			if order, ok := openOrders[ticket]; ok {
+				live = true
+				if order.StopLimit > 0 && m.StopLimit == 0 {
+					m.StopLimit, changed = order.StopLimit, true
+				}
+			}
+			if pos, ok := positionOf[ticket]; ok && addTicket(&m.Positions, pos) {
+				changed = true
+			}
+			if order, ok := historyOrders[ticket]; ok {
+				if order.PositionId != 0 && addTicket(&m.Positions, order.PositionId) {
+					changed = true
+				}
+				if order.StopLimit > 0 && m.StopLimit == 0 {
+					m.StopLimit, changed = order.StopLimit, true
+				}
+			}
+		}
+		for _, pos := range m.Positions {
+			if openPositions[pos] {
+				live = true
+			}
+		}
+
+		// A triggered stop-limit leaves no order and no position, only the limit order it placed
+		if !live && len(m.Positions) == 0 && m.StopLimit > 0 {
+			for _, order := range opened.OpenedOrders {
+				if owned[order.Ticket] || !isLimitOrder(order.Type) || order.Symbol != m.Symbol {
+					continue
+				}
+				if m.Magic != 0 && order.MagicNumber != m.Magic {
+					continue
+				}
+				if pricesEqual(order.PriceOpen, m.StopLimit) {
+					m.Orders = append(m.Orders, order.Ticket)
+					owned[order.Ticket] = true
+					result.Replaced[m.ID] = order.Ticket
+					live, changed = true, true
+					break
+				}
+			}
+		}
+
+		if !live {
+			m.Closed, changed = true, true
+			result.Closed = append(result.Closed, m.ID)
+		} else {
+			result.Live = append(result.Live, m.clone())
+		}
+		if changed {
+			m.Updated = now
+		}
+	}
+
+	if err := store.writeLocked(); err != nil {
+		return nil, fmt.Errorf("Readopt failed: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,272 @@
+package mt5
+
+/*
+Minimal Parquet writer for history exports.
+
+Writes a flat table of REQUIRED columns as one row group with one
+uncompressed, PLAIN-encoded data page per column - the simplest layout every
+reader (pyarrow, pandas, DuckDB, Spark, polars) accepts. It exists so that
+exports need no third-party dependency; it is not a general Parquet library
+(no nesting, nulls, compression or dictionary encoding).
+
+Layout:
+    "PAR1" | column chunk 1 | ... | column chunk N | FileMetaData | len(FileMetaData) | "PAR1"
+
+Column chunk = PageHeader (Thrift compact) + values.
+*/
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+const parquetMagic = "PAR1"
+
+// Parquet physical types, converted types and encodings (parquet.thrift).
+const (
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetConvertedUTF8            = 0
+	parquetConvertedTimestampMillis = 9
+
+	parquetEncodingPlain = 0
+	parquetEncodingRLE   = 3
+)
+
+// Thrift compact protocol field types.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// writeParquet writes t as a Parquet file.
+func writeParquet(w io.Writer, t *exportTable) error {
+	var file bytes.Buffer
+	file.WriteString(parquetMagic)
+
+	type chunk struct {
+		offset int64
+		size   int64
+	}
+	chunks := make([]chunk, len(t.Columns))
+
+	for i, col := range t.Columns {
+		values, err := parquetValues(col, t.Rows)
+		if err != nil {
+			return err
+		}
+
+		var header thriftWriter
+		header.i32(1, 0) // type: DATA_PAGE
+		header.i32(2, int32(len(values)))
+		header.i32(3, int32(len(values)))
+		header.beginStruct(5) // data_page_header
+		header.i32(1, int32(t.Rows))
+		header.i32(2, parquetEncodingPlain)
+		header.i32(3, parquetEncodingRLE)
+		header.i32(4, parquetEncodingRLE)
+		header.endStruct()
+		header.stop()
+
+		chunks[i].offset = int64(file.Len())
+		file.Write(header.buf.Bytes())
+		file.Write(values)
+		chunks[i].size = int64(file.Len()) - chunks[i].offset
+	}
+
+	var meta thriftWriter
+	meta.i32(1, 1) // version
+
+	meta.beginList(2, thriftStruct, len(t.Columns)+1) // schema
+	meta.beginElem()
+	meta.str(4, "schema")
+	meta.i32(5, int32(len(t.Columns)))
+	meta.endElem()
+	for _, col := range t.Columns {
+		meta.beginElem()
+		meta.i32(1, parquetPhysicalType(col.Kind))
+		meta.i32(3, 0) // repetition: REQUIRED
+		meta.str(4, col.Name)
+		switch col.Kind {
+		case exportString:
+			meta.i32(6, parquetConvertedUTF8)
+		case exportTime:
+			meta.i32(6, parquetConvertedTimestampMillis)
+		}
+		meta.endElem()
+	}
+
+	meta.i64(3, int64(t.Rows))
+
+	var total int64
+	for _, c := range chunks {
+		total += c.size
+	}
+	meta.beginList(4, thriftStruct, 1) // row_groups
+	meta.beginElem()
+	meta.beginList(1, thriftStruct, len(t.Columns)) // columns
+	for i, col := range t.Columns {
+		meta.beginElem()
+		meta.i64(2, chunks[i].offset) // file_offset
+		meta.beginStruct(3)           // meta_data
+		meta.i32(1, parquetPhysicalType(col.Kind))
+		meta.beginList(2, thriftI32, 2)
+		meta.varint(zigzag(parquetEncodingPlain))
+		meta.varint(zigzag(parquetEncodingRLE))
+		meta.beginList(3, thriftBinary, 1)
+		meta.binary(col.Name)
+		meta.i32(4, 0) // codec: UNCOMPRESSED
+		meta.i64(5, int64(t.Rows))
+		meta.i64(6, chunks[i].size)
+		meta.i64(7, chunks[i].size)
+		meta.i64(9, chunks[i].offset) // data_page_offset
+		meta.endStruct()
+		meta.endElem()
+	}
+	meta.i64(2, total)
+	meta.i64(3, int64(t.Rows))
+	meta.endElem()
+
+	meta.str(6, "GoMT5 history export")
+	meta.stop()
+
+	file.Write(meta.buf.Bytes())
+	binary.Write(&file, binary.LittleEndian, uint32(meta.buf.Len()))
+	file.WriteString(parquetMagic)
+
+	_, err := w.Write(file.Bytes())
+	return err
+}
+
+// parquetPhysicalType maps a column kind to its Parquet type.
+func parquetPhysicalType(kind exportKind) int32 {
+	switch kind {
+	case exportFloat:
+		return parquetDouble
+	case exportString:
+		return parquetByteArray
+	default:
+		return parquetInt64
+	}
+}
+
+// parquetValues PLAIN-encodes all values of a column.
+func parquetValues(col exportColumn, rows int) ([]byte, error) {
+	var buf bytes.Buffer
+	var scratch [8]byte
+	for row := 0; row < rows; row++ {
+		switch v := col.Value(row).(type) {
+		case int64:
+			binary.LittleEndian.PutUint64(scratch[:], uint64(v))
+			buf.Write(scratch[:])
+		case float64:
+			binary.LittleEndian.PutUint64(scratch[:], math.Float64bits(v))
+			buf.Write(scratch[:])
+		case string:
+			binary.LittleEndian.PutUint32(scratch[:4], uint32(len(v)))
+			buf.Write(scratch[:4])
+			buf.WriteString(v)
+		case time.Time:
+			var ms int64
+			if !v.IsZero() {
+				ms = v.UnixMilli()
+			}
+			binary.LittleEndian.PutUint64(scratch[:], uint64(ms))
+			buf.Write(scratch[:])
+		default:
+			return nil, fmt.Errorf("parquet: column %s: unsupported value %T", col.Name, v)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// thriftWriter encodes structs with the Thrift compact protocol.
+type thriftWriter struct {
+	buf  bytes.Buffer
+	last []int16 // Last field ID per open struct
+	cur  int16
+}
+
+func (w *thriftWriter) field(id int16, typ byte) {
+	if delta := id - w.cur; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.varint(zigzag(int64(id)))
+	}
+	w.cur = id
+}
+
+func (w *thriftWriter) varint(v uint64) {
+	var scratch [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(scratch[:], v)
+	w.buf.Write(scratch[:n])
+}
+
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+func (w *thriftWriter) i32(id int16, v int32) {
+	w.field(id, thriftI32)
+	w.varint(zigzag(int64(v)))
+}
+
+func (w *thriftWriter) i64(id int16, v int64) {
+	w.field(id, thriftI64)
+	w.varint(zigzag(v))
+}
+
+func (w *thriftWriter) str(id int16, s string) {
+	w.field(id, thriftBinary)
+	w.binary(s)
+}
+
+func (w *thriftWriter) binary(s string) {
+	w.varint(uint64(len(s)))
+	w.buf.WriteString(s)
+}
+
+func (w *thriftWriter) beginList(id int16, elem byte, n int) {
+	w.field(id, thriftList)
+	if n < 15 {
+		w.buf.WriteByte(byte(n)<<4 | elem)
+	} else {
+		w.buf.WriteByte(0xF0 | elem)
+		w.varint(uint64(n))
+	}
+}
+
+// beginStruct opens a struct field; beginElem opens a struct list element.
+func (w *thriftWriter) beginStruct(id int16) {
+	w.field(id, thriftStruct)
+	w.beginElem()
+}
+
+func (w *thriftWriter) beginElem() {
+	w.last = append(w.last, w.cur)
+	w.cur = 0
+}
+
+func (w *thriftWriter) endStruct() {
+	w.endElem()
+}
+
+func (w *thriftWriter) endElem() {
+	w.stop()
+	w.cur = w.last[len(w.last)-1]
+	w.last = w.last[:len(w.last)-1]
+}
+
+func (w *thriftWriter) stop() {
+	w.buf.WriteByte(0)
+}
@@ -0,0 +1,235 @@
+package mt5
+
+/*
+Spread-adjusted pending entries - price pending orders from the side of the
+quote that triggers them.
+
+  • BUY LIMIT  triggers when Ask falls to the price    → measured from Ask
+  • BUY STOP   triggers when Ask rises to the price    → measured from Ask
+  • SELL LIMIT triggers when Bid rises to the price    → measured from Bid
+  • SELL STOP  triggers when Bid falls to the price    → measured from Bid
+
+Grid and breakout code usually works with a single price - the Bid shown on
+the chart, or mid - and so places every BUY order off by the spread: a buy
+stop "10 points above the high" triggers when Bid is only 10 - spread points
+above it, a buy limit at a chart support level fills a spread early.
+
+  • PendingPrice(type, symbol, offsetPoints)  - offsetPoints away from the triggering side
+  • PendingPriceAtLevel(type, symbol, level)  - chart (Bid) level → order price (BUY orders + spread)
+  • PlacePendingOffset / PlacePendingAtLevel  - place at those prices
+  • BuyStopPoints / SellStopPoints / BuyLimitPoints / SellLimitPoints
+
+A price on the correct side but closer than SYMBOL_TRADE_STOPS_LEVEL to the
+triggering side is moved out to the stops level; PendingEntry.Adjusted is
+set and the Place* helpers report the move like SetStopsAutoAdjust does.
+A price on the wrong side (a buy stop below Ask) is an error.
+
+Usage:
+    // Breakout: buy when Bid clears the high by 5 points
+    ticket, err := sugar.PlacePendingAtLevel(pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_STOP,
+        "EURUSD", 0.1, high+5*point)
+
+    // Grid: sell limit 20 points above Bid, buy limit 20 points below Ask
+    sugar.SellLimitPoints("EURUSD", 0.1, 20)
+    sugar.BuyLimitPoints("EURUSD", 0.1, 20)
+*/
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// PendingEntry is a pending order price worked out by PendingPrice or
+// PendingPriceAtLevel.
+type PendingEntry struct {
+	Type      pb.TMT5_ENUM_ORDER_TYPE
+	Symbol    string
+	Price     float64 // Order price to send
+	Requested float64 // Price before the stops level adjustment
+	Reference float64 // Ask (BUY orders) or Bid (SELL orders) the price is measured from
+	Spread    float64 // Ask - Bid at calculation time
+	Adjusted  bool    // Price was moved out to the stops level
+
+	stopsLevel int64
+	digits     int
+}
+
+// PendingPrice returns the price offsetPoints away from the side of the
+// quote that triggers orderType (BUY_LIMIT, SELL_LIMIT, BUY_STOP or
+// SELL_STOP): below Ask for BUY LIMIT, above Ask for BUY STOP, above Bid for
+// SELL LIMIT, below Bid for SELL STOP. An offset inside the stops level is
+// widened to it and the entry is marked Adjusted. Uses 3-second timeout.
+func (s *MT5Sugar) PendingPrice(orderType pb.TMT5_ENUM_ORDER_TYPE, symbol string, offsetPoints float64) (*PendingEntry, error) {
+	if offsetPoints <= 0 {
+		return nil, fmt.Errorf("PendingPrice failed: offset must be positive, got %.1f points", offsetPoints)
+	}
+	return s.pendingEntry(orderType, symbol, func(ref, bid, point float64, above bool) float64 {
+		if above {
+			return ref + offsetPoints*point
+		}
+		return ref - offsetPoints*point
+	})
+}
+
+// PendingPriceAtLevel converts a chart level (Bid, as MT5 charts show it),
+// such as a breakout high or a support line, to the price of orderType that
+// triggers when Bid reaches the level: BUY orders add the current spread,
+// SELL orders use the level as is. A level on the wrong side of the market
+// is an error; one inside the stops level is moved out to it. Uses 3-second
+// timeout.
+func (s *MT5Sugar) PendingPriceAtLevel(orderType pb.TMT5_ENUM_ORDER_TYPE, symbol string, level float64) (*PendingEntry, error) {
+	if level <= 0 {
+		return nil, fmt.Errorf("PendingPriceAtLevel failed: invalid level %.5f", level)
+	}
+	return s.pendingEntry(orderType, symbol, func(ref, bid, point float64, above bool) float64 {
+		// Bid at the level means Ask is a spread higher
+		return level + ref - bid
+	})
+}
+
+// PlacePendingOffset places orderType offsetPoints away from its triggering
+// side (see PendingPrice) and returns the order ticket. Uses 3-second
+// timeout for the price and 10-second timeout for the order.
+func (s *MT5Sugar) PlacePendingOffset(orderType pb.TMT5_ENUM_ORDER_TYPE, symbol string, volume, offsetPoints float64) (uint64, error) {
+	entry, err := s.PendingPrice(orderType, symbol, offsetPoints)
+	if err != nil {
+		return 0, err
+	}
+	return s.placePendingEntry(entry, volume)
+}
+
+// PlacePendingAtLevel places orderType so it triggers when Bid reaches the
+// chart level (see PendingPriceAtLevel) and returns the order ticket. Uses
+// 3-second timeout for the price and 10-second timeout for the order.
+func (s *MT5Sugar) PlacePendingAtLevel(orderType pb.TMT5_ENUM_ORDER_TYPE, symbol string, volume, level float64) (uint64, error) {
+	entry, err := s.PendingPriceAtLevel(orderType, symbol, level)
+	if err != nil {
+		return 0, err
+	}
+	return s.placePendingEntry(entry, volume)
+}
+
+// BuyStopPoints places a BUY STOP points above Ask. See PlacePendingOffset.
+func (s *MT5Sugar) BuyStopPoints(symbol string, volume, points float64) (uint64, error) {
+	return s.PlacePendingOffset(pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_STOP, symbol, volume, points)
+}
+
+// SellStopPoints places a SELL STOP points below Bid. See PlacePendingOffset.
+func (s *MT5Sugar) SellStopPoints(symbol string, volume, points float64) (uint64, error) {
+	return s.PlacePendingOffset(pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL_STOP, symbol, volume, points)
+}
+
+// BuyLimitPoints places a BUY LIMIT points below Ask. See PlacePendingOffset.
+func (s *MT5Sugar) BuyLimitPoints(symbol string, volume, points float64) (uint64, error) {
+	return s.PlacePendingOffset(pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_LIMIT, symbol, volume, points)
+}
+
+// SellLimitPoints places a SELL LIMIT points above Bid. See PlacePendingOffset.
+func (s *MT5Sugar) SellLimitPoints(symbol string, volume, points float64) (uint64, error) {
+	return s.PlacePendingOffset(pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL_LIMIT, symbol, volume, points)
+}
+
+// pendingEntry reads the quote and symbol limits and works out the price of
+// orderType. price returns the requested price given the triggering side's
+// quote (ref), the Bid, the point size and whether the order sits above ref.
+func (s *MT5Sugar) pendingEntry(orderType pb.TMT5_ENUM_ORDER_TYPE, symbol string, price func(ref, bid, point float64, above bool) float64) (*PendingEntry, error) {
+	var buy, above bool
+	switch orderType {
+	case pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_LIMIT:
+		buy, above = true, false
+	case pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_STOP:
+		buy, above = true, true
+	case pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL_LIMIT:
+		buy, above = false, true
+	case pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL_STOP:
+		buy, above = false, false
+	default:
+		return nil, fmt.Errorf("%s is not a BUY/SELL LIMIT or STOP order", orderType)
+	}
+
+	symbol = s.ResolveSymbol(symbol)
+
+	ctx, cancel := context.WithTimeout(s.ctx, 3*time.Second)
+	defer cancel()
+
+	tick, err := s.service.GetSymbolTick(ctx, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price of %s: %w", symbol, err)
+	}
+	point, err := s.service.GetSymbolDouble(ctx, symbol, pb.SymbolInfoDoubleProperty_SYMBOL_POINT)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get point of %s: %w", symbol, err)
+	}
+	if point <= 0 || tick.Bid <= 0 || tick.Ask <= 0 {
+		return nil, fmt.Errorf("no valid quote for %s", symbol)
+	}
+	stopsLevel, _ := s.service.GetSymbolInteger(ctx, symbol, pb.SymbolInfoIntegerProperty_SYMBOL_TRADE_STOPS_LEVEL)
+	digits := int(math.Round(-math.Log10(point)))
+	normalize := func(v float64) float64 {
+		v, _ = strconv.ParseFloat(strconv.FormatFloat(v, 'f', digits, 64), 64)
+		return v
+	}
+
+	entry := &PendingEntry{
+		Type:       orderType,
+		Symbol:     symbol,
+		Reference:  tick.Bid,
+		Spread:     normalize(tick.Ask - tick.Bid),
+		stopsLevel: stopsLevel,
+		digits:     digits,
+	}
+	if buy {
+		entry.Reference = tick.Ask
+	}
+	entry.Requested = normalize(price(entry.Reference, tick.Bid, point, above))
+	entry.Price = entry.Requested
+
+	ref := entry.Reference
+	if above && entry.Price <= ref || !above && entry.Price >= ref {
+		side := "below"
+		if above {
+			side = "above"
+		}
+		return nil, fmt.Errorf("%s %s at %.*f must be %s the market (%.*f)",
+			orderType, symbol, digits, entry.Price, side, digits, ref)
+	}
+
+	dist := float64(stopsLevel) * point
+	if above {
+		if valid := normalize(math.Ceil((ref+dist)/point-1e-6) * point); entry.Price < valid {
+			entry.Price, entry.Adjusted = valid, true
+		}
+	} else {
+		if valid := normalize(math.Floor((ref-dist)/point+1e-6) * point); entry.Price > valid {
+			entry.Price, entry.Adjusted = valid, true
+		}
+	}
+	return entry, nil
+}
+
+// placePendingEntry sends entry and reports a stops level adjustment.
+func (s *MT5Sugar) placePendingEntry(entry *PendingEntry, volume float64) (uint64, error) {
+	if entry.Adjusted {
+		s.reportStopsAdjustment(StopsAdjustment{
+			Time:        time.Now(),
+			Symbol:      entry.Symbol,
+			Field:       "Price",
+			Requested:   entry.Requested,
+			Adjusted:    entry.Price,
+			Reference:   entry.Reference,
+			LevelPoints: entry.stopsLevel,
+			digits:      entry.digits,
+		})
+	}
+	return s.SendOrder(OrderRequest{
+		Symbol: entry.Symbol,
+		Type:   entry.Type,
+		Volume: volume,
+		Price:  entry.Price,
+	})
+}
@@ -0,0 +1,240 @@
+package mt5
+
+/*
+PnLDecomposer - floating P/L split into price move and currency conversion.
+
+A EUR account holding USDJPY earns in JPY and is converted to EUR at the
+current JPY→EUR rate. Its floating P/L changes when USDJPY moves (price
+effect) AND when JPY moves against EUR (conversion effect). Splitting both
+shows whether a position needs a price hedge or a currency hedge:
+
+  pnlProfit        = ±volume × contract × (current − open)    (profit currency)
+  PriceEffect      = pnlProfit × OpenRate                      (account currency)
+  ConversionEffect = pnlProfit × (CurrentRate − OpenRate)
+  Total            = PriceEffect + ConversionEffect = pnlProfit × CurrentRate
+
+Rates convert the symbol's profit currency into the account currency and are
+derived from tick value (tickValue / (tickSize × contractSize)). The API has
+no historical conversion rates, so OpenRate is captured the first time the
+decomposer sees a position: call Observe on a timer or on trade events.
+Positions already open when tracking starts get the rate of that moment
+(RateObservedAt), or set the real one with SetOpenRate.
+
+Symbols whose profit currency is the account currency have no conversion
+effect.
+
+Usage:
+    decomposer := mt5.NewPnLDecomposer(sugar)
+    decomposer.Observe() // right after start, then periodically
+
+    items, err := decomposer.Decompose()
+    summary := mt5.SummarizePnLDecomposition(items)
+    fmt.Printf("price %+.2f  FX %+.2f\n", summary.PriceEffect, summary.ConversionEffect)
+    for currency, fx := range summary.ConversionByCurrency {
+        fmt.Printf("  %s conversion %+.2f\n", currency, fx)
+    }
+*/
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// PnLDecomposition is the floating P/L of one position split into effects.
+type PnLDecomposition struct {
+	Ticket           uint64
+	Symbol           string
+	ProfitCurrency   string
+	AccountCurrency  string
+	Volume           float64
+	PnLProfit        float64   // Price P/L in the profit currency
+	OpenRate         float64   // Profit → account currency rate at open (or first observation)
+	CurrentRate      float64   // Profit → account currency rate now
+	PriceEffect      float64   // PnLProfit converted at OpenRate
+	ConversionEffect float64   // P/L caused by the rate change since open
+	Total            float64   // PriceEffect + ConversionEffect
+	Reported         float64   // Profit reported by the broker (for comparison)
+	RateObservedAt   time.Time // When OpenRate was captured
+}
+
+// PnLDecompositionSummary totals decomposed positions.
+type PnLDecompositionSummary struct {
+	PriceEffect          float64
+	ConversionEffect     float64
+	Total                float64
+	ConversionByCurrency map[string]float64 // Conversion effect per profit currency
+}
+
+// openRate is a captured conversion rate of a position.
+type openRate struct {
+	rate float64
+	at   time.Time
+}
+
+// PnLDecomposer tracks conversion rates of open positions.
+type PnLDecomposer struct {
+	sugar *MT5Sugar
+
+	mu    sync.Mutex
+	rates map[uint64]openRate // Position ticket → rate at open
+}
+
+// NewPnLDecomposer creates a decomposer. Call Observe as early as possible
+// so open rates are close to the real ones.
+func NewPnLDecomposer(sugar *MT5Sugar) *PnLDecomposer {
+	return &PnLDecomposer{
+		sugar: sugar,
+		rates: make(map[uint64]openRate),
+	}
+}
+
+// SetOpenRate sets the profit → account currency rate of a position at open,
+// e.g. restored from a previous run.
+func (d *PnLDecomposer) SetOpenRate(ticket uint64, rate float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.rates[ticket] = openRate{rate: rate, at: time.Now()}
+}
+
+// OpenRates returns the captured rates by position ticket (for persisting).
+func (d *PnLDecomposer) OpenRates() map[uint64]float64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	rates := make(map[uint64]float64, len(d.rates))
+	for ticket, r := range d.rates {
+		rates[ticket] = r.rate
+	}
+	return rates
+}
+
+// conversionRate returns the profit → account currency rate of a symbol.
+func conversionRate(p *SymbolParams) float64 {
+	if p.TradeTickSize <= 0 || p.TradeContractSize <= 0 {
+		return 0
+	}
+	return p.TradeTickValue / (p.TradeTickSize * p.TradeContractSize)
+}
+
+// Observe captures the conversion rate of positions not seen before and
+// forgets closed ones. Uses 10-second timeout.
+func (d *PnLDecomposer) Observe() error {
+	_, err := d.Decompose()
+	return err
+}
+
+// Decompose splits the floating P/L of every open position, capturing open
+// rates of new positions. Uses 10-second timeout.
+//
+// Returns:
+//   - One entry per open position, or error if positions, account currency
+//     or symbol specs could not be read
+func (d *PnLDecomposer) Decompose() ([]PnLDecomposition, error) {
+	positions, err := d.sugar.GetOpenPositions()
+	if err != nil {
+		return nil, fmt.Errorf("Decompose failed: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(d.sugar.ctx, 10*time.Second)
+	defer cancel()
+
+	account, err := d.sugar.service.GetAccountString(ctx, pb.AccountInfoStringPropertyType_ACCOUNT_CURRENCY)
+	if err != nil {
+		return nil, fmt.Errorf("Decompose failed: %w", err)
+	}
+
+	type symbolInfo struct {
+		params *SymbolParams
+		profit string
+	}
+	symbols := make(map[string]symbolInfo)
+	info := func(symbol string) (symbolInfo, error) {
+		if i, ok := symbols[symbol]; ok {
+			return i, nil
+		}
+		name := symbol
+		params, _, err := d.sugar.service.GetSymbolParamsMany(ctx, &name, nil, nil, nil)
+		if err != nil {
+			return symbolInfo{}, err
+		}
+		if len(params) == 0 {
+			return symbolInfo{}, fmt.Errorf("symbol %s not found", symbol)
+		}
+		profit, err := d.sugar.service.GetSymbolString(ctx, symbol, pb.SymbolInfoStringProperty_SYMBOL_CURRENCY_PROFIT)
+		if err != nil {
+			return symbolInfo{}, err
+		}
+		symbols[symbol] = symbolInfo{params: &params[0], profit: profit}
+		return symbols[symbol], nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	open := make(map[uint64]bool, len(positions))
+	items := make([]PnLDecomposition, 0, len(positions))
+	for _, pos := range positions {
+		open[pos.Ticket] = true
+		i, err := info(pos.Symbol)
+		if err != nil {
+			return nil, fmt.Errorf("Decompose failed: %w", err)
+		}
+
+		current := conversionRate(i.params)
+		if i.profit == account {
+			current = 1
+		}
+		captured, ok := d.rates[pos.Ticket]
+		if !ok {
+			captured = openRate{rate: current, at: time.Now()}
+			d.rates[pos.Ticket] = captured
+		}
+
+		pnl := pos.Volume * i.params.TradeContractSize * (pos.PriceCurrent - pos.PriceOpen)
+		if pos.Type == pb.BMT5_ENUM_POSITION_TYPE_BMT5_POSITION_TYPE_SELL {
+			pnl = -pnl
+		}
+
+		item := PnLDecomposition{
+			Ticket:          pos.Ticket,
+			Symbol:          pos.Symbol,
+			ProfitCurrency:  i.profit,
+			AccountCurrency: account,
+			Volume:          pos.Volume,
+			PnLProfit:       pnl,
+			OpenRate:        captured.rate,
+			CurrentRate:     current,
+			PriceEffect:     pnl * captured.rate,
+			Reported:        pos.Profit,
+			RateObservedAt:  captured.at,
+		}
+		item.ConversionEffect = pnl * (current - captured.rate)
+		item.Total = item.PriceEffect + item.ConversionEffect
+		items = append(items, item)
+	}
+
+	for ticket := range d.rates {
+		if !open[ticket] {
+			delete(d.rates, ticket)
+		}
+	}
+
+	return items, nil
+}
+
+// SummarizePnLDecomposition totals the effects of decomposed positions.
+func SummarizePnLDecomposition(items []PnLDecomposition) PnLDecompositionSummary {
+	summary := PnLDecompositionSummary{ConversionByCurrency: make(map[string]float64)}
+	for _, item := range items {
+		summary.PriceEffect += item.PriceEffect
+		summary.ConversionEffect += item.ConversionEffect
+		summary.Total += item.Total
+		if item.ProfitCurrency != item.AccountCurrency {
+			summary.ConversionByCurrency[item.ProfitCurrency] += item.ConversionEffect
+		}
+	}
+	return summary
+}
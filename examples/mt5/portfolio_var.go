@@ -0,0 +1,318 @@
+package mt5
+
+/*
+PortfolioVaR - Value-at-Risk and Expected Shortfall of the open portfolio.
+
+Risk is estimated from historical candle returns of every symbol held:
+  • Parametric VaR/ES  - variance-covariance method (volatilities + correlations)
+  • Historical VaR/ES  - replays past aligned returns against current exposure
+
+The gRPC API has no bar history call, so the calculator collects its own
+candles: feed it ticks with Observe (or ready candles with AddCandles).
+Figures are in account currency for a one-bar horizon.
+
+Usage:
+    calc := mt5.NewVaRCalculator(service, 0.99, time.Minute, 500)
+    for tick := range ticks {
+        calc.Observe(tick)
+    }
+    result, err := calc.Calculate(ctx)
+    fmt.Printf("VaR99: %.2f  ES99: %.2f\n", result.VaR, result.ExpectedShortfall)
+*/
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// VaRResult holds portfolio risk estimates in account currency.
+type VaRResult struct {
+	Confidence        float64                       // Confidence level (e.g., 0.99)
+	VaR               float64                       // Parametric Value-at-Risk
+	ExpectedShortfall float64                       // Parametric Expected Shortfall (CVaR)
+	HistoricalVaR     float64                       // Historical-simulation VaR (0 if too few bars)
+	HistoricalES      float64                       // Historical-simulation Expected Shortfall
+	Observations      int                           // Aligned return observations used
+	Exposures         map[string]float64            // Net exposure per symbol (P/L per 100% move, signed)
+	Volatility        map[string]float64            // Per-bar return standard deviation per symbol
+	Correlations      map[string]map[string]float64 // Return correlation matrix
+	MissingHistory    []string                      // Held symbols without enough candles (ignored)
+}
+
+// VaRCalculator estimates portfolio VaR from locally collected candles.
+// Safe for concurrent use.
+type VaRCalculator struct {
+	service    *MT5Service
+	confidence float64
+	timeframe  time.Duration
+	maxBars    int
+
+	mu       sync.Mutex
+	builders map[string]*CandleBuilder
+	history  map[string][]Candle
+}
+
+// minVaRObservations is the minimum number of aligned returns needed.
+const minVaRObservations = 20
+
+// NewVaRCalculator creates a calculator.
+//
+// Parameters:
+//   - service: MT5Service used to read positions and symbol specs
+//   - confidence: Confidence level, e.g. 0.95 or 0.99 (default 0.99)
+//   - timeframe: Candle size for returns (default 1 minute); also the VaR horizon
+//   - maxBars: Candles kept per symbol (default 500)
+func NewVaRCalculator(service *MT5Service, confidence float64, timeframe time.Duration, maxBars int) *VaRCalculator {
+	if confidence <= 0 || confidence >= 1 {
+		confidence = 0.99
+	}
+	if timeframe <= 0 {
+		timeframe = time.Minute
+	}
+	if maxBars <= 0 {
+		maxBars = 500
+	}
+	return &VaRCalculator{
+		service:    service,
+		confidence: confidence,
+		timeframe:  timeframe,
+		maxBars:    maxBars,
+		builders:   make(map[string]*CandleBuilder),
+		history:    make(map[string][]Candle),
+	}
+}
+
+// Observe feeds a tick into the per-symbol candle builder.
+func (c *VaRCalculator) Observe(tick *SymbolTick) {
+	if tick == nil || tick.Symbol == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	builder, ok := c.builders[tick.Symbol]
+	if !ok {
+		builder = NewCandleBuilder(tick.Symbol, c.timeframe)
+		c.builders[tick.Symbol] = builder
+	}
+	if closed := builder.Add(tick); closed != nil {
+		c.appendLocked(*closed)
+	}
+}
+
+// AddCandles appends already built candles (e.g., loaded from disk).
+// Candles must be in chronological order and of the calculator's timeframe.
+func (c *VaRCalculator) AddCandles(candles []Candle) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, candle := range candles {
+		c.appendLocked(candle)
+	}
+}
+
+// appendLocked stores a candle and trims history to maxBars.
+func (c *VaRCalculator) appendLocked(candle Candle) {
+	bars := append(c.history[candle.Symbol], candle)
+	if len(bars) > c.maxBars {
+		bars = bars[len(bars)-c.maxBars:]
+	}
+	c.history[candle.Symbol] = bars
+}
+
+// Calculate estimates VaR of the currently open positions.
+// Optional extra orders are added on top, which shows the portfolio risk
+// AFTER a planned entry.
+//
+// Returns:
+//   - VaRResult with parametric and historical estimates
+//   - Error if positions or symbol specs could not be read
+func (c *VaRCalculator) Calculate(ctx context.Context, extra ...OrderRequest) (*VaRResult, error) {
+	data, err := c.service.GetOpenedOrders(ctx, pb.BMT5_ENUM_OPENED_ORDER_SORT_TYPE_BMT5_OPENED_ORDER_SORT_BY_OPEN_TIME_ASC)
+	if err != nil {
+		return nil, fmt.Errorf("VaR calculation failed: %w", err)
+	}
+
+	// Net exposure per symbol: P/L in account currency for a 100% price move
+	exposures := make(map[string]float64)
+	specs := make(map[string]*SymbolParams)
+	addExposure := func(symbol string, volume, price float64, buy bool) error {
+		spec, ok := specs[symbol]
+		if !ok {
+			name := symbol
+			params, _, err := c.service.GetSymbolParamsMany(ctx, &name, nil, nil, nil)
+			if err != nil {
+				return err
+			}
+			if len(params) == 0 {
+				return fmt.Errorf("symbol %s not found", symbol)
+			}
+			spec = &params[0]
+			specs[symbol] = spec
+		}
+		if spec.TradeTickSize <= 0 {
+			return nil
+		}
+		if price <= 0 {
+			price = spec.Bid
+		}
+		value := volume * price * spec.TradeTickValue / spec.TradeTickSize
+		if !buy {
+			value = -value
+		}
+		exposures[symbol] += value
+		return nil
+	}
+
+	for _, pos := range data.PositionInfos {
+		buy := pos.Type == pb.BMT5_ENUM_POSITION_TYPE_BMT5_POSITION_TYPE_BUY
+		if err := addExposure(pos.Symbol, pos.Volume, pos.PriceCurrent, buy); err != nil {
+			return nil, fmt.Errorf("VaR calculation failed: %w", err)
+		}
+	}
+	for _, req := range extra {
+		if err := addExposure(req.Symbol, req.Volume, req.Price, req.IsBuy()); err != nil {
+			return nil, fmt.Errorf("VaR calculation failed: %w", err)
+		}
+	}
+
+	return c.calculate(exposures), nil
+}
+
+// calculate runs both VaR methods for the given exposures.
+func (c *VaRCalculator) calculate(exposures map[string]float64) *VaRResult {
+	result := &VaRResult{
+		Confidence:   c.confidence,
+		Exposures:    exposures,
+		Volatility:   make(map[string]float64),
+		Correlations: make(map[string]map[string]float64),
+	}
+
+	c.mu.Lock()
+	returns := make(map[string]map[time.Time]float64)
+	for symbol, exposure := range exposures {
+		if exposure == 0 {
+			continue
+		}
+		bars := c.history[symbol]
+		if len(bars) < 2 {
+			result.MissingHistory = append(result.MissingHistory, symbol)
+			continue
+		}
+		r := make(map[time.Time]float64, len(bars)-1)
+		for i := 1; i < len(bars); i++ {
+			if bars[i-1].Close > 0 {
+				r[bars[i].Time] = bars[i].Close/bars[i-1].Close - 1
+			}
+		}
+		returns[symbol] = r
+	}
+	c.mu.Unlock()
+
+	symbols := make([]string, 0, len(returns))
+	for symbol := range returns {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	sort.Strings(result.MissingHistory)
+
+	// Align returns on timestamps present for every symbol
+	var times []time.Time
+	if len(symbols) > 0 {
+		for t := range returns[symbols[0]] {
+			common := true
+			for _, symbol := range symbols[1:] {
+				if _, ok := returns[symbol][t]; !ok {
+					common = false
+					break
+				}
+			}
+			if common {
+				times = append(times, t)
+			}
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	result.Observations = len(times)
+	if len(times) < minVaRObservations {
+		return result
+	}
+
+	series := make([][]float64, len(symbols))
+	means := make([]float64, len(symbols))
+	stdevs := make([]float64, len(symbols))
+	for i, symbol := range symbols {
+		series[i] = make([]float64, len(times))
+		for k, t := range times {
+			series[i][k] = returns[symbol][t]
+			means[i] += series[i][k]
+		}
+		means[i] /= float64(len(times))
+		for _, v := range series[i] {
+			stdevs[i] += (v - means[i]) * (v - means[i])
+		}
+		stdevs[i] = math.Sqrt(stdevs[i] / float64(len(times)-1))
+		result.Volatility[symbol] = stdevs[i]
+	}
+
+	// Parametric: sigma_p^2 = sum_i sum_j e_i e_j s_i s_j rho_ij
+	variance := 0.0
+	for i, a := range symbols {
+		result.Correlations[a] = make(map[string]float64)
+		for j, b := range symbols {
+			rho := 1.0
+			if i != j {
+				rho = correlation(series[i], series[j], means[i], means[j], stdevs[i], stdevs[j])
+			}
+			result.Correlations[a][b] = rho
+			variance += exposures[a] * exposures[b] * stdevs[i] * stdevs[j] * rho
+		}
+	}
+	sigma := math.Sqrt(math.Max(variance, 0))
+	z := math.Sqrt2 * math.Erfinv(2*c.confidence-1)
+	density := math.Exp(-z*z/2) / math.Sqrt(2*math.Pi)
+	result.VaR = z * sigma
+	result.ExpectedShortfall = sigma * density / (1 - c.confidence)
+
+	// Historical: replay each aligned bar against today's exposure
+	pnl := make([]float64, len(times))
+	for k := range times {
+		for i, symbol := range symbols {
+			pnl[k] += exposures[symbol] * series[i][k]
+		}
+	}
+	sort.Float64s(pnl)
+	tail := int(math.Floor(float64(len(pnl)) * (1 - c.confidence)))
+	if tail < 1 {
+		tail = 1
+	}
+	result.HistoricalVaR = math.Max(-pnl[tail-1], 0)
+	sum := 0.0
+	for _, v := range pnl[:tail] {
+		sum += v
+	}
+	result.HistoricalES = math.Max(-sum/float64(tail), 0)
+
+	return result
+}
+
+// correlation returns the Pearson correlation of two equally long series.
+func correlation(a, b []float64, meanA, meanB, stdA, stdB float64) float64 {
+	if stdA == 0 || stdB == 0 || len(a) < 2 {
+		return 0
+	}
+	cov := 0.0
+	for k := range a {
+		cov += (a[k] - meanA) * (b[k] - meanB)
+	}
+	cov /= float64(len(a) - 1)
+	return cov / (stdA * stdB)
+}
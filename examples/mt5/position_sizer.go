@@ -0,0 +1,260 @@
+package mt5
+
+/*
+PositionSizer - pluggable lot sizing strategies.
+
+A PositionSizer turns account and symbol data into a lot size. The same
+sizer can be handed to Sugar helpers (SizePosition, BuyMarketSized,
+SellMarketSized) or to orchestrator configs (Sizer field), so the sizing
+rule is chosen once and used everywhere.
+
+Implementations:
+  • FixedLotSizer          - always the same volume
+  • FixedFractionalSizer   - risk a fixed % of equity per trade (needs SL)
+  • KellySizer             - risk the (fractional) Kelly % from win rate and payoff (needs SL)
+  • VolatilityTargetSizer  - size so one bar of typical movement costs a fixed % of equity
+  • EquityScaledSizer      - lots per 10,000 of equity, rescaled as equity changes
+
+Only FixedLotSizer raises a small size to the minimum lot. The computed
+sizers return ErrVolumeBelowMin instead, since the minimum lot would risk
+more than configured.
+
+Usage:
+    sizer := &mt5.FixedFractionalSizer{RiskPercent: 1.0}
+    ticket, err := sugar.BuyMarketSized("EURUSD", sizer, 200, 400)
+
+    // 0.02 lots per 10k: 0.02 at 10,000 equity, 0.05 at 25,000
+    sizer := &mt5.EquityScaledSizer{LotsPer10k: 0.02}
+*/
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrVolumeBelowMin is returned by the computed sizers when the volume for
+// the requested risk is smaller than the symbol's minimum lot. Trading the
+// minimum lot instead would risk more than asked, so no size is given.
+var ErrVolumeBelowMin = errors.New("volume below minimum lot")
+
+// SizingInput carries everything a sizer needs to compute volume.
+type SizingInput struct {
+	Symbol         string  // Trading symbol
+	Equity         float64 // Account equity
+	StopLossPoints float64 // Stop distance in points (0 = no stop)
+	Point          float64 // Symbol point size
+	PointValue     float64 // Value of 1 point for 1.0 lot, in account currency
+	VolumeMin      float64 // Minimum volume
+	VolumeMax      float64 // Maximum volume
+	VolumeStep     float64 // Volume step
+}
+
+// PositionSizer computes the lot size for a new position.
+type PositionSizer interface {
+	Size(in SizingInput) (float64, error)
+}
+
+// NormalizeVolume rounds volume down to the volume step and clamps it to min/max.
+// Meant for explicit sizes; computed sizes go through sizedVolume.
+func NormalizeVolume(volume float64, in SizingInput) float64 {
+	if in.VolumeStep > 0 {
+		volume = roundVolume(math.Floor(volume/in.VolumeStep+1e-9) * in.VolumeStep)
+	}
+	if volume < in.VolumeMin {
+		volume = in.VolumeMin
+	}
+	if in.VolumeMax > 0 && volume > in.VolumeMax {
+		volume = in.VolumeMax
+	}
+	return volume
+}
+
+// splitVolume rounds volume down to the volume step and splits it into as
+// few orders as VolumeMax allows, of near-equal size so that none falls
+// below VolumeMin. Returns nil if volume is less than one step.
+func splitVolume(volume float64, in SizingInput) []float64 {
+	step := in.VolumeStep
+	if step <= 0 {
+		step = 0.01
+	}
+	steps := int64(math.Floor(volume/step + 1e-9))
+	if steps <= 0 {
+		return nil
+	}
+	perOrder := steps
+	if in.VolumeMax > 0 {
+		perOrder = max(int64(math.Floor(in.VolumeMax/step+1e-9)), 1)
+	}
+
+	n := (steps + perOrder - 1) / perOrder
+	chunks := make([]float64, n)
+	for i := range chunks {
+		share := steps / n
+		if int64(i) < steps%n {
+			share++
+		}
+		chunks[i] = roundVolume(float64(share) * step)
+	}
+	return chunks
+}
+
+// sizedVolume normalizes a computed volume like NormalizeVolume, but returns
+// ErrVolumeBelowMin instead of raising it to the minimum lot.
+func sizedVolume(volume float64, in SizingInput) (float64, error) {
+	stepped := volume
+	if in.VolumeStep > 0 {
+		stepped = roundVolume(math.Floor(volume/in.VolumeStep+1e-9) * in.VolumeStep)
+	}
+	if stepped <= 0 || stepped < in.VolumeMin {
+		return 0, fmt.Errorf("%w: %s sized at %.4f lots, minimum is %v", ErrVolumeBelowMin, in.Symbol, volume, in.VolumeMin)
+	}
+	return NormalizeVolume(stepped, in), nil
+}
+
+// roundVolume removes float noise from step multiples (0.1*3 → 0.3).
+func roundVolume(volume float64) float64 {
+	return math.Round(volume*1e8) / 1e8
+}
+
+// riskToVolume converts a risk percentage of equity into lots for the given stop.
+func riskToVolume(riskPercent float64, in SizingInput) (float64, error) {
+	if in.StopLossPoints <= 0 {
+		return 0, fmt.Errorf("stop loss distance required for risk-based sizing")
+	}
+	if in.PointValue <= 0 {
+		return 0, fmt.Errorf("point value unknown for %s", in.Symbol)
+	}
+	riskAmount := in.Equity * riskPercent / 100.0
+	return sizedVolume(riskAmount/(in.StopLossPoints*in.PointValue), in)
+}
+
+// FixedLotSizer always returns Lots (normalized to symbol limits).
+type FixedLotSizer struct {
+	Lots float64
+}
+
+// Size implements PositionSizer.
+func (f *FixedLotSizer) Size(in SizingInput) (float64, error) {
+	return NormalizeVolume(f.Lots, in), nil
+}
+
+// FixedFractionalSizer risks RiskPercent of equity between entry and stop loss.
+type FixedFractionalSizer struct {
+	RiskPercent float64 // Risk per trade, % of equity (e.g., 1.0)
+}
+
+// Size implements PositionSizer.
+func (f *FixedFractionalSizer) Size(in SizingInput) (float64, error) {
+	return riskToVolume(f.RiskPercent, in)
+}
+
+// KellySizer risks a fraction of the Kelly-optimal percentage.
+//
+// Kelly % = WinRate - (1 - WinRate) / PayoffRatio. Full Kelly is very
+// aggressive; Fraction 0.25-0.5 is typical. MaxRiskPercent caps the result.
+type KellySizer struct {
+	WinRate        float64 // Probability of a winning trade (0-1)
+	PayoffRatio    float64 // Average win / average loss
+	Fraction       float64 // Share of full Kelly to use (default 0.5)
+	MaxRiskPercent float64 // Upper bound on risk per trade, % of equity (0 = none)
+}
+
+// NewKellySizer builds a KellySizer from performance statistics.
+//
+// Parameters:
+//   - winRatePercent: Win rate in percent (0-100), as reported by stats
+//   - avgWin: Average winning trade
+//   - avgLoss: Average losing trade (sign ignored)
+//   - fraction: Share of full Kelly to use
+//   - maxRiskPercent: Cap on risk per trade in percent
+func NewKellySizer(winRatePercent, avgWin, avgLoss, fraction, maxRiskPercent float64) *KellySizer {
+	payoff := 0.0
+	if avgLoss != 0 {
+		payoff = avgWin / math.Abs(avgLoss)
+	}
+	return &KellySizer{
+		WinRate:        winRatePercent / 100.0,
+		PayoffRatio:    payoff,
+		Fraction:       fraction,
+		MaxRiskPercent: maxRiskPercent,
+	}
+}
+
+// KellyPercent returns the risk percentage the sizer will use.
+func (k *KellySizer) KellyPercent() float64 {
+	if k.PayoffRatio <= 0 {
+		return 0
+	}
+	fraction := k.Fraction
+	if fraction <= 0 {
+		fraction = 0.5
+	}
+	kelly := (k.WinRate - (1-k.WinRate)/k.PayoffRatio) * fraction * 100.0
+	if kelly < 0 {
+		return 0
+	}
+	if k.MaxRiskPercent > 0 && kelly > k.MaxRiskPercent {
+		kelly = k.MaxRiskPercent
+	}
+	return kelly
+}
+
+// Size implements PositionSizer. Returns an error when the stats show no edge.
+func (k *KellySizer) Size(in SizingInput) (float64, error) {
+	riskPercent := k.KellyPercent()
+	if riskPercent <= 0 {
+		return 0, fmt.Errorf("kelly sizing: no positive edge (win rate %.2f, payoff %.2f)", k.WinRate, k.PayoffRatio)
+	}
+	return riskToVolume(riskPercent, in)
+}
+
+// VolatilityTargetSizer sizes so that a typical one-bar move (e.g., ATR)
+// costs TargetRiskPercent of equity. Volatile symbols get smaller lots.
+type VolatilityTargetSizer struct {
+	TargetRiskPercent float64                              // Equity % per typical move (e.g., 0.5)
+	Volatility        func(symbol string) (float64, error) // Typical move in price units (e.g., ATR)
+}
+
+// Size implements PositionSizer.
+func (v *VolatilityTargetSizer) Size(in SizingInput) (float64, error) {
+	if v.Volatility == nil {
+		return 0, fmt.Errorf("volatility source not set")
+	}
+	vol, err := v.Volatility(in.Symbol)
+	if err != nil {
+		return 0, fmt.Errorf("volatility for %s: %w", in.Symbol, err)
+	}
+	if vol <= 0 || in.Point <= 0 || in.PointValue <= 0 {
+		return 0, fmt.Errorf("volatility sizing: no data for %s", in.Symbol)
+	}
+	riskAmount := in.Equity * v.TargetRiskPercent / 100.0
+	return sizedVolume(riskAmount/(vol/in.Point*in.PointValue), in)
+}
+
+// EquityScaleBase is the equity that EquityScaledSizer lot sizes refer to.
+const EquityScaleBase = 10000.0
+
+// EquityScaledLots converts lots per EquityScaleBase of equity into lots for
+// the given equity (not normalized to symbol limits).
+func EquityScaledLots(lotsPer10k, equity float64) float64 {
+	if equity <= 0 {
+		return 0
+	}
+	return lotsPer10k * equity / EquityScaleBase
+}
+
+// EquityScaledSizer expresses size as lots per 10,000 of account equity, so
+// grids and scalers keep their risk proportional as the account grows or
+// shrinks. Unlike the risk-based sizers it needs no stop loss.
+type EquityScaledSizer struct {
+	LotsPer10k float64 // Lots per 10,000 of equity (e.g., 0.02)
+}
+
+// Size implements PositionSizer.
+func (e *EquityScaledSizer) Size(in SizingInput) (float64, error) {
+	if in.Equity <= 0 {
+		return 0, fmt.Errorf("equity scaling: equity unknown for %s", in.Symbol)
+	}
+	return sizedVolume(EquityScaledLots(e.LotsPer10k, in.Equity), in)
+}
@@ -0,0 +1,118 @@
+package mt5
+
+/*
+PositionWatcher - position lifecycle callbacks derived from the OnTrade stream.
+
+Instead of polling open positions, strategy code registers callbacks and
+reacts to positions being opened, modified (SL/TP, volume) or closed
+(manual close, SL hit, TP hit, stop out).
+
+Usage:
+    watcher := mt5.NewPositionWatcher(service)
+    watcher.OnClosed = func(pos *pb.OnTradePositionInfo, deal *pb.OnTradeHistoryDealInfo) {
+        if deal != nil && deal.Reason == pb.SUB_ENUM_DEAL_REASON_SUB_DEAL_REASON_SL {
+            fmt.Printf("#%d stopped out, profit %.2f\n", pos.Ticket, deal.Profit)
+        }
+    }
+    err := watcher.Run(ctx) // blocks until ctx is cancelled
+*/
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// PositionWatcher dispatches position lifecycle events to callbacks.
+// Callbacks are invoked sequentially from the goroutine running Run.
+// Any callback may be nil.
+type PositionWatcher struct {
+	service *MT5Service
+
+	// OnOpened is called when a new position appears. deal is the opening deal, if reported.
+	OnOpened func(position *pb.OnTradePositionInfo, deal *pb.OnTradeHistoryDealInfo)
+
+	// OnModified is called when SL/TP, volume or price of a position changes.
+	OnModified func(previous, current *pb.OnTradePositionInfo)
+
+	// OnClosed is called when a position disappears. deal is the closing deal
+	// (Reason tells SL/TP/manual/stop-out), or nil if not reported in the same event.
+	OnClosed func(position *pb.OnTradePositionInfo, deal *pb.OnTradeHistoryDealInfo)
+}
+
+// NewPositionWatcher creates a PositionWatcher on top of MT5Service.
+func NewPositionWatcher(service *MT5Service) *PositionWatcher {
+	return &PositionWatcher{
+		service: service,
+	}
+}
+
+// Run subscribes to trade events and dispatches callbacks until ctx is cancelled.
+//
+// Returns:
+//   - ctx.Err() when cancelled
+//   - Error if the trade stream fails
+func (w *PositionWatcher) Run(ctx context.Context) error {
+	dataCh, errCh := w.service.StreamTradeUpdates(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case err, ok := <-errCh:
+			if !ok {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("PositionWatcher stream failed: %w", err)
+			}
+
+		case data, ok := <-dataCh:
+			if !ok {
+				return nil
+			}
+			w.dispatch(data.GetEventData())
+		}
+	}
+}
+
+// dispatch maps a single OnTrade event to lifecycle callbacks.
+func (w *PositionWatcher) dispatch(event *pb.OnTadeEventData) {
+	if event == nil {
+		return
+	}
+
+	// Index deals by position so open/close callbacks can carry them
+	entryDeals := make(map[int64]*pb.OnTradeHistoryDealInfo)
+	exitDeals := make(map[int64]*pb.OnTradeHistoryDealInfo)
+	for _, deal := range event.NewHistoryDeals {
+		switch deal.Entry {
+		case pb.SUB_ENUM_DEAL_ENTRY_SUB_DEAL_ENTRY_IN:
+			entryDeals[deal.DealPositionId] = deal
+		case pb.SUB_ENUM_DEAL_ENTRY_SUB_DEAL_ENTRY_OUT,
+			pb.SUB_ENUM_DEAL_ENTRY_SUB_DEAL_ENTRY_OUT_BY,
+			pb.SUB_ENUM_DEAL_ENTRY_SUB_DEAL_ENTRY_INOUT:
+			exitDeals[deal.DealPositionId] = deal
+		}
+	}
+
+	if w.OnOpened != nil {
+		for _, pos := range event.NewPositions {
+			w.OnOpened(pos, entryDeals[pos.Ticket])
+		}
+	}
+
+	if w.OnModified != nil {
+		for _, upd := range event.UpdatedPositions {
+			w.OnModified(upd.PreviousPosition, upd.CurrentPosition)
+		}
+	}
+
+	if w.OnClosed != nil {
+		for _, pos := range event.DisappearedPositions {
+			w.OnClosed(pos, exitDeals[pos.Ticket])
+		}
+	}
+}
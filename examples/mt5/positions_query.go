@@ -0,0 +1,209 @@
+package mt5
+
+/*
+Positions query - one call to select open positions by several criteria.
+
+Orchestrators used to fetch every position and filter inline (tracked
+symbols, own magic, side, profit, age), each with its own loop.
+PositionsQuery does it in one place and returns typed positions:
+
+  • Symbols   - any of these symbols (resolved through the suffix resolver)
+  • Magics    - any of these magic numbers
+  • Direction - "BUY" or "SELL"
+  • MinProfit / MaxProfit - floating profit bounds (ProfitAtLeast/ProfitAtMost)
+  • MinAge / MaxAge - time since the position was opened
+  • Strategy  - owned by a strategy of the TagManager (see foreign_positions.go)
+
+Zero fields match everything. Positions are returned oldest first.
+
+Usage:
+    positions, err := sugar.PositionsQuery(mt5.PositionQuery{
+        Symbols:   []string{"EURUSD", "GBPUSD"},
+        Magics:    []int64{1001},
+        Direction: "BUY",
+        MinAge:    time.Hour,
+    }.ProfitAtMost(0))
+
+    for _, p := range positions {
+        fmt.Printf("#%d %s %s %.2f lots, %.2f after %s\n",
+            p.Ticket, p.Symbol, p.Direction, p.Volume, p.Profit, p.Age.Round(time.Minute))
+    }
+*/
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// PositionQuery selects open positions. Zero fields match everything.
+type PositionQuery struct {
+	Symbols   []string      // Any of these symbols (empty = all symbols)
+	Magics    []int64       // Any of these magic numbers (empty = any magic)
+	Direction string        // "BUY" or "SELL" ("" = both)
+	MinProfit *float64      // Floating profit >= MinProfit (nil = no bound)
+	MaxProfit *float64      // Floating profit <= MaxProfit (nil = no bound)
+	MinAge    time.Duration // Open for at least MinAge (0 = no bound)
+	MaxAge    time.Duration // Open for at most MaxAge (0 = no bound)
+	Strategy  string        // Owned by this strategy, adopted included (needs SetTagManager)
+}
+
+// ProfitAtLeast returns a copy of the query selecting positions with
+// floating profit of at least profit (e.g. 0 for profitable positions).
+func (q PositionQuery) ProfitAtLeast(profit float64) PositionQuery {
+	q.MinProfit = &profit
+	return q
+}
+
+// ProfitAtMost returns a copy of the query selecting positions with
+// floating profit of at most profit (e.g. -50 for positions losing at least 50).
+func (q PositionQuery) ProfitAtMost(profit float64) PositionQuery {
+	q.MaxProfit = &profit
+	return q
+}
+
+// Position is an open position with its direction and age decoded.
+type Position struct {
+	Ticket       uint64
+	Symbol       string
+	Direction    string // "BUY" or "SELL"
+	Volume       float64
+	PriceOpen    float64
+	PriceCurrent float64
+	StopLoss     float64
+	TakeProfit   float64
+	Profit       float64 // Floating profit, without swap and commission
+	Swap         float64
+	Commission   float64
+	Magic        int64
+	Comment      string
+	OpenTime     time.Time
+	Age          time.Duration // Time since OpenTime when the query ran
+	Info         *pb.PositionInfo
+}
+
+// IsBuy reports whether the position is a BUY.
+func (p Position) IsBuy() bool {
+	return p.Direction == "BUY"
+}
+
+// NetProfit returns profit + swap + commission.
+func (p Position) NetProfit() float64 {
+	return p.Profit + p.Swap + p.Commission
+}
+
+// newPosition decodes a PositionInfo at now.
+func newPosition(pos *pb.PositionInfo, now time.Time) Position {
+	p := Position{
+		Ticket:       pos.Ticket,
+		Symbol:       pos.Symbol,
+		Direction:    "BUY",
+		Volume:       pos.Volume,
+		PriceOpen:    pos.PriceOpen,
+		PriceCurrent: pos.PriceCurrent,
+		StopLoss:     pos.StopLoss,
+		TakeProfit:   pos.TakeProfit,
+		Profit:       pos.Profit,
+		Swap:         pos.Swap,
+		Commission:   pos.PositionCommission,
+		Magic:        pos.MagicNumber,
+		Comment:      pos.Comment,
+		Info:         pos,
+	}
+	if pos.Type == pb.BMT5_ENUM_POSITION_TYPE_BMT5_POSITION_TYPE_SELL {
+		p.Direction = "SELL"
+	}
+	if pos.OpenTime != nil {
+		p.OpenTime = pos.OpenTime.AsTime()
+		p.Age = now.Sub(p.OpenTime)
+	}
+	return p
+}
+
+// match reports whether a position passes the query. symbols holds the
+// resolved symbol names (nil = all).
+func (q PositionQuery) match(p Position, symbols map[string]bool) bool {
+	if symbols != nil && !symbols[p.Symbol] {
+		return false
+	}
+	if len(q.Magics) > 0 {
+		found := false
+		for _, magic := range q.Magics {
+			if p.Magic == magic {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if q.Direction != "" && p.Direction != strings.ToUpper(q.Direction) {
+		return false
+	}
+	if q.MinProfit != nil && p.Profit < *q.MinProfit {
+		return false
+	}
+	if q.MaxProfit != nil && p.Profit > *q.MaxProfit {
+		return false
+	}
+	if q.MinAge > 0 && p.Age < q.MinAge {
+		return false
+	}
+	if q.MaxAge > 0 && p.Age > q.MaxAge {
+		return false
+	}
+	return true
+}
+
+// PositionsQuery returns the open positions matching query, oldest first.
+// Uses 5-second timeout.
+//
+// Parameters:
+//   - query: Selection criteria (zero value = all positions)
+//
+// Returns:
+//   - Matching positions (empty slice if none), or error if positions could
+//     not be read, Direction is not "BUY", "SELL" or "", or Strategy is set
+//     without a TagManager
+func (s *MT5Sugar) PositionsQuery(query PositionQuery) ([]Position, error) {
+	switch strings.ToUpper(query.Direction) {
+	case "", "BUY", "SELL":
+	default:
+		return nil, fmt.Errorf("PositionsQuery failed: invalid direction %q (use BUY or SELL)", query.Direction)
+	}
+	if query.Strategy != "" && s.tags == nil {
+		return nil, fmt.Errorf("PositionsQuery failed: Strategy needs SetTagManager")
+	}
+
+	var symbols map[string]bool
+	if len(query.Symbols) > 0 {
+		symbols = make(map[string]bool, len(query.Symbols))
+		for _, symbol := range query.Symbols {
+			symbols[s.ResolveSymbol(symbol)] = true
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, 5*time.Second)
+	defer cancel()
+
+	data, err := s.service.GetOpenedOrders(ctx, pb.BMT5_ENUM_OPENED_ORDER_SORT_TYPE_BMT5_OPENED_ORDER_SORT_BY_OPEN_TIME_ASC)
+	if err != nil {
+		return nil, fmt.Errorf("PositionsQuery failed: %w", err)
+	}
+
+	now := time.Now()
+	result := []Position{}
+	for _, pos := range data.PositionInfos {
+		if query.Strategy != "" && s.ownership(pos).Strategy != query.Strategy {
+			continue
+		}
+		if p := newPosition(pos, now); query.match(p, symbols) {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,380 @@
+package mt5
+
+/*
+RegimeClassifier - volatility regime detection from locally built candles.
+
+Every closed candle of a symbol is classified from three measures:
+  • ATR percentile   - current ATR ranked against the ATR of the lookback window
+  • Efficiency       - net move / sum of absolute moves over TrendPeriod bars
+                       (-1..+1, sign = direction; near 0 = back and forth)
+  • Compression      - average bar range of the last CompressionBars bars
+                       relative to the average of the lookback window
+Realized volatility (standard deviation of close-to-close log returns) is
+reported alongside.
+
+Regimes, checked in this order:
+  • RegimeVolatile - ATR percentile >= VolatilePercentile
+  • RegimeTrending - |efficiency| >= TrendEfficiency
+  • RegimeQuiet    - ATR percentile <= QuietPercentile or compression <= CompressionRatio
+  • RegimeRanging  - everything else
+
+A new regime is only reported after it held for ConfirmBars consecutive
+candles, so a single noisy bar does not flip strategies back and forth.
+
+Usage:
+    classifier := mt5.NewRegimeClassifier(mt5.DefaultRegimeConfig())
+    changes := classifier.Subscribe(16)
+    go classifier.Run(ctx, service, []string{"EURUSD"})
+
+    for state := range changes {
+        fmt.Printf("%s is now %s (ATR pct %.0f, efficiency %+.2f)\n",
+            state.Symbol, state.Regime, state.ATRPercentile, state.Efficiency)
+    }
+
+    // Or poll the latest state
+    state, ok := classifier.Current("EURUSD")
+*/
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Regime is the market state of a symbol.
+type Regime int
+
+const (
+	RegimeUnknown  Regime = iota // Not enough candles yet
+	RegimeQuiet                  // Low volatility, compressed ranges
+	RegimeRanging                // Normal volatility without direction
+	RegimeTrending               // Directional movement
+	RegimeVolatile               // Volatility at the top of its recent range
+)
+
+func (r Regime) String() string {
+	switch r {
+	case RegimeQuiet:
+		return "quiet"
+	case RegimeRanging:
+		return "ranging"
+	case RegimeTrending:
+		return "trending"
+	case RegimeVolatile:
+		return "volatile"
+	default:
+		return "unknown"
+	}
+}
+
+// RegimeConfig holds classifier parameters.
+type RegimeConfig struct {
+	Timeframe          time.Duration // Candle size built from ticks
+	ATRPeriod          int           // Bars per ATR value
+	Lookback           int           // Bars the ATR percentile and compression are measured against
+	TrendPeriod        int           // Bars for efficiency and realized volatility
+	CompressionBars    int           // Recent bars compared against the lookback range
+	VolatilePercentile float64       // ATR percentile at or above which the market is volatile
+	QuietPercentile    float64       // ATR percentile at or below which the market is quiet
+	TrendEfficiency    float64       // |efficiency| at or above which the market is trending
+	CompressionRatio   float64       // Compression at or below which the market is quiet
+	ConfirmBars        int           // Consecutive candles a new regime must hold before it is reported
+}
+
+// DefaultRegimeConfig returns M5 candles with a 100-bar lookback.
+func DefaultRegimeConfig() RegimeConfig {
+	return RegimeConfig{
+		Timeframe:          5 * time.Minute,
+		ATRPeriod:          14,
+		Lookback:           100,
+		TrendPeriod:        20,
+		CompressionBars:    5,
+		VolatilePercentile: 80,
+		QuietPercentile:    20,
+		TrendEfficiency:    0.35,
+		CompressionRatio:   0.5,
+		ConfirmBars:        3,
+	}
+}
+
+// RegimeState is the classification of a symbol at a candle close.
+type RegimeState struct {
+	Symbol        string
+	Time          time.Time // Open time of the classified candle
+	Regime        Regime
+	Previous      Regime  // Regime before this candle
+	ATR           float64 // Current ATR (price units)
+	ATRPercentile float64 // 0-100 rank of ATR within the lookback window
+	RealizedVol   float64 // Std dev of log returns per bar over TrendPeriod
+	Efficiency    float64 // -1..+1 directional efficiency over TrendPeriod
+	Compression   float64 // Recent average range / lookback average range
+}
+
+// Changed reports whether the regime differs from the previous candle.
+func (s RegimeState) Changed() bool {
+	return s.Regime != s.Previous
+}
+
+// RegimeClassifier classifies symbols from their tick streams.
+// Safe for concurrent use.
+type RegimeClassifier struct {
+	config RegimeConfig
+
+	mu          sync.Mutex
+	builders    map[string]*CandleBuilder
+	history     map[string][]Candle
+	states      map[string]RegimeState
+	candidates  map[string]regimeCandidate
+	subscribers []chan RegimeState
+}
+
+// regimeCandidate is a regime waiting for ConfirmBars confirmation.
+type regimeCandidate struct {
+	regime Regime
+	bars   int
+}
+
+// NewRegimeClassifier creates a classifier; zero config fields take defaults.
+func NewRegimeClassifier(config RegimeConfig) *RegimeClassifier {
+	def := DefaultRegimeConfig()
+	if config.Timeframe <= 0 {
+		config.Timeframe = def.Timeframe
+	}
+	if config.ATRPeriod <= 0 {
+		config.ATRPeriod = def.ATRPeriod
+	}
+	if config.Lookback <= 0 {
+		config.Lookback = def.Lookback
+	}
+	if config.TrendPeriod <= 0 {
+		config.TrendPeriod = def.TrendPeriod
+	}
+	if config.CompressionBars <= 0 {
+		config.CompressionBars = def.CompressionBars
+	}
+	if config.VolatilePercentile <= 0 {
+		config.VolatilePercentile = def.VolatilePercentile
+	}
+	if config.TrendEfficiency <= 0 {
+		config.TrendEfficiency = def.TrendEfficiency
+	}
+	if config.QuietPercentile <= 0 {
+		config.QuietPercentile = def.QuietPercentile
+	}
+	if config.CompressionRatio <= 0 {
+		config.CompressionRatio = def.CompressionRatio
+	}
+	if config.ConfirmBars <= 0 {
+		config.ConfirmBars = def.ConfirmBars
+	}
+	return &RegimeClassifier{
+		config:     config,
+		builders:   make(map[string]*CandleBuilder),
+		history:    make(map[string][]Candle),
+		states:     make(map[string]RegimeState),
+		candidates: make(map[string]regimeCandidate),
+	}
+}
+
+// Subscribe returns a channel receiving the state of every regime change.
+// Changes are dropped for a subscriber whose buffer is full.
+func (c *RegimeClassifier) Subscribe(buffer int) <-chan RegimeState {
+	ch := make(chan RegimeState, buffer)
+	c.mu.Lock()
+	c.subscribers = append(c.subscribers, ch)
+	c.mu.Unlock()
+	return ch
+}
+
+// Current returns the latest state of a symbol.
+func (c *RegimeClassifier) Current(symbol string) (RegimeState, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	state, ok := c.states[symbol]
+	return state, ok
+}
+
+// Observe feeds a tick; a classification runs each time a candle closes.
+func (c *RegimeClassifier) Observe(tick *SymbolTick) {
+	if tick == nil || tick.Symbol == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	builder, ok := c.builders[tick.Symbol]
+	if !ok {
+		builder = NewCandleBuilder(tick.Symbol, c.config.Timeframe)
+		c.builders[tick.Symbol] = builder
+	}
+	if closed := builder.Add(tick); closed != nil {
+		c.addLocked(*closed)
+	}
+}
+
+// AddCandles feeds already built candles (e.g., from a CandleCache) in
+// chronological order; each one is classified as it is added.
+func (c *RegimeClassifier) AddCandles(candles []Candle) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, candle := range candles {
+		c.addLocked(candle)
+	}
+}
+
+// Run streams ticks of the symbols into the classifier until ctx is done or
+// the stream fails. Subscriber channels are closed when Run returns.
+func (c *RegimeClassifier) Run(ctx context.Context, service *MT5Service, symbols []string) error {
+	defer c.closeSubscribers()
+
+	ticks, errs := service.StreamTicks(ctx, symbols)
+	for {
+		select {
+		case tick, ok := <-ticks:
+			if !ok {
+				return nil
+			}
+			c.Observe(tick)
+		case err, ok := <-errs:
+			if !ok {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("RegimeClassifier: %w", err)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// closeSubscribers closes and forgets all subscriber channels.
+func (c *RegimeClassifier) closeSubscribers() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, ch := range c.subscribers {
+		close(ch)
+	}
+	c.subscribers = nil
+}
+
+// addLocked stores a candle, classifies the symbol and notifies on change.
+func (c *RegimeClassifier) addLocked(candle Candle) {
+	keep := c.config.Lookback + c.config.ATRPeriod + 1
+	bars := append(c.history[candle.Symbol], candle)
+	if len(bars) > keep {
+		bars = bars[len(bars)-keep:]
+	}
+	c.history[candle.Symbol] = bars
+
+	prev := c.states[candle.Symbol]
+	state := c.classify(bars)
+	state.Symbol = candle.Symbol
+	state.Time = candle.Time
+	state.Previous = prev.Regime
+
+	// Keep the previous regime until the new one is confirmed
+	if state.Regime == prev.Regime {
+		delete(c.candidates, candle.Symbol)
+	} else if prev.Regime != RegimeUnknown {
+		candidate := c.candidates[candle.Symbol]
+		if candidate.regime != state.Regime {
+			candidate = regimeCandidate{regime: state.Regime}
+		}
+		candidate.bars++
+		if candidate.bars < c.config.ConfirmBars {
+			c.candidates[candle.Symbol] = candidate
+			state.Regime = prev.Regime
+		} else {
+			delete(c.candidates, candle.Symbol)
+		}
+	}
+	c.states[candle.Symbol] = state
+
+	if !state.Changed() {
+		return
+	}
+	for _, ch := range c.subscribers {
+		select {
+		case ch <- state:
+		default:
+		}
+	}
+}
+
+// classify computes the regime measures of the most recent bars.
+func (c *RegimeClassifier) classify(bars []Candle) RegimeState {
+	cfg := c.config
+	var state RegimeState
+	if len(bars) < cfg.ATRPeriod+1 || len(bars) < cfg.TrendPeriod+1 {
+		return state
+	}
+
+	// ATR now, ranked against the ATR at each bar of the lookback window
+	state.ATR = AverageTrueRange(bars, cfg.ATRPeriod)
+	var below, samples int
+	for end := cfg.ATRPeriod + 1; end <= len(bars); end++ {
+		atr := AverageTrueRange(bars[:end], cfg.ATRPeriod)
+		samples++
+		if atr <= state.ATR {
+			below++
+		}
+	}
+	state.ATRPercentile = float64(below) / float64(samples) * 100
+
+	// Efficiency and realized volatility over TrendPeriod
+	recent := bars[len(bars)-cfg.TrendPeriod-1:]
+	var path, sum, sumSq float64
+	for i := 1; i < len(recent); i++ {
+		path += math.Abs(recent[i].Close - recent[i-1].Close)
+		if recent[i-1].Close > 0 && recent[i].Close > 0 {
+			r := math.Log(recent[i].Close / recent[i-1].Close)
+			sum += r
+			sumSq += r * r
+		}
+	}
+	if path > 0 {
+		state.Efficiency = (recent[len(recent)-1].Close - recent[0].Close) / path
+	}
+	n := float64(cfg.TrendPeriod)
+	if variance := sumSq/n - (sum/n)*(sum/n); variance > 0 {
+		state.RealizedVol = math.Sqrt(variance)
+	}
+
+	// Compression: recent bar ranges against the lookback window
+	window := bars
+	if len(window) > cfg.Lookback {
+		window = window[len(window)-cfg.Lookback:]
+	}
+	var recentRange, allRange float64
+	for i, bar := range window {
+		allRange += bar.High - bar.Low
+		if i >= len(window)-cfg.CompressionBars {
+			recentRange += bar.High - bar.Low
+		}
+	}
+	recentBars := math.Min(float64(cfg.CompressionBars), float64(len(window)))
+	if allRange > 0 {
+		state.Compression = (recentRange / recentBars) / (allRange / float64(len(window)))
+	}
+
+	// Percentile ranks are meaningless on a short window: wait for Lookback/2 bars
+	if samples < cfg.Lookback/2 {
+		return state
+	}
+	switch {
+	case state.ATRPercentile >= cfg.VolatilePercentile:
+		state.Regime = RegimeVolatile
+	case math.Abs(state.Efficiency) >= cfg.TrendEfficiency:
+		state.Regime = RegimeTrending
+	case state.ATRPercentile <= cfg.QuietPercentile || state.Compression <= cfg.CompressionRatio:
+		state.Regime = RegimeQuiet
+	default:
+		state.Regime = RegimeRanging
+	}
+	return state
+}
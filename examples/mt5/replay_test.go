@@ -0,0 +1,99 @@
+package mt5
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+	helpers "github.com/MetaRPC/GoMT5/package/Helpers"
+	"github.com/google/uuid"
+)
+
+// replayService returns a service whose account answers from a fixture file
+// in testdata, and the replayer to check that the scenario was used up.
+func replayService(t *testing.T, file string) (*MT5Service, *helpers.Replayer) {
+	t.Helper()
+	fixtures, err := helpers.LoadFixtures("testdata/" + file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayer := helpers.NewReplayer(fixtures)
+	replayer.MatchRequests = true
+
+	account, err := helpers.NewReplayAccount(1, "", uuid.New(), replayer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { account.Close() })
+
+	if _, err := account.ConnectEx(context.Background(), &pb.ConnectExRequest{User: 1}); err != nil {
+		t.Fatalf("ConnectEx: %v", err)
+	}
+	return NewMT5Service(account), replayer
+}
+
+// TestReplayMarketRoundTrip replays a BUY of 0.1 lot closed at the bid
+// (recorded from fakeserver): the spread of 2 points costs 2 USD.
+func TestReplayMarketRoundTrip(t *testing.T) {
+	service, replayer := replayService(t, "market_round_trip.json")
+	ctx := context.Background()
+
+	before, err := service.GetAccountSummary(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before.Balance != 10000 || before.Currency != "USD" {
+		t.Fatalf("summary = %v %s, want 10000 USD", before.Balance, before.Currency)
+	}
+
+	tick, err := service.GetSymbolTick(ctx, "EURUSD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tick.Bid != 1.1 || tick.Ask != 1.1002 {
+		t.Fatalf("tick = %v/%v, want 1.1/1.1002", tick.Bid, tick.Ask)
+	}
+
+	result, err := service.SendOrder(ctx, OrderRequest{
+		Symbol: "EURUSD",
+		Type:   pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY,
+		Volume: 0.1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := result.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if result.Price != tick.Ask {
+		t.Errorf("filled at %v, want ask %v", result.Price, tick.Ask)
+	}
+
+	opened, err := service.GetOpenedOrders(ctx, pb.BMT5_ENUM_OPENED_ORDER_SORT_TYPE_BMT5_OPENED_ORDER_SORT_BY_OPEN_TIME_ASC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opened.PositionInfos) != 1 || opened.PositionInfos[0].Ticket != result.Order {
+		t.Fatalf("positions = %v, want #%d", opened.PositionInfos, result.Order)
+	}
+
+	code, err := service.CloseOrder(ctx, &pb.OrderCloseRequest{Ticket: result.Order})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != helpers.TradeRetCodeDone {
+		t.Fatalf("CloseOrder = %d, want %d", code, helpers.TradeRetCodeDone)
+	}
+
+	after, err := service.GetAccountSummary(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Balance != 9998 {
+		t.Errorf("balance after round trip = %v, want 9998", after.Balance)
+	}
+
+	if left := replayer.Remaining(); len(left) != 0 {
+		t.Errorf("%d fixture(s) not replayed, first %s", len(left), left[0].Method)
+	}
+}
@@ -0,0 +1,222 @@
+package mt5
+
+/*
+Sessions - time relative to trading sessions.
+
+Symbol sessions come from the broker (SymbolInfoSessionTrade) and are in
+SERVER time, so set the broker timezone first (SetServerTimezone). Sessions
+that end at midnight and continue at 00:00 the next day (FX Monday-Friday)
+are joined into one window, so TimeUntilSessionClose on a Tuesday evening
+reports the real close, not midnight.
+
+Market centers (London, New York, Tokyo, Sydney) use their local business
+hours with daylight saving time, independent of any broker.
+
+Usage:
+    sugar.SetServerTimezone(time.FixedZone("EET", 2*3600))
+
+    left, err := sugar.TimeUntilSessionClose("US500")
+    if err == nil && left < 15*time.Minute {
+        sugar.CloseAllBySymbol("US500") // flat before the close
+    }
+
+    if minutes, err := sugar.MinutesIntoSession("GER40"); err == nil && minutes < 30 {
+        // skip the opening auction volatility
+    }
+
+    if mt5.IsLondonOpen() && mt5.IsNewYorkOpen() {
+        // London/New York overlap
+    }
+*/
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// SessionWindow is one continuous trading session in server time.
+type SessionWindow struct {
+	Open  time.Time
+	Close time.Time
+}
+
+// sessionRange is a session as offsets from the start of its day.
+type sessionRange struct {
+	from, to time.Duration
+}
+
+// tradeSessions returns the trade sessions of a weekday. Sessions are
+// returned as time-of-day offsets; the index past the last one fails or is empty.
+func (s *MT5Sugar) tradeSessions(ctx context.Context, symbol string, day time.Weekday) ([]sessionRange, error) {
+	var sessions []sessionRange
+	for i := uint32(0); i < 10; i++ {
+		session, err := s.service.GetSymbolSessionTrade(ctx, symbol, pb.DayOfWeek(day), i)
+		if err != nil {
+			if i == 0 {
+				return nil, err
+			}
+			break
+		}
+		from := time.Duration(session.From.Unix()) * time.Second
+		to := time.Duration(session.To.Unix()) * time.Second
+		if from == 0 && to == 0 {
+			break
+		}
+		if to <= from {
+			to += 24 * time.Hour
+		}
+		sessions = append(sessions, sessionRange{from: from, to: to})
+	}
+	return sessions, nil
+}
+
+// isHoliday reports whether the server-time date is a holiday for the symbol.
+func (s *MT5Sugar) isHoliday(symbol string, t time.Time) bool {
+	date := t.In(s.serverLoc).Format("2006-01-02")
+	s.calMu.RLock()
+	defer s.calMu.RUnlock()
+	return s.holidays["|"+date] || s.holidays[symbol+"|"+date]
+}
+
+// CurrentSession returns the trade session the symbol is in right now, with
+// sessions continuing over midnight joined (up to a week ahead). Uses
+// 10-second timeout.
+//
+// Parameters:
+//   - symbol: Trading symbol (e.g., "EURUSD")
+//
+// Returns:
+//   - Session in server time, ErrMarketClosed outside sessions and on
+//     holidays, or error if session info cannot be read
+func (s *MT5Sugar) CurrentSession(symbol string) (*SessionWindow, error) {
+	symbol = s.ResolveSymbol(symbol)
+
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+	defer cancel()
+
+	now := time.Now().In(s.serverLoc)
+	if s.isHoliday(symbol, now) {
+		return nil, fmt.Errorf("%w: %s", ErrMarketClosed, symbol)
+	}
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.serverLoc)
+
+	sessions, err := s.tradeSessions(ctx, symbol, now.Weekday())
+	if err != nil {
+		return nil, fmt.Errorf("CurrentSession failed: %w", err)
+	}
+	var window *SessionWindow
+	for _, session := range sessions {
+		opens, closes := midnight.Add(session.from), midnight.Add(session.to)
+		if !now.Before(opens) && now.Before(closes) {
+			window = &SessionWindow{Open: opens, Close: closes}
+			break
+		}
+	}
+	if window == nil {
+		return nil, fmt.Errorf("%w: %s", ErrMarketClosed, symbol)
+	}
+
+	// Extend over midnight while the next day starts trading at 00:00
+	for day := 1; day <= 7 && window.Close.Hour() == 0 && window.Close.Minute() == 0; day++ {
+		next := time.Date(now.Year(), now.Month(), now.Day()+day, 0, 0, 0, 0, s.serverLoc)
+		if !window.Close.Equal(next) || s.isHoliday(symbol, next) {
+			break
+		}
+		sessions, err := s.tradeSessions(ctx, symbol, next.Weekday())
+		if err != nil || len(sessions) == 0 || sessions[0].from != 0 {
+			break
+		}
+		window.Close = next.Add(sessions[0].to)
+	}
+
+	return window, nil
+}
+
+// TimeUntilSessionClose returns how long the symbol's current trade session
+// stays open. Uses 10-second timeout.
+//
+// Parameters:
+//   - symbol: Trading symbol (e.g., "EURUSD")
+//
+// Returns:
+//   - Time until close, ErrMarketClosed outside sessions, or error if
+//     session info cannot be read
+func (s *MT5Sugar) TimeUntilSessionClose(symbol string) (time.Duration, error) {
+	window, err := s.CurrentSession(symbol)
+	if err != nil {
+		return 0, err
+	}
+	return time.Until(window.Close), nil
+}
+
+// MinutesIntoSession returns the whole minutes since the symbol's current
+// trade session opened. Uses 10-second timeout.
+//
+// Parameters:
+//   - symbol: Trading symbol (e.g., "EURUSD")
+//
+// Returns:
+//   - Minutes since open, ErrMarketClosed outside sessions, or error if
+//     session info cannot be read
+func (s *MT5Sugar) MinutesIntoSession(symbol string) (int, error) {
+	window, err := s.CurrentSession(symbol)
+	if err != nil {
+		return 0, err
+	}
+	return int(time.Since(window.Open) / time.Minute), nil
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MARKET CENTERS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// MarketCenter is a financial center with local business hours (Monday-Friday).
+type MarketCenter struct {
+	Name     string
+	Location *time.Location
+	Open     time.Duration // Local time of day the session opens
+	Close    time.Duration // Local time of day the session closes
+}
+
+// loadZone loads an IANA timezone, falling back to a fixed offset (no DST)
+// where the system has no timezone database.
+func loadZone(name string, offsetHours int) *time.Location {
+	if loc, err := time.LoadLocation(name); err == nil {
+		return loc
+	}
+	return time.FixedZone(name, offsetHours*3600)
+}
+
+// Major FX market centers.
+var (
+	London  = MarketCenter{Name: "London", Location: loadZone("Europe/London", 0), Open: 8 * time.Hour, Close: 17 * time.Hour}
+	NewYork = MarketCenter{Name: "New York", Location: loadZone("America/New_York", -5), Open: 8 * time.Hour, Close: 17 * time.Hour}
+	Tokyo   = MarketCenter{Name: "Tokyo", Location: loadZone("Asia/Tokyo", 9), Open: 9 * time.Hour, Close: 18 * time.Hour}
+	Sydney  = MarketCenter{Name: "Sydney", Location: loadZone("Australia/Sydney", 10), Open: 7 * time.Hour, Close: 16 * time.Hour}
+)
+
+// IsOpen reports whether t falls inside the center's business hours.
+func (m MarketCenter) IsOpen(t time.Time) bool {
+	local := t.In(m.Location)
+	if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+		return false
+	}
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, m.Location)
+	sinceMidnight := local.Sub(midnight)
+	return sinceMidnight >= m.Open && sinceMidnight < m.Close
+}
+
+// IsLondonOpen reports whether the London session is open now.
+func IsLondonOpen() bool { return London.IsOpen(time.Now()) }
+
+// IsNewYorkOpen reports whether the New York session is open now.
+func IsNewYorkOpen() bool { return NewYork.IsOpen(time.Now()) }
+
+// IsTokyoOpen reports whether the Tokyo session is open now.
+func IsTokyoOpen() bool { return Tokyo.IsOpen(time.Now()) }
+
+// IsSydneyOpen reports whether the Sydney session is open now.
+func IsSydneyOpen() bool { return Sydney.IsOpen(time.Now()) }
@@ -0,0 +1,197 @@
+package mt5
+
+/*
+StalenessMonitor - detects frozen quote feeds per symbol.
+
+A tick stream can stay "connected" while no ticks arrive (server hiccup,
+symbol halted, silent network stall). Strategies that keep using the last
+known price then trade on stale data. StalenessMonitor records the receive
+time of the last tick per symbol and raises a StalenessEvent when a symbol
+has been silent longer than the threshold DURING TRADING HOURS (outside
+sessions silence is normal), and a recovery event when ticks resume.
+
+Usage:
+    monitor := mt5.NewStalenessMonitor(10*time.Second, func(symbol string) bool {
+        open, err := sugar.IsTradingTime(symbol)
+        return err != nil || open
+    })
+    monitor.Watch("EURUSD", "GBPUSD")
+    go monitor.Run(ctx, time.Second)
+
+    for tick := range ticks {
+        monitor.Observe(tick)
+        if monitor.IsStale(tick.Symbol) { ... }
+    }
+
+    for event := range monitor.Events() {
+        fmt.Printf("%s stale=%v gap=%v\n", event.Symbol, !event.Recovered, event.Gap)
+    }
+*/
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// StalenessEvent reports a symbol going stale or recovering.
+type StalenessEvent struct {
+	Symbol    string
+	LastTick  time.Time     // Receive time of the last tick before the gap
+	Gap       time.Duration // Silence so far (stale) or total gap (recovered)
+	Recovered bool          // false = went stale, true = ticks resumed
+	Time      time.Time     // When the event was raised
+}
+
+// StalenessMonitor tracks last-tick times and raises staleness events.
+// Safe for concurrent use.
+type StalenessMonitor struct {
+	threshold time.Duration
+	isOpen    func(symbol string) bool
+
+	mu     sync.RWMutex
+	last   map[string]time.Time
+	stale  map[string]bool
+	events chan StalenessEvent
+}
+
+// NewStalenessMonitor creates a monitor.
+//
+// Parameters:
+//   - threshold: Silence after which a symbol is stale (default 10s)
+//   - isOpen: Reports whether a symbol is in trading hours (nil = always)
+func NewStalenessMonitor(threshold time.Duration, isOpen func(symbol string) bool) *StalenessMonitor {
+	if threshold <= 0 {
+		threshold = 10 * time.Second
+	}
+	return &StalenessMonitor{
+		threshold: threshold,
+		isOpen:    isOpen,
+		last:      make(map[string]time.Time),
+		stale:     make(map[string]bool),
+		events:    make(chan StalenessEvent, 100),
+	}
+}
+
+// Watch starts tracking symbols. The silence clock starts now, so a symbol
+// that never ticks is reported too.
+func (m *StalenessMonitor) Watch(symbols ...string) {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, symbol := range symbols {
+		if _, ok := m.last[symbol]; !ok {
+			m.last[symbol] = now
+		}
+	}
+}
+
+// Observe records a tick's arrival. Receive time is used rather than the
+// tick's server time, so clock offsets do not matter.
+func (m *StalenessMonitor) Observe(tick *SymbolTick) {
+	if tick == nil {
+		return
+	}
+	now := time.Now()
+
+	m.mu.Lock()
+	prev := m.last[tick.Symbol]
+	wasStale := m.stale[tick.Symbol]
+	m.last[tick.Symbol] = now
+	m.stale[tick.Symbol] = false
+	m.mu.Unlock()
+
+	if wasStale {
+		m.emit(StalenessEvent{
+			Symbol:    tick.Symbol,
+			LastTick:  prev,
+			Gap:       now.Sub(prev),
+			Recovered: true,
+			Time:      now,
+		})
+	}
+}
+
+// Check evaluates all watched symbols and raises events for newly stale ones.
+func (m *StalenessMonitor) Check() {
+	now := time.Now()
+
+	m.mu.RLock()
+	var candidates []string
+	for symbol, last := range m.last {
+		if !m.stale[symbol] && now.Sub(last) > m.threshold {
+			candidates = append(candidates, symbol)
+		}
+	}
+	m.mu.RUnlock()
+
+	for _, symbol := range candidates {
+		if m.isOpen != nil && !m.isOpen(symbol) {
+			continue
+		}
+
+		m.mu.Lock()
+		last := m.last[symbol]
+		// A tick may have arrived while checking trading hours
+		if m.stale[symbol] || now.Sub(last) <= m.threshold {
+			m.mu.Unlock()
+			continue
+		}
+		m.stale[symbol] = true
+		m.mu.Unlock()
+
+		m.emit(StalenessEvent{
+			Symbol:   symbol,
+			LastTick: last,
+			Gap:      now.Sub(last),
+			Time:     now,
+		})
+	}
+}
+
+// Run calls Check every interval until ctx is cancelled.
+func (m *StalenessMonitor) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Check()
+		}
+	}
+}
+
+// Events returns the event channel. Events are dropped if nobody reads it.
+func (m *StalenessMonitor) Events() <-chan StalenessEvent {
+	return m.events
+}
+
+// IsStale reports whether a symbol is currently flagged stale.
+func (m *StalenessMonitor) IsStale(symbol string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.stale[symbol]
+}
+
+// LastTick returns the receive time of the symbol's last tick (zero if unknown).
+func (m *StalenessMonitor) LastTick(symbol string) time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.last[symbol]
+}
+
+// emit sends an event without blocking.
+func (m *StalenessMonitor) emit(event StalenessEvent) {
+	select {
+	case m.events <- event:
+	default:
+	}
+}
@@ -0,0 +1,433 @@
+package mt5
+
+/*
+Daily statement - end-of-day account report rendered to HTML and mailed.
+
+The statement covers one server day (see SetServerTimezone) and contains:
+  • Account      - balance, equity, margin, free margin at generation time
+  • Summary      - realized P/L, win rate, volume, commission, swap, fees
+  • Positions    - open positions with floating P/L
+  • Trades       - positions closed during the day
+  • Equity curve - inline SVG sparkline (from an EquityTracker, optional)
+
+Mail delivery goes through the MailSender interface; SMTPSender covers
+plain SMTP, and MailSenderFunc adapts any API client (SES, SendGrid, ...).
+
+Usage:
+    sender := &mt5.SMTPSender{Addr: "smtp.example.com:587", From: "bot@example.com",
+        Auth: smtp.PlainAuth("", "bot@example.com", password, "smtp.example.com")}
+
+    go sugar.RunDailyStatements(ctx, mt5.StatementSchedule{
+        At:         23*time.Hour + 55*time.Minute,
+        Recipients: []string{"me@example.com"},
+        Sender:     sender,
+        Equity:     tracker,
+    })
+*/
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"math"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// MailMessage is an HTML email.
+type MailMessage struct {
+	To      []string
+	Subject string
+	HTML    string
+}
+
+// MailSender delivers email.
+type MailSender interface {
+	Send(ctx context.Context, msg MailMessage) error
+}
+
+// MailSenderFunc adapts a function to MailSender.
+type MailSenderFunc func(ctx context.Context, msg MailMessage) error
+
+// Send calls f.
+func (f MailSenderFunc) Send(ctx context.Context, msg MailMessage) error {
+	return f(ctx, msg)
+}
+
+// SMTPSender sends mail through an SMTP server (STARTTLS when offered).
+type SMTPSender struct {
+	Addr string    // host:port
+	From string    // Envelope and header sender
+	Auth smtp.Auth // nil = no authentication
+}
+
+// Send delivers msg. ctx is not observed by net/smtp; the call blocks until the server answers.
+func (s *SMTPSender) Send(ctx context.Context, msg MailMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "From: %s\r\n", s.From)
+	fmt.Fprintf(&body, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&body, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&body, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/html; charset=UTF-8\r\n\r\n")
+	body.WriteString(msg.HTML)
+
+	if err := smtp.SendMail(s.Addr, s.Auth, s.From, msg.To, body.Bytes()); err != nil {
+		return fmt.Errorf("send statement mail: %w", err)
+	}
+	return nil
+}
+
+// StatementPosition is an open position in a statement.
+type StatementPosition struct {
+	Ticket       uint64
+	Symbol       string
+	Type         string // BUY / SELL
+	Volume       float64
+	OpenPrice    float64
+	CurrentPrice float64
+	StopLoss     float64
+	TakeProfit   float64
+	Swap         float64
+	Profit       float64
+}
+
+// StatementTrade is a position closed during the statement day.
+type StatementTrade struct {
+	Ticket     uint64
+	Symbol     string
+	Type       string
+	Volume     float64
+	OpenTime   time.Time
+	CloseTime  time.Time
+	OpenPrice  float64
+	ClosePrice float64
+	Profit     float64
+	Swap       float64
+	Commission float64
+	Fee        float64
+	Net        float64
+}
+
+// Statement is the end-of-day report of one server day.
+type Statement struct {
+	Day        time.Time // Server midnight starting the day
+	Generated  time.Time
+	Login      int64
+	Company    string
+	Currency   string
+	Balance    float64
+	Equity     float64
+	Margin     float64
+	FreeMargin float64
+	Floating   float64 // Sum of open position profit
+
+	Summary     *TradingSummaryResult
+	Positions   []StatementPosition
+	Trades      []StatementTrade
+	EquityCurve []EquityPoint // Samples inside the day (may be empty)
+
+	Format *Formatter // Number format of HTML and Subject (nil = plain, 2 decimals)
+}
+
+// BuildStatement collects the statement of the server day containing day.
+//
+// Parameters:
+//   - day: Any time inside the wanted server day
+//   - curve: Equity samples (e.g., EquityTracker.Curve()); filtered to the day, may be nil
+//
+// Returns:
+//   - *Statement, or error if account data or history could not be read
+func (s *MT5Sugar) BuildStatement(day time.Time, curve []EquityPoint) (*Statement, error) {
+	local := day.In(s.serverLoc)
+	from := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.serverLoc)
+	to := from.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	if now := time.Now(); to.After(now) {
+		to = now
+	}
+
+	account, err := s.GetAccountInfo()
+	if err != nil {
+		return nil, fmt.Errorf("BuildStatement failed: %w", err)
+	}
+	summary, closed, err := s.summarizeClosed(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("BuildStatement failed: %w", err)
+	}
+	positions, err := s.GetOpenPositions()
+	if err != nil {
+		return nil, fmt.Errorf("BuildStatement failed: %w", err)
+	}
+
+	st := &Statement{
+		Day:        from,
+		Generated:  time.Now().In(s.serverLoc),
+		Login:      account.Login,
+		Company:    account.Company,
+		Currency:   account.Currency,
+		Balance:    account.Balance,
+		Equity:     account.Equity,
+		Margin:     account.Margin,
+		FreeMargin: account.FreeMargin,
+		Summary:    summary,
+		Format:     s.newFormatter(s.locale, account.Currency),
+	}
+
+	for _, pos := range positions {
+		st.Positions = append(st.Positions, StatementPosition{
+			Ticket:       pos.Ticket,
+			Symbol:       pos.Symbol,
+			Type:         strings.TrimPrefix(pos.Type.String(), "BMT5_POSITION_TYPE_"),
+			Volume:       pos.Volume,
+			OpenPrice:    pos.PriceOpen,
+			CurrentPrice: pos.PriceCurrent,
+			StopLoss:     pos.StopLoss,
+			TakeProfit:   pos.TakeProfit,
+			Swap:         pos.Swap,
+			Profit:       pos.Profit,
+		})
+		st.Floating += pos.Profit
+	}
+
+	for _, c := range closed {
+		trade := StatementTrade{
+			Ticket:     c.info.PositionTicket,
+			Symbol:     c.info.Symbol,
+			Type:       strings.TrimPrefix(c.info.OrderType.String(), "AH_ORDER_TYPE_"),
+			Volume:     c.info.Volume,
+			OpenPrice:  c.info.OpenPrice,
+			ClosePrice: c.info.ClosePrice,
+			Profit:     c.info.Profit,
+			Swap:       c.info.Swap,
+			Commission: c.commission,
+			Fee:        c.info.Fee,
+			Net:        c.net,
+		}
+		if c.info.OpenTime != nil {
+			trade.OpenTime = c.info.OpenTime.AsTime().In(s.serverLoc)
+		}
+		if c.info.CloseTime != nil {
+			trade.CloseTime = c.info.CloseTime.AsTime().In(s.serverLoc)
+		}
+		st.Trades = append(st.Trades, trade)
+	}
+
+	for _, p := range curve {
+		if !p.Time.Before(from) && !p.Time.After(to) {
+			st.EquityCurve = append(st.EquityCurve, p)
+		}
+	}
+
+	return st, nil
+}
+
+// Subject returns the default mail subject.
+func (st *Statement) Subject() string {
+	if st.Format != nil {
+		return fmt.Sprintf("Statement %d %s: %s", st.Login, st.Day.Format("2006-01-02"), st.Format.SignedMoney(st.Summary.RealizedPnL))
+	}
+	return fmt.Sprintf("Statement %d %s: %+.2f %s", st.Login, st.Day.Format("2006-01-02"), st.Summary.RealizedPnL, st.Currency)
+}
+
+// HTML renders the statement as a self-contained HTML document (inline CSS and SVG).
+func (st *Statement) HTML() (string, error) {
+	tmpl := statementTemplate
+	if st.Format != nil {
+		clone, err := statementTemplate.Clone()
+		if err != nil {
+			return "", fmt.Errorf("render statement: %w", err)
+		}
+		tmpl = clone.Funcs(template.FuncMap{
+			"money": st.Format.Money,
+			"price": st.Format.Price,
+			"lots":  st.Format.Volume,
+			"number": func(v float64) string {
+				return st.Format.Locale.FormatNumber(v, 2)
+			},
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, st); err != nil {
+		return "", fmt.Errorf("render statement: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// Sparkline renders an equity curve as an inline SVG polyline.
+// Returns an empty string for fewer than two points.
+func Sparkline(points []EquityPoint, width, height int) template.HTML {
+	if len(points) < 2 {
+		return ""
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, p := range points {
+		lo = math.Min(lo, p.Equity)
+		hi = math.Max(hi, p.Equity)
+	}
+	span := hi - lo
+	if span == 0 {
+		span = 1
+	}
+	start, end := points[0].Time, points[len(points)-1].Time
+	duration := end.Sub(start).Seconds()
+
+	coords := make([]string, len(points))
+	for i, p := range points {
+		x := float64(i) / float64(len(points)-1) * float64(width)
+		if duration > 0 {
+			x = p.Time.Sub(start).Seconds() / duration * float64(width)
+		}
+		y := float64(height) - (p.Equity-lo)/span*float64(height)
+		coords[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+
+	color := "#2e7d32"
+	if points[len(points)-1].Equity < points[0].Equity {
+		color = "#c62828"
+	}
+	return template.HTML(fmt.Sprintf(
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d"><polyline fill="none" stroke="%s" stroke-width="1.5" points="%s"/></svg>`,
+		width, height, width, height, color, strings.Join(coords, " ")))
+}
+
+var statementTemplate = template.Must(template.New("statement").Funcs(template.FuncMap{
+	"money":  func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"price":  func(symbol string, v float64) string { return fmt.Sprintf("%g", v) },
+	"lots":   func(symbol string, v float64) string { return fmt.Sprintf("%.2f", v) },
+	"number": func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"sign": func(v float64) string {
+		if v < 0 {
+			return "neg"
+		}
+		return "pos"
+	},
+	"sparkline": Sparkline,
+}).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Statement {{.Login}} {{.Day.Format "2006-01-02"}}</title>
+<style>
+body{font-family:Arial,Helvetica,sans-serif;font-size:13px;color:#222}
+table{border-collapse:collapse;margin:8px 0 20px}
+th,td{padding:4px 10px;border-bottom:1px solid #ddd;text-align:right}
+th:first-child,td:first-child{text-align:left}
+th{background:#f3f3f3}
+.pos{color:#2e7d32}.neg{color:#c62828}
+</style></head><body>
+<h2>Daily statement {{.Day.Format "Mon 2006-01-02"}}</h2>
+<p>Account {{.Login}}{{if .Company}} · {{.Company}}{{end}} · generated {{.Generated.Format "2006-01-02 15:04 MST"}}</p>
+
+<table>
+<tr><th>Balance</th><th>Equity</th><th>Floating</th><th>Margin</th><th>Free margin</th></tr>
+<tr><td>{{money .Balance}}{{if not .Format}} {{.Currency}}{{end}}</td><td>{{money .Equity}}</td><td class="{{sign .Floating}}">{{money .Floating}}</td><td>{{money .Margin}}</td><td>{{money .FreeMargin}}</td></tr>
+</table>
+
+{{with .Summary}}<h3>Day summary</h3>
+<table>
+<tr><th>Trades</th><th>Win rate</th><th>Volume</th><th>Gross profit</th><th>Gross loss</th><th>Commission</th><th>Swap</th><th>Fees</th><th>Realized P/L</th></tr>
+<tr><td>{{.Trades}} ({{.Wins}}W / {{.Losses}}L)</td><td>{{printf "%.1f" .WinRate}}%</td><td>{{number .Volume}}</td><td>{{money .GrossProfit}}</td><td>{{money .GrossLoss}}</td><td>{{money .Commission}}</td><td>{{money .Swap}}</td><td>{{money .Fees}}</td><td class="{{sign .RealizedPnL}}"><b>{{money .RealizedPnL}}</b></td></tr>
+</table>{{end}}
+
+{{if .EquityCurve}}<h3>Equity</h3>
+<p>{{sparkline .EquityCurve 480 60}}</p>{{end}}
+
+<h3>Open positions ({{len .Positions}})</h3>
+{{if .Positions}}<table>
+<tr><th>Symbol</th><th>Ticket</th><th>Type</th><th>Lots</th><th>Open</th><th>Current</th><th>SL</th><th>TP</th><th>Swap</th><th>Profit</th></tr>
+{{range .Positions}}<tr><td>{{.Symbol}}</td><td>{{.Ticket}}</td><td>{{.Type}}</td><td>{{lots .Symbol .Volume}}</td><td>{{price .Symbol .OpenPrice}}</td><td>{{price .Symbol .CurrentPrice}}</td><td>{{price .Symbol .StopLoss}}</td><td>{{price .Symbol .TakeProfit}}</td><td>{{money .Swap}}</td><td class="{{sign .Profit}}">{{money .Profit}}</td></tr>
+{{end}}</table>{{else}}<p>None.</p>{{end}}
+
+<h3>Closed trades ({{len .Trades}})</h3>
+{{if .Trades}}<table>
+<tr><th>Symbol</th><th>Ticket</th><th>Type</th><th>Lots</th><th>Open</th><th>Close</th><th>Opened</th><th>Closed</th><th>Commission</th><th>Swap</th><th>Fee</th><th>Net</th></tr>
+{{range .Trades}}<tr><td>{{.Symbol}}</td><td>{{.Ticket}}</td><td>{{.Type}}</td><td>{{lots .Symbol .Volume}}</td><td>{{price .Symbol .OpenPrice}}</td><td>{{price .Symbol .ClosePrice}}</td><td>{{.OpenTime.Format "01-02 15:04"}}</td><td>{{.CloseTime.Format "15:04:05"}}</td><td>{{money .Commission}}</td><td>{{money .Swap}}</td><td>{{money .Fee}}</td><td class="{{sign .Net}}">{{money .Net}}</td></tr>
+{{end}}</table>{{else}}<p>None.</p>{{end}}
+</body></html>
+`))
+
+// StatementSchedule configures RunDailyStatements.
+type StatementSchedule struct {
+	At          time.Duration  // Send time as offset from server midnight (e.g., 23h55m)
+	PreviousDay bool           // Report the previous server day (for At shortly after midnight)
+	Recipients  []string       // Mail recipients
+	Sender      MailSender     // Delivery (required)
+	Equity      *EquityTracker // Source of the equity sparkline (nil = none)
+	OnError     func(error)    // Called when building or sending fails (nil = ignore)
+}
+
+// RunDailyStatements sends a statement every server day at sched.At until ctx
+// is cancelled. Blocks; run it in a goroutine.
+//
+// Parameters:
+//   - ctx: Cancel to stop the schedule
+//   - sched: Send time, recipients and mail sender
+//
+// Returns:
+//   - ctx error when stopped, or error if the schedule is invalid
+func (s *MT5Sugar) RunDailyStatements(ctx context.Context, sched StatementSchedule) error {
+	if sched.Sender == nil {
+		return fmt.Errorf("statement schedule: no mail sender")
+	}
+	if len(sched.Recipients) == 0 {
+		return fmt.Errorf("statement schedule: no recipients")
+	}
+	if sched.At < 0 || sched.At >= 24*time.Hour {
+		return fmt.Errorf("statement schedule: At must be within a day, got %s", sched.At)
+	}
+
+	for {
+		now := time.Now().In(s.serverLoc)
+		midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.serverLoc)
+		next := midnight.Add(sched.At)
+		if !next.After(now) {
+			next = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, s.serverLoc).Add(sched.At)
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		day := next
+		if sched.PreviousDay {
+			day = next.AddDate(0, 0, -1)
+		}
+		if err := s.SendStatement(ctx, day, sched.Recipients, sched.Sender, sched.Equity); err != nil && sched.OnError != nil {
+			sched.OnError(err)
+		}
+	}
+}
+
+// SendStatement builds the statement of the server day containing day and mails it.
+//
+// Parameters:
+//   - ctx: Context for the mail sender
+//   - day: Any time inside the wanted server day
+//   - recipients: Mail recipients
+//   - sender: Mail delivery
+//   - equity: Source of the equity sparkline (nil = none)
+//
+// Returns:
+//   - Error if the statement could not be built, rendered or sent
+func (s *MT5Sugar) SendStatement(ctx context.Context, day time.Time, recipients []string, sender MailSender, equity *EquityTracker) error {
+	var curve []EquityPoint
+	if equity != nil {
+		curve = equity.Curve()
+	}
+	st, err := s.BuildStatement(day, curve)
+	if err != nil {
+		return err
+	}
+	html, err := st.HTML()
+	if err != nil {
+		return err
+	}
+	return sender.Send(ctx, MailMessage{To: recipients, Subject: st.Subject(), HTML: html})
+}
@@ -0,0 +1,210 @@
+package mt5
+
+/*
+Stops auto-adjustment - nudge SL/TP that miss the stops level by a little.
+
+MT5 rejects a request with 10016 (INVALID_STOPS) when SL or TP is closer to
+the price than SYMBOL_TRADE_STOPS_LEVEL points, even when a pip-based level
+misses by a fraction of a point because the spread widened. With
+SetStopsAutoAdjust, Sugar moves such a level outwards to the nearest valid
+distance before sending and reports the change as a warning instead of
+failing the trade.
+
+  • Required distance = max(SYMBOL_TRADE_STOPS_LEVEL, SYMBOL_TRADE_FREEZE_LEVEL)
+  • Market orders and ModifyPositionSL / TP / SLTP: levels below the market
+    are measured from Bid, levels above from Ask. MT5 measures both from the
+    close price (Bid for BUY, Ask for SELL); this is never closer, so the
+    result is valid whichever convention the server applies
+  • Pending orders: measured from the order price (StopLimit price for STOP_LIMIT)
+  • Only levels on the correct side and short by at most maxPoints are moved;
+    anything else is sent unchanged and fails as before
+
+Usage:
+    sugar.SetStopsAutoAdjust(20, func(a mt5.StopsAdjustment) {
+        log.Printf("warning: %s", a)   // EURUSD SL 1.08412 → 1.08400 (stops level 30 points from 1.08430)
+    })
+*/
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// StopsAdjustment is one SL or TP moved by SetStopsAutoAdjust, or a pending
+// order price moved out to the stops level by PlacePendingOffset /
+// PlacePendingAtLevel.
+type StopsAdjustment struct {
+	Time        time.Time
+	Symbol      string
+	Ticket      uint64  // Position ticket (ModifyPosition*), 0 for new orders
+	Field       string  // "SL", "TP" or "Price" (pending entry helpers)
+	Requested   float64 // Level asked for
+	Adjusted    float64 // Level sent
+	Reference   float64 // Price the distance is measured from
+	LevelPoints int64   // Required distance in points
+
+	digits int
+}
+
+func (a StopsAdjustment) String() string {
+	return fmt.Sprintf("%s %s %.*f → %.*f (stops level %d points from %.*f)",
+		a.Symbol, a.Field, a.digits, a.Requested, a.digits, a.Adjusted, a.LevelPoints, a.digits, a.Reference)
+}
+
+// SetStopsAutoAdjust moves SL/TP that are closer to the price than the
+// symbol's stops or freeze level to the nearest valid distance, on every
+// order sent through Sugar and on ModifyPositionSL/TP/SLTP. Levels short
+// by more than maxPoints are sent unchanged; maxPoints 0 disables the
+// adjustment. Each move is reported to onAdjust, or written to the
+// standard logger as a warning if onAdjust is nil.
+func (s *MT5Sugar) SetStopsAutoAdjust(maxPoints int64, onAdjust func(StopsAdjustment)) {
+	s.devMu.Lock()
+	defer s.devMu.Unlock()
+	s.stopsAdjust = maxPoints
+	s.onStopsAdjust = onAdjust
+}
+
+// adjustOrderStops applies SetStopsAutoAdjust to a new order.
+func (s *MT5Sugar) adjustOrderStops(ctx context.Context, req *pb.OrderSendRequest) {
+	if !s.stopsAdjustEnabled() || req.StopLoss == nil && req.TakeProfit == nil {
+		return
+	}
+
+	order := OrderRequest{Type: req.Operation}
+	var low, high float64
+	switch {
+	case order.IsMarket():
+		tick, err := s.service.GetSymbolTick(ctx, req.Symbol)
+		if err != nil {
+			return
+		}
+		low, high = tick.Bid, tick.Ask
+	case req.StopLimitPrice != nil && *req.StopLimitPrice > 0:
+		low, high = *req.StopLimitPrice, *req.StopLimitPrice
+	case req.Price != nil:
+		low, high = *req.Price, *req.Price
+	}
+	s.adjustStops(ctx, req.Symbol, 0, order.IsBuy(), low, high, req.StopLoss, req.TakeProfit)
+}
+
+// adjustPositionStops applies SetStopsAutoAdjust to a position modification.
+func (s *MT5Sugar) adjustPositionStops(ctx context.Context, ticket uint64, sl, tp *float64) {
+	if !s.stopsAdjustEnabled() {
+		return
+	}
+	pos, err := s.GetPositionByTicket(ticket)
+	if err != nil {
+		return
+	}
+	tick, err := s.service.GetSymbolTick(ctx, pos.Symbol)
+	if err != nil {
+		return
+	}
+	buy := pos.Type == pb.BMT5_ENUM_POSITION_TYPE_BMT5_POSITION_TYPE_BUY
+	s.adjustStops(ctx, pos.Symbol, ticket, buy, tick.Bid, tick.Ask, sl, tp)
+}
+
+func (s *MT5Sugar) stopsAdjustEnabled() bool {
+	s.devMu.Lock()
+	defer s.devMu.Unlock()
+	return s.stopsAdjust > 0
+}
+
+// adjustStops moves sl and tp (nil or 0 = not set) of a BUY (buy) or SELL
+// position outwards to the required distance below low or above high. A
+// level is left alone if it is already valid, on the wrong side of the price
+// or short by more than the configured maximum.
+func (s *MT5Sugar) adjustStops(ctx context.Context, symbol string, ticket uint64, buy bool, low, high float64, sl, tp *float64) {
+	s.devMu.Lock()
+	maxPoints := s.stopsAdjust
+	s.devMu.Unlock()
+	if maxPoints <= 0 || low <= 0 || high <= 0 {
+		return
+	}
+
+	stopsLevel, err := s.service.GetSymbolInteger(ctx, symbol, pb.SymbolInfoIntegerProperty_SYMBOL_TRADE_STOPS_LEVEL)
+	if err != nil {
+		return
+	}
+	freezeLevel, _ := s.service.GetSymbolInteger(ctx, symbol, pb.SymbolInfoIntegerProperty_SYMBOL_TRADE_FREEZE_LEVEL)
+	level := stopsLevel
+	if freezeLevel > level {
+		level = freezeLevel
+	}
+	if level <= 0 {
+		return
+	}
+	point, err := s.service.GetSymbolDouble(ctx, symbol, pb.SymbolInfoDoubleProperty_SYMBOL_POINT)
+	if err != nil || point <= 0 {
+		return
+	}
+	digits := int(math.Round(-math.Log10(point)))
+	dist := float64(level) * point
+
+	nudge := func(field string, price *float64, below bool) {
+		if price == nil || *price == 0 {
+			return
+		}
+		requested := *price
+		normalize := func(v float64) float64 {
+			v, _ = strconv.ParseFloat(strconv.FormatFloat(v, 'f', digits, 64), 64)
+			return v
+		}
+		var ref, valid, short float64
+		if below {
+			ref = low
+			valid = normalize(math.Floor((ref-dist)/point+1e-6) * point)
+			short = (requested - valid) / point
+			if requested >= ref {
+				return
+			}
+		} else {
+			ref = high
+			valid = normalize(math.Ceil((ref+dist)/point-1e-6) * point)
+			short = (valid - requested) / point
+			if requested <= ref {
+				return
+			}
+		}
+		// Already valid, or too far off to be a rounding / spread miss
+		if short < 1e-6 || short > float64(maxPoints)+1e-6 {
+			return
+		}
+		*price = valid
+
+		s.reportStopsAdjustment(StopsAdjustment{
+			Time:        time.Now(),
+			Symbol:      symbol,
+			Ticket:      ticket,
+			Field:       field,
+			Requested:   requested,
+			Adjusted:    valid,
+			Reference:   ref,
+			LevelPoints: level,
+			digits:      digits,
+		})
+	}
+
+	// BUY: SL below the price, TP above. SELL: the other way round.
+	nudge("SL", sl, buy)
+	nudge("TP", tp, !buy)
+}
+
+// reportStopsAdjustment passes a to the SetStopsAutoAdjust callback, or logs
+// it as a warning if none is set.
+func (s *MT5Sugar) reportStopsAdjustment(a StopsAdjustment) {
+	s.devMu.Lock()
+	onAdjust := s.onStopsAdjust
+	s.devMu.Unlock()
+	if onAdjust != nil {
+		onAdjust(a)
+	} else {
+		log.Printf("warning: %s", a)
+	}
+}
@@ -0,0 +1,273 @@
+package mt5
+
+/*
+Stream lag monitoring - detects streams that stay open but fall behind.
+
+A gRPC stream can stall silently: no error, the connection looks healthy,
+but messages arrive late or not at all. Two measurements catch that:
+
+  • Tick lag      - receive time minus tick server time. Server clocks run in
+                    the broker timezone, so the smallest difference seen is
+                    taken as the baseline and Lag() reports the excess over it.
+  • Heartbeat gap - for periodic streams without timestamps (position
+                    profits), time since the last message.
+
+StreamTicksWithLag / StreamPositionProfitsWithLag re-open the stream when
+the threshold is exceeded. Consumers keep reading the same channels.
+
+Usage:
+    ticks, errs, lag := service.StreamTicksWithLag(ctx, []string{"EURUSD"}, 2*time.Second)
+    go func() {
+        for range time.Tick(10 * time.Second) {
+            fmt.Printf("tick lag %v (max %v, reconnects %d)\n", lag.Lag(), lag.Stats().Max, lag.Stats().Reconnects)
+        }
+    }()
+*/
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// LagStats summarizes a LagMonitor.
+type LagStats struct {
+	Current     time.Duration // Latest lag (or gap since last message for heartbeat streams)
+	Average     time.Duration // Exponential moving average of lag
+	Max         time.Duration // Largest lag observed
+	Samples     uint64        // Messages measured
+	Reconnects  int           // Stream re-opens triggered by the threshold
+	LastReceive time.Time     // Receive time of the last message
+}
+
+// LagMonitor measures delivery lag of one stream. Safe for concurrent use.
+type LagMonitor struct {
+	mu          sync.RWMutex
+	heartbeat   bool
+	baseline    time.Duration
+	hasBaseline bool
+	current     time.Duration
+	average     time.Duration
+	max         time.Duration
+	samples     uint64
+	reconnects  int
+	lastReceive time.Time
+}
+
+// NewLagMonitor creates a monitor. heartbeat=true measures gaps between
+// messages instead of server-time lag.
+func NewLagMonitor(heartbeat bool) *LagMonitor {
+	return &LagMonitor{heartbeat: heartbeat, lastReceive: time.Now()}
+}
+
+// RecordServerTime records a message stamped with server time.
+func (m *LagMonitor) RecordServerTime(server, received time.Time) {
+	raw := received.Sub(server)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.hasBaseline || raw < m.baseline {
+		m.baseline = raw
+		m.hasBaseline = true
+	}
+	m.record(raw-m.baseline, received)
+}
+
+// RecordHeartbeat records a message without a server timestamp.
+func (m *LagMonitor) RecordHeartbeat(received time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.record(received.Sub(m.lastReceive), received)
+}
+
+// record updates statistics. Caller must hold m.mu.
+func (m *LagMonitor) record(lag time.Duration, received time.Time) {
+	m.current = lag
+	if lag > m.max {
+		m.max = lag
+	}
+	if m.samples == 0 {
+		m.average = lag
+	} else {
+		m.average += (lag - m.average) / 10
+	}
+	m.samples++
+	m.lastReceive = received
+}
+
+// Lag returns the current lag. For heartbeat streams this is the time since
+// the last message, so it grows while the stream is silent.
+func (m *LagMonitor) Lag() time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.heartbeat {
+		return time.Since(m.lastReceive)
+	}
+	return m.current
+}
+
+// Stats returns a snapshot of all lag statistics.
+func (m *LagMonitor) Stats() LagStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	current := m.current
+	if m.heartbeat {
+		current = time.Since(m.lastReceive)
+	}
+	return LagStats{
+		Current:     current,
+		Average:     m.average,
+		Max:         m.max,
+		Samples:     m.samples,
+		Reconnects:  m.reconnects,
+		LastReceive: m.lastReceive,
+	}
+}
+
+// markReconnect counts a threshold-triggered re-open and resets the heartbeat clock.
+func (m *LagMonitor) markReconnect() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reconnects++
+	m.lastReceive = time.Now()
+}
+
+// StreamTicksWithLag streams ticks like StreamTicks and re-opens the stream
+// whenever a tick arrives more than maxLag behind the baseline.
+//
+// Parameters:
+//   - ctx: Context for cancellation (closing ctx stops the stream)
+//   - symbols: Symbols to stream
+//   - maxLag: Lag that triggers a reconnect (0 = measure only)
+//
+// Returns:
+//   - Read-only channel of *SymbolTick
+//   - Read-only channel of errors (stream errors are passed through)
+//   - LagMonitor with Lag() and Stats()
+func (s *MT5Service) StreamTicksWithLag(ctx context.Context, symbols []string, maxLag time.Duration) (<-chan *SymbolTick, <-chan error, *LagMonitor) {
+	monitor := NewLagMonitor(false)
+	outCh := make(chan *SymbolTick)
+	outErrCh := make(chan error, 1)
+
+	go func() {
+		defer close(outCh)
+		defer close(outErrCh)
+
+		for {
+			sctx, cancel := context.WithCancel(ctx)
+			tickCh, errCh := s.StreamTicks(sctx, symbols)
+			reconnect := false
+
+			for !reconnect {
+				select {
+				case <-ctx.Done():
+					cancel()
+					return
+				case tick, ok := <-tickCh:
+					if !ok {
+						cancel()
+						return
+					}
+					monitor.RecordServerTime(tick.Time, time.Now())
+					select {
+					case outCh <- tick:
+					case <-ctx.Done():
+						cancel()
+						return
+					}
+					if maxLag > 0 && monitor.Lag() > maxLag {
+						reconnect = true
+					}
+				case err, ok := <-errCh:
+					if ok && err != nil && ctx.Err() == nil {
+						outErrCh <- err
+					}
+					cancel()
+					return
+				}
+			}
+
+			cancel()
+			monitor.markReconnect()
+		}
+	}()
+
+	return outCh, outErrCh, monitor
+}
+
+// StreamPositionProfitsWithLag streams position profits like
+// StreamPositionProfits and re-opens the stream when no update arrives for
+// maxGap. The profit stream is periodic, so silence means a stall.
+//
+// Parameters:
+//   - ctx: Context for cancellation (closing ctx stops the stream)
+//   - maxGap: Silence that triggers a reconnect (0 = measure only)
+//
+// Returns:
+//   - Read-only channel of *pb.OnPositionProfitData
+//   - Read-only channel of errors
+//   - LagMonitor (heartbeat mode) with Lag() and Stats()
+func (s *MT5Service) StreamPositionProfitsWithLag(ctx context.Context, maxGap time.Duration) (<-chan *pb.OnPositionProfitData, <-chan error, *LagMonitor) {
+	monitor := NewLagMonitor(true)
+	outCh := make(chan *pb.OnPositionProfitData)
+	outErrCh := make(chan error, 1)
+
+	checkEvery := time.Second
+	if maxGap > 0 && maxGap/4 < checkEvery {
+		checkEvery = maxGap / 4
+	}
+
+	go func() {
+		defer close(outCh)
+		defer close(outErrCh)
+
+		ticker := time.NewTicker(checkEvery)
+		defer ticker.Stop()
+
+		for {
+			sctx, cancel := context.WithCancel(ctx)
+			dataCh, errCh := s.StreamPositionProfits(sctx)
+			reconnect := false
+
+			for !reconnect {
+				select {
+				case <-ctx.Done():
+					cancel()
+					return
+				case data, ok := <-dataCh:
+					if !ok {
+						cancel()
+						return
+					}
+					monitor.RecordHeartbeat(time.Now())
+					select {
+					case outCh <- data:
+					case <-ctx.Done():
+						cancel()
+						return
+					}
+				case err, ok := <-errCh:
+					if ok && err != nil && ctx.Err() == nil {
+						outErrCh <- err
+					}
+					cancel()
+					return
+				case <-ticker.C:
+					if maxGap > 0 && monitor.Lag() > maxGap {
+						reconnect = true
+					}
+				}
+			}
+
+			cancel()
+			monitor.markReconnect()
+		}
+	}()
+
+	return outCh, outErrCh, monitor
+}
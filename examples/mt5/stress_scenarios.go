@@ -0,0 +1,192 @@
+package mt5
+
+/*
+StressTest - revalue the open portfolio under price shocks.
+
+Each scenario moves the current price of one or more symbols by a percentage
+(e.g., EURUSD -2%, XAUUSD +5%) and revalues every open position with the
+symbol's tick value and tick size, the same way GetNetExposure does. The
+margin of each position is recalculated by the broker (OrderCalcMargin) at
+the shocked price, so the projected margin level reflects leverage changes.
+
+Only the shocked symbols move: tick values are taken as they are now, so a
+USDJPY shock does not revalue the JPY conversion of other crosses. Margin is
+recalculated per position, so hedged margin reductions are not modelled.
+
+Usage:
+    results, err := sugar.StressTest(
+        mt5.StressScenario{Name: "EUR selloff", Shocks: []mt5.PriceShock{{Symbol: "EURUSD", Percent: -2}}},
+        mt5.StressScenario{Name: "Gold spike", Shocks: []mt5.PriceShock{{Symbol: "XAUUSD", Percent: 5}}},
+        mt5.StressScenario{Name: "Everything -3%", Shocks: []mt5.PriceShock{{Symbol: "*", Percent: -3}}},
+    )
+    for _, r := range results {
+        fmt.Printf("%-16s P/L %+.2f  equity %.2f  margin level %.1f%%\n",
+            r.Scenario, r.PnLChange, r.Equity, r.MarginLevel)
+    }
+*/
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// PriceShock moves the price of a symbol by a percentage.
+type PriceShock struct {
+	Symbol  string  // Symbol to shock, or "*" for every symbol without its own shock
+	Percent float64 // Price change in percent (e.g., -2 = price falls 2%)
+}
+
+// StressScenario is a named set of simultaneous price shocks.
+type StressScenario struct {
+	Name   string
+	Shocks []PriceShock
+}
+
+// shockFor returns the percent move applied to symbol.
+func (sc StressScenario) shockFor(symbol string) (float64, bool) {
+	wildcard, hasWildcard := 0.0, false
+	for _, shock := range sc.Shocks {
+		switch shock.Symbol {
+		case symbol:
+			return shock.Percent, true
+		case "*":
+			wildcard, hasWildcard = shock.Percent, true
+		}
+	}
+	return wildcard, hasWildcard
+}
+
+// StressResult is the projected account state under one scenario.
+type StressResult struct {
+	Scenario    string
+	PnLChange   float64            // Change of floating P/L caused by the shocks
+	Equity      float64            // Projected equity
+	Margin      float64            // Projected used margin
+	FreeMargin  float64            // Projected free margin
+	MarginLevel float64            // Projected margin level % (0 = no margin used)
+	StopOut     bool               // Margin level at or below the broker stop-out level
+	BySymbol    map[string]float64 // P/L change per shocked symbol
+}
+
+// StressTest revalues the current open positions under each scenario.
+// Uses 30-second timeout.
+//
+// Parameters:
+//   - scenarios: Price shocks to apply; symbols without a shock keep their price
+//
+// Returns:
+//   - One result per scenario in the given order, or error if account data,
+//     symbol specs or margin could not be read
+func (s *MT5Sugar) StressTest(scenarios ...StressScenario) ([]StressResult, error) {
+	if len(scenarios) == 0 {
+		return nil, errors.New("StressTest failed: no scenarios")
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
+	defer cancel()
+
+	positions, err := s.GetOpenPositions()
+	if err != nil {
+		return nil, fmt.Errorf("StressTest failed: %w", err)
+	}
+	equity, err := s.service.GetAccountDouble(ctx, pb.AccountInfoDoublePropertyType_ACCOUNT_EQUITY)
+	if err != nil {
+		return nil, fmt.Errorf("StressTest failed: %w", err)
+	}
+	margin, err := s.service.GetAccountDouble(ctx, pb.AccountInfoDoublePropertyType_ACCOUNT_MARGIN)
+	if err != nil {
+		return nil, fmt.Errorf("StressTest failed: %w", err)
+	}
+	// Stop-out level is only comparable when the broker sets it in percent
+	// (ACCOUNT_STOPOUT_MODE_PERCENT = 0, MONEY = 1)
+	stopOut := 0.0
+	mode, err := s.service.GetAccountInteger(ctx, pb.AccountInfoIntegerPropertyType_ACCOUNT_MARGIN_SO_MODE)
+	if err == nil && mode == 0 {
+		stopOut, _ = s.service.GetAccountDouble(ctx, pb.AccountInfoDoublePropertyType_ACCOUNT_MARGIN_SO_SO)
+	}
+
+	specs := make(map[string]*SymbolParams)
+	spec := func(symbol string) (*SymbolParams, error) {
+		if p, ok := specs[symbol]; ok {
+			return p, nil
+		}
+		name := symbol
+		params, _, err := s.service.GetSymbolParamsMany(ctx, &name, nil, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(params) == 0 {
+			return nil, fmt.Errorf("symbol %s not found", symbol)
+		}
+		specs[symbol] = &params[0]
+		return specs[symbol], nil
+	}
+	positionMargin := func(pos *pb.PositionInfo, price float64) (float64, error) {
+		orderType := pb.ENUM_ORDER_TYPE_TF_ORDER_TYPE_TF_BUY
+		if pos.Type == pb.BMT5_ENUM_POSITION_TYPE_BMT5_POSITION_TYPE_SELL {
+			orderType = pb.ENUM_ORDER_TYPE_TF_ORDER_TYPE_TF_SELL
+		}
+		return s.service.CalculateMargin(ctx, &pb.OrderCalcMarginRequest{
+			Symbol:    pos.Symbol,
+			OrderType: orderType,
+			Volume:    pos.Volume,
+			OpenPrice: price,
+		})
+	}
+
+	results := make([]StressResult, 0, len(scenarios))
+	for _, scenario := range scenarios {
+		result := StressResult{
+			Scenario: scenario.Name,
+			BySymbol: make(map[string]float64),
+		}
+		projectedMargin := margin
+
+		for _, pos := range positions {
+			percent, ok := scenario.shockFor(pos.Symbol)
+			if !ok || percent == 0 {
+				continue
+			}
+			p, err := spec(pos.Symbol)
+			if err != nil {
+				return nil, fmt.Errorf("StressTest failed: %w", err)
+			}
+			if p.TradeTickSize <= 0 {
+				continue
+			}
+
+			shocked := pos.PriceCurrent * (1 + percent/100)
+			change := pos.Volume * (shocked - pos.PriceCurrent) / p.TradeTickSize * p.TradeTickValue
+			if pos.Type == pb.BMT5_ENUM_POSITION_TYPE_BMT5_POSITION_TYPE_SELL {
+				change = -change
+			}
+			result.PnLChange += change
+			result.BySymbol[pos.Symbol] += change
+
+			before, err := positionMargin(pos, pos.PriceCurrent)
+			if err != nil {
+				return nil, fmt.Errorf("StressTest failed: %w", err)
+			}
+			after, err := positionMargin(pos, shocked)
+			if err != nil {
+				return nil, fmt.Errorf("StressTest failed: %w", err)
+			}
+			projectedMargin += after - before
+		}
+
+		result.Equity = equity + result.PnLChange
+		result.Margin = projectedMargin
+		result.FreeMargin = result.Equity - result.Margin
+		if result.Margin > 0 {
+			result.MarginLevel = result.Equity / result.Margin * 100
+			result.StopOut = stopOut > 0 && result.MarginLevel <= stopOut
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
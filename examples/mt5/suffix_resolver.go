@@ -0,0 +1,162 @@
+package mt5
+
+/*
+SuffixResolver - maps canonical symbol names to the broker's naming convention.
+
+Brokers decorate symbol names per account type: "EURUSD.", "EURUSD.pro",
+"EURUSDm", "m.EURUSD". Code written for "EURUSD" then fails with "symbol
+not found" on those servers. SuffixResolver looks at the broker's symbol
+list once, learns the prefix/suffix from a few well-known probe symbols,
+and maps canonical names from then on:
+
+    "EURUSD"     → "EURUSD.pro"
+    "EURUSD.pro" → "EURUSD.pro"   (broker names pass through)
+    "US30"       → "US30"         (unknown names pass through)
+
+Symbols that do not follow the common pattern can be mapped explicitly.
+
+Usage:
+    resolver := mt5.NewSuffixResolver()
+    names, _ := sugar.GetAllSymbols()
+    if err := resolver.Discover(names); err == nil {
+        fmt.Println(resolver.Resolve("GBPUSD"))   // GBPUSD.pro
+    }
+    resolver.Map("GOLD", "XAUUSD.pro")
+*/
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DefaultSuffixProbes are symbols nearly every broker lists.
+var DefaultSuffixProbes = []string{"EURUSD", "GBPUSD", "USDJPY", "AUDUSD", "XAUUSD"}
+
+// SuffixResolver maps canonical names to broker symbol names. Safe for concurrent use.
+type SuffixResolver struct {
+	mu      sync.RWMutex
+	prefix  string
+	suffix  string
+	names   map[string]bool   // Broker symbol names
+	mapping map[string]string // Canonical → broker (explicit and cached)
+}
+
+// NewSuffixResolver creates an empty resolver (names pass through unchanged).
+func NewSuffixResolver() *SuffixResolver {
+	return &SuffixResolver{
+		names:   make(map[string]bool),
+		mapping: make(map[string]string),
+	}
+}
+
+// Discover learns the naming convention from the broker's symbol list.
+// Each probe found with decoration votes for its prefix/suffix; the most
+// common wins, so a single odd symbol does not decide.
+//
+// Parameters:
+//   - names: All broker symbol names (e.g., from GetAllSymbols)
+//   - probes: Canonical symbols to look for (default DefaultSuffixProbes)
+//
+// Returns:
+//   - Error if none of the probes exists in any form
+func (r *SuffixResolver) Discover(names []string, probes ...string) error {
+	if len(probes) == 0 {
+		probes = DefaultSuffixProbes
+	}
+
+	type convention struct{ prefix, suffix string }
+	votes := make(map[convention]int)
+	for _, probe := range probes {
+		for _, name := range names {
+			idx := strings.Index(name, probe)
+			if idx < 0 {
+				continue
+			}
+			votes[convention{name[:idx], name[idx+len(probe):]}]++
+		}
+	}
+	if len(votes) == 0 {
+		return fmt.Errorf("symbol naming not detected: none of %v found", probes)
+	}
+
+	var best convention
+	bestVotes := 0
+	for c, n := range votes {
+		better := n > bestVotes
+		if n == bestVotes {
+			// Prefer the least decorated convention on a tie
+			better = len(c.prefix)+len(c.suffix) < len(best.prefix)+len(best.suffix)
+		}
+		if better {
+			best, bestVotes = c, n
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prefix, r.suffix = best.prefix, best.suffix
+	r.names = make(map[string]bool, len(names))
+	for _, name := range names {
+		r.names[name] = true
+	}
+	return nil
+}
+
+// Map sets an explicit canonical → broker mapping (e.g., "GOLD" → "XAUUSD.pro").
+func (r *SuffixResolver) Map(canonical, broker string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mapping[canonical] = broker
+}
+
+// Resolve returns the broker name for a canonical name. Broker names and
+// names that cannot be mapped are returned unchanged.
+func (r *SuffixResolver) Resolve(name string) string {
+	r.mu.RLock()
+	if mapped, ok := r.mapping[name]; ok {
+		r.mu.RUnlock()
+		return mapped
+	}
+	if r.names[name] || (r.prefix == "" && r.suffix == "") {
+		r.mu.RUnlock()
+		return name
+	}
+	candidate := r.prefix + name + r.suffix
+	known := r.names[candidate]
+	r.mu.RUnlock()
+
+	if !known {
+		return name
+	}
+
+	r.mu.Lock()
+	r.mapping[name] = candidate
+	r.mu.Unlock()
+	return candidate
+}
+
+// Canonical strips the detected prefix/suffix from a broker name.
+func (r *SuffixResolver) Canonical(broker string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for canonical, mapped := range r.mapping {
+		if mapped == broker {
+			return canonical
+		}
+	}
+	if strings.HasPrefix(broker, r.prefix) && strings.HasSuffix(broker, r.suffix) &&
+		len(broker) > len(r.prefix)+len(r.suffix) {
+		return broker[len(r.prefix) : len(broker)-len(r.suffix)]
+	}
+	return broker
+}
+
+// Convention returns the detected prefix and suffix.
+func (r *SuffixResolver) Convention() (prefix, suffix string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.prefix, r.suffix
+}
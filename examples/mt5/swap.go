@@ -0,0 +1,213 @@
+package mt5
+
+/*
+Swap-free (Islamic) accounts - no overnight financing.
+
+Carry strategies, swap estimates and rollover guards assume that positions
+held over the server's midnight are charged or paid swap. On a swap-free
+account that assumption is wrong: there is no carry to earn and no swap to
+avoid. MT5 has no account flag for it; brokers configure the account group
+with swaps disabled (SYMBOL_SWAP_MODE_DISABLED) or zero swap rates.
+
+  • IsSwapFree      - true when every tradable symbol has no swap
+                      (detected once per session, or forced with SetSwapFree)
+  • SwapPerNight    - estimated swap of a position for one rollover, in
+                      account currency (0 on swap-free accounts)
+  • SetRolloverGuard - block new orders shortly before server midnight so a
+                      fresh position does not pay a full night of swap
+                      (no-op on swap-free accounts)
+
+Usage:
+    free, err := sugar.IsSwapFree()
+    if !free {
+        swap, err := sugar.SwapPerNight("EURUSD", true, 1.0) // long 1 lot
+        fmt.Printf("carry per night: %.2f\n", swap)
+    }
+
+    sugar.SetServerTimezone(serverLoc)
+    sugar.SetRolloverGuard(15 * time.Minute) // no entries 23:45-00:00 server time
+*/
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// ErrRolloverBlackout is returned for orders sent inside the rollover guard window.
+var ErrRolloverBlackout = errors.New("order blocked before swap rollover")
+
+// symbolHasSwap reports whether a symbol charges or pays swap.
+func symbolHasSwap(p *pb.SymbolParameters) bool {
+	if p.SwapMode == pb.BMT5_ENUM_SYMBOL_SWAP_MODE_BMT5_SYMBOL_SWAP_MODE_DISABLED {
+		return false
+	}
+	return p.SwapLong != 0 || p.SwapShort != 0
+}
+
+// detectSwapFree reports whether no tradable symbol has swap. An account
+// without tradable symbols is not considered swap-free.
+func detectSwapFree(symbols []*pb.SymbolParameters) bool {
+	tradable := 0
+	for _, p := range symbols {
+		if p.TradeMode == pb.BMT5_ENUM_SYMBOL_TRADE_MODE_BMT5_SYMBOL_TRADE_MODE_DISABLED {
+			continue
+		}
+		tradable++
+		if symbolHasSwap(p) {
+			return false
+		}
+	}
+	return tradable > 0
+}
+
+// IsSwapFree reports whether the account is swap-free: every tradable symbol
+// has swaps disabled or zero swap rates. The result is detected once per
+// session (reading all symbol parameters) unless forced with SetSwapFree.
+//
+// Returns:
+//   - true for a swap-free account
+//   - Error if symbol parameters could not be read
+func (s *MT5Sugar) IsSwapFree() (bool, error) {
+	s.swapMu.Lock()
+	defer s.swapMu.Unlock()
+	if s.swapForced != nil {
+		return *s.swapForced, nil
+	}
+	if s.swapFree != nil {
+		return *s.swapFree, nil
+	}
+
+	symbols, err := s.loadSymbolParameters(s.ctx)
+	if err != nil {
+		return false, fmt.Errorf("IsSwapFree failed: %w", err)
+	}
+	free := detectSwapFree(symbols)
+	s.swapFree = &free
+	return free, nil
+}
+
+// SetSwapFree overrides swap-free detection, e.g. for a broker that keeps
+// nominal swap rates on Islamic accounts and charges an admin fee instead.
+//
+// Parameters:
+//   - free: Value IsSwapFree reports from now on
+func (s *MT5Sugar) SetSwapFree(free bool) {
+	s.swapMu.Lock()
+	defer s.swapMu.Unlock()
+	s.swapForced = &free
+}
+
+// resetSwapFree drops the detected swap-free state (new session).
+func (s *MT5Sugar) resetSwapFree() {
+	s.swapMu.Lock()
+	s.swapFree = nil
+	s.swapMu.Unlock()
+}
+
+// SwapPerNight estimates the swap of a position for one rollover, in account
+// currency (negative = charged). Triple-swap days charge three times this.
+// Modes charged in base or margin currency are not supported. Uses 5-second
+// timeout.
+//
+// Parameters:
+//   - symbol: Trading symbol (e.g., "EURUSD")
+//   - isBuy: true for a long position, false for a short one
+//   - volume: Position volume in lots
+//
+// Returns:
+//   - Swap per night (0 on swap-free accounts and symbols without swap)
+//   - Error if the symbol could not be read or its swap mode is not supported
+func (s *MT5Sugar) SwapPerNight(symbol string, isBuy bool, volume float64) (float64, error) {
+	free, err := s.IsSwapFree()
+	if err != nil {
+		return 0, err
+	}
+	if free {
+		return 0, nil
+	}
+
+	symbol = s.ResolveSymbol(symbol)
+	ctx, cancel := context.WithTimeout(s.ctx, 5*time.Second)
+	defer cancel()
+
+	data, err := s.service.account.SymbolParamsMany(ctx, &pb.SymbolParamsManyRequest{SymbolName: &symbol})
+	if err != nil {
+		return 0, fmt.Errorf("SwapPerNight failed: %w", err)
+	}
+	if len(data.SymbolInfos) == 0 {
+		return 0, fmt.Errorf("symbol %s not found", symbol)
+	}
+	return swapPerNight(data.SymbolInfos[0], isBuy, volume)
+}
+
+// swapPerNight converts the swap rate of a symbol into account currency.
+func swapPerNight(p *pb.SymbolParameters, isBuy bool, volume float64) (float64, error) {
+	if !symbolHasSwap(p) {
+		return 0, nil
+	}
+	rate, price := p.SwapShort, p.Bid
+	if isBuy {
+		rate, price = p.SwapLong, p.Ask
+	}
+	if p.TradeTickSize <= 0 || p.TradeContractSize <= 0 {
+		return 0, fmt.Errorf("tick size unknown for %s", p.Name)
+	}
+	// Account-currency value of 1 unit of profit currency per lot
+	profitToAccount := p.TradeTickValue / (p.TradeTickSize * p.TradeContractSize)
+
+	switch p.SwapMode {
+	case pb.BMT5_ENUM_SYMBOL_SWAP_MODE_BMT5_SYMBOL_SWAP_MODE_POINTS,
+		pb.BMT5_ENUM_SYMBOL_SWAP_MODE_BMT5_SYMBOL_SWAP_MODE_REOPEN_CURRENT,
+		pb.BMT5_ENUM_SYMBOL_SWAP_MODE_BMT5_SYMBOL_SWAP_MODE_REOPEN_BID:
+		return rate * p.Point / p.TradeTickSize * p.TradeTickValue * volume, nil
+	case pb.BMT5_ENUM_SYMBOL_SWAP_MODE_BMT5_SYMBOL_SWAP_MODE_CURRENCY_DEPOSIT:
+		return rate * volume, nil
+	case pb.BMT5_ENUM_SYMBOL_SWAP_MODE_BMT5_SYMBOL_SWAP_MODE_CURRENCY_PROFIT:
+		return rate * profitToAccount * volume, nil
+	case pb.BMT5_ENUM_SYMBOL_SWAP_MODE_BMT5_SYMBOL_SWAP_MODE_INTEREST_CURRENT,
+		pb.BMT5_ENUM_SYMBOL_SWAP_MODE_BMT5_SYMBOL_SWAP_MODE_INTEREST_OPEN:
+		// Annual % of the position value, 360-day bank year (open price approximated by current)
+		value := price * p.TradeContractSize * volume
+		return value * rate / 100 / 360 * profitToAccount, nil
+	}
+	return 0, fmt.Errorf("swap mode %s of %s not supported", exportEnum(p.SwapMode.String(), "BMT5_SYMBOL_SWAP_MODE_"), p.Name)
+}
+
+// SetRolloverGuard blocks every order sent through Sugar during the last
+// window before midnight in the server timezone (SetServerTimezone), when
+// swap is charged; the order fails with an error wrapping
+// ErrRolloverBlackout. The guard is a no-op on swap-free accounts.
+//
+// Parameters:
+//   - window: Time before server midnight to block (0 to disable)
+func (s *MT5Sugar) SetRolloverGuard(window time.Duration) {
+	s.swapMu.Lock()
+	defer s.swapMu.Unlock()
+	s.rolloverWindow = window
+}
+
+// checkRollover returns ErrRolloverBlackout inside the rollover guard window.
+// Swap-free detection errors do not block.
+func (s *MT5Sugar) checkRollover(now time.Time) error {
+	s.swapMu.Lock()
+	window := s.rolloverWindow
+	s.swapMu.Unlock()
+	if window <= 0 {
+		return nil
+	}
+
+	server := now.In(s.serverLoc)
+	midnight := time.Date(server.Year(), server.Month(), server.Day()+1, 0, 0, 0, 0, s.serverLoc)
+	left := midnight.Sub(server)
+	if left > window {
+		return nil
+	}
+	if free, err := s.IsSwapFree(); err == nil && free {
+		return nil
+	}
+	return fmt.Errorf("%w: %s until rollover", ErrRolloverBlackout, left.Round(time.Second))
+}
@@ -0,0 +1,204 @@
+package mt5
+
+/*
+Symbol catalog - every symbol's trading specification in one file.
+
+ExportSymbolCatalog reads SymbolParamsMany page by page plus the weekly
+trade sessions of each symbol and writes one row per symbol, using the same
+writers as the history exports (CSV, JSON, JSONL, Parquet). Use it to
+compare brokers, review swaps and margins offline, or generate per-symbol
+configuration (lot limits, stops levels, session filters).
+
+Columns:
+  name, description, path, sector, industry, exchange, currency_base,
+  currency_profit, currency_margin, digits, point, spread, spread_float,
+  contract_size, tick_size, tick_value, volume_min, volume_max, volume_step,
+  volume_limit, margin_initial, margin_maintenance, margin_hedged,
+  calc_mode, trade_mode, execution_mode, filling, stops_level, freeze_level,
+  swap_mode, swap_long, swap_short, swap_3days, sessions
+
+sessions lists the trade sessions in server time, e.g.
+"Mon 00:05-24:00; Tue 00:00-24:00; ...". Reading them costs about seven
+calls per symbol (run on the Sugar worker pool); brokers with thousands of
+symbols take a while, and a full SymbolParamsMany page may need a larger
+MaxRecvMsgSize (see NewMT5SugarWithOptions).
+
+Usage:
+    n, err := sugar.ExportSymbolCatalog("symbols.csv", mt5.ExportCSV)
+
+    format, _ := mt5.ExportFormatFromPath(path)
+    n, err = sugar.ExportSymbolCatalog(path, format)
+*/
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// symbolCatalogPageSize is the SymbolParamsMany page size of the catalog.
+const symbolCatalogPageSize = 200
+
+// loadSymbolParameters reads the full parameters of all symbols, page by page.
+func (s *MT5Sugar) loadSymbolParameters(ctx context.Context) ([]*pb.SymbolParameters, error) {
+	var symbols []*pb.SymbolParameters
+	perPage := int32(symbolCatalogPageSize)
+
+	for page := int32(1); ; page++ {
+		pageCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		data, err := s.service.account.SymbolParamsMany(pageCtx, &pb.SymbolParamsManyRequest{
+			PageNumber:   &page,
+			ItemsPerPage: &perPage,
+		})
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		symbols = append(symbols, data.SymbolInfos...)
+		if len(data.SymbolInfos) < symbolCatalogPageSize || len(symbols) >= int(data.SymbolsTotal) {
+			break
+		}
+	}
+	return symbols, nil
+}
+
+// formatSessions renders weekly trade sessions ("Mon 00:05-24:00; Tue ...").
+func formatSessions(week [7][]sessionRange) string {
+	clock := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+	var days []string
+	for day := time.Monday; ; day = (day + 1) % 7 {
+		if len(week[day]) > 0 {
+			ranges := make([]string, len(week[day]))
+			for i, session := range week[day] {
+				ranges[i] = clock(session.from) + "-" + clock(session.to)
+			}
+			days = append(days, day.String()[:3]+" "+strings.Join(ranges, ","))
+		}
+		if day == time.Sunday {
+			break
+		}
+	}
+	return strings.Join(days, "; ")
+}
+
+// symbolCatalogTable builds the catalog schema.
+func symbolCatalogTable(symbols []*pb.SymbolParameters, sessions map[string]string) *exportTable {
+	p := func(row int) *pb.SymbolParameters { return symbols[row] }
+	return &exportTable{Rows: len(symbols), Columns: []exportColumn{
+		{"name", exportString, func(r int) any { return p(r).Name }},
+		{"description", exportString, func(r int) any { return p(r).SymDescription }},
+		{"path", exportString, func(r int) any { return p(r).Path }},
+		{"sector", exportString, func(r int) any {
+			if p(r).SectorName != "" {
+				return p(r).SectorName
+			}
+			return exportEnum(p(r).Sector.String(), "BMT5_SECTOR_")
+		}},
+		{"industry", exportString, func(r int) any {
+			if p(r).IndustryName != "" {
+				return p(r).IndustryName
+			}
+			return exportEnum(p(r).Industry.String(), "BMT5_INDUSTRY_")
+		}},
+		{"exchange", exportString, func(r int) any { return p(r).Exchange }},
+		{"currency_base", exportString, func(r int) any { return p(r).CurrencyBase }},
+		{"currency_profit", exportString, func(r int) any { return p(r).CurrencyProfit }},
+		{"currency_margin", exportString, func(r int) any { return p(r).CurrencyMargin }},
+		{"digits", exportInt, func(r int) any { return int64(p(r).Digits) }},
+		{"point", exportFloat, func(r int) any { return p(r).Point }},
+		{"spread", exportInt, func(r int) any { return int64(p(r).Spread) }},
+		{"spread_float", exportString, func(r int) any { return fmt.Sprint(p(r).SpreadFloat) }},
+		{"contract_size", exportFloat, func(r int) any { return p(r).TradeContractSize }},
+		{"tick_size", exportFloat, func(r int) any { return p(r).TradeTickSize }},
+		{"tick_value", exportFloat, func(r int) any { return p(r).TradeTickValue }},
+		{"volume_min", exportFloat, func(r int) any { return p(r).VolumeMin }},
+		{"volume_max", exportFloat, func(r int) any { return p(r).VolumeMax }},
+		{"volume_step", exportFloat, func(r int) any { return p(r).VolumeStep }},
+		{"volume_limit", exportFloat, func(r int) any { return p(r).VolumeLimit }},
+		{"margin_initial", exportFloat, func(r int) any { return p(r).MarginInitial }},
+		{"margin_maintenance", exportFloat, func(r int) any { return p(r).MarginMaintenance }},
+		{"margin_hedged", exportFloat, func(r int) any { return p(r).MarginHedged }},
+		{"calc_mode", exportString, func(r int) any { return exportEnum(p(r).TradeCalcMode.String(), "BMT5_SYMBOL_CALC_MODE_") }},
+		{"trade_mode", exportString, func(r int) any { return exportEnum(p(r).TradeMode.String(), "BMT5_SYMBOL_TRADE_MODE_") }},
+		{"execution_mode", exportString, func(r int) any {
+			return exportEnum(p(r).TradeExeMode.String(), "BMT5_SYMBOL_TRADE_EXECUTION_")
+		}},
+		{"filling", exportString, func(r int) any {
+			modes := make([]string, len(p(r).FillingMode))
+			for i, mode := range p(r).FillingMode {
+				modes[i] = exportEnum(mode.String(), "BMT5_ORDER_FILLING_")
+			}
+			return strings.Join(modes, ",")
+		}},
+		{"stops_level", exportInt, func(r int) any { return int64(p(r).TradeStopsLevel) }},
+		{"freeze_level", exportInt, func(r int) any { return int64(p(r).TradeFreezeLevel) }},
+		{"swap_mode", exportString, func(r int) any { return exportEnum(p(r).SwapMode.String(), "BMT5_SYMBOL_SWAP_MODE_") }},
+		{"swap_long", exportFloat, func(r int) any { return p(r).SwapLong }},
+		{"swap_short", exportFloat, func(r int) any { return p(r).SwapShort }},
+		{"swap_3days", exportString, func(r int) any { return exportEnum(p(r).SwapRollover_3Days.String(), "BMT5_") }},
+		{"sessions", exportString, func(r int) any { return sessions[p(r).Name] }},
+	}}
+}
+
+// ExportSymbolCatalog writes the specification of every symbol of the
+// server (digits, contract size, margins, swaps, sessions, sector) to a file.
+//
+// Parameters:
+//   - path: Destination file (created or truncated)
+//   - format: ExportCSV, ExportJSON, ExportJSONL or ExportParquet
+//     (ExportFormatFromPath picks it from the extension)
+//
+// Returns:
+//   - Number of symbols written
+//   - Error if symbol parameters could not be read or the file written;
+//     symbols whose sessions cannot be read are written with empty sessions
+func (s *MT5Sugar) ExportSymbolCatalog(path string, format ExportFormat) (int, error) {
+	symbols, err := s.loadSymbolParameters(s.ctx)
+	if err != nil {
+		return 0, fmt.Errorf("ExportSymbolCatalog failed: %w", err)
+	}
+
+	names := make([]string, len(symbols))
+	for i, p := range symbols {
+		names[i] = p.Name
+	}
+	var mu sync.Mutex
+	sessions := make(map[string]string, len(symbols))
+	s.pool.ForEachSymbol(s.ctx, names, func(ctx context.Context, symbol string) error {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		var week [7][]sessionRange
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			ranges, err := s.tradeSessions(ctx, symbol, day)
+			if err != nil {
+				return err
+			}
+			week[day] = ranges
+		}
+		mu.Lock()
+		sessions[symbol] = formatSessions(week)
+		mu.Unlock()
+		return nil
+	})
+
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("ExportSymbolCatalog failed: %w", err)
+	}
+	if err := symbolCatalogTable(symbols, sessions).write(f, format); err != nil {
+		f.Close()
+		return 0, fmt.Errorf("ExportSymbolCatalog failed: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return 0, fmt.Errorf("ExportSymbolCatalog failed: %w", err)
+	}
+	return len(symbols), nil
+}
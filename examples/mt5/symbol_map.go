@@ -0,0 +1,151 @@
+package mt5
+
+/*
+Symbol map - per-broker symbol names from a config file.
+
+Suffix detection (DiscoverSymbolNames) covers "EURUSD.pro" and "EURUSDm",
+but not brokers that rename instruments: gold is "GOLD" on one server,
+"XAUUSD.m" on another, the Dow is "US30", "DJ30" or "WS30". A SymbolMap
+keeps those names in one JSON file so the same strategy config (written in
+canonical names) runs on every broker:
+
+    {
+      "default": { "US30": "DJ30" },
+      "brokers": {
+        "FxPro-MT5*":      { "XAUUSD": "GOLD", "XAGUSD": "SILVER" },
+        "ICMarkets-Live*": { "XAUUSD": "XAUUSD.m" }
+      }
+    }
+
+Broker keys are server/cluster names and may use path.Match wildcards; every
+matching broker section is applied on top of "default", exact names last.
+Applying a map adds explicit mappings to a SuffixResolver, which Sugar uses
+for every call and Service uses once SetSymbolResolver was called (Sugar
+shares its resolver with its Service).
+
+Usage:
+    symbolMap, err := mt5.LoadSymbolMap("symbols.json")
+    sugar.ApplySymbolMap(symbolMap, "FxPro-MT5 Demo")
+    sugar.BuyMarket("XAUUSD", 0.1) // sends GOLD
+
+    // Service without Sugar
+    resolver := mt5.NewSuffixResolver()
+    symbolMap.Apply(resolver, "FxPro-MT5 Demo")
+    service.SetSymbolResolver(resolver)
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+)
+
+// SymbolMap holds canonical → broker symbol names per broker.
+type SymbolMap struct {
+	Default map[string]string            `json:"default"` // Mappings for every broker
+	Brokers map[string]map[string]string `json:"brokers"` // Server name (or pattern) → mappings
+}
+
+// LoadSymbolMap reads a symbol map from a JSON file.
+//
+// Parameters:
+//   - file: JSON file with "default" and/or "brokers" sections
+//
+// Returns:
+//   - Parsed SymbolMap, or error if the file cannot be read or parsed, or
+//     a broker pattern is malformed
+func LoadSymbolMap(file string) (*SymbolMap, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("symbol map: %w", err)
+	}
+	var m SymbolMap
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("symbol map: %s: %w", file, err)
+	}
+	for pattern := range m.Brokers {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("symbol map: broker pattern %q: %w", pattern, err)
+		}
+	}
+	return &m, nil
+}
+
+// For returns the mappings for a server: "default", then every matching
+// pattern (in name order), then the exact server name.
+func (m *SymbolMap) For(server string) map[string]string {
+	result := make(map[string]string, len(m.Default))
+	for canonical, broker := range m.Default {
+		result[canonical] = broker
+	}
+
+	patterns := make([]string, 0, len(m.Brokers))
+	for pattern := range m.Brokers {
+		if pattern != server {
+			patterns = append(patterns, pattern)
+		}
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, server); ok {
+			for canonical, broker := range m.Brokers[pattern] {
+				result[canonical] = broker
+			}
+		}
+	}
+	for canonical, broker := range m.Brokers[server] {
+		result[canonical] = broker
+	}
+	return result
+}
+
+// Apply adds the mappings for a server to a resolver.
+//
+// Returns:
+//   - Number of mappings applied
+func (m *SymbolMap) Apply(r *SuffixResolver, server string) int {
+	mappings := m.For(server)
+	for canonical, broker := range mappings {
+		r.Map(canonical, broker)
+	}
+	return len(mappings)
+}
+
+// SetSymbolResolver makes every Service call taking a symbol name (symbol
+// properties, ticks, sessions, market depth, order send/check/calc) translate
+// canonical names through r first. Broker names pass through unchanged.
+//
+// Parameters:
+//   - r: Resolver to use (nil = names are used as given)
+func (s *MT5Service) SetSymbolResolver(r *SuffixResolver) {
+	s.symbols = r
+}
+
+// resolveSymbol maps a canonical name to the broker name.
+func (s *MT5Service) resolveSymbol(symbol string) string {
+	if s.symbols == nil {
+		return symbol
+	}
+	return s.symbols.Resolve(symbol)
+}
+
+// ApplySymbolMap adds the mappings of a symbol map for a server to the
+// Sugar resolver, so canonical names in every Sugar and Service call are
+// sent as that broker's names. Mappings applied earlier stay unless the
+// new map overrides them.
+//
+// Parameters:
+//   - m: Symbol map (see LoadSymbolMap)
+//   - server: Server or cluster name (e.g., "FxPro-MT5 Demo")
+//
+// Returns:
+//   - Number of mappings applied
+func (s *MT5Sugar) ApplySymbolMap(m *SymbolMap, server string) int {
+	if s.symbols == nil {
+		s.symbols = NewSuffixResolver()
+		s.service.SetSymbolResolver(s.symbols)
+	}
+	return m.Apply(s.symbols, server)
+}
@@ -0,0 +1,188 @@
+package mt5
+
+/*
+TagManager - reversible strategy tags in order comments and magic numbers.
+
+History analytics need to know which strategy, signal and code version
+produced each deal. TagManager writes that into the two fields MT5 keeps
+with every order, position and deal:
+
+  • Comment  "grid/L5/v3"           - strategy / signal ID / version
+  • Magic    base + id*1000 + ver   - survives brokers that rewrite comments
+
+MT5 comments are limited to 31 characters and brokers append markers such
+as "[sl 1.08500]" or "[tp]" when positions close, so decoding ignores
+anything after the tag and falls back to the magic number when the comment
+is gone entirely.
+
+Usage:
+    tags := mt5.NewTagManager(770000)
+    tags.Register("grid", 1)
+    tags.Register("breakout", 2)
+
+    req := mt5.OrderRequest{Symbol: "EURUSD", Type: pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY, Volume: 0.1}
+    tags.Apply(&req, mt5.Tag{Strategy: "grid", Signal: "L5", Version: 3})
+
+    // Later, on a deal from history
+    if tag, ok := tags.Decode(deal.Comment, uint64(deal.MagicNumber)); ok {
+        pnlByStrategy[tag.Strategy] += deal.Profit
+    }
+*/
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MaxCommentLength is the MT5 order comment limit.
+const MaxCommentLength = 31
+
+// tagVersions is the number of versions encoded per strategy in the magic number.
+const tagVersions = 1000
+
+// Tag identifies the code path that produced an order.
+type Tag struct {
+	Strategy string // Registered strategy name
+	Signal   string // Signal ID (optional; comment only)
+	Version  int    // Strategy version, 0-999
+}
+
+func (t Tag) String() string {
+	return fmt.Sprintf("%s/%s/v%d", t.Strategy, t.Signal, t.Version)
+}
+
+// TagManager encodes and decodes tags. Safe for concurrent use.
+type TagManager struct {
+	magicBase uint64
+
+	mu           sync.RWMutex
+	byName       map[string]uint64
+	byID         map[uint64]string
+	foreign      foreignRule            // Policy for untagged positions
+	foreignMagic map[uint64]foreignRule // Per-magic overrides of foreign
+}
+
+// NewTagManager creates a manager. magicBase separates this bot's magic
+// numbers from other EAs on the account (0 = none).
+func NewTagManager(magicBase uint64) *TagManager {
+	return &TagManager{
+		magicBase: magicBase,
+		byName:    make(map[string]uint64),
+		byID:      make(map[uint64]string),
+	}
+}
+
+// Register assigns a numeric ID (>= 1) to a strategy name.
+// IDs must stay stable across releases, or old history decodes wrongly.
+func (m *TagManager) Register(strategy string, id uint64) error {
+	if strategy == "" || strings.ContainsAny(strategy, "/ ") {
+		return fmt.Errorf("invalid strategy name %q", strategy)
+	}
+	if id == 0 {
+		return fmt.Errorf("strategy id must be >= 1")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if existing, ok := m.byID[id]; ok && existing != strategy {
+		return fmt.Errorf("strategy id %d already used by %s", id, existing)
+	}
+	if existing, ok := m.byName[strategy]; ok && existing != id {
+		return fmt.Errorf("strategy %s already registered with id %d", strategy, existing)
+	}
+	m.byName[strategy] = id
+	m.byID[id] = strategy
+	return nil
+}
+
+// Encode returns the comment and magic number for a tag.
+//
+// Returns:
+//   - Comment "strategy/signal/vN"
+//   - Magic number magicBase + id*1000 + version
+//   - Error if the strategy is unknown, a field is invalid, or the comment exceeds 31 chars
+func (m *TagManager) Encode(tag Tag) (string, uint64, error) {
+	m.mu.RLock()
+	id, ok := m.byName[tag.Strategy]
+	m.mu.RUnlock()
+	if !ok {
+		return "", 0, fmt.Errorf("strategy %s not registered", tag.Strategy)
+	}
+	if tag.Version < 0 || tag.Version >= tagVersions {
+		return "", 0, fmt.Errorf("version must be 0-%d, got %d", tagVersions-1, tag.Version)
+	}
+	if strings.ContainsAny(tag.Signal, "/ []") {
+		return "", 0, fmt.Errorf("invalid signal id %q", tag.Signal)
+	}
+
+	comment := tag.String()
+	if len(comment) > MaxCommentLength {
+		return "", 0, fmt.Errorf("tag %q exceeds %d characters", comment, MaxCommentLength)
+	}
+	return comment, m.magicBase + id*tagVersions + uint64(tag.Version), nil
+}
+
+// Apply writes the tag into an order request's Comment and Magic.
+func (m *TagManager) Apply(req *OrderRequest, tag Tag) error {
+	comment, magic, err := m.Encode(tag)
+	if err != nil {
+		return err
+	}
+	req.Comment = comment
+	req.Magic = magic
+	return nil
+}
+
+// Decode recovers a tag from a comment and magic number. The comment wins
+// when it parses (it carries the signal ID); otherwise strategy and version
+// come from the magic number and Signal is empty.
+//
+// Returns:
+//   - Tag and true if either field identifies a registered strategy
+func (m *TagManager) Decode(comment string, magic uint64) (Tag, bool) {
+	if tag, ok := ParseTagComment(comment); ok {
+		m.mu.RLock()
+		_, known := m.byName[tag.Strategy]
+		m.mu.RUnlock()
+		if known {
+			return tag, true
+		}
+	}
+	return m.DecodeMagic(magic)
+}
+
+// DecodeMagic recovers strategy and version from a magic number.
+func (m *TagManager) DecodeMagic(magic uint64) (Tag, bool) {
+	if magic < m.magicBase+tagVersions {
+		return Tag{}, false
+	}
+	rel := magic - m.magicBase
+
+	m.mu.RLock()
+	strategy, ok := m.byID[rel/tagVersions]
+	m.mu.RUnlock()
+	if !ok {
+		return Tag{}, false
+	}
+	return Tag{Strategy: strategy, Version: int(rel % tagVersions)}, true
+}
+
+// ParseTagComment parses "strategy/signal/vN", ignoring anything the broker
+// appended after a space or '[' (e.g., "grid/L5/v3 [sl 1.08500]").
+func ParseTagComment(comment string) (Tag, bool) {
+	if i := strings.IndexAny(comment, " ["); i >= 0 {
+		comment = comment[:i]
+	}
+	parts := strings.Split(comment, "/")
+	if len(parts) != 3 || parts[0] == "" || !strings.HasPrefix(parts[2], "v") {
+		return Tag{}, false
+	}
+	version, err := strconv.Atoi(parts[2][1:])
+	if err != nil || version < 0 {
+		return Tag{}, false
+	}
+	return Tag{Strategy: parts[0], Signal: parts[1], Version: version}, true
+}
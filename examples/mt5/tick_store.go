@@ -0,0 +1,297 @@
+package mt5
+
+/*
+TickStore - tick history on disk for tick-level backtests and slippage models.
+
+The gRPC API has no historical tick call (no CopyTicks equivalent - only
+the live OnSymbolTick stream and SymbolInfoTick for the latest tick). Tick
+history therefore comes from two sources:
+
+  • Recording  - live ticks from StreamTicks written with Record
+  • Download   - an external source supplied as a TickFetcher, pulled in
+                 time-window pages with a minimum interval between requests
+                 (DownloadTicks), so vendor rate limits are respected
+
+File layout (one file per symbol and UTC day, appended as ticks arrive):
+    <dir>/EURUSD/2026-10-16.csv     time_ms,bid,ask,last,volume,volume_real,flags
+
+Usage:
+    store, err := mt5.NewTickStore("./ticks")
+    defer store.Close()
+
+    ticks, errs := service.StreamTicks(ctx, []string{"EURUSD"})
+    for tick := range ticks {
+        store.Record(tick)
+    }
+
+    // Later: replay a day
+    history, err := store.Load("EURUSD", from, to)
+*/
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TickFetcher downloads ticks of [from, to) for a symbol from an external source.
+type TickFetcher func(ctx context.Context, symbol string, from, to time.Time) ([]SymbolTick, error)
+
+// TickStore records and loads tick history. Safe for concurrent use.
+type TickStore struct {
+	dir string
+
+	mu    sync.Mutex
+	files map[string]*tickFile // key: file path
+}
+
+// tickFile is an open day file being appended to.
+type tickFile struct {
+	f *os.File
+	w *bufio.Writer
+}
+
+// NewTickStore creates a store in dir, creating the directory if needed.
+func NewTickStore(dir string) (*TickStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("tick store dir: %w", err)
+	}
+	return &TickStore{dir: dir, files: make(map[string]*tickFile)}, nil
+}
+
+// path returns the day file of a symbol.
+func (s *TickStore) path(symbol string, day time.Time) string {
+	name := strings.NewReplacer("/", "_", "\\", "_", ":", "_").Replace(symbol)
+	return filepath.Join(s.dir, name, day.UTC().Format("2006-01-02")+".csv")
+}
+
+// Record appends a tick to its day file. Writes are buffered; call Flush or
+// Close to make them visible to Load.
+func (s *TickStore) Record(tick *SymbolTick) error {
+	if tick == nil {
+		return nil
+	}
+	ms := tick.TimeMS
+	if ms == 0 {
+		ms = tick.Time.UnixMilli()
+	}
+	path := s.path(tick.Symbol, time.UnixMilli(ms))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tf, ok := s.files[path]
+	if !ok {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("tick store write: %w", err)
+		}
+		_, statErr := os.Stat(path)
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("tick store write: %w", err)
+		}
+		tf = &tickFile{f: f, w: bufio.NewWriter(f)}
+		if os.IsNotExist(statErr) {
+			tf.w.WriteString("time_ms,bid,ask,last,volume,volume_real,flags\n")
+		}
+		s.files[path] = tf
+	}
+
+	_, err := fmt.Fprintf(tf.w, "%d,%s,%s,%s,%d,%s,%d\n",
+		ms,
+		strconv.FormatFloat(tick.Bid, 'f', -1, 64),
+		strconv.FormatFloat(tick.Ask, 'f', -1, 64),
+		strconv.FormatFloat(tick.Last, 'f', -1, 64),
+		tick.Volume,
+		strconv.FormatFloat(tick.VolumeReal, 'f', -1, 64),
+		tick.Flags)
+	if err != nil {
+		return fmt.Errorf("tick store write: %w", err)
+	}
+	return nil
+}
+
+// Flush writes buffered ticks to disk.
+func (s *TickStore) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for path, tf := range s.files {
+		if err := tf.w.Flush(); err != nil {
+			return fmt.Errorf("tick store flush %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Close flushes and closes all open day files.
+func (s *TickStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var firstErr error
+	for path, tf := range s.files {
+		if err := tf.w.Flush(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("tick store close %s: %w", path, err)
+		}
+		if err := tf.f.Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("tick store close %s: %w", path, err)
+		}
+	}
+	s.files = make(map[string]*tickFile)
+	return firstErr
+}
+
+// Load returns stored ticks of [from, to) sorted by time. Buffered ticks are
+// flushed first.
+func (s *TickStore) Load(symbol string, from, to time.Time) ([]SymbolTick, error) {
+	if err := s.Flush(); err != nil {
+		return nil, err
+	}
+
+	fromMS, toMS := from.UnixMilli(), to.UnixMilli()
+	var ticks []SymbolTick
+	for day := from.UTC().Truncate(24 * time.Hour); day.Before(to); day = day.Add(24 * time.Hour) {
+		dayTicks, err := readTickFile(s.path(symbol, day), symbol)
+		if err != nil {
+			return nil, err
+		}
+		for _, tick := range dayTicks {
+			if tick.TimeMS >= fromMS && tick.TimeMS < toMS {
+				ticks = append(ticks, tick)
+			}
+		}
+	}
+	sort.SliceStable(ticks, func(i, j int) bool { return ticks[i].TimeMS < ticks[j].TimeMS })
+	return ticks, nil
+}
+
+// DownloadTicks pulls [from, to) from fetch in windows of page length and
+// records them in the store, waiting at least minInterval between requests.
+// Re-downloading a range appends duplicates, so remove the day files first.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - symbol: Symbol to download
+//   - from, to: Range to download
+//   - page: Window per request (default 1 hour)
+//   - minInterval: Minimum time between requests (0 = no limit)
+//   - fetch: External tick source
+//
+// Returns:
+//   - Number of ticks stored
+//   - Error from fetch, the store, or ctx
+func (s *TickStore) DownloadTicks(ctx context.Context, symbol string, from, to time.Time, page, minInterval time.Duration, fetch TickFetcher) (int, error) {
+	if page <= 0 {
+		page = time.Hour
+	}
+
+	total := 0
+	var lastRequest time.Time
+	for start := from; start.Before(to); start = start.Add(page) {
+		end := start.Add(page)
+		if end.After(to) {
+			end = to
+		}
+
+		if wait := minInterval - time.Since(lastRequest); !lastRequest.IsZero() && wait > 0 {
+			select {
+			case <-ctx.Done():
+				return total, ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+		lastRequest = time.Now()
+
+		ticks, err := fetch(ctx, symbol, start, end)
+		if err != nil {
+			return total, fmt.Errorf("tick download %s %s: %w", symbol, start.Format(time.RFC3339), err)
+		}
+		for i := range ticks {
+			if ticks[i].Symbol == "" {
+				ticks[i].Symbol = symbol
+			}
+			if err := s.Record(&ticks[i]); err != nil {
+				return total, err
+			}
+		}
+		total += len(ticks)
+	}
+	return total, s.Flush()
+}
+
+// readTickFile reads one day file. A missing file yields no ticks.
+func readTickFile(path, symbol string) ([]SymbolTick, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tick store read: %w", err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = 7
+
+	var ticks []SymbolTick
+	for line := 1; ; line++ {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("tick store read %s: %w", path, err)
+		}
+		if line == 1 && rec[0] == "time_ms" {
+			continue
+		}
+
+		tick, err := parseTickRecord(rec)
+		if err != nil {
+			return nil, fmt.Errorf("tick store %s line %d: %w", path, line, err)
+		}
+		tick.Symbol = symbol
+		ticks = append(ticks, tick)
+	}
+	return ticks, nil
+}
+
+// parseTickRecord converts one CSV record into a tick (without symbol).
+func parseTickRecord(rec []string) (SymbolTick, error) {
+	var tick SymbolTick
+	var err error
+
+	if tick.TimeMS, err = strconv.ParseInt(rec[0], 10, 64); err != nil {
+		return tick, err
+	}
+	tick.Time = time.UnixMilli(tick.TimeMS).UTC()
+
+	prices := []*float64{&tick.Bid, &tick.Ask, &tick.Last}
+	for i, p := range prices {
+		if *p, err = strconv.ParseFloat(rec[i+1], 64); err != nil {
+			return tick, err
+		}
+	}
+	if tick.Volume, err = strconv.ParseUint(rec[4], 10, 64); err != nil {
+		return tick, err
+	}
+	if tick.VolumeReal, err = strconv.ParseFloat(rec[5], 64); err != nil {
+		return tick, err
+	}
+	flags, err := strconv.ParseUint(rec[6], 10, 32)
+	if err != nil {
+		return tick, err
+	}
+	tick.Flags = uint32(flags)
+	return tick, nil
+}
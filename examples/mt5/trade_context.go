@@ -0,0 +1,287 @@
+package mt5
+
+/*
+Trade context - everything about one trade in a single JSON bundle.
+
+Disputing an execution with a broker (slippage, a stop filled through a gap,
+a rejected close) needs the records the broker's support will ask for.
+DumpTradeContext collects them for an order, deal or position ticket:
+
+  • Orders and deals of the position, from history (and open orders/position
+    if it is still open), as the server reported them
+  • Ticks around each deal from a TickStore (the API has no tick history),
+    plus the current tick
+  • Full symbol parameters (digits, stops/freeze level, filling, swaps, ...)
+  • Account state at dump time (balance, equity, margin, leverage, server)
+
+Parts that cannot be read are listed in Errors instead of failing the dump,
+so a bundle is produced even for an old or partially visible trade.
+Protobuf records are written with their proto field and enum names.
+
+Usage:
+    bundle, err := sugar.DumpTradeContext(123456789)
+    err = bundle.Save("ticket-123456789.json")
+
+    bundle, err = sugar.DumpTradeContextWithOptions(123456789, mt5.TradeContextOptions{
+        Lookback:   90 * 24 * time.Hour,
+        Ticks:      tickStore, // recorded with TickStore.Record
+        TickWindow: 30 * time.Second,
+    })
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// TradeContextOptions controls what DumpTradeContextWithOptions collects.
+type TradeContextOptions struct {
+	Lookback   time.Duration // History searched before now (default 30 days)
+	Ticks      *TickStore    // Source of ticks around deals (nil = current tick only)
+	TickWindow time.Duration // Ticks before and after each deal (default 1 minute)
+}
+
+// TradeContextAccount is the account state at dump time.
+type TradeContextAccount struct {
+	Login       int64     `json:"login"`
+	Server      string    `json:"server"`
+	Company     string    `json:"company"`
+	Currency    string    `json:"currency"`
+	Leverage    int64     `json:"leverage"`
+	Balance     float64   `json:"balance"`
+	Equity      float64   `json:"equity"`
+	Credit      float64   `json:"credit"`
+	Margin      float64   `json:"margin"`
+	FreeMargin  float64   `json:"free_margin"`
+	MarginLevel float64   `json:"margin_level"`
+	ServerTime  time.Time `json:"server_time"`
+}
+
+// TradeContextTicks holds the ticks recorded around one deal.
+type TradeContextTicks struct {
+	Deal  uint64       `json:"deal"`
+	Time  time.Time    `json:"time"`
+	Ticks []SymbolTick `json:"ticks"`
+}
+
+// TradeContext is the support bundle of one trade.
+type TradeContext struct {
+	Ticket      uint64               `json:"ticket"`
+	PositionID  uint64               `json:"position_id"`
+	Symbol      string               `json:"symbol"`
+	GeneratedAt time.Time            `json:"generated_at"`
+	HistoryFrom time.Time            `json:"history_from"`
+	Orders      []json.RawMessage    `json:"orders"`                // OrderHistoryData
+	Deals       []json.RawMessage    `json:"deals"`                 // DealHistoryData
+	OpenOrders  []json.RawMessage    `json:"open_orders"`           // OpenedOrderInfo
+	Position    json.RawMessage      `json:"position,omitempty"`    // PositionInfo, if still open
+	SymbolInfo  json.RawMessage      `json:"symbol_info,omitempty"` // SymbolParameters
+	CurrentTick *SymbolTick          `json:"current_tick,omitempty"`
+	Ticks       []TradeContextTicks  `json:"ticks,omitempty"`
+	Account     *TradeContextAccount `json:"account,omitempty"`
+	Errors      []string             `json:"errors,omitempty"` // Parts that could not be collected
+}
+
+// marshalProto renders a protobuf record with proto field and enum names.
+func marshalProto(m proto.Message) json.RawMessage {
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(m)
+	if err != nil {
+		return json.RawMessage(fmt.Sprintf("%q", err.Error()))
+	}
+	return data
+}
+
+// WriteJSON writes the bundle as indented JSON.
+func (c *TradeContext) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
+
+// Save writes the bundle to a JSON file (created or truncated).
+func (c *TradeContext) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := c.WriteJSON(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// DumpTradeContext collects the support bundle of a trade with default
+// options (30 days of history, current tick only).
+// See DumpTradeContextWithOptions.
+func (s *MT5Sugar) DumpTradeContext(ticket uint64) (*TradeContext, error) {
+	return s.DumpTradeContextWithOptions(ticket, TradeContextOptions{})
+}
+
+// DumpTradeContextWithOptions collects the orders and deals of a trade,
+// ticks around its deals, symbol parameters and account state into one
+// bundle. Uses 30-second timeout.
+//
+// Parameters:
+//   - ticket: Order, deal or position ticket
+//   - opts: History window and tick source (zero value = defaults)
+//
+// Returns:
+//   - Bundle; parts that could not be read are listed in Errors
+//   - Error if the ticket matches no order, deal or position
+func (s *MT5Sugar) DumpTradeContextWithOptions(ticket uint64, opts TradeContextOptions) (*TradeContext, error) {
+	if opts.Lookback <= 0 {
+		opts.Lookback = 30 * 24 * time.Hour
+	}
+	if opts.TickWindow <= 0 {
+		opts.TickWindow = time.Minute
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	bundle := &TradeContext{Ticket: ticket, PositionID: ticket, GeneratedAt: now, HistoryFrom: now.Add(-opts.Lookback)}
+	fail := func(part string, err error) {
+		bundle.Errors = append(bundle.Errors, fmt.Sprintf("%s: %v", part, err))
+	}
+
+	deals, orders, err := s.service.loadHistory(ctx, bundle.HistoryFrom, now.Add(time.Hour))
+	if err != nil {
+		fail("history", err)
+	}
+	opened, err := s.service.GetOpenedOrders(ctx, pb.BMT5_ENUM_OPENED_ORDER_SORT_TYPE_BMT5_OPENED_ORDER_SORT_BY_OPEN_TIME_ASC)
+	if err != nil {
+		fail("open orders", err)
+		opened = &pb.OpenedOrdersData{}
+	}
+
+	// The ticket may name an order or a deal; both lead to their position
+	for _, order := range orders {
+		if order.Ticket == ticket && order.PositionId != 0 {
+			bundle.PositionID = order.PositionId
+		}
+	}
+	for _, deal := range deals {
+		if deal.Ticket == ticket && deal.PositionId != 0 {
+			bundle.PositionID = deal.PositionId
+		}
+	}
+	for _, order := range opened.OpenedOrders {
+		if order.Ticket == ticket && order.PositionId != 0 {
+			bundle.PositionID = uint64(order.PositionId)
+		}
+	}
+	related := func(t, positionID uint64) bool {
+		return t == ticket || (positionID != 0 && positionID == bundle.PositionID)
+	}
+
+	var matchedDeals []*pb.DealHistoryData
+	for _, order := range orders {
+		if related(order.Ticket, order.PositionId) {
+			bundle.Orders = append(bundle.Orders, marshalProto(order))
+			bundle.Symbol = order.Symbol
+		}
+	}
+	for _, deal := range deals {
+		if related(deal.Ticket, deal.PositionId) {
+			bundle.Deals = append(bundle.Deals, marshalProto(deal))
+			matchedDeals = append(matchedDeals, deal)
+			bundle.Symbol = deal.Symbol
+		}
+	}
+	for _, order := range opened.OpenedOrders {
+		if related(order.Ticket, uint64(order.PositionId)) {
+			bundle.OpenOrders = append(bundle.OpenOrders, marshalProto(order))
+			bundle.Symbol = order.Symbol
+		}
+	}
+	for _, pos := range opened.PositionInfos {
+		if pos.Ticket == bundle.PositionID {
+			bundle.Position = marshalProto(pos)
+			bundle.Symbol = pos.Symbol
+		}
+	}
+	if bundle.Symbol == "" {
+		return nil, fmt.Errorf("DumpTradeContext failed: ticket %d not found in open trades or %s of history",
+			ticket, opts.Lookback)
+	}
+
+	symbol := bundle.Symbol
+	if data, err := s.service.account.SymbolParamsMany(ctx, &pb.SymbolParamsManyRequest{SymbolName: &symbol}); err != nil {
+		fail("symbol parameters", err)
+	} else if len(data.SymbolInfos) > 0 {
+		bundle.SymbolInfo = marshalProto(data.SymbolInfos[0])
+	}
+	if tick, err := s.service.GetSymbolTick(ctx, symbol); err != nil {
+		fail("current tick", err)
+	} else {
+		bundle.CurrentTick = tick
+	}
+
+	if opts.Ticks != nil {
+		sort.Slice(matchedDeals, func(i, j int) bool { return matchedDeals[i].Time.AsTime().Before(matchedDeals[j].Time.AsTime()) })
+		for _, deal := range matchedDeals {
+			at := deal.Time.AsTime()
+			ticks, err := opts.Ticks.Load(symbol, at.Add(-opts.TickWindow), at.Add(opts.TickWindow))
+			if err != nil {
+				fail(fmt.Sprintf("ticks of deal %d", deal.Ticket), err)
+				continue
+			}
+			bundle.Ticks = append(bundle.Ticks, TradeContextTicks{Deal: deal.Ticket, Time: at, Ticks: ticks})
+		}
+	}
+
+	if account, err := s.tradeContextAccount(ctx); err != nil {
+		fail("account", err)
+	} else {
+		bundle.Account = account
+	}
+
+	return bundle, nil
+}
+
+// tradeContextAccount reads the account state for a bundle.
+func (s *MT5Sugar) tradeContextAccount(ctx context.Context) (*TradeContextAccount, error) {
+	summary, err := s.service.GetAccountSummary(ctx)
+	if err != nil {
+		return nil, err
+	}
+	account := &TradeContextAccount{
+		Login:    summary.Login,
+		Company:  summary.CompanyName,
+		Currency: summary.Currency,
+		Leverage: summary.Leverage,
+		Balance:  summary.Balance,
+		Equity:   summary.Equity,
+		Credit:   summary.Credit,
+	}
+	if summary.ServerTime != nil {
+		account.ServerTime = *summary.ServerTime
+	}
+	if server, err := s.service.GetAccountString(ctx, pb.AccountInfoStringPropertyType_ACCOUNT_SERVER); err == nil {
+		account.Server = server
+	}
+	doubles := map[pb.AccountInfoDoublePropertyType]*float64{
+		pb.AccountInfoDoublePropertyType_ACCOUNT_MARGIN:       &account.Margin,
+		pb.AccountInfoDoublePropertyType_ACCOUNT_MARGIN_FREE:  &account.FreeMargin,
+		pb.AccountInfoDoublePropertyType_ACCOUNT_MARGIN_LEVEL: &account.MarginLevel,
+	}
+	for prop, dst := range doubles {
+		value, err := s.service.GetAccountDouble(ctx, prop)
+		if err != nil {
+			return nil, err
+		}
+		*dst = value
+	}
+	return account, nil
+}
@@ -0,0 +1,254 @@
+package mt5
+
+/*
+Trade guards - account and symbol permission checks before sending orders.
+
+A broker that disabled trading answers every order with a generic reject
+(10017 "trade disabled", 10044 "only closing allowed", ...). Checking the
+permissions first turns that into a descriptive, typed error:
+
+  • ErrTradingDisabled        - ACCOUNT_TRADE_ALLOWED = 0 (broker / investor login)
+  • ErrExpertTradingDisabled  - ACCOUNT_TRADE_EXPERT  = 0 (algo trading off)
+  • ErrSymbolTradeDisabled    - SYMBOL_TRADE_MODE = DISABLED
+  • ErrSymbolCloseOnly        - SYMBOL_TRADE_MODE = CLOSEONLY
+  • ErrSymbolLongOnly         - SELL on a LONGONLY symbol
+  • ErrSymbolShortOnly        - BUY on a SHORTONLY symbol
+
+Every error is a *TradePermissionError wrapping one of the sentinels, so
+errors.Is(err, mt5.ErrSymbolCloseOnly) works through any wrapping.
+
+Usage:
+    if err := service.CheckTradeAllowed(ctx, "EURUSD", true); err != nil {
+        if errors.Is(err, mt5.ErrTradingDisabled) { ... }
+    }
+
+Sugar runs the same checks before every order (see SetTradeGuards) from
+values cached per session, and lets orders that only reduce a netting
+position through close-only and one-sided symbols.
+*/
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+var (
+	ErrTradingDisabled       = errors.New("trading disabled by broker")
+	ErrExpertTradingDisabled = errors.New("algo trading disabled for this account")
+	ErrSymbolTradeDisabled   = errors.New("trading disabled for symbol")
+	ErrSymbolCloseOnly       = errors.New("close-only symbol")
+	ErrSymbolLongOnly        = errors.New("long-only symbol")
+	ErrSymbolShortOnly       = errors.New("short-only symbol")
+)
+
+// TradePermissionError explains why an order may not be sent.
+type TradePermissionError struct {
+	Symbol string // Symbol of the order ("" for account-level reasons)
+	Reason error  // One of the Err* sentinels above
+}
+
+func (e *TradePermissionError) Error() string {
+	if e.Symbol == "" {
+		return e.Reason.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.Reason, e.Symbol)
+}
+
+func (e *TradePermissionError) Unwrap() error {
+	return e.Reason
+}
+
+// CheckTradeAllowed verifies that a new position in the given direction may be
+// opened: account trading allowed, algo trading allowed, and the symbol's trade
+// mode permits opening that side.
+//
+// Parameters:
+//   - ctx: Context for timeout and cancellation
+//   - symbol: Trading symbol
+//   - buy: Direction of the order to open
+//
+// Returns:
+//   - *TradePermissionError if trading is not permitted
+//   - Other error if a property could not be read
+func (s *MT5Service) CheckTradeAllowed(ctx context.Context, symbol string, buy bool) error {
+	allowed, err := s.GetAccountInteger(ctx, pb.AccountInfoIntegerPropertyType_ACCOUNT_TRADE_ALLOWED)
+	if err != nil {
+		return fmt.Errorf("CheckTradeAllowed failed: %w", err)
+	}
+	if allowed == 0 {
+		return &TradePermissionError{Reason: ErrTradingDisabled}
+	}
+
+	expert, err := s.GetAccountInteger(ctx, pb.AccountInfoIntegerPropertyType_ACCOUNT_TRADE_EXPERT)
+	if err != nil {
+		return fmt.Errorf("CheckTradeAllowed failed: %w", err)
+	}
+	if expert == 0 {
+		return &TradePermissionError{Reason: ErrExpertTradingDisabled}
+	}
+
+	mode, err := s.GetSymbolInteger(ctx, symbol, pb.SymbolInfoIntegerProperty_SYMBOL_TRADE_MODE)
+	if err != nil {
+		return fmt.Errorf("CheckTradeAllowed failed: %w", err)
+	}
+
+	return checkSymbolTradeMode(symbol, pb.BMT5_ENUM_SYMBOL_TRADE_MODE(mode), buy)
+}
+
+// checkSymbolTradeMode returns a *TradePermissionError if the symbol's trade
+// mode does not allow opening the given direction.
+func checkSymbolTradeMode(symbol string, mode pb.BMT5_ENUM_SYMBOL_TRADE_MODE, buy bool) error {
+	switch mode {
+	case pb.BMT5_ENUM_SYMBOL_TRADE_MODE_BMT5_SYMBOL_TRADE_MODE_DISABLED:
+		return &TradePermissionError{Symbol: symbol, Reason: ErrSymbolTradeDisabled}
+	case pb.BMT5_ENUM_SYMBOL_TRADE_MODE_BMT5_SYMBOL_TRADE_MODE_CLOSEONLY:
+		return &TradePermissionError{Symbol: symbol, Reason: ErrSymbolCloseOnly}
+	case pb.BMT5_ENUM_SYMBOL_TRADE_MODE_BMT5_SYMBOL_TRADE_MODE_LONGONLY:
+		if !buy {
+			return &TradePermissionError{Symbol: symbol, Reason: ErrSymbolLongOnly}
+		}
+	case pb.BMT5_ENUM_SYMBOL_TRADE_MODE_BMT5_SYMBOL_TRADE_MODE_SHORTONLY:
+		if buy {
+			return &TradePermissionError{Symbol: symbol, Reason: ErrSymbolShortOnly}
+		}
+	}
+	return nil
+}
+
+// accountTradeModes are the account properties read by Sugar's trade guards.
+type accountTradeModes struct {
+	allowed bool // ACCOUNT_TRADE_ALLOWED
+	expert  bool // ACCOUNT_TRADE_EXPERT
+	hedging bool // ACCOUNT_MARGIN_MODE = RETAIL_HEDGING
+}
+
+// tradeGuardCache holds the trade modes read by Sugar's trade guards for the
+// current session.
+type tradeGuardCache struct {
+	mu      sync.Mutex
+	account *accountTradeModes                        // nil = not read yet
+	symbols map[string]pb.BMT5_ENUM_SYMBOL_TRADE_MODE // SYMBOL_TRADE_MODE per symbol
+}
+
+// checkTradeAllowed is CheckTradeAllowed for an order sent through Sugar.
+//
+// Account and symbol trade modes are read once per session (reset on
+// QuickConnect/Connect), so an order normally costs no extra round trip. A
+// refusal is confirmed with fresh values first, since the broker may have
+// changed them meanwhile. On netting accounts a market order that only
+// reduces the open position passes CLOSEONLY, LONGONLY and SHORTONLY symbols.
+func (s *MT5Sugar) checkTradeAllowed(ctx context.Context, req *pb.OrderSendRequest) error {
+	err := s.tradeAllowed(ctx, req, false)
+	var denied *TradePermissionError
+	if errors.As(err, &denied) {
+		err = s.tradeAllowed(ctx, req, true)
+	}
+	return err
+}
+
+// tradeAllowed checks req against the cached trade modes, reading them
+// first if they are missing or refresh is set.
+func (s *MT5Sugar) tradeAllowed(ctx context.Context, req *pb.OrderSendRequest, refresh bool) error {
+	account, mode, err := s.tradeModes(ctx, req.Symbol, refresh)
+	if err != nil {
+		return fmt.Errorf("CheckTradeAllowed failed: %w", err)
+	}
+	if !account.allowed {
+		return &TradePermissionError{Reason: ErrTradingDisabled}
+	}
+	if !account.expert {
+		return &TradePermissionError{Reason: ErrExpertTradingDisabled}
+	}
+
+	order := OrderRequest{Type: req.Operation}
+	err = checkSymbolTradeMode(req.Symbol, mode, order.IsBuy())
+	if err == nil || account.hedging || !order.IsMarket() || errors.Is(err, ErrSymbolTradeDisabled) {
+		return err
+	}
+
+	// Netting: an opposite order up to the position volume only closes
+	reducing, lookupErr := s.reducesPosition(ctx, req.Symbol, order.IsBuy(), req.Volume)
+	if lookupErr != nil || !reducing {
+		return err
+	}
+	return nil
+}
+
+// tradeModes returns the account's and the symbol's trade modes from the
+// session cache, reading whatever is missing (everything if refresh is set).
+func (s *MT5Sugar) tradeModes(ctx context.Context, symbol string, refresh bool) (accountTradeModes, pb.BMT5_ENUM_SYMBOL_TRADE_MODE, error) {
+	s.guards.mu.Lock()
+	account := s.guards.account
+	mode, known := s.guards.symbols[symbol]
+	s.guards.mu.Unlock()
+
+	if account == nil || refresh {
+		props := []pb.AccountInfoIntegerPropertyType{
+			pb.AccountInfoIntegerPropertyType_ACCOUNT_TRADE_ALLOWED,
+			pb.AccountInfoIntegerPropertyType_ACCOUNT_TRADE_EXPERT,
+			pb.AccountInfoIntegerPropertyType_ACCOUNT_MARGIN_MODE,
+		}
+		values := make([]int64, len(props))
+		for i, prop := range props {
+			value, err := s.service.GetAccountInteger(ctx, prop)
+			if err != nil {
+				return accountTradeModes{}, 0, err
+			}
+			values[i] = value
+		}
+		account = &accountTradeModes{
+			allowed: values[0] != 0,
+			expert:  values[1] != 0,
+			hedging: AccountMarginMode(values[2]) == MarginModeRetailHedging,
+		}
+	}
+
+	if !known || refresh {
+		value, err := s.service.GetSymbolInteger(ctx, symbol, pb.SymbolInfoIntegerProperty_SYMBOL_TRADE_MODE)
+		if err != nil {
+			return accountTradeModes{}, 0, err
+		}
+		mode = pb.BMT5_ENUM_SYMBOL_TRADE_MODE(value)
+	}
+
+	s.guards.mu.Lock()
+	s.guards.account = account
+	if s.guards.symbols == nil {
+		s.guards.symbols = make(map[string]pb.BMT5_ENUM_SYMBOL_TRADE_MODE)
+	}
+	s.guards.symbols[symbol] = mode
+	s.guards.mu.Unlock()
+
+	return *account, mode, nil
+}
+
+// reducesPosition reports whether an order of volume in the given direction
+// only reduces the symbol's open (netting) position.
+func (s *MT5Sugar) reducesPosition(ctx context.Context, symbol string, buy bool, volume float64) (bool, error) {
+	data, err := s.service.GetOpenedOrders(ctx, pb.BMT5_ENUM_OPENED_ORDER_SORT_TYPE_BMT5_OPENED_ORDER_SORT_BY_OPEN_TIME_ASC)
+	if err != nil {
+		return false, err
+	}
+	for _, pos := range data.PositionInfos {
+		if pos.Symbol != symbol {
+			continue
+		}
+		long := pos.Type == pb.BMT5_ENUM_POSITION_TYPE_BMT5_POSITION_TYPE_BUY
+		if long != buy && volume <= pos.Volume+1e-9 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// resetTradeGuards drops the cached trade modes (new session).
+func (s *MT5Sugar) resetTradeGuards() {
+	s.guards.mu.Lock()
+	s.guards.account = nil
+	s.guards.symbols = nil
+	s.guards.mu.Unlock()
+}
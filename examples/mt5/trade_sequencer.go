@@ -0,0 +1,427 @@
+package mt5
+
+/*
+TradeSequencer - exactly-once, ordered trade transaction events.
+
+The trade streams are restarted transparently when the connection drops.
+Around a reconnect the server may replay transactions that were already
+delivered, and events of one order can arrive out of lifecycle order
+(e.g., HISTORY_ADD before the ORDER_UPDATE that preceded it). Consumers that
+count fills or track order state then double-count or resurrect closed
+orders.
+
+TradeSequencer sits between the stream and the consumer:
+  • Dedup    - every event has a key. Deals, requests and order add/delete
+               are unique and a repeated key is always dropped. Updates
+               are keyed by content (ticket + state + changed fields), and
+               a real A → B → A modification repeats a key, so a repeated
+               update is dropped only while the stream replays after a
+               reconnect (sequencerReplayWindow)
+  • Ordering - events are held for a short window and released per order
+               in lifecycle order: ORDER_ADD → ORDER_UPDATE/DEAL → ORDER_DELETE → HISTORY
+  • Stale    - ORDER_ADD/ORDER_UPDATE arriving after the order was removed
+               are dropped instead of reopening it
+
+OnTrade (StreamTradeUpdates) carries no tickets per message, only diffs of
+the terminal state; those messages are deduplicated by content and passed
+on in arrival order.
+
+Usage:
+    txCh, errCh := service.StreamTransactionsSequenced(ctx, 0)
+    for tx := range txCh {
+        // each transaction exactly once, per-order lifecycle order
+    }
+*/
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+	helpers "github.com/MetaRPC/GoMT5/package/Helpers"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultSequencerWindow is how long events are held for reordering.
+const DefaultSequencerWindow = 250 * time.Millisecond
+
+// sequencerRetention is how long delivered keys and closed orders are remembered.
+const sequencerRetention = 15 * time.Minute
+
+// sequencerReplayWindow is how long after the first event of a reconnected
+// stream repeated updates are taken for replays.
+const sequencerReplayWindow = 10 * time.Second
+
+// TradeSequencer deduplicates and orders trade transactions. Safe for concurrent use.
+type TradeSequencer struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	pending []sequencedTx
+	seq     uint64
+	seen    map[string]time.Time // Delivered (or pending) event keys
+	closed  map[uint64]time.Time // Orders whose ORDER_DELETE/HISTORY_ADD was delivered
+	pruned  time.Time
+
+	replaying   bool      // Stream reconnected; replayed updates may follow
+	replayUntil time.Time // End of the replay window (zero until the first event)
+}
+
+// sequencedTx is a held transaction with its arrival order.
+type sequencedTx struct {
+	data    *pb.OnTradeTransactionData
+	arrived time.Time
+	seq     uint64
+}
+
+// NewTradeSequencer creates a sequencer holding events for window (default 250ms).
+func NewTradeSequencer(window time.Duration) *TradeSequencer {
+	if window <= 0 {
+		window = DefaultSequencerWindow
+	}
+	return &TradeSequencer{
+		window: window,
+		seen:   make(map[string]time.Time),
+		closed: make(map[uint64]time.Time),
+	}
+}
+
+// Reconnected tells the sequencer that the stream was re-opened. For
+// sequencerReplayWindow from the next event, an update identical to one
+// already seen is taken for a replay and dropped.
+func (q *TradeSequencer) Reconnected() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.replaying = true
+	q.replayUntil = time.Time{}
+}
+
+// Push adds a transaction. Duplicates are dropped.
+//
+// Returns:
+//   - false if the event was a duplicate
+func (q *TradeSequencer) Push(data *pb.OnTradeTransactionData) bool {
+	key, unique := transactionKey(data)
+	now := time.Now()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.replaying && q.replayUntil.IsZero() {
+		q.replayUntil = now.Add(sequencerReplayWindow)
+	}
+	if q.replaying && now.After(q.replayUntil) {
+		q.replaying = false
+	}
+	if _, dup := q.seen[key]; dup && (unique || q.replaying) {
+		return false
+	}
+	q.seen[key] = now
+	q.seq++
+	q.pending = append(q.pending, sequencedTx{data: data, arrived: now, seq: q.seq})
+	return true
+}
+
+// Release returns held events older than the window, ordered per order.
+// Pass force = true to release everything (e.g., when the stream ends).
+func (q *TradeSequencer) Release(force bool) []*pb.OnTradeTransactionData {
+	now := time.Now()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.prune(now)
+
+	// An order is released only when all of its held events are due, so a
+	// late event can still be sorted in front of a newer one.
+	ready := func(item sequencedTx) bool {
+		return force || now.Sub(item.arrived) >= q.window
+	}
+	due := make(map[uint64]bool)
+	for _, item := range q.pending {
+		ticket := transactionOrder(item.data)
+		if ticket == 0 {
+			continue
+		}
+		if prev, ok := due[ticket]; ok {
+			due[ticket] = prev && ready(item)
+		} else {
+			due[ticket] = ready(item)
+		}
+	}
+
+	var batch, kept []sequencedTx
+	for _, item := range q.pending {
+		ticket := transactionOrder(item.data)
+		if ticket == 0 && ready(item) || ticket != 0 && due[ticket] {
+			batch = append(batch, item)
+		} else {
+			kept = append(kept, item)
+		}
+	}
+	q.pending = kept
+	if len(batch) == 0 {
+		return nil
+	}
+
+	// Per order: lifecycle stage, then arrival. Orders keep the position of their first event.
+	first := make(map[uint64]uint64)
+	for _, item := range batch {
+		ticket := transactionOrder(item.data)
+		if _, ok := first[ticket]; !ok && ticket != 0 {
+			first[ticket] = item.seq
+		}
+	}
+	sort.SliceStable(batch, func(i, j int) bool {
+		ti, tj := transactionOrder(batch[i].data), transactionOrder(batch[j].data)
+		gi, gj := batch[i].seq, batch[j].seq
+		if ti != 0 {
+			gi = first[ti]
+		}
+		if tj != 0 {
+			gj = first[tj]
+		}
+		if gi != gj {
+			return gi < gj
+		}
+		si, sj := transactionStage(batch[i].data), transactionStage(batch[j].data)
+		if si != sj {
+			return si < sj
+		}
+		return batch[i].seq < batch[j].seq
+	})
+
+	out := make([]*pb.OnTradeTransactionData, 0, len(batch))
+	for _, item := range batch {
+		tx := item.data.GetTradeTransaction()
+		ticket := transactionOrder(item.data)
+		if ticket != 0 && tx != nil {
+			switch tx.Type {
+			case pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_ORDER_ADD,
+				pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_ORDER_UPDATE:
+				if _, gone := q.closed[ticket]; gone {
+					continue
+				}
+			case pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_ORDER_DELETE,
+				pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_HISTORY_ADD:
+				q.closed[ticket] = now
+			}
+		}
+		out = append(out, item.data)
+	}
+	return out
+}
+
+// Pending returns the number of held events.
+func (q *TradeSequencer) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// prune forgets keys and closed orders older than the retention period.
+func (q *TradeSequencer) prune(now time.Time) {
+	if now.Sub(q.pruned) < time.Minute {
+		return
+	}
+	q.pruned = now
+	for key, at := range q.seen {
+		if now.Sub(at) > sequencerRetention {
+			delete(q.seen, key)
+		}
+	}
+	for ticket, at := range q.closed {
+		if now.Sub(at) > sequencerRetention {
+			delete(q.closed, ticket)
+		}
+	}
+}
+
+// transactionOrder returns the order ticket an event belongs to (0 = none).
+func transactionOrder(data *pb.OnTradeTransactionData) uint64 {
+	return data.GetTradeTransaction().GetOrderTicket()
+}
+
+// transactionStage ranks events of one order by lifecycle.
+func transactionStage(data *pb.OnTradeTransactionData) int {
+	switch data.GetTradeTransaction().GetType() {
+	case pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_ORDER_ADD:
+		return 0
+	case pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_ORDER_DELETE:
+		return 2
+	case pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_HISTORY_ADD,
+		pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_HISTORY_UPDATE,
+		pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_HISTORY_DELETE:
+		return 3
+	default: // ORDER_UPDATE, DEAL_*, REQUEST, POSITION keep arrival order
+		return 1
+	}
+}
+
+// transactionKey identifies an event. Deals, requests and order add/delete
+// happen once per ticket (unique = true). Updates are keyed by ticket + state
+// plus the fields an update can change, which a later update may repeat.
+func transactionKey(data *pb.OnTradeTransactionData) (key string, unique bool) {
+	tx := data.GetTradeTransaction()
+	switch tx.GetType() {
+	case pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_DEAL_ADD,
+		pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_DEAL_DELETE:
+		return fmt.Sprintf("%d|%d", tx.GetType(), tx.GetDealTicket()), true
+	case pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_REQUEST:
+		return fmt.Sprintf("%d|%d|%d|%d", tx.GetType(), data.GetTradeResult().GetTerminalDispatchRequestId(),
+			data.GetTradeResult().GetOrderTicket(), data.GetTradeResult().GetDealTicket()), true
+	case pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_ORDER_ADD,
+		pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_ORDER_DELETE,
+		pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_HISTORY_ADD,
+		pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_HISTORY_DELETE:
+		return fmt.Sprintf("%d|%d", tx.GetType(), tx.GetOrderTicket()), true
+	case pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_POSITION:
+		return fmt.Sprintf("%d|%d|%g|%g|%g|%g", tx.GetType(), tx.GetPositionTicket(),
+			tx.GetVolume(), tx.GetPrice(), tx.GetPriceStopLoss(), tx.GetPriceTakeProfit()), false
+	default:
+		return fmt.Sprintf("%d|%d|%d|%d|%g|%g|%g|%g", tx.GetType(), tx.GetOrderTicket(), tx.GetDealTicket(),
+			tx.GetOrderState(), tx.GetVolume(), tx.GetPrice(), tx.GetPriceStopLoss(), tx.GetPriceTakeProfit()), false
+	}
+}
+
+// StreamTransactionsSequenced is StreamTransactions with duplicates removed
+// and events of each order delivered in lifecycle order.
+//
+// Parameters:
+//   - ctx: Context for cancellation (closing ctx stops the stream)
+//   - window: How long events are held for reordering (default 250ms)
+//
+// Returns:
+//   - Read-only channel of *pb.OnTradeTransactionData, each event exactly once
+//   - Read-only channel of errors
+func (s *MT5Service) StreamTransactionsSequenced(ctx context.Context, window time.Duration) (<-chan *pb.OnTradeTransactionData, <-chan error) {
+	sequencer := NewTradeSequencer(window)
+
+	// Same options as the account's streams, plus the reconnect signal
+	opts := s.account.StreamOptions
+	onReconnect := opts.OnStreamReconnect
+	opts.OnStreamReconnect = func(r helpers.StreamReconnect) {
+		sequencer.Reconnected()
+		if onReconnect != nil {
+			onReconnect(r)
+		}
+	}
+	txCh, errCh := s.account.OnTradeTransactionWithOptions(ctx, &pb.OnTradeTransactionRequest{}, opts)
+
+	outCh := make(chan *pb.OnTradeTransactionData, 64)
+	outErr := make(chan error, 1)
+
+	go func() {
+		defer close(outCh)
+		defer close(outErr)
+
+		ticker := time.NewTicker(sequencer.window / 2)
+		defer ticker.Stop()
+
+		deliver := func(force bool) bool {
+			for _, data := range sequencer.Release(force) {
+				select {
+				case outCh <- data:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			return true
+		}
+
+		for {
+			select {
+			case data, ok := <-txCh:
+				if !ok {
+					deliver(true)
+					return
+				}
+				sequencer.Push(data)
+			case err, ok := <-errCh:
+				if !ok {
+					errCh = nil
+					continue
+				}
+				deliver(true)
+				outErr <- err
+				return
+			case <-ticker.C:
+				if !deliver(false) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return outCh, outErr
+}
+
+// StreamTradeUpdatesDeduped is StreamTradeUpdates without replayed messages.
+// OnTrade messages carry no sequence, so they are compared by content and
+// passed on in arrival order.
+//
+// Parameters:
+//   - ctx: Context for cancellation (closing ctx stops the stream)
+//
+// Returns:
+//   - Read-only channel of *pb.OnTradeData, each distinct event exactly once
+//   - Read-only channel of errors
+func (s *MT5Service) StreamTradeUpdatesDeduped(ctx context.Context) (<-chan *pb.OnTradeData, <-chan error) {
+	dataCh, errCh := s.StreamTradeUpdates(ctx)
+
+	outCh := make(chan *pb.OnTradeData, 64)
+	outErr := make(chan error, 1)
+
+	go func() {
+		defer close(outCh)
+		defer close(outErr)
+
+		seen := make(map[string]time.Time)
+		marshal := proto.MarshalOptions{Deterministic: true}
+
+		for {
+			select {
+			case data, ok := <-dataCh:
+				if !ok {
+					return
+				}
+				raw, err := marshal.Marshal(data.GetEventData())
+				if err == nil {
+					now := time.Now()
+					if at, dup := seen[string(raw)]; dup && now.Sub(at) < sequencerRetention {
+						continue
+					}
+					seen[string(raw)] = now
+					if len(seen) > 10000 {
+						for key, at := range seen {
+							if now.Sub(at) > sequencerRetention {
+								delete(seen, key)
+							}
+						}
+					}
+				}
+				select {
+				case outCh <- data:
+				case <-ctx.Done():
+					return
+				}
+			case err, ok := <-errCh:
+				if !ok {
+					errCh = nil
+					continue
+				}
+				outErr <- err
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return outCh, outErr
+}
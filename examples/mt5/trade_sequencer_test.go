@@ -0,0 +1,79 @@
+package mt5
+
+import (
+	"testing"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// orderUpdate is an ORDER_UPDATE of order #300001 moving its stop loss.
+func orderUpdate(stopLoss float64) *pb.OnTradeTransactionData {
+	return &pb.OnTradeTransactionData{TradeTransaction: &pb.MqlTradeTransaction{
+		Type:          pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_ORDER_UPDATE,
+		OrderTicket:   300001,
+		OrderState:    pb.SUB_ENUM_ORDER_STATE_SUB_ORDER_STATE_PLACED,
+		Price:         1.0950,
+		PriceStopLoss: stopLoss,
+		Volume:        0.1,
+	}}
+}
+
+// released returns the stop losses of the released order updates.
+func released(q *TradeSequencer) []float64 {
+	var stops []float64
+	for _, data := range q.Release(true) {
+		stops = append(stops, data.GetTradeTransaction().GetPriceStopLoss())
+	}
+	return stops
+}
+
+// TestSequencerModifyBackAndForth: moving a stop A → B → A repeats the first
+// update's content; all three are real and must be delivered.
+func TestSequencerModifyBackAndForth(t *testing.T) {
+	q := NewTradeSequencer(0)
+	for _, sl := range []float64{1.0900, 1.0910, 1.0900} {
+		if !q.Push(orderUpdate(sl)) {
+			t.Fatalf("update to SL %v dropped as duplicate", sl)
+		}
+	}
+	if got := released(q); len(got) != 3 || got[2] != 1.0900 {
+		t.Fatalf("released stops = %v, want [1.09 1.091 1.09]", got)
+	}
+}
+
+// TestSequencerReplayAfterReconnect: updates replayed right after a
+// reconnect are dropped, new ones still pass.
+func TestSequencerReplayAfterReconnect(t *testing.T) {
+	q := NewTradeSequencer(0)
+	q.Push(orderUpdate(1.0900))
+	q.Push(orderUpdate(1.0910))
+	released(q)
+
+	q.Reconnected()
+	if q.Push(orderUpdate(1.0900)) || q.Push(orderUpdate(1.0910)) {
+		t.Fatal("replayed update delivered again after reconnect")
+	}
+	if !q.Push(orderUpdate(1.0920)) {
+		t.Fatal("new update after reconnect dropped")
+	}
+	if got := released(q); len(got) != 1 || got[0] != 1.0920 {
+		t.Fatalf("released stops = %v, want [1.092]", got)
+	}
+}
+
+// TestSequencerDealOnce: a deal is delivered once, reconnect or not.
+func TestSequencerDealOnce(t *testing.T) {
+	deal := &pb.OnTradeTransactionData{TradeTransaction: &pb.MqlTradeTransaction{
+		Type:        pb.SUB_ENUM_TRADE_TRANSACTION_TYPE_SUB_TRADE_TRANSACTION_DEAL_ADD,
+		OrderTicket: 300001,
+		DealTicket:  300002,
+		Volume:      0.1,
+	}}
+	q := NewTradeSequencer(0)
+	if !q.Push(deal) {
+		t.Fatal("first deal dropped")
+	}
+	if q.Push(deal) {
+		t.Fatal("repeated deal delivered twice")
+	}
+}
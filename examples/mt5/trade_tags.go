@@ -0,0 +1,146 @@
+package mt5
+
+/*
+Trade tags - default comment and magic number for every order sent through Sugar.
+
+BuyMarket, SellLimit and the other convenience methods take no comment or
+magic, so their orders land in history untagged and PositionQuery,
+CloseByMagic and P&L attribution cannot tell which strategy opened them.
+Trade tags fill in both fields on every order Sugar sends:
+
+  • SetTradeTags(tags)   - defaults for this Sugar instance
+  • WithTradeTags(tags)  - a Sugar on the same connection with its own
+                           defaults, e.g. one per orchestrator
+
+A comment or magic set on the request itself (SendOrder, ReversePosition,
+HedgePosition, ...) wins over the default, field by field. Tags are
+validated against the 31-character MT5 comment limit up front, so an order
+is never rejected for its comment.
+
+Usage:
+    sugar.SetTradeTags(mt5.TradeTags{Comment: "scalper", Magic: 5001})
+    ticket, err := sugar.BuyMarket("EURUSD", 0.1) // comment "scalper", magic 5001
+
+    // With a TagManager: strategy/signal/version encoded like tags.Apply
+    gridTags, err := tags.TradeTags(mt5.Tag{Strategy: "grid", Version: 3})
+    gridSugar, err := sugar.WithTradeTags(gridTags)
+    grid := orchestrators.NewGridTrader(gridSugar, config)
+*/
+
+import (
+	"fmt"
+
+	pb "github.com/MetaRPC/GoMT5/package"
+)
+
+// TradeTags are the comment and magic number written into orders that do
+// not set their own. Zero fields are left unset.
+type TradeTags struct {
+	Comment string // Order comment, at most MaxCommentLength characters
+	Magic   uint64 // Expert Advisor ID (magic number)
+}
+
+// Validate checks the comment length.
+func (t TradeTags) Validate() error {
+	if len(t.Comment) > MaxCommentLength {
+		return fmt.Errorf("trade tag comment %q exceeds %d characters", t.Comment, MaxCommentLength)
+	}
+	return nil
+}
+
+// TradeTags returns the comment and magic number of a tag for use as Sugar
+// defaults (see MT5Sugar.SetTradeTags).
+func (m *TagManager) TradeTags(tag Tag) (TradeTags, error) {
+	comment, magic, err := m.Encode(tag)
+	if err != nil {
+		return TradeTags{}, err
+	}
+	return TradeTags{Comment: comment, Magic: magic}, nil
+}
+
+// SetTradeTags sets the comment and magic number for every order sent
+// through this Sugar that does not set its own. TradeTags{} disables.
+//
+// Returns:
+//   - Error if the comment exceeds MaxCommentLength
+func (s *MT5Sugar) SetTradeTags(tags TradeTags) error {
+	if err := tags.Validate(); err != nil {
+		return err
+	}
+	s.devMu.Lock()
+	defer s.devMu.Unlock()
+	s.tradeTags = tags
+	return nil
+}
+
+// TradeTags returns the current default comment and magic number.
+func (s *MT5Sugar) TradeTags() TradeTags {
+	s.devMu.Lock()
+	defer s.devMu.Unlock()
+	return s.tradeTags
+}
+
+// WithTradeTags returns a Sugar that tags its orders with tags and shares
+// everything else with s: the connection, symbol resolver, tag manager,
+// news filter, commission model and worker pool. Other settings are copied
+// as they are now; later Set* calls on either instance affect only that
+// instance. Connect s, not the returned Sugar.
+//
+// Returns:
+//   - *MT5Sugar for one strategy or orchestrator
+//   - Error if the comment exceeds MaxCommentLength
+func (s *MT5Sugar) WithTradeTags(tags TradeTags) (*MT5Sugar, error) {
+	if err := tags.Validate(); err != nil {
+		return nil, err
+	}
+
+	tagged := &MT5Sugar{
+		service:       s.service,
+		ctx:           s.ctx,
+		user:          s.user,
+		password:      s.password,
+		serverLoc:     s.serverLoc,
+		serverTZSet:   s.serverTZSet,
+		commissions:   s.commissions,
+		locale:        s.locale,
+		news:          s.news,
+		symbols:       s.symbols,
+		tags:          s.tags,
+		pool:          s.pool,
+		noTradeGuards: s.noTradeGuards,
+		tradeTags:     tags,
+	}
+
+	s.devMu.Lock()
+	tagged.maxDeviation, tagged.onDeviation = s.maxDeviation, s.onDeviation
+	tagged.stopsAdjust, tagged.onStopsAdjust = s.stopsAdjust, s.onStopsAdjust
+	s.devMu.Unlock()
+
+	s.swapMu.Lock()
+	tagged.swapForced, tagged.rolloverWindow = s.swapForced, s.rolloverWindow
+	s.swapMu.Unlock()
+
+	s.calMu.RLock()
+	if s.holidays != nil {
+		tagged.holidays = make(map[string]bool, len(s.holidays))
+		for key, closed := range s.holidays {
+			tagged.holidays[key] = closed
+		}
+	}
+	s.calMu.RUnlock()
+
+	return tagged, nil
+}
+
+// applyTradeTags fills in the default comment and magic of an order.
+func (s *MT5Sugar) applyTradeTags(req *pb.OrderSendRequest) {
+	tags := s.TradeTags()
+	if tags.Comment != "" && (req.Comment == nil || *req.Comment == "") {
+		comment := tags.Comment
+		req.Comment = &comment
+	}
+	if tags.Magic > 0 && (req.ExpertId == nil || *req.ExpertId == 0) {
+		magic := tags.Magic
+		req.ExpertId = &magic
+	}
+}
@@ -0,0 +1,251 @@
+package mt5
+
+/*
+VolumeProfile - price-bucketed volume and session statistics from ticks.
+
+Feeds on the tick stream and keeps, for the current session:
+  • Volume per price bucket (tick count and real volume)
+  • Point of Control (POC) - bucket with the most volume
+  • Value Area (VAH/VAL)   - price range holding ValueAreaPercent of volume
+  • Session High / Low / Open / Last
+  • VWAP                   - volume-weighted average price (tick-weighted if
+                             the symbol reports no real volume)
+
+Sessions roll over at sessionStart after midnight in the given timezone
+(use the broker's server timezone), using the same alignment as BarTime.
+The previous session's statistics stay available via Previous(), which is
+what breakout (prior high/low, value area) and mean-reversion (distance
+from VWAP / POC) logic usually needs. Safe for concurrent use.
+
+Usage:
+    profile := mt5.NewVolumeProfile("EURUSD", 0.0005, serverLoc, 0)
+    for tick := range ticks {
+        profile.Add(tick)
+    }
+    stats := profile.Stats()
+    fmt.Printf("VWAP %.5f  POC %.5f  VA %.5f-%.5f\n", stats.VWAP, stats.POC, stats.ValueAreaLow, stats.ValueAreaHigh)
+*/
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ProfileLevel is the volume traded at one price bucket.
+type ProfileLevel struct {
+	Price      float64 // Bucket lower bound
+	TickVolume uint64  // Ticks in bucket
+	RealVolume float64 // Real volume in bucket
+}
+
+// SessionStats summarizes one session of a VolumeProfile.
+type SessionStats struct {
+	Symbol        string
+	SessionStart  time.Time      // Session open (aligned)
+	Open          float64        // First price of the session
+	High          float64        // Session high
+	Low           float64        // Session low
+	Last          float64        // Latest price
+	VWAP          float64        // Volume-weighted average price
+	POC           float64        // Point of Control (bucket with most volume)
+	ValueAreaHigh float64        // Top of value area
+	ValueAreaLow  float64        // Bottom of value area
+	TickVolume    uint64         // Total ticks
+	RealVolume    float64        // Total real volume
+	Levels        []ProfileLevel // Buckets, lowest price first
+}
+
+// ValueAreaPercent is the share of session volume inside the value area.
+const ValueAreaPercent = 70.0
+
+// profileSession holds the running state of one session.
+type profileSession struct {
+	start      time.Time
+	open       float64
+	high       float64
+	low        float64
+	last       float64
+	pvSum      float64 // Sum of price * weight for VWAP
+	weightSum  float64 // Sum of weights for VWAP
+	tickVolume uint64
+	realVolume float64
+	levels     map[int64]*ProfileLevel
+}
+
+// VolumeProfile builds per-session volume profiles from ticks.
+type VolumeProfile struct {
+	symbol       string
+	bucket       float64
+	loc          *time.Location
+	sessionStart time.Duration
+
+	mu       sync.RWMutex
+	current  *profileSession
+	previous *SessionStats
+}
+
+// NewVolumeProfile creates a profile calculator.
+//
+// Parameters:
+//   - symbol: Symbol name (ticks of other symbols are ignored)
+//   - bucket: Price bucket size in price units (e.g., 0.0005)
+//   - loc: Server timezone for session boundaries (nil = UTC)
+//   - sessionStart: Session open offset from local midnight
+func NewVolumeProfile(symbol string, bucket float64, loc *time.Location, sessionStart time.Duration) *VolumeProfile {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return &VolumeProfile{
+		symbol:       symbol,
+		bucket:       bucket,
+		loc:          loc,
+		sessionStart: sessionStart,
+	}
+}
+
+// Add feeds a tick (Bid price). Starting a new session moves the current
+// statistics to Previous().
+func (p *VolumeProfile) Add(tick *SymbolTick) {
+	if tick == nil || tick.Bid <= 0 || tick.Symbol != p.symbol || p.bucket <= 0 {
+		return
+	}
+	price := tick.Bid
+	sessionStart := BarTime(tick.Time, 24*time.Hour, p.loc, p.sessionStart)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.current != nil && sessionStart.Before(p.current.start) {
+		return
+	}
+	if p.current == nil || sessionStart.After(p.current.start) {
+		if p.current != nil {
+			stats := p.statsLocked(p.current)
+			p.previous = &stats
+		}
+		p.current = &profileSession{
+			start:  sessionStart,
+			open:   price,
+			high:   price,
+			low:    price,
+			levels: make(map[int64]*ProfileLevel),
+		}
+	}
+
+	s := p.current
+	s.high = math.Max(s.high, price)
+	s.low = math.Min(s.low, price)
+	s.last = price
+	s.tickVolume++
+	s.realVolume += tick.VolumeReal
+
+	key := int64(math.Floor(price / p.bucket))
+	level, ok := s.levels[key]
+	if !ok {
+		level = &ProfileLevel{Price: float64(key) * p.bucket}
+		s.levels[key] = level
+	}
+	level.TickVolume++
+	level.RealVolume += tick.VolumeReal
+
+	weight := tick.VolumeReal
+	if weight <= 0 {
+		weight = 1
+	}
+	s.pvSum += price * weight
+	s.weightSum += weight
+}
+
+// Stats returns statistics of the current session (zero value before the first tick).
+func (p *VolumeProfile) Stats() SessionStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.current == nil {
+		return SessionStats{Symbol: p.symbol}
+	}
+	return p.statsLocked(p.current)
+}
+
+// Previous returns statistics of the last completed session, or nil.
+func (p *VolumeProfile) Previous() *SessionStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.previous == nil {
+		return nil
+	}
+	stats := *p.previous
+	return &stats
+}
+
+// statsLocked computes statistics of a session. Caller must hold p.mu.
+func (p *VolumeProfile) statsLocked(s *profileSession) SessionStats {
+	stats := SessionStats{
+		Symbol:       p.symbol,
+		SessionStart: s.start,
+		Open:         s.open,
+		High:         s.high,
+		Low:          s.low,
+		Last:         s.last,
+		TickVolume:   s.tickVolume,
+		RealVolume:   s.realVolume,
+		Levels:       make([]ProfileLevel, 0, len(s.levels)),
+	}
+	if s.weightSum > 0 {
+		stats.VWAP = s.pvSum / s.weightSum
+	}
+	for _, level := range s.levels {
+		stats.Levels = append(stats.Levels, *level)
+	}
+	sort.Slice(stats.Levels, func(i, j int) bool { return stats.Levels[i].Price < stats.Levels[j].Price })
+	if len(stats.Levels) == 0 {
+		return stats
+	}
+
+	// Real volume when the symbol reports it, tick count otherwise
+	useReal := s.realVolume > 0
+	volume := func(i int) float64 {
+		if useReal {
+			return stats.Levels[i].RealVolume
+		}
+		return float64(stats.Levels[i].TickVolume)
+	}
+
+	poc := 0
+	total := 0.0
+	for i := range stats.Levels {
+		total += volume(i)
+		if volume(i) > volume(poc) {
+			poc = i
+		}
+	}
+	stats.POC = stats.Levels[poc].Price
+
+	// Value area: grow from POC towards the larger neighbour until the target is covered
+	lo, hi := poc, poc
+	covered := volume(poc)
+	target := total * ValueAreaPercent / 100.0
+	for covered < target && (lo > 0 || hi < len(stats.Levels)-1) {
+		below, above := -1.0, -1.0
+		if lo > 0 {
+			below = volume(lo - 1)
+		}
+		if hi < len(stats.Levels)-1 {
+			above = volume(hi + 1)
+		}
+		if above >= below {
+			hi++
+			covered += above
+		} else {
+			lo--
+			covered += below
+		}
+	}
+	stats.ValueAreaLow = stats.Levels[lo].Price
+	stats.ValueAreaHigh = stats.Levels[hi].Price + p.bucket
+
+	return stats
+}
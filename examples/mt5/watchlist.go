@@ -0,0 +1,296 @@
+package mt5
+
+/*
+Watchlists - named symbol sets stored in a JSON file.
+
+Strategies and demos reference a watchlist by name ("majors", "metals")
+instead of hard-coding symbol slices, so the same code runs against another
+broker or universe by editing one file:
+
+    {
+      "majors": ["EURUSD", "GBPUSD", "USDJPY", "USDCHF"],
+      "metals": ["XAUUSD", "XAGUSD"]
+    }
+
+Symbols are stored in canonical form; every Sugar operation resolves them
+through the suffix resolver (EURUSD → EURUSD.pro). The store is rewritten
+atomically on every change and reloaded when the file changes on disk.
+
+Usage:
+    store, err := mt5.NewWatchlistStore("watchlists.json")
+    majors, err := store.Get("majors")
+
+    failed := sugar.EnsureVisible(majors)          // add to Market Watch
+    quotes, failed := sugar.SnapshotQuotes(majors) // one PriceInfo per symbol
+    ticks, errs := sugar.SubscribeWatchlist(ctx, majors)
+
+    store.Save(mt5.Watchlist{Name: "scalping", Symbols: []string{"EURUSD", "US500"}})
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrWatchlistNotFound is returned by WatchlistStore.Get for unknown names.
+var ErrWatchlistNotFound = errors.New("watchlist not found")
+
+// Watchlist is a named set of symbols.
+type Watchlist struct {
+	Name    string
+	Symbols []string // Canonical symbol names, in display order
+}
+
+// normalizeWatchlistSymbols trims, upper-cases and de-duplicates symbols,
+// keeping their order.
+func normalizeWatchlistSymbols(symbols []string) []string {
+	seen := make(map[string]bool, len(symbols))
+	result := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol == "" || seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		result = append(result, symbol)
+	}
+	return result
+}
+
+// WatchlistStore keeps watchlists in a JSON file (name → symbols).
+type WatchlistStore struct {
+	path string
+
+	mu      sync.Mutex
+	lists   map[string][]string
+	modTime time.Time
+	size    int64
+}
+
+// NewWatchlistStore opens the store at path. A missing file is an empty
+// store; it is created on the first Save.
+func NewWatchlistStore(path string) (*WatchlistStore, error) {
+	st := &WatchlistStore{path: path, lists: make(map[string][]string)}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if err := st.reloadLocked(); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// reloadLocked re-reads the file if it changed. Caller holds st.mu.
+func (st *WatchlistStore) reloadLocked() error {
+	info, err := os.Stat(st.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("watchlist store: %w", err)
+	}
+	if info.ModTime().Equal(st.modTime) && info.Size() == st.size {
+		return nil
+	}
+
+	data, err := os.ReadFile(st.path)
+	if err != nil {
+		return fmt.Errorf("watchlist store: %w", err)
+	}
+	lists := make(map[string][]string)
+	if err := json.Unmarshal(data, &lists); err != nil {
+		return fmt.Errorf("watchlist store: %s: %w", st.path, err)
+	}
+	for name, symbols := range lists {
+		lists[name] = normalizeWatchlistSymbols(symbols)
+	}
+	st.lists, st.modTime, st.size = lists, info.ModTime(), info.Size()
+	return nil
+}
+
+// writeLocked rewrites the file atomically. Caller holds st.mu.
+func (st *WatchlistStore) writeLocked() error {
+	data, err := json.MarshalIndent(st.lists, "", "  ")
+	if err != nil {
+		return fmt.Errorf("watchlist store: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(st.path), filepath.Base(st.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("watchlist store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("watchlist store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("watchlist store: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), st.path); err != nil {
+		return fmt.Errorf("watchlist store: %w", err)
+	}
+	if info, err := os.Stat(st.path); err == nil {
+		st.modTime, st.size = info.ModTime(), info.Size()
+	}
+	return nil
+}
+
+// Get returns a watchlist by name, or ErrWatchlistNotFound.
+func (st *WatchlistStore) Get(name string) (Watchlist, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if err := st.reloadLocked(); err != nil {
+		return Watchlist{}, err
+	}
+	symbols, ok := st.lists[name]
+	if !ok {
+		return Watchlist{}, fmt.Errorf("%w: %s", ErrWatchlistNotFound, name)
+	}
+	return Watchlist{Name: name, Symbols: append([]string(nil), symbols...)}, nil
+}
+
+// Names returns all watchlist names, sorted.
+func (st *WatchlistStore) Names() ([]string, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if err := st.reloadLocked(); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(st.lists))
+	for name := range st.lists {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Save creates or replaces a watchlist and writes the file.
+func (st *WatchlistStore) Save(list Watchlist) error {
+	if strings.TrimSpace(list.Name) == "" {
+		return fmt.Errorf("watchlist store: empty watchlist name")
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if err := st.reloadLocked(); err != nil {
+		return err
+	}
+	st.lists[list.Name] = normalizeWatchlistSymbols(list.Symbols)
+	return st.writeLocked()
+}
+
+// Delete removes a watchlist and writes the file. Unknown names are ignored.
+func (st *WatchlistStore) Delete(name string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if err := st.reloadLocked(); err != nil {
+		return err
+	}
+	if _, ok := st.lists[name]; !ok {
+		return nil
+	}
+	delete(st.lists, name)
+	return st.writeLocked()
+}
+
+// EnsureVisible adds every symbol of the watchlist to Market Watch, which
+// the terminal requires before it delivers quotes and ticks for a symbol.
+// Uses 5-second timeout per symbol.
+//
+// Parameters:
+//   - list: Watchlist to show
+//
+// Returns:
+//   - Map of canonical symbol → error for symbols that could not be
+//     selected (empty = all visible)
+func (s *MT5Sugar) EnsureVisible(list Watchlist) map[string]error {
+	return s.pool.ForEachSymbol(s.ctx, list.Symbols, func(ctx context.Context, symbol string) error {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		symbol = s.ResolveSymbol(symbol)
+		ok, err := s.service.SymbolSelect(ctx, symbol, true)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("symbol %s could not be selected", symbol)
+		}
+		return nil
+	})
+}
+
+// SnapshotQuotes returns the current quote of every symbol of the watchlist.
+//
+// Parameters:
+//   - list: Watchlist to quote
+//
+// Returns:
+//   - Map of canonical symbol → *PriceInfo for symbols quoted successfully
+//   - Map of canonical symbol → error for the others (empty = all quoted)
+func (s *MT5Sugar) SnapshotQuotes(list Watchlist) (map[string]*PriceInfo, map[string]error) {
+	var mu sync.Mutex
+	quotes := make(map[string]*PriceInfo, len(list.Symbols))
+	failed := s.pool.ForEachSymbol(s.ctx, list.Symbols, func(ctx context.Context, symbol string) error {
+		info, err := s.GetPriceInfo(symbol)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		quotes[symbol] = info
+		mu.Unlock()
+		return nil
+	})
+	return quotes, failed
+}
+
+// SubscribeWatchlist streams ticks of every symbol of the watchlist over one
+// subscription. Symbols are made visible first; symbols that cannot be
+// selected, and symbols outside their trading sessions (ErrMarketClosed,
+// once SetServerTimezone is set), are reported on the error channel right
+// away and streamed anyway.
+//
+// Parameters:
+//   - ctx: Context for cancellation (closing ctx stops the stream)
+//   - list: Watchlist to stream
+//
+// Returns:
+//   - Read-only channel of *SymbolTick (Symbol is the broker name)
+//   - Read-only channel of errors
+func (s *MT5Sugar) SubscribeWatchlist(ctx context.Context, list Watchlist) (<-chan *SymbolTick, <-chan error) {
+	failed := s.EnsureVisible(list)
+
+	symbols := make([]string, len(list.Symbols))
+	for i, symbol := range list.Symbols {
+		symbols[i] = s.ResolveSymbol(symbol)
+		if _, ok := failed[symbol]; !ok && s.marketClosed(symbol) {
+			failed[symbol] = ErrMarketClosed
+		}
+	}
+	ticks, streamErrs := s.service.StreamTicks(ctx, symbols)
+	if len(failed) == 0 {
+		return ticks, streamErrs
+	}
+
+	errCh := make(chan error, len(failed)+1)
+	for symbol, err := range failed {
+		errCh <- fmt.Errorf("SubscribeWatchlist %s: %w", symbol, err)
+	}
+	go func() {
+		defer close(errCh)
+		for err := range streamErrs {
+			select {
+			case errCh <- err:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ticks, errCh
+}
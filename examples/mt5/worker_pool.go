@@ -0,0 +1,153 @@
+package mt5
+
+/*
+WorkerPool - bounded parallelism with per-symbol serialization.
+
+Batch operations (closing many positions, rebalancing a portfolio, placing
+grid levels) are faster in parallel, but two concurrent trade requests on
+the same symbol race each other: a netting position changes under the
+second request, or the broker rejects it with "trade context busy".
+WorkerPool runs operations on different symbols concurrently (up to the
+worker limit) while operations on the same symbol run one at a time.
+
+The symbol locks are shared by every call on the pool, so a batch close and
+a rebalancer using the same pool never touch one symbol at the same time.
+
+Usage:
+    pool := sugar.WorkerPool()
+    errs := pool.Run(ctx, []mt5.SymbolTask{
+        {Symbol: "EURUSD", Run: func(ctx context.Context) error { return closeEURUSD(ctx) }},
+        {Symbol: "GBPUSD", Run: func(ctx context.Context) error { return closeGBPUSD(ctx) }},
+    })
+*/
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultPoolWorkers is the worker limit of the pool created by NewMT5Sugar.
+const DefaultPoolWorkers = 4
+
+// SymbolTask is one operation on one symbol.
+type SymbolTask struct {
+	Symbol string
+	Run    func(ctx context.Context) error
+}
+
+// WorkerPool runs symbol tasks with bounded parallelism. Safe for concurrent use.
+type WorkerPool struct {
+	slots chan struct{} // One token per running task
+
+	mu    sync.Mutex
+	locks map[string]*symbolLock
+}
+
+// symbolLock serializes tasks on one symbol; refs counts holders and waiters.
+type symbolLock struct {
+	ch   chan struct{}
+	refs int
+}
+
+// NewWorkerPool creates a pool running at most workers tasks at once (min 1).
+func NewWorkerPool(workers int) *WorkerPool {
+	if workers < 1 {
+		workers = 1
+	}
+	return &WorkerPool{
+		slots: make(chan struct{}, workers),
+		locks: make(map[string]*symbolLock),
+	}
+}
+
+// Workers returns the maximum number of tasks running at once.
+func (p *WorkerPool) Workers() int {
+	return cap(p.slots)
+}
+
+// Do runs fn once no other task holds symbol and a worker is free.
+//
+// Returns:
+//   - Error from fn, or ctx error if ctx ended while waiting
+func (p *WorkerPool) Do(ctx context.Context, symbol string, fn func(ctx context.Context) error) error {
+	lock := p.acquire(symbol)
+	defer p.release(symbol, lock)
+
+	// Symbol first, then worker: waiting for a busy symbol must not hold a worker
+	select {
+	case lock.ch <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-lock.ch }()
+
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-p.slots }()
+
+	return fn(ctx)
+}
+
+// Run executes all tasks and waits for them to finish.
+//
+// Returns:
+//   - One error per task, in task order (nil = success)
+func (p *WorkerPool) Run(ctx context.Context, tasks []SymbolTask) []error {
+	errs := make([]error, len(tasks))
+	var wg sync.WaitGroup
+	for i, task := range tasks {
+		wg.Add(1)
+		go func(i int, task SymbolTask) {
+			defer wg.Done()
+			errs[i] = p.Do(ctx, task.Symbol, task.Run)
+		}(i, task)
+	}
+	wg.Wait()
+	return errs
+}
+
+// ForEachSymbol runs fn once per symbol.
+//
+// Returns:
+//   - Map of symbol → error for symbols whose fn failed (empty = all succeeded)
+func (p *WorkerPool) ForEachSymbol(ctx context.Context, symbols []string, fn func(ctx context.Context, symbol string) error) map[string]error {
+	tasks := make([]SymbolTask, len(symbols))
+	for i, symbol := range symbols {
+		symbol := symbol
+		tasks[i] = SymbolTask{Symbol: symbol, Run: func(ctx context.Context) error { return fn(ctx, symbol) }}
+	}
+
+	failed := make(map[string]error)
+	for i, err := range p.Run(ctx, tasks) {
+		if err != nil {
+			failed[symbols[i]] = err
+		}
+	}
+	return failed
+}
+
+// acquire returns the lock of symbol, creating it on first use.
+func (p *WorkerPool) acquire(symbol string) *symbolLock {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	lock, ok := p.locks[symbol]
+	if !ok {
+		lock = &symbolLock{ch: make(chan struct{}, 1)}
+		p.locks[symbol] = lock
+	}
+	lock.refs++
+	return lock
+}
+
+// release drops a reference and forgets the lock once nobody uses it.
+func (p *WorkerPool) release(symbol string, lock *symbolLock) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	lock.refs--
+	if lock.refs == 0 {
+		delete(p.locks, symbol)
+	}
+}
@@ -65,6 +65,7 @@ METHOD GROUPS:
 
 7. STREAMING METHODS (5 methods) - Real-time data streams
    • OnSymbolTick                           - Stream tick data (Bid/Ask updates)
+     (fast paths: OnSymbolTickQuotes - pooled values, OnSymbolTickBatches - struct-of-arrays)
    • OnTrade                                - Stream trade events
    • OnPositionProfit                       - Stream position P&L updates
    • OnPositionsAndPendingOrdersTickets     - Stream ticket changes
@@ -72,10 +73,23 @@ METHOD GROUPS:
 
 UTILITIES:
    • NewMT5Account              - Create new MT5 account instance
+   • NewMT5AccountWithOptions   - Same with AccountOptions (gzip, message size limits)
+   • NewRecorder / NewReplayAccount - Capture RPC fixtures from a live account, serve them offline
+   • fakeserver.New             - In-process fake gateway for end-to-end tests (package fakeserver)
    • Close                      - Close gRPC connection
    • IsConnected                - Check connection status
+   • ActiveStreams              - Diagnostics for open subscriptions (StreamManager)
+   • UseDataEndpoint            - Route quotes, symbol info and history over a separate connection
+   • SetPriorityLanes           - Hold history/paging calls back while trade RPCs are in flight
+   • ConnState                  - Connectivity state, transitions, drops, keepalive failures, RTT
+   • SetSafety / Safety         - Trade RPC interlocks (read-only, lot/rate limits, whitelist)
+   • CheckOrderSend             - Run the OrderSend interlocks without sending
+   • TradeMode                  - Demo/contest/real, detected at connect time
    • ExecuteWithReconnect       - Generic wrapper for unary RPCs with auto-reconnect
    • ExecuteStreamWithReconnect - Generic wrapper for streaming RPCs with auto-reconnect
+   • ExecuteStreamWithOptions   - Same with explicit StreamOptions (lifetime, dial timeout, retry)
+   • RetryPolicy                - Backoff for unary retries and stream reconnects
+   • RetryStats / Logger        - Retry counters; retries logged at Debug, summarized Warn every RetryWarnEvery
 
 ══════════════════════════════════════════════════════════════════════════════
 */
@@ -86,11 +100,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
-	"math/rand"
+	"log/slog"
 	"time"
 	"net"
 	"strings"
+	"sync/atomic"
 
 	pb "git.mtapi.io/root/mrpc-proto/mt5/libraries/go"
 
@@ -99,9 +113,9 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
-	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/backoff"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -129,6 +143,20 @@ type MT5Account struct {
 	TradeFunctionsClient     pb.TradeFunctionsClient
 	HealthClient             pb.HealthClient
 	Id                       uuid.UUID
+	Streams                  *StreamManager // Owns all open subscriptions (see ActiveStreams)
+	StreamOptions            StreamOptions  // Lifetime, dial timeout and reconnect policy of every stream (zero = ctx only)
+	RetryPolicy              RetryPolicy    // Retry delays of unary calls (zero = DefaultRetryPolicy)
+	RetryWarnEvery           int            // Retries per summarized Warn log (0 = DefaultRetryWarnEvery, <0 = never)
+	Logger                   *slog.Logger   // Destination of retry and stream logs (nil = slog.Default())
+	Options                  AccountOptions // Connection options the account was dialed with (read-only)
+
+	conn      *connMonitor                 // Connectivity transitions and metrics (see ConnState)
+	safety    atomic.Pointer[safetyGuard]  // Trade RPC interlocks (see SetSafety), nil = none
+	lanes     *laneScheduler               // Trade/background call priority (see SetPriorityLanes), nil = off
+	tradeMode atomic.Int32                 // ACCOUNT_TRADE_MODE + 1, detected at connect (0 = unknown)
+	halt      atomic.Pointer[haltState]    // Kill switch (see Halt), nil = trading allowed
+	data      atomic.Pointer[dataEndpoint] // Market-data connection (see UseDataEndpoint), nil = GrpcConn
+	retries   retryCounter                 // Retry counters (see RetryStats)
 }
 
 type mrpcError interface {
@@ -139,10 +167,58 @@ type mrpcError interface {
 // Default grpcServer is "mt5.mrpc.pro:443" if empty string is provided.
 // The connection is established with TLS, keepalive, and automatic reconnect configured.
 func NewMT5Account(user uint64, password string, grpcServer string, id uuid.UUID) (*MT5Account, error) {
+	return NewMT5AccountWithOptions(user, password, grpcServer, id, AccountOptions{})
+}
+
+// NewMT5AccountWithOptions is NewMT5Account with connection options
+// (compression, message size limits, extra dial options).
+func NewMT5AccountWithOptions(user uint64, password string, grpcServer string, id uuid.UUID, opts AccountOptions) (*MT5Account, error) {
 	if grpcServer == "" {
 		grpcServer = "mt5.mrpc.pro:443"
 	}
 
+	conn, kp, err := dialServer(grpcServer, opts)
+	if err != nil {
+		return nil, err
+	}
+	return newAccount(user, password, grpcServer, id, conn, kp, opts), nil
+}
+
+// newAccount wires the service clients and monitors of an account to conn.
+func newAccount(user uint64, password string, grpcServer string, id uuid.UUID, conn *grpc.ClientConn, kp keepalive.ClientParameters, opts AccountOptions) *MT5Account {
+	monitor := newConnMonitor(kp)
+	go monitor.watch(conn)
+
+	return &MT5Account{
+		User:                     user,
+		Password:                 password,
+		GrpcServer:               grpcServer,
+		GrpcConn:                 conn,
+		ConnectionClient:         pb.NewConnectionClient(conn),
+		SubscriptionClient:       pb.NewSubscriptionServiceClient(conn),
+		AccountClient:            pb.NewAccountHelperClient(conn),
+		AccountInformationClient: pb.NewAccountInformationClient(conn),
+		TradeClient:              pb.NewTradingHelperClient(conn),
+		MarketInfoClient:         pb.NewMarketInfoClient(conn),
+		TradeFunctionsClient:     pb.NewTradeFunctionsClient(conn),
+		HealthClient:             pb.NewHealthClient(conn),
+		Id:                       id,
+		Streams:                  NewStreamManager(),
+		Options:                  opts,
+		conn:                     monitor,
+		Port:                     443,
+		ConnectTimeout:           30,
+	}
+}
+
+// dialServer opens a TLS gRPC connection to grpcServer with keepalive and
+// reconnect backoff configured from opts.
+func dialServer(grpcServer string, opts AccountOptions) (*grpc.ClientConn, keepalive.ClientParameters, error) {
+	extra, err := opts.dialOptions()
+	if err != nil {
+		return nil, keepalive.ClientParameters{}, fmt.Errorf("invalid account options: %w", err)
+	}
+
 	host := grpcServer
 	if strings.Contains(host, ":") {
 		if h, _, err := net.SplitHostPort(grpcServer); err == nil {
@@ -169,15 +245,9 @@ func NewMT5Account(user uint64, password string, grpcServer string, id uuid.UUID
 		MaxDelay:   3 * time.Second,
 	}
 
-	kp := keepalive.ClientParameters{
-		Time:                20 * time.Second,
-		Timeout:             5 * time.Second,
-		PermitWithoutStream: true,
-	}
+	kp := opts.keepaliveParams()
 
-	conn, err := grpc.DialContext(
-		dctx,
-		grpcServer,
+	dialOpts := append([]grpc.DialOption{
 		grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)),
 		grpc.WithBlock(),
 		grpc.WithConnectParams(grpc.ConnectParams{
@@ -185,28 +255,13 @@ func NewMT5Account(user uint64, password string, grpcServer string, id uuid.UUID
 			MinConnectTimeout: 5 * time.Second,
 		}),
 		grpc.WithKeepaliveParams(kp),
-	)
+	}, extra...)
+
+	conn, err := grpc.DialContext(dctx, grpcServer, dialOpts...)
 	if err != nil {
-		return nil, fmt.Errorf("grpc dial failed to %s: %w", grpcServer, err)
+		return nil, kp, fmt.Errorf("grpc dial failed to %s: %w", grpcServer, err)
 	}
-
-	return &MT5Account{
-		User:                     user,
-		Password:                 password,
-		GrpcServer:               grpcServer,
-		GrpcConn:                 conn,
-		ConnectionClient:         pb.NewConnectionClient(conn),
-		SubscriptionClient:       pb.NewSubscriptionServiceClient(conn),
-		AccountClient:            pb.NewAccountHelperClient(conn),
-		AccountInformationClient: pb.NewAccountInformationClient(conn),
-		TradeClient:              pb.NewTradingHelperClient(conn),
-		MarketInfoClient:         pb.NewMarketInfoClient(conn),
-		TradeFunctionsClient:     pb.NewTradeFunctionsClient(conn),
-		HealthClient:             pb.NewHealthClient(conn),
-		Id:                       id,
-		Port:                     443,
-		ConnectTimeout:           30,
-	}, nil
+	return conn, kp, nil
 }
 
 // isConnected checks if the account has an active gRPC connection.
@@ -223,10 +278,17 @@ func (a *MT5Account) getHeaders() metadata.MD {
 }
 
 // Close closes the gRPC connection and cleans up resources.
+// All open streams are cancelled and drained before the connection is closed.
 func (a *MT5Account) Close() error {
 	if a == nil {
 		return nil
 	}
+	if !a.Streams.CloseAll(5 * time.Second) {
+		a.logger().Warn("[streams] close timed out", "active", len(a.Streams.ActiveStreams()))
+	}
+	if d := a.data.Swap(nil); d != nil {
+		d.conn.Close()
+	}
 	if a.GrpcConn != nil {
 		err := a.GrpcConn.Close()
 		a.GrpcConn = nil
@@ -240,6 +302,15 @@ func (a *MT5Account) IsConnected() bool {
 	return a != nil && a.GrpcConn != nil && a.Id != uuid.Nil
 }
 
+// ActiveStreams returns diagnostics for all currently open subscriptions.
+// Use it to spot leaked readers: every stream should disappear once its ctx is cancelled.
+func (a *MT5Account) ActiveStreams() []StreamInfo {
+	if a == nil {
+		return nil
+	}
+	return a.Streams.ActiveStreams()
+}
+
 // ExecuteWithReconnect is THE CORE PATTERN used by ALL non-streaming methods in this file.
 //
 // WHAT THIS DOES:
@@ -259,11 +330,14 @@ func (a *MT5Account) IsConnected() bool {
 //   MT5 Terminal can drop connection (timeout, restart, network issues).
 //   This mechanism makes the API resilient to network failures.
 //
-// RETRY LOGIC:
+// RETRY LOGIC (a.RetryPolicy, DefaultRetryPolicy when zero):
 //   - Initial delay: 500ms
 //   - Max delay: 5s
 //   - Exponential backoff with jitter
+//   - Unlimited retries until ctx is done (MaxAttempts caps them)
 //   - Retries on: Unavailable, DeadlineExceeded, TERMINAL_INSTANCE_NOT_FOUND
+//   - Each retry is logged at Debug and counted (RetryStats); every
+//     RetryWarnEvery retries one summarized Warn is logged
 func ExecuteWithReconnect[T any](
 	a *MT5Account,
 	ctx context.Context,
@@ -275,27 +349,33 @@ func ExecuteWithReconnect[T any](
 		ctx = context.Background()
 	}
 
-	const (
-		initialDelay = 500 * time.Millisecond
-		maxDelay     = 5 * time.Second
-	)
-	delay := initialDelay
+	policy := a.RetryPolicy.withDefaults(DefaultRetryPolicy)
+	retries := 0
+
+	// nextDelay returns the wait before the next retry, or an error once MaxAttempts is used up.
+	nextDelay := func(cause error) (time.Duration, error) {
+		retries++
+		if policy.Exhausted(retries) {
+			a.retries.exhausted.Add(1)
+			return 0, errRetriesExhausted(policy.MaxAttempts, cause)
+		}
+		return policy.Delay(retries), nil
+	}
 
 	for {
 		headers := a.getHeaders()
 
 		res, err := grpcCall(headers)
 		if err != nil {
+			a.conn.observeError(err)
 			if s, ok := status.FromError(err); ok && (s.Code() == codes.Unavailable || s.Code() == codes.DeadlineExceeded) {
-				log.Printf("[grpc-retry] code=%s msg=%q next_delay=%s", s.Code(), s.Message(), delay)
-				j := time.Duration(rand.Int63n(int64(delay/2))) - delay/4
-				wait := delay + j
+				delay, exhausted := nextDelay(err)
+				if exhausted != nil {
+					return zeroT, exhausted
+				}
+				a.logRetry(retryTransport, s.Code().String(), s.Message(), delay)
 				select {
-				case <-time.After(wait):
-					delay *= 2
-					if delay > maxDelay {
-						delay = maxDelay
-					}
+				case <-time.After(delay):
 					continue
 				case <-ctx.Done():
 					return zeroT, ctx.Err()
@@ -311,15 +391,13 @@ func ExecuteWithReconnect[T any](
 		if apiErr != nil && apiErr.GetErrorCode() != "" {
 			code := apiErr.GetErrorCode()
 			if code == "TERMINAL_INSTANCE_NOT_FOUND" || code == "TERMINAL_REGISTRY_TERMINAL_NOT_FOUND" {
-				log.Printf("[api-retry] code=%s next_delay=%s", code, delay)
-				j := time.Duration(rand.Int63n(int64(delay/2))) - delay/4
-				wait := delay + j
+				delay, exhausted := nextDelay(fmt.Errorf("API error (code=%s)", code))
+				if exhausted != nil {
+					return zeroT, exhausted
+				}
+				a.logRetry(retryAPI, code, "", delay)
 				select {
-				case <-time.After(wait):
-					delay *= 2
-					if delay > maxDelay {
-						delay = maxDelay
-					}
+				case <-time.After(delay):
 					continue
 				case <-ctx.Done():
 					return zeroT, ctx.Err()
@@ -347,18 +425,29 @@ func ExecuteWithReconnect[T any](
 //   2. Start goroutine that continuously receives messages
 //   3. Send data to dataChan, errors to errChan
 //   4. If stream error (TERMINAL_INSTANCE_NOT_FOUND, Unavailable) → restart stream
-//   5. Apply exponential backoff between retries
+//   5. Apply StreamOptions.Retry backoff between retries
 //   6. Close channels when context cancelled or stream ends
 //
 // WHY THIS IS NEEDED:
 //   Streaming connections can break due to network issues or MT5 restart.
 //   This mechanism ensures continuous data flow by auto-restarting streams.
 //
-// RETRY LOGIC:
-//   - Initial delay: 500ms
-//   - Max delay: 5s
-//   - Exponential backoff with jitter
-//   - Infinite retries until context cancelled
+// RETRY LOGIC (StreamOptions.Retry, DefaultStreamRetryPolicy when zero):
+//   - Fixed 500ms delay with ±50% jitter
+//   - Infinite retries until context cancelled (MaxAttempts caps consecutive
+//     failed attempts; the count resets once an attempt delivers data)
+//   - StreamOptions.OnStreamReconnect is called before every reconnect
+//
+// LIFECYCLE:
+//   Every stream is registered in a.Streams, so it shows up in ActiveStreams()
+//   and is cancelled and drained by Close() / Disconnect().
+//
+// CONTEXTS:
+//   The subscription lives as long as ctx (adjusted by a.StreamOptions). Each
+//   attempt runs on its own child context that is cancelled when the attempt
+//   ends, and only the stream open is bounded by StreamOptions.DialTimeout.
+//   Set StreamOptions.DetachDeadline when ctx carries a per-call deadline that
+//   must not end the subscription.
 func ExecuteStreamWithReconnect[TRequest any, TReply any, TData any](
 	ctx context.Context,
 	a *MT5Account,
@@ -367,6 +456,21 @@ func ExecuteStreamWithReconnect[TRequest any, TReply any, TData any](
 	getError func(TReply) mrpcError,
 	getData func(TReply) (TData, bool),
 	newReply func() TReply,
+) (<-chan TData, <-chan error) {
+	return ExecuteStreamWithOptions(ctx, a, a.StreamOptions, request, streamInvoker, getError, getData, newReply)
+}
+
+// ExecuteStreamWithOptions is ExecuteStreamWithReconnect with explicit
+// StreamOptions instead of the account defaults.
+func ExecuteStreamWithOptions[TRequest any, TReply any, TData any](
+	ctx context.Context,
+	a *MT5Account,
+	opts StreamOptions,
+	request TRequest,
+	streamInvoker func(TRequest, metadata.MD, context.Context) (grpc.ClientStream, error),
+	getError func(TReply) mrpcError,
+	getData func(TReply) (TData, bool),
+	newReply func() TReply,
 ) (<-chan TData, <-chan error) {
 	dataCh := make(chan TData)
 	errCh := make(chan error, 1)
@@ -375,87 +479,145 @@ func ExecuteStreamWithReconnect[TRequest any, TReply any, TData any](
 		ctx = context.Background()
 	}
 
-	go func() {
-		defer close(dataCh)
-		defer close(errCh)
+	ctx, cancelSub := subscriptionContext(ctx, opts)
 
-		for {
-			reconnectRequired := false
-			headers := a.getHeaders()
-
-			stream, err := streamInvoker(request, headers, ctx)
-			if err != nil {
-				if s, ok := status.FromError(err); ok && s.Code() == codes.Unavailable {
-					select {
-					case <-time.After(500*time.Millisecond + time.Duration(rand.Intn(501)-250)*time.Millisecond):
-						continue
-					case <-ctx.Done():
-						errCh <- ctx.Err()
-						return
-					}
-				}
-				errCh <- err
-				return
+	name := streamName(fmt.Sprintf("%T", request))
+	var tracked *managedStream
+	if a.Streams != nil {
+		ctx, tracked = a.Streams.register(ctx, name)
+	}
+
+	policy := opts.Retry.withDefaults(DefaultStreamRetryPolicy)
+	var (
+		failures int    // Consecutive attempts without data
+		total    uint64 // All reconnects of this subscription
+		received bool   // Current attempt delivered data
+		cause    error  // Why the current attempt ended
+	)
+
+	// backoff waits before the next attempt; false if the subscription ended.
+	backoff := func() bool {
+		if received {
+			failures = 0
+		}
+		failures++
+		if policy.Exhausted(failures) {
+			if cause == nil {
+				cause = errors.New("stream closed by server")
 			}
+			errCh <- errRetriesExhausted(policy.MaxAttempts, cause)
+			return false
+		}
+		total++
+		delay := policy.Delay(failures)
+		a.logStreamRetry(name, failures, cause, delay)
+		if opts.OnStreamReconnect != nil {
+			opts.OnStreamReconnect(StreamReconnect{Stream: name, Attempt: failures, Total: total, Delay: delay, Err: cause})
+		}
+		select {
+		case <-time.After(delay):
+			return true
+		case <-ctx.Done():
+			errCh <- ctx.Err()
+			return false
+		}
+	}
 
-			for {
-				reply := newReply()
+	// attempt runs one stream until it ends. Returns true if a reconnect is required.
+	attempt := func() bool {
+		received, cause = false, nil
+		attemptCtx, cancelAttempt := context.WithCancel(ctx)
+		defer cancelAttempt()
 
-				recvErr := stream.RecvMsg(reply)
-				if recvErr != nil {
-					if s, ok := status.FromError(recvErr); ok && s.Code() == codes.Unavailable {
-						reconnectRequired = true
-						break
-					}
-					if errors.Is(recvErr, io.EOF) {
-						return
-					}
-					if errors.Is(recvErr, context.Canceled) || errors.Is(recvErr, context.DeadlineExceeded) {
-						errCh <- recvErr
-						return
-					}
-					errCh <- recvErr
-					return
-				}
+		var dialTimer *time.Timer
+		if opts.DialTimeout > 0 {
+			dialTimer = time.AfterFunc(opts.DialTimeout, cancelAttempt)
+		}
+		stream, err := streamInvoker(request, a.getHeaders(), attemptCtx)
+		dialTimedOut := dialTimer != nil && !dialTimer.Stop()
 
-				apiErr := getError(reply)
-				if apiErr != nil && apiErr.GetErrorCode() != "" {
-					code := apiErr.GetErrorCode()
-					if code == "TERMINAL_INSTANCE_NOT_FOUND" || code == "TERMINAL_REGISTRY_TERMINAL_NOT_FOUND" {
-						reconnectRequired = true
-						break
-					}
-					// Convert mrpcError to *pb.Error and wrap in ApiError
-					if pbErr, ok := apiErr.(*pb.Error); ok {
-						errCh <- mt5errors.NewApiError(pbErr)
-					} else {
-						errCh <- fmt.Errorf("API error: unknown error type")
-					}
-					return
+		if err != nil || dialTimedOut {
+			if ctx.Err() == nil && dialTimedOut {
+				cause = fmt.Errorf("stream open exceeded %s", opts.DialTimeout)
+				return true
+			}
+			if s, ok := status.FromError(err); ok && s.Code() == codes.Unavailable {
+				a.conn.observeError(err)
+				cause = err
+				return true
+			}
+			if err == nil {
+				err = ctx.Err()
+			}
+			errCh <- err
+			return false
+		}
+
+		for {
+			reply := newReply()
+
+			recvErr := stream.RecvMsg(reply)
+			if recvErr != nil {
+				if s, ok := status.FromError(recvErr); ok && s.Code() == codes.Unavailable {
+					a.conn.observeError(recvErr)
+					cause = recvErr
+					return true
+				}
+				if errors.Is(recvErr, io.EOF) {
+					return false
 				}
+				errCh <- recvErr
+				return false
+			}
 
-				if d, ok := getData(reply); ok {
-					select {
-					case dataCh <- d:
-					case <-ctx.Done():
-						errCh <- ctx.Err()
-						return
-					}
+			apiErr := getError(reply)
+			if apiErr != nil && apiErr.GetErrorCode() != "" {
+				code := apiErr.GetErrorCode()
+				if code == "TERMINAL_INSTANCE_NOT_FOUND" || code == "TERMINAL_REGISTRY_TERMINAL_NOT_FOUND" {
+					cause = fmt.Errorf("API error (code=%s)", code)
+					return true
+				}
+				// Convert mrpcError to *pb.Error and wrap in ApiError
+				if pbErr, ok := apiErr.(*pb.Error); ok {
+					errCh <- mt5errors.NewApiError(pbErr)
+				} else {
+					errCh <- fmt.Errorf("API error: unknown error type")
 				}
+				return false
 			}
 
-			if reconnectRequired {
-				base := 500 * time.Millisecond
-				jitter := time.Duration(rand.Intn(501)-250) * time.Millisecond
+			if d, ok := getData(reply); ok {
 				select {
-				case <-time.After(base + jitter):
-					continue
+				case dataCh <- d:
+					received = true
+					if tracked != nil {
+						tracked.messages.Add(1)
+						tracked.lastMessage.Store(time.Now().UnixNano())
+					}
 				case <-ctx.Done():
 					errCh <- ctx.Err()
-					return
+					return false
 				}
 			}
 		}
+	}
+
+	go func() {
+		defer close(dataCh)
+		defer close(errCh)
+		defer cancelSub()
+		if tracked != nil {
+			defer a.Streams.unregister(tracked)
+		}
+
+		for n := 0; ; n++ {
+			if n > 0 && tracked != nil {
+				tracked.reconnects.Add(1)
+			}
+			if !attempt() || !backoff() {
+				return
+			}
+		}
 	}()
 
 	return dataCh, errCh
@@ -509,6 +671,9 @@ func (a *MT5Account) ConnectEx(ctx context.Context, req *pb.ConnectExRequest) (*
 		return nil, err
 	}
 
+	// Best effort: trade RPCs detect it lazily if this fails
+	_ = a.detectTradeMode(ctx)
+
 	return reply.GetData(), nil
 }
 
@@ -553,6 +718,9 @@ func (a *MT5Account) Connect(ctx context.Context, req *pb.ConnectRequest) (*pb.C
 		return nil, err
 	}
 
+	// Best effort: trade RPCs detect it lazily if this fails
+	_ = a.detectTradeMode(ctx)
+
 	return reply.GetData(), nil
 }
 
@@ -596,6 +764,9 @@ func (a *MT5Account) ConnectProxy(ctx context.Context, req *pb.ConnectProxyReque
 		return nil, err
 	}
 
+	// Best effort: trade RPCs detect it lazily if this fails
+	_ = a.detectTradeMode(ctx)
+
 	return reply.GetData(), nil
 }
 
@@ -627,7 +798,12 @@ func (a *MT5Account) CheckConnect(ctx context.Context, req *pb.CheckConnectReque
 
 	grpcCall := func(headers metadata.MD) (*pb.CheckConnectReply, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
-		return a.ConnectionClient.CheckConnect(c, req)
+		start := time.Now()
+		reply, err := a.ConnectionClient.CheckConnect(c, req)
+		if err == nil {
+			a.conn.observeRTT(time.Since(start))
+		}
+		return reply, err
 	}
 
 	errorSelector := func(reply *pb.CheckConnectReply) mrpcError {
@@ -682,6 +858,9 @@ func (a *MT5Account) Disconnect(ctx context.Context, req *pb.DisconnectRequest)
 		return nil, err
 	}
 
+	// Session is gone - drain every subscription so no reader outlives it
+	a.Streams.CloseAll(3 * time.Second)
+
 	return reply.GetData(), nil
 }
 
@@ -954,7 +1133,7 @@ func (a *MT5Account) SymbolsTotal(ctx context.Context, req *pb.SymbolsTotalReque
 
 	grpcCall := func(headers metadata.MD) (*pb.SymbolsTotalReply, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
-		return a.MarketInfoClient.SymbolsTotal(c, req)
+		return a.marketInfo().SymbolsTotal(c, req)
 	}
 
 	errorSelector := func(reply *pb.SymbolsTotalReply) mrpcError {
@@ -997,7 +1176,7 @@ func (a *MT5Account) SymbolExist(ctx context.Context, req *pb.SymbolExistRequest
 
 	grpcCall := func(headers metadata.MD) (*pb.SymbolExistReply, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
-		return a.MarketInfoClient.SymbolExist(c, req)
+		return a.marketInfo().SymbolExist(c, req)
 	}
 
 	errorSelector := func(reply *pb.SymbolExistReply) mrpcError {
@@ -1040,7 +1219,7 @@ func (a *MT5Account) SymbolName(ctx context.Context, req *pb.SymbolNameRequest)
 
 	grpcCall := func(headers metadata.MD) (*pb.SymbolNameReply, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
-		return a.MarketInfoClient.SymbolName(c, req)
+		return a.marketInfo().SymbolName(c, req)
 	}
 
 	errorSelector := func(reply *pb.SymbolNameReply) mrpcError {
@@ -1083,7 +1262,7 @@ func (a *MT5Account) SymbolSelect(ctx context.Context, req *pb.SymbolSelectReque
 
 	grpcCall := func(headers metadata.MD) (*pb.SymbolSelectReply, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
-		return a.MarketInfoClient.SymbolSelect(c, req)
+		return a.marketInfo().SymbolSelect(c, req)
 	}
 
 	errorSelector := func(reply *pb.SymbolSelectReply) mrpcError {
@@ -1126,7 +1305,7 @@ func (a *MT5Account) SymbolIsSynchronized(ctx context.Context, req *pb.SymbolIsS
 
 	grpcCall := func(headers metadata.MD) (*pb.SymbolIsSynchronizedReply, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
-		return a.MarketInfoClient.SymbolIsSynchronized(c, req)
+		return a.marketInfo().SymbolIsSynchronized(c, req)
 	}
 
 	errorSelector := func(reply *pb.SymbolIsSynchronizedReply) mrpcError {
@@ -1169,7 +1348,7 @@ func (a *MT5Account) SymbolInfoDouble(ctx context.Context, req *pb.SymbolInfoDou
 
 	grpcCall := func(headers metadata.MD) (*pb.SymbolInfoDoubleReply, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
-		return a.MarketInfoClient.SymbolInfoDouble(c, req)
+		return a.marketInfo().SymbolInfoDouble(c, req)
 	}
 
 	errorSelector := func(reply *pb.SymbolInfoDoubleReply) mrpcError {
@@ -1212,7 +1391,7 @@ func (a *MT5Account) SymbolInfoInteger(ctx context.Context, req *pb.SymbolInfoIn
 
 	grpcCall := func(headers metadata.MD) (*pb.SymbolInfoIntegerReply, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
-		return a.MarketInfoClient.SymbolInfoInteger(c, req)
+		return a.marketInfo().SymbolInfoInteger(c, req)
 	}
 
 	errorSelector := func(reply *pb.SymbolInfoIntegerReply) mrpcError {
@@ -1255,7 +1434,7 @@ func (a *MT5Account) SymbolInfoString(ctx context.Context, req *pb.SymbolInfoStr
 
 	grpcCall := func(headers metadata.MD) (*pb.SymbolInfoStringReply, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
-		return a.MarketInfoClient.SymbolInfoString(c, req)
+		return a.marketInfo().SymbolInfoString(c, req)
 	}
 
 	errorSelector := func(reply *pb.SymbolInfoStringReply) mrpcError {
@@ -1298,7 +1477,7 @@ func (a *MT5Account) SymbolInfoMarginRate(ctx context.Context, req *pb.SymbolInf
 
 	grpcCall := func(headers metadata.MD) (*pb.SymbolInfoMarginRateReply, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
-		return a.MarketInfoClient.SymbolInfoMarginRate(c, req)
+		return a.marketInfo().SymbolInfoMarginRate(c, req)
 	}
 
 	errorSelector := func(reply *pb.SymbolInfoMarginRateReply) mrpcError {
@@ -1341,7 +1520,7 @@ func (a *MT5Account) SymbolInfoTick(ctx context.Context, req *pb.SymbolInfoTickR
 
 	grpcCall := func(headers metadata.MD) (*pb.SymbolInfoTickRequestReply, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
-		return a.MarketInfoClient.SymbolInfoTick(c, req)
+		return a.marketInfo().SymbolInfoTick(c, req)
 	}
 
 	errorSelector := func(reply *pb.SymbolInfoTickRequestReply) mrpcError {
@@ -1384,7 +1563,7 @@ func (a *MT5Account) SymbolInfoSessionQuote(ctx context.Context, req *pb.SymbolI
 
 	grpcCall := func(headers metadata.MD) (*pb.SymbolInfoSessionQuoteReply, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
-		return a.MarketInfoClient.SymbolInfoSessionQuote(c, req)
+		return a.marketInfo().SymbolInfoSessionQuote(c, req)
 	}
 
 	errorSelector := func(reply *pb.SymbolInfoSessionQuoteReply) mrpcError {
@@ -1427,7 +1606,7 @@ func (a *MT5Account) SymbolInfoSessionTrade(ctx context.Context, req *pb.SymbolI
 
 	grpcCall := func(headers metadata.MD) (*pb.SymbolInfoSessionTradeReply, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
-		return a.MarketInfoClient.SymbolInfoSessionTrade(c, req)
+		return a.marketInfo().SymbolInfoSessionTrade(c, req)
 	}
 
 	errorSelector := func(reply *pb.SymbolInfoSessionTradeReply) mrpcError {
@@ -1470,9 +1649,15 @@ func (a *MT5Account) SymbolParamsMany(ctx context.Context, req *pb.SymbolParamsM
 		defer cancel()
 	}
 
+	release, err := a.backgroundLane(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	grpcCall := func(headers metadata.MD) (*pb.SymbolParamsManyReply, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
-		return a.AccountClient.SymbolParamsMany(c, req)
+		return a.historyClient().SymbolParamsMany(c, req)
 	}
 
 	errorSelector := func(reply *pb.SymbolParamsManyReply) mrpcError {
@@ -1647,9 +1832,15 @@ func (a *MT5Account) OrderHistory(ctx context.Context, req *pb.OrderHistoryReque
 		defer cancel()
 	}
 
+	release, err := a.backgroundLane(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	grpcCall := func(headers metadata.MD) (*pb.OrderHistoryReply, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
-		return a.AccountClient.OrderHistory(c, req)
+		return a.historyClient().OrderHistory(c, req)
 	}
 
 	errorSelector := func(reply *pb.OrderHistoryReply) mrpcError {
@@ -1692,9 +1883,15 @@ func (a *MT5Account) PositionsHistory(ctx context.Context, req *pb.PositionsHist
 		defer cancel()
 	}
 
+	release, err := a.backgroundLane(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	grpcCall := func(headers metadata.MD) (*pb.PositionsHistoryReply, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
-		return a.AccountClient.PositionsHistory(c, req)
+		return a.historyClient().PositionsHistory(c, req)
 	}
 
 	errorSelector := func(reply *pb.PositionsHistoryReply) mrpcError {
@@ -1743,7 +1940,7 @@ func (a *MT5Account) MarketBookAdd(ctx context.Context, req *pb.MarketBookAddReq
 
 	grpcCall := func(headers metadata.MD) (*pb.MarketBookAddReply, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
-		return a.MarketInfoClient.MarketBookAdd(c, req)
+		return a.marketInfo().MarketBookAdd(c, req)
 	}
 
 	errorSelector := func(reply *pb.MarketBookAddReply) mrpcError {
@@ -1786,7 +1983,7 @@ func (a *MT5Account) MarketBookRelease(ctx context.Context, req *pb.MarketBookRe
 
 	grpcCall := func(headers metadata.MD) (*pb.MarketBookReleaseReply, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
-		return a.MarketInfoClient.MarketBookRelease(c, req)
+		return a.marketInfo().MarketBookRelease(c, req)
 	}
 
 	errorSelector := func(reply *pb.MarketBookReleaseReply) mrpcError {
@@ -1831,7 +2028,7 @@ func (a *MT5Account) MarketBookGet(ctx context.Context, req *pb.MarketBookGetReq
 
 	grpcCall := func(headers metadata.MD) (*pb.MarketBookGetReply, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
-		return a.MarketInfoClient.MarketBookGet(c, req)
+		return a.marketInfo().MarketBookGet(c, req)
 	}
 
 	errorSelector := func(reply *pb.MarketBookGetReply) mrpcError {
@@ -1877,6 +2074,11 @@ func (a *MT5Account) OrderSend(ctx context.Context, req *pb.OrderSendRequest) (*
 		ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
 		defer cancel()
 	}
+	if err := a.checkOrderSend(ctx, req, true); err != nil {
+		return nil, err
+	}
+
+	defer a.tradeLane()()
 
 	grpcCall := func(headers metadata.MD) (*pb.OrderSendReply, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
@@ -1921,6 +2123,11 @@ func (a *MT5Account) OrderModify(ctx context.Context, req *pb.OrderModifyRequest
 		ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
 		defer cancel()
 	}
+	if err := a.checkTradeRPC(ctx, "OrderModify"); err != nil {
+		return nil, err
+	}
+
+	defer a.tradeLane()()
 
 	grpcCall := func(headers metadata.MD) (*pb.OrderModifyReply, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
@@ -1964,6 +2171,11 @@ func (a *MT5Account) OrderClose(ctx context.Context, req *pb.OrderCloseRequest)
 		ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
 		defer cancel()
 	}
+	if err := a.checkTradeRPC(ctx, "OrderClose"); err != nil {
+		return nil, err
+	}
+
+	defer a.tradeLane()()
 
 	grpcCall := func(headers metadata.MD) (*pb.OrderCloseReply, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
@@ -2134,7 +2346,7 @@ func (a *MT5Account) OrderCalcProfit(ctx context.Context, req *pb.OrderCalcProfi
 func (a *MT5Account) OnSymbolTick(ctx context.Context, req *pb.OnSymbolTickRequest) (<-chan *pb.OnSymbolTickData, <-chan error) {
 	streamInvoker := func(request *pb.OnSymbolTickRequest, headers metadata.MD, ctx context.Context) (grpc.ClientStream, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
-		return a.SubscriptionClient.OnSymbolTick(c, request)
+		return a.tickClient().OnSymbolTick(c, request)
 	}
 
 	getError := func(reply *pb.OnSymbolTickReply) mrpcError {
@@ -2276,6 +2488,13 @@ func (a *MT5Account) OnPositionsAndPendingOrdersTickets(ctx context.Context, req
 //   - Data channel: receives OnTradeTransactionData with MqlTradeTransaction containing Type, OrderState, DealTicket, OrderTicket, Symbol, Price, Volume
 //   - Error channel: receives errors if stream fails (both channels closed on context cancellation)
 func (a *MT5Account) OnTradeTransaction(ctx context.Context, req *pb.OnTradeTransactionRequest) (<-chan *pb.OnTradeTransactionData, <-chan error) {
+	return a.OnTradeTransactionWithOptions(ctx, req, a.StreamOptions)
+}
+
+// OnTradeTransactionWithOptions is OnTradeTransaction with explicit
+// StreamOptions instead of a.StreamOptions, e.g. to learn about reconnects of
+// this one subscription through OnStreamReconnect.
+func (a *MT5Account) OnTradeTransactionWithOptions(ctx context.Context, req *pb.OnTradeTransactionRequest, opts StreamOptions) (<-chan *pb.OnTradeTransactionData, <-chan error) {
 	streamInvoker := func(request *pb.OnTradeTransactionRequest, headers metadata.MD, ctx context.Context) (grpc.ClientStream, error) {
 		c := metadata.NewOutgoingContext(ctx, headers)
 		return a.SubscriptionClient.OnTradeTransaction(c, request)
@@ -2296,6 +2515,6 @@ func (a *MT5Account) OnTradeTransaction(ctx context.Context, req *pb.OnTradeTran
 		return &pb.OnTradeTransactionReply{}
 	}
 
-	return ExecuteStreamWithReconnect(ctx, a, req, streamInvoker, getError, getData, newReply)
+	return ExecuteStreamWithOptions(ctx, a, opts, req, streamInvoker, getError, getData, newReply)
 }
 // #endregion
@@ -0,0 +1,81 @@
+package mt5
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/keepalive"
+)
+
+// CompressionGzip compresses every request and asks the server to compress
+// replies (AccountOptions.Compression).
+const CompressionGzip = gzip.Name
+
+// AccountOptions tunes the gRPC connection of an MT5Account at dial time
+// (see NewMT5AccountWithOptions). The zero value keeps the gRPC defaults and
+// pings every 20s with a 5s timeout.
+//
+// Large replies - SymbolParamsMany for every symbol of a broker, order or
+// position history with thousands of rows - can exceed the default 4 MB
+// receive limit and fail with ResourceExhausted. Raise MaxRecvMsgSize for
+// those, and enable compression on slow or metered links: symbol and history
+// replies are highly repetitive and usually shrink several times.
+//
+// Keepalive detects dead links (NAT timeouts, silent proxies) between calls.
+// Shorten it behind aggressive NATs; lengthen it if the server answers with
+// GOAWAY "too_many_pings".
+type AccountOptions struct {
+	Compression    string // Call compressor: "" = none, CompressionGzip = gzip
+	MaxRecvMsgSize int    // Max reply size in bytes (0 = gRPC default, 4 MB)
+	MaxSendMsgSize int    // Max request size in bytes (0 = gRPC default, unlimited)
+
+	KeepaliveTime    time.Duration // Ping after this long without activity (0 = DefaultKeepaliveTime, gRPC minimum 10s)
+	KeepaliveTimeout time.Duration // Drop the transport if a ping is not answered within this (0 = DefaultKeepaliveTimeout)
+
+	DialOptions []grpc.DialOption // Extra dial options, applied after the built-in ones
+}
+
+// keepalive returns the keepalive parameters, defaults filled in.
+func (o AccountOptions) keepaliveParams() keepalive.ClientParameters {
+	kp := keepalive.ClientParameters{
+		Time:                o.KeepaliveTime,
+		Timeout:             o.KeepaliveTimeout,
+		PermitWithoutStream: true,
+	}
+	if kp.Time <= 0 {
+		kp.Time = DefaultKeepaliveTime
+	}
+	if kp.Timeout <= 0 {
+		kp.Timeout = DefaultKeepaliveTimeout
+	}
+	return kp
+}
+
+// dialOptions converts the options into gRPC dial options.
+func (o AccountOptions) dialOptions() ([]grpc.DialOption, error) {
+	var callOpts []grpc.CallOption
+	switch o.Compression {
+	case "":
+	case CompressionGzip:
+		callOpts = append(callOpts, grpc.UseCompressor(gzip.Name))
+	default:
+		return nil, fmt.Errorf("unsupported compression %q (use %q)", o.Compression, CompressionGzip)
+	}
+	if o.MaxRecvMsgSize < 0 || o.MaxSendMsgSize < 0 {
+		return nil, fmt.Errorf("message size limits must not be negative")
+	}
+	if o.MaxRecvMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(o.MaxRecvMsgSize))
+	}
+	if o.MaxSendMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallSendMsgSize(o.MaxSendMsgSize))
+	}
+
+	var opts []grpc.DialOption
+	if len(callOpts) > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(callOpts...))
+	}
+	return append(opts, o.DialOptions...), nil
+}
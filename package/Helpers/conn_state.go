@@ -0,0 +1,168 @@
+package mt5
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+)
+
+// Keepalive defaults used when AccountOptions leaves them at zero.
+const (
+	DefaultKeepaliveTime    = 20 * time.Second
+	DefaultKeepaliveTimeout = 5 * time.Second
+)
+
+// maxConnTransitions is how many state transitions ConnState keeps.
+const maxConnTransitions = 32
+
+// ConnTransition is one change of the gRPC connectivity state.
+type ConnTransition struct {
+	From connectivity.State
+	To   connectivity.State
+	At   time.Time
+}
+
+// ConnState is a snapshot of the connection health of an MT5Account.
+type ConnState struct {
+	State       connectivity.State // Current gRPC connectivity state
+	Since       time.Time          // When the current state was entered
+	Transitions []ConnTransition   // Recent transitions, oldest first (up to 32)
+
+	Drops             uint64        // READY → anything else (transport lost)
+	KeepaliveFailures uint64        // Drops caused by an unanswered keepalive ping (as seen by RPCs)
+	LastRTT           time.Duration // Round trip of the last successful CheckConnect (0 = none yet)
+	LastRTTAt         time.Time     // When LastRTT was measured
+
+	Keepalive keepalive.ClientParameters // Keepalive parameters in use
+}
+
+// IsReady reports whether the transport is up.
+func (c ConnState) IsReady() bool {
+	return c.State == connectivity.Ready
+}
+
+// connMonitor records connectivity transitions and connection metrics.
+type connMonitor struct {
+	mu          sync.Mutex
+	state       connectivity.State
+	since       time.Time
+	transitions []ConnTransition
+	readyEpoch  uint64 // Times the transport became READY
+	drops       uint64
+	kaFailures  uint64
+	kaEpoch     uint64 // readyEpoch of the last counted keepalive failure
+	rtt         time.Duration
+	rttAt       time.Time
+	keepalive   keepalive.ClientParameters
+}
+
+func newConnMonitor(kp keepalive.ClientParameters) *connMonitor {
+	return &connMonitor{state: connectivity.Idle, since: time.Now(), keepalive: kp}
+}
+
+// watch follows the state of conn until it is shut down.
+func (m *connMonitor) watch(conn *grpc.ClientConn) {
+	for {
+		s := conn.GetState()
+		m.record(s)
+		if s == connectivity.Shutdown {
+			return
+		}
+		if !conn.WaitForStateChange(context.Background(), s) {
+			return
+		}
+	}
+}
+
+// record stores a newly observed state.
+func (m *connMonitor) record(s connectivity.State) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s == m.state {
+		return
+	}
+	now := time.Now()
+	m.transitions = append(m.transitions, ConnTransition{From: m.state, To: s, At: now})
+	if len(m.transitions) > maxConnTransitions {
+		m.transitions = m.transitions[len(m.transitions)-maxConnTransitions:]
+	}
+	if m.state == connectivity.Ready && s != connectivity.Ready {
+		m.drops++
+	}
+	if s == connectivity.Ready {
+		m.readyEpoch++
+	}
+	m.state, m.since = s, now
+}
+
+// observeError counts a keepalive failure reported by an RPC. All calls in
+// flight fail with the same error, so it is counted once per READY period.
+func (m *connMonitor) observeError(err error) {
+	if m == nil || !isKeepaliveFailure(err) {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.kaFailures > 0 && m.kaEpoch == m.readyEpoch {
+		return
+	}
+	m.kaFailures++
+	m.kaEpoch = m.readyEpoch
+}
+
+// observeRTT stores a measured round trip.
+func (m *connMonitor) observeRTT(rtt time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rtt, m.rttAt = rtt, time.Now()
+}
+
+func (m *connMonitor) snapshot() ConnState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return ConnState{
+		State:             m.state,
+		Since:             m.since,
+		Transitions:       append([]ConnTransition(nil), m.transitions...),
+		Drops:             m.drops,
+		KeepaliveFailures: m.kaFailures,
+		LastRTT:           m.rtt,
+		LastRTTAt:         m.rttAt,
+		Keepalive:         m.keepalive,
+	}
+}
+
+// isKeepaliveFailure reports whether err comes from a transport closed by an
+// unanswered keepalive ping.
+func isKeepaliveFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	s, ok := status.FromError(err)
+	return ok && s.Code() == codes.Unavailable && strings.Contains(s.Message(), "keepalive ping failed")
+}
+
+// ConnState returns the connectivity state, recent transitions, drop and
+// keepalive failure counts, and the last measured round trip.
+//
+// LastRTT is measured by CheckConnect (the call behind Sugar's Ping), so call
+// it periodically to keep the value fresh. Keepalive failures are only seen
+// when an RPC or stream is in flight as the transport drops; Drops counts
+// every loss of the transport.
+func (a *MT5Account) ConnState() ConnState {
+	if a == nil || a.conn == nil {
+		return ConnState{State: connectivity.Shutdown}
+	}
+	return a.conn.snapshot()
+}
@@ -0,0 +1,90 @@
+package mt5
+
+import (
+	"fmt"
+
+	pb "git.mtapi.io/root/mrpc-proto/mt5/libraries/go"
+	"google.golang.org/grpc"
+)
+
+// dataEndpoint is the connection serving market-data RPCs.
+type dataEndpoint struct {
+	server       string
+	conn         *grpc.ClientConn
+	marketInfo   pb.MarketInfoClient
+	account      pb.AccountHelperClient
+	subscription pb.SubscriptionServiceClient
+}
+
+// UseDataEndpoint routes market-data-only RPCs over a separate gRPC
+// connection, so heavy data pulls never queue in front of order submission
+// on the trading connection.
+//
+// Routed: every Symbol* call, MarketBook*, SymbolParamsMany, OrderHistory,
+// PositionsHistory and the OnSymbolTick stream. Connection, account, position
+// and trade RPCs stay on GrpcConn.
+//
+// grpcServer may name a read replica of the gateway; it must serve the same
+// terminal session (calls carry the account's session Id). "" opens a second
+// connection to GrpcServer. Calling it again replaces the previous endpoint.
+func (a *MT5Account) UseDataEndpoint(grpcServer string, opts AccountOptions) error {
+	if grpcServer == "" {
+		grpcServer = a.GrpcServer
+	}
+	conn, _, err := dialServer(grpcServer, opts)
+	if err != nil {
+		return fmt.Errorf("data endpoint: %w", err)
+	}
+	old := a.data.Swap(&dataEndpoint{
+		server:       grpcServer,
+		conn:         conn,
+		marketInfo:   pb.NewMarketInfoClient(conn),
+		account:      pb.NewAccountHelperClient(conn),
+		subscription: pb.NewSubscriptionServiceClient(conn),
+	})
+	if old != nil {
+		old.conn.Close()
+	}
+	return nil
+}
+
+// CloseDataEndpoint closes the data connection; market-data RPCs go back to
+// GrpcConn. No-op without a data endpoint.
+func (a *MT5Account) CloseDataEndpoint() error {
+	if d := a.data.Swap(nil); d != nil {
+		return d.conn.Close()
+	}
+	return nil
+}
+
+// DataEndpoint returns the server market-data RPCs are sent to.
+func (a *MT5Account) DataEndpoint() string {
+	if d := a.data.Load(); d != nil {
+		return d.server
+	}
+	return a.GrpcServer
+}
+
+// marketInfo returns the client for quote, symbol and market book RPCs.
+func (a *MT5Account) marketInfo() pb.MarketInfoClient {
+	if d := a.data.Load(); d != nil {
+		return d.marketInfo
+	}
+	return a.MarketInfoClient
+}
+
+// historyClient returns the client for symbol parameter and history RPCs.
+func (a *MT5Account) historyClient() pb.AccountHelperClient {
+	if d := a.data.Load(); d != nil {
+		return d.account
+	}
+	return a.AccountClient
+}
+
+// tickClient returns the client for the tick stream.
+func (a *MT5Account) tickClient() pb.SubscriptionServiceClient {
+	if d := a.data.Load(); d != nil {
+		return d.subscription
+	}
+	return a.SubscriptionClient
+}
@@ -0,0 +1,224 @@
+package mt5
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	pb "git.mtapi.io/root/mrpc-proto/mt5/libraries/go"
+)
+
+// Explanation translates an MT5 error code into a likely cause and a
+// suggested fix, for logs, CLI output and notifications.
+//
+//	if ex, ok := mt5.ExplainErr(err); ok {
+//	    log.Printf("order failed: %s", ex)
+//	    // TRADE_RETCODE_INVALID_STOPS (10016): SL/TP closer than the symbol's stops level. Fix: ...
+//	}
+type Explanation struct {
+	Code      int32  // Trade return code (10004-10046) or MQL error code (4001+)
+	Name      string // Code name, e.g. "TRADE_RETCODE_INVALID_STOPS"
+	Cause     string // What most likely went wrong
+	Fix       string // What to change before sending again ("" if nothing can be done)
+	Retryable bool   // The same request may succeed if simply retried
+}
+
+// String formats the explanation on one line: "NAME (code): cause. Fix: fix".
+func (e Explanation) String() string {
+	s := e.Name
+	if e.Code != 0 {
+		s += fmt.Sprintf(" (%d)", e.Code)
+	}
+	s += ": " + e.Cause
+	if e.Fix != "" {
+		s += ". Fix: " + e.Fix
+	}
+	return s
+}
+
+// WithStopsLevel fills in the symbol's SYMBOL_TRADE_STOPS_LEVEL and
+// SYMBOL_TRADE_FREEZE_LEVEL (points) for codes caused by them, e.g.
+// INVALID_STOPS becomes "SL/TP closer than stops level 30 points".
+// Other explanations are returned unchanged.
+func (e Explanation) WithStopsLevel(stopsLevel, freezeLevel int64) Explanation {
+	switch uint32(e.Code) {
+	case TradeRetCodeInvalidStops:
+		if stopsLevel > 0 {
+			e.Cause = fmt.Sprintf("SL/TP closer than stops level %d points", stopsLevel)
+			e.Fix = fmt.Sprintf("place SL/TP at least %d points from the current price", stopsLevel)
+		}
+	case TradeRetCodeInvalidPrice:
+		if stopsLevel > 0 {
+			e.Cause = fmt.Sprintf("price is stale or a pending price is closer than stops level %d points", stopsLevel)
+			e.Fix = fmt.Sprintf("use the current Bid/Ask for market orders, keep pending prices at least %d points away", stopsLevel)
+		}
+	case TradeRetCodeFrozen:
+		if freezeLevel > 0 {
+			e.Cause = fmt.Sprintf("order or SL/TP is within freeze level %d points of the market", freezeLevel)
+		}
+	}
+	return e
+}
+
+// explanation is one entry of the explain tables.
+type explanation struct {
+	cause string
+	fix   string
+}
+
+// retCodeExplanations holds causes and fixes for trade return codes.
+var retCodeExplanations = map[uint32]explanation{
+	TradeRetCodeDone:        {"request completed", ""},
+	TradeRetCodeDonePartial: {"only part of the volume was filled (not enough liquidity)", "send the remaining volume again or use FOK/IOC filling"},
+	TradeRetCodePlaced:      {"pending order placed, not yet executed", ""},
+
+	TradeRetCodeRequote:      {"price moved before execution (requote)", "retry with the current price or allow more slippage (deviation)"},
+	TradeRetCodePriceChanged: {"price moved before execution", "retry with the current price or allow more slippage (deviation)"},
+
+	TradeRetCodeReject:             {"request rejected by the dealer", "check the broker comment; retry later or contact the broker"},
+	TradeRetCodeCancel:             {"request canceled by the trader", ""},
+	TradeRetCodeInvalidRequest:     {"request fields are inconsistent (wrong action, type, ticket or missing field)", "check order type, symbol, ticket and price fields"},
+	TradeRetCodeInvalidVolume:      {"volume outside SYMBOL_VOLUME_MIN/MAX or not a multiple of SYMBOL_VOLUME_STEP", "round the volume to the symbol's volume step within min/max"},
+	TradeRetCodeInvalidPrice:       {"price is stale or a pending price is too close to the market", "use the current Bid/Ask for market orders, keep pending prices beyond the stops level"},
+	TradeRetCodeInvalidStops:       {"SL/TP closer than the symbol's stops level, or on the wrong side of the price", "place SL/TP beyond SYMBOL_TRADE_STOPS_LEVEL points (below entry for BUY SL, above for SELL SL)"},
+	TradeRetCodeInvalidExpiration:  {"expiration type or time not allowed for this symbol", "check SYMBOL_EXPIRATION_MODE; use GTC or a future expiration time"},
+	TradeRetCodeInvalidFill:        {"filling mode not supported by the symbol", "use a filling mode allowed by SYMBOL_FILLING_MODE (FOK, IOC or RETURN)"},
+	TradeRetCodeInvalidOrder:       {"order type not allowed for this symbol", "check SYMBOL_ORDER_MODE for allowed order types"},
+	TradeRetCodeInvalidCloseVolume: {"close volume exceeds the position volume", "close at most the position's current volume"},
+
+	TradeRetCodeTradeDisabled:    {"trading is disabled for the symbol or account", "check SYMBOL_TRADE_MODE and account trade permissions"},
+	TradeRetCodeMarketClosed:     {"market is closed for this symbol", "wait for the trading session to open (see IsTradingTime)"},
+	TradeRetCodeServerDisablesAt: {"algorithmic trading is disabled by the server", "ask the broker to enable algo trading for the account"},
+	TradeRetCodeClientDisablesAt: {"algorithmic trading is disabled in the terminal", "enable \"Algo Trading\" in the terminal"},
+	TradeRetCodeOnlyReal:         {"operation is allowed only for live accounts", ""},
+	TradeRetCodeLongOnly:         {"only long positions are allowed for this symbol", "open BUY positions only"},
+	TradeRetCodeShortOnly:        {"only short positions are allowed for this symbol", "open SELL positions only"},
+	TradeRetCodeCloseOnly:        {"symbol is in close-only mode", "close existing positions; new positions are refused"},
+	TradeRetCodeFifoClose:        {"positions must be closed in FIFO order", "close the oldest position on the symbol first"},
+	TradeRetCodeHedgeProhibited:  {"opposite positions on the same symbol are not allowed (netting/no hedging)", "close the existing opposite position instead"},
+
+	TradeRetCodeNoMoney:        {"not enough free margin for the volume", "reduce the volume or free margin (see CalculateRequiredMargin)"},
+	TradeRetCodeLimitOrders:    {"account limit of pending orders reached", "delete unused pending orders"},
+	TradeRetCodeLimitVolume:    {"symbol limit of total volume reached (SYMBOL_VOLUME_LIMIT)", "reduce volume or close positions on the symbol"},
+	TradeRetCodeLimitPositions: {"account limit of open positions reached", "close positions before opening new ones"},
+
+	TradeRetCodeError:           {"trade server failed to process the request", "retry; contact the broker if it persists"},
+	TradeRetCodeTimeout:         {"request timed out on the trade server", "check positions/orders before retrying to avoid duplicates"},
+	TradeRetCodeNoQuotes:        {"no quotes for the symbol (market off or feed gap)", "wait for quotes and retry"},
+	TradeRetCodeTooManyRequests: {"too many requests in a short time", "slow down and retry with backoff"},
+	TradeRetCodeLocked:          {"request locked for processing", "retry after a short delay"},
+	TradeRetCodeFrozen:          {"order or SL/TP is within the symbol's freeze level", "wait until the price moves away (SYMBOL_TRADE_FREEZE_LEVEL)"},
+	TradeRetCodeNoConnection:    {"terminal has no connection to the trade server", "wait for the terminal to reconnect and retry"},
+
+	TradeRetCodeOrderChanged:    {"order state changed while the request was processed", "reload the order and retry"},
+	TradeRetCodeNoChanges:       {"new SL/TP or price equal the current values", "skip the modification or change the values"},
+	TradeRetCodePositionClosed:  {"position is already closed", "reload positions; nothing to close"},
+	TradeRetCodeCloseOrderExist: {"a close order for the position already exists", "wait for or delete the existing close order"},
+	TradeRetCodeRejectCancel:    {"pending order activation was rejected and the order canceled", "check margin and symbol restrictions, then place it again"},
+}
+
+// mqlExplanations holds causes and fixes for common MQL error codes.
+var mqlExplanations = map[pb.MqlErrorCode]explanation{
+	pb.MqlErrorCode_ERR_INTERNAL_ERROR:              {"unexpected internal error in the terminal", "retry; restart the terminal if it persists"},
+	pb.MqlErrorCode_ERR_INVALID_PARAMETER:           {"wrong parameter in the request", "check the request fields and property IDs"},
+	pb.MqlErrorCode_ERR_NOT_ENOUGH_MEMORY:           {"terminal ran out of memory", "request less data at once"},
+	pb.MqlErrorCode_ERR_MARKET_UNKNOWN_SYMBOL:       {"symbol does not exist on this server", "check the symbol name and broker suffix (see ResolveSymbol)"},
+	pb.MqlErrorCode_ERR_MARKET_NOT_SELECTED:         {"symbol is not in Market Watch", "select it first (SymbolSelect / EnsureVisible)"},
+	pb.MqlErrorCode_ERR_MARKET_WRONG_PROPERTY:       {"wrong symbol property ID", "check the SymbolInfo property enum"},
+	pb.MqlErrorCode_ERR_MARKET_SELECT_LIMIT:         {"Market Watch symbol limit exceeded", "remove unused symbols from Market Watch"},
+	pb.MqlErrorCode_ERR_HISTORY_NOT_FOUND:           {"requested history is not available", "widen the date range or wait for history to load"},
+	pb.MqlErrorCode_ERR_ACCOUNT_WRONG_PROPERTY:      {"wrong account property ID", "check the AccountInfo property enum"},
+	pb.MqlErrorCode_ERR_TRADE_WRONG_PROPERTY:        {"wrong trade property ID", "check the property enum"},
+	pb.MqlErrorCode_ERR_TRADE_DISABLED:              {"trading by Expert Advisors is disabled", "enable \"Algo Trading\" in the terminal"},
+	pb.MqlErrorCode_ERR_TRADE_POSITION_NOT_FOUND:    {"position not found (already closed or wrong ticket)", "reload positions before acting on a ticket"},
+	pb.MqlErrorCode_ERR_TRADE_ORDER_NOT_FOUND:       {"order not found (filled, canceled or wrong ticket)", "reload orders before acting on a ticket"},
+	pb.MqlErrorCode_ERR_TRADE_DEAL_NOT_FOUND:        {"deal not found", "check the ticket and history range"},
+	pb.MqlErrorCode_ERR_TRADE_SEND_FAILED:           {"trade request could not be sent", "check the terminal connection and retry"},
+	pb.MqlErrorCode_ERR_TRADE_CALC_FAILED:           {"failed to calculate profit or margin", "check symbol, volume and price; the symbol may lack quotes"},
+	pb.MqlErrorCode_ERR_TERMINAL_WRONG_PROPERTY:     {"wrong terminal property ID", "check the property enum"},
+	pb.MqlErrorCode_ERR_NOTIFICATION_WRONG_SETTINGS: {"notification settings are wrong", "check notification settings in the terminal"},
+}
+
+// Explain returns the cause and suggested fix for a trade return code
+// (OrderSendData.ReturnedCode, MqlErrorTradeIntCode). Unknown codes get
+// the GetRetCodeMessage text and no fix.
+func Explain(retCode uint32) Explanation {
+	e := Explanation{
+		Code:      int32(retCode),
+		Name:      pb.MqlErrorTradeCode(retCode).String(),
+		Retryable: IsRetCodeRetryable(retCode) || IsRetCodeRequote(retCode),
+	}
+	if _, known := pb.MqlErrorTradeCode_name[int32(retCode)]; !known {
+		e.Name = "TRADE_RETCODE_UNKNOWN"
+	}
+	if ex, ok := retCodeExplanations[retCode]; ok {
+		e.Cause, e.Fix = ex.cause, ex.fix
+	} else {
+		e.Cause = GetRetCodeMessage(retCode)
+	}
+	return e
+}
+
+// ExplainMql returns the cause and suggested fix for an MQL error code
+// (ApiError.MqlErrorCode). Codes without an entry keep description, the
+// server's MqlErrorDescription, as cause and have no fix.
+func ExplainMql(code pb.MqlErrorCode, description string) Explanation {
+	e := Explanation{Code: int32(code), Name: code.String(), Cause: description}
+	if ex, ok := mqlExplanations[code]; ok {
+		e.Cause, e.Fix = ex.cause, ex.fix
+	}
+	if e.Cause == "" {
+		e.Cause = "MQL error " + code.String()
+	}
+	return e
+}
+
+// ExplainErr explains err if it carries an MT5 error code:
+//   - *ApiError: the trade error code if set, otherwise the MQL error code
+//   - errors with a RetCode() uint32 method, such as the Sugar's TradeRejectedError
+//   - ErrNotConnected and context deadline/cancel errors
+//
+// Returns false for nil and errors without a known code.
+func ExplainErr(err error) (Explanation, bool) {
+	if err == nil {
+		return Explanation{}, false
+	}
+
+	var apiErr *ApiError
+	if errors.As(err, &apiErr) {
+		if code := apiErr.MqlErrorTradeIntCode(); code != 0 {
+			return Explain(uint32(code)), true
+		}
+		if apiErr.MqlErrorIntCode() != 0 {
+			return ExplainMql(apiErr.MqlErrorCode(), apiErr.MqlErrorDescription()), true
+		}
+		return Explanation{}, false
+	}
+
+	var rejected interface{ RetCode() uint32 }
+	if errors.As(err, &rejected) {
+		return Explain(rejected.RetCode()), true
+	}
+
+	switch {
+	case errors.Is(err, ErrNotConnected):
+		return Explanation{
+			Name:  "NOT_CONNECTED",
+			Cause: "MT5Account is not connected to a terminal",
+			Fix:   "call Connect() or ConnectEx() first",
+		}, true
+	case errors.Is(err, context.DeadlineExceeded):
+		return Explanation{
+			Name:      "DEADLINE_EXCEEDED",
+			Cause:     "no answer from the gateway before the timeout",
+			Fix:       "check the terminal and network; check orders/positions before resending a trade",
+			Retryable: true,
+		}, true
+	case errors.Is(err, context.Canceled):
+		return Explanation{
+			Name:  "CANCELED",
+			Cause: "the operation was canceled by the caller",
+		}, true
+	}
+	return Explanation{}, false
+}
@@ -0,0 +1,904 @@
+package fakeserver
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	pb "git.mtapi.io/root/mrpc-proto/mt5/libraries/go"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Trade server return codes used by the engine.
+const (
+	retDone          = 10009
+	retInvalid       = 10013
+	retInvalidVolume = 10014
+	retInvalidPrice  = 10015
+	retInvalidStops  = 10016
+	retNoMoney       = 10019
+)
+
+// firstTicket is the first order/deal ticket handed out.
+const firstTicket = 100001
+
+// quote is a symbol and its current price.
+type quote struct {
+	Symbol
+	bid, ask float64
+	time     time.Time
+}
+
+func (q *quote) contract() float64 {
+	if q.ContractSize > 0 {
+		return q.ContractSize
+	}
+	return 100000
+}
+
+// normalize rounds a price to the symbol digits, as a real feed quotes it.
+func (q *quote) normalize(price float64) float64 {
+	pow := math.Pow(10, float64(q.Digits))
+	return math.Round(price*pow) / pow
+}
+
+// validVolume checks volume against min, max and step.
+func (q *quote) validVolume(volume float64) bool {
+	min, max, step := q.VolumeMin, q.VolumeMax, q.VolumeStep
+	if min == 0 {
+		min = 0.01
+	}
+	if max == 0 {
+		max = 100
+	}
+	if step == 0 {
+		step = 0.01
+	}
+	if volume < min-1e-9 || volume > max+1e-9 {
+		return false
+	}
+	steps := (volume - min) / step
+	return math.Abs(steps-math.Round(steps)) < 1e-6
+}
+
+// minDistance is the stops level in price units, less a tenth of a point so
+// a level exactly at the limit is not refused for float rounding.
+func (q *quote) minDistance() float64 {
+	if q.StopsLevel == 0 {
+		return 0
+	}
+	return (float64(q.StopsLevel) - 0.1) * q.Point()
+}
+
+// validStops checks SL/TP of a buy or sell at price (0 = not set).
+func (q *quote) validStops(buy bool, price, sl, tp float64) bool {
+	dist := q.minDistance()
+	if buy {
+		return (sl == 0 || price-sl >= dist && sl < price) && (tp == 0 || tp-price >= dist && tp > price)
+	}
+	return (sl == 0 || sl-price >= dist && sl > price) && (tp == 0 || price-tp >= dist && tp < price)
+}
+
+// validPending checks the price of a pending order against the market.
+func (q *quote) validPending(typ pb.TMT5_ENUM_ORDER_TYPE, price float64) bool {
+	dist := q.minDistance()
+	switch typ {
+	case pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_LIMIT:
+		return price > 0 && price <= q.ask-dist
+	case pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL_LIMIT:
+		return price >= q.bid+dist
+	case pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_STOP:
+		return price >= q.ask+dist
+	case pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL_STOP:
+		return price > 0 && price <= q.bid-dist
+	}
+	return false
+}
+
+// order is an open pending order.
+type order struct {
+	ticket  uint64
+	symbol  string
+	typ     pb.TMT5_ENUM_ORDER_TYPE
+	volume  float64
+	price   float64
+	sl, tp  float64
+	comment string
+	magic   int64
+	setup   time.Time
+}
+
+func (o *order) buy() bool {
+	return o.typ == pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_LIMIT || o.typ == pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_STOP
+}
+
+// triggered reports whether the market reached the order price.
+func (o *order) triggered(q *quote) bool {
+	switch o.typ {
+	case pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_LIMIT:
+		return q.ask <= o.price
+	case pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL_LIMIT:
+		return q.bid >= o.price
+	case pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_STOP:
+		return q.ask >= o.price
+	case pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL_STOP:
+		return q.bid <= o.price
+	}
+	return false
+}
+
+// position is an open position. Its ticket is the ticket of the order that
+// opened it.
+type position struct {
+	ticket    uint64
+	symbol    string
+	buy       bool
+	volume    float64
+	price     float64
+	sl, tp    float64
+	comment   string
+	magic     int64
+	opened    time.Time
+	fromOrder bool
+}
+
+// closePrice is the price the position would close at now.
+func (p *position) closePrice(q *quote) float64 {
+	if p.buy {
+		return q.bid
+	}
+	return q.ask
+}
+
+// profitAt is the profit of closing volume at price.
+func (p *position) profitAt(q *quote, price, volume float64) float64 {
+	move := price - p.price
+	if !p.buy {
+		move = -move
+	}
+	return round2(move * volume * q.contract())
+}
+
+// accountState is a snapshot of the account figures.
+type accountState struct {
+	Balance, Equity, Profit, Margin, FreeMargin, MarginLevel float64
+}
+
+// tradeResult is the outcome of a trade request.
+type tradeResult struct {
+	code    uint32
+	comment string
+	order   uint64
+	deal    uint64
+	volume  float64
+	price   float64
+	bid     float64
+	ask     float64
+}
+
+func reject(code uint32, comment string) tradeResult {
+	return tradeResult{code: code, comment: comment}
+}
+
+// engine is the account, its orders and positions, and the matching logic.
+type engine struct {
+	mu  sync.Mutex
+	cfg Config
+
+	symbols   map[string]*quote
+	names     []string // Symbols in Config order
+	balance   float64
+	next      uint64
+	connected bool
+
+	orders     map[uint64]*order
+	positions  map[uint64]*position
+	histOrders []*pb.OrderHistoryData
+	deals      []*pb.DealHistoryData
+	closed     []*pb.PositionHistoryInfo
+
+	onTick  func(*pb.MrpcSubscriptionMqlTick)
+	onTrade func(*pb.OnTadeEventData)
+}
+
+func newEngine(cfg Config, onTick func(*pb.MrpcSubscriptionMqlTick), onTrade func(*pb.OnTadeEventData)) *engine {
+	e := &engine{
+		cfg:       cfg,
+		symbols:   make(map[string]*quote),
+		balance:   cfg.Balance,
+		next:      firstTicket,
+		orders:    make(map[uint64]*order),
+		positions: make(map[uint64]*position),
+		onTick:    onTick,
+		onTrade:   onTrade,
+	}
+	now := time.Now()
+	for _, s := range cfg.Symbols {
+		e.symbols[s.Name] = &quote{Symbol: s, bid: s.Bid, ask: s.Ask, time: now}
+		e.names = append(e.names, s.Name)
+	}
+	return e
+}
+
+func (e *engine) nextTicket() uint64 {
+	t := e.next
+	e.next++
+	return t
+}
+
+// ═══════════════════════════════════════════════════════════════
+// TRADE REQUESTS
+// ═══════════════════════════════════════════════════════════════
+
+// send executes an OrderSend request.
+func (e *engine) send(req *pb.OrderSendRequest) tradeResult {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	q, ok := e.symbols[req.GetSymbol()]
+	if !ok {
+		return reject(retInvalid, "unknown symbol")
+	}
+	if !q.validVolume(req.GetVolume()) {
+		return reject(retInvalidVolume, "invalid volume")
+	}
+	sl, tp := req.GetStopLoss(), req.GetTakeProfit()
+	now := time.Now()
+
+	switch typ := req.GetOperation(); typ {
+	case pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY, pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL:
+		buy := typ == pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY
+		price := q.bid
+		if buy {
+			price = q.ask
+		}
+		if !q.validStops(buy, price, sl, tp) {
+			return reject(retInvalidStops, "invalid stops")
+		}
+		if e.accountLocked().FreeMargin < e.margin(q, req.GetVolume(), price) {
+			return reject(retNoMoney, "no money")
+		}
+		p := &position{
+			ticket: e.nextTicket(), symbol: q.Name, buy: buy, volume: req.GetVolume(), price: price,
+			sl: sl, tp: tp, comment: req.GetComment(), magic: int64(req.GetExpertId()), opened: now,
+		}
+		deal := e.open(q, p, &pb.OrderHistoryData{
+			Ticket:        p.ticket,
+			SetupTime:     timestamppb.New(now),
+			DoneTime:      timestamppb.New(now),
+			State:         pb.BMT5_ENUM_ORDER_STATE_BMT5_ORDER_STATE_FILLED,
+			PriceOpen:     price,
+			PriceCurrent:  price,
+			StopLoss:      sl,
+			TakeProfit:    tp,
+			VolumeInitial: p.volume,
+			MagicNumber:   p.magic,
+			Type:          pb.BMT5_ENUM_ORDER_TYPE(typ),
+			PositionId:    p.ticket,
+			Symbol:        p.symbol,
+			Comment:       p.comment,
+		})
+		return tradeResult{code: retDone, comment: "done", order: p.ticket, deal: deal,
+			volume: p.volume, price: price, bid: q.bid, ask: q.ask}
+
+	case pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_LIMIT, pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL_LIMIT,
+		pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_STOP, pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_SELL_STOP:
+		price := req.GetPrice()
+		if !q.validPending(typ, price) {
+			return reject(retInvalidPrice, "invalid price")
+		}
+		o := &order{
+			ticket: e.nextTicket(), symbol: q.Name, typ: typ, volume: req.GetVolume(), price: price,
+			sl: sl, tp: tp, comment: req.GetComment(), magic: int64(req.GetExpertId()), setup: now,
+		}
+		if !q.validStops(o.buy(), price, sl, tp) {
+			return reject(retInvalidStops, "invalid stops")
+		}
+		e.orders[o.ticket] = o
+		e.onTrade(&pb.OnTadeEventData{NewOrders: []*pb.OnTradeOrderInfo{o.event(pb.SUB_ENUM_ORDER_STATE_SUB_ORDER_STATE_PLACED)}})
+		return tradeResult{code: retDone, comment: "done", order: o.ticket, volume: o.volume,
+			price: price, bid: q.bid, ask: q.ask}
+	}
+	return reject(retInvalid, "order type not supported")
+}
+
+// modify executes an OrderModify request.
+func (e *engine) modify(req *pb.OrderModifyRequest) tradeResult {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if o, ok := e.orders[req.GetTicket()]; ok {
+		q := e.symbols[o.symbol]
+		price, sl, tp := o.price, o.sl, o.tp
+		if req.Price != nil {
+			price = req.GetPrice()
+		}
+		if req.StopLoss != nil {
+			sl = req.GetStopLoss()
+		}
+		if req.TakeProfit != nil {
+			tp = req.GetTakeProfit()
+		}
+		if !q.validPending(o.typ, price) {
+			return reject(retInvalidPrice, "invalid price")
+		}
+		if !q.validStops(o.buy(), price, sl, tp) {
+			return reject(retInvalidStops, "invalid stops")
+		}
+		o.price, o.sl, o.tp = price, sl, tp
+		return tradeResult{code: retDone, comment: "done", order: o.ticket, volume: o.volume, price: price, bid: q.bid, ask: q.ask}
+	}
+
+	if p, ok := e.positions[req.GetTicket()]; ok {
+		q := e.symbols[p.symbol]
+		sl, tp := p.sl, p.tp
+		if req.StopLoss != nil {
+			sl = req.GetStopLoss()
+		}
+		if req.TakeProfit != nil {
+			tp = req.GetTakeProfit()
+		}
+		if !q.validStops(p.buy, p.closePrice(q), sl, tp) {
+			return reject(retInvalidStops, "invalid stops")
+		}
+		p.sl, p.tp = sl, tp
+		return tradeResult{code: retDone, comment: "done", order: p.ticket, volume: p.volume, price: p.price, bid: q.bid, ask: q.ask}
+	}
+	return reject(retInvalid, "unknown ticket")
+}
+
+// close executes an OrderClose request: deletes a pending order or closes
+// a position fully (Volume 0) or partially.
+func (e *engine) close(req *pb.OrderCloseRequest) (tradeResult, pb.MRPC_ORDER_CLOSE_MODE) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if o, ok := e.orders[req.GetTicket()]; ok {
+		delete(e.orders, o.ticket)
+		canceled := o.history(pb.BMT5_ENUM_ORDER_STATE_BMT5_ORDER_STATE_CANCELED, time.Now(), 0)
+		canceled.AccountLogin = int64(e.cfg.Login)
+		e.histOrders = append(e.histOrders, canceled)
+		e.onTrade(&pb.OnTadeEventData{DisappearedOrders: []*pb.OnTradeOrderInfo{o.event(pb.SUB_ENUM_ORDER_STATE_SUB_ORDER_STATE_CANCELED)}})
+		return tradeResult{code: retDone, comment: "done", order: o.ticket}, pb.MRPC_ORDER_CLOSE_MODE_MRPC_PENDING_ORDER_REMOVE
+	}
+
+	p, ok := e.positions[req.GetTicket()]
+	if !ok {
+		return reject(retInvalid, "unknown ticket"), pb.MRPC_ORDER_CLOSE_MODE_MRPC_MARKET_ORDER_CLOSE
+	}
+	q := e.symbols[p.symbol]
+	volume := req.GetVolume()
+	mode := pb.MRPC_ORDER_CLOSE_MODE_MRPC_MARKET_ORDER_PARTIAL_CLOSE
+	if volume == 0 || math.Abs(volume-p.volume) < 1e-9 {
+		volume = p.volume
+		mode = pb.MRPC_ORDER_CLOSE_MODE_MRPC_MARKET_ORDER_CLOSE
+	} else if volume > p.volume || !q.validVolume(volume) {
+		return reject(retInvalidVolume, "invalid volume"), mode
+	}
+	price := p.closePrice(q)
+	deal := e.closePosition(q, p, volume, price, pb.BMT5_ENUM_DEAL_REASON_BMT5_DEAL_REASON_EXPERT)
+	return tradeResult{code: retDone, comment: "done", order: p.ticket, deal: deal, volume: volume, price: price, bid: q.bid, ask: q.ask}, mode
+}
+
+// open books a new position: the filled order, entry deal and trade event.
+func (e *engine) open(q *quote, p *position, filled *pb.OrderHistoryData) uint64 {
+	e.positions[p.ticket] = p
+	filled.AccountLogin = int64(e.cfg.Login)
+	e.histOrders = append(e.histOrders, filled)
+	deal := e.deal(p, p.volume, p.price, 0, p.buy, pb.BMT5_ENUM_DEAL_ENTRY_TYPE_BMT5_DEAL_ENTRY_IN, pb.BMT5_ENUM_DEAL_REASON_BMT5_DEAL_REASON_EXPERT, p.opened)
+	e.onTrade(&pb.OnTadeEventData{
+		NewPositions:    []*pb.OnTradePositionInfo{p.event(q, int64(e.cfg.Login))},
+		NewHistoryDeals: []*pb.OnTradeHistoryDealInfo{dealEvent(deal)},
+	})
+	return deal.Ticket
+}
+
+// closePosition books closing volume of p at price and returns the deal ticket.
+func (e *engine) closePosition(q *quote, p *position, volume, price float64, reason pb.BMT5_ENUM_DEAL_REASON) uint64 {
+	now := time.Now()
+	profit := p.profitAt(q, price, volume)
+	e.balance = round2(e.balance + profit)
+
+	// The closing market order
+	closeType := pb.BMT5_ENUM_ORDER_TYPE_BMT5_ORDER_TYPE_SELL
+	if !p.buy {
+		closeType = pb.BMT5_ENUM_ORDER_TYPE_BMT5_ORDER_TYPE_BUY
+	}
+	e.histOrders = append(e.histOrders, &pb.OrderHistoryData{
+		Ticket:        e.nextTicket(),
+		SetupTime:     timestamppb.New(now),
+		DoneTime:      timestamppb.New(now),
+		State:         pb.BMT5_ENUM_ORDER_STATE_BMT5_ORDER_STATE_FILLED,
+		PriceOpen:     price,
+		PriceCurrent:  price,
+		VolumeInitial: volume,
+		MagicNumber:   p.magic,
+		Type:          closeType,
+		PositionId:    p.ticket,
+		Symbol:        p.symbol,
+		AccountLogin:  int64(e.cfg.Login),
+	})
+	deal := e.deal(p, volume, price, profit, !p.buy, pb.BMT5_ENUM_DEAL_ENTRY_TYPE_BMT5_DEAL_ENTRY_OUT, reason, now)
+
+	ev := &pb.OnTadeEventData{NewHistoryDeals: []*pb.OnTradeHistoryDealInfo{dealEvent(deal)}}
+	if volume < p.volume-1e-9 {
+		p.volume = round2(p.volume - volume)
+	} else {
+		delete(e.positions, p.ticket)
+		orderType := pb.AH_ENUM_POSITIONS_HISTORY_ORDER_TYPE_AH_ORDER_TYPE_BUY
+		if !p.buy {
+			orderType = pb.AH_ENUM_POSITIONS_HISTORY_ORDER_TYPE_AH_ORDER_TYPE_SELL
+		}
+		e.closed = append(e.closed, &pb.PositionHistoryInfo{
+			PositionTicket: p.ticket,
+			OrderType:      orderType,
+			OpenTime:       timestamppb.New(p.opened),
+			CloseTime:      timestamppb.New(now),
+			Volume:         volume,
+			OpenPrice:      p.price,
+			ClosePrice:     price,
+			StopLoss:       p.sl,
+			TakeProfit:     p.tp,
+			Profit:         e.positionProfit(p.ticket),
+			Comment:        p.comment,
+			Symbol:         p.symbol,
+			Magic:          p.magic,
+		})
+		ev.DisappearedPositions = []*pb.OnTradePositionInfo{p.event(q, int64(e.cfg.Login))}
+	}
+	e.onTrade(ev)
+	return deal.Ticket
+}
+
+// deal books a deal of position p.
+func (e *engine) deal(p *position, volume, price, profit float64, buy bool, entry pb.BMT5_ENUM_DEAL_ENTRY_TYPE, reason pb.BMT5_ENUM_DEAL_REASON, at time.Time) *pb.DealHistoryData {
+	typ := pb.BMT5_ENUM_DEAL_TYPE_BMT5_DEAL_TYPE_SELL
+	if buy {
+		typ = pb.BMT5_ENUM_DEAL_TYPE_BMT5_DEAL_TYPE_BUY
+	}
+	d := &pb.DealHistoryData{
+		Ticket:       e.nextTicket(),
+		Profit:       profit,
+		Price:        price,
+		StopLoss:     p.sl,
+		TakeProfit:   p.tp,
+		Volume:       volume,
+		EntryType:    entry,
+		Time:         timestamppb.New(at),
+		Type:         typ,
+		Reason:       reason,
+		PositionId:   p.ticket,
+		Comment:      p.comment,
+		Symbol:       p.symbol,
+		AccountLogin: int64(e.cfg.Login),
+	}
+	e.deals = append(e.deals, d)
+	return d
+}
+
+// positionProfit sums the exit deals of a position.
+func (e *engine) positionProfit(ticket uint64) float64 {
+	var sum float64
+	for _, d := range e.deals {
+		if d.PositionId == ticket {
+			sum += d.Profit
+		}
+	}
+	return round2(sum)
+}
+
+// ═══════════════════════════════════════════════════════════════
+// MARKET DATA
+// ═══════════════════════════════════════════════════════════════
+
+// tick applies a quote: triggers pending orders and SL/TP, then publishes it.
+func (e *engine) tick(t Tick) error {
+	e.mu.Lock()
+	q, ok := e.symbols[t.Symbol]
+	if !ok {
+		e.mu.Unlock()
+		return fmt.Errorf("fakeserver: unknown symbol %s", t.Symbol)
+	}
+	if t.Bid <= 0 || t.Ask < t.Bid {
+		e.mu.Unlock()
+		return fmt.Errorf("fakeserver: invalid quote %s %v/%v", t.Symbol, t.Bid, t.Ask)
+	}
+	q.bid, q.ask, q.time = q.normalize(t.Bid), q.normalize(t.Ask), t.Time
+
+	for _, ticket := range sortedKeys(e.orders) {
+		o := e.orders[ticket]
+		if o.symbol != q.Name || !o.triggered(q) {
+			continue
+		}
+		delete(e.orders, ticket)
+		price := q.bid
+		if o.buy() {
+			price = q.ask
+		}
+		e.onTrade(&pb.OnTadeEventData{DisappearedOrders: []*pb.OnTradeOrderInfo{o.event(pb.SUB_ENUM_ORDER_STATE_SUB_ORDER_STATE_FILLED)}})
+		filled := o.history(pb.BMT5_ENUM_ORDER_STATE_BMT5_ORDER_STATE_FILLED, t.Time, o.ticket)
+		filled.PriceCurrent = price
+		e.open(q, &position{
+			ticket: o.ticket, symbol: o.symbol, buy: o.buy(), volume: o.volume, price: price,
+			sl: o.sl, tp: o.tp, comment: o.comment, magic: o.magic, opened: t.Time, fromOrder: true,
+		}, filled)
+	}
+
+	for _, ticket := range sortedKeys(e.positions) {
+		p := e.positions[ticket]
+		if p.symbol != q.Name {
+			continue
+		}
+		price := p.closePrice(q)
+		switch {
+		case p.sl > 0 && (p.buy && price <= p.sl || !p.buy && price >= p.sl):
+			e.closePosition(q, p, p.volume, price, pb.BMT5_ENUM_DEAL_REASON_BMT5_DEAL_REASON_SL)
+		case p.tp > 0 && (p.buy && price >= p.tp || !p.buy && price <= p.tp):
+			e.closePosition(q, p, p.volume, price, pb.BMT5_ENUM_DEAL_REASON_BMT5_DEAL_REASON_TP)
+		}
+	}
+
+	tick := &pb.MrpcSubscriptionMqlTick{
+		Time:    timestamppb.New(t.Time),
+		Bid:     t.Bid,
+		Ask:     t.Ask,
+		TimeMsc: t.Time.UnixMilli(),
+		Symbol:  t.Symbol,
+	}
+	e.mu.Unlock()
+
+	e.onTick(tick)
+	return nil
+}
+
+// quote returns a copy of the quote of symbol.
+func (e *engine) quote(symbol string) (quote, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	q, ok := e.symbols[symbol]
+	if !ok {
+		return quote{}, false
+	}
+	return *q, true
+}
+
+// ═══════════════════════════════════════════════════════════════
+// ACCOUNT AND QUERIES
+// ═══════════════════════════════════════════════════════════════
+
+// connect checks the credentials and opens the session.
+func (e *engine) connect(login uint64, password string) error {
+	if e.cfg.Login != 0 && login != e.cfg.Login || e.cfg.Password != "" && password != e.cfg.Password {
+		return fmt.Errorf("invalid account or password")
+	}
+	e.mu.Lock()
+	e.connected = true
+	e.mu.Unlock()
+	return nil
+}
+
+func (e *engine) setConnected(connected bool) {
+	e.mu.Lock()
+	e.connected = connected
+	e.mu.Unlock()
+}
+
+func (e *engine) isConnected() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.connected
+}
+
+// margin is the margin of volume at price.
+func (e *engine) margin(q *quote, volume, price float64) float64 {
+	return volume * q.contract() * price / float64(e.cfg.Leverage)
+}
+
+func (e *engine) account() accountState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.accountLocked()
+}
+
+func (e *engine) accountLocked() accountState {
+	a := accountState{Balance: e.balance}
+	for _, p := range e.positions {
+		q := e.symbols[p.symbol]
+		a.Profit += p.profitAt(q, p.closePrice(q), p.volume)
+		a.Margin += e.margin(q, p.volume, p.price)
+	}
+	a.Profit, a.Margin = round2(a.Profit), round2(a.Margin)
+	a.Equity = round2(a.Balance + a.Profit)
+	a.FreeMargin = round2(a.Equity - a.Margin)
+	if a.Margin > 0 {
+		a.MarginLevel = round2(a.Equity / a.Margin * 100)
+	}
+	return a
+}
+
+// openedOrders returns open orders and positions by ticket.
+func (e *engine) openedOrders() *pb.OpenedOrdersData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	data := &pb.OpenedOrdersData{}
+	for i, ticket := range sortedKeys(e.orders) {
+		o := e.orders[ticket]
+		q := e.symbols[o.symbol]
+		current := q.bid
+		if o.buy() {
+			current = q.ask
+		}
+		data.OpenedOrders = append(data.OpenedOrders, &pb.OpenedOrderInfo{
+			Index:         uint32(i),
+			Ticket:        o.ticket,
+			PriceCurrent:  current,
+			PriceOpen:     o.price,
+			StopLoss:      o.sl,
+			TakeProfit:    o.tp,
+			VolumeCurrent: o.volume,
+			VolumeInitial: o.volume,
+			MagicNumber:   o.magic,
+			Type:          pb.BMT5_ENUM_ORDER_TYPE(o.typ),
+			State:         pb.BMT5_ENUM_ORDER_STATE_BMT5_ORDER_STATE_PLACED,
+			TimeSetup:     timestamppb.New(o.setup),
+			Symbol:        o.symbol,
+			Comment:       o.comment,
+			AccountLogin:  int64(e.cfg.Login),
+		})
+	}
+	for i, ticket := range sortedKeys(e.positions) {
+		p := e.positions[ticket]
+		q := e.symbols[p.symbol]
+		typ := pb.BMT5_ENUM_POSITION_TYPE_BMT5_POSITION_TYPE_BUY
+		if !p.buy {
+			typ = pb.BMT5_ENUM_POSITION_TYPE_BMT5_POSITION_TYPE_SELL
+		}
+		data.PositionInfos = append(data.PositionInfos, &pb.PositionInfo{
+			Index:        uint32(i),
+			Ticket:       p.ticket,
+			OpenTime:     timestamppb.New(p.opened),
+			Volume:       p.volume,
+			PriceOpen:    p.price,
+			StopLoss:     p.sl,
+			TakeProfit:   p.tp,
+			PriceCurrent: p.closePrice(q),
+			Profit:       p.profitAt(q, p.closePrice(q), p.volume),
+			Type:         typ,
+			MagicNumber:  p.magic,
+			Identifier:   int64(p.ticket),
+			Symbol:       p.symbol,
+			Comment:      p.comment,
+			AccountLogin: int64(e.cfg.Login),
+		})
+	}
+	return data
+}
+
+// tickets returns the open position and pending order tickets.
+func (e *engine) tickets() (positions, orders []uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return sortedKeys(e.positions), sortedKeys(e.orders)
+}
+
+// positionProfits returns the floating profit of every open position.
+func (e *engine) positionProfits() map[uint64]*pb.OnPositionProfitPositionInfo {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make(map[uint64]*pb.OnPositionProfitPositionInfo, len(e.positions))
+	for i, ticket := range sortedKeys(e.positions) {
+		p := e.positions[ticket]
+		q := e.symbols[p.symbol]
+		out[ticket] = &pb.OnPositionProfitPositionInfo{
+			Index:          int32(i),
+			Ticket:         int64(ticket),
+			Profit:         p.profitAt(q, p.closePrice(q), p.volume),
+			PositionSymbol: p.symbol,
+		}
+	}
+	return out
+}
+
+// orderHistory returns history orders and deals of [from, to] as one list,
+// ordered by time (descending for *_DESC sort modes), one page of it.
+func (e *engine) orderHistory(from, to time.Time, sortMode pb.BMT5_ENUM_ORDER_HISTORY_SORT_TYPE, page, perPage int32) *pb.OrdersHistoryData {
+	e.mu.Lock()
+	type item struct {
+		at     time.Time
+		ticket uint64
+		data   *pb.HistoryData
+	}
+	var items []item
+	for _, o := range e.histOrders {
+		if at := o.GetDoneTime().AsTime(); inRange(at, from, to) {
+			items = append(items, item{at, o.Ticket, &pb.HistoryData{HistoryOrder: o}})
+		}
+	}
+	for _, d := range e.deals {
+		if at := d.GetTime().AsTime(); inRange(at, from, to) {
+			items = append(items, item{at, d.Ticket, &pb.HistoryData{HistoryDeal: d}})
+		}
+	}
+	e.mu.Unlock()
+
+	byTicket := strings.Contains(sortMode.String(), "TICKET")
+	desc := strings.HasSuffix(sortMode.String(), "DESC")
+	sort.SliceStable(items, func(i, j int) bool {
+		less := items[i].at.Before(items[j].at) || items[i].at.Equal(items[j].at) && items[i].ticket < items[j].ticket
+		if byTicket {
+			less = items[i].ticket < items[j].ticket
+		}
+		if desc {
+			return !less
+		}
+		return less
+	})
+
+	start, end := pageBounds(len(items), page, perPage)
+	data := &pb.OrdersHistoryData{ArrayTotal: int32(len(items)), PageNumber: page, ItemsPerPage: perPage}
+	for i, it := range items[start:end] {
+		it.data.Index = uint32(start + i)
+		data.HistoryData = append(data.HistoryData, it.data)
+	}
+	return data
+}
+
+// positionsHistory returns closed positions opened in [from, to], one page.
+func (e *engine) positionsHistory(from, to time.Time, sortType pb.AH_ENUM_POSITIONS_HISTORY_SORT_TYPE, page, perPage int32) *pb.PositionsHistoryData {
+	e.mu.Lock()
+	var items []*pb.PositionHistoryInfo
+	for _, p := range e.closed {
+		if inRange(p.GetOpenTime().AsTime(), from, to) {
+			items = append(items, p)
+		}
+	}
+	e.mu.Unlock()
+
+	byTicket := strings.Contains(sortType.String(), "TICKET")
+	desc := strings.HasSuffix(sortType.String(), "DESC")
+	sort.SliceStable(items, func(i, j int) bool {
+		less := items[i].GetOpenTime().AsTime().Before(items[j].GetOpenTime().AsTime())
+		if byTicket {
+			less = items[i].PositionTicket < items[j].PositionTicket
+		}
+		if desc {
+			return !less
+		}
+		return less
+	})
+
+	start, end := pageBounds(len(items), page, perPage)
+	data := &pb.PositionsHistoryData{}
+	for i, p := range items[start:end] {
+		p.Index = int32(start + i)
+		data.HistoryPositions = append(data.HistoryPositions, p)
+	}
+	return data
+}
+
+// ═══════════════════════════════════════════════════════════════
+// EVENTS AND HELPERS
+// ═══════════════════════════════════════════════════════════════
+
+func (o *order) event(state pb.SUB_ENUM_ORDER_STATE) *pb.OnTradeOrderInfo {
+	return &pb.OnTradeOrderInfo{
+		Ticket:        int64(o.ticket),
+		State:         state,
+		SetupTimeMsc:  o.setup.UnixMilli(),
+		StopLoss:      o.sl,
+		TakeProfit:    o.tp,
+		Comment:       o.comment,
+		Symbol:        o.symbol,
+		Magic:         o.magic,
+		PriceOpen:     o.price,
+		SetupTime:     timestamppb.New(o.setup),
+		VolumeCurrent: o.volume,
+		VolumeInitial: o.volume,
+		OrderType:     pb.SUB_ENUM_ORDER_TYPE(o.typ),
+	}
+}
+
+// history returns the history record of a pending order that left the book.
+func (o *order) history(state pb.BMT5_ENUM_ORDER_STATE, done time.Time, positionID uint64) *pb.OrderHistoryData {
+	return &pb.OrderHistoryData{
+		Ticket:        o.ticket,
+		SetupTime:     timestamppb.New(o.setup),
+		DoneTime:      timestamppb.New(done),
+		State:         state,
+		PriceOpen:     o.price,
+		StopLoss:      o.sl,
+		TakeProfit:    o.tp,
+		VolumeCurrent: o.volume,
+		VolumeInitial: o.volume,
+		MagicNumber:   o.magic,
+		Type:          pb.BMT5_ENUM_ORDER_TYPE(o.typ),
+		PositionId:    positionID,
+		Symbol:        o.symbol,
+		Comment:       o.comment,
+	}
+}
+
+func (p *position) event(q *quote, login int64) *pb.OnTradePositionInfo {
+	typ := pb.SUB_ENUM_POSITION_TYPE_SUB_POSITION_TYPE_BUY
+	if !p.buy {
+		typ = pb.SUB_ENUM_POSITION_TYPE_SUB_POSITION_TYPE_SELL
+	}
+	return &pb.OnTradePositionInfo{
+		Ticket:           int64(p.ticket),
+		Type:             typ,
+		PositionTime:     timestamppb.New(p.opened),
+		PriceOpen:        p.price,
+		Profit:           p.profitAt(q, p.closePrice(q), p.volume),
+		Sl:               p.sl,
+		Tp:               p.tp,
+		Volume:           p.volume,
+		Comment:          p.comment,
+		Symbol:           p.symbol,
+		Magic:            p.magic,
+		PriceCurrent:     p.closePrice(q),
+		AccountLogin:     login,
+		FromPendingOrder: p.fromOrder,
+	}
+}
+
+func dealEvent(d *pb.DealHistoryData) *pb.OnTradeHistoryDealInfo {
+	return &pb.OnTradeHistoryDealInfo{
+		Ticket:         d.Ticket,
+		OrderTicket:    int64(d.PositionId),
+		Type:           pb.SUB_ENUM_DEAL_TYPE(d.Type),
+		DealTime:       d.Time,
+		Entry:          pb.SUB_ENUM_DEAL_ENTRY(d.EntryType),
+		DealPositionId: int64(d.PositionId),
+		Price:          d.Price,
+		Profit:         d.Profit,
+		Sl:             d.StopLoss,
+		Tp:             d.TakeProfit,
+		Volume:         d.Volume,
+		Comment:        d.Comment,
+		Symbol:         d.Symbol,
+		Reason:         pb.SUB_ENUM_DEAL_REASON(d.Reason),
+		AccountLogin:   d.AccountLogin,
+	}
+}
+
+// sortedKeys returns the tickets of m in ascending order.
+func sortedKeys[V any](m map[uint64]V) []uint64 {
+	keys := make([]uint64, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// inRange reports whether t is in [from, to]; zero bounds are open.
+func inRange(t, from, to time.Time) bool {
+	return (from.IsZero() || !t.Before(from)) && (to.IsZero() || !t.After(to))
+}
+
+// pageBounds returns the slice bounds of a 1-based page (page <= 1 = first,
+// perPage <= 0 = everything).
+func pageBounds(n int, page, perPage int32) (int, int) {
+	if perPage <= 0 {
+		return 0, n
+	}
+	if page < 1 {
+		page = 1
+	}
+	start := int(page-1) * int(perPage)
+	if start > n {
+		start = n
+	}
+	end := start + int(perPage)
+	if end > n {
+		end = n
+	}
+	return start, end
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
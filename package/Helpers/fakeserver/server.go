@@ -0,0 +1,407 @@
+// Package fakeserver is an in-process MT5 gRPC server for local end-to-end
+// tests of the client stack (MT5Account, MT5Service, MT5Sugar).
+//
+// It implements the Connection, AccountHelper, AccountInformation,
+// TradingHelper, MarketInfo, Health and SubscriptionService services on top
+// of a small hedging matching engine:
+//
+//   - market orders fill at the current bid/ask, pending limit and stop
+//     orders trigger on scripted ticks, SL/TP close positions
+//   - balance, equity, margin and floating profit follow the positions
+//     (profit = price move * volume * contract size, in account currency)
+//   - orders, deals and closed positions appear in history
+//   - OnSymbolTick, OnTrade, OnPositionProfit and
+//     OnPositionsAndPendingOrdersTickets streams are served
+//
+// Market data is scripted with SetPrice and Play. Failures are scripted with
+// InjectFault (gRPC status or MT5 API error for the next calls of a method),
+// DropStreams (open subscriptions end with Unavailable and the client
+// reconnects) and Restart (the whole transport goes away and comes back).
+//
+// Usage:
+//
+//	srv := fakeserver.New(fakeserver.Config{Login: 12345})
+//	defer srv.Close()
+//
+//	account, _ := mt5.NewMT5AccountWithOptions(12345, "", "fake", uuid.New(),
+//	    mt5.AccountOptions{DialOptions: srv.DialOptions()})
+//	sugar := mt5sugar.NewMT5SugarFromAccount(account)
+//	sugar.QuickConnect("")
+//
+//	ticket, _ := sugar.BuyLimit("EURUSD", 0.1, 1.0950)
+//	srv.SetPrice("EURUSD", 1.0948, 1.0949) // fills the limit
+//
+// Not modelled: OnTradeTransaction, stop-limit and close-by orders, swaps,
+// commissions, order expiration. SymbolInfo properties the engine does not
+// know read as zero.
+package fakeserver
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	pb "git.mtapi.io/root/mrpc-proto/mt5/libraries/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Config describes the simulated account. Zero fields take the defaults
+// noted on each field.
+type Config struct {
+	Login     uint64                      // Accepted login (0 = any)
+	Password  string                      // Accepted password ("" = any)
+	Balance   float64                     // Starting balance (0 = 10000)
+	Leverage  int64                       // Account leverage (0 = 100)
+	Currency  string                      // Account currency ("" = "USD")
+	TradeMode pb.MrpcEnumAccountTradeMode // Demo, contest or real (zero = demo)
+	Symbols   []Symbol                    // Tradable symbols (nil = DefaultSymbols)
+}
+
+// Symbol is a tradable symbol and its starting quote.
+type Symbol struct {
+	Name         string
+	Digits       int32
+	ContractSize float64 // 0 = 100000
+	VolumeMin    float64 // 0 = 0.01
+	VolumeMax    float64 // 0 = 100
+	VolumeStep   float64 // 0 = 0.01
+	StopsLevel   int32   // Minimum SL/TP/pending distance in points
+	Bid, Ask     float64
+}
+
+// Point returns the price of one point.
+func (s Symbol) Point() float64 {
+	p := 1.0
+	for i := int32(0); i < s.Digits; i++ {
+		p /= 10
+	}
+	return p
+}
+
+// DefaultSymbols are used when Config.Symbols is nil.
+var DefaultSymbols = []Symbol{
+	{Name: "EURUSD", Digits: 5, Bid: 1.10000, Ask: 1.10010},
+	{Name: "GBPUSD", Digits: 5, Bid: 1.27000, Ask: 1.27015},
+	{Name: "USDJPY", Digits: 3, Bid: 150.000, Ask: 150.012},
+}
+
+// Fault makes the next calls of a method fail (see InjectFault).
+type Fault struct {
+	Code     codes.Code // gRPC status returned instead of running the call (e.g. codes.Unavailable)
+	APIError string     // If Code is OK: MT5 API error code put into the reply (e.g. "TERMINAL_INSTANCE_NOT_FOUND")
+	Message  string     // Error message
+	Times    int        // Number of calls affected (0 = 1)
+}
+
+// Server is the fake MT5 gateway. It listens on an in-memory connection;
+// clients reach it through DialOptions.
+type Server struct {
+	cfg Config
+
+	mu       sync.Mutex
+	engine   *engine
+	listener *bufconn.Listener
+	grpc     *grpc.Server
+	faults   map[string][]Fault
+	drop     chan struct{} // Closed by DropStreams, then replaced
+	closed   bool
+
+	ticks  hub // *pb.MrpcSubscriptionMqlTick
+	trades hub // *pb.OnTadeEventData
+}
+
+// New starts a server for cfg.
+func New(cfg Config) *Server {
+	if cfg.Balance == 0 {
+		cfg.Balance = 10000
+	}
+	if cfg.Leverage == 0 {
+		cfg.Leverage = 100
+	}
+	if cfg.Currency == "" {
+		cfg.Currency = "USD"
+	}
+	if cfg.Symbols == nil {
+		cfg.Symbols = DefaultSymbols
+	}
+
+	s := &Server{
+		cfg:    cfg,
+		faults: make(map[string][]Fault),
+		drop:   make(chan struct{}),
+	}
+	s.engine = newEngine(cfg, s.publishTick, s.publishTrade)
+	s.serve()
+	return s
+}
+
+// serve starts a gRPC server on a fresh in-memory listener.
+func (s *Server) serve() {
+	lis := bufconn.Listen(1 << 20)
+	gs := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.unaryFault),
+		grpc.ChainStreamInterceptor(s.streamFault),
+	)
+	pb.RegisterConnectionServer(gs, connectionService{s: s})
+	pb.RegisterAccountHelperServer(gs, accountHelperService{s: s})
+	pb.RegisterAccountInformationServer(gs, accountInformationService{s: s})
+	pb.RegisterTradingHelperServer(gs, tradingService{s: s})
+	pb.RegisterMarketInfoServer(gs, marketInfoService{s: s})
+	pb.RegisterHealthServer(gs, healthService{s: s})
+	pb.RegisterSubscriptionServiceServer(gs, subscriptionService{s: s})
+
+	s.mu.Lock()
+	s.listener, s.grpc = lis, gs
+	s.mu.Unlock()
+	go gs.Serve(lis)
+}
+
+// DialOptions connects a client to this server. Pass them in
+// AccountOptions.DialOptions; the server address is ignored.
+func (s *Server) DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			s.mu.Lock()
+			lis := s.listener
+			s.mu.Unlock()
+			return lis.DialContext(ctx)
+		}),
+	}
+}
+
+// Close stops the server. Open calls fail with Unavailable.
+func (s *Server) Close() {
+	s.mu.Lock()
+	gs := s.grpc
+	s.closed = true
+	s.mu.Unlock()
+	gs.Stop()
+}
+
+// Restart drops every connection and starts listening again, like a
+// gateway restart. Account state (balance, orders, positions) survives.
+func (s *Server) Restart() {
+	s.mu.Lock()
+	gs := s.grpc
+	closed := s.closed
+	s.mu.Unlock()
+	if closed {
+		return
+	}
+	gs.Stop()
+	s.serve()
+}
+
+// DropStreams ends every open subscription with Unavailable. Clients using
+// ExecuteStreamWithReconnect re-open them.
+func (s *Server) DropStreams() {
+	s.mu.Lock()
+	close(s.drop)
+	s.drop = make(chan struct{})
+	s.mu.Unlock()
+}
+
+// dropped returns the channel closed by the next DropStreams.
+func (s *Server) dropped() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.drop
+}
+
+// InjectFault makes the next f.Times calls of method fail. method is the
+// RPC name ("OrderSend") or the full gRPC method
+// ("/mt5_term_api.TradingHelper/OrderSend"). Faults queue up in order.
+func (s *Server) InjectFault(method string, f Fault) {
+	if f.Times <= 0 {
+		f.Times = 1
+	}
+	s.mu.Lock()
+	s.faults[methodName(method)] = append(s.faults[methodName(method)], f)
+	s.mu.Unlock()
+}
+
+// takeFault consumes one injected fault for fullMethod.
+func (s *Server) takeFault(fullMethod string) (Fault, bool) {
+	name := methodName(fullMethod)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	queue := s.faults[name]
+	if len(queue) == 0 {
+		return Fault{}, false
+	}
+	f := queue[0]
+	if queue[0].Times--; queue[0].Times == 0 {
+		queue = queue[1:]
+	}
+	if len(queue) == 0 {
+		delete(s.faults, name)
+	} else {
+		s.faults[name] = queue
+	}
+	return f, true
+}
+
+func (s *Server) unaryFault(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	f, ok := s.takeFault(info.FullMethod)
+	if !ok {
+		return handler(ctx, req)
+	}
+	if f.Code != codes.OK {
+		return nil, status.Error(f.Code, f.Message)
+	}
+	reply, err := errorReply(info.FullMethod, f)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Server) streamFault(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	f, ok := s.takeFault(info.FullMethod)
+	if !ok {
+		return handler(srv, ss)
+	}
+	if f.Code != codes.OK {
+		return status.Error(f.Code, f.Message)
+	}
+	reply, err := errorReply(info.FullMethod, f)
+	if err != nil {
+		return err
+	}
+	return ss.SendMsg(reply)
+}
+
+// errorReply builds the reply message of fullMethod carrying an MT5 API
+// error. Every reply of the API has an "error" member in its response oneof.
+func errorReply(fullMethod string, f Fault) (proto.Message, error) {
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "fakeserver: unknown service %s", service)
+	}
+	md := desc.(protoreflect.ServiceDescriptor).Methods().ByName(protoreflect.Name(method))
+	if md == nil {
+		return nil, status.Errorf(codes.Internal, "fakeserver: unknown method %s", fullMethod)
+	}
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(md.Output().FullName())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "fakeserver: unknown reply of %s", fullMethod)
+	}
+	reply := mt.New()
+	fd := reply.Descriptor().Fields().ByName("error")
+	if fd == nil {
+		return nil, status.Errorf(codes.Internal, "fakeserver: %s has no error member", md.Output().FullName())
+	}
+	apiErr := &pb.Error{ErrorCode: f.APIError, ErrorMessage: f.Message}
+	reply.Set(fd, protoreflect.ValueOfMessage(apiErr.ProtoReflect()))
+	return reply.Interface(), nil
+}
+
+// methodName returns the RPC name of a full gRPC method.
+func methodName(method string) string {
+	if i := strings.LastIndex(method, "/"); i >= 0 {
+		return method[i+1:]
+	}
+	return method
+}
+
+// apiError returns an MT5 API error for replies.
+func apiError(code, message string) *pb.Error {
+	return &pb.Error{ErrorCode: code, ErrorMessage: message}
+}
+
+// ═══════════════════════════════════════════════════════════════
+// MARKET DATA
+// ═══════════════════════════════════════════════════════════════
+
+// Tick is one scripted quote.
+type Tick struct {
+	Symbol   string
+	Bid, Ask float64
+	Time     time.Time // Zero = time of delivery
+}
+
+// SetPrice sets the quote of symbol, runs the matching engine (pending
+// orders, SL/TP) and publishes the tick to OnSymbolTick subscribers.
+func (s *Server) SetPrice(symbol string, bid, ask float64) error {
+	return s.engine.tick(Tick{Symbol: symbol, Bid: bid, Ask: ask, Time: time.Now()})
+}
+
+// Play delivers ticks one after another, interval apart, until all are
+// delivered or ctx is done.
+func (s *Server) Play(ctx context.Context, ticks []Tick, interval time.Duration) error {
+	for i, t := range ticks {
+		if i > 0 && interval > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(interval):
+			}
+		}
+		if t.Time.IsZero() {
+			t.Time = time.Now()
+		}
+		if err := s.engine.tick(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Balance returns the account balance.
+func (s *Server) Balance() float64 {
+	return s.engine.account().Balance
+}
+
+// ═══════════════════════════════════════════════════════════════
+// SUBSCRIPTIONS
+// ═══════════════════════════════════════════════════════════════
+
+// hubBuffer is the event buffer of one subscriber. A subscriber that falls
+// further behind misses events, like a slow client of the real gateway.
+const hubBuffer = 1024
+
+// hub fans events out to open streams.
+type hub struct {
+	mu   sync.Mutex
+	subs map[chan any]struct{}
+}
+
+func (h *hub) subscribe() (<-chan any, func()) {
+	ch := make(chan any, hubBuffer)
+	h.mu.Lock()
+	if h.subs == nil {
+		h.subs = make(map[chan any]struct{})
+	}
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subs, ch)
+		h.mu.Unlock()
+	}
+}
+
+func (h *hub) publish(ev any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+func (s *Server) publishTick(t *pb.MrpcSubscriptionMqlTick) { s.ticks.publish(t) }
+func (s *Server) publishTrade(ev *pb.OnTadeEventData)       { s.trades.publish(ev) }
@@ -0,0 +1,176 @@
+package fakeserver_test
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "git.mtapi.io/root/mrpc-proto/mt5/libraries/go"
+	mt5 "github.com/MetaRPC/GoMT5/package/Helpers"
+	"github.com/MetaRPC/GoMT5/package/Helpers/fakeserver"
+	"github.com/google/uuid"
+)
+
+const retcodeDone = 10009 // TRADE_RETCODE_DONE
+
+// dial starts a server with one EURUSD quote and returns a connected account.
+func dial(t *testing.T) (*fakeserver.Server, *mt5.MT5Account) {
+	t.Helper()
+	srv := fakeserver.New(fakeserver.Config{
+		Login:   1,
+		Symbols: []fakeserver.Symbol{{Name: "EURUSD", Digits: 5, Bid: 1.1000, Ask: 1.1002}},
+	})
+	t.Cleanup(srv.Close)
+
+	account, err := mt5.NewMT5AccountWithOptions(1, "", "fake", uuid.New(),
+		mt5.AccountOptions{DialOptions: srv.DialOptions()})
+	if err != nil {
+		t.Fatalf("NewMT5AccountWithOptions: %v", err)
+	}
+	t.Cleanup(func() { account.Close() })
+
+	if _, err := account.ConnectEx(context.Background(), &pb.ConnectExRequest{User: 1}); err != nil {
+		t.Fatalf("ConnectEx: %v", err)
+	}
+	return srv, account
+}
+
+// opened returns the open positions and pending orders.
+func opened(t *testing.T, account *mt5.MT5Account) *pb.OpenedOrdersData {
+	t.Helper()
+	data, err := account.OpenedOrders(context.Background(), &pb.OpenedOrdersRequest{})
+	if err != nil {
+		t.Fatalf("OpenedOrders: %v", err)
+	}
+	return data
+}
+
+func TestMarketRoundTrip(t *testing.T) {
+	srv, account := dial(t)
+	ctx := context.Background()
+
+	sent, err := account.OrderSend(ctx, &pb.OrderSendRequest{
+		Symbol:    "EURUSD",
+		Operation: pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY,
+		Volume:    0.1,
+	})
+	if err != nil {
+		t.Fatalf("OrderSend: %v", err)
+	}
+	if sent.ReturnedCode != retcodeDone || sent.Price != 1.1002 {
+		t.Fatalf("OrderSend = %d @ %v, want %d @ 1.1002", sent.ReturnedCode, sent.Price, retcodeDone)
+	}
+
+	positions := opened(t, account).GetPositionInfos()
+	if len(positions) != 1 || positions[0].Volume != 0.1 {
+		t.Fatalf("positions = %v, want one of 0.1 lots", positions)
+	}
+	ticket := positions[0].Ticket
+
+	if err := srv.SetPrice("EURUSD", 1.1012, 1.1014); err != nil {
+		t.Fatalf("SetPrice: %v", err)
+	}
+	closed, err := account.OrderClose(ctx, &pb.OrderCloseRequest{Ticket: ticket})
+	if err != nil {
+		t.Fatalf("OrderClose: %v", err)
+	}
+	if closed.ReturnedCode != retcodeDone {
+		t.Fatalf("OrderClose = %d, want %d", closed.ReturnedCode, retcodeDone)
+	}
+
+	if left := opened(t, account).GetPositionInfos(); len(left) != 0 {
+		t.Fatalf("positions after close = %v, want none", left)
+	}
+	// Bought at 1.1002, sold at 1.1012: 10 pips on 0.1 lot = 10 USD
+	if got := srv.Balance(); math.Abs(got-10010) > 1e-6 {
+		t.Errorf("balance = %v, want 10010", got)
+	}
+}
+
+func TestPendingPlaceDelete(t *testing.T) {
+	_, account := dial(t)
+	ctx := context.Background()
+
+	price := 1.0950
+	sent, err := account.OrderSend(ctx, &pb.OrderSendRequest{
+		Symbol:    "EURUSD",
+		Operation: pb.TMT5_ENUM_ORDER_TYPE_TMT5_ORDER_TYPE_BUY_LIMIT,
+		Volume:    0.1,
+		Price:     &price,
+	})
+	if err != nil {
+		t.Fatalf("OrderSend: %v", err)
+	}
+	if sent.ReturnedCode != retcodeDone || sent.Order == 0 {
+		t.Fatalf("OrderSend = %d order #%d, want %d and a ticket", sent.ReturnedCode, sent.Order, retcodeDone)
+	}
+
+	orders := opened(t, account).GetOpenedOrders()
+	if len(orders) != 1 || orders[0].Ticket != sent.Order || orders[0].PriceOpen != price {
+		t.Fatalf("orders = %v, want #%d @ %v", orders, sent.Order, price)
+	}
+	if positions := opened(t, account).GetPositionInfos(); len(positions) != 0 {
+		t.Fatalf("limit below market filled: %v", positions)
+	}
+
+	deleted, err := account.OrderClose(ctx, &pb.OrderCloseRequest{Ticket: sent.Order})
+	if err != nil {
+		t.Fatalf("OrderClose: %v", err)
+	}
+	if deleted.ReturnedCode != retcodeDone {
+		t.Fatalf("OrderClose = %d, want %d", deleted.ReturnedCode, retcodeDone)
+	}
+	if left := opened(t, account).GetOpenedOrders(); len(left) != 0 {
+		t.Fatalf("orders after delete = %v, want none", left)
+	}
+}
+
+func TestStreamReconnect(t *testing.T) {
+	srv, account := dial(t)
+
+	var reconnects atomic.Int32
+	account.StreamOptions = mt5.StreamOptions{
+		Retry: mt5.RetryPolicy{BaseDelay: 10 * time.Millisecond, Jitter: mt5.NoJitter},
+		OnStreamReconnect: func(mt5.StreamReconnect) {
+			reconnects.Add(1)
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ticks, errs := account.OnSymbolTick(ctx, &pb.OnSymbolTickRequest{SymbolNames: []string{"EURUSD"}})
+
+	// waitTick moves the price until the stream delivers it; the subscription
+	// goes live asynchronously, so earlier ticks may be missed.
+	waitTick := func(bid float64) {
+		t.Helper()
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case tick := <-ticks:
+				if tick.GetSymbolTick().GetBid() == bid {
+					return
+				}
+			case err := <-errs:
+				t.Fatalf("tick stream: %v", err)
+			case <-ticker.C:
+				if err := srv.SetPrice("EURUSD", bid, bid+0.0002); err != nil {
+					t.Fatalf("SetPrice: %v", err)
+				}
+			case <-ctx.Done():
+				t.Fatalf("no tick at %v", bid)
+			}
+		}
+	}
+
+	waitTick(1.1010)
+	srv.DropStreams()
+	waitTick(1.1020)
+
+	if reconnects.Load() == 0 {
+		t.Error("stream delivered ticks after DropStreams without reconnecting")
+	}
+}
@@ -0,0 +1,483 @@
+package fakeserver
+
+import (
+	"context"
+	"time"
+
+	pb "git.mtapi.io/root/mrpc-proto/mt5/libraries/go"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// defaultStreamPeriod is the period of timer-driven streams whose request
+// leaves TimerPeriodMilliseconds at zero.
+const defaultStreamPeriod = time.Second
+
+// ═══════════════════════════════════════════════════════════════
+// CONNECTION / HEALTH
+// ═══════════════════════════════════════════════════════════════
+
+type connectionService struct {
+	pb.UnimplementedConnectionServer
+	s *Server
+}
+
+func (c connectionService) ConnectEx(ctx context.Context, req *pb.ConnectExRequest) (*pb.ConnectExReply, error) {
+	if err := c.s.engine.connect(req.GetUser(), req.GetPassword()); err != nil {
+		return &pb.ConnectExReply{Response: &pb.ConnectExReply_Error{Error: apiError("INVALID_CREDENTIALS", err.Error())}}, nil
+	}
+	return &pb.ConnectExReply{Response: &pb.ConnectExReply_Data{Data: &pb.ConnectData{TerminalInstanceGuid: uuid.NewString()}}}, nil
+}
+
+func (c connectionService) Connect(ctx context.Context, req *pb.ConnectRequest) (*pb.ConnectReply, error) {
+	if err := c.s.engine.connect(req.GetUser(), req.GetPassword()); err != nil {
+		return &pb.ConnectReply{Response: &pb.ConnectReply_Error{Error: apiError("INVALID_CREDENTIALS", err.Error())}}, nil
+	}
+	return &pb.ConnectReply{Response: &pb.ConnectReply_Data{Data: &pb.ConnectData{TerminalInstanceGuid: uuid.NewString()}}}, nil
+}
+
+func (c connectionService) CheckConnect(ctx context.Context, req *pb.CheckConnectRequest) (*pb.CheckConnectReply, error) {
+	alive := c.s.engine.isConnected()
+	return &pb.CheckConnectReply{Response: &pb.CheckConnectReply_Data{Data: &pb.CheckConnectData{
+		HealthCheck: &pb.TerminalHealthCheck{IsAlive: alive, ApiIsAlive: true, TerminalIsConnectedToMtServer: alive},
+	}}}, nil
+}
+
+func (c connectionService) Disconnect(ctx context.Context, req *pb.DisconnectRequest) (*pb.DisconnectReply, error) {
+	c.s.engine.setConnected(false)
+	return &pb.DisconnectReply{Response: &pb.DisconnectReply_Data{Data: &pb.DisconnectData{}}}, nil
+}
+
+func (c connectionService) Reconnect(ctx context.Context, req *pb.ReconnectRequest) (*pb.ReconnectReply, error) {
+	c.s.engine.setConnected(true)
+	return &pb.ReconnectReply{Response: &pb.ReconnectReply_Data{Data: &pb.ReconnectData{TerminalInstanceGuid: uuid.NewString()}}}, nil
+}
+
+type healthService struct {
+	pb.UnimplementedHealthServer
+	s *Server
+}
+
+func (h healthService) Check(ctx context.Context, req *pb.HealthCheckRequest) (*pb.HealthCheckReply, error) {
+	return &pb.HealthCheckReply{IsConnectedToServer: h.s.engine.isConnected(), ServerTimeSeconds: time.Now().Unix()}, nil
+}
+
+// ═══════════════════════════════════════════════════════════════
+// ACCOUNT
+// ═══════════════════════════════════════════════════════════════
+
+type accountHelperService struct {
+	pb.UnimplementedAccountHelperServer
+	s *Server
+}
+
+func (a accountHelperService) AccountSummary(ctx context.Context, req *pb.AccountSummaryRequest) (*pb.AccountSummaryReply, error) {
+	cfg, acc := a.s.cfg, a.s.engine.account()
+	return &pb.AccountSummaryReply{Response: &pb.AccountSummaryReply_Data{Data: &pb.AccountSummaryData{
+		AccountLogin:       int64(cfg.Login),
+		AccountBalance:     acc.Balance,
+		AccountEquity:      acc.Equity,
+		AccountUserName:    "Fake Trader",
+		AccountLeverage:    cfg.Leverage,
+		AccountTradeMode:   cfg.TradeMode,
+		AccountCompanyName: "fakeserver",
+		AccountCurrency:    cfg.Currency,
+		ServerTime:         timestamppb.Now(),
+	}}}, nil
+}
+
+func (a accountHelperService) OpenedOrders(ctx context.Context, req *pb.OpenedOrdersRequest) (*pb.OpenedOrdersReply, error) {
+	return &pb.OpenedOrdersReply{Response: &pb.OpenedOrdersReply_Data{Data: a.s.engine.openedOrders()}}, nil
+}
+
+func (a accountHelperService) OpenedOrdersTickets(ctx context.Context, req *pb.OpenedOrdersTicketsRequest) (*pb.OpenedOrdersTicketsReply, error) {
+	positions, orders := a.s.engine.tickets()
+	data := &pb.OpenedOrdersTicketsData{}
+	for _, t := range positions {
+		data.OpenedPositionTickets = append(data.OpenedPositionTickets, int64(t))
+	}
+	for _, t := range orders {
+		data.OpenedOrdersTickets = append(data.OpenedOrdersTickets, int64(t))
+	}
+	return &pb.OpenedOrdersTicketsReply{Response: &pb.OpenedOrdersTicketsReply_Data{Data: data}}, nil
+}
+
+func (a accountHelperService) OrderHistory(ctx context.Context, req *pb.OrderHistoryRequest) (*pb.OrderHistoryReply, error) {
+	data := a.s.engine.orderHistory(timeOf(req.GetInputFrom()), timeOf(req.GetInputTo()), req.GetInputSortMode(), req.GetPageNumber(), req.GetItemsPerPage())
+	return &pb.OrderHistoryReply{Response: &pb.OrderHistoryReply_Data{Data: data}}, nil
+}
+
+func (a accountHelperService) PositionsHistory(ctx context.Context, req *pb.PositionsHistoryRequest) (*pb.PositionsHistoryReply, error) {
+	data := a.s.engine.positionsHistory(timeOf(req.GetPositionOpenTimeFrom()), timeOf(req.GetPositionOpenTimeTo()), req.GetSortType(), req.GetPageNumber(), req.GetItemsPerPage())
+	return &pb.PositionsHistoryReply{Response: &pb.PositionsHistoryReply_Data{Data: data}}, nil
+}
+
+type accountInformationService struct {
+	pb.UnimplementedAccountInformationServer
+	s *Server
+}
+
+func (a accountInformationService) AccountInfoDouble(ctx context.Context, req *pb.AccountInfoDoubleRequest) (*pb.AccountInfoDoubleReply, error) {
+	acc := a.s.engine.account()
+	var v float64
+	switch req.GetPropertyId() {
+	case pb.AccountInfoDoublePropertyType_ACCOUNT_BALANCE:
+		v = acc.Balance
+	case pb.AccountInfoDoublePropertyType_ACCOUNT_EQUITY:
+		v = acc.Equity
+	case pb.AccountInfoDoublePropertyType_ACCOUNT_PROFIT:
+		v = acc.Profit
+	case pb.AccountInfoDoublePropertyType_ACCOUNT_MARGIN:
+		v = acc.Margin
+	case pb.AccountInfoDoublePropertyType_ACCOUNT_MARGIN_FREE:
+		v = acc.FreeMargin
+	case pb.AccountInfoDoublePropertyType_ACCOUNT_MARGIN_LEVEL:
+		v = acc.MarginLevel
+	}
+	return &pb.AccountInfoDoubleReply{Response: &pb.AccountInfoDoubleReply_Data{Data: &pb.AccountInfoDoubleData{RequestedValue: v}}}, nil
+}
+
+func (a accountInformationService) AccountInfoInteger(ctx context.Context, req *pb.AccountInfoIntegerRequest) (*pb.AccountInfoIntegerReply, error) {
+	cfg := a.s.cfg
+	var v int64
+	switch req.GetPropertyId() {
+	case pb.AccountInfoIntegerPropertyType_ACCOUNT_LOGIN:
+		v = int64(cfg.Login)
+	case pb.AccountInfoIntegerPropertyType_ACCOUNT_TRADE_MODE:
+		v = int64(cfg.TradeMode)
+	case pb.AccountInfoIntegerPropertyType_ACCOUNT_LEVERAGE:
+		v = cfg.Leverage
+	case pb.AccountInfoIntegerPropertyType_ACCOUNT_TRADE_ALLOWED, pb.AccountInfoIntegerPropertyType_ACCOUNT_TRADE_EXPERT,
+		pb.AccountInfoIntegerPropertyType_ACCOUNT_HEDGE_ALLOWED:
+		v = 1
+	case pb.AccountInfoIntegerPropertyType_ACCOUNT_MARGIN_MODE:
+		v = 2 // ACCOUNT_MARGIN_MODE_RETAIL_HEDGING
+	case pb.AccountInfoIntegerPropertyType_ACCOUNT_CURRENCY_DIGITS:
+		v = 2
+	}
+	return &pb.AccountInfoIntegerReply{Response: &pb.AccountInfoIntegerReply_Data{Data: &pb.AccountInfoIntegerData{RequestedValue: v}}}, nil
+}
+
+func (a accountInformationService) AccountInfoString(ctx context.Context, req *pb.AccountInfoStringRequest) (*pb.AccountInfoStringReply, error) {
+	var v string
+	switch req.GetPropertyId() {
+	case pb.AccountInfoStringPropertyType_ACCOUNT_NAME:
+		v = "Fake Trader"
+	case pb.AccountInfoStringPropertyType_ACCOUNT_SERVER:
+		v = "fakeserver"
+	case pb.AccountInfoStringPropertyType_ACCOUNT_CURRENCY:
+		v = a.s.cfg.Currency
+	case pb.AccountInfoStringPropertyType_ACCOUNT_COMPANY:
+		v = "fakeserver"
+	}
+	return &pb.AccountInfoStringReply{Response: &pb.AccountInfoStringReply_Data{Data: &pb.AccountInfoStringData{RequestedValue: v}}}, nil
+}
+
+// ═══════════════════════════════════════════════════════════════
+// TRADING
+// ═══════════════════════════════════════════════════════════════
+
+type tradingService struct {
+	pb.UnimplementedTradingHelperServer
+	s *Server
+}
+
+func (t tradingService) OrderSend(ctx context.Context, req *pb.OrderSendRequest) (*pb.OrderSendReply, error) {
+	r := t.s.engine.send(req)
+	return &pb.OrderSendReply{Response: &pb.OrderSendReply_Data{Data: &pb.OrderSendData{
+		ReturnedCode: r.code, Deal: r.deal, Order: r.order, Volume: r.volume, Price: r.price,
+		Bid: r.bid, Ask: r.ask, Comment: r.comment,
+	}}}, nil
+}
+
+func (t tradingService) OrderModify(ctx context.Context, req *pb.OrderModifyRequest) (*pb.OrderModifyReply, error) {
+	r := t.s.engine.modify(req)
+	return &pb.OrderModifyReply{Response: &pb.OrderModifyReply_Data{Data: &pb.OrderModifyData{
+		ReturnedCode: r.code, Order: r.order, Volume: r.volume, Price: r.price,
+		Bid: r.bid, Ask: r.ask, Comment: r.comment,
+	}}}, nil
+}
+
+func (t tradingService) OrderClose(ctx context.Context, req *pb.OrderCloseRequest) (*pb.OrderCloseReply, error) {
+	r, mode := t.s.engine.close(req)
+	return &pb.OrderCloseReply{Response: &pb.OrderCloseReply_Data{Data: &pb.OrderCloseData{
+		ReturnedCode: r.code, ReturnedCodeDescription: r.comment, CloseMode: mode,
+	}}}, nil
+}
+
+// ═══════════════════════════════════════════════════════════════
+// MARKET INFO
+// ═══════════════════════════════════════════════════════════════
+
+type marketInfoService struct {
+	pb.UnimplementedMarketInfoServer
+	s *Server
+}
+
+func (m marketInfoService) SymbolsTotal(ctx context.Context, req *pb.SymbolsTotalRequest) (*pb.SymbolsTotalReply, error) {
+	return &pb.SymbolsTotalReply{Response: &pb.SymbolsTotalReply_Data{Data: &pb.SymbolsTotalData{Total: int32(len(m.s.engine.names))}}}, nil
+}
+
+func (m marketInfoService) SymbolName(ctx context.Context, req *pb.SymbolNameRequest) (*pb.SymbolNameReply, error) {
+	names := m.s.engine.names
+	if req.GetIndex() < 0 || int(req.GetIndex()) >= len(names) {
+		return &pb.SymbolNameReply{Response: &pb.SymbolNameReply_Error{Error: apiError("SYMBOL_NOT_FOUND", "symbol index out of range")}}, nil
+	}
+	return &pb.SymbolNameReply{Response: &pb.SymbolNameReply_Data{Data: &pb.SymbolNameData{Name: names[req.GetIndex()]}}}, nil
+}
+
+func (m marketInfoService) SymbolExist(ctx context.Context, req *pb.SymbolExistRequest) (*pb.SymbolExistReply, error) {
+	_, ok := m.s.engine.quote(req.GetName())
+	return &pb.SymbolExistReply{Response: &pb.SymbolExistReply_Data{Data: &pb.SymbolExistData{Exists: ok}}}, nil
+}
+
+func (m marketInfoService) SymbolSelect(ctx context.Context, req *pb.SymbolSelectRequest) (*pb.SymbolSelectReply, error) {
+	_, ok := m.s.engine.quote(req.GetSymbol())
+	return &pb.SymbolSelectReply{Response: &pb.SymbolSelectReply_Data{Data: &pb.SymbolSelectData{Success: ok}}}, nil
+}
+
+func (m marketInfoService) SymbolInfoTick(ctx context.Context, req *pb.SymbolInfoTickRequest) (*pb.SymbolInfoTickRequestReply, error) {
+	q, ok := m.s.engine.quote(req.GetSymbol())
+	if !ok {
+		return &pb.SymbolInfoTickRequestReply{Response: &pb.SymbolInfoTickRequestReply_Error{Error: unknownSymbol(req.GetSymbol())}}, nil
+	}
+	return &pb.SymbolInfoTickRequestReply{Response: &pb.SymbolInfoTickRequestReply_Data{Data: &pb.MrpcMqlTick{
+		Time: q.time.Unix(), Bid: q.bid, Ask: q.ask, TimeMsc: q.time.UnixMilli(),
+	}}}, nil
+}
+
+func (m marketInfoService) SymbolInfoDouble(ctx context.Context, req *pb.SymbolInfoDoubleRequest) (*pb.SymbolInfoDoubleReply, error) {
+	q, ok := m.s.engine.quote(req.GetSymbol())
+	if !ok {
+		return &pb.SymbolInfoDoubleReply{Response: &pb.SymbolInfoDoubleReply_Error{Error: unknownSymbol(req.GetSymbol())}}, nil
+	}
+	var v float64
+	switch req.GetType() {
+	case pb.SymbolInfoDoubleProperty_SYMBOL_BID:
+		v = q.bid
+	case pb.SymbolInfoDoubleProperty_SYMBOL_ASK:
+		v = q.ask
+	case pb.SymbolInfoDoubleProperty_SYMBOL_POINT, pb.SymbolInfoDoubleProperty_SYMBOL_TRADE_TICK_SIZE:
+		v = q.Point()
+	case pb.SymbolInfoDoubleProperty_SYMBOL_TRADE_TICK_VALUE, pb.SymbolInfoDoubleProperty_SYMBOL_TRADE_TICK_VALUE_PROFIT,
+		pb.SymbolInfoDoubleProperty_SYMBOL_TRADE_TICK_VALUE_LOSS:
+		v = q.Point() * q.contract()
+	case pb.SymbolInfoDoubleProperty_SYMBOL_TRADE_CONTRACT_SIZE:
+		v = q.contract()
+	case pb.SymbolInfoDoubleProperty_SYMBOL_VOLUME_MIN:
+		v = orDefault(q.VolumeMin, 0.01)
+	case pb.SymbolInfoDoubleProperty_SYMBOL_VOLUME_MAX:
+		v = orDefault(q.VolumeMax, 100)
+	case pb.SymbolInfoDoubleProperty_SYMBOL_VOLUME_STEP:
+		v = orDefault(q.VolumeStep, 0.01)
+	}
+	return &pb.SymbolInfoDoubleReply{Response: &pb.SymbolInfoDoubleReply_Data{Data: &pb.SymbolInfoDoubleData{Value: v}}}, nil
+}
+
+func (m marketInfoService) SymbolInfoInteger(ctx context.Context, req *pb.SymbolInfoIntegerRequest) (*pb.SymbolInfoIntegerReply, error) {
+	q, ok := m.s.engine.quote(req.GetSymbol())
+	if !ok {
+		return &pb.SymbolInfoIntegerReply{Response: &pb.SymbolInfoIntegerReply_Error{Error: unknownSymbol(req.GetSymbol())}}, nil
+	}
+	var v int64
+	switch req.GetType() {
+	case pb.SymbolInfoIntegerProperty_SYMBOL_DIGITS:
+		v = int64(q.Digits)
+	case pb.SymbolInfoIntegerProperty_SYMBOL_SPREAD:
+		v = int64((q.ask-q.bid)/q.Point() + 0.5)
+	case pb.SymbolInfoIntegerProperty_SYMBOL_SPREAD_FLOAT, pb.SymbolInfoIntegerProperty_SYMBOL_EXIST,
+		pb.SymbolInfoIntegerProperty_SYMBOL_SELECT, pb.SymbolInfoIntegerProperty_SYMBOL_VISIBLE:
+		v = 1
+	case pb.SymbolInfoIntegerProperty_SYMBOL_TRADE_STOPS_LEVEL:
+		v = int64(q.StopsLevel)
+	case pb.SymbolInfoIntegerProperty_SYMBOL_TRADE_MODE:
+		v = int64(pb.BMT5_ENUM_SYMBOL_TRADE_MODE_BMT5_SYMBOL_TRADE_MODE_FULL)
+	case pb.SymbolInfoIntegerProperty_SYMBOL_TIME:
+		v = q.time.Unix()
+	case pb.SymbolInfoIntegerProperty_SYMBOL_TIME_MSC:
+		v = q.time.UnixMilli()
+	case pb.SymbolInfoIntegerProperty_SYMBOL_FILLING_MODE:
+		v = 3 // FOK | IOC
+	}
+	return &pb.SymbolInfoIntegerReply{Response: &pb.SymbolInfoIntegerReply_Data{Data: &pb.SymbolInfoIntegerData{Value: v}}}, nil
+}
+
+func (m marketInfoService) SymbolInfoString(ctx context.Context, req *pb.SymbolInfoStringRequest) (*pb.SymbolInfoStringReply, error) {
+	q, ok := m.s.engine.quote(req.GetSymbol())
+	if !ok {
+		return &pb.SymbolInfoStringReply{Response: &pb.SymbolInfoStringReply_Error{Error: unknownSymbol(req.GetSymbol())}}, nil
+	}
+	base, profit := q.Name, q.Name
+	if len(q.Name) >= 6 {
+		base, profit = q.Name[:3], q.Name[3:6]
+	}
+	var v string
+	switch req.GetType() {
+	case pb.SymbolInfoStringProperty_SYMBOL_CURRENCY_BASE, pb.SymbolInfoStringProperty_SYMBOL_CURRENCY_MARGIN:
+		v = base
+	case pb.SymbolInfoStringProperty_SYMBOL_CURRENCY_PROFIT:
+		v = profit
+	case pb.SymbolInfoStringProperty_SYMBOL_DESCRIPTION:
+		v = q.Name + " (fake)"
+	}
+	return &pb.SymbolInfoStringReply{Response: &pb.SymbolInfoStringReply_Data{Data: &pb.SymbolInfoStringData{Value: v}}}, nil
+}
+
+func unknownSymbol(symbol string) *pb.Error {
+	return apiError("SYMBOL_NOT_FOUND", "unknown symbol "+symbol)
+}
+
+// ═══════════════════════════════════════════════════════════════
+// SUBSCRIPTIONS
+// ═══════════════════════════════════════════════════════════════
+
+type subscriptionService struct {
+	pb.UnimplementedSubscriptionServiceServer
+	s *Server
+}
+
+// errStreamDropped ends a stream on DropStreams.
+var errStreamDropped = status.Error(codes.Unavailable, "fakeserver: stream dropped")
+
+func (sub subscriptionService) OnSymbolTick(req *pb.OnSymbolTickRequest, stream pb.SubscriptionService_OnSymbolTickServer) error {
+	want := make(map[string]bool)
+	for _, name := range req.GetSymbolNames() {
+		want[name] = true
+	}
+	return sub.follow(stream, &sub.s.ticks, func(ev any) error {
+		tick := ev.(*pb.MrpcSubscriptionMqlTick)
+		if len(want) > 0 && !want[tick.Symbol] {
+			return nil
+		}
+		return stream.Send(&pb.OnSymbolTickReply{Response: &pb.OnSymbolTickReply_Data{Data: &pb.OnSymbolTickData{SymbolTick: tick}}})
+	})
+}
+
+func (sub subscriptionService) OnTrade(req *pb.OnTradeRequest, stream pb.SubscriptionService_OnTradeServer) error {
+	return sub.follow(stream, &sub.s.trades, func(ev any) error {
+		return stream.Send(&pb.OnTradeReply{Response: &pb.OnTradeReply_Data{Data: &pb.OnTradeData{
+			Type:        pb.MT5_SUB_ENUM_EVENT_GROUP_TYPE_OrderUpdate,
+			EventData:   ev.(*pb.OnTadeEventData),
+			AccountInfo: sub.accountInfo(),
+		}}})
+	})
+}
+
+func (sub subscriptionService) OnPositionProfit(req *pb.OnPositionProfitRequest, stream pb.SubscriptionService_OnPositionProfitServer) error {
+	last := make(map[uint64]*pb.OnPositionProfitPositionInfo)
+	return sub.every(stream, req.GetTimerPeriodMilliseconds(), func() error {
+		now := sub.s.engine.positionProfits()
+		data := &pb.OnPositionProfitData{Type: pb.MT5_SUB_ENUM_EVENT_GROUP_TYPE_OrderProfit, AccountInfo: sub.accountInfo()}
+		for _, ticket := range sortedKeys(now) {
+			prev, ok := last[ticket]
+			switch {
+			case !ok:
+				data.NewPositions = append(data.NewPositions, now[ticket])
+			case prev.Profit != now[ticket].Profit:
+				data.UpdatedPositions = append(data.UpdatedPositions, now[ticket])
+			}
+		}
+		for _, ticket := range sortedKeys(last) {
+			if _, ok := now[ticket]; !ok {
+				data.DeletedPositions = append(data.DeletedPositions, last[ticket])
+			}
+		}
+		last = now
+		empty := len(data.NewPositions)+len(data.UpdatedPositions)+len(data.DeletedPositions) == 0
+		if empty && req.GetIgnoreEmptyData() {
+			return nil
+		}
+		return stream.Send(&pb.OnPositionProfitReply{Response: &pb.OnPositionProfitReply_Data{Data: data}})
+	})
+}
+
+func (sub subscriptionService) OnPositionsAndPendingOrdersTickets(req *pb.OnPositionsAndPendingOrdersTicketsRequest, stream pb.SubscriptionService_OnPositionsAndPendingOrdersTicketsServer) error {
+	return sub.every(stream, req.GetTimerPeriodMilliseconds(), func() error {
+		positions, orders := sub.s.engine.tickets()
+		return stream.Send(&pb.OnPositionsAndPendingOrdersTicketsReply{Response: &pb.OnPositionsAndPendingOrdersTicketsReply_Data{
+			Data: &pb.OnPositionsAndPendingOrdersTicketsData{
+				PositionTickets:     positions,
+				PendingOrderTickets: orders,
+				ServerTime:          timestamppb.Now(),
+			},
+		}})
+	})
+}
+
+// follow sends the events of h until the client leaves or DropStreams.
+func (sub subscriptionService) follow(stream grpc.ServerStream, h *hub, send func(ev any) error) error {
+	events, cancel := h.subscribe()
+	defer cancel()
+	drop := sub.s.dropped()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-drop:
+			return errStreamDropped
+		case ev := <-events:
+			if err := send(ev); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// every calls send each period (ms, 0 = defaultStreamPeriod), first
+// immediately, until the client leaves or DropStreams.
+func (sub subscriptionService) every(stream grpc.ServerStream, periodMs int32, send func() error) error {
+	period := time.Duration(periodMs) * time.Millisecond
+	if period <= 0 {
+		period = defaultStreamPeriod
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	drop := sub.s.dropped()
+	for {
+		if err := send(); err != nil {
+			return err
+		}
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-drop:
+			return errStreamDropped
+		case <-ticker.C:
+		}
+	}
+}
+
+func (sub subscriptionService) accountInfo() *pb.OnEventAccountInfo {
+	acc := sub.s.engine.account()
+	return &pb.OnEventAccountInfo{
+		Balance:     acc.Balance,
+		Equity:      acc.Equity,
+		Margin:      acc.Margin,
+		FreeMargin:  acc.FreeMargin,
+		Profit:      acc.Profit,
+		MarginLevel: acc.MarginLevel,
+		Login:       int64(sub.s.cfg.Login),
+	}
+}
+
+// ═══════════════════════════════════════════════════════════════
+// HELPERS
+// ═══════════════════════════════════════════════════════════════
+
+// timeOf converts an optional timestamp (nil = zero time).
+func timeOf(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func orDefault(v, def float64) float64 {
+	if v == 0 {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,373 @@
+package mt5
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Fixture is one recorded RPC: its request, the reply (unary) or every
+// received message (stream) and the status the call ended with. Messages
+// are stored as protojson, so fixture files can be read and edited by hand.
+type Fixture struct {
+	Method  string            `json:"method"` // Full gRPC method, e.g. "/mt5_term_api.MarketInfo/SymbolInfoTick"
+	Stream  bool              `json:"stream,omitempty"`
+	Request json.RawMessage   `json:"request,omitempty"`
+	Replies []json.RawMessage `json:"replies,omitempty"`
+	Code    codes.Code        `json:"code,omitempty"` // Final status; OK ends a stream with io.EOF
+	Message string            `json:"message,omitempty"`
+}
+
+// LoadFixtures reads fixtures written by Recorder.Save.
+func LoadFixtures(path string) ([]Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read fixtures failed: %w", err)
+	}
+	var fixtures []Fixture
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("parse fixtures %s failed: %w", path, err)
+	}
+	return fixtures, nil
+}
+
+// ═══════════════════════════════════════════════════════════════
+// RECORDING
+// ═══════════════════════════════════════════════════════════════
+
+// Recorder captures the RPCs of a live account as fixtures. Install it
+// through AccountOptions.DialOptions:
+//
+//	rec := mt5.NewRecorder()
+//	account, _ := mt5.NewMT5AccountWithOptions(user, password, server, uuid.New(),
+//	    mt5.AccountOptions{DialOptions: rec.DialOptions()})
+//	... run the scenario ...
+//	rec.Save("testdata/scenario.json")
+//
+// Request fields whose name contains "password" are cleared before they are
+// stored. Unary calls are stored when they return, streams when they end
+// (including by cancellation), so stream fixtures follow the unary calls
+// made while they were open.
+type Recorder struct {
+	mu       sync.Mutex
+	fixtures []Fixture
+}
+
+// NewRecorder returns an empty recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// DialOptions returns the interceptors that feed the recorder.
+func (r *Recorder) DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(r.unary),
+		grpc.WithChainStreamInterceptor(r.stream),
+	}
+}
+
+// Fixtures returns a copy of the fixtures recorded so far.
+func (r *Recorder) Fixtures() []Fixture {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Fixture(nil), r.fixtures...)
+}
+
+// Save writes the fixtures recorded so far to path as indented JSON.
+func (r *Recorder) Save(path string) error {
+	data, err := json.MarshalIndent(r.Fixtures(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode fixtures failed: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write fixtures failed: %w", err)
+	}
+	return nil
+}
+
+func (r *Recorder) add(f Fixture) {
+	r.mu.Lock()
+	r.fixtures = append(r.fixtures, f)
+	r.mu.Unlock()
+}
+
+func (r *Recorder) unary(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	err := invoker(ctx, method, req, reply, cc, opts...)
+
+	st := status.Convert(err)
+	f := Fixture{Method: method, Request: encodeRequest(req), Code: st.Code(), Message: st.Message()}
+	if err == nil {
+		f.Replies = []json.RawMessage{encodeMessage(reply)}
+	}
+	r.add(f)
+	return err
+}
+
+func (r *Recorder) stream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	cs, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		st := status.Convert(err)
+		r.add(Fixture{Method: method, Stream: true, Code: st.Code(), Message: st.Message()})
+		return nil, err
+	}
+	return &recordingStream{ClientStream: cs, rec: r, fixture: Fixture{Method: method, Stream: true}}, nil
+}
+
+// recordingStream copies the request and every received message of a stream.
+type recordingStream struct {
+	grpc.ClientStream
+	rec *Recorder
+
+	mu      sync.Mutex
+	fixture Fixture
+	done    bool
+}
+
+func (s *recordingStream) SendMsg(m any) error {
+	s.mu.Lock()
+	s.fixture.Request = encodeRequest(m)
+	s.mu.Unlock()
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *recordingStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done {
+		return err
+	}
+	if err == nil {
+		s.fixture.Replies = append(s.fixture.Replies, encodeMessage(m))
+		return nil
+	}
+	if !errors.Is(err, io.EOF) {
+		st := status.Convert(err)
+		s.fixture.Code, s.fixture.Message = st.Code(), st.Message()
+	}
+	s.done = true
+	s.rec.add(s.fixture)
+	return err
+}
+
+// ═══════════════════════════════════════════════════════════════
+// REPLAY
+// ═══════════════════════════════════════════════════════════════
+
+// Replayer answers RPCs from fixtures instead of a server. Each call takes
+// the first unused fixture of its method, so a scenario replays in the
+// order it was recorded. A call without a fixture fails with Unimplemented.
+type Replayer struct {
+	// MatchRequests also requires the request to equal the recorded one
+	// (password fields ignored). Off by default: requests that carry the
+	// current time, like history ranges, never match a recording.
+	MatchRequests bool
+
+	mu       sync.Mutex
+	fixtures []Fixture
+	used     []bool
+}
+
+// NewReplayer returns a replayer serving fixtures.
+func NewReplayer(fixtures []Fixture) *Replayer {
+	return &Replayer{fixtures: fixtures, used: make([]bool, len(fixtures))}
+}
+
+// Remaining returns the fixtures no call has used yet. A test that expects
+// its scenario to be replayed completely checks that this is empty.
+func (p *Replayer) Remaining() []Fixture {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var left []Fixture
+	for i, f := range p.fixtures {
+		if !p.used[i] {
+			left = append(left, f)
+		}
+	}
+	return left
+}
+
+// NewReplayAccount returns an account whose RPCs are answered by p. No
+// network connection is made; wrap it with NewMT5Service or
+// NewMT5SugarFromAccount to test the higher layers offline:
+//
+//	fixtures, _ := mt5.LoadFixtures("testdata/scenario.json")
+//	account, _ := mt5.NewReplayAccount(user, "", uuid.New(), mt5.NewReplayer(fixtures))
+func NewReplayAccount(user uint64, password string, id uuid.UUID, p *Replayer) (*MT5Account, error) {
+	conn, err := grpc.NewClient("passthrough:///replay",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(p.unary),
+		grpc.WithStreamInterceptor(p.stream),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("replay client failed: %w", err)
+	}
+	opts := AccountOptions{}
+	return newAccount(user, password, "replay", id, conn, opts.keepaliveParams(), opts), nil
+}
+
+// next takes the fixture for a call.
+func (p *Replayer) next(method string, req any) (Fixture, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, f := range p.fixtures {
+		if p.used[i] || f.Method != method {
+			continue
+		}
+		if p.MatchRequests && !requestMatches(f.Request, req) {
+			continue
+		}
+		p.used[i] = true
+		return f, nil
+	}
+	return Fixture{}, status.Errorf(codes.Unimplemented, "replay: no fixture left for %s", method)
+}
+
+func (p *Replayer) unary(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	f, err := p.next(method, req)
+	if err != nil {
+		return err
+	}
+	if f.Code != codes.OK {
+		return status.Error(f.Code, f.Message)
+	}
+	if len(f.Replies) == 0 {
+		return nil
+	}
+	return decodeMessage(f.Replies[0], reply)
+}
+
+func (p *Replayer) stream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return &replayStream{ctx: ctx, method: method, replayer: p}, nil
+}
+
+// replayStream plays back one stream fixture. The fixture is picked on the
+// first RecvMsg, once the request has been sent.
+type replayStream struct {
+	ctx      context.Context
+	method   string
+	replayer *Replayer
+	req      any
+	fixture  *Fixture
+	pos      int
+}
+
+func (s *replayStream) Header() (metadata.MD, error) { return nil, nil }
+func (s *replayStream) Trailer() metadata.MD         { return nil }
+func (s *replayStream) CloseSend() error             { return nil }
+func (s *replayStream) Context() context.Context     { return s.ctx }
+
+func (s *replayStream) SendMsg(m any) error {
+	s.req = m
+	return nil
+}
+
+func (s *replayStream) RecvMsg(m any) error {
+	if s.fixture == nil {
+		f, err := s.replayer.next(s.method, s.req)
+		if err != nil {
+			return err
+		}
+		s.fixture = &f
+	}
+	if err := s.ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+
+	if s.pos < len(s.fixture.Replies) {
+		reply := s.fixture.Replies[s.pos]
+		s.pos++
+		return decodeMessage(reply, m)
+	}
+
+	switch s.fixture.Code {
+	case codes.OK:
+		return io.EOF
+	case codes.Canceled, codes.DeadlineExceeded:
+		// Recorded until the caller gave up: stay open until it does again
+		<-s.ctx.Done()
+		return status.FromContextError(s.ctx.Err()).Err()
+	}
+	return status.Error(s.fixture.Code, s.fixture.Message)
+}
+
+// ═══════════════════════════════════════════════════════════════
+// ENCODING
+// ═══════════════════════════════════════════════════════════════
+
+// encodeMessage returns m as protojson, or nil if m is not a message.
+func encodeMessage(m any) json.RawMessage {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return nil
+	}
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// encodeRequest is encodeMessage with password fields cleared.
+func encodeRequest(m any) json.RawMessage {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return nil
+	}
+	return encodeMessage(redact(msg))
+}
+
+// decodeMessage fills m from protojson data.
+func decodeMessage(data json.RawMessage, m any) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "replay: %T is not a protobuf message", m)
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, msg); err != nil {
+		return status.Errorf(codes.Internal, "replay: decode %T failed: %v", m, err)
+	}
+	return nil
+}
+
+// redact returns a copy of msg with top-level password fields cleared.
+func redact(msg proto.Message) proto.Message {
+	clone := proto.Clone(msg)
+	r := clone.ProtoReflect()
+	fields := r.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if fd := fields.Get(i); strings.Contains(strings.ToLower(string(fd.Name())), "password") {
+			r.Clear(fd)
+		}
+	}
+	return clone
+}
+
+// requestMatches reports whether req equals the recorded request.
+func requestMatches(recorded json.RawMessage, req any) bool {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return recorded == nil
+	}
+	want := msg.ProtoReflect().New().Interface()
+	if len(recorded) > 0 {
+		if err := protojson.Unmarshal(recorded, want); err != nil {
+			return false
+		}
+	}
+	return proto.Equal(want, redact(msg))
+}
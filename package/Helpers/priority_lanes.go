@@ -0,0 +1,151 @@
+package mt5
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultMaxDefer is how long a background call waits for trades when
+// LaneConfig.MaxDefer is zero.
+const DefaultMaxDefer = 5 * time.Second
+
+// LaneConfig schedules RPCs sharing one connection in two lanes.
+//
+// Trade lane: OrderSend, OrderModify and OrderClose - never delayed.
+// Background lane: OrderHistory, PositionsHistory and SymbolParamsMany -
+// the large, paged replies that otherwise occupy the connection while an
+// order is waiting. A background call does not start while a trade RPC is in
+// flight; it waits until the trade lane is idle, at most MaxDefer (then it
+// runs anyway, so a steady stream of trades cannot starve history). Paging
+// loops issue one call per page, so they pause between pages.
+//
+// The lanes only order calls of this account; a separate data connection
+// (UseDataEndpoint) removes the contention entirely.
+type LaneConfig struct {
+	MaxDefer      time.Duration // Longest wait for an idle trade lane (0 = DefaultMaxDefer)
+	MaxBackground int           // Concurrent background calls (0 = unlimited)
+}
+
+// LaneStats is a snapshot of the lane scheduler.
+type LaneStats struct {
+	TradesInFlight     int           // Trade RPCs running now
+	BackgroundInFlight int           // Background RPCs running now
+	Deferred           uint64        // Background calls that had to wait for trades
+	DeferredTime       time.Duration // Total time background calls waited
+	Overdue            uint64        // Background calls started after MaxDefer with trades still in flight
+}
+
+// laneScheduler holds background calls back while trades are in flight.
+type laneScheduler struct {
+	cfg   LaneConfig
+	slots chan struct{} // MaxBackground tokens, nil = unlimited
+
+	mu    sync.Mutex
+	idle  chan struct{} // Closed while no trade is in flight
+	stats LaneStats
+}
+
+// SetPriorityLanes enables priority lanes for this account (see LaneConfig).
+// Call before trading starts; calling again replaces the scheduler.
+func (a *MT5Account) SetPriorityLanes(cfg LaneConfig) {
+	if cfg.MaxDefer <= 0 {
+		cfg.MaxDefer = DefaultMaxDefer
+	}
+	l := &laneScheduler{cfg: cfg, idle: make(chan struct{})}
+	close(l.idle)
+	if cfg.MaxBackground > 0 {
+		l.slots = make(chan struct{}, cfg.MaxBackground)
+	}
+	a.lanes = l
+}
+
+// LaneStats returns the scheduler counters and false if lanes are off.
+func (a *MT5Account) LaneStats() (LaneStats, bool) {
+	if a.lanes == nil {
+		return LaneStats{}, false
+	}
+	a.lanes.mu.Lock()
+	defer a.lanes.mu.Unlock()
+	return a.lanes.stats, true
+}
+
+// tradeLane marks a trade RPC in flight until the returned func is called.
+func (a *MT5Account) tradeLane() func() {
+	l := a.lanes
+	if l == nil {
+		return func() {}
+	}
+	l.mu.Lock()
+	if l.stats.TradesInFlight == 0 {
+		l.idle = make(chan struct{})
+	}
+	l.stats.TradesInFlight++
+	l.mu.Unlock()
+
+	return func() {
+		l.mu.Lock()
+		l.stats.TradesInFlight--
+		if l.stats.TradesInFlight == 0 {
+			close(l.idle)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// backgroundLane waits until a background RPC may start and returns the
+// func releasing its slot. It fails only when ctx is done.
+func (a *MT5Account) backgroundLane(ctx context.Context) (func(), error) {
+	l := a.lanes
+	if l == nil {
+		return func() {}, nil
+	}
+
+	l.mu.Lock()
+	idle, busy := l.idle, l.stats.TradesInFlight > 0
+	l.mu.Unlock()
+
+	if busy {
+		start := time.Now()
+		timer := time.NewTimer(l.cfg.MaxDefer)
+		overdue := false
+		select {
+		case <-idle:
+		case <-timer.C:
+			overdue = true
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+		timer.Stop()
+
+		l.mu.Lock()
+		l.stats.Deferred++
+		l.stats.DeferredTime += time.Since(start)
+		if overdue {
+			l.stats.Overdue++
+		}
+		l.mu.Unlock()
+	}
+
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	l.mu.Lock()
+	l.stats.BackgroundInFlight++
+	l.mu.Unlock()
+
+	return func() {
+		l.mu.Lock()
+		l.stats.BackgroundInFlight--
+		l.mu.Unlock()
+		if l.slots != nil {
+			<-l.slots
+		}
+	}, nil
+}
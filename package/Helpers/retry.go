@@ -0,0 +1,96 @@
+package mt5
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy controls the delay between retries of unary calls
+// (MT5Account.RetryPolicy) and stream reconnects (StreamOptions.Retry).
+// A zero policy means the applicable default. Otherwise only BaseDelay,
+// MaxAttempts and Jitter fall back to the default when zero; set Jitter to
+// NoJitter for exact delays.
+type RetryPolicy struct {
+	BaseDelay   time.Duration // Delay before the first retry
+	Multiplier  float64       // Delay growth per retry (0 or 1 = fixed delay)
+	MaxDelay    time.Duration // Upper bound on the delay (0 = no cap)
+	MaxAttempts int           // Consecutive retries before giving up (0 = unlimited)
+	Jitter      float64       // Random ± fraction of each delay (0.25 = ±25%, NoJitter = off)
+}
+
+// NoJitter disables jitter in a RetryPolicy.
+const NoJitter = -1
+
+// DefaultRetryPolicy is used for unary calls: 500ms doubling up to 5s, ±25%.
+var DefaultRetryPolicy = RetryPolicy{
+	BaseDelay:  500 * time.Millisecond,
+	Multiplier: 2,
+	MaxDelay:   5 * time.Second,
+	Jitter:     0.25,
+}
+
+// DefaultStreamRetryPolicy is used for stream reconnects: fixed 500ms, ±50%.
+var DefaultStreamRetryPolicy = RetryPolicy{
+	BaseDelay:  500 * time.Millisecond,
+	Multiplier: 1,
+	MaxDelay:   500 * time.Millisecond,
+	Jitter:     0.5,
+}
+
+// StreamReconnect is passed to StreamOptions.OnStreamReconnect before each reconnect.
+type StreamReconnect struct {
+	Stream  string        // Stream kind (e.g., "OnSymbolTick")
+	Attempt int           // Consecutive reconnect attempt, 1-based (reset after data arrives)
+	Total   uint64        // Reconnects over the subscription's lifetime
+	Delay   time.Duration // Wait before this attempt
+	Err     error         // Why the stream was re-opened (nil if the server closed it)
+}
+
+// withDefaults returns def for a zero policy and otherwise fills BaseDelay,
+// MaxAttempts and Jitter from def. Multiplier and MaxDelay stay the
+// caller's, so a longer BaseDelay is never capped by the default MaxDelay.
+func (p RetryPolicy) withDefaults(def RetryPolicy) RetryPolicy {
+	if p == (RetryPolicy{}) {
+		return def
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = def.BaseDelay
+	}
+	if p.Multiplier <= 0 {
+		p.Multiplier = 1
+	}
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = def.MaxAttempts
+	}
+	if p.Jitter == 0 {
+		p.Jitter = def.Jitter
+	}
+	return p
+}
+
+// Delay returns the wait before retry number attempt (1-based), jitter included.
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := float64(p.BaseDelay) * math.Pow(p.Multiplier, float64(attempt-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delay)
+}
+
+// Exhausted reports whether attempt exceeds MaxAttempts.
+func (p RetryPolicy) Exhausted(attempt int) bool {
+	return p.MaxAttempts > 0 && attempt > p.MaxAttempts
+}
+
+// errRetriesExhausted wraps the last error after MaxAttempts retries.
+func errRetriesExhausted(attempts int, last error) error {
+	return fmt.Errorf("giving up after %d retries: %w", attempts, last)
+}
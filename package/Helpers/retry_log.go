@@ -0,0 +1,126 @@
+package mt5
+
+import (
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultRetryWarnEvery is how many retries are summarized in one Warn log
+// when MT5Account.RetryWarnEvery is zero.
+const DefaultRetryWarnEvery = 20
+
+// retryKind tells transport retries from API retries.
+type retryKind int
+
+const (
+	retryTransport retryKind = iota // Unavailable / DeadlineExceeded
+	retryAPI                        // TERMINAL_INSTANCE_NOT_FOUND and friends
+)
+
+func (k retryKind) String() string {
+	if k == retryAPI {
+		return "[api-retry]"
+	}
+	return "[grpc-retry]"
+}
+
+// RetryStats counts retries of an MT5Account. During a broker or gateway
+// restart these grow fast; watch them instead of the log.
+type RetryStats struct {
+	Transport        uint64    // Unary retries after Unavailable / DeadlineExceeded
+	API              uint64    // Unary retries after TERMINAL_INSTANCE_NOT_FOUND
+	Exhausted        uint64    // Unary calls that gave up after RetryPolicy.MaxAttempts
+	StreamReconnects uint64    // Stream re-opens (all subscriptions)
+	LastCode         string    // Code of the last retried failure
+	LastAt           time.Time // When the last retry was scheduled
+}
+
+// Total returns all retries and reconnects.
+func (s RetryStats) Total() uint64 {
+	return s.Transport + s.API + s.StreamReconnects
+}
+
+// retryCounter holds the RetryStats counters.
+type retryCounter struct {
+	transport atomic.Uint64
+	api       atomic.Uint64
+	exhausted atomic.Uint64
+	streams   atomic.Uint64
+
+	mu        sync.Mutex
+	lastCode  string
+	lastAt    time.Time
+	sinceWarn int            // Retries since the last summarized Warn
+	codes     map[string]int // Codes seen since the last summarized Warn
+}
+
+// RetryStats returns the retry counters of this account.
+func (a *MT5Account) RetryStats() RetryStats {
+	a.retries.mu.Lock()
+	defer a.retries.mu.Unlock()
+	return RetryStats{
+		Transport:        a.retries.transport.Load(),
+		API:              a.retries.api.Load(),
+		Exhausted:        a.retries.exhausted.Load(),
+		StreamReconnects: a.retries.streams.Load(),
+		LastCode:         a.retries.lastCode,
+		LastAt:           a.retries.lastAt,
+	}
+}
+
+// logger returns the account logger.
+func (a *MT5Account) logger() *slog.Logger {
+	if a.Logger != nil {
+		return a.Logger
+	}
+	return slog.Default()
+}
+
+// logRetry counts a unary retry, logs it at Debug and every RetryWarnEvery
+// retries logs one Warn summarizing them.
+func (a *MT5Account) logRetry(kind retryKind, code, msg string, delay time.Duration) {
+	if kind == retryAPI {
+		a.retries.api.Add(1)
+	} else {
+		a.retries.transport.Add(1)
+	}
+
+	log := a.logger()
+	log.Debug(kind.String(), "code", code, "msg", msg, "next_delay", delay)
+
+	every := a.RetryWarnEvery
+	if every == 0 {
+		every = DefaultRetryWarnEvery
+	}
+
+	r := &a.retries
+	r.mu.Lock()
+	r.lastCode, r.lastAt = code, time.Now()
+	if every < 0 {
+		r.mu.Unlock()
+		return
+	}
+	if r.codes == nil {
+		r.codes = make(map[string]int)
+	}
+	r.codes[code]++
+	r.sinceWarn++
+	if r.sinceWarn < every {
+		r.mu.Unlock()
+		return
+	}
+	codes := r.codes
+	r.sinceWarn, r.codes = 0, nil
+	r.mu.Unlock()
+
+	log.Warn("[retry] calls are being retried", "retries", every, "codes", codes,
+		"total_transport", r.transport.Load(), "total_api", r.api.Load())
+}
+
+// logStreamRetry counts a stream re-open and logs it at Debug.
+func (a *MT5Account) logStreamRetry(stream string, attempt int, cause error, delay time.Duration) {
+	a.retries.streams.Add(1)
+	a.logger().Debug("[stream-retry]", "stream", stream, "attempt", attempt, "err", cause, "next_delay", delay)
+}
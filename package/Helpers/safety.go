@@ -0,0 +1,237 @@
+package mt5
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	pb "git.mtapi.io/root/mrpc-proto/mt5/libraries/go"
+)
+
+// ErrSafetyViolation is wrapped by every error returned by a safety interlock.
+var ErrSafetyViolation = errors.New("safety interlock")
+
+// SafetyConfig limits what trade RPCs an MT5Account may send.
+//
+// The checks run inside OrderSend, OrderModify and OrderClose before anything
+// reaches the server, so no higher layer (Service, Sugar, orchestrators, demo
+// code) can bypass them. Zero values disable the corresponding check, except
+// AllowRealTrading: once a SafetyConfig is installed, trade RPCs on a REAL
+// account are refused unless it is explicitly set.
+type SafetyConfig struct {
+	ReadOnly           bool     // Reject all trade RPCs (monitoring-only sessions)
+	MaxLotsPerOrder    float64  // Max volume of a single OrderSend (0 = unlimited)
+	MaxOrdersPerMinute int      // Max trade RPCs in any 60-second window (0 = unlimited)
+	AllowedSymbols     []string // OrderSend symbol whitelist (empty = all symbols)
+	AllowRealTrading   bool     // Must be true to trade a REAL account (demo/contest always allowed)
+}
+
+// SafetyError describes a blocked trade RPC.
+type SafetyError struct {
+	Operation string // "OrderSend", "OrderModify" or "OrderClose"
+	Reason    string
+}
+
+func (e *SafetyError) Error() string {
+	return fmt.Sprintf("%s: %s blocked: %s", ErrSafetyViolation, e.Operation, e.Reason)
+}
+
+func (e *SafetyError) Unwrap() error {
+	return ErrSafetyViolation
+}
+
+// safetyGuard enforces a SafetyConfig and keeps the rate window.
+type safetyGuard struct {
+	mu      sync.Mutex
+	cfg     SafetyConfig
+	allowed map[string]bool
+	recent  []time.Time // Trade RPCs in the last minute
+}
+
+// SetSafety installs safety interlocks for all trade RPCs of this account.
+// Safe to call while trading; calling again replaces the config and resets
+// the rate window. Calls already past the check are not affected.
+func (a *MT5Account) SetSafety(cfg SafetyConfig) {
+	guard := &safetyGuard{cfg: cfg}
+	if len(cfg.AllowedSymbols) > 0 {
+		guard.allowed = make(map[string]bool, len(cfg.AllowedSymbols))
+		for _, symbol := range cfg.AllowedSymbols {
+			guard.allowed[strings.ToUpper(symbol)] = true
+		}
+	}
+	a.safety.Store(guard)
+}
+
+// Safety returns the active safety config and whether one is installed.
+func (a *MT5Account) Safety() (SafetyConfig, bool) {
+	guard := a.safety.Load()
+	if guard == nil {
+		return SafetyConfig{}, false
+	}
+	return guard.cfg, true
+}
+
+// TradeMode returns the account trade mode (demo, contest, real) detected at
+// connect time, and false if it is not known yet.
+func (a *MT5Account) TradeMode() (pb.MrpcEnumAccountTradeMode, bool) {
+	v := a.tradeMode.Load()
+	if v == 0 {
+		return 0, false
+	}
+	return pb.MrpcEnumAccountTradeMode(v - 1), true
+}
+
+// detectTradeMode reads ACCOUNT_TRADE_MODE and caches it.
+func (a *MT5Account) detectTradeMode(ctx context.Context) error {
+	data, err := a.AccountInfoInteger(ctx, &pb.AccountInfoIntegerRequest{
+		PropertyId: pb.AccountInfoIntegerPropertyType_ACCOUNT_TRADE_MODE,
+	})
+	if err != nil {
+		return err
+	}
+	a.tradeMode.Store(int32(data.GetRequestedValue()) + 1)
+	return nil
+}
+
+// checkRealAccount refuses trade RPCs on a real account without AllowRealTrading.
+// An unknown trade mode is detected first; if that fails the call is refused.
+func (a *MT5Account) checkRealAccount(ctx context.Context, guard *safetyGuard, operation string) error {
+	if guard.cfg.AllowRealTrading {
+		return nil
+	}
+	mode, ok := a.TradeMode()
+	if !ok {
+		if err := a.detectTradeMode(ctx); err != nil {
+			return &SafetyError{Operation: operation, Reason: fmt.Sprintf("account trade mode unknown: %v", err)}
+		}
+		mode, _ = a.TradeMode()
+	}
+	if mode == pb.MrpcEnumAccountTradeMode_MRPC_ACCOUNT_TRADE_MODE_REAL {
+		return &SafetyError{Operation: operation, Reason: "real account requires AllowRealTrading"}
+	}
+	return nil
+}
+
+// haltState records why and when the kill switch was engaged.
+type haltState struct {
+	reason string
+	since  time.Time
+}
+
+// Halt engages the account kill switch: every OrderSend and OrderModify is
+// refused until Resume. OrderClose stays allowed so pending orders can still
+// be cancelled and positions flattened. Works with or without SetSafety.
+//
+// Returns true if this call engaged the switch, false if it was already engaged
+// (the original reason is kept).
+func (a *MT5Account) Halt(reason string) bool {
+	return a.halt.CompareAndSwap(nil, &haltState{reason: reason, since: time.Now()})
+}
+
+// Resume releases the kill switch.
+func (a *MT5Account) Resume() {
+	a.halt.Store(nil)
+}
+
+// Halted reports whether the kill switch is engaged, with its reason and time.
+func (a *MT5Account) Halted() (reason string, since time.Time, halted bool) {
+	h := a.halt.Load()
+	if h == nil {
+		return "", time.Time{}, false
+	}
+	return h.reason, h.since, true
+}
+
+// checkHalt refuses new trade RPCs while the kill switch is engaged.
+func (a *MT5Account) checkHalt(operation string) error {
+	if h := a.halt.Load(); h != nil {
+		return &SafetyError{Operation: operation, Reason: fmt.Sprintf("kill switch engaged: %s", h.reason)}
+	}
+	return nil
+}
+
+// CheckOrderSend runs the interlocks OrderSend would apply to req (kill
+// switch, read-only, lot limit, symbol whitelist, real account, rate) without
+// sending it. The call does not count towards MaxOrdersPerMinute.
+//
+// Returns:
+//   - nil if OrderSend would pass the interlocks, or the *SafetyError it would return
+func (a *MT5Account) CheckOrderSend(ctx context.Context, req *pb.OrderSendRequest) error {
+	return a.checkOrderSend(ctx, req, false)
+}
+
+// checkOrderSend applies all interlocks to a new order; record counts it
+// towards the rate limit.
+func (a *MT5Account) checkOrderSend(ctx context.Context, req *pb.OrderSendRequest, record bool) error {
+	if err := a.checkHalt("OrderSend"); err != nil {
+		return err
+	}
+	guard := a.safety.Load()
+	if guard == nil {
+		return nil
+	}
+	if guard.cfg.MaxLotsPerOrder > 0 && req.Volume > guard.cfg.MaxLotsPerOrder {
+		return &SafetyError{Operation: "OrderSend", Reason: fmt.Sprintf("volume %.2f exceeds max %.2f lots", req.Volume, guard.cfg.MaxLotsPerOrder)}
+	}
+	if guard.allowed != nil && !guard.allowed[strings.ToUpper(req.Symbol)] {
+		return &SafetyError{Operation: "OrderSend", Reason: fmt.Sprintf("symbol %s not in allowed list", req.Symbol)}
+	}
+	if err := a.checkRealAccount(ctx, guard, "OrderSend"); err != nil {
+		return err
+	}
+	return guard.admit("OrderSend", record)
+}
+
+// checkTradeRPC applies kill switch (modify only), read-only, real-account and
+// rate interlocks to modify/close.
+func (a *MT5Account) checkTradeRPC(ctx context.Context, operation string) error {
+	if operation != "OrderClose" {
+		if err := a.checkHalt(operation); err != nil {
+			return err
+		}
+	}
+	guard := a.safety.Load()
+	if guard == nil {
+		return nil
+	}
+	if err := a.checkRealAccount(ctx, guard, operation); err != nil {
+		return err
+	}
+	return guard.admit(operation, true)
+}
+
+// admit checks read-only mode and the per-minute rate, recording the call
+// if record is set.
+func (g *safetyGuard) admit(operation string, record bool) error {
+	if g.cfg.ReadOnly {
+		return &SafetyError{Operation: operation, Reason: "account is in read-only mode"}
+	}
+	if g.cfg.MaxOrdersPerMinute <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	cutoff := now.Add(-time.Minute)
+	kept := g.recent[:0]
+	for _, t := range g.recent {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	g.recent = kept
+
+	if len(g.recent) >= g.cfg.MaxOrdersPerMinute {
+		return &SafetyError{Operation: operation, Reason: fmt.Sprintf("more than %d trade requests per minute", g.cfg.MaxOrdersPerMinute)}
+	}
+	if record {
+		g.recent = append(g.recent, now)
+	}
+	return nil
+}
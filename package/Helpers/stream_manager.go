@@ -0,0 +1,213 @@
+package mt5
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// StreamInfo describes one open subscription (see MT5Account.ActiveStreams).
+type StreamInfo struct {
+	ID          uint64    // Unique stream ID within the account
+	Name        string    // Stream kind (e.g., "OnSymbolTick")
+	StartedAt   time.Time // When the subscription was opened
+	Messages    uint64    // Messages delivered to the consumer
+	Reconnects  uint64    // Times the underlying gRPC stream was re-opened
+	LastMessage time.Time // Time of the last delivered message (zero if none)
+}
+
+// StreamOptions separates a subscription's lifetime from per-attempt timeouts.
+//
+// By default a subscription runs until its ctx is done (cancelled or past its
+// deadline) and reconnects indefinitely in between. A ctx created for a single
+// call (e.g., "quote within 3s") therefore also bounds every reconnect; use
+// DetachDeadline or MaxLifetime to express the lifetime explicitly instead.
+type StreamOptions struct {
+	DialTimeout    time.Duration // Bound on opening the stream per attempt; retried on expiry (0 = none)
+	DetachDeadline bool          // Ignore ctx's deadline; only ctx cancellation ends the subscription
+	MaxLifetime    time.Duration // End the subscription after this long, reconnects included (0 = none)
+
+	Retry             RetryPolicy           // Reconnect delays (zero = DefaultStreamRetryPolicy)
+	OnStreamReconnect func(StreamReconnect) // Called before each reconnect (nil = none)
+}
+
+// subscriptionContext derives the context that bounds a whole subscription.
+func subscriptionContext(parent context.Context, opts StreamOptions) (context.Context, context.CancelFunc) {
+	ctx, cancel := parent, context.CancelFunc(func() {})
+
+	if _, hasDeadline := parent.Deadline(); opts.DetachDeadline && hasDeadline {
+		detached, cancelDetached := context.WithCancel(context.WithoutCancel(parent))
+		stop := context.AfterFunc(parent, func() {
+			if errors.Is(parent.Err(), context.Canceled) {
+				cancelDetached()
+			}
+		})
+		ctx = detached
+		cancel = func() {
+			stop()
+			cancelDetached()
+		}
+	}
+
+	if opts.MaxLifetime > 0 {
+		limited, cancelLimited := context.WithTimeout(ctx, opts.MaxLifetime)
+		outer := cancel
+		ctx = limited
+		cancel = func() {
+			cancelLimited()
+			outer()
+		}
+	}
+
+	return ctx, cancel
+}
+
+// StreamManager owns all open subscriptions of an MT5Account.
+//
+// Every stream started through ExecuteStreamWithReconnect is registered here
+// with its own cancel function. CloseAll cancels them and waits until their
+// reader goroutines have exited, so Close/Disconnect never leave leaked
+// readers behind. Streams started while CloseAll runs are refused.
+type StreamManager struct {
+	mu      sync.Mutex
+	nextID  uint64
+	streams map[uint64]*managedStream
+	closing int           // CloseAll calls in progress; no new streams meanwhile
+	drained chan struct{} // Closed when the last stream unregisters during CloseAll
+}
+
+// managedStream holds bookkeeping for one registered stream.
+type managedStream struct {
+	id          uint64
+	name        string
+	startedAt   time.Time
+	cancel      context.CancelFunc
+	messages    atomic.Uint64
+	reconnects  atomic.Uint64
+	lastMessage atomic.Int64
+}
+
+// NewStreamManager creates an empty StreamManager.
+func NewStreamManager() *StreamManager {
+	return &StreamManager{
+		streams: make(map[uint64]*managedStream),
+	}
+}
+
+// register adds a stream and returns its child context.
+// The caller must call unregister when its goroutine exits.
+// While CloseAll runs the stream is refused: the context is already
+// cancelled and the returned *managedStream is nil.
+func (m *StreamManager) register(ctx context.Context, name string) (context.Context, *managedStream) {
+	sctx, cancel := context.WithCancel(ctx)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closing > 0 {
+		cancel()
+		return sctx, nil
+	}
+
+	m.nextID++
+	s := &managedStream{
+		id:        m.nextID,
+		name:      name,
+		startedAt: time.Now(),
+		cancel:    cancel,
+	}
+	m.streams[s.id] = s
+
+	return sctx, s
+}
+
+// unregister removes a stream once its goroutine has exited.
+func (m *StreamManager) unregister(s *managedStream) {
+	m.mu.Lock()
+	delete(m.streams, s.id)
+	if len(m.streams) == 0 && m.drained != nil {
+		close(m.drained)
+		m.drained = nil
+	}
+	m.mu.Unlock()
+
+	s.cancel()
+}
+
+// ActiveStreams returns a snapshot of all open streams, oldest first.
+func (m *StreamManager) ActiveStreams() []StreamInfo {
+	if m == nil {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	infos := make([]StreamInfo, 0, len(m.streams))
+	for _, s := range m.streams {
+		info := StreamInfo{
+			ID:         s.id,
+			Name:       s.name,
+			StartedAt:  s.startedAt,
+			Messages:   s.messages.Load(),
+			Reconnects: s.reconnects.Load(),
+		}
+		if last := s.lastMessage.Load(); last != 0 {
+			info.LastMessage = time.Unix(0, last)
+		}
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+	return infos
+}
+
+// CloseAll cancels every open stream and waits for their goroutines to exit.
+// Returns false if some streams did not finish within timeout.
+func (m *StreamManager) CloseAll(timeout time.Duration) bool {
+	if m == nil {
+		return true
+	}
+
+	m.mu.Lock()
+	if len(m.streams) == 0 {
+		m.mu.Unlock()
+		return true
+	}
+	m.closing++
+	if m.drained == nil {
+		m.drained = make(chan struct{})
+	}
+	drained := m.drained
+	for _, s := range m.streams {
+		s.cancel()
+	}
+	m.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	done := true
+	select {
+	case <-drained:
+	case <-timer.C:
+		done = false
+	}
+
+	m.mu.Lock()
+	m.closing--
+	m.mu.Unlock()
+	return done
+}
+
+// streamName turns a request type like "*pb.OnSymbolTickRequest" into "OnSymbolTick".
+func streamName(typeName string) string {
+	if i := strings.LastIndex(typeName, "."); i >= 0 {
+		typeName = typeName[i+1:]
+	}
+	return strings.TrimSuffix(typeName, "Request")
+}
@@ -0,0 +1,215 @@
+package mt5
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pb "git.mtapi.io/root/mrpc-proto/mt5/libraries/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// TickQuote is the bid/ask/time part of a tick, passed by value.
+//
+// OnSymbolTick hands every consumer a freshly allocated reply message.
+// Consumers that only need prices use OnSymbolTickQuotes instead: reply
+// envelopes are recycled through a sync.Pool and the channel carries plain
+// values, so nothing escapes per tick beyond what the gRPC codec allocates
+// while decoding.
+type TickQuote struct {
+	Symbol  string
+	Bid     float64
+	Ask     float64
+	TimeMsc int64 // Unix milliseconds
+}
+
+// Time returns the tick time.
+func (q TickQuote) Time() time.Time {
+	return time.UnixMilli(q.TimeMsc)
+}
+
+// Spread returns Ask - Bid.
+func (q TickQuote) Spread() float64 {
+	return q.Ask - q.Bid
+}
+
+// tickReplyPool recycles OnSymbolTick reply envelopes.
+var tickReplyPool = sync.Pool{
+	New: func() any { return &pb.OnSymbolTickReply{} },
+}
+
+// OnSymbolTickQuotes streams ticks as TickQuote values (see TickQuote).
+// Reconnect behaviour and channels are those of OnSymbolTick.
+func (a *MT5Account) OnSymbolTickQuotes(ctx context.Context, req *pb.OnSymbolTickRequest) (<-chan TickQuote, <-chan error) {
+	streamInvoker := func(request *pb.OnSymbolTickRequest, headers metadata.MD, ctx context.Context) (grpc.ClientStream, error) {
+		c := metadata.NewOutgoingContext(ctx, headers)
+		return a.tickClient().OnSymbolTick(c, request)
+	}
+
+	getError := func(reply *pb.OnSymbolTickReply) mrpcError {
+		return reply.GetError()
+	}
+
+	// getData copies the prices out; the reply is not referenced afterwards
+	// and goes back to the pool.
+	getData := func(reply *pb.OnSymbolTickReply) (TickQuote, bool) {
+		tick := reply.GetData().GetSymbolTick()
+		if tick == nil {
+			tickReplyPool.Put(reply)
+			return TickQuote{}, false
+		}
+		q := TickQuote{Symbol: tick.Symbol, Bid: tick.Bid, Ask: tick.Ask, TimeMsc: tick.TimeMsc}
+		if q.TimeMsc == 0 && tick.Time != nil {
+			q.TimeMsc = tick.Time.AsTime().UnixMilli()
+		}
+		reply.Reset()
+		tickReplyPool.Put(reply)
+		return q, true
+	}
+
+	newReply := func() *pb.OnSymbolTickReply {
+		return tickReplyPool.Get().(*pb.OnSymbolTickReply)
+	}
+
+	return ExecuteStreamWithReconnect(ctx, a, req, streamInvoker, getError, getData, newReply)
+}
+
+// TickBatch holds ticks in struct-of-arrays form: element i of every slice
+// belongs to the same tick. Call Release when done; the batch and its slices
+// are reused for later batches.
+type TickBatch struct {
+	Symbol  []string
+	TimeMsc []int64
+	Bid     []float64
+	Ask     []float64
+}
+
+// Len returns the number of ticks in the batch.
+func (b *TickBatch) Len() int {
+	return len(b.TimeMsc)
+}
+
+// Quote returns tick i as a TickQuote.
+func (b *TickBatch) Quote(i int) TickQuote {
+	return TickQuote{Symbol: b.Symbol[i], Bid: b.Bid[i], Ask: b.Ask[i], TimeMsc: b.TimeMsc[i]}
+}
+
+// Release returns the batch to the pool. It must not be used afterwards.
+func (b *TickBatch) Release() {
+	b.Symbol, b.TimeMsc, b.Bid, b.Ask = b.Symbol[:0], b.TimeMsc[:0], b.Bid[:0], b.Ask[:0]
+	tickBatchPool.Put(b)
+}
+
+func (b *TickBatch) add(q TickQuote) {
+	b.Symbol = append(b.Symbol, q.Symbol)
+	b.TimeMsc = append(b.TimeMsc, q.TimeMsc)
+	b.Bid = append(b.Bid, q.Bid)
+	b.Ask = append(b.Ask, q.Ask)
+}
+
+// tickBatchPool recycles released batches.
+var tickBatchPool = sync.Pool{
+	New: func() any { return &TickBatch{} },
+}
+
+// OnSymbolTickBatches streams ticks in batches of up to size ticks. A
+// partial batch is delivered once maxWait has passed since its first tick
+// (0 = only full batches, plus the remainder when the stream ends).
+// Batching trades up to maxWait of latency for one channel send per batch.
+func (a *MT5Account) OnSymbolTickBatches(ctx context.Context, req *pb.OnSymbolTickRequest, size int, maxWait time.Duration) (<-chan *TickBatch, <-chan error) {
+	if size <= 0 {
+		size = 1
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	quotes, errs := a.OnSymbolTickQuotes(ctx, req)
+	return batchQuotes(ctx, quotes, errs, size, maxWait)
+}
+
+// batchQuotes groups a quote stream into batches (see OnSymbolTickBatches).
+func batchQuotes(ctx context.Context, quotes <-chan TickQuote, errs <-chan error, size int, maxWait time.Duration) (<-chan *TickBatch, <-chan error) {
+	batchCh := make(chan *TickBatch)
+	outErrCh := make(chan error, 1)
+
+	go func() {
+		defer close(batchCh)
+		defer close(outErrCh)
+
+		var (
+			batch *TickBatch
+			timer *time.Timer
+			due   <-chan time.Time
+		)
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+
+		// flush delivers the current batch; false (ctx error reported) if ctx is done.
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+				due = nil
+			}
+			if batch == nil || batch.Len() == 0 {
+				return true
+			}
+			select {
+			case batchCh <- batch:
+				batch = nil
+				return true
+			case <-ctx.Done():
+				batch.Release()
+				batch = nil
+				outErrCh <- ctx.Err()
+				return false
+			}
+		}
+
+		for {
+			select {
+			case q, ok := <-quotes:
+				if !ok {
+					if !flush() {
+						return
+					}
+					if err, ok := <-errs; ok {
+						outErrCh <- err
+					}
+					return
+				}
+				if batch == nil {
+					batch = tickBatchPool.Get().(*TickBatch)
+					if maxWait > 0 {
+						if timer == nil {
+							timer = time.NewTimer(maxWait)
+						} else {
+							timer.Reset(maxWait)
+						}
+						due = timer.C
+					}
+				}
+				batch.add(q)
+				if batch.Len() >= size && !flush() {
+					return
+				}
+			case <-due:
+				due = nil
+				if !flush() {
+					return
+				}
+			case <-ctx.Done():
+				if batch != nil {
+					batch.Release()
+				}
+				outErrCh <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return batchCh, outErrCh
+}